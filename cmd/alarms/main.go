@@ -151,7 +151,7 @@ func main() {
 	dbTracer, dbCloser := jaeger.Init("alarms_db", cfg.jaegerURL, logger)
 	defer dbCloser.Close()
 
-	svc := newService(things, dbTracer, db, logger)
+	svc := newService(things, pubSub, dbTracer, db, logger)
 
 	if err = consumers.Alarms(svcName, pubSub, svc); err != nil {
 		logger.Error(fmt.Sprintf("Failed to subscribe to alarms: %s", err))
@@ -165,6 +165,10 @@ func main() {
 		return subscribeToThingsES(ctx, svc, cfg, logger)
 	})
 
+	g.Go(func() error {
+		return svc.ScheduleEscalations(ctx)
+	})
+
 	g.Go(func() error {
 		if sig := errors.SignalHandler(ctx); sig != nil {
 			cancel()
@@ -272,13 +276,15 @@ func subscribeToThingsES(ctx context.Context, svc alarms.Service, cfg config, lo
 	return subscriber.Subscribe(ctx, handler)
 }
 
-func newService(ts domain.ThingsClient, dbTracer opentracing.Tracer, db *sqlx.DB, logger logger.Logger) alarms.Service {
+func newService(ts domain.ThingsClient, pub alarms.Publisher, dbTracer opentracing.Tracer, db *sqlx.DB, logger logger.Logger) alarms.Service {
 	database := dbutil.NewDatabase(db)
 	alarmsRepo := postgres.NewAlarmRepository(database)
 	alarmsRepo = tracing.AlarmRepositoryMiddleware(dbTracer, alarmsRepo)
+	escalationsRepo := postgres.NewEscalationRepository(database)
+	escalationsRepo = tracing.EscalationRepositoryMiddleware(dbTracer, escalationsRepo)
 	idProvider := uuid.New()
 
-	svc := alarms.New(ts, alarmsRepo, idProvider)
+	svc := alarms.New(ts, pub, alarmsRepo, escalationsRepo, idProvider, logger)
 	svc = api.LoggingMiddleware(svc, logger)
 	svc = api.MetricsMiddleware(
 		svc,
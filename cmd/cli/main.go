@@ -21,6 +21,7 @@ func main() {
 		ThingsURL:       fmt.Sprintf("%s/svcthings", defURL),
 		UsersURL:        fmt.Sprintf("%s/svcusers", defURL),
 		WebhooksURL:     fmt.Sprintf("%s/svcwebhooks", defURL),
+		RulesURL:        fmt.Sprintf("%s/svcrules", defURL),
 		ReaderURL:       fmt.Sprintf("%s/reader", defURL),
 		HTTPAdapterURL:  fmt.Sprintf("%s/http", defURL),
 		CertsURL:        defURL,
@@ -218,8 +218,23 @@ func main() {
 	usersAuth := authapi.NewClient(aConn, authTracer, cfg.authGRPCTimeout)
 	tc := thingsapi.NewClient(tConn, thingsTracer, cfg.thingsGRPCTimeout)
 
+	esPub, err := mfevents.NewPublisher(mfevents.PublisherConfig{
+		URL:    cfg.esURL,
+		Stream: mfevents.MQTTStream,
+	}, logger)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to initialize event publisher: %s", err))
+		os.Exit(1)
+	}
+	defer func() {
+		if err := esPub.Close(); err != nil {
+			logger.Error(fmt.Sprintf("Failed to close event publisher: %s", err))
+		}
+	}()
+
 	cc := mqttredis.NewConnectionCache(ac)
 	cc = tracing.ConnectionCacheMiddleware(cacheTracer, cc)
+	cc = events.NewEventStoreMiddleware(cc, esPub)
 
 	svc := newService(usersAuth, tc, db, cc, dbTracer, logger)
 
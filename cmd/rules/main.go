@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/MainfluxLabs/mainflux"
@@ -30,6 +31,7 @@ import (
 	"github.com/MainfluxLabs/mainflux/rules/events"
 	"github.com/MainfluxLabs/mainflux/rules/postgres"
 	"github.com/MainfluxLabs/mainflux/rules/tracing"
+	shadowsapi "github.com/MainfluxLabs/mainflux/shadows/api/grpc"
 	thingsapi "github.com/MainfluxLabs/mainflux/things/api/grpc"
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
 	"github.com/jmoiron/sqlx"
@@ -42,57 +44,67 @@ const (
 	svcName      = "rules"
 	stopWaitTime = 5 * time.Second
 
-	defBrokerURL          = "nats://localhost:4222"
-	defLogLevel           = "error"
-	defDBHost             = "localhost"
-	defDBPort             = "5432"
-	defDBUser             = "mainflux"
-	defDBPass             = "mainflux"
-	defDB                 = svcName
-	defDBSSLMode          = "disable"
-	defDBSSLCert          = ""
-	defDBSSLKey           = ""
-	defDBSSLRootCert      = ""
-	defClientTLS          = "false"
-	defCACerts            = ""
-	defHTTPPort           = "9027"
-	defJaegerURL          = ""
-	defServerCert         = ""
-	defServerKey          = ""
-	defThingsGRPCURL      = "localhost:8183"
-	defThingsGRPCTimeout  = "1s"
-	defReadersGRPCURL     = "localhost:8186"
-	defReadersGRPCTimeout = "1s"
-	defAuthGRPCURL        = "localhost:8181"
-	defAuthGRPCTimeout    = "1s"
-	defESURL              = "redis://localhost:6379/0"
-	defScriptsEnabled     = "false"
-
-	envBrokerURL          = "MF_BROKER_URL"
-	envLogLevel           = "MF_RULES_LOG_LEVEL"
-	envDBHost             = "MF_RULES_DB_HOST"
-	envDBPort             = "MF_RULES_DB_PORT"
-	envDBUser             = "MF_RULES_DB_USER"
-	envDBPass             = "MF_RULES_DB_PASS"
-	envDB                 = "MF_RULES_DB"
-	envDBSSLMode          = "MF_RULES_DB_SSL_MODE"
-	envDBSSLCert          = "MF_RULES_DB_SSL_CERT"
-	envDBSSLKey           = "MF_RULES_DB_SSL_KEY"
-	envDBSSLRootCert      = "MF_RULES_DB_SSL_ROOT_CERT"
-	envClientTLS          = "MF_RULES_CLIENT_TLS"
-	envCACerts            = "MF_RULES_CA_CERTS"
-	envHTTPPort           = "MF_RULES_HTTP_PORT"
-	envServerCert         = "MF_RULES_SERVER_CERT"
-	envServerKey          = "MF_RULES_SERVER_KEY"
-	envJaegerURL          = "MF_JAEGER_URL"
-	envThingsGRPCURL      = "MF_THINGS_AUTH_GRPC_URL"
-	envThingsGRPCTimeout  = "MF_THINGS_AUTH_GRPC_TIMEOUT"
-	envReadersGRPCURL     = "MF_POSTGRES_READER_GRPC_URL"
-	envReadersGRPCTimeout = "MF_POSTGRES_READER_GRPC_TIMEOUT"
-	envAuthGRPCURL        = "MF_AUTH_GRPC_URL"
-	envAuthGRPCTimeout    = "MF_AUTH_GRPC_TIMEOUT"
-	envESURL              = "MF_RULES_ES_URL"
-	envScriptsEnabled     = "MF_RULES_SCRIPTS_ENABLED"
+	defBrokerURL           = "nats://localhost:4222"
+	defLogLevel            = "error"
+	defDBHost              = "localhost"
+	defDBPort              = "5432"
+	defDBUser              = "mainflux"
+	defDBPass              = "mainflux"
+	defDB                  = svcName
+	defDBSSLMode           = "disable"
+	defDBSSLCert           = ""
+	defDBSSLKey            = ""
+	defDBSSLRootCert       = ""
+	defClientTLS           = "false"
+	defCACerts             = ""
+	defHTTPPort            = "9027"
+	defJaegerURL           = ""
+	defServerCert          = ""
+	defServerKey           = ""
+	defThingsGRPCURL       = "localhost:8183"
+	defThingsGRPCTimeout   = "1s"
+	defReadersGRPCURL      = "localhost:8186"
+	defReadersGRPCTimeout  = "1s"
+	defAuthGRPCURL         = "localhost:8181"
+	defAuthGRPCTimeout     = "1s"
+	defShadowsGRPCURL      = "localhost:8187"
+	defShadowsGRPCTimeout  = "1s"
+	defESURL               = "redis://localhost:6379/0"
+	defScriptsEnabled      = "false"
+	defScriptHTTPAllowlist = ""
+	defScriptRunsMaxAge    = "720h"
+	defScriptRunsMaxCount  = "1000"
+
+	envBrokerURL           = "MF_BROKER_URL"
+	envLogLevel            = "MF_RULES_LOG_LEVEL"
+	envDBHost              = "MF_RULES_DB_HOST"
+	envDBPort              = "MF_RULES_DB_PORT"
+	envDBUser              = "MF_RULES_DB_USER"
+	envDBPass              = "MF_RULES_DB_PASS"
+	envDB                  = "MF_RULES_DB"
+	envDBSSLMode           = "MF_RULES_DB_SSL_MODE"
+	envDBSSLCert           = "MF_RULES_DB_SSL_CERT"
+	envDBSSLKey            = "MF_RULES_DB_SSL_KEY"
+	envDBSSLRootCert       = "MF_RULES_DB_SSL_ROOT_CERT"
+	envClientTLS           = "MF_RULES_CLIENT_TLS"
+	envCACerts             = "MF_RULES_CA_CERTS"
+	envHTTPPort            = "MF_RULES_HTTP_PORT"
+	envServerCert          = "MF_RULES_SERVER_CERT"
+	envServerKey           = "MF_RULES_SERVER_KEY"
+	envJaegerURL           = "MF_JAEGER_URL"
+	envThingsGRPCURL       = "MF_THINGS_AUTH_GRPC_URL"
+	envThingsGRPCTimeout   = "MF_THINGS_AUTH_GRPC_TIMEOUT"
+	envReadersGRPCURL      = "MF_POSTGRES_READER_GRPC_URL"
+	envReadersGRPCTimeout  = "MF_POSTGRES_READER_GRPC_TIMEOUT"
+	envAuthGRPCURL         = "MF_AUTH_GRPC_URL"
+	envAuthGRPCTimeout     = "MF_AUTH_GRPC_TIMEOUT"
+	envShadowsGRPCURL      = "MF_SHADOWS_GRPC_URL"
+	envShadowsGRPCTimeout  = "MF_SHADOWS_GRPC_TIMEOUT"
+	envESURL               = "MF_RULES_ES_URL"
+	envScriptsEnabled      = "MF_RULES_SCRIPTS_ENABLED"
+	envScriptHTTPAllowlist = "MF_RULES_SCRIPT_HTTP_ALLOWLIST"
+	envScriptRunsMaxAge    = "MF_RULES_SCRIPT_RUNS_MAX_AGE"
+	envScriptRunsMaxCount  = "MF_RULES_SCRIPT_RUNS_MAX_COUNT"
 )
 
 type config struct {
@@ -103,12 +115,14 @@ type config struct {
 	thingsConfig       clients.Config
 	readersConfig      clients.Config
 	authConfig         clients.Config
+	shadowsConfig      clients.Config
 	jaegerURL          string
 	thingsGRPCTimeout  time.Duration
 	readersGRPCTimeout time.Duration
 	authGRPCTimeout    time.Duration
+	shadowsGRPCTimeout time.Duration
 	esURL              string
-	scriptsEnabled     bool
+	scriptsConfig      rules.ScriptsConfig
 }
 
 func main() {
@@ -160,7 +174,15 @@ func main() {
 
 	auth := authapi.NewClient(authConn, authTracer, cfg.authGRPCTimeout)
 
-	svc := newService(dbTracer, db, tc, rc, ps, logger, cfg.scriptsEnabled)
+	shadowsConn := clientsgrpc.Connect(cfg.shadowsConfig, logger)
+	defer shadowsConn.Close()
+
+	shadowsTracer, shadowsCloser := jaeger.Init("rules_shadows", cfg.jaegerURL, logger)
+	defer shadowsCloser.Close()
+
+	sc := shadowsapi.NewClient(shadowsConn, shadowsTracer, cfg.shadowsGRPCTimeout)
+
+	svc := newService(dbTracer, db, tc, rc, sc, ps, logger, cfg.scriptsConfig)
 
 	if err = consumers.Messages(svcName, ps, svc, nats.SubjectRules); err != nil {
 		logger.Error(fmt.Sprintf("Failed to create rule engine: %s", err))
@@ -168,6 +190,9 @@ func main() {
 	if err = consumers.Alarms(svcName, ps, svc); err != nil {
 		logger.Error(fmt.Sprintf("Failed to create rule engine: %s", err))
 	}
+	if err = consumers.Messages(svcName, ps, consumers.MessageConsumerFunc(svc.TransformMessage), nats.SubjectTransforms); err != nil {
+		logger.Error(fmt.Sprintf("Failed to create message transformer: %s", err))
+	}
 
 	g.Go(func() error {
 		return servershttp.Start(ctx, httpapi.MakeHandler(rulesHttpTracer, svc, auth, logger), cfg.httpConfig, logger)
@@ -177,6 +202,10 @@ func main() {
 		return subscribeToThingsES(ctx, svc, cfg, logger)
 	})
 
+	g.Go(func() error {
+		return svc.LoadAndScheduleScripts(ctx)
+	})
+
 	g.Go(func() error {
 		if sig := errors.SignalHandler(ctx); sig != nil {
 			cancel()
@@ -215,6 +244,28 @@ func loadConfig() config {
 		log.Fatalf("Invalid %s value: %s", envAuthGRPCTimeout, err.Error())
 	}
 
+	shadowsGRPCTimeout, err := time.ParseDuration(mainflux.Env(envShadowsGRPCTimeout, defShadowsGRPCTimeout))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envShadowsGRPCTimeout, err.Error())
+	}
+
+	scriptRunsMaxAge, err := time.ParseDuration(mainflux.Env(envScriptRunsMaxAge, defScriptRunsMaxAge))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envScriptRunsMaxAge, err.Error())
+	}
+
+	scriptRunsMaxCount, err := strconv.ParseUint(mainflux.Env(envScriptRunsMaxCount, defScriptRunsMaxCount), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envScriptRunsMaxCount, err.Error())
+	}
+
+	var scriptHTTPAllowlist []string
+	for _, host := range strings.Split(mainflux.Env(envScriptHTTPAllowlist, defScriptHTTPAllowlist), ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			scriptHTTPAllowlist = append(scriptHTTPAllowlist, host)
+		}
+	}
+
 	dbConfig := postgres.Config{
 		Host:        mainflux.Env(envDBHost, defDBHost),
 		Port:        mainflux.Env(envDBPort, defDBPort),
@@ -256,6 +307,13 @@ func loadConfig() config {
 		ClientName: clients.Auth,
 	}
 
+	shadowsConfig := clients.Config{
+		ClientTLS:  tls,
+		CaCerts:    mainflux.Env(envCACerts, defCACerts),
+		URL:        mainflux.Env(envShadowsGRPCURL, defShadowsGRPCURL),
+		ClientName: clients.Shadows,
+	}
+
 	return config{
 		brokerURL:          mainflux.Env(envBrokerURL, defBrokerURL),
 		logLevel:           mainflux.Env(envLogLevel, defLogLevel),
@@ -264,12 +322,19 @@ func loadConfig() config {
 		thingsConfig:       thingsConfig,
 		readersConfig:      readersConfig,
 		authConfig:         authConfig,
+		shadowsConfig:      shadowsConfig,
 		jaegerURL:          mainflux.Env(envJaegerURL, defJaegerURL),
 		thingsGRPCTimeout:  thingsGRPCTimeout,
 		readersGRPCTimeout: readersGRPCTimeout,
 		authGRPCTimeout:    authGRPCTimeout,
+		shadowsGRPCTimeout: shadowsGRPCTimeout,
 		esURL:              mainflux.Env(envESURL, defESURL),
-		scriptsEnabled:     scriptsEnabled,
+		scriptsConfig: rules.ScriptsConfig{
+			Enabled:       scriptsEnabled,
+			HTTPAllowlist: scriptHTTPAllowlist,
+			RunsMaxAge:    scriptRunsMaxAge,
+			RunsMaxCount:  scriptRunsMaxCount,
+		},
 	}
 }
 
@@ -303,14 +368,14 @@ func subscribeToThingsES(ctx context.Context, svc rules.Service, cfg config, log
 	return subscriber.Subscribe(ctx, handler)
 }
 
-func newService(dbTracer opentracing.Tracer, db *sqlx.DB, tc domain.ThingsClient, rc domain.ReadersClient, nps rules.Publisher, logger logger.Logger, scriptsEnabled bool) rules.Service {
+func newService(dbTracer opentracing.Tracer, db *sqlx.DB, tc domain.ThingsClient, rc domain.ReadersClient, sc domain.ShadowsClient, nps rules.Publisher, logger logger.Logger, scriptsConfig rules.ScriptsConfig) rules.Service {
 	database := dbutil.NewDatabase(db)
 
 	rulesRepo := postgres.NewRuleRepository(database)
 	rulesRepo = tracing.RuleRepositoryMiddleware(dbTracer, rulesRepo)
 
 	idProvider := uuid.New()
-	svc := rules.New(rulesRepo, tc, rc, nps, idProvider, logger, scriptsEnabled)
+	svc := rules.New(rulesRepo, tc, rc, sc, nps, idProvider, logger, scriptsConfig)
 	svc = api.LoggingMiddleware(svc, logger)
 	svc = api.MetricsMiddleware(
 		svc,
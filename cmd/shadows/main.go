@@ -25,6 +25,7 @@ import (
 	"github.com/MainfluxLabs/mainflux/pkg/messaging"
 	"github.com/MainfluxLabs/mainflux/pkg/messaging/nats"
 	"github.com/MainfluxLabs/mainflux/pkg/servers"
+	serversgrpc "github.com/MainfluxLabs/mainflux/pkg/servers/grpc"
 	servershttp "github.com/MainfluxLabs/mainflux/pkg/servers/http"
 	"github.com/MainfluxLabs/mainflux/shadows"
 	"github.com/MainfluxLabs/mainflux/shadows/api"
@@ -57,6 +58,9 @@ const (
 	defClientTLS         = "false"
 	defCACerts           = ""
 	defHTTPPort          = "9031"
+	defGRPCPort          = "8187"
+	defGRPCServerCert    = ""
+	defGRPCServerKey     = ""
 	defJaegerURL         = ""
 	defServerCert        = ""
 	defServerKey         = ""
@@ -66,6 +70,8 @@ const (
 	defAuthGRPCTimeout   = "1s"
 	defBrokerURL         = "nats://localhost:4222"
 	defESURL             = "redis://localhost:6379/0"
+	defHistoryMaxAge     = "720h"
+	defHistoryMaxCount   = "1000"
 
 	envLogLevel          = "MF_SHADOWS_LOG_LEVEL"
 	envDBHost            = "MF_SHADOWS_DB_HOST"
@@ -80,6 +86,9 @@ const (
 	envClientTLS         = "MF_SHADOWS_CLIENT_TLS"
 	envCACerts           = "MF_SHADOWS_CA_CERTS"
 	envHTTPPort          = "MF_SHADOWS_HTTP_PORT"
+	envGRPCPort          = "MF_SHADOWS_GRPC_PORT"
+	envGRPCServerCert    = "MF_SHADOWS_GRPC_SERVER_CERT"
+	envGRPCServerKey     = "MF_SHADOWS_GRPC_SERVER_KEY"
 	envServerCert        = "MF_SHADOWS_SERVER_CERT"
 	envServerKey         = "MF_SHADOWS_SERVER_KEY"
 	envJaegerURL         = "MF_JAEGER_URL"
@@ -89,12 +98,15 @@ const (
 	envAuthGRPCTimeout   = "MF_AUTH_GRPC_TIMEOUT"
 	envBrokerURL         = "MF_BROKER_URL"
 	envESURL             = "MF_SHADOWS_ES_URL"
+	envHistoryMaxAge     = "MF_SHADOWS_HISTORY_MAX_AGE"
+	envHistoryMaxCount   = "MF_SHADOWS_HISTORY_MAX_COUNT"
 )
 
 type config struct {
 	logLevel          string
 	dbConfig          postgres.Config
 	httpConfig        servers.Config
+	grpcConfig        servers.Config
 	thingsConfig      clients.Config
 	authConfig        clients.Config
 	jaegerURL         string
@@ -102,6 +114,7 @@ type config struct {
 	authGRPCTimeout   time.Duration
 	brokerURL         string
 	esURL             string
+	historyConfig     shadows.HistoryConfig
 }
 
 func main() {
@@ -146,7 +159,7 @@ func main() {
 	}
 	defer pubSub.Close()
 
-	svc := newService(things, pubSub, dbTracer, db, logger)
+	svc := newService(things, pubSub, dbTracer, db, cfg.historyConfig, logger)
 
 	subjects := []string{nats.SubjectMessages, nats.SubjectMessagesWithSubtopic}
 	if err := consumers.Messages(svcName, pubSub, svc, subjects...); err != nil {
@@ -154,14 +167,34 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := consumers.Commands(svcName, pubSub, svc, nats.SubjectShadows); err != nil {
+		logger.Error(fmt.Sprintf("Failed to subscribe to message broker: %s", err))
+		os.Exit(1)
+	}
+
+	g.Go(func() error {
+		return subscribeToES(ctx, svc, cfg, mfevents.ThingsStream, logger)
+	})
+
 	g.Go(func() error {
-		return subscribeToThingsES(ctx, svc, cfg, logger)
+		return subscribeToES(ctx, svc, cfg, mfevents.MQTTStream, logger)
+	})
+
+	g.Go(func() error {
+		return svc.ProcessHistory(ctx)
 	})
 
 	g.Go(func() error {
 		return servershttp.Start(ctx, httpapi.MakeHandler(shadowsTracer, svc, auth, logger), cfg.httpConfig, logger)
 	})
 
+	shadowsGRPCTracer, shadowsGRPCCloser := jaeger.Init("shadows_grpc", cfg.jaegerURL, logger)
+	defer shadowsGRPCCloser.Close()
+
+	g.Go(func() error {
+		return serversgrpc.Start(ctx, shadowsGRPCTracer, svc, cfg.grpcConfig, logger)
+	})
+
 	g.Go(func() error {
 		if sig := errors.SignalHandler(ctx); sig != nil {
 			cancel()
@@ -191,6 +224,21 @@ func loadConfig() config {
 		log.Fatalf("Invalid %s value: %s", envAuthGRPCTimeout, err.Error())
 	}
 
+	historyMaxAge, err := time.ParseDuration(mainflux.Env(envHistoryMaxAge, defHistoryMaxAge))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envHistoryMaxAge, err.Error())
+	}
+
+	historyMaxCount, err := strconv.ParseUint(mainflux.Env(envHistoryMaxCount, defHistoryMaxCount), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envHistoryMaxCount, err.Error())
+	}
+
+	historyConfig := shadows.HistoryConfig{
+		MaxAge:   historyMaxAge,
+		MaxCount: historyMaxCount,
+	}
+
 	dbConfig := postgres.Config{
 		Host:        mainflux.Env(envDBHost, defDBHost),
 		Port:        mainflux.Env(envDBPort, defDBPort),
@@ -211,6 +259,14 @@ func loadConfig() config {
 		StopWaitTime: stopWaitTime,
 	}
 
+	grpcConfig := servers.Config{
+		ServerName:   svcName,
+		Port:         mainflux.Env(envGRPCPort, defGRPCPort),
+		ServerCert:   mainflux.Env(envGRPCServerCert, defGRPCServerCert),
+		ServerKey:    mainflux.Env(envGRPCServerKey, defGRPCServerKey),
+		StopWaitTime: stopWaitTime,
+	}
+
 	thingsConfig := clients.Config{
 		ClientTLS:  tls,
 		CaCerts:    mainflux.Env(envCACerts, defCACerts),
@@ -229,6 +285,7 @@ func loadConfig() config {
 		logLevel:          mainflux.Env(envLogLevel, defLogLevel),
 		dbConfig:          dbConfig,
 		httpConfig:        httpConfig,
+		grpcConfig:        grpcConfig,
 		thingsConfig:      thingsConfig,
 		authConfig:        authConfig,
 		brokerURL:         mainflux.Env(envBrokerURL, defBrokerURL),
@@ -236,13 +293,14 @@ func loadConfig() config {
 		thingsGRPCTimeout: thingsGRPCTimeout,
 		authGRPCTimeout:   authGRPCTimeout,
 		esURL:             mainflux.Env(envESURL, defESURL),
+		historyConfig:     historyConfig,
 	}
 }
 
-func subscribeToThingsES(ctx context.Context, svc shadows.Service, cfg config, logger logger.Logger) error {
+func subscribeToES(ctx context.Context, svc shadows.Service, cfg config, stream string, logger logger.Logger) error {
 	subscriber, err := mfevents.NewSubscriber(mfevents.SubscriberConfig{
 		URL:    cfg.esURL,
-		Stream: mfevents.ThingsStream,
+		Stream: stream,
 		Name:   svcName,
 	}, logger)
 	if err != nil {
@@ -258,11 +316,11 @@ func subscribeToThingsES(ctx context.Context, svc shadows.Service, cfg config, l
 	return subscriber.Subscribe(ctx, events.NewEventHandler(svc))
 }
 
-func newService(things domain.ThingsClient, pub messaging.CommandPublisher, dbTracer opentracing.Tracer, db *sqlx.DB, logger logger.Logger) shadows.Service {
+func newService(things domain.ThingsClient, pub messaging.CommandPublisher, dbTracer opentracing.Tracer, db *sqlx.DB, historyConfig shadows.HistoryConfig, logger logger.Logger) shadows.Service {
 	repo := postgres.NewShadowRepository(dbutil.NewDatabase(db))
 	repo = tracing.ShadowRepositoryMiddleware(dbTracer, repo)
 
-	svc := shadows.New(things, repo, pub, logger)
+	svc := shadows.New(things, repo, pub, historyConfig, logger)
 	svc = api.LoggingMiddleware(svc, logger)
 	svc = api.MetricsMiddleware(
 		svc,
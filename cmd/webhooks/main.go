@@ -70,6 +70,19 @@ const (
 	defAuthGRPCURL       = "localhost:8181"
 	defAuthGRPCTimeout   = "1s"
 	defESURL             = "redis://localhost:6379/0"
+	defMaxAttempts       = "5"
+	defRetryInterval     = "1s"
+	defMaxRetryInterval  = "1m"
+	defDisableAfter      = "20"
+	defSecretGracePeriod = "24h"
+	defBatchSize         = "100"
+	defBatchMaxBytes     = "1048576"
+	defBatchInterval     = "1s"
+	defQueueSize         = "1000"
+	defDeliveriesMaxAge  = "720h"
+	defDeliveriesMaxCnt  = "1000"
+	defDeadLettersMaxAge = "2160h"
+	defDeadLettersMaxCnt = "10000"
 
 	envBrokerURL         = "MF_BROKER_URL"
 	envLogLevel          = "MF_WEBHOOKS_LOG_LEVEL"
@@ -93,6 +106,19 @@ const (
 	envAuthGRPCURL       = "MF_AUTH_GRPC_URL"
 	envAuthGRPCTimeout   = "MF_AUTH_GRPC_TIMEOUT"
 	envESURL             = "MF_WEBHOOKS_ES_URL"
+	envMaxAttempts       = "MF_WEBHOOKS_MAX_ATTEMPTS"
+	envRetryInterval     = "MF_WEBHOOKS_RETRY_INTERVAL"
+	envMaxRetryInterval  = "MF_WEBHOOKS_MAX_RETRY_INTERVAL"
+	envDisableAfter      = "MF_WEBHOOKS_DISABLE_AFTER"
+	envSecretGracePeriod = "MF_WEBHOOKS_SECRET_GRACE_PERIOD"
+	envBatchSize         = "MF_WEBHOOKS_BATCH_SIZE"
+	envBatchMaxBytes     = "MF_WEBHOOKS_BATCH_MAX_BYTES"
+	envBatchInterval     = "MF_WEBHOOKS_BATCH_INTERVAL"
+	envQueueSize         = "MF_WEBHOOKS_QUEUE_SIZE"
+	envDeliveriesMaxAge  = "MF_WEBHOOKS_DELIVERIES_MAX_AGE"
+	envDeliveriesMaxCnt  = "MF_WEBHOOKS_DELIVERIES_MAX_COUNT"
+	envDeadLettersMaxAge = "MF_WEBHOOKS_DEAD_LETTERS_MAX_AGE"
+	envDeadLettersMaxCnt = "MF_WEBHOOKS_DEAD_LETTERS_MAX_COUNT"
 )
 
 type config struct {
@@ -106,6 +132,7 @@ type config struct {
 	thingsGRPCTimeout time.Duration
 	authGRPCTimeout   time.Duration
 	esURL             string
+	deliveryConfig    webhooks.DeliveryConfig
 }
 
 func main() {
@@ -150,7 +177,7 @@ func main() {
 	dbTracer, dbCloser := jaeger.Init("webhooks_db", cfg.jaegerURL, logger)
 	defer dbCloser.Close()
 
-	svc := newService(things, dbTracer, db, logger)
+	svc := newService(things, dbTracer, db, cfg.deliveryConfig, logger)
 
 	g.Go(func() error {
 		return subscribeToThingsES(ctx, svc, cfg, logger)
@@ -160,6 +187,10 @@ func main() {
 		logger.Error(fmt.Sprintf("Failed to create Webhook: %s", err))
 	}
 
+	g.Go(func() error {
+		return svc.ProcessDeliveries(ctx)
+	})
+
 	g.Go(func() error {
 		return servershttp.Start(ctx, httpapi.MakeHandler(webhooksTracer, svc, auth, logger), cfg.httpConfig, logger)
 	})
@@ -227,6 +258,95 @@ func loadConfig() config {
 		ClientName: clients.Auth,
 	}
 
+	maxAttempts, err := strconv.ParseUint(mainflux.Env(envMaxAttempts, defMaxAttempts), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envMaxAttempts, err.Error())
+	}
+
+	retryInterval, err := time.ParseDuration(mainflux.Env(envRetryInterval, defRetryInterval))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envRetryInterval, err.Error())
+	}
+
+	maxRetryInterval, err := time.ParseDuration(mainflux.Env(envMaxRetryInterval, defMaxRetryInterval))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envMaxRetryInterval, err.Error())
+	}
+
+	disableAfter, err := strconv.ParseUint(mainflux.Env(envDisableAfter, defDisableAfter), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envDisableAfter, err.Error())
+	}
+
+	secretGracePeriod, err := time.ParseDuration(mainflux.Env(envSecretGracePeriod, defSecretGracePeriod))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envSecretGracePeriod, err.Error())
+	}
+
+	batchSize, err := strconv.Atoi(mainflux.Env(envBatchSize, defBatchSize))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envBatchSize, err.Error())
+	}
+
+	batchMaxBytes, err := strconv.Atoi(mainflux.Env(envBatchMaxBytes, defBatchMaxBytes))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envBatchMaxBytes, err.Error())
+	}
+
+	batchInterval, err := time.ParseDuration(mainflux.Env(envBatchInterval, defBatchInterval))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envBatchInterval, err.Error())
+	}
+
+	queueSize, err := strconv.Atoi(mainflux.Env(envQueueSize, defQueueSize))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envQueueSize, err.Error())
+	}
+
+	deliveriesMaxAge, err := time.ParseDuration(mainflux.Env(envDeliveriesMaxAge, defDeliveriesMaxAge))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envDeliveriesMaxAge, err.Error())
+	}
+
+	deliveriesMaxCount, err := strconv.ParseUint(mainflux.Env(envDeliveriesMaxCnt, defDeliveriesMaxCnt), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envDeliveriesMaxCnt, err.Error())
+	}
+
+	deadLettersMaxAge, err := time.ParseDuration(mainflux.Env(envDeadLettersMaxAge, defDeadLettersMaxAge))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envDeadLettersMaxAge, err.Error())
+	}
+
+	deadLettersMaxCount, err := strconv.ParseUint(mainflux.Env(envDeadLettersMaxCnt, defDeadLettersMaxCnt), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envDeadLettersMaxCnt, err.Error())
+	}
+
+	instanceID, err := uuid.New().ID()
+	if err != nil {
+		log.Fatalf("Failed to generate instance ID: %s", err.Error())
+	}
+
+	deliveryConfig := webhooks.DeliveryConfig{
+		MaxAttempts:       maxAttempts,
+		RetryInterval:     retryInterval,
+		MaxRetryInterval:  maxRetryInterval,
+		DisableAfter:      disableAfter,
+		SecretGracePeriod: secretGracePeriod,
+		Batch: webhooks.BatchConfig{
+			MaxMessages: batchSize,
+			MaxBytes:    batchMaxBytes,
+			Interval:    batchInterval,
+		},
+		QueueSize:           queueSize,
+		DeliveriesMaxAge:    deliveriesMaxAge,
+		DeliveriesMaxCount:  deliveriesMaxCount,
+		DeadLettersMaxAge:   deadLettersMaxAge,
+		DeadLettersMaxCount: deadLettersMaxCount,
+		InstanceID:          instanceID,
+	}
+
 	return config{
 		brokerURL:         mainflux.Env(envBrokerURL, defBrokerURL),
 		logLevel:          mainflux.Env(envLogLevel, defLogLevel),
@@ -238,6 +358,7 @@ func loadConfig() config {
 		thingsGRPCTimeout: thingsAuthGRPCTimeout,
 		authGRPCTimeout:   authGRPCTimeout,
 		esURL:             mainflux.Env(envESURL, defESURL),
+		deliveryConfig:    deliveryConfig,
 	}
 }
 
@@ -271,14 +392,20 @@ func subscribeToThingsES(ctx context.Context, svc webhooks.Service, cfg config,
 	return subscriber.Subscribe(ctx, handler)
 }
 
-func newService(ts domain.ThingsClient, dbTracer opentracing.Tracer, db *sqlx.DB, logger logger.Logger) webhooks.Service {
+func newService(ts domain.ThingsClient, dbTracer opentracing.Tracer, db *sqlx.DB, deliveryConfig webhooks.DeliveryConfig, logger logger.Logger) webhooks.Service {
 	database := dbutil.NewDatabase(db)
 	webhooksRepo := postgres.NewWebhookRepository(database)
 	webhooksRepo = tracing.WebhookRepositoryMiddleware(dbTracer, webhooksRepo)
+	deliveriesRepo := postgres.NewDeliveryRepository(database)
+	deliveriesRepo = tracing.DeliveryRepositoryMiddleware(dbTracer, deliveriesRepo)
+	deadLettersRepo := postgres.NewDeadLetterRepository(database)
+	deadLettersRepo = tracing.DeadLetterRepositoryMiddleware(dbTracer, deadLettersRepo)
+	pendingRepo := postgres.NewPendingMessageRepository(database)
+	pendingRepo = tracing.PendingMessageRepositoryMiddleware(dbTracer, pendingRepo)
 	forwarder := webhooks.NewForwarder()
 	idProvider := uuid.New()
 
-	svc := webhooks.New(ts, webhooksRepo, forwarder, idProvider)
+	svc := webhooks.New(ts, webhooksRepo, deliveriesRepo, deadLettersRepo, pendingRepo, forwarder, idProvider, deliveryConfig, logger)
 	svc = api.LoggingMiddleware(svc, logger)
 	svc = api.MetricsMiddleware(
 		svc,
@@ -12,6 +12,9 @@ const (
 	StatusCleared = "cleared"
 )
 
+// Alarm represents an alarm raised by a rule or a script for a thing and subtopic.
+// While an alarm isn't cleared, repeated occurrences are recorded on it
+// instead of raising new alarms.
 type Alarm struct {
 	ID       string
 	ThingID  string
@@ -23,7 +26,23 @@ type Alarm struct {
 	Rule     *domain.RuleInfo
 	Level    int32
 	Status   string
-	Created  int64
+	// Occurrences is the number of times the alarm was raised.
+	Occurrences uint64
+	// LastSeen is the time the alarm was last raised.
+	LastSeen int64
+	// History lists the alarm status changes, oldest first.
+	History []StatusChange
+	Created int64
+}
+
+// StatusChange represents a change of the alarm status.
+type StatusChange struct {
+	Status string `json:"status"`
+	// UserID and Email identify the user who changed the status.
+	// They are empty if the alarm was cleared automatically by its rule.
+	UserID  string `json:"user_id,omitempty"`
+	Email   string `json:"email,omitempty"`
+	Created int64  `json:"created"`
 }
 
 type AlarmsPage struct {
@@ -33,10 +52,12 @@ type AlarmsPage struct {
 
 // AlarmOrderFields maps API-facing order keys to SQL column expressions for the alarms table.
 var AlarmOrderFields = map[string]string{
-	"id":      "id",
-	"created": "created",
-	"level":   "level",
-	"status":  "status",
+	"id":          "id",
+	"created":     "created",
+	"level":       "level",
+	"status":      "status",
+	"last_seen":   "last_seen",
+	"occurrences": "occurrences",
 }
 
 // AlarmRepository specifies an alarm persistence API.
@@ -71,8 +92,17 @@ type AlarmRepository interface {
 	// identified by a given group ID.
 	RemoveByGroup(ctx context.Context, groupID string) error
 
-	// UpdateStatus updates the status of an alarm identified by the provided ID.
-	UpdateStatus(ctx context.Context, id, status string) error
+	// RetrieveOpen retrieves the alarm that isn't cleared, raised for the thing and
+	// subtopic of the provided alarm by the same rule or script.
+	RetrieveOpen(ctx context.Context, alarm Alarm) (Alarm, error)
+
+	// UpdateOccurrence records an occurrence of the alarm identified by the provided ID,
+	// raised at the given time.
+	UpdateOccurrence(ctx context.Context, id string, seen int64) error
+
+	// UpdateStatus updates the status of an alarm identified by the provided ID
+	// and appends the change to the alarm status history.
+	UpdateStatus(ctx context.Context, id string, change StatusChange) error
 
 	// ExportByThing retrieves alarms related to a certain thing,
 	// identified by a given thing ID.
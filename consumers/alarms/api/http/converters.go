@@ -20,18 +20,20 @@ func ConvertToJSONFile(page alarms.AlarmsPage, timeFormat string) ([]byte, error
 
 	for _, a := range page.Alarms {
 		item := map[string]any{
-			"thing_id":  a.ThingID,
-			"group_id":  a.GroupID,
-			"rule_id":   a.RuleID,
-			"script_id": a.ScriptID,
-			"subtopic":  a.Subtopic,
-			"protocol":  a.Protocol,
-			"rule":      a.Rule,
-			"level":     a.Level,
-			"status":    a.Status,
+			"thing_id":    a.ThingID,
+			"group_id":    a.GroupID,
+			"rule_id":     a.RuleID,
+			"script_id":   a.ScriptID,
+			"subtopic":    a.Subtopic,
+			"protocol":    a.Protocol,
+			"rule":        a.Rule,
+			"level":       a.Level,
+			"status":      a.Status,
+			"occurrences": a.Occurrences,
 		}
 
 		item["created"] = formatTimeNs(a.Created, timeFormat)
+		item["last_seen"] = formatTimeNs(a.LastSeen, timeFormat)
 		result = append(result, item)
 	}
 
@@ -57,6 +59,8 @@ func ConvertToCSVFile(page alarms.AlarmsPage, timeFormat string) ([]byte, error)
 		"rule",
 		"level",
 		"status",
+		"occurrences",
+		"last_seen",
 	}
 
 	if err := writer.Write(header); err != nil {
@@ -84,6 +88,8 @@ func ConvertToCSVFile(page alarms.AlarmsPage, timeFormat string) ([]byte, error)
 			rule,
 			fmt.Sprintf("%d", alarm.Level),
 			alarm.Status,
+			fmt.Sprintf("%d", alarm.Occurrences),
+			formatTimeNs(alarm.LastSeen, timeFormat),
 		}
 
 		if err := writer.Write(row); err != nil {
@@ -137,6 +137,118 @@ func exportAlarmsByThingEndpoint(svc alarms.Service) endpoint.Endpoint {
 	}
 }
 
+func createEscalationPoliciesEndpoint(svc alarms.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(createEscalationPoliciesReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		var policies []alarms.EscalationPolicy
+		for _, p := range req.Policies {
+			policies = append(policies, alarms.EscalationPolicy{
+				Name:  p.Name,
+				Level: p.Level,
+				Steps: p.Steps,
+			})
+		}
+
+		saved, err := svc.CreateEscalationPolicies(ctx, req.token, req.groupID, policies...)
+		if err != nil {
+			return nil, err
+		}
+
+		res := escalationPoliciesRes{Policies: []escalationPolicyResponse{}}
+		for _, p := range saved {
+			res.Policies = append(res.Policies, buildEscalationPolicyResponse(p))
+		}
+
+		return res, nil
+	}
+}
+
+func listEscalationPoliciesByGroupEndpoint(svc alarms.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(listEscalationPoliciesReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListEscalationPoliciesByGroup(ctx, req.token, req.groupID, req.pageMetadata)
+		if err != nil {
+			return nil, err
+		}
+
+		res := EscalationPoliciesPageRes{
+			Total:    page.Total,
+			Offset:   req.pageMetadata.Offset,
+			Limit:    req.pageMetadata.Limit,
+			Order:    req.pageMetadata.Order,
+			Dir:      req.pageMetadata.Dir,
+			Name:     req.pageMetadata.Name,
+			Policies: []escalationPolicyResponse{},
+		}
+		for _, p := range page.Policies {
+			res.Policies = append(res.Policies, buildEscalationPolicyResponse(p))
+		}
+
+		return res, nil
+	}
+}
+
+func viewEscalationPolicyEndpoint(svc alarms.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(escalationPolicyReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		policy, err := svc.ViewEscalationPolicy(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return buildEscalationPolicyResponse(policy), nil
+	}
+}
+
+func updateEscalationPolicyEndpoint(svc alarms.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(updateEscalationPolicyReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		policy := alarms.EscalationPolicy{
+			ID:    req.id,
+			Name:  req.Name,
+			Level: req.Level,
+			Steps: req.Steps,
+		}
+
+		if err := svc.UpdateEscalationPolicy(ctx, req.token, policy); err != nil {
+			return nil, err
+		}
+
+		return apiutil.EmptyRes{StatusCode: http.StatusOK}, nil
+	}
+}
+
+func removeEscalationPoliciesEndpoint(svc alarms.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(removeEscalationPoliciesReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveEscalationPolicies(ctx, req.token, req.PolicyIDs...); err != nil {
+			return nil, err
+		}
+
+		return apiutil.EmptyRes{StatusCode: http.StatusNoContent}, nil
+	}
+}
+
 func buildAlarmsPageResponse(ap alarms.AlarmsPage, pm alarms.PageMetadata) AlarmsPageRes {
 	res := AlarmsPageRes{
 		Total:  ap.Total,
@@ -156,16 +268,29 @@ func buildAlarmsPageResponse(ap alarms.AlarmsPage, pm alarms.PageMetadata) Alarm
 
 func buildAlarmResponse(alarm alarms.Alarm) alarmResponse {
 	return alarmResponse{
-		ID:       alarm.ID,
-		ThingID:  alarm.ThingID,
-		GroupID:  alarm.GroupID,
-		RuleID:   alarm.RuleID,
-		ScriptID: alarm.ScriptID,
-		Subtopic: alarm.Subtopic,
-		Protocol: alarm.Protocol,
-		Rule:     alarm.Rule,
-		Level:    alarm.Level,
-		Status:   alarm.Status,
-		Created:  alarm.Created,
+		ID:          alarm.ID,
+		ThingID:     alarm.ThingID,
+		GroupID:     alarm.GroupID,
+		RuleID:      alarm.RuleID,
+		ScriptID:    alarm.ScriptID,
+		Subtopic:    alarm.Subtopic,
+		Protocol:    alarm.Protocol,
+		Rule:        alarm.Rule,
+		Level:       alarm.Level,
+		Status:      alarm.Status,
+		Occurrences: alarm.Occurrences,
+		LastSeen:    alarm.LastSeen,
+		History:     alarm.History,
+		Created:     alarm.Created,
+	}
+}
+
+func buildEscalationPolicyResponse(policy alarms.EscalationPolicy) escalationPolicyResponse {
+	return escalationPolicyResponse{
+		ID:      policy.ID,
+		GroupID: policy.GroupID,
+		Name:    policy.Name,
+		Level:   policy.Level,
+		Steps:   policy.Steps,
 	}
 }
@@ -4,6 +4,7 @@
 package http_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -35,23 +36,26 @@ const (
 	thingID     = "5384fb1c-d0ae-4cbe-be52-c54223150fe0"
 	groupID     = "574106f7-030e-4881-8ab0-151195c29f94"
 	orgID       = "7e3d5e48-b0b4-4d7b-9d6a-c81f40e30e2c"
-	ruleID      = "5384fb1c-d0ae-4cbe-be52-c54223150fe1"
+	rulePrefix  = "5384fb1c-d0ae-4cbe-be52-"
 	subtopic    = "sensors"
 	protocol    = "mqtt"
 	ruleSub     = "alarms.rule"
+	notifierID  = "2fc4c3a8-5d0e-4c3b-9d3a-6f2b1d0e7a11"
 )
 
 type alarmRes struct {
-	ID       string           `json:"id"`
-	ThingID  string           `json:"thing_id"`
-	GroupID  string           `json:"group_id"`
-	RuleID   string           `json:"rule_id"`
-	Subtopic string           `json:"subtopic"`
-	Protocol string           `json:"protocol"`
-	Rule     *domain.RuleInfo `json:"rule,omitempty"`
-	Level    int32            `json:"level"`
-	Status   string           `json:"status"`
-	Created  int64            `json:"created"`
+	ID          string           `json:"id"`
+	ThingID     string           `json:"thing_id"`
+	GroupID     string           `json:"group_id"`
+	RuleID      string           `json:"rule_id"`
+	Subtopic    string           `json:"subtopic"`
+	Protocol    string           `json:"protocol"`
+	Rule        *domain.RuleInfo `json:"rule,omitempty"`
+	Level       int32            `json:"level"`
+	Status      string           `json:"status"`
+	Occurrences uint64           `json:"occurrences"`
+	LastSeen    int64            `json:"last_seen"`
+	Created     int64            `json:"created"`
 }
 
 type alarmsPageRes struct {
@@ -73,9 +77,10 @@ func newService() alarms.Service {
 		},
 	)
 	alarmRepo := alarmmocks.NewAlarmRepository()
+	escalationRepo := alarmmocks.NewEscalationRepository(alarmRepo)
 	idp := uuid.NewMock()
 
-	return alarms.New(ths, alarmRepo, idp)
+	return alarms.New(ths, alarmmocks.NewPublisher(), alarmRepo, escalationRepo, idp, logger.NewMock())
 }
 
 func newHTTPServer(svc alarms.Service) *httptest.Server {
@@ -135,23 +140,25 @@ func saveAlarms(t *testing.T, svc alarms.Service, n int) []alarmRes {
 			Protocol: protocol,
 			Created:  int64(1000000 + i),
 			Level:    int32(i % 3),
-			RuleId:   ruleID,
+			RuleId:   fmt.Sprintf("%s%012d", rulePrefix, i+1),
 			RuleInfo: ruleInfo,
 		}
 		err := svc.ConsumeAlarm(ruleSub, a)
 		require.Nil(t, err, fmt.Sprintf("unexpected error saving alarm %d: %s", i+1, err))
 
 		saved[i] = alarmRes{
-			ID:       fmt.Sprintf("%s%012d", uuid.Prefix, i+1),
-			ThingID:  a.ThingId,
-			GroupID:  groupID,
-			RuleID:   a.RuleId,
-			Subtopic: a.Subtopic,
-			Protocol: a.Protocol,
-			Rule:     &ri,
-			Level:    a.Level,
-			Status:   alarms.StatusActive,
-			Created:  a.Created,
+			ID:          fmt.Sprintf("%s%012d", uuid.Prefix, i+1),
+			ThingID:     a.ThingId,
+			GroupID:     groupID,
+			RuleID:      a.RuleId,
+			Subtopic:    a.Subtopic,
+			Protocol:    a.Protocol,
+			Rule:        &ri,
+			Level:       a.Level,
+			Status:      alarms.StatusActive,
+			Occurrences: 1,
+			LastSeen:    a.Created,
+			Created:     a.Created,
 		}
 	}
 
@@ -694,6 +701,14 @@ func TestUpdateAlarmStatus(t *testing.T) {
 			status:      alarms.StatusCleared,
 			httpStatus:  http.StatusOK,
 		},
+		{
+			desc:        "update status of cleared alarm",
+			auth:        token,
+			id:          alarmID,
+			contentType: contentType,
+			status:      alarms.StatusNoted,
+			httpStatus:  http.StatusConflict,
+		},
 		{
 			desc:        "update alarm status with invalid status",
 			auth:        token,
@@ -754,3 +769,162 @@ func TestUpdateAlarmStatus(t *testing.T) {
 		assert.Equal(t, tc.httpStatus, res.StatusCode, fmt.Sprintf("%s: expected status %d got %d\n", tc.desc, tc.httpStatus, res.StatusCode))
 	}
 }
+
+func TestCreateEscalationPolicies(t *testing.T) {
+	svc := newService()
+	ts := newHTTPServer(svc)
+	defer ts.Close()
+
+	step := alarms.EscalationStep{Delay: 5, Type: alarms.NotifierTypeSMTP, NotifierID: notifierID}
+
+	cases := []struct {
+		desc        string
+		auth        string
+		contentType string
+		groupID     string
+		policy      map[string]any
+		status      int
+	}{
+		{
+			desc:        "create escalation policy",
+			auth:        token,
+			contentType: contentType,
+			groupID:     groupID,
+			policy:      map[string]any{"name": "policy", "level": 2, "steps": []alarms.EscalationStep{step}},
+			status:      http.StatusCreated,
+		},
+		{
+			desc:        "create escalation policy with empty token",
+			auth:        emptyValue,
+			contentType: contentType,
+			groupID:     groupID,
+			policy:      map[string]any{"name": "policy", "level": 2, "steps": []alarms.EscalationStep{step}},
+			status:      http.StatusUnauthorized,
+		},
+		{
+			desc:        "create escalation policy without name",
+			auth:        token,
+			contentType: contentType,
+			groupID:     groupID,
+			policy:      map[string]any{"level": 2, "steps": []alarms.EscalationStep{step}},
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "create escalation policy with invalid level",
+			auth:        token,
+			contentType: contentType,
+			groupID:     groupID,
+			policy:      map[string]any{"name": "policy", "level": 6, "steps": []alarms.EscalationStep{step}},
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "create escalation policy without steps",
+			auth:        token,
+			contentType: contentType,
+			groupID:     groupID,
+			policy:      map[string]any{"name": "policy", "level": 2},
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "create escalation policy with invalid notifier type",
+			auth:        token,
+			contentType: contentType,
+			groupID:     groupID,
+			policy:      map[string]any{"name": "policy", "level": 2, "steps": []alarms.EscalationStep{{Delay: 5, Type: "http", NotifierID: notifierID}}},
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "create escalation policy without notifier id",
+			auth:        token,
+			contentType: contentType,
+			groupID:     groupID,
+			policy:      map[string]any{"name": "policy", "level": 2, "steps": []alarms.EscalationStep{{Delay: 5, Type: alarms.NotifierTypeSMPP}}},
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "create escalation policy for another group",
+			auth:        token,
+			contentType: contentType,
+			groupID:     wrongValue,
+			policy:      map[string]any{"name": "policy", "level": 2, "steps": []alarms.EscalationStep{step}},
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "create escalation policy without content type",
+			auth:        token,
+			contentType: emptyValue,
+			groupID:     groupID,
+			policy:      map[string]any{"name": "policy", "level": 2, "steps": []alarms.EscalationStep{step}},
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		body := toJSON(map[string]any{"escalation_policies": []map[string]any{tc.policy}})
+
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/groups/%s/escalations", ts.URL, tc.groupID),
+			token:       tc.auth,
+			contentType: tc.contentType,
+			body:        strings.NewReader(body),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status %d got %d\n", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestViewEscalationPolicy(t *testing.T) {
+	svc := newService()
+	ts := newHTTPServer(svc)
+	defer ts.Close()
+
+	policy := alarms.EscalationPolicy{
+		Name:  "policy",
+		Level: 2,
+		Steps: []alarms.EscalationStep{{Delay: 5, Type: alarms.NotifierTypeSMTP, NotifierID: notifierID}},
+	}
+	saved, err := svc.CreateEscalationPolicies(context.Background(), token, groupID, policy)
+	require.Nil(t, err, fmt.Sprintf("unexpected error creating escalation policy: %s", err))
+	policyID := saved[0].ID
+
+	cases := []struct {
+		desc   string
+		auth   string
+		id     string
+		status int
+	}{
+		{
+			desc:   "view existing escalation policy",
+			auth:   token,
+			id:     policyID,
+			status: http.StatusOK,
+		},
+		{
+			desc:   "view escalation policy with empty token",
+			auth:   emptyValue,
+			id:     policyID,
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "view non-existing escalation policy",
+			auth:   token,
+			id:     wrongValue,
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/escalations/%s", ts.URL, tc.id),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status %d got %d\n", tc.desc, tc.status, res.StatusCode))
+	}
+}
@@ -10,8 +10,12 @@ import (
 const (
 	minLen        = 1
 	maxLimitSize  = 200
+	maxNameSize   = 254
 	minAlarmLevel = 1
 	maxAlarmLevel = 5
+	maxSteps      = 10
+	// maxStepDelay is the maximum escalation step delay in minutes (one week).
+	maxStepDelay = 7 * 24 * 60
 )
 
 // validatePageMetadata validates the alarms page metadata.
@@ -175,3 +179,155 @@ func (req exportAlarmsByThingReq) validate() error {
 
 	return validatePageMetadata(req.pageMetadata)
 }
+
+// validateEscalationPageMetadata validates the escalation policies page metadata.
+func validateEscalationPageMetadata(pm alarms.PageMetadata) error {
+	common := apiutil.PageMetadata{Offset: pm.Offset, Limit: pm.Limit, Order: pm.Order, Dir: pm.Dir}
+	if err := common.Validate(maxLimitSize, alarms.EscalationPolicyOrderFields); err != nil {
+		return err
+	}
+
+	if len(pm.Name) > maxNameSize {
+		return apiutil.ErrNameSize
+	}
+
+	return nil
+}
+
+func validateEscalationPolicy(name string, level int32, steps []alarms.EscalationStep) error {
+	if name == "" || len(name) > maxNameSize {
+		return apiutil.ErrNameSize
+	}
+
+	if level < minAlarmLevel || level > maxAlarmLevel {
+		return apiutil.ErrInvalidAlarmLevel
+	}
+
+	if len(steps) < minLen || len(steps) > maxSteps {
+		return apiutil.ErrInvalidEscalationStep
+	}
+
+	for _, step := range steps {
+		if step.Delay > maxStepDelay || step.NotifierID == "" {
+			return apiutil.ErrInvalidEscalationStep
+		}
+
+		switch step.Type {
+		case alarms.NotifierTypeSMTP, alarms.NotifierTypeSMPP:
+		default:
+			return apiutil.ErrInvalidEscalationStep
+		}
+	}
+
+	return nil
+}
+
+type escalationPolicyReq struct {
+	token string
+	id    string
+}
+
+func (req escalationPolicyReq) validate() error {
+	if req.token == "" {
+		return apiutil.ErrBearerToken
+	}
+
+	if req.id == "" {
+		return apiutil.ErrMissingEscalationPolicyID
+	}
+
+	return nil
+}
+
+type createEscalationPolicyReq struct {
+	Name  string                  `json:"name"`
+	Level int32                   `json:"level"`
+	Steps []alarms.EscalationStep `json:"steps"`
+}
+
+type createEscalationPoliciesReq struct {
+	token    string
+	groupID  string
+	Policies []createEscalationPolicyReq `json:"escalation_policies"`
+}
+
+func (req createEscalationPoliciesReq) validate() error {
+	if req.token == "" {
+		return apiutil.ErrBearerToken
+	}
+
+	if req.groupID == "" {
+		return apiutil.ErrMissingGroupID
+	}
+
+	if len(req.Policies) < minLen {
+		return apiutil.ErrEmptyList
+	}
+
+	for _, p := range req.Policies {
+		if err := validateEscalationPolicy(p.Name, p.Level, p.Steps); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type listEscalationPoliciesReq struct {
+	token        string
+	groupID      string
+	pageMetadata alarms.PageMetadata
+}
+
+func (req listEscalationPoliciesReq) validate() error {
+	if req.token == "" {
+		return apiutil.ErrBearerToken
+	}
+
+	if req.groupID == "" {
+		return apiutil.ErrMissingGroupID
+	}
+
+	return validateEscalationPageMetadata(req.pageMetadata)
+}
+
+type updateEscalationPolicyReq struct {
+	token string
+	id    string
+	Name  string                  `json:"name"`
+	Level int32                   `json:"level"`
+	Steps []alarms.EscalationStep `json:"steps"`
+}
+
+func (req updateEscalationPolicyReq) validate() error {
+	if req.token == "" {
+		return apiutil.ErrBearerToken
+	}
+
+	if req.id == "" {
+		return apiutil.ErrMissingEscalationPolicyID
+	}
+
+	return validateEscalationPolicy(req.Name, req.Level, req.Steps)
+}
+
+type removeEscalationPoliciesReq struct {
+	token     string
+	PolicyIDs []string `json:"escalation_policy_ids,omitempty"`
+}
+
+func (req removeEscalationPoliciesReq) validate() error {
+	if req.token == "" {
+		return apiutil.ErrBearerToken
+	}
+
+	if len(req.PolicyIDs) < minLen {
+		return apiutil.ErrEmptyList
+	}
+
+	if slices.Contains(req.PolicyIDs, "") {
+		return apiutil.ErrMissingEscalationPolicyID
+	}
+
+	return nil
+}
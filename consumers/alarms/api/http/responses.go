@@ -6,21 +6,25 @@ package http
 import (
 	"net/http"
 
+	"github.com/MainfluxLabs/mainflux/consumers/alarms"
 	"github.com/MainfluxLabs/mainflux/pkg/domain"
 )
 
 type alarmResponse struct {
-	ID       string           `json:"id"`
-	ThingID  string           `json:"thing_id"`
-	GroupID  string           `json:"group_id"`
-	RuleID   string           `json:"rule_id,omitempty"`
-	ScriptID string           `json:"script_id,omitempty"`
-	Subtopic string           `json:"subtopic"`
-	Protocol string           `json:"protocol"`
-	Rule     *domain.RuleInfo `json:"rule,omitempty"`
-	Level    int32            `json:"level"`
-	Status   string           `json:"status"`
-	Created  int64            `json:"created"`
+	ID          string                `json:"id"`
+	ThingID     string                `json:"thing_id"`
+	GroupID     string                `json:"group_id"`
+	RuleID      string                `json:"rule_id,omitempty"`
+	ScriptID    string                `json:"script_id,omitempty"`
+	Subtopic    string                `json:"subtopic"`
+	Protocol    string                `json:"protocol"`
+	Rule        *domain.RuleInfo      `json:"rule,omitempty"`
+	Level       int32                 `json:"level"`
+	Status      string                `json:"status"`
+	Occurrences uint64                `json:"occurrences"`
+	LastSeen    int64                 `json:"last_seen"`
+	History     []alarms.StatusChange `json:"history,omitempty"`
+	Created     int64                 `json:"created"`
 }
 
 type AlarmsPageRes struct {
@@ -47,3 +51,61 @@ func (res exportFileRes) Headers() map[string]string {
 func (res exportFileRes) Empty() bool {
 	return len(res.file) == 0
 }
+
+type escalationPolicyResponse struct {
+	ID      string                  `json:"id"`
+	GroupID string                  `json:"group_id"`
+	Name    string                  `json:"name"`
+	Level   int32                   `json:"level"`
+	Steps   []alarms.EscalationStep `json:"steps"`
+}
+
+func (res escalationPolicyResponse) Code() int {
+	return http.StatusOK
+}
+
+func (res escalationPolicyResponse) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res escalationPolicyResponse) Empty() bool {
+	return false
+}
+
+type escalationPoliciesRes struct {
+	Policies []escalationPolicyResponse `json:"escalation_policies"`
+}
+
+func (res escalationPoliciesRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res escalationPoliciesRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res escalationPoliciesRes) Empty() bool {
+	return false
+}
+
+type EscalationPoliciesPageRes struct {
+	Total    uint64                     `json:"total"`
+	Offset   uint64                     `json:"offset"`
+	Limit    uint64                     `json:"limit"`
+	Order    string                     `json:"order,omitempty"`
+	Dir      string                     `json:"dir,omitempty"`
+	Name     string                     `json:"name,omitempty"`
+	Policies []escalationPolicyResponse `json:"escalation_policies"`
+}
+
+func (res EscalationPoliciesPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res EscalationPoliciesPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res EscalationPoliciesPageRes) Empty() bool {
+	return false
+}
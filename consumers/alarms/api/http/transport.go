@@ -116,6 +116,56 @@ func MakeHandler(tracer opentracing.Tracer, svc alarms.Service, ac domain.AuthCl
 		opts...,
 	))
 
+	r.Post("/groups/:id/escalations", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "create_escalation_policies"),
+			withIdentity,
+		)(createEscalationPoliciesEndpoint(svc)),
+		decodeCreateEscalationPolicies,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/groups/:id/escalations", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "list_escalation_policies_by_group"),
+			withIdentity,
+		)(listEscalationPoliciesByGroupEndpoint(svc)),
+		decodeListEscalationPolicies,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/escalations/:id", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "view_escalation_policy"),
+			withIdentity,
+		)(viewEscalationPolicyEndpoint(svc)),
+		decodeViewEscalationPolicy,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Put("/escalations/:id", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "update_escalation_policy"),
+			withIdentity,
+		)(updateEscalationPolicyEndpoint(svc)),
+		decodeUpdateEscalationPolicy,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Patch("/escalations", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "remove_escalation_policies"),
+			withIdentity,
+		)(removeEscalationPoliciesEndpoint(svc)),
+		decodeRemoveEscalationPolicies,
+		encodeResponse,
+		opts...,
+	))
+
 	r.GetFunc("/health", mainflux.Health("alarms"))
 	r.Handle("/metrics", promhttp.Handler())
 
@@ -278,6 +328,87 @@ func decodeExportAlarmsByThing(_ context.Context, r *http.Request) (any, error)
 	}, nil
 }
 
+func decodeCreateEscalationPolicies(_ context.Context, r *http.Request) (any, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), apiutil.ContentTypeJSON) {
+		return nil, apiutil.ErrUnsupportedContentType
+	}
+
+	req := createEscalationPoliciesReq{
+		token:   apiutil.ExtractBearerToken(r),
+		groupID: bone.GetValue(r, apiutil.IDKey),
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeListEscalationPolicies(_ context.Context, r *http.Request) (any, error) {
+	base, err := apiutil.BuildPageMetadata(r)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := apiutil.ReadStringQuery(r, apiutil.NameKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return listEscalationPoliciesReq{
+		token:   apiutil.ExtractBearerToken(r),
+		groupID: bone.GetValue(r, apiutil.IDKey),
+		pageMetadata: alarms.PageMetadata{
+			Offset: base.Offset,
+			Limit:  base.Limit,
+			Order:  base.Order,
+			Dir:    base.Dir,
+			Name:   n,
+		},
+	}, nil
+}
+
+func decodeViewEscalationPolicy(_ context.Context, r *http.Request) (any, error) {
+	return escalationPolicyReq{
+		token: apiutil.ExtractBearerToken(r),
+		id:    bone.GetValue(r, apiutil.IDKey),
+	}, nil
+}
+
+func decodeUpdateEscalationPolicy(_ context.Context, r *http.Request) (any, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), apiutil.ContentTypeJSON) {
+		return nil, apiutil.ErrUnsupportedContentType
+	}
+
+	req := updateEscalationPolicyReq{
+		token: apiutil.ExtractBearerToken(r),
+		id:    bone.GetValue(r, apiutil.IDKey),
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeRemoveEscalationPolicies(_ context.Context, r *http.Request) (any, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), apiutil.ContentTypeJSON) {
+		return nil, apiutil.ErrUnsupportedContentType
+	}
+
+	req := removeEscalationPoliciesReq{
+		token: apiutil.ExtractBearerToken(r),
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
 func encodeResponse(_ context.Context, w http.ResponseWriter, response any) error {
 	w.Header().Set("Content-Type", apiutil.ContentTypeJSON)
 
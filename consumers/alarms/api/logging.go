@@ -147,6 +147,115 @@ func (lm loggingMiddleware) ExportAlarmsByThing(ctx context.Context, token, thin
 	return lm.svc.ExportAlarmsByThing(ctx, token, thingID, pm)
 }
 
+func (lm loggingMiddleware) CreateEscalationPolicies(ctx context.Context, token, groupID string, policies ...alarms.EscalationPolicy) (_ []alarms.EscalationPolicy, err error) {
+	defer func(begin time.Time) {
+		email := authn.EmailFromToken(token)
+		message := fmt.Sprintf("Method create_escalation_policies by user %s, group id %s took %s to complete", email, groupID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.CreateEscalationPolicies(ctx, token, groupID, policies...)
+}
+
+func (lm loggingMiddleware) ListEscalationPoliciesByGroup(ctx context.Context, token, groupID string, pm alarms.PageMetadata) (_ alarms.EscalationPoliciesPage, err error) {
+	defer func(begin time.Time) {
+		email := authn.EmailFromToken(token)
+		message := fmt.Sprintf("Method list_escalation_policies_by_group by user %s, group id %s took %s to complete", email, groupID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListEscalationPoliciesByGroup(ctx, token, groupID, pm)
+}
+
+func (lm loggingMiddleware) ViewEscalationPolicy(ctx context.Context, token, id string) (_ alarms.EscalationPolicy, err error) {
+	defer func(begin time.Time) {
+		email := authn.EmailFromToken(token)
+		message := fmt.Sprintf("Method view_escalation_policy by user %s, escalation policy id %s took %s to complete", email, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewEscalationPolicy(ctx, token, id)
+}
+
+func (lm loggingMiddleware) UpdateEscalationPolicy(ctx context.Context, token string, policy alarms.EscalationPolicy) (err error) {
+	defer func(begin time.Time) {
+		email := authn.EmailFromToken(token)
+		message := fmt.Sprintf("Method update_escalation_policy by user %s, escalation policy id %s took %s to complete", email, policy.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.UpdateEscalationPolicy(ctx, token, policy)
+}
+
+func (lm loggingMiddleware) RemoveEscalationPolicies(ctx context.Context, token string, ids ...string) (err error) {
+	defer func(begin time.Time) {
+		email := authn.EmailFromToken(token)
+		message := fmt.Sprintf("Method remove_escalation_policies by user %s, escalation policy ids %s took %s to complete", email, ids, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveEscalationPolicies(ctx, token, ids...)
+}
+
+func (lm loggingMiddleware) RemoveEscalationPoliciesByGroup(ctx context.Context, groupID string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_escalation_policies_by_group for group id %s took %s to complete", groupID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveEscalationPoliciesByGroup(ctx, groupID)
+}
+
+func (lm loggingMiddleware) EscalateAlarms(ctx context.Context) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method escalate_alarms took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.EscalateAlarms(ctx)
+}
+
+func (lm loggingMiddleware) ScheduleEscalations(ctx context.Context) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method schedule_escalations took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ScheduleEscalations(ctx)
+}
+
 func (lm loggingMiddleware) ConsumeAlarm(subject string, alarm protomfx.Alarm) (err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method consume_alarm took %s to complete", time.Since(begin))
@@ -107,6 +107,78 @@ func (ms *metricsMiddleware) ExportAlarmsByThing(ctx context.Context, token, thi
 	return ms.svc.ExportAlarmsByThing(ctx, token, thingID, pm)
 }
 
+func (ms *metricsMiddleware) CreateEscalationPolicies(ctx context.Context, token, groupID string, policies ...alarms.EscalationPolicy) ([]alarms.EscalationPolicy, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "create_escalation_policies").Add(1)
+		ms.latency.With("method", "create_escalation_policies").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CreateEscalationPolicies(ctx, token, groupID, policies...)
+}
+
+func (ms *metricsMiddleware) ListEscalationPoliciesByGroup(ctx context.Context, token, groupID string, pm alarms.PageMetadata) (alarms.EscalationPoliciesPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_escalation_policies_by_group").Add(1)
+		ms.latency.With("method", "list_escalation_policies_by_group").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListEscalationPoliciesByGroup(ctx, token, groupID, pm)
+}
+
+func (ms *metricsMiddleware) ViewEscalationPolicy(ctx context.Context, token, id string) (alarms.EscalationPolicy, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_escalation_policy").Add(1)
+		ms.latency.With("method", "view_escalation_policy").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewEscalationPolicy(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) UpdateEscalationPolicy(ctx context.Context, token string, policy alarms.EscalationPolicy) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "update_escalation_policy").Add(1)
+		ms.latency.With("method", "update_escalation_policy").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UpdateEscalationPolicy(ctx, token, policy)
+}
+
+func (ms *metricsMiddleware) RemoveEscalationPolicies(ctx context.Context, token string, ids ...string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_escalation_policies").Add(1)
+		ms.latency.With("method", "remove_escalation_policies").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveEscalationPolicies(ctx, token, ids...)
+}
+
+func (ms *metricsMiddleware) RemoveEscalationPoliciesByGroup(ctx context.Context, groupID string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_escalation_policies_by_group").Add(1)
+		ms.latency.With("method", "remove_escalation_policies_by_group").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveEscalationPoliciesByGroup(ctx, groupID)
+}
+
+func (ms *metricsMiddleware) EscalateAlarms(ctx context.Context) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "escalate_alarms").Add(1)
+		ms.latency.With("method", "escalate_alarms").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.EscalateAlarms(ctx)
+}
+
+func (ms *metricsMiddleware) ScheduleEscalations(ctx context.Context) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "schedule_escalations").Add(1)
+		ms.latency.With("method", "schedule_escalations").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ScheduleEscalations(ctx)
+}
+
 func (ms *metricsMiddleware) ConsumeAlarm(subject string, alarm protomfx.Alarm) error {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "consume_alarm").Add(1)
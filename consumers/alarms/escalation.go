@@ -0,0 +1,214 @@
+package alarms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/MainfluxLabs/mainflux/pkg/cron"
+	protomfx "github.com/MainfluxLabs/mainflux/pkg/proto"
+)
+
+const (
+	NotifierTypeSMTP = "smtp"
+	NotifierTypeSMPP = "smpp"
+
+	escalationsTaskID = "escalations"
+)
+
+// EscalationPolicy represents a chain of notifiers that are notified, one after another,
+// while an alarm of the group with at least the policy level stays active.
+type EscalationPolicy struct {
+	ID      string
+	GroupID string
+	Name    string
+	// Level is the minimum level of the alarms the policy applies to.
+	Level int32
+	Steps []EscalationStep
+}
+
+// EscalationStep represents a notifier notified once an alarm has been active for the
+// step delay after the previous step, or after the alarm was raised for the first step.
+type EscalationStep struct {
+	// Delay is the step delay in minutes.
+	Delay uint64 `json:"delay"`
+	// Type is the notifier type, smtp or smpp.
+	Type       string `json:"type"`
+	NotifierID string `json:"notifier_id"`
+}
+
+// EscalationPoliciesPage contains a page of escalation policies.
+type EscalationPoliciesPage struct {
+	Total    uint64
+	Policies []EscalationPolicy
+}
+
+// Escalation represents the escalation of an active alarm by a policy.
+type Escalation struct {
+	Alarm Alarm
+	// Step is the number of policy steps already notified.
+	Step int
+}
+
+// EscalationPolicyOrderFields maps API-facing order keys to SQL column expressions for the escalation policies table.
+var EscalationPolicyOrderFields = map[string]string{
+	"id":    "id",
+	"name":  "LOWER(name)",
+	"level": "level",
+}
+
+// EscalationRepository specifies an escalation policy persistence API.
+type EscalationRepository interface {
+	// Save persists multiple escalation policies. Policies are saved using a transaction.
+	// If one policy fails, none will be saved.
+	Save(ctx context.Context, policies ...EscalationPolicy) ([]EscalationPolicy, error)
+
+	// RetrieveByID retrieves the escalation policy having the provided ID.
+	RetrieveByID(ctx context.Context, id string) (EscalationPolicy, error)
+
+	// RetrieveByGroup retrieves escalation policies related to a certain group,
+	// identified by a given group ID.
+	RetrieveByGroup(ctx context.Context, groupID string, pm PageMetadata) (EscalationPoliciesPage, error)
+
+	// RetrieveAll retrieves all escalation policies.
+	RetrieveAll(ctx context.Context) ([]EscalationPolicy, error)
+
+	// Update performs an update to the existing escalation policy.
+	Update(ctx context.Context, policy EscalationPolicy) error
+
+	// Remove removes escalation policies having the provided IDs.
+	Remove(ctx context.Context, ids ...string) error
+
+	// RemoveByGroup removes escalation policies related to a certain group,
+	// identified by a given group ID.
+	RemoveByGroup(ctx context.Context, groupID string) error
+
+	// RetrieveEscalations retrieves the active alarms the policy applies to,
+	// whose escalation hasn't reached the last policy step.
+	RetrieveEscalations(ctx context.Context, policy EscalationPolicy) ([]Escalation, error)
+
+	// UpdateStep records that the escalation of the alarm by the policy reached the given step.
+	// It reports false if the step was already reached, e.g. by another service instance.
+	UpdateStep(ctx context.Context, alarmID, policyID string, step int) (bool, error)
+}
+
+// escalationNotification is the payload of the notifications sent by escalation policy steps.
+type escalationNotification struct {
+	AlarmID     string `json:"alarm_id"`
+	ThingID     string `json:"thing_id"`
+	GroupID     string `json:"group_id"`
+	RuleID      string `json:"rule_id,omitempty"`
+	ScriptID    string `json:"script_id,omitempty"`
+	Subtopic    string `json:"subtopic,omitempty"`
+	Level       int32  `json:"level"`
+	Status      string `json:"status"`
+	Occurrences uint64 `json:"occurrences"`
+	Created     int64  `json:"created"`
+	PolicyID    string `json:"escalation_policy_id"`
+	PolicyName  string `json:"escalation_policy_name,omitempty"`
+	Step        int    `json:"step"`
+}
+
+func (as *alarmService) EscalateAlarms(ctx context.Context) error {
+	policies, err := as.escalations.RetrieveAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UnixNano()
+	for _, policy := range policies {
+		if err := as.escalate(ctx, policy, now); err != nil {
+			as.logger.Warn(fmt.Sprintf("failed to escalate alarms by policy %s: %s", policy.ID, err))
+		}
+	}
+
+	return nil
+}
+
+func (as *alarmService) ScheduleEscalations(ctx context.Context) error {
+	task := func() {
+		if err := as.EscalateAlarms(ctx); err != nil {
+			as.logger.Warn(fmt.Sprintf("failed to escalate alarms: %s", err))
+		}
+	}
+
+	if err := as.scheduler.ScheduleRepeatingTask(task, cron.Scheduler{TimeZone: "UTC", Frequency: cron.MinutelyFreq, Minute: 1}, escalationsTaskID); err != nil {
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		as.scheduler.Stop()
+	}()
+
+	return nil
+}
+
+// escalate notifies the notifiers of the policy steps that are due at time now
+// for the active alarms the policy applies to.
+func (as *alarmService) escalate(ctx context.Context, policy EscalationPolicy, now int64) error {
+	escalations, err := as.escalations.RetrieveEscalations(ctx, policy)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range escalations {
+		for step := e.Step; step < len(policy.Steps) && now >= stepDue(e.Alarm, policy, step); step++ {
+			ok, err := as.escalations.UpdateStep(ctx, e.Alarm.ID, policy.ID, step+1)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				break
+			}
+
+			if err := as.notify(e.Alarm, policy, step, now); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// stepDue returns the time (unix nanoseconds) the policy step is due for the alarm:
+// the alarm creation time delayed by the delays of the steps up to and including the step.
+func stepDue(alarm Alarm, policy EscalationPolicy, step int) int64 {
+	var delay uint64
+	for _, s := range policy.Steps[:step+1] {
+		delay += s.Delay
+	}
+
+	return alarm.Created + int64(time.Duration(delay)*time.Minute)
+}
+
+func (as *alarmService) notify(alarm Alarm, policy EscalationPolicy, step int, now int64) error {
+	payload, err := json.Marshal(escalationNotification{
+		AlarmID:     alarm.ID,
+		ThingID:     alarm.ThingID,
+		GroupID:     alarm.GroupID,
+		RuleID:      alarm.RuleID,
+		ScriptID:    alarm.ScriptID,
+		Subtopic:    alarm.Subtopic,
+		Level:       alarm.Level,
+		Status:      alarm.Status,
+		Occurrences: alarm.Occurrences,
+		Created:     alarm.Created,
+		PolicyID:    policy.ID,
+		PolicyName:  policy.Name,
+		Step:        step + 1,
+	})
+	if err != nil {
+		return err
+	}
+
+	s := policy.Steps[step]
+	return as.pub.PublishNotification(fmt.Sprintf("%s.%s", s.Type, s.NotifierID), protomfx.Notification{
+		ThingId:  alarm.ThingID,
+		Subtopic: alarm.Subtopic,
+		Protocol: alarm.Protocol,
+		Payload:  payload,
+		Created:  now,
+	})
+}
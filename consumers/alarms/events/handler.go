@@ -24,6 +24,9 @@ func (h *eventHandler) Handle(ctx context.Context, event events.Event) error {
 	case events.ThingRemoved:
 		return h.svc.RemoveAlarmsByThing(ctx, e.ID)
 	case events.GroupRemoved:
+		if err := h.svc.RemoveEscalationPoliciesByGroup(ctx, e.ID); err != nil {
+			return err
+		}
 		return h.svc.RemoveAlarmsByGroup(ctx, e.ID)
 	}
 	return nil
@@ -118,7 +118,37 @@ func (arm *alarmRepositoryMock) retrieve(als []alarms.Alarm, pm alarms.PageMetad
 	}
 }
 
-func (arm *alarmRepositoryMock) UpdateStatus(_ context.Context, id, status string) error {
+func (arm *alarmRepositoryMock) RetrieveOpen(_ context.Context, alarm alarms.Alarm) (alarms.Alarm, error) {
+	arm.mu.Lock()
+	defer arm.mu.Unlock()
+
+	for _, a := range arm.alarms {
+		if a.ThingID == alarm.ThingID && a.RuleID == alarm.RuleID && a.ScriptID == alarm.ScriptID &&
+			a.Subtopic == alarm.Subtopic && a.Status != alarms.StatusCleared {
+			return a, nil
+		}
+	}
+
+	return alarms.Alarm{}, dbutil.ErrNotFound
+}
+
+func (arm *alarmRepositoryMock) UpdateOccurrence(_ context.Context, id string, seen int64) error {
+	arm.mu.Lock()
+	defer arm.mu.Unlock()
+
+	a, ok := arm.alarms[id]
+	if !ok {
+		return dbutil.ErrNotFound
+	}
+
+	a.Occurrences++
+	a.LastSeen = max(a.LastSeen, seen)
+	arm.alarms[id] = a
+
+	return nil
+}
+
+func (arm *alarmRepositoryMock) UpdateStatus(_ context.Context, id string, change alarms.StatusChange) error {
 	arm.mu.Lock()
 	defer arm.mu.Unlock()
 
@@ -127,7 +157,8 @@ func (arm *alarmRepositoryMock) UpdateStatus(_ context.Context, id, status strin
 		return dbutil.ErrNotFound
 	}
 
-	a.Status = status
+	a.Status = change.Status
+	a.History = append(append([]alarms.StatusChange{}, a.History...), change)
 	arm.alarms[id] = a
 
 	return nil
@@ -0,0 +1,173 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/MainfluxLabs/mainflux/consumers/alarms"
+	"github.com/MainfluxLabs/mainflux/pkg/dbutil"
+	"github.com/MainfluxLabs/mainflux/pkg/uuid"
+)
+
+var _ alarms.EscalationRepository = (*escalationRepositoryMock)(nil)
+
+type escalationRepositoryMock struct {
+	mu       sync.Mutex
+	alarms   alarms.AlarmRepository
+	policies map[string]alarms.EscalationPolicy
+	// steps holds the reached escalation steps, indexed by alarm ID and policy ID.
+	steps map[string]map[string]int
+}
+
+// NewEscalationRepository creates in-memory escalation policy repository used for testing.
+// Escalated alarms are retrieved from the provided alarm repository.
+func NewEscalationRepository(alarmRepo alarms.AlarmRepository) alarms.EscalationRepository {
+	return &escalationRepositoryMock{
+		alarms:   alarmRepo,
+		policies: make(map[string]alarms.EscalationPolicy),
+		steps:    make(map[string]map[string]int),
+	}
+}
+
+func (erm *escalationRepositoryMock) Save(_ context.Context, policies ...alarms.EscalationPolicy) ([]alarms.EscalationPolicy, error) {
+	erm.mu.Lock()
+	defer erm.mu.Unlock()
+
+	for _, p := range policies {
+		erm.policies[p.ID] = p
+	}
+
+	return policies, nil
+}
+
+func (erm *escalationRepositoryMock) RetrieveByID(_ context.Context, id string) (alarms.EscalationPolicy, error) {
+	erm.mu.Lock()
+	defer erm.mu.Unlock()
+
+	p, ok := erm.policies[id]
+	if !ok {
+		return alarms.EscalationPolicy{}, dbutil.ErrNotFound
+	}
+
+	return p, nil
+}
+
+func (erm *escalationRepositoryMock) RetrieveByGroup(_ context.Context, groupID string, pm alarms.PageMetadata) (alarms.EscalationPoliciesPage, error) {
+	erm.mu.Lock()
+	defer erm.mu.Unlock()
+
+	var all, items []alarms.EscalationPolicy
+	first := uint64(pm.Offset) + 1
+	last := first + pm.Limit
+
+	for _, p := range erm.policies {
+		if p.GroupID == groupID {
+			all = append(all, p)
+			id := uuid.ParseID(p.ID)
+			if pm.Limit == 0 || (id >= first && id < last) {
+				items = append(items, p)
+			}
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return uuid.ParseID(items[i].ID) < uuid.ParseID(items[j].ID)
+	})
+
+	return alarms.EscalationPoliciesPage{
+		Total:    uint64(len(all)),
+		Policies: items,
+	}, nil
+}
+
+func (erm *escalationRepositoryMock) RetrieveAll(_ context.Context) ([]alarms.EscalationPolicy, error) {
+	erm.mu.Lock()
+	defer erm.mu.Unlock()
+
+	var items []alarms.EscalationPolicy
+	for _, p := range erm.policies {
+		items = append(items, p)
+	}
+
+	return items, nil
+}
+
+func (erm *escalationRepositoryMock) Update(_ context.Context, policy alarms.EscalationPolicy) error {
+	erm.mu.Lock()
+	defer erm.mu.Unlock()
+
+	p, ok := erm.policies[policy.ID]
+	if !ok {
+		return dbutil.ErrNotFound
+	}
+
+	p.Name = policy.Name
+	p.Level = policy.Level
+	p.Steps = policy.Steps
+	erm.policies[policy.ID] = p
+
+	return nil
+}
+
+func (erm *escalationRepositoryMock) Remove(_ context.Context, ids ...string) error {
+	erm.mu.Lock()
+	defer erm.mu.Unlock()
+
+	for _, id := range ids {
+		if _, ok := erm.policies[id]; !ok {
+			return dbutil.ErrNotFound
+		}
+		delete(erm.policies, id)
+	}
+
+	return nil
+}
+
+func (erm *escalationRepositoryMock) RemoveByGroup(_ context.Context, groupID string) error {
+	erm.mu.Lock()
+	defer erm.mu.Unlock()
+
+	for id, p := range erm.policies {
+		if p.GroupID == groupID {
+			delete(erm.policies, id)
+		}
+	}
+
+	return nil
+}
+
+func (erm *escalationRepositoryMock) RetrieveEscalations(ctx context.Context, policy alarms.EscalationPolicy) ([]alarms.Escalation, error) {
+	page, err := erm.alarms.RetrieveByGroup(ctx, policy.GroupID, alarms.PageMetadata{Status: alarms.StatusActive})
+	if err != nil {
+		return nil, err
+	}
+
+	erm.mu.Lock()
+	defer erm.mu.Unlock()
+
+	var items []alarms.Escalation
+	for _, a := range page.Alarms {
+		step := erm.steps[a.ID][policy.ID]
+		if a.Level >= policy.Level && step < len(policy.Steps) {
+			items = append(items, alarms.Escalation{Alarm: a, Step: step})
+		}
+	}
+
+	return items, nil
+}
+
+func (erm *escalationRepositoryMock) UpdateStep(_ context.Context, alarmID, policyID string, step int) (bool, error) {
+	erm.mu.Lock()
+	defer erm.mu.Unlock()
+
+	if _, ok := erm.steps[alarmID]; !ok {
+		erm.steps[alarmID] = make(map[string]int)
+	}
+	if erm.steps[alarmID][policyID] >= step {
+		return false, nil
+	}
+	erm.steps[alarmID][policyID] = step
+
+	return true, nil
+}
@@ -0,0 +1,39 @@
+package mocks
+
+import (
+	"sync"
+
+	"github.com/MainfluxLabs/mainflux/consumers/alarms"
+	protomfx "github.com/MainfluxLabs/mainflux/pkg/proto"
+)
+
+var _ alarms.Publisher = (*PublisherMock)(nil)
+
+// PublisherMock is an in-memory publisher that records the published notifications.
+type PublisherMock struct {
+	mu            sync.Mutex
+	notifications map[string][]protomfx.Notification
+}
+
+// NewPublisher creates a publisher mock used for testing.
+func NewPublisher() *PublisherMock {
+	return &PublisherMock{
+		notifications: make(map[string][]protomfx.Notification),
+	}
+}
+
+func (pm *PublisherMock) PublishNotification(subject string, notification protomfx.Notification) error {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	pm.notifications[subject] = append(pm.notifications[subject], notification)
+	return nil
+}
+
+// Notifications returns the notifications published to the subject.
+func (pm *PublisherMock) Notifications(subject string) []protomfx.Notification {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	return pm.notifications[subject]
+}
@@ -35,8 +35,8 @@ func (ar *alarmRepository) Save(ctx context.Context, alarms ...alarms.Alarm) err
 	}
 	defer tx.Rollback()
 
-	q := `INSERT INTO alarms (id, thing_id, group_id, rule_id, script_id, subtopic, protocol, rule, level, status, created)
-	      VALUES (:id, :thing_id, :group_id, :rule_id, :script_id, :subtopic, :protocol, :rule, :level, :status, :created);`
+	q := `INSERT INTO alarms (id, thing_id, group_id, rule_id, script_id, subtopic, protocol, rule, level, status, occurrences, last_seen, history, created)
+	      VALUES (:id, :thing_id, :group_id, :rule_id, :script_id, :subtopic, :protocol, :rule, :level, :status, :occurrences, :last_seen, :history, :created);`
 
 	for _, alarm := range alarms {
 		dbAlarm, err := toDBAlarm(alarm)
@@ -66,12 +66,62 @@ func (ar *alarmRepository) Save(ctx context.Context, alarms ...alarms.Alarm) err
 	return nil
 }
 
-func (ar *alarmRepository) UpdateStatus(ctx context.Context, id, status string) error {
-	q := `UPDATE alarms SET status = :status WHERE id = :id;`
+func (ar *alarmRepository) RetrieveOpen(ctx context.Context, alarm alarms.Alarm) (alarms.Alarm, error) {
+	q := `SELECT id, thing_id, group_id, rule_id, script_id, subtopic, protocol, rule, level, status, occurrences, last_seen, history, created
+	      FROM alarms WHERE thing_id = $1 AND COALESCE(rule_id, script_id) = $2 AND COALESCE(subtopic, '') = $3 AND status <> 'cleared';`
 
-	dba := dbAlarm{ID: id, Status: status}
+	originID := alarm.RuleID
+	if originID == "" {
+		originID = alarm.ScriptID
+	}
+
+	var dba dbAlarm
+	if err := ar.db.QueryRowxContext(ctx, q, alarm.ThingID, originID, alarm.Subtopic).StructScan(&dba); err != nil {
+		pgErr, ok := err.(*pgconn.PgError)
+		if err == sql.ErrNoRows || ok && pgerrcode.InvalidTextRepresentation == pgErr.Code {
+			return alarms.Alarm{}, errors.Wrap(dbutil.ErrNotFound, err)
+		}
+		return alarms.Alarm{}, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+	}
+
+	return toAlarm(dba)
+}
+
+func (ar *alarmRepository) UpdateOccurrence(ctx context.Context, id string, seen int64) error {
+	q := `UPDATE alarms SET occurrences = occurrences + 1, last_seen = GREATEST(last_seen, :last_seen) WHERE id = :id;`
+
+	dba := dbAlarm{ID: id, LastSeen: seen}
+	res, err := ar.db.NamedExecContext(ctx, q, dba)
+	if err != nil {
+		return errors.Wrap(dbutil.ErrUpdateEntity, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(dbutil.ErrUpdateEntity, err)
+	}
+	if cnt == 0 {
+		return dbutil.ErrNotFound
+	}
+
+	return nil
+}
+
+func (ar *alarmRepository) UpdateStatus(ctx context.Context, id string, change alarms.StatusChange) error {
+	q := `UPDATE alarms SET status = :status, history = history || CAST(:history AS JSONB) WHERE id = :id;`
+
+	history, err := json.Marshal([]alarms.StatusChange{change})
+	if err != nil {
+		return errors.Wrap(dbutil.ErrUpdateEntity, err)
+	}
+
+	dba := dbAlarm{ID: id, Status: change.Status, History: history}
 	res, err := ar.db.NamedExecContext(ctx, q, dba)
 	if err != nil {
+		pgErr, ok := err.(*pgconn.PgError)
+		if ok && pgErr.Code == pgerrcode.UniqueViolation {
+			return errors.Wrap(dbutil.ErrConflict, err)
+		}
 		return errors.Wrap(dbutil.ErrUpdateEntity, err)
 	}
 
@@ -87,7 +137,7 @@ func (ar *alarmRepository) UpdateStatus(ctx context.Context, id, status string)
 }
 
 func (ar *alarmRepository) RetrieveByID(ctx context.Context, id string) (alarms.Alarm, error) {
-	q := `SELECT id, thing_id, group_id, rule_id, script_id, subtopic, protocol, rule, level, status, created FROM alarms WHERE id = $1;`
+	q := `SELECT id, thing_id, group_id, rule_id, script_id, subtopic, protocol, rule, level, status, occurrences, last_seen, history, created FROM alarms WHERE id = $1;`
 
 	var dba dbAlarm
 	if err := ar.db.QueryRowxContext(ctx, q, id).StructScan(&dba); err != nil {
@@ -113,7 +163,7 @@ func (ar *alarmRepository) RetrieveByThing(ctx context.Context, thingID string,
 	sq, subtopic := dbutil.GetLikeQuery("subtopic", pm.Subtopic)
 	whereClause := dbutil.BuildWhereClause("thing_id = :thing_id", levelQuery(pm.Level), statusQuery(pm.Status), protocolQuery(pm.Protocol), sq, timeRangeQuery(pm.From, pm.To))
 
-	q := fmt.Sprintf(`SELECT id, thing_id, group_id, rule_id, script_id, subtopic, protocol, rule, level, status, created
+	q := fmt.Sprintf(`SELECT id, thing_id, group_id, rule_id, script_id, subtopic, protocol, rule, level, status, occurrences, last_seen, history, created
 	                  FROM alarms %s ORDER BY %s %s %s;`, whereClause, oq, dq, olq)
 	qc := fmt.Sprintf(`SELECT COUNT(*) FROM alarms %s;`, whereClause)
 
@@ -144,7 +194,7 @@ func (ar *alarmRepository) RetrieveByGroup(ctx context.Context, groupID string,
 	sq, subtopic := dbutil.GetLikeQuery("subtopic", pm.Subtopic)
 	whereClause := dbutil.BuildWhereClause("group_id = :group_id", levelQuery(pm.Level), statusQuery(pm.Status), protocolQuery(pm.Protocol), sq, timeRangeQuery(pm.From, pm.To))
 
-	q := fmt.Sprintf(`SELECT id, thing_id, group_id, rule_id, script_id, subtopic, protocol, rule, level, status, created
+	q := fmt.Sprintf(`SELECT id, thing_id, group_id, rule_id, script_id, subtopic, protocol, rule, level, status, occurrences, last_seen, history, created
 	                  FROM alarms %s ORDER BY %s %s %s;`, whereClause, oq, dq, olq)
 	qc := fmt.Sprintf(`SELECT COUNT(*) FROM alarms %s;`, whereClause)
 
@@ -175,7 +225,7 @@ func (ar *alarmRepository) RetrieveByGroups(ctx context.Context, groupIDs []stri
 	sq, subtopic := dbutil.GetLikeQuery("subtopic", pm.Subtopic)
 	whereClause := dbutil.BuildWhereClause(dbutil.GetGroupIDsQuery(groupIDs), levelQuery(pm.Level), statusQuery(pm.Status), protocolQuery(pm.Protocol), sq, timeRangeQuery(pm.From, pm.To))
 
-	query := fmt.Sprintf(`SELECT id, thing_id, group_id, rule_id, script_id, subtopic, protocol, rule, level, status, created FROM alarms %s ORDER BY %s %s %s;`, whereClause, oq, dq, olq)
+	query := fmt.Sprintf(`SELECT id, thing_id, group_id, rule_id, script_id, subtopic, protocol, rule, level, status, occurrences, last_seen, history, created FROM alarms %s ORDER BY %s %s %s;`, whereClause, oq, dq, olq)
 	cquery := fmt.Sprintf(`SELECT COUNT(*) FROM alarms %s;`, whereClause)
 
 	params := map[string]any{
@@ -237,7 +287,7 @@ func (ar *alarmRepository) ExportByThing(ctx context.Context, thingID string, pm
 	dq := dbutil.GetDirQuery(pm.Dir)
 	whereClause := dbutil.BuildWhereClause("thing_id = :thing_id")
 
-	q := fmt.Sprintf(`SELECT id, thing_id, group_id, rule_id, script_id, subtopic, protocol, rule, level, status, created
+	q := fmt.Sprintf(`SELECT id, thing_id, group_id, rule_id, script_id, subtopic, protocol, rule, level, status, occurrences, last_seen, history, created
 	                  FROM alarms %s ORDER BY %s %s;`, whereClause, oq, dq)
 	qc := fmt.Sprintf(`SELECT COUNT(*) FROM alarms %s;`, whereClause)
 
@@ -284,17 +334,20 @@ func (ar *alarmRepository) retrieve(ctx context.Context, query, cquery string, p
 }
 
 type dbAlarm struct {
-	ID       string         `db:"id"`
-	ThingID  string         `db:"thing_id"`
-	GroupID  string         `db:"group_id"`
-	RuleID   sql.NullString `db:"rule_id"`
-	ScriptID sql.NullString `db:"script_id"`
-	Subtopic string         `db:"subtopic"`
-	Protocol string         `db:"protocol"`
-	Rule     []byte         `db:"rule"`
-	Level    int32          `db:"level"`
-	Status   string         `db:"status"`
-	Created  int64          `db:"created"`
+	ID          string         `db:"id"`
+	ThingID     string         `db:"thing_id"`
+	GroupID     string         `db:"group_id"`
+	RuleID      sql.NullString `db:"rule_id"`
+	ScriptID    sql.NullString `db:"script_id"`
+	Subtopic    string         `db:"subtopic"`
+	Protocol    string         `db:"protocol"`
+	Rule        []byte         `db:"rule"`
+	Level       int32          `db:"level"`
+	Status      string         `db:"status"`
+	Occurrences uint64         `db:"occurrences"`
+	LastSeen    int64          `db:"last_seen"`
+	History     []byte         `db:"history"`
+	Created     int64          `db:"created"`
 }
 
 func toDBAlarm(alarm alarms.Alarm) (dbAlarm, error) {
@@ -303,18 +356,30 @@ func toDBAlarm(alarm alarms.Alarm) (dbAlarm, error) {
 		return dbAlarm{}, err
 	}
 
+	history := alarm.History
+	if history == nil {
+		history = []alarms.StatusChange{}
+	}
+	hist, err := json.Marshal(history)
+	if err != nil {
+		return dbAlarm{}, err
+	}
+
 	return dbAlarm{
-		ID:       alarm.ID,
-		ThingID:  alarm.ThingID,
-		GroupID:  alarm.GroupID,
-		RuleID:   sql.NullString{String: alarm.RuleID, Valid: alarm.RuleID != ""},
-		ScriptID: sql.NullString{String: alarm.ScriptID, Valid: alarm.ScriptID != ""},
-		Subtopic: alarm.Subtopic,
-		Protocol: alarm.Protocol,
-		Rule:     rule,
-		Level:    alarm.Level,
-		Status:   alarm.Status,
-		Created:  alarm.Created,
+		ID:          alarm.ID,
+		ThingID:     alarm.ThingID,
+		GroupID:     alarm.GroupID,
+		RuleID:      sql.NullString{String: alarm.RuleID, Valid: alarm.RuleID != ""},
+		ScriptID:    sql.NullString{String: alarm.ScriptID, Valid: alarm.ScriptID != ""},
+		Subtopic:    alarm.Subtopic,
+		Protocol:    alarm.Protocol,
+		Rule:        rule,
+		Level:       alarm.Level,
+		Status:      alarm.Status,
+		Occurrences: alarm.Occurrences,
+		LastSeen:    alarm.LastSeen,
+		History:     hist,
+		Created:     alarm.Created,
 	}, nil
 }
 
@@ -327,18 +392,28 @@ func toAlarm(dbAlarm dbAlarm) (alarms.Alarm, error) {
 		}
 	}
 
+	var history []alarms.StatusChange
+	if len(dbAlarm.History) > 0 {
+		if err := json.Unmarshal(dbAlarm.History, &history); err != nil {
+			return alarms.Alarm{}, errors.Wrap(dbutil.ErrMalformedEntity, err)
+		}
+	}
+
 	return alarms.Alarm{
-		ID:       dbAlarm.ID,
-		ThingID:  dbAlarm.ThingID,
-		GroupID:  dbAlarm.GroupID,
-		RuleID:   dbAlarm.RuleID.String,
-		ScriptID: dbAlarm.ScriptID.String,
-		Subtopic: dbAlarm.Subtopic,
-		Protocol: dbAlarm.Protocol,
-		Rule:     ruleInfo,
-		Level:    dbAlarm.Level,
-		Status:   dbAlarm.Status,
-		Created:  dbAlarm.Created,
+		ID:          dbAlarm.ID,
+		ThingID:     dbAlarm.ThingID,
+		GroupID:     dbAlarm.GroupID,
+		RuleID:      dbAlarm.RuleID.String,
+		ScriptID:    dbAlarm.ScriptID.String,
+		Subtopic:    dbAlarm.Subtopic,
+		Protocol:    dbAlarm.Protocol,
+		Rule:        ruleInfo,
+		Level:       dbAlarm.Level,
+		Status:      dbAlarm.Status,
+		Occurrences: dbAlarm.Occurrences,
+		LastSeen:    dbAlarm.LastSeen,
+		History:     history,
+		Created:     dbAlarm.Created,
 	}, nil
 }
 
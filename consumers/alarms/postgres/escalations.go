@@ -0,0 +1,319 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/MainfluxLabs/mainflux/consumers/alarms"
+	"github.com/MainfluxLabs/mainflux/pkg/dbutil"
+	"github.com/MainfluxLabs/mainflux/pkg/errors"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var _ alarms.EscalationRepository = (*escalationRepository)(nil)
+
+type escalationRepository struct {
+	db dbutil.Database
+}
+
+// NewEscalationRepository instantiates a PostgreSQL implementation of escalation policy repository.
+func NewEscalationRepository(db dbutil.Database) alarms.EscalationRepository {
+	return &escalationRepository{
+		db: db,
+	}
+}
+
+func (er *escalationRepository) Save(ctx context.Context, policies ...alarms.EscalationPolicy) ([]alarms.EscalationPolicy, error) {
+	tx, err := er.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return []alarms.EscalationPolicy{}, errors.Wrap(dbutil.ErrCreateEntity, err)
+	}
+	defer tx.Rollback()
+
+	q := `INSERT INTO escalation_policies (id, group_id, name, level, steps) VALUES (:id, :group_id, :name, :level, :steps);`
+
+	for _, policy := range policies {
+		dbp, err := toDBEscalationPolicy(policy)
+		if err != nil {
+			return []alarms.EscalationPolicy{}, errors.Wrap(dbutil.ErrCreateEntity, err)
+		}
+
+		if _, err := tx.NamedExecContext(ctx, q, dbp); err != nil {
+			pgErr, ok := err.(*pgconn.PgError)
+			if ok {
+				switch pgErr.Code {
+				case pgerrcode.InvalidTextRepresentation:
+					return []alarms.EscalationPolicy{}, errors.Wrap(dbutil.ErrMalformedEntity, err)
+				case pgerrcode.UniqueViolation:
+					return []alarms.EscalationPolicy{}, errors.Wrap(dbutil.ErrConflict, err)
+				case pgerrcode.StringDataRightTruncationWarning:
+					return []alarms.EscalationPolicy{}, errors.Wrap(dbutil.ErrMalformedEntity, err)
+				}
+			}
+			return []alarms.EscalationPolicy{}, errors.Wrap(dbutil.ErrCreateEntity, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return []alarms.EscalationPolicy{}, errors.Wrap(dbutil.ErrCreateEntity, err)
+	}
+	return policies, nil
+}
+
+func (er *escalationRepository) RetrieveByID(ctx context.Context, id string) (alarms.EscalationPolicy, error) {
+	q := `SELECT id, group_id, name, level, steps FROM escalation_policies WHERE id = $1;`
+
+	var dbp dbEscalationPolicy
+	if err := er.db.QueryRowxContext(ctx, q, id).StructScan(&dbp); err != nil {
+		pgErr, ok := err.(*pgconn.PgError)
+		if err == sql.ErrNoRows || ok && pgerrcode.InvalidTextRepresentation == pgErr.Code {
+			return alarms.EscalationPolicy{}, errors.Wrap(dbutil.ErrNotFound, err)
+		}
+		return alarms.EscalationPolicy{}, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+	}
+
+	return toEscalationPolicy(dbp)
+}
+
+func (er *escalationRepository) RetrieveByGroup(ctx context.Context, groupID string, pm alarms.PageMetadata) (alarms.EscalationPoliciesPage, error) {
+	if _, err := uuid.FromString(groupID); err != nil {
+		return alarms.EscalationPoliciesPage{}, errors.Wrap(dbutil.ErrNotFound, err)
+	}
+
+	oq := dbutil.GetOrderQuery(pm.Order, alarms.EscalationPolicyOrderFields)
+	dq := dbutil.GetDirQuery(pm.Dir)
+	olq := dbutil.GetOffsetLimitQuery(pm.Limit)
+	nq, name := dbutil.GetNameQuery(pm.Name)
+	whereClause := dbutil.BuildWhereClause("group_id = :group_id", nq)
+
+	q := fmt.Sprintf(`SELECT id, group_id, name, level, steps FROM escalation_policies %s ORDER BY %s %s %s;`, whereClause, oq, dq, olq)
+	qc := fmt.Sprintf(`SELECT COUNT(*) FROM escalation_policies %s;`, whereClause)
+
+	params := map[string]any{
+		"group_id": groupID,
+		"limit":    pm.Limit,
+		"offset":   pm.Offset,
+		"name":     name,
+	}
+
+	rows, err := er.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return alarms.EscalationPoliciesPage{}, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+	}
+	defer rows.Close()
+
+	var items []alarms.EscalationPolicy
+	for rows.Next() {
+		var dbp dbEscalationPolicy
+		if err := rows.StructScan(&dbp); err != nil {
+			return alarms.EscalationPoliciesPage{}, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+		}
+
+		policy, err := toEscalationPolicy(dbp)
+		if err != nil {
+			return alarms.EscalationPoliciesPage{}, err
+		}
+
+		items = append(items, policy)
+	}
+
+	total, err := dbutil.Total(ctx, er.db, qc, params)
+	if err != nil {
+		return alarms.EscalationPoliciesPage{}, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+	}
+
+	page := alarms.EscalationPoliciesPage{
+		Policies: items,
+		Total:    total,
+	}
+	return page, nil
+}
+
+func (er *escalationRepository) RetrieveAll(ctx context.Context) ([]alarms.EscalationPolicy, error) {
+	q := `SELECT id, group_id, name, level, steps FROM escalation_policies;`
+
+	var dbps []dbEscalationPolicy
+	if err := er.db.SelectContext(ctx, &dbps, q); err != nil {
+		return nil, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+	}
+
+	var items []alarms.EscalationPolicy
+	for _, dbp := range dbps {
+		policy, err := toEscalationPolicy(dbp)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, policy)
+	}
+
+	return items, nil
+}
+
+func (er *escalationRepository) Update(ctx context.Context, policy alarms.EscalationPolicy) error {
+	q := `UPDATE escalation_policies SET name = :name, level = :level, steps = :steps WHERE id = :id;`
+
+	dbp, err := toDBEscalationPolicy(policy)
+	if err != nil {
+		return errors.Wrap(dbutil.ErrUpdateEntity, err)
+	}
+
+	res, err := er.db.NamedExecContext(ctx, q, dbp)
+	if err != nil {
+		pgErr, ok := err.(*pgconn.PgError)
+		if ok {
+			switch pgErr.Code {
+			case pgerrcode.InvalidTextRepresentation:
+				return errors.Wrap(dbutil.ErrMalformedEntity, err)
+			case pgerrcode.StringDataRightTruncationDataException:
+				return errors.Wrap(dbutil.ErrMalformedEntity, err)
+			}
+		}
+		return errors.Wrap(dbutil.ErrUpdateEntity, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(dbutil.ErrUpdateEntity, err)
+	}
+	if cnt == 0 {
+		return dbutil.ErrNotFound
+	}
+
+	return nil
+}
+
+func (er *escalationRepository) Remove(ctx context.Context, ids ...string) error {
+	q := `DELETE FROM escalation_policies WHERE id = :id;`
+
+	for _, id := range ids {
+		dbp := dbEscalationPolicy{ID: id}
+		if _, err := er.db.NamedExecContext(ctx, q, dbp); err != nil {
+			return errors.Wrap(dbutil.ErrRemoveEntity, err)
+		}
+	}
+
+	return nil
+}
+
+func (er *escalationRepository) RemoveByGroup(ctx context.Context, groupID string) error {
+	q := `DELETE FROM escalation_policies WHERE group_id = :group_id;`
+
+	dbp := dbEscalationPolicy{GroupID: groupID}
+	if _, err := er.db.NamedExecContext(ctx, q, dbp); err != nil {
+		return errors.Wrap(dbutil.ErrRemoveEntity, err)
+	}
+
+	return nil
+}
+
+func (er *escalationRepository) RetrieveEscalations(ctx context.Context, policy alarms.EscalationPolicy) ([]alarms.Escalation, error) {
+	q := `SELECT a.id, a.thing_id, a.group_id, a.rule_id, a.script_id, a.subtopic, a.protocol, a.rule, a.level, a.status,
+	             a.occurrences, a.last_seen, a.history, a.created, COALESCE(e.step, 0) AS step
+	      FROM alarms a LEFT JOIN alarm_escalations e ON e.alarm_id = a.id AND e.policy_id = :policy_id
+	      WHERE a.group_id = :group_id AND a.status = 'active' AND a.level >= :level AND COALESCE(e.step, 0) < :steps;`
+
+	params := map[string]any{
+		"policy_id": policy.ID,
+		"group_id":  policy.GroupID,
+		"level":     policy.Level,
+		"steps":     len(policy.Steps),
+	}
+
+	rows, err := er.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return nil, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+	}
+	defer rows.Close()
+
+	var items []alarms.Escalation
+	for rows.Next() {
+		var dbe dbEscalation
+		if err := rows.StructScan(&dbe); err != nil {
+			return nil, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+		}
+
+		alarm, err := toAlarm(dbe.dbAlarm)
+		if err != nil {
+			return nil, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+		}
+
+		items = append(items, alarms.Escalation{Alarm: alarm, Step: dbe.Step})
+	}
+
+	return items, nil
+}
+
+func (er *escalationRepository) UpdateStep(ctx context.Context, alarmID, policyID string, step int) (bool, error) {
+	q := `INSERT INTO alarm_escalations (alarm_id, policy_id, step) VALUES (:alarm_id, :policy_id, :step)
+	      ON CONFLICT (alarm_id, policy_id) DO UPDATE SET step = EXCLUDED.step WHERE alarm_escalations.step < EXCLUDED.step;`
+
+	params := map[string]any{
+		"alarm_id":  alarmID,
+		"policy_id": policyID,
+		"step":      step,
+	}
+
+	res, err := er.db.NamedExecContext(ctx, q, params)
+	if err != nil {
+		return false, errors.Wrap(dbutil.ErrUpdateEntity, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(dbutil.ErrUpdateEntity, err)
+	}
+
+	return cnt > 0, nil
+}
+
+type dbEscalationPolicy struct {
+	ID      string `db:"id"`
+	GroupID string `db:"group_id"`
+	Name    string `db:"name"`
+	Level   int32  `db:"level"`
+	Steps   []byte `db:"steps"`
+}
+
+type dbEscalation struct {
+	dbAlarm
+	Step int `db:"step"`
+}
+
+func toDBEscalationPolicy(policy alarms.EscalationPolicy) (dbEscalationPolicy, error) {
+	steps := policy.Steps
+	if steps == nil {
+		steps = []alarms.EscalationStep{}
+	}
+	data, err := json.Marshal(steps)
+	if err != nil {
+		return dbEscalationPolicy{}, errors.Wrap(dbutil.ErrMalformedEntity, err)
+	}
+
+	return dbEscalationPolicy{
+		ID:      policy.ID,
+		GroupID: policy.GroupID,
+		Name:    policy.Name,
+		Level:   policy.Level,
+		Steps:   data,
+	}, nil
+}
+
+func toEscalationPolicy(dbp dbEscalationPolicy) (alarms.EscalationPolicy, error) {
+	var steps []alarms.EscalationStep
+	if err := json.Unmarshal(dbp.Steps, &steps); err != nil {
+		return alarms.EscalationPolicy{}, errors.Wrap(dbutil.ErrMalformedEntity, err)
+	}
+
+	return alarms.EscalationPolicy{
+		ID:      dbp.ID,
+		GroupID: dbp.GroupID,
+		Name:    dbp.Name,
+		Level:   dbp.Level,
+		Steps:   steps,
+	}, nil
+}
@@ -87,6 +87,52 @@ func migrateDB(db *sqlx.DB) error {
 					`ALTER TABLE alarms ADD COLUMN payload JSONB;`,
 				},
 			},
+			{
+				Id: "alarms_4",
+				Up: []string{
+					`ALTER TABLE alarms ADD COLUMN occurrences BIGINT NOT NULL DEFAULT 1;`,
+					`ALTER TABLE alarms ADD COLUMN last_seen   BIGINT;`,
+					`ALTER TABLE alarms ADD COLUMN history     JSONB NOT NULL DEFAULT '[]';`,
+					`UPDATE alarms SET last_seen = created;`,
+					`UPDATE alarms a SET status = 'cleared'
+					 WHERE status <> 'cleared' AND EXISTS (
+						SELECT 1 FROM alarms b
+						WHERE b.thing_id = a.thing_id AND COALESCE(b.rule_id, b.script_id) = COALESCE(a.rule_id, a.script_id)
+						AND COALESCE(b.subtopic, '') = COALESCE(a.subtopic, '') AND b.status <> 'cleared'
+						AND (b.created, b.id) > (a.created, a.id)
+					 );`,
+					`CREATE UNIQUE INDEX alarms_open_key ON alarms (thing_id, COALESCE(rule_id, script_id), COALESCE(subtopic, ''))
+					 WHERE status <> 'cleared';`,
+				},
+				Down: []string{
+					`DROP INDEX IF EXISTS alarms_open_key;`,
+					`ALTER TABLE alarms DROP COLUMN IF EXISTS occurrences;`,
+					`ALTER TABLE alarms DROP COLUMN IF EXISTS last_seen;`,
+					`ALTER TABLE alarms DROP COLUMN IF EXISTS history;`,
+				},
+			},
+			{
+				Id: "alarms_5",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS escalation_policies (
+						id       UUID PRIMARY KEY,
+						group_id UUID NOT NULL,
+						name     VARCHAR(254),
+						level    SMALLINT NOT NULL,
+						steps    JSONB NOT NULL
+					)`,
+					`CREATE TABLE IF NOT EXISTS alarm_escalations (
+						alarm_id  UUID REFERENCES alarms (id) ON DELETE CASCADE,
+						policy_id UUID REFERENCES escalation_policies (id) ON DELETE CASCADE,
+						step      INTEGER NOT NULL,
+						PRIMARY KEY (alarm_id, policy_id)
+					)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS alarm_escalations;`,
+					`DROP TABLE IF EXISTS escalation_policies;`,
+				},
+			},
 		},
 	}
 	_, err := migrate.Exec(db.DB, "postgres", migrations, migrate.Up)
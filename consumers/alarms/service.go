@@ -5,14 +5,23 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/MainfluxLabs/mainflux/consumers"
+	"github.com/MainfluxLabs/mainflux/logger"
+	"github.com/MainfluxLabs/mainflux/pkg/authn"
+	"github.com/MainfluxLabs/mainflux/pkg/cron"
+	"github.com/MainfluxLabs/mainflux/pkg/dbutil"
 	"github.com/MainfluxLabs/mainflux/pkg/domain"
 	"github.com/MainfluxLabs/mainflux/pkg/errors"
+	"github.com/MainfluxLabs/mainflux/pkg/messaging"
 	protomfx "github.com/MainfluxLabs/mainflux/pkg/proto"
 	"github.com/MainfluxLabs/mainflux/pkg/uuid"
 )
 
+// ErrAlarmCleared indicates an attempt to change the status of a cleared alarm.
+var ErrAlarmCleared = errors.New("alarm is already cleared")
+
 // PageMetadata contains page metadata that helps navigation.
 type PageMetadata struct {
 	Total    uint64 `json:"total,omitempty"`
@@ -20,6 +29,7 @@ type PageMetadata struct {
 	Limit    uint64 `json:"limit,omitempty"`
 	Order    string `json:"order,omitempty"`
 	Dir      string `json:"dir,omitempty"`
+	Name     string `json:"name,omitempty"`
 	Level    int32  `json:"level,omitempty"`
 	Status   string `json:"status,omitempty"`
 	Protocol string `json:"protocol,omitempty"`
@@ -51,7 +61,8 @@ type Service interface {
 	// RemoveAlarms removes alarms identified with the provided IDs.
 	RemoveAlarms(ctx context.Context, token string, id ...string) error
 
-	// UpdateAlarmStatus updates the status of the alarm identified by the provided ID.
+	// UpdateAlarmStatus updates the status of the alarm identified by the provided ID,
+	// recording the user who changed it in the alarm status history.
 	UpdateAlarmStatus(ctx context.Context, token, id, status string) error
 
 	// RemoveAlarmsByThing removes alarms related to the specified thing,
@@ -66,22 +77,62 @@ type Service interface {
 	// identified by the provided thing ID, intended for exporting.
 	ExportAlarmsByThing(ctx context.Context, token, thingID string, pm PageMetadata) (AlarmsPage, error)
 
+	// CreateEscalationPolicies creates escalation policies for a certain group identified by the group ID.
+	CreateEscalationPolicies(ctx context.Context, token, groupID string, policies ...EscalationPolicy) ([]EscalationPolicy, error)
+
+	// ListEscalationPoliciesByGroup retrieves data about a subset of escalation policies
+	// related to a certain group, identified by the provided group ID.
+	ListEscalationPoliciesByGroup(ctx context.Context, token, groupID string, pm PageMetadata) (EscalationPoliciesPage, error)
+
+	// ViewEscalationPolicy retrieves data about the escalation policy identified by the provided ID.
+	ViewEscalationPolicy(ctx context.Context, token, id string) (EscalationPolicy, error)
+
+	// UpdateEscalationPolicy updates the escalation policy identified by the provided ID.
+	UpdateEscalationPolicy(ctx context.Context, token string, policy EscalationPolicy) error
+
+	// RemoveEscalationPolicies removes escalation policies identified with the provided IDs.
+	RemoveEscalationPolicies(ctx context.Context, token string, ids ...string) error
+
+	// RemoveEscalationPoliciesByGroup removes escalation policies related to the specified group,
+	// identified by the provided group ID.
+	RemoveEscalationPoliciesByGroup(ctx context.Context, groupID string) error
+
+	// EscalateAlarms notifies the notifiers of the escalation policy steps that are due
+	// for alarms that are still active.
+	EscalateAlarms(ctx context.Context) error
+
+	// ScheduleEscalations schedules alarm escalation every minute, until the context is done.
+	ScheduleEscalations(ctx context.Context) error
+
 	consumers.AlarmConsumer
 }
 
+// Publisher specifies the minimal publishing capability the alarms service needs.
+type Publisher interface {
+	messaging.NotificationPublisher
+}
+
 type alarmService struct {
-	things     domain.ThingsClient
-	alarms     AlarmRepository
-	idProvider uuid.IDProvider
+	things      domain.ThingsClient
+	pub         Publisher
+	alarms      AlarmRepository
+	escalations EscalationRepository
+	idProvider  uuid.IDProvider
+	scheduler   *cron.ScheduleManager
+	logger      logger.Logger
 }
 
 var _ Service = (*alarmService)(nil)
 
-func New(things domain.ThingsClient, alarms AlarmRepository, idp uuid.IDProvider) Service {
+func New(things domain.ThingsClient, pub Publisher, alarms AlarmRepository, escalations EscalationRepository, idp uuid.IDProvider, logger logger.Logger) Service {
 	return &alarmService{
-		things:     things,
-		alarms:     alarms,
-		idProvider: idp,
+		things:      things,
+		pub:         pub,
+		alarms:      alarms,
+		escalations: escalations,
+		idProvider:  idp,
+		scheduler:   cron.NewScheduleManager(),
+		logger:      logger,
 	}
 }
 
@@ -145,7 +196,16 @@ func (as *alarmService) UpdateAlarmStatus(ctx context.Context, token, id, status
 		return errors.Wrap(errors.ErrAuthorization, err)
 	}
 
-	return as.alarms.UpdateStatus(ctx, id, status)
+	if alarm.Status == StatusCleared {
+		return errors.Wrap(dbutil.ErrConflict, ErrAlarmCleared)
+	}
+
+	change := StatusChange{Status: status, Created: time.Now().UnixNano()}
+	if identity, ok := authn.IdentityFromCtx(ctx); ok {
+		change.UserID, change.Email = identity.ID, identity.Email
+	}
+
+	return as.alarms.UpdateStatus(ctx, id, change)
 }
 
 func (as *alarmService) RemoveAlarms(ctx context.Context, token string, ids ...string) error {
@@ -184,6 +244,75 @@ func (as *alarmService) ExportAlarmsByThing(ctx context.Context, token, thingID
 	return alarms, nil
 }
 
+func (as *alarmService) CreateEscalationPolicies(ctx context.Context, token, groupID string, policies ...EscalationPolicy) ([]EscalationPolicy, error) {
+	if err := as.things.CanUserAccessGroup(ctx, domain.UserAccessReq{Token: token, ID: groupID, Action: domain.GroupEditor}); err != nil {
+		return []EscalationPolicy{}, errors.Wrap(errors.ErrAuthorization, err)
+	}
+
+	for i := range policies {
+		id, err := as.idProvider.ID()
+		if err != nil {
+			return []EscalationPolicy{}, err
+		}
+		policies[i].ID = id
+		policies[i].GroupID = groupID
+	}
+
+	return as.escalations.Save(ctx, policies...)
+}
+
+func (as *alarmService) ListEscalationPoliciesByGroup(ctx context.Context, token, groupID string, pm PageMetadata) (EscalationPoliciesPage, error) {
+	if err := as.things.CanUserAccessGroup(ctx, domain.UserAccessReq{Token: token, ID: groupID, Action: domain.GroupViewer}); err != nil {
+		return EscalationPoliciesPage{}, err
+	}
+
+	return as.escalations.RetrieveByGroup(ctx, groupID, pm)
+}
+
+func (as *alarmService) ViewEscalationPolicy(ctx context.Context, token, id string) (EscalationPolicy, error) {
+	policy, err := as.escalations.RetrieveByID(ctx, id)
+	if err != nil {
+		return EscalationPolicy{}, err
+	}
+
+	if err := as.things.CanUserAccessGroup(ctx, domain.UserAccessReq{Token: token, ID: policy.GroupID, Action: domain.GroupViewer}); err != nil {
+		return EscalationPolicy{}, err
+	}
+
+	return policy, nil
+}
+
+func (as *alarmService) UpdateEscalationPolicy(ctx context.Context, token string, policy EscalationPolicy) error {
+	p, err := as.escalations.RetrieveByID(ctx, policy.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := as.things.CanUserAccessGroup(ctx, domain.UserAccessReq{Token: token, ID: p.GroupID, Action: domain.GroupEditor}); err != nil {
+		return errors.Wrap(errors.ErrAuthorization, err)
+	}
+
+	return as.escalations.Update(ctx, policy)
+}
+
+func (as *alarmService) RemoveEscalationPolicies(ctx context.Context, token string, ids ...string) error {
+	for _, id := range ids {
+		policy, err := as.escalations.RetrieveByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := as.things.CanUserAccessGroup(ctx, domain.UserAccessReq{Token: token, ID: policy.GroupID, Action: domain.GroupEditor}); err != nil {
+			return errors.Wrap(errors.ErrAuthorization, err)
+		}
+	}
+
+	return as.escalations.Remove(ctx, ids...)
+}
+
+func (as *alarmService) RemoveEscalationPoliciesByGroup(ctx context.Context, groupID string) error {
+	return as.escalations.RemoveByGroup(ctx, groupID)
+}
+
 func (as *alarmService) createAlarm(ctx context.Context, alarm *Alarm) error {
 	grID, err := as.things.GetGroupIDByThing(ctx, alarm.ThingID)
 	if err != nil {
@@ -196,9 +325,50 @@ func (as *alarmService) createAlarm(ctx context.Context, alarm *Alarm) error {
 		return err
 	}
 	alarm.ID = id
+	alarm.Status = StatusActive
+	alarm.Occurrences = 1
+	alarm.LastSeen = alarm.Created
+	alarm.History = []StatusChange{{Status: StatusActive, Created: alarm.Created}}
 
 	return as.alarms.Save(ctx, *alarm)
+}
+
+// raiseAlarm records an occurrence of the alarm that isn't cleared yet, raised for the
+// same thing, subtopic and rule or script, or creates a new alarm if there is none.
+func (as *alarmService) raiseAlarm(ctx context.Context, alarm *Alarm) error {
+	open, err := as.alarms.RetrieveOpen(ctx, *alarm)
+	switch {
+	case err == nil:
+		return as.alarms.UpdateOccurrence(ctx, open.ID, alarm.Created)
+	case !errors.Contains(err, dbutil.ErrNotFound):
+		return err
+	}
+
+	err = as.createAlarm(ctx, alarm)
+	if !errors.Contains(err, dbutil.ErrConflict) {
+		return err
+	}
+
+	// The alarm was concurrently raised by another occurrence.
+	if open, err = as.alarms.RetrieveOpen(ctx, *alarm); err != nil {
+		return err
+	}
 
+	return as.alarms.UpdateOccurrence(ctx, open.ID, alarm.Created)
+}
+
+// clearAlarm clears the alarm that isn't cleared yet, raised for the same thing,
+// subtopic and rule or script, if there is one.
+func (as *alarmService) clearAlarm(ctx context.Context, alarm Alarm) error {
+	open, err := as.alarms.RetrieveOpen(ctx, alarm)
+	if err != nil {
+		if errors.Contains(err, dbutil.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	return as.alarms.UpdateStatus(ctx, open.ID, StatusChange{Status: StatusCleared, Created: alarm.Created})
 }
 
 func (as *alarmService) ConsumeAlarm(subject string, alarm protomfx.Alarm) error {
@@ -215,7 +385,6 @@ func (as *alarmService) ConsumeAlarm(subject string, alarm protomfx.Alarm) error
 		Subtopic: alarm.Subtopic,
 		Protocol: alarm.Protocol,
 		Level:    alarm.Level,
-		Status:   StatusActive,
 		Created:  alarm.Created,
 	}
 
@@ -236,5 +405,9 @@ func (as *alarmService) ConsumeAlarm(subject string, alarm protomfx.Alarm) error
 		return fmt.Errorf("invalid subject origin type: %s", originType)
 	}
 
-	return as.createAlarm(ctx, &a)
+	if alarm.Cleared {
+		return as.clearAlarm(ctx, a)
+	}
+
+	return as.raiseAlarm(ctx, &a)
 }
@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/MainfluxLabs/mainflux/consumers/alarms"
 	"github.com/MainfluxLabs/mainflux/consumers/alarms/mocks"
+	"github.com/MainfluxLabs/mainflux/logger"
 	"github.com/MainfluxLabs/mainflux/pkg/dbutil"
 	"github.com/MainfluxLabs/mainflux/pkg/domain"
 	"github.com/MainfluxLabs/mainflux/pkg/errors"
@@ -26,12 +28,18 @@ const (
 	groupID    = "574106f7-030e-4881-8ab0-151195c29f94"
 	orgID      = "7e3d5e48-b0b4-4d7b-9d6a-c81f40e30e2c"
 	ruleID     = "5384fb1c-d0ae-4cbe-be52-c54223150fe1"
+	rulePrefix = "5384fb1c-d0ae-4cbe-be52-"
 	subtopic   = "sensors"
 	protocol   = "mqtt"
 	ruleSub    = "alarms.rule"
+	notifierID = "2fc4c3a8-5d0e-4c3b-9d3a-6f2b1d0e7a11"
 )
 
 func newService() alarms.Service {
+	return newServiceWithPublisher(mocks.NewPublisher())
+}
+
+func newServiceWithPublisher(pub alarms.Publisher) alarms.Service {
 	ths := authmock.NewThingsServiceClient(
 		nil,
 		map[string]things.Thing{
@@ -43,9 +51,10 @@ func newService() alarms.Service {
 		},
 	)
 	alarmRepo := mocks.NewAlarmRepository()
+	escalationRepo := mocks.NewEscalationRepository(alarmRepo)
 	idProvider := uuid.NewMock()
 
-	return alarms.New(ths, alarmRepo, idProvider)
+	return alarms.New(ths, pub, alarmRepo, escalationRepo, idProvider, logger.NewMock())
 }
 
 func saveAlarms(t *testing.T, svc alarms.Service, n int) []alarms.Alarm {
@@ -68,7 +77,7 @@ func saveAlarms(t *testing.T, svc alarms.Service, n int) []alarms.Alarm {
 			ThingId:  thingID,
 			Subtopic: subtopic,
 			Protocol: protocol,
-			RuleId:   ruleID,
+			RuleId:   fmt.Sprintf("%s%012d", rulePrefix, i+1),
 			RuleInfo: ruleInfo,
 			Level:    int32(i % 3),
 			Created:  int64(1000000 + i),
@@ -77,16 +86,19 @@ func saveAlarms(t *testing.T, svc alarms.Service, n int) []alarms.Alarm {
 		require.Nil(t, err, fmt.Sprintf("unexpected error saving alarm %d: %s", i+1, err))
 
 		saved[i] = alarms.Alarm{
-			ID:       fmt.Sprintf("%s%012d", uuid.Prefix, i+1),
-			ThingID:  a.ThingId,
-			GroupID:  groupID,
-			RuleID:   a.RuleId,
-			Subtopic: a.Subtopic,
-			Protocol: a.Protocol,
-			Rule:     &ri,
-			Level:    a.Level,
-			Status:   alarms.StatusActive,
-			Created:  a.Created,
+			ID:          fmt.Sprintf("%s%012d", uuid.Prefix, i+1),
+			ThingID:     a.ThingId,
+			GroupID:     groupID,
+			RuleID:      a.RuleId,
+			Subtopic:    a.Subtopic,
+			Protocol:    a.Protocol,
+			Rule:        &ri,
+			Level:       a.Level,
+			Status:      alarms.StatusActive,
+			Occurrences: 1,
+			LastSeen:    a.Created,
+			History:     []alarms.StatusChange{{Status: alarms.StatusActive, Created: a.Created}},
+			Created:     a.Created,
 		}
 	}
 
@@ -139,6 +151,115 @@ func TestConsumeAlarm(t *testing.T) {
 	}
 }
 
+func TestConsumeAlarmLifecycle(t *testing.T) {
+	svc := newService()
+
+	raised := protomfx.Alarm{
+		ThingId:  thingID,
+		Subtopic: subtopic,
+		Protocol: protocol,
+		Level:    2,
+		RuleId:   ruleID,
+	}
+	cleared := raised
+	cleared.Cleared = true
+	otherSubtopic := raised
+	otherSubtopic.Subtopic = "other"
+
+	cases := []struct {
+		desc        string
+		alarm       protomfx.Alarm
+		created     int64
+		total       uint64
+		active      uint64
+		occurrences uint64
+		lastSeen    int64
+	}{
+		{
+			desc:        "raise alarm",
+			alarm:       raised,
+			created:     1000,
+			total:       1,
+			active:      1,
+			occurrences: 1,
+			lastSeen:    1000,
+		},
+		{
+			desc:        "raise active alarm again",
+			alarm:       raised,
+			created:     2000,
+			total:       1,
+			active:      1,
+			occurrences: 2,
+			lastSeen:    2000,
+		},
+		{
+			desc:        "raise alarm for another subtopic",
+			alarm:       otherSubtopic,
+			created:     3000,
+			total:       2,
+			active:      2,
+			occurrences: 2,
+			lastSeen:    2000,
+		},
+		{
+			desc:        "clear alarm",
+			alarm:       cleared,
+			created:     4000,
+			total:       2,
+			active:      1,
+			occurrences: 2,
+			lastSeen:    2000,
+		},
+		{
+			desc:        "clear alarm that is already cleared",
+			alarm:       cleared,
+			created:     5000,
+			total:       2,
+			active:      1,
+			occurrences: 2,
+			lastSeen:    2000,
+		},
+		{
+			desc:        "raise cleared alarm again",
+			alarm:       raised,
+			created:     6000,
+			total:       3,
+			active:      2,
+			occurrences: 1,
+			lastSeen:    6000,
+		},
+	}
+
+	for _, tc := range cases {
+		tc.alarm.Created = tc.created
+		err := svc.ConsumeAlarm(ruleSub, tc.alarm)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+
+		page, err := svc.ListAlarmsByThing(context.Background(), token, thingID, alarms.PageMetadata{})
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected %d alarms got %d", tc.desc, tc.total, page.Total))
+
+		active, err := svc.ListAlarmsByThing(context.Background(), token, thingID, alarms.PageMetadata{Status: alarms.StatusActive})
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.active, active.Total, fmt.Sprintf("%s: expected %d active alarms got %d", tc.desc, tc.active, active.Total))
+
+		for _, a := range active.Alarms {
+			if a.Subtopic != subtopic {
+				continue
+			}
+			assert.Equal(t, tc.occurrences, a.Occurrences, fmt.Sprintf("%s: expected %d occurrences got %d", tc.desc, tc.occurrences, a.Occurrences))
+			assert.Equal(t, tc.lastSeen, a.LastSeen, fmt.Sprintf("%s: expected last seen %d got %d", tc.desc, tc.lastSeen, a.LastSeen))
+		}
+	}
+
+	page, err := svc.ListAlarmsByThing(context.Background(), token, thingID, alarms.PageMetadata{Status: alarms.StatusCleared})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	require.Equal(t, uint64(1), page.Total, fmt.Sprintf("expected 1 cleared alarm got %d", page.Total))
+	history := []alarms.StatusChange{{Status: alarms.StatusActive, Created: 1000}, {Status: alarms.StatusCleared, Created: 4000}}
+	assert.Equal(t, history, page.Alarms[0].History, fmt.Sprintf("expected history %v got %v", history, page.Alarms[0].History))
+}
+
 func TestUpdateAlarmStatus(t *testing.T) {
 	svc := newService()
 	saved := saveAlarms(t, svc, 1)
@@ -165,6 +286,13 @@ func TestUpdateAlarmStatus(t *testing.T) {
 			status: alarms.StatusCleared,
 			err:    nil,
 		},
+		{
+			desc:   "update status of cleared alarm",
+			token:  token,
+			id:     alarmID,
+			status: alarms.StatusNoted,
+			err:    alarms.ErrAlarmCleared,
+		},
 		{
 			desc:   "update alarm status with wrong token",
 			token:  wrongValue,
@@ -627,3 +755,217 @@ func TestExportAlarmsByThing(t *testing.T) {
 		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
 	}
 }
+
+func createEscalationPolicy(t *testing.T, svc alarms.Service, level int32) alarms.EscalationPolicy {
+	t.Helper()
+
+	policy := alarms.EscalationPolicy{
+		Name:  "policy",
+		Level: level,
+		Steps: []alarms.EscalationStep{
+			{Delay: 5, Type: alarms.NotifierTypeSMTP, NotifierID: notifierID},
+			{Delay: 10, Type: alarms.NotifierTypeSMPP, NotifierID: notifierID},
+		},
+	}
+	saved, err := svc.CreateEscalationPolicies(context.Background(), token, groupID, policy)
+	require.Nil(t, err, fmt.Sprintf("unexpected error creating escalation policy: %s", err))
+
+	return saved[0]
+}
+
+func TestCreateEscalationPolicies(t *testing.T) {
+	svc := newService()
+
+	policy := alarms.EscalationPolicy{
+		Name:  "policy",
+		Level: 2,
+		Steps: []alarms.EscalationStep{{Delay: 5, Type: alarms.NotifierTypeSMTP, NotifierID: notifierID}},
+	}
+
+	cases := []struct {
+		desc    string
+		token   string
+		groupID string
+		err     error
+	}{
+		{
+			desc:    "create escalation policy",
+			token:   token,
+			groupID: groupID,
+			err:     nil,
+		},
+		{
+			desc:    "create escalation policy with wrong credentials",
+			token:   wrongValue,
+			groupID: groupID,
+			err:     errors.ErrAuthentication,
+		},
+		{
+			desc:    "create escalation policy for another group",
+			token:   token,
+			groupID: wrongValue,
+			err:     errors.ErrAuthorization,
+		},
+	}
+
+	for _, tc := range cases {
+		saved, err := svc.CreateEscalationPolicies(context.Background(), tc.token, tc.groupID, policy)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		if err == nil {
+			require.Len(t, saved, 1, fmt.Sprintf("%s: expected one escalation policy", tc.desc))
+			assert.NotEmpty(t, saved[0].ID, fmt.Sprintf("%s: expected escalation policy ID", tc.desc))
+			assert.Equal(t, tc.groupID, saved[0].GroupID, fmt.Sprintf("%s: expected group ID %s got %s", tc.desc, tc.groupID, saved[0].GroupID))
+		}
+	}
+}
+
+func TestUpdateEscalationPolicy(t *testing.T) {
+	svc := newService()
+	policy := createEscalationPolicy(t, svc, 2)
+
+	updated := policy
+	updated.Name = "updated"
+	updated.Level = 4
+	updated.Steps = updated.Steps[:1]
+
+	cases := []struct {
+		desc   string
+		token  string
+		policy alarms.EscalationPolicy
+		err    error
+	}{
+		{
+			desc:   "update escalation policy with wrong credentials",
+			token:  wrongValue,
+			policy: updated,
+			err:    errors.ErrAuthentication,
+		},
+		{
+			desc:   "update non-existing escalation policy",
+			token:  token,
+			policy: alarms.EscalationPolicy{ID: wrongValue},
+			err:    dbutil.ErrNotFound,
+		},
+		{
+			desc:   "update escalation policy",
+			token:  token,
+			policy: updated,
+			err:    nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.UpdateEscalationPolicy(context.Background(), tc.token, tc.policy)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+	}
+
+	res, err := svc.ViewEscalationPolicy(context.Background(), token, policy.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error viewing escalation policy: %s", err))
+	assert.Equal(t, updated, res, fmt.Sprintf("expected escalation policy %+v got %+v", updated, res))
+}
+
+func TestRemoveEscalationPolicies(t *testing.T) {
+	svc := newService()
+	policy := createEscalationPolicy(t, svc, 2)
+
+	cases := []struct {
+		desc  string
+		token string
+		ids   []string
+		err   error
+	}{
+		{
+			desc:  "remove escalation policy with wrong credentials",
+			token: wrongValue,
+			ids:   []string{policy.ID},
+			err:   errors.ErrAuthentication,
+		},
+		{
+			desc:  "remove escalation policy",
+			token: token,
+			ids:   []string{policy.ID},
+			err:   nil,
+		},
+		{
+			desc:  "remove non-existing escalation policy",
+			token: token,
+			ids:   []string{policy.ID},
+			err:   dbutil.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.RemoveEscalationPolicies(context.Background(), tc.token, tc.ids...)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+	}
+}
+
+func TestEscalateAlarms(t *testing.T) {
+	pub := mocks.NewPublisher()
+	svc := newServiceWithPublisher(pub)
+
+	now := time.Now()
+	raised := []struct {
+		level  int32
+		age    time.Duration
+		status string
+	}{
+		{level: 2, age: 30 * time.Minute, status: alarms.StatusNoted},
+		{level: 1, age: 30 * time.Minute, status: alarms.StatusActive},
+		{level: 2, age: 2 * time.Minute, status: alarms.StatusActive},
+		{level: 2, age: 7 * time.Minute, status: alarms.StatusActive},
+		{level: 3, age: 20 * time.Minute, status: alarms.StatusActive},
+	}
+
+	for i, r := range raised {
+		a := protomfx.Alarm{
+			ThingId: thingID,
+			RuleId:  fmt.Sprintf("%s%012d", rulePrefix, i+1),
+			Level:   r.level,
+			Created: now.Add(-r.age).UnixNano(),
+		}
+		err := svc.ConsumeAlarm(ruleSub, a)
+		require.Nil(t, err, fmt.Sprintf("unexpected error saving alarm %d: %s", i+1, err))
+
+		if r.status == alarms.StatusNoted {
+			err := svc.UpdateAlarmStatus(context.Background(), token, fmt.Sprintf("%s%012d", uuid.Prefix, i+1), r.status)
+			require.Nil(t, err, fmt.Sprintf("unexpected error noting alarm %d: %s", i+1, err))
+		}
+	}
+
+	createEscalationPolicy(t, svc, 2)
+
+	cases := []struct {
+		desc    string
+		subject string
+		alarms  []string
+	}{
+		{
+			desc:    "escalate alarms to first step notifier",
+			subject: fmt.Sprintf("%s.%s", alarms.NotifierTypeSMTP, notifierID),
+			alarms:  []string{fmt.Sprintf("%s%012d", uuid.Prefix, 4), fmt.Sprintf("%s%012d", uuid.Prefix, 5)},
+		},
+		{
+			desc:    "escalate alarms to second step notifier",
+			subject: fmt.Sprintf("%s.%s", alarms.NotifierTypeSMPP, notifierID),
+			alarms:  []string{fmt.Sprintf("%s%012d", uuid.Prefix, 5)},
+		},
+	}
+
+	// Escalating again must not notify the same step twice.
+	for range 2 {
+		err := svc.EscalateAlarms(context.Background())
+		require.Nil(t, err, fmt.Sprintf("unexpected error escalating alarms: %s", err))
+	}
+
+	for _, tc := range cases {
+		var escalated []string
+		for _, n := range pub.Notifications(tc.subject) {
+			var payload map[string]any
+			err := json.Unmarshal(n.Payload, &payload)
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error decoding notification: %s", tc.desc, err))
+			escalated = append(escalated, payload["alarm_id"].(string))
+		}
+		assert.ElementsMatch(t, tc.alarms, escalated, fmt.Sprintf("%s: expected alarms %v got %v", tc.desc, tc.alarms, escalated))
+	}
+}
@@ -14,6 +14,8 @@ const (
 	retrieveAlarmsByThing  = "retrieve_alarms_by_thing"
 	retrieveAlarmsByGroups = "retrieve_alarms_by_groups"
 	retrieveAlarmByID      = "retrieve_alarm_by_id"
+	retrieveOpenAlarm      = "retrieve_open_alarm"
+	updateAlarmOccurrence  = "update_alarm_occurrence"
 	updateAlarmStatus      = "update_alarm_status"
 	removeAlarms           = "remove_alarms"
 	removeAlarmsByThing    = "remove_alarms_by_thing"
@@ -79,12 +81,28 @@ func (arm alarmRepositoryMiddleware) RetrieveByID(ctx context.Context, id string
 	return arm.repo.RetrieveByID(ctx, id)
 }
 
-func (arm alarmRepositoryMiddleware) UpdateStatus(ctx context.Context, id, status string) error {
+func (arm alarmRepositoryMiddleware) RetrieveOpen(ctx context.Context, alarm alarms.Alarm) (alarms.Alarm, error) {
+	span := dbutil.CreateSpan(ctx, arm.tracer, retrieveOpenAlarm)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return arm.repo.RetrieveOpen(ctx, alarm)
+}
+
+func (arm alarmRepositoryMiddleware) UpdateOccurrence(ctx context.Context, id string, seen int64) error {
+	span := dbutil.CreateSpan(ctx, arm.tracer, updateAlarmOccurrence)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return arm.repo.UpdateOccurrence(ctx, id, seen)
+}
+
+func (arm alarmRepositoryMiddleware) UpdateStatus(ctx context.Context, id string, change alarms.StatusChange) error {
 	span := dbutil.CreateSpan(ctx, arm.tracer, updateAlarmStatus)
 	defer span.Finish()
 	ctx = opentracing.ContextWithSpan(ctx, span)
 
-	return arm.repo.UpdateStatus(ctx, id, status)
+	return arm.repo.UpdateStatus(ctx, id, change)
 }
 
 func (arm alarmRepositoryMiddleware) Remove(ctx context.Context, ids ...string) error {
@@ -0,0 +1,111 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/MainfluxLabs/mainflux/consumers/alarms"
+	"github.com/MainfluxLabs/mainflux/pkg/dbutil"
+	"github.com/opentracing/opentracing-go"
+)
+
+const (
+	saveEscalationPolicies            = "save_escalation_policies"
+	retrieveEscalationPolicyByID      = "retrieve_escalation_policy_by_id"
+	retrieveEscalationPoliciesByGroup = "retrieve_escalation_policies_by_group"
+	retrieveAllEscalationPolicies     = "retrieve_all_escalation_policies"
+	updateEscalationPolicy            = "update_escalation_policy"
+	removeEscalationPolicies          = "remove_escalation_policies"
+	removeEscalationPoliciesByGroup   = "remove_escalation_policies_by_group"
+	retrieveEscalations               = "retrieve_escalations"
+	updateEscalationStep              = "update_escalation_step"
+)
+
+var (
+	_ alarms.EscalationRepository = (*escalationRepositoryMiddleware)(nil)
+)
+
+type escalationRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   alarms.EscalationRepository
+}
+
+// EscalationRepositoryMiddleware tracks request and their latency, and adds spans
+// to context.
+func EscalationRepositoryMiddleware(tracer opentracing.Tracer, repo alarms.EscalationRepository) alarms.EscalationRepository {
+	return escalationRepositoryMiddleware{
+		tracer: tracer,
+		repo:   repo,
+	}
+}
+
+func (erm escalationRepositoryMiddleware) Save(ctx context.Context, policies ...alarms.EscalationPolicy) ([]alarms.EscalationPolicy, error) {
+	span := dbutil.CreateSpan(ctx, erm.tracer, saveEscalationPolicies)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return erm.repo.Save(ctx, policies...)
+}
+
+func (erm escalationRepositoryMiddleware) RetrieveByID(ctx context.Context, id string) (alarms.EscalationPolicy, error) {
+	span := dbutil.CreateSpan(ctx, erm.tracer, retrieveEscalationPolicyByID)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return erm.repo.RetrieveByID(ctx, id)
+}
+
+func (erm escalationRepositoryMiddleware) RetrieveByGroup(ctx context.Context, groupID string, pm alarms.PageMetadata) (alarms.EscalationPoliciesPage, error) {
+	span := dbutil.CreateSpan(ctx, erm.tracer, retrieveEscalationPoliciesByGroup)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return erm.repo.RetrieveByGroup(ctx, groupID, pm)
+}
+
+func (erm escalationRepositoryMiddleware) RetrieveAll(ctx context.Context) ([]alarms.EscalationPolicy, error) {
+	span := dbutil.CreateSpan(ctx, erm.tracer, retrieveAllEscalationPolicies)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return erm.repo.RetrieveAll(ctx)
+}
+
+func (erm escalationRepositoryMiddleware) Update(ctx context.Context, policy alarms.EscalationPolicy) error {
+	span := dbutil.CreateSpan(ctx, erm.tracer, updateEscalationPolicy)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return erm.repo.Update(ctx, policy)
+}
+
+func (erm escalationRepositoryMiddleware) Remove(ctx context.Context, ids ...string) error {
+	span := dbutil.CreateSpan(ctx, erm.tracer, removeEscalationPolicies)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return erm.repo.Remove(ctx, ids...)
+}
+
+func (erm escalationRepositoryMiddleware) RemoveByGroup(ctx context.Context, groupID string) error {
+	span := dbutil.CreateSpan(ctx, erm.tracer, removeEscalationPoliciesByGroup)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return erm.repo.RemoveByGroup(ctx, groupID)
+}
+
+func (erm escalationRepositoryMiddleware) RetrieveEscalations(ctx context.Context, policy alarms.EscalationPolicy) ([]alarms.Escalation, error) {
+	span := dbutil.CreateSpan(ctx, erm.tracer, retrieveEscalations)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return erm.repo.RetrieveEscalations(ctx, policy)
+}
+
+func (erm escalationRepositoryMiddleware) UpdateStep(ctx context.Context, alarmID, policyID string, step int) (bool, error) {
+	span := dbutil.CreateSpan(ctx, erm.tracer, updateEscalationStep)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return erm.repo.UpdateStep(ctx, alarmID, policyID, step)
+}
@@ -13,6 +13,14 @@ type MessageConsumer interface {
 	ConsumeMessage(subject string, msg protomfx.Message) error
 }
 
+// MessageConsumerFunc is an adapter allowing the use of a function as a MessageConsumer.
+type MessageConsumerFunc func(subject string, msg protomfx.Message) error
+
+// ConsumeMessage calls f(subject, msg).
+func (f MessageConsumerFunc) ConsumeMessage(subject string, msg protomfx.Message) error {
+	return f(subject, msg)
+}
+
 // AlarmConsumer specifies an API for consuming protomfx.Alarm.
 type AlarmConsumer interface {
 	ConsumeAlarm(subject string, alarm protomfx.Alarm) error
@@ -28,6 +36,11 @@ type WebhookConsumer interface {
 	ConsumeWebhook(subject string, webhook protomfx.Webhook) error
 }
 
+// CommandConsumer specifies an API for consuming protomfx.Command.
+type CommandConsumer interface {
+	ConsumeCommand(subject string, cmd protomfx.Command) error
+}
+
 // Messages subscribes the given MessageConsumer to the given subjects.
 func Messages(id string, sub messaging.Subscriber, c MessageConsumer, subjects ...string) error {
 	for _, subject := range subjects {
@@ -53,6 +66,16 @@ func Webhooks(id string, sub messaging.WebhookSubscriber, c WebhookConsumer) err
 	return sub.SubscribeWebhooks(id, webhookHandler{c})
 }
 
+// Commands subscribes the given CommandConsumer to the given subjects.
+func Commands(id string, sub messaging.CommandSubscriber, c CommandConsumer, subjects ...string) error {
+	for _, subject := range subjects {
+		if err := sub.SubscribeCommands(id, subject, commandHandler{c}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 type messageHandler struct{ c MessageConsumer }
 
 func (h messageHandler) Handle(subject string, msg protomfx.Message) error {
@@ -84,3 +107,11 @@ func (h webhookHandler) Handle(subject string, webhook protomfx.Webhook) error {
 }
 
 func (h webhookHandler) Cancel() error { return nil }
+
+type commandHandler struct{ c CommandConsumer }
+
+func (h commandHandler) Handle(subject string, cmd protomfx.Command) error {
+	return h.c.ConsumeCommand(subject, cmd)
+}
+
+func (h commandHandler) Cancel() error { return nil }
@@ -0,0 +1,64 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package events
+
+import (
+	"context"
+
+	"github.com/MainfluxLabs/mainflux/mqtt/redis/cache"
+	"github.com/MainfluxLabs/mainflux/pkg/events"
+)
+
+var _ cache.ConnectionCache = (*eventStore)(nil)
+
+type eventStore struct {
+	cache.ConnectionCache
+	pub events.Publisher
+}
+
+// NewEventStoreMiddleware returns a connection cache that publishes an event
+// whenever an MQTT client of a thing connects or disconnects.
+func NewEventStoreMiddleware(cc cache.ConnectionCache, pub events.Publisher) cache.ConnectionCache {
+	return eventStore{
+		ConnectionCache: cc,
+		pub:             pub,
+	}
+}
+
+func (es eventStore) Connect(ctx context.Context, clientID, thingID string) error {
+	if err := es.ConnectionCache.Connect(ctx, clientID, thingID); err != nil {
+		return err
+	}
+
+	es.pub.Publish(ctx, events.Event{
+		Action: events.ThingConnected{
+			ID:       thingID,
+			ClientID: clientID,
+		},
+	})
+
+	return nil
+}
+
+func (es eventStore) Disconnect(ctx context.Context, clientID string) error {
+	thingID := es.ConnectionCache.RetrieveThingByClient(ctx, clientID)
+
+	if err := es.ConnectionCache.Disconnect(ctx, clientID); err != nil {
+		return err
+	}
+
+	// Clients that never identified as a thing aren't cached.
+	if thingID == "" {
+		return nil
+	}
+
+	es.pub.Publish(ctx, events.Event{
+		Action: events.ThingDisconnected{
+			ID:       thingID,
+			ClientID: clientID,
+		},
+	})
+
+	return nil
+}
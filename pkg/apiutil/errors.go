@@ -39,6 +39,9 @@ var (
 	// ErrMissingAlarmID indicates missing alarm ID.
 	ErrMissingAlarmID = errors.New("missing alarm id")
 
+	// ErrMissingEscalationPolicyID indicates missing escalation policy ID.
+	ErrMissingEscalationPolicyID = errors.New("missing escalation policy id")
+
 	// ErrMissingRuleID indicates missing rule ID.
 	ErrMissingRuleID = errors.New("missing rule id")
 
@@ -108,6 +111,12 @@ var (
 	// ErrEmptyState indicates that the provided state object is empty.
 	ErrEmptyState = errors.New("empty state provided")
 
+	// ErrInvalidShadowVersion indicates an invalid shadow version in the If-Match header.
+	ErrInvalidShadowVersion = errors.New("invalid shadow version")
+
+	// ErrInvalidShadowName indicates an invalid or reserved shadow name.
+	ErrInvalidShadowName = errors.New("invalid shadow name")
+
 	// ErrMissingPublisherID indicates missing publisher ID.
 	ErrMissingPublisherID = errors.New("missing publisher ID")
 
@@ -174,15 +183,48 @@ var (
 	// ErrMissingConditionThreshold indicates a missing condition threshold
 	ErrMissingConditionThreshold = errors.New("missing condition threshold")
 
+	// ErrInvalidConditionValue indicates a missing or invalid condition value
+	ErrInvalidConditionValue = errors.New("missing or invalid condition value")
+
+	// ErrInvalidConditionExpression indicates an invalid nested condition expression
+	ErrInvalidConditionExpression = errors.New("invalid condition expression")
+
+	// ErrInvalidConditionMode indicates an invalid condition mode
+	ErrInvalidConditionMode = errors.New("invalid condition mode")
+
+	// ErrInvalidClearThreshold indicates a condition clear threshold that doesn't match its comparator and threshold
+	ErrInvalidClearThreshold = errors.New("invalid condition clear threshold")
+
+	// ErrInvalidConditionDuration indicates an invalid condition duration
+	ErrInvalidConditionDuration = errors.New("invalid condition duration")
+
+	// ErrInvalidConditionAggregation indicates an invalid condition aggregation
+	ErrInvalidConditionAggregation = errors.New("invalid condition aggregation")
+
 	// ErrInvalidActionType indicates an invalid action type
 	ErrInvalidActionType = errors.New("missing or invalid action type")
 
 	// ErrMissingActionID indicates a missing action id
 	ErrMissingActionID = errors.New("missing action id")
 
+	// ErrInvalidActionTarget indicates a missing or invalid action target
+	ErrInvalidActionTarget = errors.New("missing or invalid action target")
+
+	// ErrInvalidPayloadTemplate indicates an invalid payload template
+	ErrInvalidPayloadTemplate = errors.New("invalid payload template")
+
+	// ErrInvalidSampleMessage indicates a missing or invalid sample message
+	ErrInvalidSampleMessage = errors.New("missing or invalid sample message")
+
+	// ErrInvalidCooldown indicates an invalid rule cooldown
+	ErrInvalidCooldown = errors.New("invalid rule cooldown")
+
 	// ErrInvalidAlarmLevel indicates an invalid alarm level value
 	ErrInvalidAlarmLevel = errors.New("invalid alarm level")
 
+	// ErrInvalidEscalationStep indicates an invalid escalation policy step
+	ErrInvalidEscalationStep = errors.New("invalid escalation policy step")
+
 	// ErrInvalidAlarmStatus indicates an invalid alarm status value
 	ErrInvalidAlarmStatus = errors.New("invalid alarm status")
 
@@ -192,6 +234,18 @@ var (
 	// ErrInvalidInputType indicates an invalid rule input type
 	ErrInvalidInputType = errors.New("missing or invalid input type")
 
+	// ErrInvalidTrigger indicates an invalid script trigger
+	ErrInvalidTrigger = errors.New("invalid script trigger")
+
+	// ErrMissingScriptVersion indicates a missing script version
+	ErrMissingScriptVersion = errors.New("missing script version")
+
+	// ErrMissingModuleID indicates a missing Lua module ID
+	ErrMissingModuleID = errors.New("missing module id")
+
+	// ErrInvalidModuleName indicates an invalid Lua module name
+	ErrInvalidModuleName = errors.New("invalid module name")
+
 	// ErrInviteExpired indicates that an invite has expired
 	ErrInviteExpired = errors.New("invite expired")
 
@@ -65,6 +65,7 @@ func LoggingErrorEncoder(logger logger.Logger, enc kithttp.ErrorEncoder) kithttp
 			errors.Contains(err, ErrMissingOrgID),
 			errors.Contains(err, ErrMissingNotifierID),
 			errors.Contains(err, ErrMissingAlarmID),
+			errors.Contains(err, ErrMissingEscalationPolicyID),
 			errors.Contains(err, ErrMissingUserID),
 			errors.Contains(err, ErrMissingRole),
 			errors.Contains(err, ErrInvalidSubject),
@@ -84,6 +85,8 @@ func LoggingErrorEncoder(logger logger.Logger, enc kithttp.ErrorEncoder) kithttp
 			errors.Contains(err, ErrInvalidDirection),
 			errors.Contains(err, ErrEmptyList),
 			errors.Contains(err, ErrEmptyState),
+			errors.Contains(err, ErrInvalidShadowVersion),
+			errors.Contains(err, ErrInvalidShadowName),
 			errors.Contains(err, ErrMissingSerial),
 			errors.Contains(err, ErrMissingCertData),
 			errors.Contains(err, ErrInvalidContact),
@@ -101,12 +104,27 @@ func LoggingErrorEncoder(logger logger.Logger, enc kithttp.ErrorEncoder) kithttp
 			errors.Contains(err, ErrMissingConditionField),
 			errors.Contains(err, ErrInvalidConditionComparator),
 			errors.Contains(err, ErrMissingConditionThreshold),
+			errors.Contains(err, ErrInvalidConditionValue),
+			errors.Contains(err, ErrInvalidConditionExpression),
+			errors.Contains(err, ErrInvalidConditionMode),
+			errors.Contains(err, ErrInvalidClearThreshold),
+			errors.Contains(err, ErrInvalidConditionDuration),
+			errors.Contains(err, ErrInvalidConditionAggregation),
 			errors.Contains(err, ErrInvalidActionType),
 			errors.Contains(err, ErrMissingActionID),
+			errors.Contains(err, ErrInvalidActionTarget),
+			errors.Contains(err, ErrInvalidPayloadTemplate),
+			errors.Contains(err, ErrInvalidSampleMessage),
+			errors.Contains(err, ErrInvalidCooldown),
 			errors.Contains(err, ErrInvalidAlarmLevel),
+			errors.Contains(err, ErrInvalidEscalationStep),
 			errors.Contains(err, ErrInvalidAlarmStatus),
 			errors.Contains(err, ErrInvalidOperator),
 			errors.Contains(err, ErrInvalidInputType),
+			errors.Contains(err, ErrInvalidTrigger),
+			errors.Contains(err, ErrMissingScriptVersion),
+			errors.Contains(err, ErrMissingModuleID),
+			errors.Contains(err, ErrInvalidModuleName),
 			errors.Contains(err, ErrThingIDsSize),
 			errors.Contains(err, ErrInvalidThingType),
 			errors.Contains(err, ErrMissingAuth):
@@ -177,6 +195,7 @@ func EncodeError(err error, w http.ResponseWriter) {
 		errors.Contains(err, ErrMissingMemberID),
 		errors.Contains(err, ErrMissingNotifierID),
 		errors.Contains(err, ErrMissingAlarmID),
+		errors.Contains(err, ErrMissingEscalationPolicyID),
 		errors.Contains(err, ErrMissingRuleID),
 		errors.Contains(err, ErrMissingScriptID),
 		errors.Contains(err, ErrMissingScriptRunID),
@@ -195,6 +214,8 @@ func EncodeError(err error, w http.ResponseWriter) {
 		errors.Contains(err, ErrInvalidDirection),
 		errors.Contains(err, ErrEmptyList),
 		errors.Contains(err, ErrEmptyState),
+		errors.Contains(err, ErrInvalidShadowVersion),
+		errors.Contains(err, ErrInvalidShadowName),
 		errors.Contains(err, ErrMissingSerial),
 		errors.Contains(err, ErrMissingCertData),
 		errors.Contains(err, ErrInvalidContact),
@@ -212,17 +233,33 @@ func EncodeError(err error, w http.ResponseWriter) {
 		errors.Contains(err, errors.ErrMalformedEntity),
 		errors.Contains(err, ErrInvalidRole),
 		errors.Contains(err, ErrMissingConditionField),
+		errors.Contains(err, ErrInvalidConditionComparator),
 		errors.Contains(err, ErrMissingConditionThreshold),
+		errors.Contains(err, ErrInvalidConditionValue),
+		errors.Contains(err, ErrInvalidConditionExpression),
+		errors.Contains(err, ErrInvalidConditionMode),
+		errors.Contains(err, ErrInvalidClearThreshold),
+		errors.Contains(err, ErrInvalidConditionDuration),
+		errors.Contains(err, ErrInvalidConditionAggregation),
 		errors.Contains(err, ErrInvalidActionType),
 		errors.Contains(err, ErrMissingActionID),
+		errors.Contains(err, ErrInvalidActionTarget),
+		errors.Contains(err, ErrInvalidPayloadTemplate),
+		errors.Contains(err, ErrInvalidSampleMessage),
+		errors.Contains(err, ErrInvalidCooldown),
 		errors.Contains(err, ErrInvalidOperator),
 		errors.Contains(err, ErrInvalidProvider),
 		errors.Contains(err, ErrMissingProviderCode),
 		errors.Contains(err, ErrInvalidState),
 		errors.Contains(err, ErrInvalidThingType),
 		errors.Contains(err, ErrInvalidAlarmLevel),
+		errors.Contains(err, ErrInvalidEscalationStep),
 		errors.Contains(err, ErrInvalidAlarmStatus),
 		errors.Contains(err, ErrInvalidInputType),
+		errors.Contains(err, ErrInvalidTrigger),
+		errors.Contains(err, ErrMissingScriptVersion),
+		errors.Contains(err, ErrMissingModuleID),
+		errors.Contains(err, ErrInvalidModuleName),
 		errors.Contains(err, ErrThingIDsSize):
 		w.WriteHeader(http.StatusBadRequest)
 	case errors.Contains(err, errors.ErrAuthorization),
@@ -9,6 +9,7 @@ const (
 	Users   = "users"
 	Rules   = "rules"
 	Readers = "readers"
+	Shadows = "shadows"
 )
 
 type Config struct {
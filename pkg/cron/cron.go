@@ -42,6 +42,16 @@ func (sm *ScheduleManager) RemoveCronEntry(entityID, timezone string) {
 	}
 }
 
+func (sm *ScheduleManager) RemoveTimer(entityID string) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if t, ok := sm.TimerByID[entityID]; ok {
+		t.Stop()
+		delete(sm.TimerByID, entityID)
+	}
+}
+
 func (sm *ScheduleManager) InitCron(timezone string) (*cron.Cron, error) {
 	sm.mu.RLock()
 	c, exists := sm.CronByTZ[timezone]
@@ -0,0 +1,34 @@
+package dbutil
+
+import (
+	"context"
+)
+
+// DeleteBatchSize is the number of rows removed at once by DeleteInBatches.
+const DeleteBatchSize = 1000
+
+// DeleteInBatches executes the delete query until it removes fewer than DeleteBatchSize rows,
+// so that removing many rows doesn't lock them all in a single transaction. The query must
+// remove at most :batch rows, e.g. by selecting the IDs of the removed rows with LIMIT :batch.
+func DeleteInBatches(ctx context.Context, db Database, query string, params map[string]any) error {
+	args := map[string]any{"batch": DeleteBatchSize}
+	for k, v := range params {
+		args[k] = v
+	}
+
+	for {
+		res, err := db.NamedExecContext(ctx, query, args)
+		if err != nil {
+			return err
+		}
+
+		cnt, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if cnt < DeleteBatchSize {
+			return nil
+		}
+	}
+}
@@ -14,11 +14,44 @@ const (
 type Condition struct {
 	Field      string   `json:"field"`
 	Comparator string   `json:"comparator"`
-	Threshold  *float64 `json:"threshold"`
+	Threshold  *float64 `json:"threshold,omitempty"`
+	// Value is the operand of non-numeric comparisons: a string or boolean for equality,
+	// a substring for "contains", a regular expression for "matches" or a list for "in" and "not in".
+	Value any `json:"value,omitempty"`
+	// ClearThreshold enables hysteresis: once met, the condition stays met
+	// until the value no longer satisfies the comparator against ClearThreshold.
+	ClearThreshold *float64 `json:"clear_threshold,omitempty"`
+	// Duration is the number of seconds the condition must hold continuously before it is met.
+	Duration uint64 `json:"duration,omitempty"`
+	// Mode selects the compared value: the raw field value by default, or its delta / rate of change.
+	Mode string `json:"mode,omitempty"`
+	// Aggregation compares an aggregate of the field values over a window of recent messages
+	// instead of the value of the current message.
+	Aggregation *Aggregation `json:"aggregation,omitempty"`
 }
 
-// RuleInfo captures the evaluation logic of the rule that triggered an alarm.
+// Aggregation describes an aggregate (avg, min, max, sum or count) computed over a window
+// bounded by the number of most recent messages, by time, or by both.
+type Aggregation struct {
+	Type string `json:"type"`
+	// Count is the number of most recent messages in the window.
+	Count uint64 `json:"count,omitempty"`
+	// Period is the length of the window in seconds.
+	Period uint64 `json:"period,omitempty"`
+}
+
+// ConditionGroup represents a node of a nested boolean expression: its conditions and
+// subgroups are joined by the logical operator, e.g. (temp > 80 AND humidity > 60) OR smoke == 1.
+type ConditionGroup struct {
+	Operator   string           `json:"operator,omitempty"`
+	Conditions []Condition      `json:"conditions,omitempty"`
+	Groups     []ConditionGroup `json:"groups,omitempty"`
+}
+
+// RuleInfo captures the evaluation logic of the rule that triggered an alarm: the
+// conditions and operator of a flat rule, or the expression of a nested one.
 type RuleInfo struct {
-	Conditions []Condition `json:"conditions"`
-	Operator   string      `json:"operator,omitempty"`
+	Conditions []Condition     `json:"conditions,omitempty"`
+	Operator   string          `json:"operator,omitempty"`
+	Expression *ConditionGroup `json:"expression,omitempty"`
 }
@@ -0,0 +1,27 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package domain
+
+import "context"
+
+// Shadow represents the state of a thing, or of one of its components in case
+// of a named shadow, as kept by the shadows service.
+type Shadow struct {
+	ThingID string
+	// Name identifies one of the shadows of the thing. It is empty for the default shadow.
+	Name     string
+	Desired  map[string]any
+	Reported map[string]any
+	// Delta holds the desired state keys that differ from the reported state.
+	Delta      map[string]any
+	Version    uint64
+	ReportedAt int64
+	UpdatedAt  int64
+}
+
+// ShadowsClient specifies the API for reading thing shadows from the shadows service via gRPC.
+type ShadowsClient interface {
+	// ViewShadow returns the thing's shadow having the given name.
+	ViewShadow(ctx context.Context, thingID, name string) (Shadow, error)
+}
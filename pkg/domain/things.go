@@ -124,6 +124,8 @@ type ProfileConfig struct {
 	WriteEnabled   bool        `json:"write_enabled"`
 	WebhookEnabled bool        `json:"webhook_enabled"`
 	RuleEnabled    bool        `json:"rule_enabled"`
+	// TransformScriptID is the ID of the Lua script that transforms the messages before they are stored.
+	TransformScriptID string `json:"transform_script_id,omitempty"`
 }
 
 // Transformer represents message transformation config.
@@ -163,6 +165,7 @@ type ThingsClient interface {
 	GetGroupIDByProfile(ctx context.Context, profileID string) (string, error)
 	GetGroupIDsByOrg(ctx context.Context, ar OrgAccessReq) ([]string, error)
 	GetThingIDsByProfile(ctx context.Context, profileID string) ([]string, error)
+	GetThingIDsByGroup(ctx context.Context, groupID string) ([]string, error)
 	CreateGroupMemberships(ctx context.Context, memberships ...GroupMembership) error
 	GetGroup(ctx context.Context, groupID string) (Group, error)
 	GetKeyByThingID(ctx context.Context, thingID string) (ThingKey, error)
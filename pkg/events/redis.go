@@ -24,6 +24,8 @@ const (
 	ThingUpdate                = thingPrefix + "update"
 	ThingUpdateGroupAndProfile = thingPrefix + "update_group_and_profile"
 	ThingRemove                = thingPrefix + "remove"
+	ThingConnect               = thingPrefix + "connect"
+	ThingDisconnect            = thingPrefix + "disconnect"
 
 	ProfileCreate = profilePrefix + "create"
 	ProfileUpdate = profilePrefix + "update"
@@ -46,6 +48,7 @@ const (
 	// Redis event streams
 	ThingsStream = mainfluxPrefix + "things"
 	AuthStream   = mainfluxPrefix + "auth"
+	MQTTStream   = mainfluxPrefix + "mqtt"
 )
 
 // redisEvent is the raw payload delivered on a Redis stream.
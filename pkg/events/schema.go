@@ -69,6 +69,10 @@ func decodeEvent(re redisEvent) (Event, error) {
 		action = decodeThingGroupAndProfileUpdated(re)
 	case ThingRemove:
 		action = decodeThingRemoved(re)
+	case ThingConnect:
+		action = decodeThingConnected(re)
+	case ThingDisconnect:
+		action = decodeThingDisconnected(re)
 	case ProfileCreate:
 		action = decodeProfileCreated(re)
 	case ProfileUpdate:
@@ -226,6 +230,54 @@ func decodeThingRemoved(e redisEvent) ThingRemoved {
 	return ThingRemoved{ID: e.field("id", "")}
 }
 
+// ThingConnected signals that an MQTT client of a thing has connected.
+type ThingConnected struct {
+	ID       string
+	ClientID string
+}
+
+func (e ThingConnected) Operation() string {
+	return ThingConnect
+}
+
+func (e ThingConnected) Encode() map[string]any {
+	return redisEvent{
+		"id":        e.ID,
+		"client_id": e.ClientID,
+	}
+}
+
+func decodeThingConnected(e redisEvent) ThingConnected {
+	return ThingConnected{
+		ID:       e.field("id", ""),
+		ClientID: e.field("client_id", ""),
+	}
+}
+
+// ThingDisconnected signals that an MQTT client of a thing has disconnected.
+type ThingDisconnected struct {
+	ID       string
+	ClientID string
+}
+
+func (e ThingDisconnected) Operation() string {
+	return ThingDisconnect
+}
+
+func (e ThingDisconnected) Encode() map[string]any {
+	return redisEvent{
+		"id":        e.ID,
+		"client_id": e.ClientID,
+	}
+}
+
+func decodeThingDisconnected(e redisEvent) ThingDisconnected {
+	return ThingDisconnected{
+		ID:       e.field("id", ""),
+		ClientID: e.field("client_id", ""),
+	}
+}
+
 // ProfileCreated signals the creation of a profile.
 type ProfileCreated struct {
 	ID       string
@@ -0,0 +1,119 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package lru provides an in-memory cache of bounded size, which evicts the
+// least recently used entries.
+package lru
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Cache is an in-memory cache holding up to a fixed number of entries, which
+// optionally expire after a fixed time. It is safe for concurrent use.
+type Cache[K comparable, V any] struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	entries map[K]*list.Element
+	order   *list.List
+}
+
+type entry[K comparable, V any] struct {
+	key     K
+	value   V
+	expires time.Time
+}
+
+// New returns a cache holding up to size entries, which expire ttl after they
+// are added. Entries don't expire if ttl is zero.
+func New[K comparable, V any](size int, ttl time.Duration) *Cache[K, V] {
+	return &Cache[K, V]{
+		size:    max(size, 1),
+		ttl:     ttl,
+		entries: make(map[K]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Get returns the value cached for the key, and reports whether it was found.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+	el, ok := c.entries[key]
+	if !ok {
+		return zero, false
+	}
+
+	e := el.Value.(*entry[K, V])
+	if c.ttl > 0 && time.Now().After(e.expires) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return zero, false
+	}
+
+	c.order.MoveToFront(el)
+	return e.value, true
+}
+
+// Add caches the value for the key, evicting the least recently used entry
+// if the cache is full.
+func (c *Cache[K, V]) Add(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e := &entry[K, V]{key: key, value: value}
+	if c.ttl > 0 {
+		e.expires = time.Now().Add(c.ttl)
+	}
+
+	if el, ok := c.entries[key]; ok {
+		el.Value = e
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(e)
+	if c.order.Len() > c.size {
+		last := c.order.Back()
+		c.order.Remove(last)
+		delete(c.entries, last.Value.(*entry[K, V]).key)
+	}
+}
+
+// Remove removes the value cached for the key.
+func (c *Cache[K, V]) Remove(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		c.order.Remove(el)
+		delete(c.entries, key)
+	}
+}
+
+// RemoveFunc removes the values cached for the keys for which fn returns true.
+func (c *Cache[K, V]) RemoveFunc(fn func(key K) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, el := range c.entries {
+		if fn(key) {
+			c.order.Remove(el)
+			delete(c.entries, key)
+		}
+	}
+}
+
+// Len returns the number of cached entries, including the expired ones that
+// weren't removed yet.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
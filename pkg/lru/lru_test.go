@@ -0,0 +1,84 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package lru_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MainfluxLabs/mainflux/pkg/lru"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	c := lru.New[string, int](2, 0)
+	c.Add("a", 1)
+	c.Add("b", 2)
+
+	// Getting a makes b the least recently used entry, which is evicted by adding c.
+	_, ok := c.Get("a")
+	assert.True(t, ok, "expected a to be cached")
+	c.Add("c", 3)
+
+	cases := []struct {
+		desc  string
+		key   string
+		value int
+		found bool
+	}{
+		{
+			desc:  "get recently used entry",
+			key:   "a",
+			value: 1,
+			found: true,
+		},
+		{
+			desc:  "get evicted entry",
+			key:   "b",
+			value: 0,
+			found: false,
+		},
+		{
+			desc:  "get added entry",
+			key:   "c",
+			value: 3,
+			found: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			value, found := c.Get(tc.key)
+			assert.Equal(t, tc.found, found)
+			assert.Equal(t, tc.value, value)
+		})
+	}
+
+	assert.Equal(t, 2, c.Len())
+
+	c.Remove("a")
+	_, ok = c.Get("a")
+	assert.False(t, ok, "expected removed entry not to be cached")
+
+	c.Add("ab", 4)
+	c.RemoveFunc(func(key string) bool { return key[0] == 'a' })
+	_, ok = c.Get("ab")
+	assert.False(t, ok, "expected entry removed by func not to be cached")
+	_, ok = c.Get("c")
+	assert.True(t, ok, "expected entry not removed by func to be cached")
+}
+
+func TestCacheExpiry(t *testing.T) {
+	c := lru.New[string, int](2, time.Millisecond)
+	c.Add("a", 1)
+
+	value, ok := c.Get("a")
+	assert.True(t, ok, "expected a to be cached")
+	assert.Equal(t, 1, value)
+
+	time.Sleep(2 * time.Millisecond)
+	_, ok = c.Get("a")
+	assert.False(t, ok, "expected expired entry not to be cached")
+	assert.Equal(t, 0, c.Len())
+}
@@ -5,6 +5,7 @@ package nats
 
 import (
 	"errors"
+	"slices"
 	"strings"
 
 	broker "github.com/nats-io/nats.go"
@@ -21,6 +22,8 @@ var streamSubjects = []string{
 	SubjectSmpp,
 	SubjectWebhooks,
 	SubjectRules,
+	SubjectShadows,
+	SubjectTransforms,
 }
 
 func connect(url string) (*broker.Conn, broker.JetStreamContext, error) {
@@ -44,9 +47,9 @@ func connect(url string) (*broker.Conn, broker.JetStreamContext, error) {
 }
 
 func ensureStream(js broker.JetStreamContext) error {
-	_, err := js.StreamInfo(streamName)
+	info, err := js.StreamInfo(streamName)
 	if err == nil {
-		return nil
+		return updateStreamSubjects(js, info.Config)
 	}
 
 	if !errors.Is(err, broker.ErrStreamNotFound) {
@@ -66,6 +69,23 @@ func ensureStream(js broker.JetStreamContext) error {
 	return err
 }
 
+// updateStreamSubjects adds the subjects missing from a stream created by a previous version.
+func updateStreamSubjects(js broker.JetStreamContext, cfg broker.StreamConfig) error {
+	missing := false
+	for _, subject := range streamSubjects {
+		if !slices.Contains(cfg.Subjects, subject) {
+			cfg.Subjects = append(cfg.Subjects, subject)
+			missing = true
+		}
+	}
+	if !missing {
+		return nil
+	}
+
+	_, err := js.UpdateStream(&cfg)
+	return err
+}
+
 func durableName(queue, id, topic string) string {
 	base := id
 	if queue != "" {
@@ -18,10 +18,12 @@ const (
 	// will never give up on retrying to re-establish connection to NATS server.
 	maxReconnects = -1
 
-	thingsPrefix   = "things"
-	groupsPrefix   = "groups"
-	messagesSuffix = "messages"
-	commandsSuffix = "commands"
+	thingsPrefix     = "things"
+	groupsPrefix     = "groups"
+	messagesSuffix   = "messages"
+	commandsSuffix   = "commands"
+	shadowsPrefix    = "shadows"
+	transformsPrefix = "transforms"
 )
 
 type publisher struct {
@@ -91,6 +93,16 @@ func GetGroupCommandsSubject(groupID, subtopic string) string {
 	return createSubject(groupsPrefix, groupID, commandsSuffix, subtopic)
 }
 
+// GetShadowsSubject returns the subject used to route the thing's desired state updates to the shadows service.
+func GetShadowsSubject(thingID string) string {
+	return fmt.Sprintf("%s.%s", shadowsPrefix, thingID)
+}
+
+// GetTransformsSubject returns the subject used to route messages to the rules service for transformation by the script.
+func GetTransformsSubject(scriptID string) string {
+	return fmt.Sprintf("%s.%s", transformsPrefix, scriptID)
+}
+
 func createSubject(entity, id, suffix, subtopic string) string {
 	subject := fmt.Sprintf("%s.%s.%s", entity, id, suffix)
 	if subtopic != "" {
@@ -106,7 +118,12 @@ func (pub *publisher) Dispatch(msg protomfx.Message, pc *domain.ProfileConfig) e
 	}
 
 	if pc.WriteEnabled {
-		if err := pub.publish(GetMessagesSubject(msg.Publisher, msg.Subtopic), &msg); err != nil {
+		subject := GetMessagesSubject(msg.Publisher, msg.Subtopic)
+		// Messages having a transform script are stored once the rules service transforms them.
+		if pc.TransformScriptID != "" {
+			subject = GetTransformsSubject(pc.TransformScriptID)
+		}
+		if err := pub.publish(subject, &msg); err != nil {
 			return err
 		}
 	}
@@ -117,9 +134,10 @@ func (pub *publisher) Dispatch(msg protomfx.Message, pc *domain.ProfileConfig) e
 	}
 	if pc.WebhookEnabled {
 		webhook := protomfx.Webhook{
-			ThingId: msg.Publisher,
-			Payload: msg.Payload,
-			Created: msg.Created,
+			ThingId:  msg.Publisher,
+			Payload:  msg.Payload,
+			Created:  msg.Created,
+			Subtopic: msg.Subtopic,
 		}
 		if err := pub.PublishWebhook(SubjectWebhooks, webhook); err != nil {
 			return err
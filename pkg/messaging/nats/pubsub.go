@@ -6,6 +6,7 @@ package nats
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/gogo/protobuf/proto"
 
@@ -42,6 +43,13 @@ const (
 	SubjectWebhooks = "webhooks"
 	// SubjectRules represents subject used to route messages to the rules service.
 	SubjectRules = "rules"
+	// SubjectShadows represents subject used to route desired state updates to the shadows service.
+	SubjectShadows = "shadows.*"
+	// SubjectTransforms represents subject used to route messages to the rules service for transformation before they are stored.
+	SubjectTransforms = "transforms.*"
+
+	// webhookRedeliveryDelay is the delay before a webhook message that failed to be handled is redelivered.
+	webhookRedeliveryDelay = time.Second
 )
 
 type subscription struct {
@@ -146,7 +154,7 @@ func (ps *pubsub) unsubscribe(id, topic string) error {
 
 // subscribe registers a NATS subscription for the given id and topic.
 // Must be called with ps.mu held.
-func (ps *pubsub) subscribe(id, topic string, nh broker.MsgHandler, cancelFn func() error) error {
+func (ps *pubsub) subscribe(id, topic string, nh broker.MsgHandler, cancelFn func() error, opts ...broker.SubOpt) error {
 	s, ok := ps.subscriptions[topic]
 	if !ok {
 		s = make(map[string]subscription)
@@ -158,11 +166,12 @@ func (ps *pubsub) subscribe(id, topic string, nh broker.MsgHandler, cancelFn fun
 		err error
 	)
 	durable := durableName(ps.queue, id, topic)
+	opts = append([]broker.SubOpt{broker.Durable(durable), broker.DeliverAll()}, opts...)
 	switch ps.queue {
 	case "":
-		sub, err = ps.js.Subscribe(topic, nh, broker.Durable(durable), broker.DeliverAll())
+		sub, err = ps.js.Subscribe(topic, nh, opts...)
 	default:
-		sub, err = ps.js.QueueSubscribe(topic, ps.queue, nh, broker.Durable(durable), broker.DeliverAll())
+		sub, err = ps.js.QueueSubscribe(topic, ps.queue, nh, opts...)
 	}
 	if err != nil {
 		return err
@@ -298,7 +307,8 @@ func (ps *pubsub) SubscribeWebhooks(id string, handler messaging.WebhookHandler)
 	ps.mu.Lock()
 	defer ps.mu.Unlock()
 
-	return ps.subscribe(id, SubjectWebhooks, ps.natsWebhookHandler(handler), handler.Cancel)
+	// Webhook messages are acknowledged once handled, so that those failing to be handled are redelivered.
+	return ps.subscribe(id, SubjectWebhooks, ps.natsWebhookHandler(handler), handler.Cancel, broker.ManualAck())
 }
 
 func (ps *pubsub) UnsubscribeWebhooks(id string) error {
@@ -330,11 +340,15 @@ func (ps *pubsub) natsWebhookHandler(h messaging.WebhookHandler) broker.MsgHandl
 		var webhook protomfx.Webhook
 		if err := proto.Unmarshal(m.Data, &webhook); err != nil {
 			ps.logger.Warn(fmt.Sprintf("Failed to unmarshal received webhook: %s", err))
+			m.Term()
 			return
 		}
 		if err := h.Handle(m.Subject, webhook); err != nil {
 			ps.logger.Warn(fmt.Sprintf("Failed to handle webhook: %s", err))
+			m.NakWithDelay(webhookRedeliveryDelay)
+			return
 		}
+		m.Ack()
 	}
 }
 
@@ -0,0 +1,41 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/MainfluxLabs/mainflux/pkg/dbutil"
+	"github.com/MainfluxLabs/mainflux/pkg/domain"
+)
+
+var _ domain.ShadowsClient = (*shadowsClient)(nil)
+
+type shadowsClient struct {
+	mu      sync.Mutex
+	shadows map[string]domain.Shadow
+}
+
+// NewShadowsClient returns a ShadowsClient mock serving the given shadows.
+func NewShadowsClient(shadows ...domain.Shadow) domain.ShadowsClient {
+	sc := &shadowsClient{shadows: make(map[string]domain.Shadow)}
+	for _, sh := range shadows {
+		sc.shadows[sh.ThingID+"/"+sh.Name] = sh
+	}
+
+	return sc
+}
+
+func (sc *shadowsClient) ViewShadow(_ context.Context, thingID, name string) (domain.Shadow, error) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	sh, ok := sc.shadows[thingID+"/"+name]
+	if !ok {
+		return domain.Shadow{}, dbutil.ErrNotFound
+	}
+
+	return sh, nil
+}
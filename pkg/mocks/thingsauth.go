@@ -179,6 +179,17 @@ func (svc thingsServiceMock) GetThingIDsByProfile(_ context.Context, profileID s
 	return ids, nil
 }
 
+func (svc thingsServiceMock) GetThingIDsByGroup(_ context.Context, groupID string) ([]string, error) {
+	var ids []string
+	for key, t := range svc.things {
+		// Things are also keyed by their keys, so only the entries keyed by ID are listed.
+		if key == t.ID && t.GroupID == groupID {
+			ids = append(ids, t.ID)
+		}
+	}
+	return ids, nil
+}
+
 func (svc thingsServiceMock) CreateGroupMemberships(_ context.Context, _ ...domain.GroupMembership) error {
 	return nil
 }
@@ -0,0 +1,198 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package predicate evaluates comparisons of JSON payload values, shared by
+// the rule conditions and the webhook filters.
+package predicate
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/MainfluxLabs/mainflux/pkg/lru"
+)
+
+const (
+	OperatorAND = "AND"
+	OperatorOR  = "OR"
+
+	ComparatorEQ       = "=="
+	ComparatorNEQ      = "!="
+	ComparatorGTE      = ">="
+	ComparatorLTE      = "<="
+	ComparatorGT       = ">"
+	ComparatorLT       = "<"
+	ComparatorContains = "contains"
+	ComparatorIn       = "in"
+	ComparatorNotIn    = "not in"
+	ComparatorMatches  = "matches"
+)
+
+// Field returns the value of the dot-separated path of the payload field, e.g.
+// sensor.temp, and reports whether the field exists.
+func Field(payload map[string]any, path string) (any, bool) {
+	if path == "" {
+		return nil, false
+	}
+
+	parts := strings.Split(path, ".")
+	current := payload
+	for _, key := range parts[:len(parts)-1] {
+		nested, ok := current[key].(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		current = nested
+	}
+
+	value, ok := current[parts[len(parts)-1]]
+	return value, ok
+}
+
+// ToFloat converts numeric values, and strings holding numbers, to float64.
+func ToFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case string:
+		val, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, false
+		}
+		return val, true
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// CompareNumbers reports whether val1 and val2 satisfy the numeric comparator.
+func CompareNumbers(comparator string, val1, val2 float64) bool {
+	switch comparator {
+	case ComparatorEQ:
+		return val1 == val2
+	case ComparatorNEQ:
+		return val1 != val2
+	case ComparatorGTE:
+		return val1 >= val2
+	case ComparatorLTE:
+		return val1 <= val2
+	case ComparatorGT:
+		return val1 > val2
+	case ComparatorLT:
+		return val1 < val2
+	default:
+		return false
+	}
+}
+
+// Equal reports whether two values are equal. Booleans and strings are compared
+// as such, and other values as numbers.
+func Equal(val1, val2 any) bool {
+	switch v1 := val1.(type) {
+	case bool:
+		v2, ok := val2.(bool)
+		return ok && v1 == v2
+	case string:
+		if v2, ok := val2.(string); ok {
+			return v1 == v2
+		}
+	}
+
+	n1, ok := ToFloat(val1)
+	if !ok {
+		return false
+	}
+	n2, ok := ToFloat(val2)
+	return ok && n1 == n2
+}
+
+// Contains reports whether a string value contains the operand substring, or
+// whether an array value contains the operand.
+func Contains(value, operand any) bool {
+	switch v := value.(type) {
+	case string:
+		sub, ok := operand.(string)
+		return ok && strings.Contains(v, sub)
+	case []any:
+		return slices.ContainsFunc(v, func(item any) bool { return Equal(item, operand) })
+	default:
+		return false
+	}
+}
+
+// In reports whether the operand is a list containing the value.
+func In(value, operand any) bool {
+	values, ok := operand.([]any)
+	if !ok {
+		return false
+	}
+	return slices.ContainsFunc(values, func(item any) bool { return Equal(value, item) })
+}
+
+// NotIn reports whether the operand is a list not containing the value.
+func NotIn(value, operand any) bool {
+	if _, ok := operand.([]any); !ok {
+		return false
+	}
+	return !In(value, operand)
+}
+
+// Patterns caches compiled regular expressions of matches comparisons.
+// A nil Patterns compiles the regular expression on every comparison.
+type Patterns struct {
+	cache *lru.Cache[string, *regexp.Regexp]
+}
+
+// NewPatterns returns a cache of up to size compiled regular expressions.
+func NewPatterns(size int) *Patterns {
+	return &Patterns{cache: lru.New[string, *regexp.Regexp](size, 0)}
+}
+
+// Matches reports whether the string value matches the operand regular expression.
+func (p *Patterns) Matches(value, operand any) bool {
+	str, ok := value.(string)
+	if !ok {
+		return false
+	}
+	pattern, ok := operand.(string)
+	if !ok {
+		return false
+	}
+
+	re, err := p.compile(pattern)
+	if err != nil {
+		return false
+	}
+
+	return re.MatchString(str)
+}
+
+func (p *Patterns) compile(pattern string) (*regexp.Regexp, error) {
+	if p == nil {
+		return regexp.Compile(pattern)
+	}
+
+	if re, ok := p.cache.Get(pattern); ok {
+		return re, nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	p.cache.Add(pattern, re)
+
+	return re, nil
+}
@@ -0,0 +1,123 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package predicate_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/MainfluxLabs/mainflux/pkg/predicate"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToFloat(t *testing.T) {
+	cases := []struct {
+		desc  string
+		value any
+		res   float64
+		ok    bool
+	}{
+		{desc: "convert float64", value: float64(1.5), res: 1.5, ok: true},
+		{desc: "convert int", value: int(2), res: 2, ok: true},
+		{desc: "convert int32", value: int32(3), res: 3, ok: true},
+		{desc: "convert int64", value: int64(4), res: 4, ok: true},
+		{desc: "convert uint", value: uint(5), res: 5, ok: true},
+		{desc: "convert uint64", value: uint64(6), res: 6, ok: true},
+		{desc: "convert numeric string", value: "7.5", res: 7.5, ok: true},
+		{desc: "convert non-numeric string", value: "seven", res: 0, ok: false},
+		{desc: "convert bool", value: true, res: 0, ok: false},
+	}
+
+	for _, tc := range cases {
+		res, ok := predicate.ToFloat(tc.value)
+		assert.Equal(t, tc.ok, ok, fmt.Sprintf("%s: expected %t got %t", tc.desc, tc.ok, ok))
+		assert.Equal(t, tc.res, res, fmt.Sprintf("%s: expected %f got %f", tc.desc, tc.res, res))
+	}
+}
+
+func TestField(t *testing.T) {
+	payload := map[string]any{
+		"temp":   21.5,
+		"sensor": map[string]any{"humidity": 40},
+	}
+
+	cases := []struct {
+		desc  string
+		path  string
+		value any
+		ok    bool
+	}{
+		{desc: "get top level field", path: "temp", value: 21.5, ok: true},
+		{desc: "get nested field", path: "sensor.humidity", value: 40, ok: true},
+		{desc: "get missing field", path: "sensor.pressure", value: nil, ok: false},
+		{desc: "get field of non-object", path: "temp.value", value: nil, ok: false},
+		{desc: "get empty path", path: "", value: nil, ok: false},
+	}
+
+	for _, tc := range cases {
+		value, ok := predicate.Field(payload, tc.path)
+		assert.Equal(t, tc.ok, ok, fmt.Sprintf("%s: expected %t got %t", tc.desc, tc.ok, ok))
+		assert.Equal(t, tc.value, value, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.value, value))
+	}
+}
+
+func TestEqual(t *testing.T) {
+	cases := []struct {
+		desc string
+		val1 any
+		val2 any
+		res  bool
+	}{
+		{desc: "compare equal numbers of different types", val1: int64(3), val2: float64(3), res: true},
+		{desc: "compare number and numeric string", val1: float64(3), val2: "3", res: true},
+		{desc: "compare different strings", val1: "on", val2: "off", res: false},
+		{desc: "compare equal booleans", val1: true, val2: true, res: true},
+		{desc: "compare boolean and string", val1: true, val2: "true", res: false},
+	}
+
+	for _, tc := range cases {
+		res := predicate.Equal(tc.val1, tc.val2)
+		assert.Equal(t, tc.res, res, fmt.Sprintf("%s: expected %t got %t", tc.desc, tc.res, res))
+	}
+}
+
+func TestNotIn(t *testing.T) {
+	cases := []struct {
+		desc    string
+		value   any
+		operand any
+		res     bool
+	}{
+		{desc: "value not in list", value: "idle", operand: []any{"active", "alarm"}, res: true},
+		{desc: "value in list", value: float64(2), operand: []any{"1", "2"}, res: false},
+		{desc: "operand not a list", value: "idle", operand: "active", res: false},
+	}
+
+	for _, tc := range cases {
+		res := predicate.NotIn(tc.value, tc.operand)
+		assert.Equal(t, tc.res, res, fmt.Sprintf("%s: expected %t got %t", tc.desc, tc.res, res))
+	}
+}
+
+func TestPatternsMatches(t *testing.T) {
+	cases := []struct {
+		desc    string
+		value   any
+		operand any
+		res     bool
+	}{
+		{desc: "match string", value: "sensor-12", operand: `^sensor-\d+$`, res: true},
+		{desc: "match string again from cache", value: "sensor-7", operand: `^sensor-\d+$`, res: true},
+		{desc: "mismatch string", value: "probe-12", operand: `^sensor-\d+$`, res: false},
+		{desc: "match non-string value", value: float64(12), operand: `\d+`, res: false},
+		{desc: "match invalid pattern", value: "sensor", operand: `(`, res: false},
+	}
+
+	for _, patterns := range []*predicate.Patterns{predicate.NewPatterns(1), nil} {
+		for _, tc := range cases {
+			res := patterns.Matches(tc.value, tc.operand)
+			assert.Equal(t, tc.res, res, fmt.Sprintf("%s: expected %t got %t", tc.desc, tc.res, res))
+		}
+	}
+}
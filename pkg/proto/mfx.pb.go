@@ -210,6 +210,7 @@ type Alarm struct {
 	Level                int32    `protobuf:"varint,5,opt,name=level,proto3" json:"level,omitempty"`
 	RuleId               string   `protobuf:"bytes,6,opt,name=rule_id,json=ruleId,proto3" json:"rule_id,omitempty"`
 	RuleInfo             []byte   `protobuf:"bytes,7,opt,name=rule_info,json=ruleInfo,proto3" json:"rule_info,omitempty"`
+	Cleared              bool     `protobuf:"varint,8,opt,name=cleared,proto3" json:"cleared,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -297,6 +298,13 @@ func (m *Alarm) GetRuleInfo() []byte {
 	return nil
 }
 
+func (m *Alarm) GetCleared() bool {
+	if m != nil {
+		return m.Cleared
+	}
+	return false
+}
+
 type Notification struct {
 	ThingId              string   `protobuf:"bytes,1,opt,name=thing_id,json=thingId,proto3" json:"thing_id,omitempty"`
 	Subtopic             string   `protobuf:"bytes,2,opt,name=subtopic,proto3" json:"subtopic,omitempty"`
@@ -380,6 +388,8 @@ type Webhook struct {
 	ThingId              string   `protobuf:"bytes,1,opt,name=thing_id,json=thingId,proto3" json:"thing_id,omitempty"`
 	Payload              []byte   `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
 	Created              int64    `protobuf:"varint,3,opt,name=created,proto3" json:"created,omitempty"`
+	Subtopic             string   `protobuf:"bytes,4,opt,name=subtopic,proto3" json:"subtopic,omitempty"`
+	WebhookId            string   `protobuf:"bytes,5,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -439,6 +449,20 @@ func (m *Webhook) GetCreated() int64 {
 	return 0
 }
 
+func (m *Webhook) GetSubtopic() string {
+	if m != nil {
+		return m.Subtopic
+	}
+	return ""
+}
+
+func (m *Webhook) GetWebhookId() string {
+	if m != nil {
+		return m.WebhookId
+	}
+	return ""
+}
+
 type ThingKey struct {
 	Value                string   `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
 	Type                 string   `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
@@ -555,6 +579,7 @@ type Config struct {
 	WriteEnabled         bool         `protobuf:"varint,3,opt,name=writeEnabled,proto3" json:"writeEnabled,omitempty"`
 	WebhookEnabled       bool         `protobuf:"varint,4,opt,name=webhookEnabled,proto3" json:"webhookEnabled,omitempty"`
 	RuleEnabled          bool         `protobuf:"varint,5,opt,name=ruleEnabled,proto3" json:"ruleEnabled,omitempty"`
+	TransformScriptID    string       `protobuf:"bytes,6,opt,name=transformScriptID,proto3" json:"transformScriptID,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
 	XXX_unrecognized     []byte       `json:"-"`
 	XXX_sizecache        int32        `json:"-"`
@@ -628,6 +653,13 @@ func (m *Config) GetRuleEnabled() bool {
 	return false
 }
 
+func (m *Config) GetTransformScriptID() string {
+	if m != nil {
+		return m.TransformScriptID
+	}
+	return ""
+}
+
 type ConfigByThingRes struct {
 	Config               *Config  `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -3176,6 +3208,148 @@ func (m *ListSenMLMessagesRes) GetMessages() []*Message {
 	return nil
 }
 
+type ShadowReq struct {
+	ThingId              string   `protobuf:"bytes,1,opt,name=thing_id,json=thingId,proto3" json:"thing_id,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ShadowReq) Reset()         { *m = ShadowReq{} }
+func (m *ShadowReq) String() string { return proto.CompactTextString(m) }
+func (*ShadowReq) ProtoMessage()    {}
+func (*ShadowReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f5c89a6f82d4869, []int{49}
+}
+func (m *ShadowReq) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ShadowReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ShadowReq.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ShadowReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShadowReq.Merge(m, src)
+}
+func (m *ShadowReq) XXX_Size() int {
+	return m.Size()
+}
+func (m *ShadowReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShadowReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ShadowReq proto.InternalMessageInfo
+
+func (m *ShadowReq) GetThingId() string {
+	if m != nil {
+		return m.ThingId
+	}
+	return ""
+}
+
+func (m *ShadowReq) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type ShadowRes struct {
+	Desired              []byte   `protobuf:"bytes,1,opt,name=desired,proto3" json:"desired,omitempty"`
+	Reported             []byte   `protobuf:"bytes,2,opt,name=reported,proto3" json:"reported,omitempty"`
+	Delta                []byte   `protobuf:"bytes,3,opt,name=delta,proto3" json:"delta,omitempty"`
+	Version              uint64   `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+	ReportedAt           int64    `protobuf:"varint,5,opt,name=reported_at,json=reportedAt,proto3" json:"reported_at,omitempty"`
+	UpdatedAt            int64    `protobuf:"varint,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ShadowRes) Reset()         { *m = ShadowRes{} }
+func (m *ShadowRes) String() string { return proto.CompactTextString(m) }
+func (*ShadowRes) ProtoMessage()    {}
+func (*ShadowRes) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f5c89a6f82d4869, []int{50}
+}
+func (m *ShadowRes) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ShadowRes) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ShadowRes.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ShadowRes) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShadowRes.Merge(m, src)
+}
+func (m *ShadowRes) XXX_Size() int {
+	return m.Size()
+}
+func (m *ShadowRes) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShadowRes.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ShadowRes proto.InternalMessageInfo
+
+func (m *ShadowRes) GetDesired() []byte {
+	if m != nil {
+		return m.Desired
+	}
+	return nil
+}
+
+func (m *ShadowRes) GetReported() []byte {
+	if m != nil {
+		return m.Reported
+	}
+	return nil
+}
+
+func (m *ShadowRes) GetDelta() []byte {
+	if m != nil {
+		return m.Delta
+	}
+	return nil
+}
+
+func (m *ShadowRes) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *ShadowRes) GetReportedAt() int64 {
+	if m != nil {
+		return m.ReportedAt
+	}
+	return 0
+}
+
+func (m *ShadowRes) GetUpdatedAt() int64 {
+	if m != nil {
+		return m.UpdatedAt
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Message)(nil), "protomfx.Message")
 	proto.RegisterType((*Command)(nil), "protomfx.Command")
@@ -3226,151 +3400,164 @@ func init() {
 	proto.RegisterType((*ListJSONMessagesRes)(nil), "protomfx.ListJSONMessagesRes")
 	proto.RegisterType((*ListSenMLMessagesReq)(nil), "protomfx.ListSenMLMessagesReq")
 	proto.RegisterType((*ListSenMLMessagesRes)(nil), "protomfx.ListSenMLMessagesRes")
+	proto.RegisterType((*ShadowReq)(nil), "protomfx.ShadowReq")
+	proto.RegisterType((*ShadowRes)(nil), "protomfx.ShadowRes")
 }
 
 func init() { proto.RegisterFile("pkg/proto/mfx.proto", fileDescriptor_4f5c89a6f82d4869) }
 
 var fileDescriptor_4f5c89a6f82d4869 = []byte{
-	// 2214 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xdc, 0x58, 0x5b, 0x73, 0x1b, 0x49,
-	0x15, 0xd6, 0x58, 0xf7, 0x23, 0xd9, 0x96, 0xdb, 0x5e, 0xaf, 0xa2, 0xac, 0xbd, 0x4e, 0x2f, 0x2c,
-	0xa9, 0xad, 0xc2, 0xd9, 0x72, 0x02, 0x0b, 0x1b, 0x6e, 0xb6, 0x95, 0xa8, 0x44, 0xe2, 0xd8, 0x35,
-	0x71, 0x92, 0x2d, 0x28, 0x2a, 0x35, 0x92, 0x5a, 0xe3, 0x21, 0x73, 0x11, 0x33, 0x2d, 0x67, 0xc5,
-	0x03, 0xbf, 0x81, 0xa2, 0xa0, 0x8a, 0x5f, 0x40, 0xf1, 0xc6, 0x13, 0x4f, 0x3c, 0xc0, 0x23, 0x8f,
-	0xcb, 0x3f, 0x80, 0xf0, 0x47, 0xa8, 0xbe, 0xcd, 0xf4, 0x8c, 0x46, 0x8a, 0x93, 0x65, 0x5f, 0xf6,
-	0x69, 0xe6, 0xf4, 0xe5, 0xeb, 0x3e, 0x7d, 0xbe, 0xd3, 0x7d, 0xce, 0x81, 0xcd, 0xc9, 0x0b, 0xfb,
-	0xd6, 0x24, 0x0c, 0x68, 0x70, 0xcb, 0x1b, 0x7f, 0xbe, 0xcf, 0xff, 0x50, 0x8d, 0x7f, 0xbc, 0xf1,
-	0xe7, 0x9d, 0xeb, 0x76, 0x10, 0xd8, 0x2e, 0x11, 0x23, 0x06, 0xd3, 0xf1, 0x2d, 0xe2, 0x4d, 0xe8,
-	0x4c, 0x0c, 0xc3, 0x7f, 0x35, 0xa0, 0x7a, 0x42, 0xa2, 0xc8, 0xb2, 0x09, 0x7a, 0x0f, 0xea, 0x93,
-	0xe9, 0xc0, 0x75, 0xa2, 0x0b, 0x12, 0xb6, 0x8d, 0x3d, 0xe3, 0x66, 0xdd, 0x4c, 0x1a, 0x50, 0x07,
-	0x6a, 0xd1, 0x74, 0x40, 0x83, 0x89, 0x33, 0x6c, 0xaf, 0xf0, 0xce, 0x58, 0x46, 0x6d, 0xa8, 0x4e,
-	0xac, 0x99, 0x1b, 0x58, 0xa3, 0x76, 0x71, 0xcf, 0xb8, 0xd9, 0x34, 0x95, 0x88, 0xf6, 0xa0, 0x31,
-	0x0c, 0x7c, 0x4a, 0x7c, 0x7a, 0x3e, 0x9b, 0x90, 0x76, 0x89, 0x4f, 0xd4, 0x9b, 0x18, 0x2e, 0xdf,
-	0xca, 0x30, 0x70, 0xdb, 0x65, 0x81, 0xab, 0x64, 0x86, 0x3b, 0x0c, 0x89, 0x45, 0xc9, 0xa8, 0x5d,
-	0xd9, 0x33, 0x6e, 0x16, 0x4d, 0x25, 0xf2, 0x7d, 0x1f, 0x07, 0x9e, 0x67, 0xf9, 0xa3, 0xaf, 0x6a,
-	0xdf, 0x21, 0x19, 0x3a, 0x13, 0x87, 0xf8, 0xb4, 0xdf, 0x55, 0xfb, 0xd6, 0x9a, 0xde, 0x72, 0xdf,
-	0xff, 0x30, 0xa0, 0x7c, 0xe8, 0x5a, 0xa1, 0x87, 0xae, 0x41, 0x8d, 0x5e, 0x38, 0xbe, 0xfd, 0xdc,
-	0x19, 0xc9, 0x4d, 0x57, 0xb9, 0xdc, 0x1f, 0x2d, 0xdd, 0xb2, 0xbe, 0x6c, 0x71, 0xf1, 0xb2, 0xa5,
-	0xd4, 0xb2, 0x68, 0x0b, 0xca, 0x2e, 0xb9, 0x24, 0x62, 0xa7, 0x65, 0x53, 0x08, 0xe8, 0x5d, 0xa8,
-	0x86, 0x53, 0x97, 0xb0, 0x1d, 0x54, 0x38, 0x54, 0x85, 0x89, 0xfd, 0x11, 0xba, 0x0e, 0x75, 0xd1,
-	0xe1, 0x8f, 0x83, 0x76, 0x95, 0x9f, 0x4c, 0x8d, 0x77, 0xf9, 0xe3, 0x00, 0xff, 0xc1, 0x80, 0xe6,
-	0xa3, 0x80, 0x3a, 0x63, 0x67, 0x68, 0x51, 0x27, 0xf0, 0xbf, 0x22, 0x4d, 0x94, 0x61, 0x4a, 0x69,
-	0xc3, 0x68, 0x3a, 0x96, 0xd3, 0x47, 0xfb, 0x19, 0x54, 0x9f, 0x91, 0xc1, 0x45, 0x10, 0xbc, 0x58,
-	0xb6, 0x23, 0x0d, 0x79, 0x65, 0x21, 0x72, 0x31, 0x8d, 0x7c, 0x07, 0x6a, 0xe7, 0x6c, 0xfa, 0x03,
-	0x32, 0x63, 0x27, 0x79, 0x69, 0xb9, 0x53, 0x22, 0x71, 0x85, 0x80, 0x10, 0x94, 0x28, 0xe3, 0xb7,
-	0xd0, 0x91, 0xff, 0x63, 0x0f, 0x36, 0xce, 0xa6, 0x83, 0xe3, 0xc0, 0x1f, 0x3b, 0xf6, 0xd1, 0xec,
-	0x01, 0x99, 0x99, 0x24, 0x62, 0xbc, 0x8a, 0xa9, 0xd9, 0xef, 0x4a, 0x10, 0xbd, 0x09, 0x7d, 0x17,
-	0x56, 0x27, 0x61, 0x30, 0x76, 0x5c, 0x22, 0xa6, 0x72, 0xcc, 0xc6, 0x41, 0x6b, 0x5f, 0x39, 0xf4,
-	0xbe, 0x68, 0x37, 0xd3, 0xc3, 0xf0, 0xbf, 0x0c, 0xa8, 0x88, 0xdf, 0xac, 0xd3, 0x19, 0xf3, 0x4e,
-	0xf7, 0x09, 0x34, 0x68, 0x68, 0xf9, 0xd1, 0x38, 0x08, 0x3d, 0x12, 0xca, 0x25, 0xde, 0x49, 0x96,
-	0x38, 0x4f, 0x3a, 0x4d, 0x7d, 0x24, 0xc2, 0xd0, 0x7c, 0x19, 0x3a, 0x94, 0xdc, 0xf3, 0xad, 0x81,
-	0x2b, 0x4f, 0xaa, 0x66, 0xa6, 0xda, 0xd0, 0x87, 0xb0, 0xf6, 0x52, 0x18, 0x42, 0x8d, 0x2a, 0xf1,
-	0x51, 0x99, 0x56, 0xee, 0x63, 0x53, 0x37, 0x86, 0x2a, 0xf3, 0x41, 0x7a, 0x13, 0xfe, 0x01, 0xb4,
-	0xd4, 0xf9, 0x71, 0x03, 0xb0, 0x13, 0xbc, 0x09, 0x95, 0xa1, 0x38, 0x18, 0x63, 0xc1, 0xc1, 0xc8,
-	0x7e, 0xfc, 0x17, 0x03, 0x1a, 0x9a, 0x22, 0x6c, 0xbd, 0x91, 0x45, 0xad, 0xfb, 0x8e, 0x4b, 0x49,
-	0x18, 0xb5, 0x8d, 0xbd, 0x22, 0x3b, 0x16, 0xad, 0x89, 0xdd, 0x24, 0x42, 0x24, 0xee, 0x48, 0xda,
-	0x32, 0x69, 0x60, 0xbd, 0xd4, 0xf1, 0x88, 0xe8, 0x15, 0x8c, 0x4d, 0x1a, 0xd0, 0x2e, 0x00, 0x17,
-	0x82, 0xd0, 0xb3, 0xa8, 0xbc, 0x30, 0xb4, 0x16, 0x76, 0x72, 0x4c, 0x7a, 0x18, 0x08, 0xaf, 0x91,
-	0x77, 0x46, 0xaa, 0x0d, 0xbf, 0x0f, 0x55, 0xae, 0x67, 0xbf, 0x9b, 0xcf, 0x33, 0xfc, 0x9e, 0x64,
-	0x62, 0xbf, 0x1b, 0xa1, 0x16, 0x14, 0x9d, 0x91, 0x52, 0x83, 0xfd, 0xe2, 0x1b, 0x50, 0x3f, 0x13,
-	0x9c, 0x58, 0x08, 0xf0, 0x3e, 0x54, 0x7b, 0x61, 0x30, 0x9d, 0x2c, 0x5b, 0x41, 0x0e, 0xc8, 0x5b,
-	0x61, 0x07, 0xca, 0xa7, 0xa1, 0xbd, 0x0c, 0xfd, 0xf4, 0xa5, 0xcf, 0x69, 0x9c, 0x3f, 0x60, 0x07,
-	0xca, 0xe7, 0xc1, 0x0b, 0xe2, 0x2f, 0xe8, 0xbe, 0x03, 0xcd, 0x27, 0x11, 0x09, 0xfb, 0x23, 0xe2,
-	0x53, 0x87, 0xce, 0xd0, 0x1a, 0xac, 0xc4, 0x1e, 0xbc, 0xe2, 0xf0, 0x6b, 0x8c, 0x78, 0x96, 0xe3,
-	0x4a, 0xdb, 0x08, 0x01, 0x77, 0xa1, 0xd6, 0x8f, 0xa2, 0x29, 0x31, 0xc9, 0xaf, 0xae, 0x36, 0x23,
-	0x76, 0x57, 0x66, 0xc4, 0x55, 0xe9, 0xae, 0x3e, 0x34, 0x0f, 0xa7, 0xf4, 0x22, 0x08, 0x9d, 0x5f,
-	0x73, 0xa4, 0x2d, 0x28, 0x53, 0xb6, 0x55, 0xb5, 0x43, 0x2e, 0xa0, 0x6d, 0xa8, 0x04, 0x83, 0x5f,
-	0x92, 0x21, 0x95, 0x80, 0x52, 0x62, 0x97, 0x47, 0x34, 0x15, 0x1d, 0x82, 0x19, 0x4a, 0x64, 0x33,
-	0xac, 0x21, 0xb7, 0xb8, 0xe0, 0x84, 0x94, 0xf0, 0x09, 0xac, 0x32, 0x5d, 0x0f, 0x87, 0x43, 0x12,
-	0x45, 0x8b, 0x17, 0x14, 0x0a, 0xad, 0xc4, 0x0a, 0x25, 0x70, 0xc5, 0x14, 0xdc, 0x01, 0xac, 0x71,
-	0x66, 0x24, 0x78, 0x2d, 0x28, 0xbe, 0x20, 0x33, 0x89, 0xc6, 0x7e, 0xb3, 0x58, 0xf8, 0x09, 0xac,
-	0xf3, 0x39, 0xf2, 0x21, 0x65, 0x93, 0x5e, 0x7f, 0x3f, 0x65, 0x5e, 0xc6, 0x95, 0xb9, 0x97, 0x11,
-	0x9b, 0xb0, 0xc5, 0x61, 0x39, 0x8f, 0xde, 0x08, 0xbb, 0x0d, 0x55, 0x5b, 0x90, 0x4f, 0xe2, 0x2a,
-	0x11, 0x77, 0xa1, 0xc4, 0x4e, 0xeb, 0x8a, 0xf6, 0xdd, 0x86, 0x4a, 0x44, 0x2d, 0x3a, 0x8d, 0xd4,
-	0x21, 0x09, 0x09, 0xff, 0xd6, 0x80, 0xe6, 0x99, 0x65, 0x93, 0x13, 0x42, 0x2d, 0xe6, 0xd7, 0xe2,
-	0xcc, 0xa9, 0xe5, 0x72, 0xc4, 0x92, 0x29, 0x04, 0x6e, 0xe4, 0xf1, 0x38, 0x22, 0xc2, 0xc8, 0x25,
-	0x53, 0x4a, 0xfc, 0x15, 0x75, 0x3c, 0x47, 0x98, 0xb8, 0x64, 0x0a, 0x21, 0xd9, 0x42, 0x49, 0xdf,
-	0xc2, 0x16, 0x94, 0x83, 0x70, 0x44, 0x42, 0xe9, 0xe7, 0x42, 0x60, 0x36, 0x19, 0x39, 0xa1, 0x7c,
-	0x6d, 0xd9, 0x2f, 0xfe, 0x08, 0x5a, 0x4c, 0xb1, 0xe8, 0x68, 0x76, 0x8f, 0xcd, 0xe3, 0x96, 0xdb,
-	0x86, 0x0a, 0x07, 0x51, 0xae, 0x27, 0x25, 0xfc, 0x0b, 0x41, 0x99, 0xe8, 0x68, 0xd6, 0xef, 0x2a,
-	0x13, 0xa7, 0x1d, 0x14, 0x7d, 0x0a, 0xcd, 0x89, 0xa6, 0xa0, 0xbc, 0xd9, 0xb7, 0x93, 0x3b, 0x52,
-	0x57, 0xdf, 0x4c, 0x8d, 0xc5, 0x2e, 0xd4, 0x38, 0x3c, 0xbb, 0x65, 0xbf, 0x01, 0xe5, 0x69, 0xa4,
-	0x6e, 0xc9, 0xc6, 0xc1, 0x5a, 0x02, 0xc0, 0x86, 0x98, 0xa2, 0xf3, 0x4b, 0xad, 0x76, 0x1b, 0x56,
-	0x0f, 0xa3, 0xc8, 0xb1, 0x7d, 0x33, 0x70, 0x73, 0x5d, 0x17, 0x41, 0x29, 0x0c, 0xdc, 0xf8, 0x4d,
-	0x65, 0xff, 0xf8, 0x06, 0xac, 0x9b, 0x84, 0x86, 0x0e, 0xb9, 0x24, 0x0b, 0xa6, 0xe1, 0x6f, 0x66,
-	0x87, 0x44, 0x31, 0x92, 0xa1, 0x21, 0xdd, 0x85, 0xe6, 0x69, 0xa8, 0x79, 0xcb, 0x3b, 0x50, 0x09,
-	0x42, 0x2d, 0x60, 0x28, 0x07, 0x21, 0x0b, 0x17, 0x62, 0xa7, 0x5c, 0xd1, 0x9c, 0x12, 0xf7, 0xa0,
-	0x21, 0x2e, 0x49, 0xff, 0xd2, 0xa1, 0x44, 0xa7, 0xad, 0x91, 0xa2, 0x2d, 0x7b, 0x14, 0x3c, 0xe2,
-	0x0d, 0x48, 0x68, 0x26, 0x9a, 0x68, 0x2d, 0xf8, 0x0b, 0x03, 0xae, 0x1d, 0xf3, 0x28, 0xa3, 0xcb,
-	0x5e, 0x09, 0x9f, 0xb2, 0xdb, 0x95, 0x83, 0x2e, 0xbe, 0x11, 0x38, 0xb3, 0xec, 0xd8, 0x45, 0x84,
-	0xc0, 0x9c, 0xcb, 0xe1, 0x13, 0xb9, 0xd6, 0x92, 0xf7, 0x7a, 0x13, 0xfa, 0x08, 0x5a, 0x13, 0xd7,
-	0xa2, 0xec, 0x31, 0x14, 0x4b, 0xc4, 0x71, 0xed, 0x5c, 0x3b, 0xfa, 0x3e, 0x34, 0xed, 0x44, 0xc1,
-	0xa8, 0x5d, 0xe6, 0x2c, 0xd0, 0x02, 0x04, 0x4d, 0x7d, 0x33, 0x35, 0x14, 0xff, 0x06, 0xb6, 0x0e,
-	0x87, 0xd4, 0xb9, 0xb4, 0x28, 0x49, 0x29, 0x93, 0xb7, 0xbc, 0xb1, 0x60, 0xf9, 0x6d, 0xa8, 0x30,
-	0x82, 0xc5, 0x3a, 0x4a, 0x89, 0xbd, 0xa1, 0x21, 0x19, 0x39, 0x21, 0x19, 0xd2, 0x33, 0x8b, 0x5e,
-	0x48, 0x2d, 0x53, 0x6d, 0xf8, 0x19, 0xac, 0xf3, 0xcd, 0x9d, 0xf0, 0x53, 0x8e, 0x2e, 0x9c, 0x89,
-	0x06, 0x67, 0xa4, 0xe0, 0x16, 0x5e, 0x37, 0x31, 0x63, 0x8a, 0x1a, 0x63, 0x3e, 0x53, 0xa6, 0xca,
-	0xc0, 0x73, 0xfa, 0xdc, 0x85, 0x86, 0x97, 0xb4, 0x48, 0xaf, 0xb9, 0x96, 0x39, 0xaf, 0x64, 0x8e,
-	0xa9, 0x8f, 0xc6, 0xe7, 0xf0, 0x61, 0x8f, 0xd0, 0x2c, 0x03, 0x8e, 0x66, 0x67, 0xa9, 0x73, 0x79,
-	0xc3, 0x43, 0xc4, 0x7f, 0x36, 0xa0, 0x1e, 0x83, 0xe5, 0x5d, 0x9c, 0x39, 0x2c, 0x6a, 0x43, 0x35,
-	0x08, 0xed, 0x47, 0x96, 0xa7, 0x54, 0x57, 0x62, 0x96, 0x5f, 0xa5, 0x79, 0x7e, 0x7d, 0x09, 0xce,
-	0x7c, 0x0f, 0xe0, 0xa9, 0x43, 0x5e, 0x9e, 0x86, 0xf6, 0x1b, 0xd2, 0x1e, 0x1f, 0x43, 0xf1, 0x34,
-	0xb4, 0xe7, 0xb4, 0x63, 0x7a, 0x88, 0x40, 0x44, 0x59, 0x56, 0x8a, 0xcc, 0xb2, 0x7e, 0xa2, 0x1e,
-	0xff, 0xc7, 0xdf, 0x82, 0x46, 0x8f, 0x50, 0xbe, 0x3d, 0xb6, 0xfe, 0x42, 0x77, 0xc6, 0x87, 0x50,
-	0xe6, 0xa3, 0xae, 0x78, 0x9a, 0x79, 0x6b, 0xfd, 0xae, 0x08, 0x9b, 0x0f, 0x9d, 0x88, 0xfe, 0xf4,
-	0xf1, 0xe9, 0x23, 0x99, 0x78, 0x73, 0x02, 0xdd, 0x82, 0xba, 0x48, 0x59, 0xd4, 0x9b, 0xdd, 0x38,
-	0x40, 0x5a, 0x3c, 0x2e, 0xd3, 0x0f, 0x53, 0xe4, 0x35, 0x2c, 0x11, 0x79, 0xb3, 0x47, 0x4a, 0x4f,
-	0xc4, 0x4a, 0x99, 0x44, 0x2c, 0x95, 0x3f, 0x97, 0x73, 0xf2, 0xe7, 0x38, 0x4d, 0xab, 0x64, 0xd2,
-	0x34, 0x04, 0xa5, 0x71, 0x18, 0x78, 0x3c, 0x45, 0x2c, 0x9a, 0xfc, 0x9f, 0x1d, 0x0d, 0x0d, 0xda,
-	0x35, 0xde, 0xb2, 0x42, 0x03, 0xb6, 0xcf, 0x31, 0x0f, 0xaf, 0xdb, 0x75, 0xe1, 0x7c, 0x42, 0x42,
-	0x37, 0xa0, 0x69, 0xd9, 0xf6, 0x73, 0xc7, 0xa7, 0x24, 0xbc, 0xb4, 0xdc, 0x36, 0x08, 0x46, 0x59,
-	0xb6, 0xdd, 0x97, 0x4d, 0x2c, 0x0d, 0x65, 0x43, 0x44, 0xa0, 0xd8, 0xe0, 0xea, 0xd4, 0x2c, 0xdb,
-	0x7e, 0xca, 0x53, 0xae, 0x6b, 0xc0, 0xfe, 0x9f, 0xf3, 0x38, 0xae, 0x29, 0xcc, 0x64, 0xd9, 0x36,
-	0xcf, 0x6e, 0x76, 0x00, 0x58, 0xd7, 0x98, 0xc5, 0xe5, 0x51, 0x7b, 0x95, 0xbf, 0x8e, 0x0c, 0x89,
-	0x07, 0xea, 0x91, 0x7a, 0x84, 0xd7, 0x92, 0x47, 0xf8, 0x67, 0x79, 0x36, 0x89, 0x16, 0x44, 0x07,
-	0xdf, 0x86, 0x9a, 0x27, 0x07, 0xb5, 0x57, 0x38, 0xc7, 0x37, 0x12, 0x43, 0xc9, 0xe9, 0x66, 0x3c,
-	0x04, 0xff, 0xa9, 0x04, 0x5b, 0x0c, 0xfc, 0x31, 0xf1, 0x4f, 0x1e, 0x7e, 0x1d, 0x2c, 0xce, 0x29,
-	0x5d, 0x4d, 0x28, 0x9d, 0xc4, 0xf2, 0xcc, 0xe8, 0x86, 0x4a, 0x89, 0x77, 0x01, 0x86, 0x81, 0x37,
-	0xb1, 0x42, 0x8b, 0x06, 0xca, 0xf6, 0x5a, 0x0b, 0x33, 0xd2, 0x20, 0x08, 0x5c, 0x69, 0x5d, 0xe0,
-	0xc9, 0x5f, 0x9d, 0xb5, 0x08, 0xf3, 0xde, 0x80, 0x66, 0x44, 0x43, 0x76, 0x3c, 0x89, 0xf9, 0xeb,
-	0x66, 0x43, 0xb4, 0x89, 0x21, 0x3b, 0x00, 0x2c, 0x92, 0x90, 0x03, 0x9a, 0x49, 0xba, 0xf6, 0x54,
-	0xe5, 0xe4, 0x9c, 0x9c, 0xab, 0x73, 0xe4, 0x5c, 0x8b, 0xc9, 0x99, 0x25, 0xe1, 0xfa, 0x6b, 0x48,
-	0xd8, 0x5a, 0x42, 0xc2, 0x8d, 0x65, 0x24, 0x44, 0x0b, 0x48, 0xb8, 0x99, 0x90, 0xf0, 0xe7, 0xb9,
-	0x3c, 0xf9, 0xff, 0xb0, 0xf0, 0xe0, 0x6f, 0x06, 0xac, 0x99, 0xc4, 0x1a, 0x91, 0x30, 0x7a, 0x4c,
-	0xc2, 0x4b, 0x67, 0x48, 0x90, 0x09, 0xad, 0x2c, 0xe9, 0xd1, 0x4e, 0x82, 0x91, 0x73, 0x49, 0x75,
-	0x96, 0x76, 0x47, 0xb8, 0x80, 0x9e, 0xc0, 0xc6, 0x9c, 0x0e, 0x68, 0x37, 0x3d, 0x2b, 0xeb, 0x08,
-	0x9d, 0xe5, 0xfd, 0x11, 0x2e, 0x1c, 0xfc, 0xa7, 0x06, 0xab, 0xdc, 0x21, 0xe2, 0xcd, 0xdf, 0x87,
-	0x8d, 0x1e, 0xa1, 0xe9, 0xfa, 0x0a, 0xca, 0x71, 0x9f, 0xce, 0x75, 0x2d, 0x18, 0xcd, 0x56, 0x63,
-	0x70, 0x01, 0x1d, 0x43, 0xab, 0x47, 0x68, 0xaa, 0xc8, 0x80, 0x36, 0x32, 0x30, 0xfd, 0x6e, 0xa7,
-	0x93, 0x2d, 0x32, 0x24, 0x05, 0x09, 0x5c, 0x40, 0x3d, 0x40, 0xc7, 0x96, 0x9f, 0x64, 0x73, 0x02,
-	0xe6, 0xdd, 0x74, 0xcc, 0x1c, 0x87, 0x9a, 0x9d, 0xed, 0x7d, 0x51, 0x91, 0xdd, 0x57, 0x15, 0xd9,
-	0xfd, 0x7b, 0xde, 0x84, 0xce, 0x70, 0x01, 0xf5, 0x61, 0x2b, 0x05, 0x24, 0xb3, 0xf9, 0xb7, 0x81,
-	0xca, 0xee, 0x49, 0xbc, 0x5b, 0x6f, 0xb5, 0xa7, 0xcd, 0x63, 0xcb, 0xd7, 0x72, 0x4b, 0x81, 0xd4,
-	0xce, 0x1c, 0xd2, 0x55, 0xa0, 0xee, 0xc3, 0xba, 0x82, 0x52, 0xb5, 0xdb, 0x6b, 0x19, 0x98, 0x24,
-	0x5d, 0x5c, 0x82, 0x73, 0xc6, 0x8f, 0x69, 0x2e, 0xc7, 0xd4, 0x89, 0x96, 0x97, 0x80, 0x2e, 0x41,
-	0xbc, 0x0d, 0x35, 0x51, 0x74, 0x18, 0xe7, 0xb3, 0x68, 0x9e, 0x12, 0xb8, 0x80, 0xee, 0x72, 0x0e,
-	0xca, 0x6a, 0xc9, 0x12, 0xf2, 0x6c, 0x64, 0x43, 0x20, 0x36, 0xf9, 0xc7, 0xb0, 0xa9, 0x4f, 0x56,
-	0x96, 0xde, 0xd4, 0xe8, 0xaa, 0x4a, 0x39, 0xf9, 0x00, 0x3f, 0xe1, 0xcc, 0x55, 0xb5, 0x9a, 0xa3,
-	0x19, 0x0b, 0x83, 0xb4, 0xcc, 0x4b, 0x4f, 0x6e, 0x3a, 0x68, 0x0e, 0x80, 0xd1, 0xf6, 0x10, 0xb6,
-	0x7a, 0x84, 0xaa, 0x7a, 0xd2, 0x6b, 0xf6, 0x80, 0xe6, 0xf4, 0x62, 0x10, 0xcf, 0x60, 0x3b, 0x3f,
-	0x26, 0x46, 0x1f, 0x68, 0x1e, 0xb3, 0x28, 0x6a, 0x5e, 0x62, 0x90, 0x3b, 0x50, 0x53, 0xda, 0x21,
-	0x3d, 0x84, 0x4c, 0xc2, 0xb4, 0xce, 0x7a, 0x46, 0x29, 0x5c, 0x40, 0x9f, 0xc2, 0x7a, 0x8f, 0xd0,
-	0x07, 0x64, 0x26, 0xad, 0xd1, 0xef, 0xe6, 0xd9, 0x23, 0xc7, 0xc0, 0xb8, 0x70, 0xf0, 0x7b, 0x43,
-	0x14, 0x9f, 0xe2, 0x2b, 0xe6, 0x47, 0xb0, 0xda, 0x23, 0x34, 0x49, 0xb8, 0xb3, 0xce, 0x13, 0xa7,
-	0xe1, 0x3a, 0xa0, 0x4a, 0xa0, 0x71, 0x01, 0x75, 0xb9, 0x81, 0x52, 0xc9, 0x3d, 0xea, 0xcc, 0x41,
-	0xc4, 0x59, 0x7f, 0x3e, 0xca, 0xc1, 0xdf, 0xcb, 0xd0, 0x38, 0x9c, 0xd2, 0x0b, 0xb5, 0xab, 0x7d,
-	0x28, 0xf3, 0x62, 0x97, 0x4e, 0x53, 0x55, 0xfd, 0xd2, 0x8f, 0x84, 0x97, 0xd9, 0x70, 0x01, 0x7d,
-	0x47, 0x63, 0x76, 0xb6, 0xbb, 0xb3, 0x9d, 0x5e, 0x52, 0xd5, 0xdd, 0x70, 0x01, 0xfd, 0x10, 0xea,
-	0x71, 0x35, 0x4c, 0xa7, 0x95, 0x5e, 0x22, 0x5b, 0x62, 0xbe, 0x4f, 0xb8, 0x21, 0x64, 0x2d, 0x50,
-	0x70, 0x73, 0x3d, 0xc5, 0xcd, 0x34, 0xab, 0xe5, 0x40, 0xee, 0x16, 0x90, 0x54, 0x05, 0xf4, 0x13,
-	0x4f, 0xd5, 0x0a, 0x96, 0xde, 0x31, 0x4d, 0x3d, 0xfd, 0xd7, 0x2f, 0x98, 0x4c, 0xe5, 0xa0, 0xb3,
-	0xb0, 0x2b, 0xc5, 0xec, 0x6c, 0x5a, 0x36, 0xcf, 0xec, 0x9c, 0xd4, 0x7d, 0xc9, 0x06, 0x4f, 0x60,
-	0x63, 0x2e, 0x3f, 0xd6, 0x6f, 0xae, 0xbc, 0xe4, 0x79, 0x09, 0x9c, 0x0f, 0x1f, 0x5c, 0x21, 0x77,
-	0x44, 0x1f, 0xa7, 0x7c, 0xe8, 0x0a, 0xa9, 0x66, 0x67, 0x33, 0x6d, 0x2f, 0xde, 0x8e, 0x0b, 0xe8,
-	0x63, 0xa8, 0xca, 0x54, 0x0d, 0x6d, 0x25, 0x23, 0x92, 0xec, 0xad, 0xb3, 0x9a, 0x9a, 0x87, 0x0b,
-	0x47, 0xad, 0x7f, 0xbe, 0xda, 0x35, 0xbe, 0x78, 0xb5, 0x6b, 0xfc, 0xfb, 0xd5, 0xae, 0xf1, 0xc7,
-	0xff, 0xee, 0x16, 0x06, 0x15, 0x3e, 0xe2, 0xf6, 0xff, 0x02, 0x00, 0x00, 0xff, 0xff, 0xf0, 0xa4,
-	0xe6, 0xb9, 0xb9, 0x1c, 0x00, 0x00,
+	// 2387 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xdc, 0x58, 0x4b, 0x93, 0x1b, 0x49,
+	0xf1, 0x57, 0x8f, 0xde, 0x29, 0xcd, 0xab, 0x66, 0x76, 0x56, 0xd6, 0xee, 0x8c, 0xc7, 0xb5, 0xff,
+	0xff, 0xe2, 0xd8, 0x80, 0xf1, 0xc6, 0xd8, 0x60, 0xb0, 0x79, 0xcd, 0x8c, 0x6c, 0x85, 0xb0, 0xc7,
+	0x33, 0xd1, 0x7e, 0x11, 0x10, 0x84, 0xa3, 0x47, 0x2a, 0xf5, 0x34, 0x6e, 0x75, 0x8b, 0xea, 0xd2,
+	0x78, 0xc5, 0x81, 0xcf, 0x40, 0xf0, 0x88, 0xe0, 0xc8, 0x89, 0xe0, 0x46, 0x70, 0xe0, 0xc4, 0x81,
+	0x2b, 0xc7, 0x3d, 0x73, 0x22, 0xcc, 0x07, 0x81, 0xa8, 0x57, 0x77, 0x75, 0xab, 0x25, 0x8f, 0xbd,
+	0xec, 0x85, 0x93, 0x94, 0x59, 0x55, 0x59, 0xf9, 0xf8, 0x65, 0x76, 0x65, 0xc2, 0xc6, 0xf8, 0xa5,
+	0x7b, 0x63, 0x4c, 0x43, 0x16, 0xde, 0x18, 0x0d, 0x3f, 0xdb, 0x13, 0xff, 0x50, 0x4d, 0xfc, 0x8c,
+	0x86, 0x9f, 0xb5, 0x3f, 0x70, 0xc3, 0xd0, 0xf5, 0x89, 0xdc, 0x71, 0x36, 0x19, 0xde, 0x20, 0xa3,
+	0x31, 0x9b, 0xca, 0x6d, 0xf8, 0x2f, 0x16, 0x54, 0x8f, 0x49, 0x14, 0x39, 0x2e, 0x41, 0x1f, 0x42,
+	0x7d, 0x3c, 0x39, 0xf3, 0xbd, 0xe8, 0x9c, 0xd0, 0x96, 0xb5, 0x6b, 0x5d, 0xaf, 0xdb, 0x09, 0x03,
+	0xb5, 0xa1, 0x16, 0x4d, 0xce, 0x58, 0x38, 0xf6, 0xfa, 0xad, 0x25, 0xb1, 0x18, 0xd3, 0xa8, 0x05,
+	0xd5, 0xb1, 0x33, 0xf5, 0x43, 0x67, 0xd0, 0x2a, 0xee, 0x5a, 0xd7, 0x9b, 0xb6, 0x26, 0xd1, 0x2e,
+	0x34, 0xfa, 0x61, 0xc0, 0x48, 0xc0, 0x9e, 0x4c, 0xc7, 0xa4, 0x55, 0x12, 0x07, 0x4d, 0x16, 0x97,
+	0x2b, 0x54, 0xe9, 0x87, 0x7e, 0xab, 0x2c, 0xe5, 0x6a, 0x9a, 0xcb, 0xed, 0x53, 0xe2, 0x30, 0x32,
+	0x68, 0x55, 0x76, 0xad, 0xeb, 0x45, 0x5b, 0x93, 0x42, 0xef, 0xa3, 0x70, 0x34, 0x72, 0x82, 0xc1,
+	0x97, 0xa5, 0x37, 0x25, 0x7d, 0x6f, 0xec, 0x91, 0x80, 0xf5, 0x3a, 0x5a, 0x6f, 0x83, 0xf5, 0x8e,
+	0x7a, 0xff, 0xc3, 0x82, 0xf2, 0x81, 0xef, 0xd0, 0x11, 0xba, 0x02, 0x35, 0x76, 0xee, 0x05, 0xee,
+	0x0b, 0x6f, 0xa0, 0x94, 0xae, 0x0a, 0xba, 0x37, 0x58, 0xa8, 0xb2, 0x79, 0x6d, 0x71, 0xfe, 0xb5,
+	0xa5, 0xd4, 0xb5, 0x68, 0x13, 0xca, 0x3e, 0xb9, 0x20, 0x52, 0xd3, 0xb2, 0x2d, 0x09, 0xf4, 0x3e,
+	0x54, 0xe9, 0xc4, 0x27, 0x2f, 0x3c, 0xa9, 0x66, 0xdd, 0xae, 0x70, 0xb2, 0x37, 0x40, 0x1f, 0x40,
+	0x5d, 0x2e, 0x04, 0xc3, 0xb0, 0x55, 0x15, 0x9e, 0xa9, 0x89, 0xa5, 0x60, 0x18, 0x8a, 0x5b, 0x7c,
+	0xe2, 0x50, 0x32, 0x68, 0xd5, 0x76, 0xad, 0xeb, 0x35, 0x5b, 0x93, 0xf8, 0xb7, 0x16, 0x34, 0x1f,
+	0x85, 0xcc, 0x1b, 0x7a, 0x7d, 0x87, 0x79, 0x61, 0xf0, 0x25, 0xd9, 0xa8, 0x43, 0x56, 0x4a, 0x87,
+	0xcc, 0xb0, 0xbe, 0x9c, 0x76, 0xfa, 0xaf, 0x2d, 0xa8, 0x3e, 0x27, 0x67, 0xe7, 0x61, 0xf8, 0x72,
+	0x91, 0x4a, 0x86, 0xe8, 0xa5, 0xb9, 0xa2, 0x8b, 0x69, 0xc7, 0x9a, 0x66, 0x94, 0x32, 0x66, 0x6c,
+	0x03, 0xbc, 0x92, 0xb7, 0xbe, 0xf0, 0xa4, 0x4e, 0x75, 0xbb, 0xae, 0x38, 0xbd, 0x01, 0xbe, 0x05,
+	0xb5, 0x27, 0xfc, 0xe6, 0x07, 0x64, 0xca, 0xe3, 0x73, 0xe1, 0xf8, 0x13, 0xa2, 0x54, 0x92, 0x04,
+	0x42, 0x50, 0x62, 0x3c, 0x6b, 0xa4, 0x7f, 0xc4, 0x7f, 0x3c, 0x82, 0xf5, 0xd3, 0xc9, 0xd9, 0x51,
+	0x18, 0x0c, 0x3d, 0xf7, 0x70, 0xfa, 0x80, 0x4c, 0x6d, 0x12, 0x71, 0xb4, 0xc6, 0x80, 0xef, 0x75,
+	0x94, 0x10, 0x93, 0x85, 0xbe, 0x01, 0xcb, 0x63, 0x1a, 0x0e, 0x3d, 0x9f, 0xc8, 0xa3, 0x42, 0x66,
+	0x63, 0x7f, 0x6d, 0x4f, 0x97, 0x89, 0x3d, 0xc9, 0xb7, 0xd3, 0xdb, 0xf0, 0xbf, 0x2d, 0xa8, 0xc8,
+	0xbf, 0xd9, 0x54, 0xb6, 0x66, 0x53, 0xf9, 0x36, 0x34, 0x18, 0x75, 0x82, 0x68, 0x18, 0xd2, 0x11,
+	0xa1, 0xea, 0x8a, 0xf7, 0x92, 0x2b, 0x9e, 0x24, 0x8b, 0xb6, 0xb9, 0x13, 0x61, 0x68, 0xbe, 0xa2,
+	0x1e, 0x23, 0xf7, 0x02, 0xe7, 0xcc, 0x57, 0x4e, 0xae, 0xd9, 0x29, 0x1e, 0xfa, 0x18, 0x56, 0x94,
+	0xef, 0xf4, 0xae, 0x92, 0xd8, 0x95, 0xe1, 0x8a, 0xcc, 0x9d, 0xf8, 0xb1, 0xa8, 0xb2, 0xd8, 0x64,
+	0xb2, 0xd0, 0x57, 0x61, 0x3d, 0xbe, 0xfc, 0x71, 0x9f, 0x7a, 0x63, 0x9e, 0xe1, 0x32, 0x01, 0x66,
+	0x17, 0xf0, 0xb7, 0x61, 0x4d, 0x7b, 0x5b, 0x84, 0x8b, 0xfb, 0xfb, 0x3a, 0x54, 0xfa, 0xd2, 0x8d,
+	0xd6, 0x1c, 0x37, 0xaa, 0x75, 0xfc, 0x27, 0x0b, 0x1a, 0x86, 0xd9, 0x5c, 0xbb, 0x81, 0xc3, 0x9c,
+	0xfb, 0x9e, 0xcf, 0x08, 0x8d, 0x5a, 0xd6, 0x6e, 0x91, 0x3b, 0xd1, 0x60, 0xf1, 0x6a, 0x26, 0x49,
+	0xe2, 0x0f, 0x54, 0xe4, 0x13, 0x06, 0x5f, 0x65, 0xde, 0x88, 0xc8, 0x55, 0x99, 0x1b, 0x09, 0x03,
+	0xed, 0x00, 0x08, 0x22, 0xa4, 0x23, 0x87, 0x29, 0x3c, 0x1a, 0x1c, 0xee, 0x67, 0x4e, 0x3d, 0x0c,
+	0x65, 0x7e, 0x2a, 0x4c, 0xa6, 0x78, 0xf8, 0x2a, 0x54, 0x85, 0x9d, 0xbd, 0x4e, 0x3e, 0x2a, 0xf1,
+	0x87, 0x0a, 0xb7, 0xbd, 0x4e, 0x84, 0xd6, 0xa0, 0xe8, 0x0d, 0xb4, 0x19, 0xfc, 0x2f, 0xbe, 0x06,
+	0xf5, 0x53, 0x89, 0xa0, 0xb9, 0x02, 0xae, 0x42, 0xb5, 0x4b, 0xc3, 0xc9, 0x78, 0xd1, 0x0d, 0x6a,
+	0x43, 0xde, 0x0d, 0xdb, 0x50, 0x3e, 0xa1, 0xee, 0x22, 0xe9, 0x27, 0xaf, 0x02, 0x42, 0xe7, 0x6e,
+	0xd8, 0x86, 0xf2, 0x93, 0xf0, 0x25, 0x09, 0xe6, 0x2c, 0xdf, 0x82, 0xe6, 0xd3, 0x88, 0xd0, 0xde,
+	0x80, 0x04, 0xcc, 0x63, 0x53, 0xb4, 0x02, 0x4b, 0x71, 0xa9, 0x58, 0xf2, 0x44, 0x29, 0x25, 0x23,
+	0xc7, 0xf3, 0x55, 0x6c, 0x24, 0x81, 0x3b, 0x50, 0xeb, 0x45, 0xd1, 0x84, 0xd8, 0xe4, 0x67, 0x97,
+	0x3b, 0x11, 0x27, 0x37, 0x0f, 0xe2, 0xb2, 0x4a, 0xee, 0x00, 0x9a, 0x07, 0x13, 0x76, 0x1e, 0x52,
+	0xef, 0xe7, 0x42, 0xd2, 0x26, 0x94, 0x19, 0x57, 0x55, 0x6b, 0x28, 0x08, 0xb4, 0x05, 0x95, 0xf0,
+	0xec, 0xa7, 0xa4, 0xcf, 0x94, 0x40, 0x45, 0xf1, 0x2a, 0x15, 0x4d, 0xe4, 0x82, 0x44, 0x86, 0x26,
+	0xf9, 0x09, 0xa7, 0x2f, 0x22, 0x2e, 0x31, 0xa1, 0x28, 0x7c, 0x0c, 0xcb, 0xdc, 0xd6, 0x83, 0x7e,
+	0x9f, 0x44, 0xd1, 0xfc, 0x0b, 0xa5, 0x41, 0x4b, 0xb1, 0x41, 0x89, 0xb8, 0x62, 0x4a, 0xdc, 0x3e,
+	0xac, 0x08, 0x64, 0x24, 0xf2, 0xd6, 0xa0, 0xf8, 0x92, 0x4c, 0x95, 0x34, 0xfe, 0x37, 0x2b, 0x0b,
+	0x3f, 0x85, 0x55, 0x71, 0x46, 0x7d, 0xcc, 0xf9, 0xa1, 0x37, 0x57, 0xb3, 0xcc, 0xd7, 0x79, 0x69,
+	0xe6, 0xeb, 0x8c, 0x6d, 0xd8, 0x14, 0x62, 0x05, 0x8e, 0xde, 0x4a, 0x76, 0x0b, 0xaa, 0xae, 0x04,
+	0x9f, 0x92, 0xab, 0x49, 0xdc, 0x81, 0x12, 0xf7, 0xd6, 0x25, 0xe3, 0xbb, 0x05, 0x95, 0x88, 0x39,
+	0x6c, 0x12, 0x69, 0x27, 0x49, 0x0a, 0xff, 0xd2, 0x82, 0xe6, 0xa9, 0xe3, 0x92, 0x63, 0xc2, 0x1c,
+	0x9e, 0xd7, 0xd2, 0xe7, 0xcc, 0xf1, 0x85, 0xc4, 0x92, 0x2d, 0x09, 0x11, 0xe4, 0xe1, 0x30, 0x22,
+	0x32, 0xc8, 0x25, 0x5b, 0x51, 0xe2, 0x4b, 0xee, 0x8d, 0x3c, 0x19, 0xe2, 0x92, 0x2d, 0x89, 0x44,
+	0x85, 0x92, 0xa9, 0xc2, 0x26, 0x94, 0x43, 0x3a, 0x20, 0x54, 0xe5, 0xb9, 0x24, 0x78, 0x4c, 0x06,
+	0x1e, 0x55, 0x05, 0x8f, 0xff, 0xc5, 0x9f, 0xc0, 0x1a, 0x37, 0x2c, 0x3a, 0x9c, 0xde, 0xe3, 0xe7,
+	0x44, 0xe4, 0xb6, 0xa0, 0x22, 0x84, 0xe8, 0xd4, 0x53, 0x14, 0xfe, 0x89, 0x84, 0x4c, 0x74, 0x38,
+	0xed, 0x75, 0x74, 0x88, 0xd3, 0x09, 0x8a, 0xee, 0x40, 0x73, 0x6c, 0x18, 0xa8, 0xbe, 0x03, 0x5b,
+	0x49, 0x8d, 0x34, 0xcd, 0xb7, 0x53, 0x7b, 0xb1, 0x0f, 0x35, 0x21, 0x9e, 0x57, 0xd9, 0xff, 0x83,
+	0xf2, 0x24, 0xd2, 0x55, 0xb2, 0xb1, 0xbf, 0x92, 0x08, 0xe0, 0x5b, 0x6c, 0xb9, 0xf8, 0x85, 0x6e,
+	0xbb, 0x09, 0xcb, 0x07, 0x51, 0xe4, 0xb9, 0x81, 0x1d, 0xfa, 0xb9, 0xa9, 0x8b, 0xa0, 0x44, 0x43,
+	0x3f, 0xfe, 0x02, 0xf3, 0xff, 0xf8, 0x1a, 0xac, 0xda, 0x84, 0x51, 0x8f, 0x5c, 0x90, 0x39, 0xc7,
+	0xf0, 0xff, 0x67, 0xb7, 0x44, 0xb1, 0x24, 0xcb, 0x90, 0x74, 0x17, 0x9a, 0x27, 0xd4, 0xc8, 0x96,
+	0xf7, 0xa0, 0x12, 0x52, 0xe3, 0x65, 0x52, 0x0e, 0x29, 0x7f, 0x97, 0xc4, 0x49, 0xb9, 0x64, 0x24,
+	0x25, 0xee, 0x42, 0x43, 0x16, 0xc9, 0xe0, 0xc2, 0x63, 0xc4, 0x84, 0xad, 0x95, 0x82, 0x2d, 0xff,
+	0x28, 0x8c, 0xc8, 0xe8, 0x8c, 0x50, 0x3b, 0xb1, 0xc4, 0xe0, 0xe0, 0xcf, 0x2d, 0xb8, 0x72, 0x24,
+	0x9e, 0x33, 0x1d, 0xfe, 0x95, 0x08, 0x18, 0xaf, 0xae, 0x42, 0xe8, 0xfc, 0x8a, 0x20, 0x90, 0xe5,
+	0xc6, 0x29, 0x22, 0x09, 0x9e, 0x5c, 0x9e, 0x38, 0x28, 0xac, 0x56, 0xb8, 0x37, 0x59, 0xe8, 0x13,
+	0x58, 0x1b, 0xfb, 0x0e, 0xe3, 0x1f, 0x43, 0x79, 0x45, 0xfc, 0xb6, 0x9e, 0xe1, 0xa3, 0x6f, 0x41,
+	0xd3, 0x4d, 0x0c, 0x8c, 0x5a, 0xe5, 0xdd, 0x62, 0xfa, 0x39, 0x61, 0x98, 0x6f, 0xa7, 0xb6, 0xe2,
+	0x5f, 0xc0, 0xe6, 0x41, 0x9f, 0x79, 0x17, 0x0e, 0x23, 0x29, 0x63, 0xf2, 0xae, 0xb7, 0xe6, 0x5c,
+	0xbf, 0x05, 0x15, 0x0e, 0xb0, 0xd8, 0x46, 0x45, 0xf1, 0x6f, 0x28, 0x25, 0x03, 0x8f, 0x92, 0x3e,
+	0x3b, 0x75, 0xd8, 0xb9, 0xb2, 0x32, 0xc5, 0xc3, 0xcf, 0x61, 0x55, 0x28, 0x77, 0x2c, 0xbc, 0x1c,
+	0x9d, 0x7b, 0x63, 0x43, 0x9c, 0x95, 0x12, 0x37, 0xb7, 0xdc, 0xc4, 0x88, 0x29, 0x1a, 0x88, 0xf9,
+	0xa1, 0x0e, 0x55, 0x46, 0xbc, 0x80, 0xcf, 0x5d, 0x68, 0x8c, 0x12, 0x8e, 0xca, 0x9a, 0x2b, 0x19,
+	0x7f, 0x25, 0x67, 0x6c, 0x73, 0x37, 0x7e, 0x02, 0x1f, 0x77, 0x09, 0xcb, 0x22, 0xe0, 0x70, 0x7a,
+	0x9a, 0xf2, 0xcb, 0x5b, 0x3a, 0x11, 0xff, 0xd1, 0x82, 0x7a, 0x2c, 0x2c, 0xaf, 0x70, 0xe6, 0xa0,
+	0xa8, 0x05, 0xd5, 0x90, 0xba, 0x8f, 0x9c, 0x91, 0x36, 0x5d, 0x93, 0x59, 0x7c, 0x95, 0x66, 0xf1,
+	0xf5, 0x05, 0x30, 0xf3, 0x4d, 0x80, 0x67, 0x1e, 0x79, 0x75, 0x42, 0xdd, 0xb7, 0x84, 0x3d, 0x3e,
+	0x82, 0xe2, 0x09, 0x75, 0x67, 0xac, 0xe3, 0x76, 0xc8, 0x87, 0x88, 0x8e, 0xac, 0x22, 0x79, 0x64,
+	0x83, 0xc4, 0x3c, 0xf1, 0x1f, 0x7f, 0x05, 0x1a, 0x5d, 0xc2, 0x84, 0x7a, 0xfc, 0xfe, 0xb9, 0xe9,
+	0x8c, 0x0f, 0xa0, 0x2c, 0x76, 0x5d, 0xd2, 0x9b, 0x79, 0x77, 0xfd, 0xaa, 0x08, 0x1b, 0x0f, 0xbd,
+	0x88, 0xfd, 0xe0, 0xf1, 0xc9, 0x23, 0xd5, 0xfc, 0x0b, 0x00, 0xdd, 0x80, 0xba, 0xec, 0x8d, 0xf4,
+	0x37, 0xbb, 0xb1, 0x8f, 0x8c, 0xd7, 0xbb, 0x6a, 0x56, 0xec, 0x1a, 0xd3, 0x6d, 0xcb, 0xdb, 0x7d,
+	0xa4, 0x16, 0xf5, 0x4a, 0xa9, 0x1e, 0xbe, 0x9c, 0xd3, 0xc3, 0xc7, 0x0d, 0x61, 0x25, 0xd3, 0x10,
+	0x22, 0x28, 0x0d, 0x69, 0x38, 0x12, 0x6d, 0x6a, 0xd1, 0x16, 0xff, 0xb9, 0x6b, 0x58, 0x28, 0xba,
+	0xd3, 0xa2, 0xbd, 0xc4, 0x42, 0xae, 0xe7, 0x50, 0x3c, 0xaf, 0x5b, 0x75, 0x99, 0x7c, 0x92, 0x42,
+	0xd7, 0xa0, 0xe9, 0xb8, 0xee, 0x0b, 0x2f, 0x60, 0x84, 0x5e, 0x38, 0x7e, 0x0b, 0x24, 0xa2, 0x1c,
+	0xd7, 0xed, 0x29, 0x16, 0x6f, 0x85, 0xf9, 0x16, 0xf9, 0x50, 0x6c, 0x08, 0x73, 0x6a, 0x8e, 0xeb,
+	0x3e, 0xe3, 0x34, 0x6f, 0x26, 0xf9, 0xa2, 0x78, 0xc7, 0x35, 0x65, 0x98, 0x1c, 0xd7, 0x15, 0xbd,
+	0xd0, 0x36, 0x00, 0x5f, 0x1a, 0xf2, 0x77, 0x79, 0xd4, 0x5a, 0x16, 0x5f, 0x47, 0x2e, 0x49, 0x3c,
+	0xd4, 0x23, 0xfd, 0x11, 0x5e, 0x49, 0x3e, 0xc2, 0x3f, 0xca, 0x8b, 0x49, 0x34, 0xe7, 0x75, 0xf0,
+	0x35, 0xa8, 0x8d, 0xd4, 0xa6, 0xd6, 0x92, 0xc0, 0xf8, 0x7a, 0x12, 0x28, 0x75, 0xdc, 0x8e, 0xb7,
+	0xe0, 0x3f, 0x94, 0x60, 0x93, 0x0b, 0x7f, 0x4c, 0x82, 0xe3, 0x87, 0xff, 0x0b, 0x11, 0x17, 0x90,
+	0xae, 0x26, 0x90, 0x4e, 0xde, 0xf2, 0x3c, 0xe8, 0x96, 0x6e, 0xa0, 0x77, 0x00, 0xfa, 0xe1, 0x68,
+	0xec, 0x50, 0x87, 0x85, 0x3a, 0xf6, 0x06, 0x87, 0x07, 0xe9, 0x2c, 0x0c, 0x7d, 0x15, 0x5d, 0x10,
+	0xad, 0x62, 0x9d, 0x73, 0x64, 0x78, 0xaf, 0x41, 0x33, 0x62, 0x94, 0xbb, 0x27, 0x09, 0x7f, 0xdd,
+	0x6e, 0x48, 0x9e, 0xdc, 0xb2, 0x0d, 0xc0, 0x5f, 0x12, 0x6a, 0x43, 0x33, 0x69, 0xd7, 0x9e, 0xe9,
+	0x0e, 0x5e, 0x80, 0x73, 0x79, 0x06, 0x9c, 0x2b, 0x31, 0x38, 0xb3, 0x20, 0x5c, 0x7d, 0x03, 0x08,
+	0xd7, 0x16, 0x80, 0x70, 0x7d, 0x11, 0x08, 0xd1, 0x1c, 0x10, 0x6e, 0x24, 0x20, 0xfc, 0x71, 0x2e,
+	0x4e, 0xfe, 0x4b, 0x28, 0xbc, 0x03, 0xf5, 0xc7, 0xe7, 0xce, 0x20, 0x7c, 0xc5, 0x91, 0xb7, 0x60,
+	0x0e, 0xa3, 0xe3, 0xbb, 0x64, 0x94, 0xac, 0x3f, 0x5b, 0xc9, 0xe1, 0x88, 0x57, 0xc7, 0x01, 0x89,
+	0x3c, 0x4a, 0xe4, 0xd9, 0xa6, 0xad, 0x49, 0x8e, 0x1b, 0x4a, 0xc6, 0x21, 0xe5, 0xa3, 0x9a, 0x25,
+	0x35, 0xb8, 0x52, 0x34, 0x37, 0x62, 0x40, 0x7c, 0xe6, 0xa8, 0x59, 0x9f, 0x24, 0xb8, 0xac, 0x0b,
+	0x42, 0x23, 0xdd, 0x1c, 0x95, 0x6c, 0x4d, 0xa2, 0xab, 0xd0, 0xd0, 0x67, 0x5f, 0x38, 0x4c, 0x0d,
+	0x95, 0x40, 0xb3, 0x0e, 0x18, 0x77, 0xef, 0x64, 0x3c, 0x70, 0xd4, 0xba, 0x9c, 0xf4, 0xd5, 0x15,
+	0xe7, 0x80, 0xed, 0xff, 0xd5, 0x82, 0x15, 0x9b, 0x38, 0x03, 0x42, 0xa3, 0xc7, 0x84, 0x5e, 0x78,
+	0x7d, 0x82, 0x6c, 0x58, 0xcb, 0x26, 0x39, 0xda, 0x4e, 0x7c, 0x96, 0x53, 0x94, 0xdb, 0x0b, 0x97,
+	0x23, 0x5c, 0x40, 0x4f, 0x61, 0x7d, 0x26, 0x66, 0x68, 0x27, 0x7d, 0x2a, 0x9b, 0xf8, 0xed, 0xc5,
+	0xeb, 0x11, 0x2e, 0xec, 0xff, 0xbe, 0x0e, 0xcb, 0xa2, 0x00, 0xc4, 0xca, 0xdf, 0x87, 0xf5, 0x2e,
+	0x61, 0xe9, 0xe9, 0x13, 0xca, 0x29, 0x17, 0xed, 0x0f, 0x8c, 0xc7, 0x77, 0x76, 0x56, 0x85, 0x0b,
+	0xe8, 0x08, 0xd6, 0xba, 0x84, 0xa5, 0x86, 0x2a, 0x68, 0x3d, 0x23, 0xa6, 0xd7, 0x69, 0xb7, 0xb3,
+	0x43, 0x95, 0x64, 0x00, 0x83, 0x0b, 0xa8, 0x0b, 0xe8, 0xc8, 0x09, 0x92, 0xee, 0x55, 0x8a, 0x79,
+	0x3f, 0xdd, 0x23, 0xc4, 0x4f, 0xeb, 0xf6, 0xd6, 0x9e, 0x9c, 0x82, 0xef, 0xe9, 0x29, 0xf8, 0xde,
+	0x3d, 0x3e, 0x05, 0xc7, 0x05, 0xd4, 0x83, 0xcd, 0x94, 0x20, 0x35, 0xbd, 0x78, 0x17, 0x51, 0x59,
+	0x9d, 0xe4, 0x77, 0xfa, 0x9d, 0x74, 0xda, 0x38, 0x72, 0x02, 0xa3, 0x97, 0x96, 0x92, 0x5a, 0x19,
+	0x27, 0x5d, 0x46, 0xd4, 0x7d, 0x58, 0xd5, 0xa2, 0xf4, 0xbc, 0xfc, 0x4a, 0x46, 0x4c, 0xd2, 0x1e,
+	0x2f, 0x90, 0x73, 0x2a, 0xdc, 0x34, 0xd3, 0x53, 0x9b, 0x40, 0xcb, 0x6b, 0xb8, 0x17, 0x48, 0xbc,
+	0x09, 0x35, 0x39, 0x64, 0x19, 0xe6, 0xa3, 0x68, 0x16, 0x12, 0xb8, 0x80, 0xee, 0x0a, 0x0c, 0xaa,
+	0xe9, 0xd0, 0x02, 0xf0, 0xac, 0x67, 0x9f, 0x7c, 0xfc, 0xf0, 0xf7, 0x60, 0xc3, 0x3c, 0xac, 0x23,
+	0xbd, 0x61, 0xc0, 0x55, 0x8f, 0xae, 0xf2, 0x05, 0x7c, 0x5f, 0x20, 0x57, 0xd1, 0xd1, 0xe1, 0x94,
+	0x3f, 0xfb, 0x8c, 0x4e, 0xd3, 0x6c, 0xe6, 0xda, 0x68, 0x46, 0x00, 0x87, 0xed, 0x01, 0x6c, 0x76,
+	0x09, 0x53, 0x5a, 0x46, 0x6f, 0xd0, 0x01, 0xcd, 0xd8, 0xc5, 0x45, 0x7c, 0x07, 0x50, 0x4a, 0x84,
+	0xc4, 0xc6, 0xac, 0xbe, 0x73, 0x8e, 0x3f, 0x87, 0xad, 0xfc, 0x16, 0x02, 0x7d, 0x64, 0x24, 0xdc,
+	0xbc, 0x26, 0x63, 0x41, 0x3c, 0x6f, 0x41, 0x4d, 0x3b, 0x07, 0x99, 0x2f, 0xee, 0xe4, 0x55, 0xdb,
+	0x5e, 0xcd, 0x28, 0x89, 0x0b, 0xe8, 0x0e, 0xac, 0x76, 0x09, 0x7b, 0x40, 0xa6, 0x2a, 0x98, 0xbd,
+	0x4e, 0x5e, 0x38, 0x73, 0xf0, 0x81, 0x0b, 0xfb, 0xbf, 0xb1, 0xe4, 0xac, 0x2e, 0xae, 0x50, 0xdf,
+	0x85, 0xe5, 0x2e, 0x61, 0xc9, 0x7c, 0x22, 0x9b, 0x7b, 0xf1, 0xd4, 0xa2, 0x8d, 0x32, 0x0b, 0xb2,
+	0xa8, 0x74, 0x44, 0x7c, 0x53, 0xb3, 0x10, 0xd4, 0x9e, 0x11, 0x11, 0x0f, 0x49, 0xf2, 0xa5, 0xec,
+	0xff, 0xad, 0x0c, 0x0d, 0x3e, 0xc6, 0xd3, 0x5a, 0xed, 0x41, 0x59, 0xcc, 0x06, 0x4d, 0x94, 0xeb,
+	0x61, 0xa1, 0xe9, 0x12, 0x31, 0x95, 0xc4, 0x05, 0xf4, 0x75, 0x23, 0x31, 0xb2, 0xcb, 0xed, 0xad,
+	0xf4, 0x95, 0x7a, 0x4c, 0x29, 0x70, 0x51, 0x8f, 0x87, 0x87, 0x26, 0x2a, 0xcd, 0x89, 0xe2, 0x82,
+	0xf0, 0xdd, 0x16, 0x81, 0x50, 0xa3, 0x53, 0x09, 0xed, 0xd5, 0x14, 0xb4, 0xd3, 0x49, 0xa1, 0x36,
+	0x8a, 0xac, 0x82, 0x64, 0x88, 0x62, 0x7a, 0x3c, 0x35, 0x5a, 0x59, 0x58, 0xa2, 0x9a, 0xe6, 0xb4,
+	0xc4, 0xac, 0x4f, 0x99, 0x41, 0x4b, 0x7b, 0xee, 0x52, 0x0a, 0xd9, 0xd9, 0x2e, 0x76, 0x16, 0xd9,
+	0x39, 0x93, 0x8e, 0x05, 0x0a, 0x1e, 0xc3, 0xfa, 0xcc, 0x38, 0xc1, 0x2c, 0x7c, 0x79, 0xb3, 0x86,
+	0x05, 0xe2, 0x02, 0xf8, 0xe8, 0x12, 0xad, 0x36, 0xfa, 0x34, 0x95, 0x43, 0x97, 0xe8, 0xcc, 0xdb,
+	0x1b, 0xe9, 0x78, 0x09, 0x3e, 0x2e, 0xa0, 0x4f, 0xa1, 0xaa, 0x3a, 0x5b, 0xb4, 0x99, 0xec, 0x48,
+	0x9a, 0xdd, 0xf6, 0x72, 0xea, 0x1c, 0x2e, 0xec, 0xf7, 0x60, 0x45, 0x3e, 0xb6, 0xe2, 0xcc, 0xba,
+	0x0d, 0xf5, 0x2e, 0x61, 0x92, 0x69, 0x16, 0xab, 0xf8, 0x41, 0xd7, 0xce, 0x61, 0x46, 0xb8, 0x70,
+	0xb8, 0xf6, 0xf7, 0xd7, 0x3b, 0xd6, 0xe7, 0xaf, 0x77, 0xac, 0x7f, 0xbe, 0xde, 0xb1, 0x7e, 0xf7,
+	0xaf, 0x9d, 0xc2, 0x59, 0x45, 0xec, 0xbb, 0xf9, 0x9f, 0x01, 0x00, 0x8e, 0xbf, 0x1d, 0x7d, 0xb7,
+	0x1e, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -3506,6 +3693,7 @@ type ThingsServiceClient interface {
 	GetGroupIDByProfile(ctx context.Context, in *ProfileID, opts ...grpc.CallOption) (*GroupID, error)
 	GetGroupIDsByOrg(ctx context.Context, in *OrgAccessReq, opts ...grpc.CallOption) (*GroupIDs, error)
 	GetThingIDsByProfile(ctx context.Context, in *ProfileID, opts ...grpc.CallOption) (*ThingIDs, error)
+	GetThingIDsByGroup(ctx context.Context, in *GroupID, opts ...grpc.CallOption) (*ThingIDs, error)
 	CreateGroupMemberships(ctx context.Context, in *CreateGroupMembershipsReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	GetGroup(ctx context.Context, in *GetGroupReq, opts ...grpc.CallOption) (*Group, error)
 	GetKeyByThingID(ctx context.Context, in *ThingID, opts ...grpc.CallOption) (*ThingKey, error)
@@ -3636,6 +3824,15 @@ func (c *thingsServiceClient) GetThingIDsByProfile(ctx context.Context, in *Prof
 	return out, nil
 }
 
+func (c *thingsServiceClient) GetThingIDsByGroup(ctx context.Context, in *GroupID, opts ...grpc.CallOption) (*ThingIDs, error) {
+	out := new(ThingIDs)
+	err := c.cc.Invoke(ctx, "/protomfx.ThingsService/GetThingIDsByGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *thingsServiceClient) CreateGroupMemberships(ctx context.Context, in *CreateGroupMembershipsReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	out := new(emptypb.Empty)
 	err := c.cc.Invoke(ctx, "/protomfx.ThingsService/CreateGroupMemberships", in, out, opts...)
@@ -3678,6 +3875,7 @@ type ThingsServiceServer interface {
 	GetGroupIDByProfile(context.Context, *ProfileID) (*GroupID, error)
 	GetGroupIDsByOrg(context.Context, *OrgAccessReq) (*GroupIDs, error)
 	GetThingIDsByProfile(context.Context, *ProfileID) (*ThingIDs, error)
+	GetThingIDsByGroup(context.Context, *GroupID) (*ThingIDs, error)
 	CreateGroupMemberships(context.Context, *CreateGroupMembershipsReq) (*emptypb.Empty, error)
 	GetGroup(context.Context, *GetGroupReq) (*Group, error)
 	GetKeyByThingID(context.Context, *ThingID) (*ThingKey, error)
@@ -3726,6 +3924,9 @@ func (*UnimplementedThingsServiceServer) GetGroupIDsByOrg(ctx context.Context, r
 func (*UnimplementedThingsServiceServer) GetThingIDsByProfile(ctx context.Context, req *ProfileID) (*ThingIDs, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetThingIDsByProfile not implemented")
 }
+func (*UnimplementedThingsServiceServer) GetThingIDsByGroup(ctx context.Context, req *GroupID) (*ThingIDs, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetThingIDsByGroup not implemented")
+}
 func (*UnimplementedThingsServiceServer) CreateGroupMemberships(ctx context.Context, req *CreateGroupMembershipsReq) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateGroupMemberships not implemented")
 }
@@ -3974,6 +4175,24 @@ func _ThingsService_GetThingIDsByProfile_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ThingsService_GetThingIDsByGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ThingsServiceServer).GetThingIDsByGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protomfx.ThingsService/GetThingIDsByGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ThingsServiceServer).GetThingIDsByGroup(ctx, req.(*GroupID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ThingsService_CreateGroupMemberships_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CreateGroupMembershipsReq)
 	if err := dec(in); err != nil {
@@ -4084,6 +4303,10 @@ var _ThingsService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetThingIDsByProfile",
 			Handler:    _ThingsService_GetThingIDsByProfile_Handler,
 		},
+		{
+			MethodName: "GetThingIDsByGroup",
+			Handler:    _ThingsService_GetThingIDsByGroup_Handler,
+		},
 		{
 			MethodName: "CreateGroupMemberships",
 			Handler:    _ThingsService_CreateGroupMemberships_Handler,
@@ -4605,6 +4828,78 @@ var _AuthService_serviceDesc = grpc.ServiceDesc{
 	Metadata: "pkg/proto/mfx.proto",
 }
 
+// ShadowsServiceClient is the client API for ShadowsService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ShadowsServiceClient interface {
+	GetShadow(ctx context.Context, in *ShadowReq, opts ...grpc.CallOption) (*ShadowRes, error)
+}
+
+type shadowsServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewShadowsServiceClient(cc *grpc.ClientConn) ShadowsServiceClient {
+	return &shadowsServiceClient{cc}
+}
+
+func (c *shadowsServiceClient) GetShadow(ctx context.Context, in *ShadowReq, opts ...grpc.CallOption) (*ShadowRes, error) {
+	out := new(ShadowRes)
+	err := c.cc.Invoke(ctx, "/protomfx.ShadowsService/GetShadow", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ShadowsServiceServer is the server API for ShadowsService service.
+type ShadowsServiceServer interface {
+	GetShadow(context.Context, *ShadowReq) (*ShadowRes, error)
+}
+
+// UnimplementedShadowsServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedShadowsServiceServer struct {
+}
+
+func (*UnimplementedShadowsServiceServer) GetShadow(ctx context.Context, req *ShadowReq) (*ShadowRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetShadow not implemented")
+}
+
+func RegisterShadowsServiceServer(s *grpc.Server, srv ShadowsServiceServer) {
+	s.RegisterService(&_ShadowsService_serviceDesc, srv)
+}
+
+func _ShadowsService_GetShadow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShadowReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShadowsServiceServer).GetShadow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protomfx.ShadowsService/GetShadow",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShadowsServiceServer).GetShadow(ctx, req.(*ShadowReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ShadowsService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "protomfx.ShadowsService",
+	HandlerType: (*ShadowsServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetShadow",
+			Handler:    _ShadowsService_GetShadow_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/proto/mfx.proto",
+}
+
 func (m *Message) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -4763,6 +5058,16 @@ func (m *Alarm) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.Cleared {
+		i--
+		if m.Cleared {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x40
+	}
 	if len(m.RuleInfo) > 0 {
 		i -= len(m.RuleInfo)
 		copy(dAtA[i:], m.RuleInfo)
@@ -4895,6 +5200,20 @@ func (m *Webhook) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.WebhookId) > 0 {
+		i -= len(m.WebhookId)
+		copy(dAtA[i:], m.WebhookId)
+		i = encodeVarintMfx(dAtA, i, uint64(len(m.WebhookId)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.Subtopic) > 0 {
+		i -= len(m.Subtopic)
+		copy(dAtA[i:], m.Subtopic)
+		i = encodeVarintMfx(dAtA, i, uint64(len(m.Subtopic)))
+		i--
+		dAtA[i] = 0x22
+	}
 	if m.Created != 0 {
 		i = encodeVarintMfx(dAtA, i, uint64(m.Created))
 		i--
@@ -5028,6 +5347,13 @@ func (m *Config) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.TransformScriptID) > 0 {
+		i -= len(m.TransformScriptID)
+		copy(dAtA[i:], m.TransformScriptID)
+		i = encodeVarintMfx(dAtA, i, uint64(len(m.TransformScriptID)))
+		i--
+		dAtA[i] = 0x32
+	}
 	if m.RuleEnabled {
 		i--
 		if m.RuleEnabled {
@@ -7072,41 +7398,145 @@ func (m *ListSenMLMessagesRes) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func encodeVarintMfx(dAtA []byte, offset int, v uint64) int {
-	offset -= sovMfx(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *ShadowReq) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	dAtA[offset] = uint8(v)
-	return base
+	return dAtA[:n], nil
 }
-func (m *Message) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+
+func (m *ShadowReq) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ShadowReq) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Publisher)
-	if l > 0 {
-		n += 1 + l + sovMfx(uint64(l))
-	}
-	l = len(m.Subtopic)
-	if l > 0 {
-		n += 1 + l + sovMfx(uint64(l))
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	l = len(m.Payload)
-	if l > 0 {
-		n += 1 + l + sovMfx(uint64(l))
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintMfx(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0x12
 	}
-	l = len(m.ContentType)
-	if l > 0 {
-		n += 1 + l + sovMfx(uint64(l))
+	if len(m.ThingId) > 0 {
+		i -= len(m.ThingId)
+		copy(dAtA[i:], m.ThingId)
+		i = encodeVarintMfx(dAtA, i, uint64(len(m.ThingId)))
+		i--
+		dAtA[i] = 0xa
 	}
-	l = len(m.Protocol)
-	if l > 0 {
+	return len(dAtA) - i, nil
+}
+
+func (m *ShadowRes) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ShadowRes) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ShadowRes) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.UpdatedAt != 0 {
+		i = encodeVarintMfx(dAtA, i, uint64(m.UpdatedAt))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.ReportedAt != 0 {
+		i = encodeVarintMfx(dAtA, i, uint64(m.ReportedAt))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.Version != 0 {
+		i = encodeVarintMfx(dAtA, i, uint64(m.Version))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.Delta) > 0 {
+		i -= len(m.Delta)
+		copy(dAtA[i:], m.Delta)
+		i = encodeVarintMfx(dAtA, i, uint64(len(m.Delta)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Reported) > 0 {
+		i -= len(m.Reported)
+		copy(dAtA[i:], m.Reported)
+		i = encodeVarintMfx(dAtA, i, uint64(len(m.Reported)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Desired) > 0 {
+		i -= len(m.Desired)
+		copy(dAtA[i:], m.Desired)
+		i = encodeVarintMfx(dAtA, i, uint64(len(m.Desired)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintMfx(dAtA []byte, offset int, v uint64) int {
+	offset -= sovMfx(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *Message) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Publisher)
+	if l > 0 {
+		n += 1 + l + sovMfx(uint64(l))
+	}
+	l = len(m.Subtopic)
+	if l > 0 {
+		n += 1 + l + sovMfx(uint64(l))
+	}
+	l = len(m.Payload)
+	if l > 0 {
+		n += 1 + l + sovMfx(uint64(l))
+	}
+	l = len(m.ContentType)
+	if l > 0 {
+		n += 1 + l + sovMfx(uint64(l))
+	}
+	l = len(m.Protocol)
+	if l > 0 {
 		n += 1 + l + sovMfx(uint64(l))
 	}
 	if m.Created != 0 {
@@ -7185,6 +7615,9 @@ func (m *Alarm) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovMfx(uint64(l))
 	}
+	if m.Cleared {
+		n += 2
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -7239,6 +7672,14 @@ func (m *Webhook) Size() (n int) {
 	if m.Created != 0 {
 		n += 1 + sovMfx(uint64(m.Created))
 	}
+	l = len(m.Subtopic)
+	if l > 0 {
+		n += 1 + l + sovMfx(uint64(l))
+	}
+	l = len(m.WebhookId)
+	if l > 0 {
+		n += 1 + l + sovMfx(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -7308,6 +7749,10 @@ func (m *Config) Size() (n int) {
 	if m.RuleEnabled {
 		n += 2
 	}
+	l = len(m.TransformScriptID)
+	if l > 0 {
+		n += 1 + l + sovMfx(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -8286,6 +8731,59 @@ func (m *ListSenMLMessagesRes) Size() (n int) {
 	return n
 }
 
+func (m *ShadowReq) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ThingId)
+	if l > 0 {
+		n += 1 + l + sovMfx(uint64(l))
+	}
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovMfx(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ShadowRes) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Desired)
+	if l > 0 {
+		n += 1 + l + sovMfx(uint64(l))
+	}
+	l = len(m.Reported)
+	if l > 0 {
+		n += 1 + l + sovMfx(uint64(l))
+	}
+	l = len(m.Delta)
+	if l > 0 {
+		n += 1 + l + sovMfx(uint64(l))
+	}
+	if m.Version != 0 {
+		n += 1 + sovMfx(uint64(m.Version))
+	}
+	if m.ReportedAt != 0 {
+		n += 1 + sovMfx(uint64(m.ReportedAt))
+	}
+	if m.UpdatedAt != 0 {
+		n += 1 + sovMfx(uint64(m.UpdatedAt))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
 func sovMfx(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -8985,6 +9483,26 @@ func (m *Alarm) Unmarshal(dAtA []byte) error {
 				m.RuleInfo = []byte{}
 			}
 			iNdEx = postIndex
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cleared", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMfx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Cleared = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMfx(dAtA[iNdEx:])
@@ -9321,6 +9839,70 @@ func (m *Webhook) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Subtopic", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMfx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMfx
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMfx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Subtopic = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WebhookId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMfx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMfx
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMfx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.WebhookId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMfx(dAtA[iNdEx:])
@@ -9734,6 +10316,38 @@ func (m *Config) Unmarshal(dAtA []byte) error {
 				}
 			}
 			m.RuleEnabled = bool(v != 0)
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TransformScriptID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMfx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMfx
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMfx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TransformScriptID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMfx(dAtA[iNdEx:])
@@ -15414,6 +16028,331 @@ func (m *ListSenMLMessagesRes) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *ShadowReq) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMfx
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ShadowReq: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ShadowReq: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ThingId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMfx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMfx
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMfx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ThingId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMfx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMfx
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMfx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMfx(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMfx
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ShadowRes) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMfx
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ShadowRes: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ShadowRes: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Desired", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMfx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthMfx
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMfx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Desired = append(m.Desired[:0], dAtA[iNdEx:postIndex]...)
+			if m.Desired == nil {
+				m.Desired = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Reported", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMfx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthMfx
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMfx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Reported = append(m.Reported[:0], dAtA[iNdEx:postIndex]...)
+			if m.Reported == nil {
+				m.Reported = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Delta", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMfx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthMfx
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMfx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Delta = append(m.Delta[:0], dAtA[iNdEx:postIndex]...)
+			if m.Delta == nil {
+				m.Delta = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			m.Version = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMfx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Version |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReportedAt", wireType)
+			}
+			m.ReportedAt = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMfx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ReportedAt |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UpdatedAt", wireType)
+			}
+			m.UpdatedAt = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMfx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.UpdatedAt |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMfx(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMfx
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipMfx(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -25,11 +25,12 @@ func ProtoConfigToDomain(c *protomfx.Config) *domain.ProfileConfig {
 	}
 
 	return &domain.ProfileConfig{
-		ContentType:    c.GetContentType(),
-		Transformer:    tr,
-		WriteEnabled:   c.GetWriteEnabled(),
-		WebhookEnabled: c.GetWebhookEnabled(),
-		RuleEnabled:    c.GetRuleEnabled(),
+		ContentType:       c.GetContentType(),
+		Transformer:       tr,
+		WriteEnabled:      c.GetWriteEnabled(),
+		WebhookEnabled:    c.GetWebhookEnabled(),
+		RuleEnabled:       c.GetRuleEnabled(),
+		TransformScriptID: c.GetTransformScriptID(),
 	}
 }
 
@@ -48,9 +49,10 @@ func DomainConfigToProto(c *domain.ProfileConfig) *protomfx.Config {
 			TimeFormat:   c.Transformer.TimeFormat,
 			TimeLocation: c.Transformer.TimeLocation,
 		},
-		WriteEnabled:   c.WriteEnabled,
-		WebhookEnabled: c.WebhookEnabled,
-		RuleEnabled:    c.RuleEnabled,
+		WriteEnabled:      c.WriteEnabled,
+		WebhookEnabled:    c.WebhookEnabled,
+		RuleEnabled:       c.RuleEnabled,
+		TransformScriptID: c.TransformScriptID,
 	}
 
 	return cfg
@@ -38,3 +38,15 @@ type removeMembershipsReq struct {
 type deleteWebhooksReq struct {
 	WebhookIDs []string `json:"webhook_ids"`
 }
+
+// testRuleReq contains the rule and the sample message it is tested against
+type testRuleReq struct {
+	Rule    Rule          `json:"rule"`
+	Message SampleMessage `json:"message"`
+}
+
+// testScriptReq contains the Lua script and the sample message it is tested against
+type testScriptReq struct {
+	Script  string        `json:"script"`
+	Message SampleMessage `json:"message"`
+}
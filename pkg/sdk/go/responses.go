@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/MainfluxLabs/mainflux/pkg/domain"
 	"github.com/MainfluxLabs/mainflux/pkg/transformers/senml"
 )
 
@@ -133,3 +134,36 @@ type WebhooksPage struct {
 	Webhooks []Webhook `json:"webhooks"`
 	pageRes
 }
+
+// RuleTestResult represents the outcome of testing a rule against a sample message.
+type RuleTestResult struct {
+	Triggered  bool              `json:"triggered"`
+	Conditions []ConditionResult `json:"conditions"`
+	Actions    []ActionResult    `json:"actions"`
+}
+
+// ConditionResult reports whether a rule condition is met by a sample message.
+type ConditionResult struct {
+	Condition domain.Condition `json:"condition"`
+	Met       bool             `json:"met"`
+}
+
+// ActionResult describes an action a rule would trigger, along with its rendered payload.
+type ActionResult struct {
+	Action  RuleAction `json:"action"`
+	Payload string     `json:"payload,omitempty"`
+	Error   string     `json:"error,omitempty"`
+}
+
+// ScriptTestRun represents the outcome of running a Lua script against a sample message payload.
+type ScriptTestRun struct {
+	Status           string       `json:"status"`
+	Logs             []string     `json:"logs"`
+	Error            string       `json:"error,omitempty"`
+	InstructionCount uint         `json:"instruction_count"`
+	Actions          []RuleAction `json:"actions"`
+}
+
+type testScriptRes struct {
+	Runs []ScriptTestRun `json:"runs"`
+}
@@ -0,0 +1,87 @@
+package sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/MainfluxLabs/mainflux/pkg/errors"
+)
+
+const (
+	rulesEndpoint   = "rules"
+	scriptsEndpoint = "scripts"
+	testEndpoint    = "test"
+)
+
+func (sdk mfSDK) TestRule(rule Rule, msg SampleMessage, groupID, token string) (RuleTestResult, error) {
+	data, err := json.Marshal(testRuleReq{Rule: rule, Message: msg})
+	if err != nil {
+		return RuleTestResult{}, err
+	}
+
+	url := fmt.Sprintf("%s/groups/%s/%s/%s", sdk.rulesURL, groupID, rulesEndpoint, testEndpoint)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return RuleTestResult{}, err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return RuleTestResult{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return RuleTestResult{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return RuleTestResult{}, errors.Wrap(ErrFailedFetch, errors.New(resp.Status))
+	}
+
+	var res RuleTestResult
+	if err := json.Unmarshal(body, &res); err != nil {
+		return RuleTestResult{}, err
+	}
+
+	return res, nil
+}
+
+func (sdk mfSDK) TestScript(script string, msg SampleMessage, groupID, token string) ([]ScriptTestRun, error) {
+	data, err := json.Marshal(testScriptReq{Script: script, Message: msg})
+	if err != nil {
+		return []ScriptTestRun{}, err
+	}
+
+	url := fmt.Sprintf("%s/groups/%s/%s/%s", sdk.rulesURL, groupID, scriptsEndpoint, testEndpoint)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return []ScriptTestRun{}, err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return []ScriptTestRun{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return []ScriptTestRun{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return []ScriptTestRun{}, errors.Wrap(ErrFailedFetch, errors.New(resp.Status))
+	}
+
+	var res testScriptRes
+	if err := json.Unmarshal(body, &res); err != nil {
+		return []ScriptTestRun{}, err
+	}
+
+	return res.Runs, nil
+}
@@ -60,6 +60,12 @@ var (
 
 	// ErrCertsRemove indicates failure while cleaning up from the Certs service.
 	ErrCertsRemove = errors.New("failed to remove certificate")
+
+	// ErrInvalidSignature indicates a missing, malformed or mismatching webhook signature.
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+
+	// ErrSignatureExpired indicates a webhook signature timestamp outside the tolerance.
+	ErrSignatureExpired = errors.New("webhook signature timestamp outside tolerance")
 )
 
 // ContentType represents all possible content types.
@@ -213,6 +219,85 @@ type Webhook struct {
 	Name    string            `json:"name"`
 	Url     string            `json:"url"`
 	Headers map[string]string `json:"headers"`
+	// Method is the HTTP method messages are forwarded with, POST by default.
+	Method string `json:"method,omitempty"`
+	// Template is an optional Go template the request body is rendered from.
+	Template string `json:"template,omitempty"`
+	// Format is the body format, either json (default), form or xml.
+	Format string `json:"format,omitempty"`
+	// Filter selects the forwarded messages. All messages are forwarded if it is nil.
+	Filter *WebhookFilter `json:"filter,omitempty"`
+	// Batch forwards messages in batches, as a JSON array of the message bodies.
+	Batch bool `json:"batch,omitempty"`
+	// Secret signs forwarded messages. It is generated unless provided on
+	// creation, and is returned only when the webhook is created.
+	Secret string `json:"secret,omitempty"`
+}
+
+// WebhookFilter selects the messages forwarded to a webhook by their subtopic,
+// matched against MQTT-style patterns with + and # wildcards, and by conditions
+// on payload fields joined by the operator, AND by default.
+type WebhookFilter struct {
+	Subtopics  []string           `json:"subtopics,omitempty"`
+	Operator   string             `json:"operator,omitempty"`
+	Conditions []WebhookCondition `json:"conditions,omitempty"`
+}
+
+// WebhookCondition is a predicate on a payload field, using one of the
+// ==, !=, >, >=, <, <=, contains, in and exists comparators.
+type WebhookCondition struct {
+	Field      string `json:"field"`
+	Comparator string `json:"comparator"`
+	Value      any    `json:"value,omitempty"`
+}
+
+// WebhookSecret represents a rotated webhook signing secret.
+type WebhookSecret struct {
+	Secret string `json:"secret"`
+	// PreviousSecretExpiresAt is the end of the grace period during which messages
+	// are signed with the previous secret as well, in unix nanoseconds.
+	PreviousSecretExpiresAt int64 `json:"previous_secret_expires_at,omitempty"`
+}
+
+// Rule represents a rule evaluated against messages published by things.
+type Rule struct {
+	ID           string                 `json:"id,omitempty"`
+	GroupID      string                 `json:"group_id,omitempty"`
+	Name         string                 `json:"name,omitempty"`
+	Description  string                 `json:"description,omitempty"`
+	Input        RuleInput              `json:"input"`
+	Conditions   []domain.Condition     `json:"conditions,omitempty"`
+	Operator     string                 `json:"operator,omitempty"`
+	Expression   *domain.ConditionGroup `json:"expression,omitempty"`
+	Actions      []RuleAction           `json:"actions"`
+	Cooldown     uint64                 `json:"cooldown,omitempty"`
+	OnTransition bool                   `json:"on_transition,omitempty"`
+}
+
+// RuleInput represents the kind of messages a rule is evaluated against.
+type RuleInput struct {
+	Type     string         `json:"type"`
+	ThingIDs []string       `json:"thing_ids,omitempty"`
+	Config   map[string]any `json:"config,omitempty"`
+}
+
+// RuleAction represents an action triggered by a rule or a Lua script.
+type RuleAction struct {
+	ID       string `json:"id,omitempty"`
+	Type     string `json:"type"`
+	Level    int32  `json:"level,omitempty"`
+	Target   string `json:"target,omitempty"`
+	Subtopic string `json:"subtopic,omitempty"`
+	Payload  string `json:"payload,omitempty"`
+}
+
+// SampleMessage represents a sample message rules and Lua scripts are tested against.
+type SampleMessage struct {
+	Publisher   string          `json:"publisher,omitempty"`
+	Subtopic    string          `json:"subtopic,omitempty"`
+	ContentType string          `json:"content_type,omitempty"`
+	Created     int64           `json:"created,omitempty"`
+	Payload     json.RawMessage `json:"payload"`
 }
 
 type Metadata map[string]any
@@ -474,6 +559,15 @@ type SDK interface {
 
 	// DeleteWebhooks removes existing webhooks.
 	DeleteWebhooks(ids []string, token string) error
+
+	// RotateWebhookSecret replaces the signing secret of the webhook with a new one.
+	RotateWebhookSecret(webhookID, token string) (WebhookSecret, error)
+
+	// TestRule evaluates the rule against a sample message of the group without triggering any actions.
+	TestRule(rule Rule, msg SampleMessage, groupID, token string) (RuleTestResult, error)
+
+	// TestScript runs the Lua script against a sample message of the group without triggering any actions.
+	TestScript(script string, msg SampleMessage, groupID, token string) ([]ScriptTestRun, error)
 }
 
 type mfSDK struct {
@@ -484,6 +578,7 @@ type mfSDK struct {
 	thingsURL      string
 	usersURL       string
 	webhooksURL    string
+	rulesURL       string
 
 	msgContentType ContentType
 	client         *http.Client
@@ -498,6 +593,7 @@ type Config struct {
 	ThingsURL      string
 	UsersURL       string
 	WebhooksURL    string
+	RulesURL       string
 
 	MsgContentType  ContentType
 	TLSVerification bool
@@ -513,6 +609,7 @@ func NewSDK(conf Config) SDK {
 		thingsURL:      conf.ThingsURL,
 		usersURL:       conf.UsersURL,
 		webhooksURL:    conf.WebhooksURL,
+		rulesURL:       conf.RulesURL,
 
 		msgContentType: conf.MsgContentType,
 		client: &http.Client{
@@ -2,15 +2,31 @@ package sdk
 
 import (
 	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/MainfluxLabs/mainflux/pkg/errors"
 )
 
-const webhooksEndpoint = "webhooks"
+const (
+	webhooksEndpoint = "webhooks"
+
+	// WebhookSignatureHeader is the header carrying the signature of a forwarded message.
+	WebhookSignatureHeader = "X-Mainflux-Signature"
+
+	// DefaultSignatureTolerance is the recommended maximum age of a webhook signature.
+	DefaultSignatureTolerance = 5 * time.Minute
+
+	signatureScheme = "v1"
+)
 
 func (sdk mfSDK) CreateWebhooks(whs []Webhook, thingID, token string) ([]Webhook, error) {
 	data, err := json.Marshal(whs)
@@ -185,3 +201,85 @@ func (sdk mfSDK) DeleteWebhooks(ids []string, token string) error {
 
 	return nil
 }
+
+func (sdk mfSDK) RotateWebhookSecret(webhookID, token string) (WebhookSecret, error) {
+	url := fmt.Sprintf("%s/%s/%s/secret/rotate", sdk.webhooksURL, webhooksEndpoint, webhookID)
+	req, err := http.NewRequest(http.MethodPost, url, nil)
+	if err != nil {
+		return WebhookSecret{}, err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return WebhookSecret{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return WebhookSecret{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return WebhookSecret{}, errors.Wrap(ErrFailedUpdate, errors.New(resp.Status))
+	}
+
+	var ws WebhookSecret
+	if err := json.Unmarshal(body, &ws); err != nil {
+		return WebhookSecret{}, err
+	}
+
+	return ws, nil
+}
+
+// VerifyWebhookSignature verifies the signature header of a message forwarded
+// to a webhook against its raw payload and signing secret. Signatures with a
+// timestamp further than the tolerance from the current time are rejected to
+// prevent replays, unless the tolerance is zero.
+func VerifyWebhookSignature(payload []byte, header, secret string, tolerance time.Duration) error {
+	var ts int64
+	var sigs []string
+	for _, part := range strings.Split(header, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return ErrInvalidSignature
+		}
+
+		switch k {
+		case "t":
+			t, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return ErrInvalidSignature
+			}
+			ts = t
+		case signatureScheme:
+			sigs = append(sigs, v)
+		}
+	}
+
+	if ts == 0 || len(sigs) == 0 {
+		return ErrInvalidSignature
+	}
+
+	if tolerance > 0 {
+		age := time.Since(time.Unix(ts, 0))
+		if age > tolerance || age < -tolerance {
+			return ErrSignatureExpired
+		}
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", ts)
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+
+	// During a secret rotation the header carries a signature for each active secret.
+	for _, sig := range sigs {
+		b, err := hex.DecodeString(sig)
+		if err == nil && hmac.Equal(b, expected) {
+			return nil
+		}
+	}
+
+	return ErrInvalidSignature
+}
@@ -0,0 +1,95 @@
+package sdk_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	sdk "github.com/MainfluxLabs/mainflux/pkg/sdk/go"
+	"github.com/MainfluxLabs/mainflux/webhooks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	payload := []byte(`{"key":"val"}`)
+	secret := "whsec_current"
+	now := time.Now().Unix()
+	old := time.Now().Add(-time.Hour).Unix()
+	signed := func(ts int64, secrets ...string) string {
+		header := fmt.Sprintf("t=%d", ts)
+		for _, s := range secrets {
+			header += fmt.Sprintf(",v1=%s", webhooks.Sign(s, ts, payload))
+		}
+		return header
+	}
+
+	cases := []struct {
+		desc      string
+		payload   []byte
+		header    string
+		tolerance time.Duration
+		err       error
+	}{
+		{
+			desc:      "verify valid signature",
+			payload:   payload,
+			header:    signed(now, secret),
+			tolerance: sdk.DefaultSignatureTolerance,
+			err:       nil,
+		},
+		{
+			desc:      "verify signature with the current secret during rotation",
+			payload:   payload,
+			header:    signed(now, "whsec_next", secret),
+			tolerance: sdk.DefaultSignatureTolerance,
+			err:       nil,
+		},
+		{
+			desc:      "verify signature with wrong secret",
+			payload:   payload,
+			header:    signed(now, "whsec_other"),
+			tolerance: sdk.DefaultSignatureTolerance,
+			err:       sdk.ErrInvalidSignature,
+		},
+		{
+			desc:      "verify signature of tampered payload",
+			payload:   []byte(`{"key":"tampered"}`),
+			header:    signed(now, secret),
+			tolerance: sdk.DefaultSignatureTolerance,
+			err:       sdk.ErrInvalidSignature,
+		},
+		{
+			desc:      "verify replayed signature",
+			payload:   payload,
+			header:    signed(old, secret),
+			tolerance: sdk.DefaultSignatureTolerance,
+			err:       sdk.ErrSignatureExpired,
+		},
+		{
+			desc:      "verify old signature without tolerance",
+			payload:   payload,
+			header:    signed(old, secret),
+			tolerance: 0,
+			err:       nil,
+		},
+		{
+			desc:      "verify signature without timestamp",
+			payload:   payload,
+			header:    fmt.Sprintf("v1=%s", webhooks.Sign(secret, now, payload)),
+			tolerance: sdk.DefaultSignatureTolerance,
+			err:       sdk.ErrInvalidSignature,
+		},
+		{
+			desc:      "verify empty signature",
+			payload:   payload,
+			header:    "",
+			tolerance: sdk.DefaultSignatureTolerance,
+			err:       sdk.ErrInvalidSignature,
+		},
+	}
+
+	for _, tc := range cases {
+		err := sdk.VerifyWebhookSignature(tc.payload, tc.header, secret, tc.tolerance)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+	}
+}
@@ -16,6 +16,8 @@ import (
 	"github.com/MainfluxLabs/mainflux/pkg/servers"
 	"github.com/MainfluxLabs/mainflux/readers"
 	grpcreaders "github.com/MainfluxLabs/mainflux/readers/api/grpc"
+	"github.com/MainfluxLabs/mainflux/shadows"
+	grpcshadows "github.com/MainfluxLabs/mainflux/shadows/api/grpc"
 	"github.com/MainfluxLabs/mainflux/things"
 	grpcthings "github.com/MainfluxLabs/mainflux/things/api/grpc"
 	"github.com/MainfluxLabs/mainflux/users"
@@ -58,6 +60,8 @@ func Start(ctx context.Context, tracer opentracing.Tracer, svc any, cfg servers.
 		protomfx.RegisterAuthServiceServer(server, grpcauth.NewServer(tracer, v))
 	case readers.Service:
 		protomfx.RegisterReadersServiceServer(server, grpcreaders.NewServer(tracer, v))
+	case shadows.Service:
+		protomfx.RegisterShadowsServiceServer(server, grpcshadows.NewServer(tracer, v))
 	default:
 		return fmt.Errorf("unknown service: %s", cfg.ServerName)
 	}
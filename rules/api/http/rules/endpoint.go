@@ -22,12 +22,15 @@ func createRulesEndpoint(svc rules.Service) endpoint.Endpoint {
 		var rulesList []rules.Rule
 		for _, rReq := range req.Rules {
 			r := rules.Rule{
-				Name:        rReq.Name,
-				Description: rReq.Description,
-				Input:       rReq.Input,
-				Conditions:  rReq.Conditions,
-				Operator:    rReq.Operator,
-				Actions:     rReq.Actions,
+				Name:         rReq.Name,
+				Description:  rReq.Description,
+				Input:        rReq.Input,
+				Conditions:   rReq.Conditions,
+				Operator:     rReq.Operator,
+				Expression:   rReq.Expression,
+				Actions:      rReq.Actions,
+				Cooldown:     rReq.Cooldown,
+				OnTransition: rReq.OnTransition,
 			}
 			rulesList = append(rulesList, r)
 		}
@@ -45,6 +48,30 @@ func createRulesEndpoint(svc rules.Service) endpoint.Endpoint {
 	}
 }
 
+func testRuleEndpoint(svc rules.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(testRuleReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		rule := rules.Rule{
+			Input:      req.Rule.Input,
+			Conditions: req.Rule.Conditions,
+			Operator:   req.Rule.Operator,
+			Expression: req.Rule.Expression,
+			Actions:    req.Rule.Actions,
+		}
+
+		result, err := svc.TestRule(ctx, req.token, req.groupID, rule, req.Message.Proto())
+		if err != nil {
+			return nil, err
+		}
+
+		return toTestRuleRes(result), nil
+	}
+}
+
 func listRulesByThingEndpoint(svc rules.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request any) (any, error) {
 		req := request.(listRulesByThingReq)
@@ -118,13 +145,16 @@ func updateRuleEndpoint(svc rules.Service) endpoint.Endpoint {
 		}
 
 		rule := rules.Rule{
-			ID:          req.id,
-			Name:        req.Name,
-			Description: req.Description,
-			Input:       rules.Input{Type: req.Input.Type, Config: req.Input.Config},
-			Conditions:  req.Conditions,
-			Operator:    req.Operator,
-			Actions:     req.Actions,
+			ID:           req.id,
+			Name:         req.Name,
+			Description:  req.Description,
+			Input:        rules.Input{Type: req.Input.Type, Config: req.Input.Config},
+			Conditions:   req.Conditions,
+			Operator:     req.Operator,
+			Expression:   req.Expression,
+			Actions:      req.Actions,
+			Cooldown:     req.Cooldown,
+			OnTransition: req.OnTransition,
 		}
 
 		if err := svc.UpdateRule(ctx, req.token, rule); err != nil {
@@ -182,14 +212,17 @@ func removeRulesEndpoint(svc rules.Service) endpoint.Endpoint {
 
 func toRuleResponse(r rules.Rule) ruleResponse {
 	return ruleResponse{
-		ID:          r.ID,
-		GroupID:     r.GroupID,
-		Name:        r.Name,
-		Description: r.Description,
-		Input:       r.Input,
-		Conditions:  r.Conditions,
-		Operator:    r.Operator,
-		Actions:     r.Actions,
+		ID:           r.ID,
+		GroupID:      r.GroupID,
+		Name:         r.Name,
+		Description:  r.Description,
+		Input:        r.Input,
+		Conditions:   r.Conditions,
+		Operator:     r.Operator,
+		Expression:   r.Expression,
+		Actions:      r.Actions,
+		Cooldown:     r.Cooldown,
+		OnTransition: r.OnTransition,
 	}
 }
 
@@ -43,18 +43,33 @@ var (
 	threshold1, threshold2 = 30.0, 80.0
 	condTemp               = rules.Condition{Field: "temperature", Comparator: ">", Threshold: &threshold1}
 	condHum                = rules.Condition{Field: "humidity", Comparator: "<", Threshold: &threshold2}
-	action                 = rules.Action{Type: rules.ActionTypeAlarm, Level: 1}
+	clearThreshold         = 25.0
+	condStatus             = rules.Condition{Field: "status", Comparator: rules.ComparatorEQ, Value: "FAULT"}
+	condInSet              = rules.Condition{Field: "mode", Comparator: rules.ComparatorIn, Value: []any{"auto", "manual"}}
+	condStateful           = rules.Condition{Field: "temperature", Comparator: ">", Threshold: &threshold1, ClearThreshold: &clearThreshold, Duration: 300}
+	condAggregate          = rules.Condition{Field: "temperature", Comparator: ">", Threshold: &threshold1, Aggregation: &rules.Aggregation{Type: rules.AggregationAvg, Count: 10, Period: 600}}
+	expression             = rules.ConditionGroup{
+		Operator:   rules.OperatorOR,
+		Conditions: []rules.Condition{condStatus},
+		Groups:     []rules.ConditionGroup{{Operator: rules.OperatorAND, Conditions: []rules.Condition{condTemp, condHum}}},
+	}
+	action        = rules.Action{Type: rules.ActionTypeAlarm, Level: 1}
+	commandAction = rules.Action{Type: rules.ActionTypeCommand, Target: rules.ActionTargetThing, ID: thingID, Subtopic: "fan", Payload: `{"fan": "on"}`}
+	shadowAction  = rules.Action{Type: rules.ActionTypeShadow, Target: rules.ActionTargetGroup, ID: groupID, Payload: `{"fan": "on"}`}
 )
 
 type rule struct {
-	ID          string            `json:"id,omitempty"`
-	GroupID     string            `json:"group_id,omitempty"`
-	Name        string            `json:"name,omitempty"`
-	Description string            `json:"description,omitempty"`
-	Input       rules.Input       `json:"input"`
-	Conditions  []rules.Condition `json:"conditions,omitempty"`
-	Operator    string            `json:"operator,omitempty"`
-	Actions     []rules.Action    `json:"actions,omitempty"`
+	ID           string                `json:"id,omitempty"`
+	GroupID      string                `json:"group_id,omitempty"`
+	Name         string                `json:"name,omitempty"`
+	Description  string                `json:"description,omitempty"`
+	Input        rules.Input           `json:"input"`
+	Conditions   []rules.Condition     `json:"conditions,omitempty"`
+	Operator     string                `json:"operator,omitempty"`
+	Expression   *rules.ConditionGroup `json:"expression,omitempty"`
+	Actions      []rules.Action        `json:"actions,omitempty"`
+	Cooldown     uint64                `json:"cooldown,omitempty"`
+	OnTransition bool                  `json:"on_transition,omitempty"`
 }
 
 type rulesReq struct {
@@ -89,7 +104,7 @@ func newService() rules.Service {
 	idp := uuid.NewMock()
 	log := logger.NewMock()
 
-	return rules.New(rulesRepo, ths, pkgmocks.NewReadersClient(), pub, idp, log, true)
+	return rules.New(rulesRepo, ths, pkgmocks.NewReadersClient(), pkgmocks.NewShadowsClient(), pub, idp, log, rules.ScriptsConfig{Enabled: true})
 }
 
 func newHTTPServer(svc rules.Service) *httptest.Server {
@@ -370,6 +385,72 @@ func TestCreateRules(t *testing.T) {
 			status: http.StatusBadRequest,
 			size:   0,
 		},
+		{
+			desc:        "create rule with command action",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body: rulesReq{Rules: []rule{
+				{Name: ruleName, Input: validInput, Conditions: []rules.Condition{condTemp}, Actions: []rules.Action{commandAction}},
+			}},
+			status: http.StatusCreated,
+			size:   1,
+		},
+		{
+			desc:        "create rule with command action without target",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body: rulesReq{Rules: []rule{
+				{Name: ruleName, Input: validInput, Conditions: []rules.Condition{condTemp}, Actions: []rules.Action{{Type: rules.ActionTypeCommand, ID: thingID}}},
+			}},
+			status: http.StatusBadRequest,
+			size:   0,
+		},
+		{
+			desc:        "create rule with command action without id",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body: rulesReq{Rules: []rule{
+				{Name: ruleName, Input: validInput, Conditions: []rules.Condition{condTemp}, Actions: []rules.Action{{Type: rules.ActionTypeCommand, Target: rules.ActionTargetThing}}},
+			}},
+			status: http.StatusBadRequest,
+			size:   0,
+		},
+		{
+			desc:        "create rule with command action with invalid payload template",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body: rulesReq{Rules: []rule{
+				{Name: ruleName, Input: validInput, Conditions: []rules.Condition{condTemp}, Actions: []rules.Action{{Type: rules.ActionTypeCommand, Target: rules.ActionTargetThing, ID: thingID, Payload: `{"fan": {{.payload.fan}`}}},
+			}},
+			status: http.StatusBadRequest,
+			size:   0,
+		},
+		{
+			desc:        "create rule with shadow action",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body: rulesReq{Rules: []rule{
+				{Name: ruleName, Input: validInput, Conditions: []rules.Condition{condTemp}, Actions: []rules.Action{shadowAction}},
+			}},
+			status: http.StatusCreated,
+			size:   1,
+		},
+		{
+			desc:        "create rule with shadow action without payload",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body: rulesReq{Rules: []rule{
+				{Name: ruleName, Input: validInput, Conditions: []rules.Condition{condTemp}, Actions: []rules.Action{{Type: rules.ActionTypeShadow, Target: rules.ActionTargetGroup, ID: groupID}}},
+			}},
+			status: http.StatusBadRequest,
+			size:   0,
+		},
 		{
 			desc:        "create rule with invalid alarm level",
 			auth:        token,
@@ -381,6 +462,215 @@ func TestCreateRules(t *testing.T) {
 			status: http.StatusBadRequest,
 			size:   0,
 		},
+		{
+			desc:        "create rule with stateful condition",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body: rulesReq{Rules: []rule{
+				{Name: ruleName, Input: validInput, Conditions: []rules.Condition{condStateful}, Actions: []rules.Action{action}},
+			}},
+			status: http.StatusCreated,
+			size:   1,
+		},
+		{
+			desc:        "create rule with invalid condition mode",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body: rulesReq{Rules: []rule{
+				{Name: ruleName, Input: validInput, Conditions: []rules.Condition{{Field: "temperature", Comparator: ">", Threshold: &threshold1, Mode: "invalid"}}, Actions: []rules.Action{action}},
+			}},
+			status: http.StatusBadRequest,
+			size:   0,
+		},
+		{
+			desc:        "create rule with clear threshold above threshold",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body: rulesReq{Rules: []rule{
+				{Name: ruleName, Input: validInput, Conditions: []rules.Condition{{Field: "temperature", Comparator: ">", Threshold: &threshold1, ClearThreshold: &threshold2}}, Actions: []rules.Action{action}},
+			}},
+			status: http.StatusBadRequest,
+			size:   0,
+		},
+		{
+			desc:        "create rule with string and set conditions",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body: rulesReq{Rules: []rule{
+				{Name: ruleName, Input: validInput, Conditions: []rules.Condition{condStatus, condInSet}, Operator: rules.OperatorOR, Actions: []rules.Action{action}},
+			}},
+			status: http.StatusCreated,
+			size:   1,
+		},
+		{
+			desc:        "create rule with invalid regex condition",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body: rulesReq{Rules: []rule{
+				{Name: ruleName, Input: validInput, Conditions: []rules.Condition{{Field: "status", Comparator: rules.ComparatorMatches, Value: "(["}}, Actions: []rules.Action{action}},
+			}},
+			status: http.StatusBadRequest,
+			size:   0,
+		},
+		{
+			desc:        "create rule with empty set condition",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body: rulesReq{Rules: []rule{
+				{Name: ruleName, Input: validInput, Conditions: []rules.Condition{{Field: "status", Comparator: rules.ComparatorIn, Value: []any{}}}, Actions: []rules.Action{action}},
+			}},
+			status: http.StatusBadRequest,
+			size:   0,
+		},
+		{
+			desc:        "create rule with invalid comparator",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body: rulesReq{Rules: []rule{
+				{Name: ruleName, Input: validInput, Conditions: []rules.Condition{{Field: "status", Comparator: "~=", Value: "FAULT"}}, Actions: []rules.Action{action}},
+			}},
+			status: http.StatusBadRequest,
+			size:   0,
+		},
+		{
+			desc:        "create rule with nested expression",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body: rulesReq{Rules: []rule{
+				{Name: ruleName, Input: validInput, Expression: &expression, Actions: []rules.Action{action}},
+			}},
+			status: http.StatusCreated,
+			size:   1,
+		},
+		{
+			desc:        "create rule with both conditions and expression",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body: rulesReq{Rules: []rule{
+				{Name: ruleName, Input: validInput, Conditions: []rules.Condition{condTemp}, Expression: &expression, Actions: []rules.Action{action}},
+			}},
+			status: http.StatusBadRequest,
+			size:   0,
+		},
+		{
+			desc:        "create rule with empty expression group",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body: rulesReq{Rules: []rule{
+				{Name: ruleName, Input: validInput, Expression: &rules.ConditionGroup{Operator: rules.OperatorAND, Groups: []rules.ConditionGroup{{}}}, Actions: []rules.Action{action}},
+			}},
+			status: http.StatusBadRequest,
+			size:   0,
+		},
+		{
+			desc:        "create rule with expression group missing operator",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body: rulesReq{Rules: []rule{
+				{Name: ruleName, Input: validInput, Expression: &rules.ConditionGroup{Conditions: []rules.Condition{condTemp, condHum}}, Actions: []rules.Action{action}},
+			}},
+			status: http.StatusBadRequest,
+			size:   0,
+		},
+		{
+			desc:        "create rule with stateful condition and alarm input",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body: rulesReq{Rules: []rule{
+				{Name: ruleName, Input: rules.Input{Type: rules.InputTypeAlarm, ThingIDs: []string{thingID}}, Conditions: []rules.Condition{condStateful}, Actions: []rules.Action{{Type: rules.ActionTypeWebhook}}},
+			}},
+			status: http.StatusBadRequest,
+			size:   0,
+		},
+		{
+			desc:        "create rule with aggregate condition",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body: rulesReq{Rules: []rule{
+				{Name: ruleName, Input: validInput, Conditions: []rules.Condition{condAggregate}, Actions: []rules.Action{action}},
+			}},
+			status: http.StatusCreated,
+			size:   1,
+		},
+		{
+			desc:        "create rule with invalid aggregation type",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body: rulesReq{Rules: []rule{
+				{Name: ruleName, Input: validInput, Conditions: []rules.Condition{{Field: "temperature", Comparator: ">", Threshold: &threshold1, Aggregation: &rules.Aggregation{Type: "median", Count: 10}}}, Actions: []rules.Action{action}},
+			}},
+			status: http.StatusBadRequest,
+			size:   0,
+		},
+		{
+			desc:        "create rule with aggregation without window",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body: rulesReq{Rules: []rule{
+				{Name: ruleName, Input: validInput, Conditions: []rules.Condition{{Field: "temperature", Comparator: ">", Threshold: &threshold1, Aggregation: &rules.Aggregation{Type: rules.AggregationMax}}}, Actions: []rules.Action{action}},
+			}},
+			status: http.StatusBadRequest,
+			size:   0,
+		},
+		{
+			desc:        "create rule with aggregation window exceeding maximum size",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body: rulesReq{Rules: []rule{
+				{Name: ruleName, Input: validInput, Conditions: []rules.Condition{{Field: "temperature", Comparator: ">", Threshold: &threshold1, Aggregation: &rules.Aggregation{Type: rules.AggregationMax, Count: rules.MaxWindowSize + 1}}}, Actions: []rules.Action{action}},
+			}},
+			status: http.StatusBadRequest,
+			size:   0,
+		},
+		{
+			desc:        "create rule with cooldown firing on transition",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body: rulesReq{Rules: []rule{
+				{Name: ruleName, Input: validInput, Conditions: []rules.Condition{condTemp}, Actions: []rules.Action{action}, Cooldown: 300, OnTransition: true},
+			}},
+			status: http.StatusCreated,
+			size:   1,
+		},
+		{
+			desc:        "create rule with cooldown exceeding maximum",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body: rulesReq{Rules: []rule{
+				{Name: ruleName, Input: validInput, Conditions: []rules.Condition{condTemp}, Actions: []rules.Action{action}, Cooldown: 7*24*60*60 + 1},
+			}},
+			status: http.StatusBadRequest,
+			size:   0,
+		},
+		{
+			desc:        "create rule with aggregate condition and alarm input",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body: rulesReq{Rules: []rule{
+				{Name: ruleName, Input: rules.Input{Type: rules.InputTypeAlarm, ThingIDs: []string{thingID}}, Conditions: []rules.Condition{condAggregate}, Actions: []rules.Action{{Type: rules.ActionTypeWebhook}}},
+			}},
+			status: http.StatusBadRequest,
+			size:   0,
+		},
 	}
 
 	for _, tc := range cases {
@@ -408,6 +698,146 @@ func TestCreateRules(t *testing.T) {
 	}
 }
 
+func TestTestRule(t *testing.T) {
+	svc := newService()
+	ts := newHTTPServer(svc)
+	defer ts.Close()
+
+	validRule := map[string]any{
+		"input":      rules.Input{Type: rules.InputTypeMessage},
+		"conditions": []rules.Condition{condTemp, condHum},
+		"operator":   rules.OperatorAND,
+		"actions":    []rules.Action{action},
+	}
+	validMsg := map[string]any{
+		"publisher": thingID,
+		"payload":   map[string]any{"temperature": 35, "humidity": 50},
+	}
+
+	cases := []struct {
+		desc        string
+		auth        string
+		groupID     string
+		contentType string
+		body        any
+		status      int
+		triggered   bool
+		actions     int
+	}{
+		{
+			desc:        "test triggered rule",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body:        map[string]any{"rule": validRule, "message": validMsg},
+			status:      http.StatusOK,
+			triggered:   true,
+			actions:     1,
+		},
+		{
+			desc:        "test rule not triggered",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body:        map[string]any{"rule": validRule, "message": map[string]any{"payload": map[string]any{"temperature": 20}}},
+			status:      http.StatusOK,
+			triggered:   false,
+			actions:     0,
+		},
+		{
+			desc:        "test rule with senml message",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body: map[string]any{"rule": map[string]any{
+				"input":      rules.Input{Type: rules.InputTypeMessage},
+				"conditions": []rules.Condition{condTemp},
+				"actions":    []rules.Action{action},
+			}, "message": map[string]any{"content_type": "application/senml+json", "payload": []map[string]any{{"n": "temperature", "v": 35}}}},
+			status:    http.StatusOK,
+			triggered: true,
+			actions:   1,
+		},
+		{
+			desc:        "test rule without message payload",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body:        map[string]any{"rule": validRule, "message": map[string]any{"publisher": thingID}},
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "test rule with invalid message content type",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body:        map[string]any{"rule": validRule, "message": map[string]any{"content_type": "text/plain", "payload": "temperature"}},
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "test rule without actions",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body: map[string]any{"rule": map[string]any{
+				"input":      rules.Input{Type: rules.InputTypeMessage},
+				"conditions": []rules.Condition{condTemp},
+			}, "message": validMsg},
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:        "test rule with invalid auth token",
+			auth:        wrongValue,
+			groupID:     groupID,
+			contentType: contentType,
+			body:        map[string]any{"rule": validRule, "message": validMsg},
+			status:      http.StatusUnauthorized,
+		},
+		{
+			desc:        "test rule with invalid content type",
+			auth:        token,
+			groupID:     groupID,
+			contentType: emptyValue,
+			body:        map[string]any{"rule": validRule, "message": validMsg},
+			status:      http.StatusUnsupportedMediaType,
+		},
+		{
+			desc:        "test rule with malformed body",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body:        "{",
+			status:      http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		reqBody, ok := tc.body.(string)
+		if !ok {
+			reqBody = toJSON(tc.body)
+		}
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/groups/%s/rules/test", ts.URL, tc.groupID),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(reqBody),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+
+		var resBody struct {
+			Triggered bool             `json:"triggered"`
+			Actions   []map[string]any `json:"actions"`
+		}
+		json.NewDecoder(res.Body).Decode(&resBody)
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status %d got %d\n", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.triggered, resBody.Triggered, fmt.Sprintf("%s: expected triggered %t got %t\n", tc.desc, tc.triggered, resBody.Triggered))
+		assert.Equal(t, tc.actions, len(resBody.Actions), fmt.Sprintf("%s: expected %d actions got %d\n", tc.desc, tc.actions, len(resBody.Actions)))
+	}
+}
+
 func TestViewRule(t *testing.T) {
 	svc := newService()
 	ts := newHTTPServer(svc)
@@ -19,15 +19,20 @@ const (
 	maxThingIDs   = 100
 	minAlarmLevel = 1
 	maxAlarmLevel = 5
+	// maxCooldown is the maximum rule cooldown in seconds (7 days).
+	maxCooldown = 7 * 24 * 60 * 60
 )
 
 type createRule struct {
-	Name        string            `json:"name"`
-	Description string            `json:"description,omitempty"`
-	Input       rules.Input       `json:"input"`
-	Conditions  []rules.Condition `json:"conditions"`
-	Operator    string            `json:"operator,omitempty"`
-	Actions     []rules.Action    `json:"actions"`
+	Name         string                `json:"name"`
+	Description  string                `json:"description,omitempty"`
+	Input        rules.Input           `json:"input"`
+	Conditions   []rules.Condition     `json:"conditions,omitempty"`
+	Operator     string                `json:"operator,omitempty"`
+	Expression   *rules.ConditionGroup `json:"expression,omitempty"`
+	Actions      []rules.Action        `json:"actions"`
+	Cooldown     uint64                `json:"cooldown,omitempty"`
+	OnTransition bool                  `json:"on_transition,omitempty"`
 }
 
 type createRulesReq struct {
@@ -71,13 +76,56 @@ func (req createRule) validate() error {
 		return err
 	}
 
-	if err := validateConditions(req.Conditions, req.Operator); err != nil {
+	if err := api.ValidateConditions(req.Input.Type, req.Conditions, req.Operator, req.Expression); err != nil {
 		return err
 	}
 
+	if req.Cooldown > maxCooldown {
+		return apiutil.ErrInvalidCooldown
+	}
+
 	return validateActions(req.Input.Type, req.Actions)
 }
 
+type testRule struct {
+	Input      rules.Input           `json:"input"`
+	Conditions []rules.Condition     `json:"conditions,omitempty"`
+	Operator   string                `json:"operator,omitempty"`
+	Expression *rules.ConditionGroup `json:"expression,omitempty"`
+	Actions    []rules.Action        `json:"actions"`
+}
+
+type testRuleReq struct {
+	token   string
+	groupID string
+	Rule    testRule    `json:"rule"`
+	Message api.Message `json:"message"`
+}
+
+func (req testRuleReq) validate() error {
+	if req.token == "" {
+		return apiutil.ErrBearerToken
+	}
+
+	if req.groupID == "" {
+		return apiutil.ErrMissingGroupID
+	}
+
+	if err := validateInputType(req.Rule.Input.Type); err != nil {
+		return err
+	}
+
+	if err := api.ValidateConditions(req.Rule.Input.Type, req.Rule.Conditions, req.Rule.Operator, req.Rule.Expression); err != nil {
+		return err
+	}
+
+	if err := validateActions(req.Rule.Input.Type, req.Rule.Actions); err != nil {
+		return err
+	}
+
+	return req.Message.Validate()
+}
+
 type ruleReq struct {
 	token string
 	id    string
@@ -137,14 +185,17 @@ type updateRuleInput struct {
 }
 
 type updateRuleReq struct {
-	token       string
-	id          string
-	Name        string            `json:"name"`
-	Description string            `json:"description,omitempty"`
-	Input       updateRuleInput   `json:"input"`
-	Conditions  []rules.Condition `json:"conditions"`
-	Operator    string            `json:"operator,omitempty"`
-	Actions     []rules.Action    `json:"actions"`
+	token        string
+	id           string
+	Name         string                `json:"name"`
+	Description  string                `json:"description,omitempty"`
+	Input        updateRuleInput       `json:"input"`
+	Conditions   []rules.Condition     `json:"conditions,omitempty"`
+	Operator     string                `json:"operator,omitempty"`
+	Expression   *rules.ConditionGroup `json:"expression,omitempty"`
+	Actions      []rules.Action        `json:"actions"`
+	Cooldown     uint64                `json:"cooldown,omitempty"`
+	OnTransition bool                  `json:"on_transition,omitempty"`
 }
 
 func (req updateRuleReq) validate() error {
@@ -164,10 +215,14 @@ func (req updateRuleReq) validate() error {
 		return err
 	}
 
-	if err := validateConditions(req.Conditions, req.Operator); err != nil {
+	if err := api.ValidateConditions(req.Input.Type, req.Conditions, req.Operator, req.Expression); err != nil {
 		return err
 	}
 
+	if req.Cooldown > maxCooldown {
+		return apiutil.ErrInvalidCooldown
+	}
+
 	return validateActions(req.Input.Type, req.Actions)
 }
 
@@ -231,31 +286,6 @@ func validateInputType(inputType string) error {
 	}
 }
 
-func validateConditions(conditions []rules.Condition, operator string) error {
-	if len(conditions) < minLen {
-		return apiutil.ErrEmptyList
-	}
-	for _, condition := range conditions {
-		if condition.Field == "" {
-			return apiutil.ErrMissingConditionField
-		}
-		switch condition.Comparator {
-		case rules.ComparatorEQ, rules.ComparatorGT, rules.ComparatorLT, rules.ComparatorGTE, rules.ComparatorLTE:
-		default:
-			return apiutil.ErrInvalidConditionComparator
-		}
-		if condition.Threshold == nil {
-			return apiutil.ErrMissingConditionThreshold
-		}
-	}
-	if len(conditions) > minLen {
-		if operator != rules.OperatorAND && operator != rules.OperatorOR {
-			return apiutil.ErrInvalidOperator
-		}
-	}
-	return nil
-}
-
 func validateActions(inputType string, actions []rules.Action) error {
 	if len(actions) < minLen {
 		return apiutil.ErrEmptyList
@@ -274,6 +304,20 @@ func validateActions(inputType string, actions []rules.Action) error {
 				return apiutil.ErrInvalidAlarmLevel
 			}
 		case rules.ActionTypeWebhook:
+		case rules.ActionTypeCommand, rules.ActionTypeShadow:
+			if action.ID == "" {
+				return apiutil.ErrMissingActionID
+			}
+			if action.Target != rules.ActionTargetThing && action.Target != rules.ActionTargetGroup {
+				return apiutil.ErrInvalidActionTarget
+			}
+			if _, err := rules.ParsePayloadTemplate(action.Payload); err != nil {
+				return apiutil.ErrInvalidPayloadTemplate
+			}
+			// Shadow actions need an explicit desired state patch.
+			if action.Type == rules.ActionTypeShadow && action.Payload == "" {
+				return apiutil.ErrInvalidPayloadTemplate
+			}
 		default:
 			return apiutil.ErrInvalidActionType
 		}
@@ -14,6 +14,7 @@ var (
 	_ apiutil.Response = (*ruleResponse)(nil)
 	_ apiutil.Response = (*rulesRes)(nil)
 	_ apiutil.Response = (*thingIDsRes)(nil)
+	_ apiutil.Response = (*testRuleRes)(nil)
 )
 
 type pageRes struct {
@@ -26,15 +27,18 @@ type pageRes struct {
 }
 
 type ruleResponse struct {
-	ID          string            `json:"id"`
-	GroupID     string            `json:"group_id"`
-	Name        string            `json:"name"`
-	Description string            `json:"description,omitempty"`
-	Input       rules.Input       `json:"input"`
-	Conditions  []rules.Condition `json:"conditions"`
-	Operator    string            `json:"operator"`
-	Actions     []rules.Action    `json:"actions"`
-	updated     bool
+	ID           string                `json:"id"`
+	GroupID      string                `json:"group_id"`
+	Name         string                `json:"name"`
+	Description  string                `json:"description,omitempty"`
+	Input        rules.Input           `json:"input"`
+	Conditions   []rules.Condition     `json:"conditions,omitempty"`
+	Operator     string                `json:"operator"`
+	Expression   *rules.ConditionGroup `json:"expression,omitempty"`
+	Actions      []rules.Action        `json:"actions"`
+	Cooldown     uint64                `json:"cooldown,omitempty"`
+	OnTransition bool                  `json:"on_transition,omitempty"`
+	updated      bool
 }
 
 func (res ruleResponse) Code() int {
@@ -102,3 +106,48 @@ func (res thingIDsRes) Headers() map[string]string {
 func (res thingIDsRes) Empty() bool {
 	return false
 }
+
+type conditionResultRes struct {
+	Condition rules.Condition `json:"condition"`
+	Met       bool            `json:"met"`
+}
+
+type actionResultRes struct {
+	Action  rules.Action `json:"action"`
+	Payload string       `json:"payload,omitempty"`
+	Error   string       `json:"error,omitempty"`
+}
+
+type testRuleRes struct {
+	Triggered  bool                 `json:"triggered"`
+	Conditions []conditionResultRes `json:"conditions"`
+	Actions    []actionResultRes    `json:"actions"`
+}
+
+func toTestRuleRes(result rules.RuleTestResult) testRuleRes {
+	res := testRuleRes{
+		Triggered:  result.Triggered,
+		Conditions: []conditionResultRes{},
+		Actions:    []actionResultRes{},
+	}
+	for _, c := range result.Conditions {
+		res.Conditions = append(res.Conditions, conditionResultRes{Condition: c.Condition, Met: c.Met})
+	}
+	for _, a := range result.Actions {
+		res.Actions = append(res.Actions, actionResultRes{Action: a.Action, Payload: a.Payload, Error: a.Error})
+	}
+
+	return res
+}
+
+func (res testRuleRes) Code() int {
+	return http.StatusOK
+}
+
+func (res testRuleRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res testRuleRes) Empty() bool {
+	return false
+}
@@ -41,6 +41,15 @@ func MakeHandler(svc rules.Service, ac domain.AuthClient, mux *bone.Mux, tracer
 		encodeResponse,
 		opts...,
 	))
+	mux.Post("/groups/:id/rules/test", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "test_rule"),
+			withIdentity,
+		)(testRuleEndpoint(svc)),
+		decodeTestRule,
+		encodeResponse,
+		opts...,
+	))
 	mux.Get("/rules/:id", kithttp.NewServer(
 		endpoint.Chain(
 			kitot.TraceServer(tracer, "view_rule"),
@@ -132,6 +141,22 @@ func decodeCreateRules(_ context.Context, r *http.Request) (any, error) {
 	return req, nil
 }
 
+func decodeTestRule(_ context.Context, r *http.Request) (any, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), apiutil.ContentTypeJSON) {
+		return nil, apiutil.ErrUnsupportedContentType
+	}
+
+	req := testRuleReq{
+		token:   apiutil.ExtractBearerToken(r),
+		groupID: bone.GetValue(r, apiutil.IDKey),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
 func decodeListRulesByThing(_ context.Context, r *http.Request) (any, error) {
 	base, err := apiutil.BuildPageMetadata(r)
 	if err != nil {
@@ -6,8 +6,10 @@ package scripts
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/MainfluxLabs/mainflux/pkg/apiutil"
+	"github.com/MainfluxLabs/mainflux/pkg/cron"
 	"github.com/MainfluxLabs/mainflux/rules"
 	"github.com/go-kit/kit/endpoint"
 )
@@ -25,6 +27,7 @@ func createScriptsEndpoint(svc rules.Service) endpoint.Endpoint {
 				Name:        sReq.Name,
 				Script:      sReq.Script,
 				Description: sReq.Description,
+				Triggers:    normalizeTriggers(sReq.Triggers),
 			}
 
 			reqScripts = append(reqScripts, script)
@@ -40,6 +43,22 @@ func createScriptsEndpoint(svc rules.Service) endpoint.Endpoint {
 	}
 }
 
+func testScriptEndpoint(svc rules.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(testScriptReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		results, err := svc.TestScript(ctx, req.token, req.groupID, rules.LuaScript{Script: req.Script}, req.Message.Proto())
+		if err != nil {
+			return nil, err
+		}
+
+		return buildTestScriptResponse(results), nil
+	}
+}
+
 func listScriptsByThingEndpoint(svc rules.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request any) (any, error) {
 		req := request.(listScriptsByThingReq)
@@ -117,6 +136,7 @@ func updateScriptEndpoint(svc rules.Service) endpoint.Endpoint {
 			Name:        req.Name,
 			Script:      req.Script,
 			Description: req.Description,
+			Triggers:    normalizeTriggers(req.Triggers),
 		}
 
 		if err := svc.UpdateScript(ctx, req.token, script); err != nil {
@@ -142,6 +162,83 @@ func removeScriptsEndpoint(svc rules.Service) endpoint.Endpoint {
 	}
 }
 
+func listScriptVersionsEndpoint(svc rules.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(listScriptVersionsReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListScriptVersions(ctx, req.token, req.id, req.pageMetadata)
+		if err != nil {
+			return nil, err
+		}
+
+		return buildScriptVersionsPageResponse(page, req.pageMetadata), nil
+	}
+}
+
+func rollbackScriptEndpoint(svc rules.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(rollbackScriptReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RollbackScript(ctx, req.token, req.id, req.Version); err != nil {
+			return nil, err
+		}
+
+		return apiutil.EmptyRes{StatusCode: http.StatusOK}, nil
+	}
+}
+
+func enableScriptEndpoint(svc rules.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(scriptReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.EnableScript(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return apiutil.EmptyRes{StatusCode: http.StatusNoContent}, nil
+	}
+}
+
+func disableScriptEndpoint(svc rules.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(scriptReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.DisableScript(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return apiutil.EmptyRes{StatusCode: http.StatusNoContent}, nil
+	}
+}
+
+func viewScriptStatsEndpoint(svc rules.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(scriptReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		stats, err := svc.ViewScriptStats(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return buildScriptStatsResponse(stats), nil
+	}
+}
+
 func assignScriptsEndpoint(svc rules.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request any) (any, error) {
 		req := request.(thingScriptsReq)
@@ -172,6 +269,36 @@ func unassignScriptsEndpoint(svc rules.Service) endpoint.Endpoint {
 	}
 }
 
+func enableThingScriptsEndpoint(svc rules.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(thingScriptsReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.EnableThingScripts(ctx, req.token, req.thingID, req.ScriptIDs...); err != nil {
+			return nil, err
+		}
+
+		return apiutil.EmptyRes{StatusCode: http.StatusNoContent}, nil
+	}
+}
+
+func disableThingScriptsEndpoint(svc rules.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(thingScriptsReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.DisableThingScripts(ctx, req.token, req.thingID, req.ScriptIDs...); err != nil {
+			return nil, err
+		}
+
+		return apiutil.EmptyRes{StatusCode: http.StatusNoContent}, nil
+	}
+}
+
 func listScriptRunsByThingEndpoint(svc rules.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request any) (any, error) {
 		req := request.(listScriptRunsByThingReq)
@@ -203,6 +330,102 @@ func removeScriptRunsEndpoint(svc rules.Service) endpoint.Endpoint {
 	}
 }
 
+func createModulesEndpoint(svc rules.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(createModulesReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		var reqModules []rules.LuaModule
+		for _, mReq := range req.Modules {
+			module := rules.LuaModule{
+				Name:        mReq.Name,
+				Description: mReq.Description,
+				Script:      mReq.Script,
+			}
+
+			reqModules = append(reqModules, module)
+		}
+
+		modules, err := svc.CreateModules(ctx, req.token, req.groupID, reqModules...)
+		if err != nil {
+			return nil, err
+		}
+
+		return buildModulesResponse(modules, true), nil
+	}
+}
+
+func listModulesByGroupEndpoint(svc rules.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(listModulesByGroupReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListModulesByGroup(ctx, req.token, req.groupID, req.pageMetadata)
+		if err != nil {
+			return nil, err
+		}
+
+		return buildModulesPageResponse(page, req.pageMetadata), nil
+	}
+}
+
+func viewModuleEndpoint(svc rules.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(moduleReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		module, err := svc.ViewModule(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return buildModuleResponse(module, false), nil
+	}
+}
+
+func updateModuleEndpoint(svc rules.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(updateModuleReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		module := rules.LuaModule{
+			ID:          req.id,
+			Name:        req.Name,
+			Description: req.Description,
+			Script:      req.Script,
+		}
+
+		if err := svc.UpdateModule(ctx, req.token, module); err != nil {
+			return nil, err
+		}
+
+		return moduleRes{updated: true}, nil
+	}
+}
+
+func removeModulesEndpoint(svc rules.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(removeModulesReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveModules(ctx, req.token, req.ModuleIDs...); err != nil {
+			return nil, err
+		}
+
+		return apiutil.EmptyRes{StatusCode: http.StatusNoContent}, nil
+	}
+}
+
 func buildScriptsResponse(scripts []rules.LuaScript, created bool) scriptsRes {
 	res := scriptsRes{Scripts: []scriptRes{}, created: created}
 
@@ -213,6 +436,10 @@ func buildScriptsResponse(scripts []rules.LuaScript, created bool) scriptsRes {
 			Name:        s.Name,
 			Script:      s.Script,
 			Description: s.Description,
+			Triggers:    s.Triggers,
+			Version:     s.Version,
+			Status:      s.Status,
+			ThingStatus: s.ThingStatus,
 		}
 		res.Scripts = append(res.Scripts, sr)
 	}
@@ -240,6 +467,10 @@ func buildScriptsPageResponse(page rules.LuaScriptsPage, pm rules.PageMetadata)
 			Name:        s.Name,
 			Script:      s.Script,
 			Description: s.Description,
+			Triggers:    s.Triggers,
+			Version:     s.Version,
+			Status:      s.Status,
+			ThingStatus: s.ThingStatus,
 		}
 		res.Scripts = append(res.Scripts, sr)
 	}
@@ -254,6 +485,9 @@ func buildScriptResponse(s rules.LuaScript, updated bool) scriptRes {
 		Name:        s.Name,
 		Script:      s.Script,
 		Description: s.Description,
+		Triggers:    s.Triggers,
+		Version:     s.Version,
+		Status:      s.Status,
 		updated:     updated,
 	}
 }
@@ -273,17 +507,128 @@ func buildScriptRunsPageResponse(page rules.ScriptRunsPage, pm rules.PageMetadat
 
 	for _, run := range page.Runs {
 		sr := scriptRunRes{
-			ID:         run.ID,
-			ScriptID:   run.ScriptID,
-			ThingID:    run.ThingID,
-			Logs:       run.Logs,
-			StartedAt:  run.StartedAt,
-			FinishedAt: run.FinishedAt,
-			Status:     run.Status,
-			Error:      run.Error,
+			ID:               run.ID,
+			ScriptID:         run.ScriptID,
+			ThingID:          run.ThingID,
+			Logs:             run.Logs,
+			StartedAt:        run.StartedAt,
+			FinishedAt:       run.FinishedAt,
+			Status:           run.Status,
+			Error:            run.Error,
+			Version:          run.Version,
+			InstructionCount: run.InstructionCount,
 		}
 		res.Runs = append(res.Runs, sr)
 	}
 
 	return res
 }
+
+func buildScriptVersionsPageResponse(page rules.ScriptVersionsPage, pm rules.PageMetadata) scriptVersionsPageRes {
+	res := scriptVersionsPageRes{
+		pageRes: pageRes{
+			Total:  page.Total,
+			Offset: pm.Offset,
+			Limit:  pm.Limit,
+		},
+		Versions: []scriptVersionRes{},
+	}
+
+	for _, v := range page.Versions {
+		res.Versions = append(res.Versions, scriptVersionRes{
+			Version:   v.Version,
+			Script:    v.Script,
+			CreatedAt: v.CreatedAt,
+		})
+	}
+
+	return res
+}
+
+func buildScriptStatsResponse(stats rules.ScriptStats) scriptStatsRes {
+	res := scriptStatsRes{
+		ScriptID:            stats.ScriptID,
+		Succeeded:           stats.Succeeded,
+		Failed:              stats.Failed,
+		AvgDurationMs:       float64(stats.AvgDuration) / float64(time.Millisecond),
+		AvgInstructionCount: stats.AvgInstructionCount,
+	}
+
+	if !stats.LastRunAt.IsZero() {
+		res.LastRunAt = &stats.LastRunAt
+	}
+
+	return res
+}
+
+func buildModulesResponse(modules []rules.LuaModule, created bool) modulesRes {
+	res := modulesRes{Modules: []moduleRes{}, created: created}
+
+	for _, m := range modules {
+		res.Modules = append(res.Modules, buildModuleResponse(m, false))
+	}
+
+	return res
+}
+
+func buildModulesPageResponse(page rules.LuaModulesPage, pm rules.PageMetadata) modulesPageRes {
+	res := modulesPageRes{
+		pageRes: pageRes{
+			Total:  page.Total,
+			Offset: pm.Offset,
+			Limit:  pm.Limit,
+			Ord:    pm.Order,
+			Dir:    pm.Dir,
+			Name:   pm.Name,
+		},
+		Modules: []moduleRes{},
+	}
+
+	for _, m := range page.Modules {
+		res.Modules = append(res.Modules, buildModuleResponse(m, false))
+	}
+
+	return res
+}
+
+func buildModuleResponse(m rules.LuaModule, updated bool) moduleRes {
+	return moduleRes{
+		ID:          m.ID,
+		GroupID:     m.GroupID,
+		Name:        m.Name,
+		Description: m.Description,
+		Script:      m.Script,
+		updated:     updated,
+	}
+}
+
+func buildTestScriptResponse(results []rules.ScriptTestResult) testScriptRes {
+	res := testScriptRes{Runs: []scriptTestRunRes{}}
+
+	for _, r := range results {
+		tr := scriptTestRunRes{
+			Status:           r.Status,
+			Logs:             r.Logs,
+			Error:            r.Error,
+			InstructionCount: r.InstructionCount,
+			Actions:          r.Actions,
+		}
+		if tr.Actions == nil {
+			tr.Actions = []rules.Action{}
+		}
+		res.Runs = append(res.Runs, tr)
+	}
+
+	return res
+}
+
+func normalizeTriggers(triggers []rules.Trigger) []rules.Trigger {
+	for i, t := range triggers {
+		if t.Scheduler != nil {
+			scheduler := cron.NormalizeTimezone(*t.Scheduler)
+			triggers[i].Scheduler = &scheduler
+		}
+	}
+
+	return triggers
+}
@@ -91,7 +91,7 @@ func newService() rules.Service {
 	idp := uuid.NewMock()
 	log := logger.NewMock()
 
-	return rules.New(rulesRepo, ths, pkgmocks.NewReadersClient(), pub, idp, log, true)
+	return rules.New(rulesRepo, ths, pkgmocks.NewReadersClient(), pkgmocks.NewShadowsClient(), pub, idp, log, rules.ScriptsConfig{Enabled: true})
 }
 
 func newHTTPServer(svc rules.Service) *httptest.Server {
@@ -160,6 +160,14 @@ func TestCreateScripts(t *testing.T) {
 			map[string]any{"name": "script-2", "script": "return 2"},
 		},
 	})
+	triggersBody := func(triggers ...any) string {
+		return toJSON(map[string]any{
+			"scripts": []any{
+				map[string]any{"name": scriptName, "script": scriptBody, "triggers": triggers},
+			},
+		})
+	}
+	minutely := map[string]any{"frequency": "minutely", "minute": 5}
 
 	cases := []struct {
 		desc        string
@@ -273,6 +281,46 @@ func TestCreateScripts(t *testing.T) {
 			status: http.StatusBadRequest,
 			size:   0,
 		},
+		{
+			desc:        "create script with triggers",
+			token:       token,
+			groupID:     groupID,
+			contentType: contentType,
+			body: triggersBody(
+				map[string]any{"type": rules.TriggerTypeMessage, "subtopic": "temperature"},
+				map[string]any{"type": rules.TriggerTypeAlarm},
+				map[string]any{"type": rules.TriggerTypeSchedule, "scheduler": minutely},
+			),
+			status: http.StatusCreated,
+			size:   1,
+		},
+		{
+			desc:        "create script with invalid trigger type",
+			token:       token,
+			groupID:     groupID,
+			contentType: contentType,
+			body:        triggersBody(map[string]any{"type": "invalid"}),
+			status:      http.StatusBadRequest,
+			size:        0,
+		},
+		{
+			desc:        "create script with schedule trigger without scheduler",
+			token:       token,
+			groupID:     groupID,
+			contentType: contentType,
+			body:        triggersBody(map[string]any{"type": rules.TriggerTypeSchedule}),
+			status:      http.StatusBadRequest,
+			size:        0,
+		},
+		{
+			desc:        "create script with message trigger with scheduler",
+			token:       token,
+			groupID:     groupID,
+			contentType: contentType,
+			body:        triggersBody(map[string]any{"type": rules.TriggerTypeMessage, "scheduler": minutely}),
+			status:      http.StatusBadRequest,
+			size:        0,
+		},
 	}
 
 	for _, tc := range cases {
@@ -355,6 +403,115 @@ func TestViewScript(t *testing.T) {
 	}
 }
 
+func TestTestScript(t *testing.T) {
+	svc := newService()
+	ts := newHTTPServer(svc)
+	defer ts.Close()
+
+	validMsg := map[string]any{
+		"publisher": thingID,
+		"payload":   map[string]any{"temperature": 35},
+	}
+
+	cases := []struct {
+		desc        string
+		auth        string
+		groupID     string
+		contentType string
+		body        any
+		status      int
+		runs        int
+	}{
+		{
+			desc:        "test script",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body:        map[string]any{"script": `mfx.log("ok") mfx.create_alarm(2)`, "message": validMsg},
+			status:      http.StatusOK,
+			runs:        1,
+		},
+		{
+			desc:        "test script against array payload",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body:        map[string]any{"script": scriptBody, "message": map[string]any{"payload": []map[string]any{{"temperature": 35}, {"temperature": 20}}}},
+			status:      http.StatusOK,
+			runs:        2,
+		},
+		{
+			desc:        "test script with runtime error",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body:        map[string]any{"script": `error("failed")`, "message": validMsg},
+			status:      http.StatusOK,
+			runs:        1,
+		},
+		{
+			desc:        "test empty script",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body:        map[string]any{"script": emptyValue, "message": validMsg},
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "test script without message payload",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body:        map[string]any{"script": scriptBody, "message": map[string]any{"publisher": thingID}},
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "test script with unknown publisher",
+			auth:        token,
+			groupID:     groupID,
+			contentType: contentType,
+			body:        map[string]any{"script": scriptBody, "message": map[string]any{"publisher": wrongValue, "payload": map[string]any{"temperature": 35}}},
+			status:      http.StatusNotFound,
+		},
+		{
+			desc:        "test script with invalid auth token",
+			auth:        wrongValue,
+			groupID:     groupID,
+			contentType: contentType,
+			body:        map[string]any{"script": scriptBody, "message": validMsg},
+			status:      http.StatusUnauthorized,
+		},
+		{
+			desc:        "test script with invalid content type",
+			auth:        token,
+			groupID:     groupID,
+			contentType: emptyValue,
+			body:        map[string]any{"script": scriptBody, "message": validMsg},
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/groups/%s/scripts/test", ts.URL, tc.groupID),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(toJSON(tc.body)),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+
+		var resBody struct {
+			Runs []map[string]any `json:"runs"`
+		}
+		json.NewDecoder(res.Body).Decode(&resBody)
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status %d got %d\n", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.runs, len(resBody.Runs), fmt.Sprintf("%s: expected %d runs got %d\n", tc.desc, tc.runs, len(resBody.Runs)))
+	}
+}
+
 func TestListScriptsByGroup(t *testing.T) {
 	svc := newService()
 	ts := newHTTPServer(svc)
@@ -1026,3 +1183,880 @@ func TestRemoveScriptRuns(t *testing.T) {
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status %d got %d\n", tc.desc, tc.status, res.StatusCode))
 	}
 }
+
+func TestListScriptVersions(t *testing.T) {
+	svc := newService()
+	ts := newHTTPServer(svc)
+	defer ts.Close()
+
+	sc := saveScripts(t, svc, 1)[0]
+	sc.Script = "return 2 + 2"
+	err := svc.UpdateScript(context.Background(), token, sc)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc     string
+		token    string
+		url      string
+		status   int
+		versions []uint64
+	}{
+		{
+			desc:     "list script versions",
+			token:    token,
+			url:      fmt.Sprintf("%s/scripts/%s/versions", ts.URL, sc.ID),
+			status:   http.StatusOK,
+			versions: []uint64{2, 1},
+		},
+		{
+			desc:     "list script versions with limit",
+			token:    token,
+			url:      fmt.Sprintf("%s/scripts/%s/versions?limit=1", ts.URL, sc.ID),
+			status:   http.StatusOK,
+			versions: []uint64{2},
+		},
+		{
+			desc:   "list script versions with limit exceeding max",
+			token:  token,
+			url:    fmt.Sprintf("%s/scripts/%s/versions?limit=201", ts.URL, sc.ID),
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "list versions of non-existing script",
+			token:  token,
+			url:    fmt.Sprintf("%s/scripts/%s/versions", ts.URL, wrongValue),
+			status: http.StatusNotFound,
+		},
+		{
+			desc:   "list script versions with wrong token",
+			token:  wrongValue,
+			url:    fmt.Sprintf("%s/scripts/%s/versions", ts.URL, sc.ID),
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    tc.url,
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status %d got %d\n", tc.desc, tc.status, res.StatusCode))
+
+		var body struct {
+			Versions []struct {
+				Version uint64 `json:"version"`
+			} `json:"versions"`
+		}
+		json.NewDecoder(res.Body).Decode(&body)
+
+		var versions []uint64
+		for _, v := range body.Versions {
+			versions = append(versions, v.Version)
+		}
+		assert.Equal(t, tc.versions, versions, fmt.Sprintf("%s: expected versions %v got %v\n", tc.desc, tc.versions, versions))
+	}
+}
+
+func TestRollbackScript(t *testing.T) {
+	svc := newService()
+	ts := newHTTPServer(svc)
+	defer ts.Close()
+
+	sc := saveScripts(t, svc, 1)[0]
+	original := sc.Script
+	sc.Script = "return 2 + 2"
+	err := svc.UpdateScript(context.Background(), token, sc)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc        string
+		token       string
+		id          string
+		contentType string
+		version     uint64
+		status      int
+	}{
+		{
+			desc:        "rollback script",
+			token:       token,
+			id:          sc.ID,
+			contentType: contentType,
+			version:     1,
+			status:      http.StatusOK,
+		},
+		{
+			desc:        "rollback script without version",
+			token:       token,
+			id:          sc.ID,
+			contentType: contentType,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "rollback script to non-existing version",
+			token:       token,
+			id:          sc.ID,
+			contentType: contentType,
+			version:     10,
+			status:      http.StatusNotFound,
+		},
+		{
+			desc:        "rollback non-existing script",
+			token:       token,
+			id:          wrongValue,
+			contentType: contentType,
+			version:     1,
+			status:      http.StatusNotFound,
+		},
+		{
+			desc:        "rollback script with wrong token",
+			token:       wrongValue,
+			id:          sc.ID,
+			contentType: contentType,
+			version:     1,
+			status:      http.StatusUnauthorized,
+		},
+		{
+			desc:        "rollback script without content type",
+			token:       token,
+			id:          sc.ID,
+			contentType: emptyValue,
+			version:     1,
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/scripts/%s/rollback", ts.URL, tc.id),
+			token:       tc.token,
+			contentType: tc.contentType,
+			body:        strings.NewReader(toJSON(map[string]uint64{"version": tc.version})),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status %d got %d\n", tc.desc, tc.status, res.StatusCode))
+	}
+
+	script, err := svc.ViewScript(context.Background(), token, sc.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, original, script.Script, fmt.Sprintf("expected script %s got %s", original, script.Script))
+	assert.Equal(t, uint64(3), script.Version, fmt.Sprintf("expected version 3 got %d", script.Version))
+}
+
+func TestEnableScript(t *testing.T) {
+	testChangeScriptStatus(t, "enable", rules.ScriptEnabledStatus)
+}
+
+func TestDisableScript(t *testing.T) {
+	testChangeScriptStatus(t, "disable", rules.ScriptDisabledStatus)
+}
+
+func testChangeScriptStatus(t *testing.T, action, status string) {
+	svc := newService()
+	ts := newHTTPServer(svc)
+	defer ts.Close()
+
+	sc := saveScripts(t, svc, 1)[0]
+
+	cases := []struct {
+		desc   string
+		token  string
+		id     string
+		status int
+	}{
+		{
+			desc:   fmt.Sprintf("%s script", action),
+			token:  token,
+			id:     sc.ID,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   fmt.Sprintf("%s non-existing script", action),
+			token:  token,
+			id:     wrongValue,
+			status: http.StatusNotFound,
+		},
+		{
+			desc:   fmt.Sprintf("%s script with wrong token", action),
+			token:  wrongValue,
+			id:     sc.ID,
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   fmt.Sprintf("%s script with empty token", action),
+			token:  emptyValue,
+			id:     sc.ID,
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodPost,
+			url:    fmt.Sprintf("%s/scripts/%s/%s", ts.URL, tc.id, action),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status %d got %d\n", tc.desc, tc.status, res.StatusCode))
+	}
+
+	script, err := svc.ViewScript(context.Background(), token, sc.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, status, script.Status, fmt.Sprintf("expected status %s got %s", status, script.Status))
+}
+
+func TestDisableThingScripts(t *testing.T) {
+	svc := newService()
+	ts := newHTTPServer(svc)
+	defer ts.Close()
+
+	saved := saveScripts(t, svc, 2)
+	err := svc.AssignScripts(context.Background(), token, thingID, saved[0].ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc        string
+		token       string
+		thingID     string
+		contentType string
+		ids         []string
+		status      int
+	}{
+		{
+			desc:        "disable scripts for thing",
+			token:       token,
+			thingID:     thingID,
+			contentType: contentType,
+			ids:         []string{saved[0].ID},
+			status:      http.StatusNoContent,
+		},
+		{
+			desc:        "disable unassigned scripts for thing",
+			token:       token,
+			thingID:     thingID,
+			contentType: contentType,
+			ids:         []string{saved[1].ID},
+			status:      http.StatusNotFound,
+		},
+		{
+			desc:        "disable scripts with empty list",
+			token:       token,
+			thingID:     thingID,
+			contentType: contentType,
+			ids:         []string{},
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "disable scripts for wrong thing ID",
+			token:       token,
+			thingID:     wrongValue,
+			contentType: contentType,
+			ids:         []string{saved[0].ID},
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "disable scripts with wrong token",
+			token:       wrongValue,
+			thingID:     thingID,
+			contentType: contentType,
+			ids:         []string{saved[0].ID},
+			status:      http.StatusUnauthorized,
+		},
+		{
+			desc:        "disable scripts without content type",
+			token:       token,
+			thingID:     thingID,
+			contentType: emptyValue,
+			ids:         []string{saved[0].ID},
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		body := toJSON(struct {
+			ScriptIDs []string `json:"script_ids"`
+		}{tc.ids})
+
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/things/%s/scripts/disable", ts.URL, tc.thingID),
+			token:       tc.token,
+			contentType: tc.contentType,
+			body:        strings.NewReader(body),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status %d got %d\n", tc.desc, tc.status, res.StatusCode))
+	}
+
+	page, err := svc.ListScriptsByThing(context.Background(), token, thingID, rules.PageMetadata{})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, rules.ScriptDisabledStatus, page.Scripts[0].ThingStatus, fmt.Sprintf("expected thing status %s got %s", rules.ScriptDisabledStatus, page.Scripts[0].ThingStatus))
+}
+
+func TestViewScriptStats(t *testing.T) {
+	svc := newService()
+	ts := newHTTPServer(svc)
+	defer ts.Close()
+
+	sc := saveScripts(t, svc, 1)[0]
+
+	cases := []struct {
+		desc   string
+		token  string
+		id     string
+		status int
+	}{
+		{
+			desc:   "view script stats",
+			token:  token,
+			id:     sc.ID,
+			status: http.StatusOK,
+		},
+		{
+			desc:   "view stats of non-existing script",
+			token:  token,
+			id:     wrongValue,
+			status: http.StatusNotFound,
+		},
+		{
+			desc:   "view script stats with wrong token",
+			token:  wrongValue,
+			id:     sc.ID,
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/scripts/%s/stats", ts.URL, tc.id),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status %d got %d\n", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+type moduleRes struct {
+	ID          string `json:"id"`
+	GroupID     string `json:"group_id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Script      string `json:"script,omitempty"`
+}
+
+type modulesRes struct {
+	Modules []moduleRes `json:"modules"`
+}
+
+type modulesPageRes struct {
+	Total   uint64      `json:"total"`
+	Offset  uint64      `json:"offset"`
+	Limit   uint64      `json:"limit"`
+	Modules []moduleRes `json:"modules"`
+}
+
+func saveModules(t *testing.T, svc rules.Service, n int) []rules.LuaModule {
+	t.Helper()
+	var saved []rules.LuaModule
+	for i := range n {
+		module := rules.LuaModule{
+			Name:   fmt.Sprintf("module_%d", i+1),
+			Script: fmt.Sprintf("return {variant = %d}", i+1),
+		}
+		modules, err := svc.CreateModules(context.Background(), token, groupID, module)
+		require.Nil(t, err, fmt.Sprintf("unexpected error saving module %d: %s", i+1, err))
+		saved = append(saved, modules...)
+	}
+	return saved
+}
+
+func TestCreateModules(t *testing.T) {
+	svc := newService()
+	ts := newHTTPServer(svc)
+	defer ts.Close()
+
+	modulesBody := func(modules ...any) string {
+		return toJSON(map[string]any{"modules": modules})
+	}
+	validModule := map[string]any{"name": "convert", "script": "return {}"}
+
+	cases := []struct {
+		desc        string
+		token       string
+		groupID     string
+		contentType string
+		body        string
+		status      int
+		size        int
+	}{
+		{
+			desc:        "create valid module",
+			token:       token,
+			groupID:     groupID,
+			contentType: contentType,
+			body:        modulesBody(validModule),
+			status:      http.StatusCreated,
+			size:        1,
+		},
+		{
+			desc:        "create multiple modules",
+			token:       token,
+			groupID:     groupID,
+			contentType: contentType,
+			body: modulesBody(
+				map[string]any{"name": "crc", "script": "return {}"},
+				map[string]any{"name": "vendor.parse", "script": "return {}", "description": "vendor payload parser"},
+			),
+			status: http.StatusCreated,
+			size:   2,
+		},
+		{
+			desc:        "create module with taken name",
+			token:       token,
+			groupID:     groupID,
+			contentType: contentType,
+			body:        modulesBody(validModule),
+			status:      http.StatusConflict,
+			size:        0,
+		},
+		{
+			desc:        "create module with empty token",
+			token:       emptyValue,
+			groupID:     groupID,
+			contentType: contentType,
+			body:        modulesBody(validModule),
+			status:      http.StatusUnauthorized,
+			size:        0,
+		},
+		{
+			desc:        "create module with wrong group ID",
+			token:       token,
+			groupID:     wrongValue,
+			contentType: contentType,
+			body:        modulesBody(map[string]any{"name": "other", "script": "return {}"}),
+			status:      http.StatusForbidden,
+			size:        0,
+		},
+		{
+			desc:        "create module without content type",
+			token:       token,
+			groupID:     groupID,
+			contentType: emptyValue,
+			body:        modulesBody(validModule),
+			status:      http.StatusUnsupportedMediaType,
+			size:        0,
+		},
+		{
+			desc:        "create module with malformed JSON",
+			token:       token,
+			groupID:     groupID,
+			contentType: contentType,
+			body:        "}{",
+			status:      http.StatusBadRequest,
+			size:        0,
+		},
+		{
+			desc:        "create module with empty list",
+			token:       token,
+			groupID:     groupID,
+			contentType: contentType,
+			body:        modulesBody(),
+			status:      http.StatusBadRequest,
+			size:        0,
+		},
+		{
+			desc:        "create module with missing name",
+			token:       token,
+			groupID:     groupID,
+			contentType: contentType,
+			body:        modulesBody(map[string]any{"script": "return {}"}),
+			status:      http.StatusBadRequest,
+			size:        0,
+		},
+		{
+			desc:        "create module with invalid name",
+			token:       token,
+			groupID:     groupID,
+			contentType: contentType,
+			body:        modulesBody(map[string]any{"name": "my-module", "script": "return {}"}),
+			status:      http.StatusBadRequest,
+			size:        0,
+		},
+		{
+			desc:        "create module with missing script content",
+			token:       token,
+			groupID:     groupID,
+			contentType: contentType,
+			body:        modulesBody(map[string]any{"name": "empty"}),
+			status:      http.StatusBadRequest,
+			size:        0,
+		},
+		{
+			desc:        "create module with script exceeding size limit",
+			token:       token,
+			groupID:     groupID,
+			contentType: contentType,
+			body:        modulesBody(map[string]any{"name": "large", "script": strings.Repeat("a", 65_536)}),
+			status:      http.StatusBadRequest,
+			size:        0,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/groups/%s/modules", ts.URL, tc.groupID),
+			contentType: tc.contentType,
+			token:       tc.token,
+			body:        strings.NewReader(tc.body),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+
+		var body modulesRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status %d got %d\n", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.size, len(body.Modules), fmt.Sprintf("%s: expected size %d got %d\n", tc.desc, tc.size, len(body.Modules)))
+	}
+}
+
+func TestListModulesByGroup(t *testing.T) {
+	svc := newService()
+	ts := newHTTPServer(svc)
+	defer ts.Close()
+
+	n := 10
+	saveModules(t, svc, n)
+
+	cases := []struct {
+		desc   string
+		token  string
+		url    string
+		status int
+		size   int
+	}{
+		{
+			desc:   "list modules by group",
+			token:  token,
+			url:    fmt.Sprintf("%s/groups/%s/modules?limit=%d&offset=0", ts.URL, groupID, n),
+			status: http.StatusOK,
+			size:   n,
+		},
+		{
+			desc:   "list modules by group with limit",
+			token:  token,
+			url:    fmt.Sprintf("%s/groups/%s/modules?limit=5&offset=0", ts.URL, groupID),
+			status: http.StatusOK,
+			size:   5,
+		},
+		{
+			desc:   "list modules by group with offset",
+			token:  token,
+			url:    fmt.Sprintf("%s/groups/%s/modules?limit=%d&offset=%d", ts.URL, groupID, n, n-1),
+			status: http.StatusOK,
+			size:   1,
+		},
+		{
+			desc:   "list modules by group with name",
+			token:  token,
+			url:    fmt.Sprintf("%s/groups/%s/modules?name=module_10", ts.URL, groupID),
+			status: http.StatusOK,
+			size:   1,
+		},
+		{
+			desc:   "list modules by group with limit exceeding max",
+			token:  token,
+			url:    fmt.Sprintf("%s/groups/%s/modules?limit=201", ts.URL, groupID),
+			status: http.StatusBadRequest,
+			size:   0,
+		},
+		{
+			desc:   "list modules by group with wrong group ID",
+			token:  token,
+			url:    fmt.Sprintf("%s/groups/%s/modules?limit=%d", ts.URL, wrongValue, n),
+			status: http.StatusForbidden,
+			size:   0,
+		},
+		{
+			desc:   "list modules by group with empty token",
+			token:  emptyValue,
+			url:    fmt.Sprintf("%s/groups/%s/modules?limit=%d", ts.URL, groupID, n),
+			status: http.StatusUnauthorized,
+			size:   0,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    tc.url,
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+
+		var page modulesPageRes
+		json.NewDecoder(res.Body).Decode(&page)
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status %d got %d\n", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.size, len(page.Modules), fmt.Sprintf("%s: expected size %d got %d\n", tc.desc, tc.size, len(page.Modules)))
+	}
+}
+
+func TestViewModule(t *testing.T) {
+	svc := newService()
+	ts := newHTTPServer(svc)
+	defer ts.Close()
+
+	saved := saveModules(t, svc, 1)
+	module := saved[0]
+
+	cases := []struct {
+		desc   string
+		token  string
+		id     string
+		status int
+		res    moduleRes
+	}{
+		{
+			desc:   "view existing module",
+			token:  token,
+			id:     module.ID,
+			status: http.StatusOK,
+			res:    moduleRes{ID: module.ID, GroupID: groupID, Name: module.Name, Script: module.Script},
+		},
+		{
+			desc:   "view module with empty token",
+			token:  emptyValue,
+			id:     module.ID,
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "view module with wrong token",
+			token:  wrongValue,
+			id:     module.ID,
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "view non-existing module",
+			token:  token,
+			id:     wrongValue,
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/modules/%s", ts.URL, tc.id),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+
+		var body moduleRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status %d got %d\n", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.res, body, fmt.Sprintf("%s: expected module %v got %v\n", tc.desc, tc.res, body))
+	}
+}
+
+func TestUpdateModule(t *testing.T) {
+	svc := newService()
+	ts := newHTTPServer(svc)
+	defer ts.Close()
+
+	saved := saveModules(t, svc, 2)
+	moduleID := saved[0].ID
+
+	updatedBody := toJSON(map[string]any{
+		"name":   "updated_module",
+		"script": "return {updated = true}",
+	})
+
+	cases := []struct {
+		desc        string
+		token       string
+		id          string
+		contentType string
+		body        string
+		status      int
+	}{
+		{
+			desc:        "update existing module",
+			token:       token,
+			id:          moduleID,
+			contentType: contentType,
+			body:        updatedBody,
+			status:      http.StatusOK,
+		},
+		{
+			desc:        "update module with taken name",
+			token:       token,
+			id:          moduleID,
+			contentType: contentType,
+			body:        toJSON(map[string]any{"name": saved[1].Name, "script": "return {}"}),
+			status:      http.StatusConflict,
+		},
+		{
+			desc:        "update module with empty token",
+			token:       emptyValue,
+			id:          moduleID,
+			contentType: contentType,
+			body:        updatedBody,
+			status:      http.StatusUnauthorized,
+		},
+		{
+			desc:        "update non-existing module",
+			token:       token,
+			id:          wrongValue,
+			contentType: contentType,
+			body:        updatedBody,
+			status:      http.StatusNotFound,
+		},
+		{
+			desc:        "update module without content type",
+			token:       token,
+			id:          moduleID,
+			contentType: emptyValue,
+			body:        updatedBody,
+			status:      http.StatusUnsupportedMediaType,
+		},
+		{
+			desc:        "update module with malformed JSON",
+			token:       token,
+			id:          moduleID,
+			contentType: contentType,
+			body:        "}{",
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "update module with invalid name",
+			token:       token,
+			id:          moduleID,
+			contentType: contentType,
+			body:        toJSON(map[string]any{"name": "1module", "script": "return {}"}),
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "update module with missing script content",
+			token:       token,
+			id:          moduleID,
+			contentType: contentType,
+			body:        toJSON(map[string]any{"name": "updated_module"}),
+			status:      http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPut,
+			url:         fmt.Sprintf("%s/modules/%s", ts.URL, tc.id),
+			contentType: tc.contentType,
+			token:       tc.token,
+			body:        strings.NewReader(tc.body),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status %d got %d\n", tc.desc, tc.status, res.StatusCode))
+	}
+
+	module, err := svc.ViewModule(context.Background(), token, moduleID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	assert.Equal(t, "updated_module", module.Name, fmt.Sprintf("expected name updated_module got %s", module.Name))
+	assert.Equal(t, groupID, module.GroupID, fmt.Sprintf("expected group id %s got %s", groupID, module.GroupID))
+}
+
+func TestRemoveModules(t *testing.T) {
+	svc := newService()
+	ts := newHTTPServer(svc)
+	defer ts.Close()
+
+	saved := saveModules(t, svc, 1)
+	moduleID := saved[0].ID
+
+	cases := []struct {
+		desc        string
+		token       string
+		contentType string
+		ids         []string
+		status      int
+	}{
+		{
+			desc:        "remove modules with empty token",
+			token:       emptyValue,
+			contentType: contentType,
+			ids:         []string{moduleID},
+			status:      http.StatusUnauthorized,
+		},
+		{
+			desc:        "remove existing modules",
+			token:       token,
+			contentType: contentType,
+			ids:         []string{moduleID},
+			status:      http.StatusNoContent,
+		},
+		{
+			desc:        "remove non-existing modules",
+			token:       token,
+			contentType: contentType,
+			ids:         []string{wrongValue},
+			status:      http.StatusNotFound,
+		},
+		{
+			desc:        "remove modules with empty list",
+			token:       token,
+			contentType: contentType,
+			ids:         []string{},
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "remove modules with empty ID",
+			token:       token,
+			contentType: contentType,
+			ids:         []string{emptyValue},
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "remove modules without content type",
+			token:       token,
+			contentType: emptyValue,
+			ids:         []string{moduleID},
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		body := toJSON(struct {
+			ModuleIDs []string `json:"module_ids"`
+		}{tc.ids})
+
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPatch,
+			url:         fmt.Sprintf("%s/modules", ts.URL),
+			token:       tc.token,
+			contentType: tc.contentType,
+			body:        strings.NewReader(body),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status %d got %d\n", tc.desc, tc.status, res.StatusCode))
+	}
+}
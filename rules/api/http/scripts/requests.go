@@ -4,6 +4,8 @@
 package scripts
 
 import (
+	"regexp"
+
 	"github.com/MainfluxLabs/mainflux/pkg/apiutil"
 	"github.com/MainfluxLabs/mainflux/pkg/errors"
 	"github.com/MainfluxLabs/mainflux/rules"
@@ -15,12 +17,56 @@ const (
 	maxLimitSize  = 200
 	maxNameSize   = 254
 	maxScriptSize = 65_535
+	maxTriggers   = 10
 )
 
+// moduleNameRegexp matches the names of Lua modules, which consist of identifiers separated by dots.
+var moduleNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
 type script struct {
-	Name        string `json:"name"`
-	Description string `json:"description,omitempty"`
-	Script      string `json:"script"`
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Script      string          `json:"script"`
+	Triggers    []rules.Trigger `json:"triggers,omitempty"`
+}
+
+func (s script) validate() error {
+	if s.Name == "" || len(s.Name) > maxNameSize {
+		return apiutil.ErrNameSize
+	}
+
+	if s.Script == "" {
+		return errors.ErrMalformedEntity
+	}
+
+	if len(s.Script) > maxScriptSize {
+		return rules.ErrScriptSize
+	}
+
+	return validateTriggers(s.Triggers)
+}
+
+func validateTriggers(triggers []rules.Trigger) error {
+	if len(triggers) > maxTriggers {
+		return apiutil.ErrInvalidTrigger
+	}
+
+	for _, t := range triggers {
+		switch t.Type {
+		case rules.TriggerTypeMessage, rules.TriggerTypeAlarm:
+			if t.Scheduler != nil {
+				return apiutil.ErrInvalidTrigger
+			}
+		case rules.TriggerTypeSchedule:
+			if t.Subtopic != "" || t.Scheduler == nil || !t.Scheduler.IsValid() {
+				return apiutil.ErrInvalidTrigger
+			}
+		default:
+			return apiutil.ErrInvalidTrigger
+		}
+	}
+
+	return nil
 }
 
 type createScriptsReq struct {
@@ -43,20 +89,39 @@ func (req createScriptsReq) validate() error {
 	}
 
 	for _, s := range req.Scripts {
-		if s.Name == "" || len(s.Name) > maxNameSize {
-			return apiutil.ErrNameSize
+		if err := s.validate(); err != nil {
+			return err
 		}
+	}
 
-		if s.Script == "" {
-			return errors.ErrMalformedEntity
-		}
+	return nil
+}
 
-		if len(s.Script) > maxScriptSize {
-			return rules.ErrScriptSize
-		}
+type testScriptReq struct {
+	token   string
+	groupID string
+	Script  string      `json:"script"`
+	Message api.Message `json:"message"`
+}
+
+func (req testScriptReq) validate() error {
+	if req.token == "" {
+		return apiutil.ErrBearerToken
 	}
 
-	return nil
+	if req.groupID == "" {
+		return apiutil.ErrMissingGroupID
+	}
+
+	if req.Script == "" {
+		return errors.ErrMalformedEntity
+	}
+
+	if len(req.Script) > maxScriptSize {
+		return rules.ErrScriptSize
+	}
+
+	return req.Message.Validate()
 }
 
 type listScriptsByThingReq struct {
@@ -127,19 +192,7 @@ func (req updateScriptReq) validate() error {
 		return apiutil.ErrMissingScriptID
 	}
 
-	if req.Name == "" || len(req.Name) > maxNameSize {
-		return apiutil.ErrNameSize
-	}
-
-	if req.Script == "" {
-		return errors.ErrMalformedEntity
-	}
-
-	if len(req.Script) > maxScriptSize {
-		return rules.ErrScriptSize
-	}
-
-	return nil
+	return req.script.validate()
 }
 
 type removeScriptsReq struct {
@@ -165,6 +218,46 @@ func (req removeScriptsReq) validate() error {
 	return nil
 }
 
+type listScriptVersionsReq struct {
+	token        string
+	id           string
+	pageMetadata rules.PageMetadata
+}
+
+func (req listScriptVersionsReq) validate() error {
+	if req.token == "" {
+		return apiutil.ErrBearerToken
+	}
+
+	if req.id == "" {
+		return apiutil.ErrMissingScriptID
+	}
+
+	return api.ValidatePageMetadata(req.pageMetadata, maxLimitSize, maxNameSize)
+}
+
+type rollbackScriptReq struct {
+	token   string
+	id      string
+	Version uint64 `json:"version"`
+}
+
+func (req rollbackScriptReq) validate() error {
+	if req.token == "" {
+		return apiutil.ErrBearerToken
+	}
+
+	if req.id == "" {
+		return apiutil.ErrMissingScriptID
+	}
+
+	if req.Version == 0 {
+		return apiutil.ErrMissingScriptVersion
+	}
+
+	return nil
+}
+
 type thingScriptsReq struct {
 	token     string
 	thingID   string
@@ -233,3 +326,129 @@ func (req removeScriptRunsReq) validate() error {
 
 	return nil
 }
+
+type module struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Script      string `json:"script"`
+}
+
+func (m module) validate() error {
+	if len(m.Name) > maxNameSize || !moduleNameRegexp.MatchString(m.Name) {
+		return apiutil.ErrInvalidModuleName
+	}
+
+	if m.Script == "" {
+		return errors.ErrMalformedEntity
+	}
+
+	if len(m.Script) > maxScriptSize {
+		return rules.ErrScriptSize
+	}
+
+	return nil
+}
+
+type createModulesReq struct {
+	token   string
+	groupID string
+	Modules []module `json:"modules"`
+}
+
+func (req createModulesReq) validate() error {
+	if req.token == "" {
+		return apiutil.ErrBearerToken
+	}
+
+	if req.groupID == "" {
+		return apiutil.ErrMissingGroupID
+	}
+
+	if len(req.Modules) < minLen {
+		return apiutil.ErrEmptyList
+	}
+
+	for _, m := range req.Modules {
+		if err := m.validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type listModulesByGroupReq struct {
+	token        string
+	groupID      string
+	pageMetadata rules.PageMetadata
+}
+
+func (req listModulesByGroupReq) validate() error {
+	if req.token == "" {
+		return apiutil.ErrBearerToken
+	}
+
+	if req.groupID == "" {
+		return apiutil.ErrMissingGroupID
+	}
+
+	return api.ValidatePageMetadata(req.pageMetadata, maxLimitSize, maxNameSize)
+}
+
+type moduleReq struct {
+	token string
+	id    string
+}
+
+func (req moduleReq) validate() error {
+	if req.token == "" {
+		return apiutil.ErrBearerToken
+	}
+
+	if req.id == "" {
+		return apiutil.ErrMissingModuleID
+	}
+
+	return nil
+}
+
+type updateModuleReq struct {
+	token string
+	id    string
+	module
+}
+
+func (req updateModuleReq) validate() error {
+	if req.token == "" {
+		return apiutil.ErrBearerToken
+	}
+
+	if req.id == "" {
+		return apiutil.ErrMissingModuleID
+	}
+
+	return req.module.validate()
+}
+
+type removeModulesReq struct {
+	token     string
+	ModuleIDs []string `json:"module_ids"`
+}
+
+func (req removeModulesReq) validate() error {
+	if req.token == "" {
+		return apiutil.ErrBearerToken
+	}
+
+	if len(req.ModuleIDs) < minLen {
+		return apiutil.ErrEmptyList
+	}
+
+	for _, id := range req.ModuleIDs {
+		if id == "" {
+			return apiutil.ErrMissingModuleID
+		}
+	}
+
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/MainfluxLabs/mainflux/pkg/apiutil"
+	"github.com/MainfluxLabs/mainflux/rules"
 )
 
 var (
@@ -17,6 +18,12 @@ var (
 	_ apiutil.Response = (*scriptsPageRes)(nil)
 	_ apiutil.Response = (*scriptRunRes)(nil)
 	_ apiutil.Response = (*scriptRunsPageRes)(nil)
+	_ apiutil.Response = (*testScriptRes)(nil)
+	_ apiutil.Response = (*scriptVersionsPageRes)(nil)
+	_ apiutil.Response = (*scriptStatsRes)(nil)
+	_ apiutil.Response = (*moduleRes)(nil)
+	_ apiutil.Response = (*modulesRes)(nil)
+	_ apiutil.Response = (*modulesPageRes)(nil)
 )
 
 type pageRes struct {
@@ -45,11 +52,15 @@ func (res thingIDsRes) Empty() bool {
 }
 
 type scriptRes struct {
-	ID          string `json:"id"`
-	GroupID     string `json:"group_id"`
-	Name        string `json:"name"`
-	Script      string `json:"script,omitempty"`
-	Description string `json:"description,omitempty"`
+	ID          string          `json:"id"`
+	GroupID     string          `json:"group_id"`
+	Name        string          `json:"name"`
+	Script      string          `json:"script,omitempty"`
+	Description string          `json:"description,omitempty"`
+	Triggers    []rules.Trigger `json:"triggers,omitempty"`
+	Version     uint64          `json:"version,omitempty"`
+	Status      string          `json:"status,omitempty"`
+	ThingStatus string          `json:"thing_status,omitempty"`
 	updated     bool
 }
 
@@ -104,14 +115,16 @@ func (res scriptsPageRes) Empty() bool {
 }
 
 type scriptRunRes struct {
-	ID         string    `json:"id"`
-	ScriptID   string    `json:"script_id"`
-	ThingID    string    `json:"thing_id"`
-	Logs       []string  `json:"logs"`
-	StartedAt  time.Time `json:"started_at"`
-	FinishedAt time.Time `json:"finished_at"`
-	Status     string    `json:"status"`
-	Error      string    `json:"error,omitempty"`
+	ID               string    `json:"id"`
+	ScriptID         string    `json:"script_id"`
+	ThingID          string    `json:"thing_id"`
+	Logs             []string  `json:"logs"`
+	StartedAt        time.Time `json:"started_at"`
+	FinishedAt       time.Time `json:"finished_at"`
+	Status           string    `json:"status"`
+	Error            string    `json:"error,omitempty"`
+	Version          uint64    `json:"version"`
+	InstructionCount uint      `json:"instruction_count"`
 }
 
 func (res scriptRunRes) Code() int {
@@ -142,3 +155,130 @@ func (res scriptRunsPageRes) Headers() map[string]string {
 func (res scriptRunsPageRes) Empty() bool {
 	return false
 }
+
+type scriptTestRunRes struct {
+	Status           string         `json:"status"`
+	Logs             []string       `json:"logs"`
+	Error            string         `json:"error,omitempty"`
+	InstructionCount uint           `json:"instruction_count"`
+	Actions          []rules.Action `json:"actions"`
+}
+
+type testScriptRes struct {
+	Runs []scriptTestRunRes `json:"runs"`
+}
+
+func (res testScriptRes) Code() int {
+	return http.StatusOK
+}
+
+func (res testScriptRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res testScriptRes) Empty() bool {
+	return false
+}
+
+type scriptVersionRes struct {
+	Version   uint64    `json:"version"`
+	Script    string    `json:"script"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type scriptVersionsPageRes struct {
+	pageRes
+	Versions []scriptVersionRes `json:"versions"`
+}
+
+func (res scriptVersionsPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res scriptVersionsPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res scriptVersionsPageRes) Empty() bool {
+	return false
+}
+
+type scriptStatsRes struct {
+	ScriptID            string     `json:"script_id"`
+	Succeeded           uint64     `json:"succeeded"`
+	Failed              uint64     `json:"failed"`
+	AvgDurationMs       float64    `json:"avg_duration_ms"`
+	AvgInstructionCount uint64     `json:"avg_instruction_count"`
+	LastRunAt           *time.Time `json:"last_run_at,omitempty"`
+}
+
+func (res scriptStatsRes) Code() int {
+	return http.StatusOK
+}
+
+func (res scriptStatsRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res scriptStatsRes) Empty() bool {
+	return false
+}
+
+type moduleRes struct {
+	ID          string `json:"id"`
+	GroupID     string `json:"group_id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Script      string `json:"script,omitempty"`
+	updated     bool
+}
+
+func (res moduleRes) Code() int {
+	return http.StatusOK
+}
+
+func (res moduleRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res moduleRes) Empty() bool {
+	return res.updated
+}
+
+type modulesRes struct {
+	Modules []moduleRes `json:"modules"`
+	created bool
+}
+
+func (res modulesRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
+func (res modulesRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res modulesRes) Empty() bool {
+	return false
+}
+
+type modulesPageRes struct {
+	pageRes
+	Modules []moduleRes `json:"modules"`
+}
+
+func (res modulesPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res modulesPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res modulesPageRes) Empty() bool {
+	return false
+}
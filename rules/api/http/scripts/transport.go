@@ -49,6 +49,16 @@ func MakeHandler(svc rules.Service, ac domain.AuthClient, mux *bone.Mux, tracer
 		opts...,
 	))
 
+	mux.Post("/groups/:id/scripts/test", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "test_script"),
+			withIdentity,
+		)(testScriptEndpoint(svc)),
+		decodeTestScript,
+		encodeResponse,
+		opts...,
+	))
+
 	mux.Get("/things/:id/scripts", kithttp.NewServer(
 		endpoint.Chain(
 			kitot.TraceServer(tracer, "list_scripts_by_thing"),
@@ -99,6 +109,56 @@ func MakeHandler(svc rules.Service, ac domain.AuthClient, mux *bone.Mux, tracer
 		opts...,
 	))
 
+	mux.Get("/scripts/:id/versions", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "list_script_versions"),
+			withIdentity,
+		)(listScriptVersionsEndpoint(svc)),
+		decodeListScriptVersions,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/scripts/:id/rollback", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "rollback_script"),
+			withIdentity,
+		)(rollbackScriptEndpoint(svc)),
+		decodeRollbackScript,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/scripts/:id/enable", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "enable_script"),
+			withIdentity,
+		)(enableScriptEndpoint(svc)),
+		decodeScriptReq,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/scripts/:id/disable", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "disable_script"),
+			withIdentity,
+		)(disableScriptEndpoint(svc)),
+		decodeScriptReq,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/scripts/:id/stats", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "view_script_stats"),
+			withIdentity,
+		)(viewScriptStatsEndpoint(svc)),
+		decodeScriptReq,
+		encodeResponse,
+		opts...,
+	))
+
 	mux.Patch("/scripts", kithttp.NewServer(
 		endpoint.Chain(
 			kitot.TraceServer(tracer, "remove_scripts"),
@@ -129,6 +189,26 @@ func MakeHandler(svc rules.Service, ac domain.AuthClient, mux *bone.Mux, tracer
 		opts...,
 	))
 
+	mux.Post("/things/:id/scripts/enable", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "enable_thing_scripts"),
+			withIdentity,
+		)(enableThingScriptsEndpoint(svc)),
+		decodeThingScripts,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/things/:id/scripts/disable", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "disable_thing_scripts"),
+			withIdentity,
+		)(disableThingScriptsEndpoint(svc)),
+		decodeThingScripts,
+		encodeResponse,
+		opts...,
+	))
+
 	mux.Get("/things/:id/runs", kithttp.NewServer(
 		endpoint.Chain(
 			kitot.TraceServer(tracer, "list_script_runs_by_thing"),
@@ -149,6 +229,56 @@ func MakeHandler(svc rules.Service, ac domain.AuthClient, mux *bone.Mux, tracer
 		opts...,
 	))
 
+	mux.Post("/groups/:id/modules", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "create_modules"),
+			withIdentity,
+		)(createModulesEndpoint(svc)),
+		decodeCreateModules,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/groups/:id/modules", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "list_modules_by_group"),
+			withIdentity,
+		)(listModulesByGroupEndpoint(svc)),
+		decodeListModulesByGroup,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/modules/:id", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "view_module"),
+			withIdentity,
+		)(viewModuleEndpoint(svc)),
+		decodeModuleReq,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Put("/modules/:id", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "update_module"),
+			withIdentity,
+		)(updateModuleEndpoint(svc)),
+		decodeUpdateModule,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Patch("/modules", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "remove_modules"),
+			withIdentity,
+		)(removeModulesEndpoint(svc)),
+		decodeRemoveModules,
+		encodeResponse,
+		opts...,
+	))
+
 	return mux
 }
 
@@ -169,6 +299,23 @@ func decodeCreateScripts(_ context.Context, r *http.Request) (any, error) {
 	return req, nil
 }
 
+func decodeTestScript(_ context.Context, r *http.Request) (any, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), apiutil.ContentTypeJSON) {
+		return nil, apiutil.ErrUnsupportedContentType
+	}
+
+	req := testScriptReq{
+		token:   apiutil.ExtractBearerToken(r),
+		groupID: bone.GetValue(r, apiutil.IDKey),
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
 func decodeListScriptsByThing(_ context.Context, r *http.Request) (any, error) {
 	base, err := apiutil.BuildPageMetadata(r)
 	if err != nil {
@@ -241,6 +388,41 @@ func decodeUpdateScript(_ context.Context, r *http.Request) (any, error) {
 	return req, nil
 }
 
+func decodeListScriptVersions(_ context.Context, r *http.Request) (any, error) {
+	base, err := apiutil.BuildPageMetadata(r)
+	if err != nil {
+		return nil, err
+	}
+
+	req := listScriptVersionsReq{
+		token: apiutil.ExtractBearerToken(r),
+		id:    bone.GetValue(r, apiutil.IDKey),
+		pageMetadata: rules.PageMetadata{
+			Offset: base.Offset,
+			Limit:  base.Limit,
+		},
+	}
+
+	return req, nil
+}
+
+func decodeRollbackScript(_ context.Context, r *http.Request) (any, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), apiutil.ContentTypeJSON) {
+		return nil, apiutil.ErrUnsupportedContentType
+	}
+
+	req := rollbackScriptReq{
+		token: apiutil.ExtractBearerToken(r),
+		id:    bone.GetValue(r, apiutil.IDKey),
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
 func decodeRemoveScripts(_ context.Context, r *http.Request) (any, error) {
 	if !strings.Contains(r.Header.Get("Content-Type"), apiutil.ContentTypeJSON) {
 		return nil, apiutil.ErrUnsupportedContentType
@@ -340,6 +522,91 @@ func decodeRemoveScriptRuns(_ context.Context, r *http.Request) (any, error) {
 	return req, nil
 }
 
+func decodeCreateModules(_ context.Context, r *http.Request) (any, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), apiutil.ContentTypeJSON) {
+		return nil, apiutil.ErrUnsupportedContentType
+	}
+
+	req := createModulesReq{
+		token:   apiutil.ExtractBearerToken(r),
+		groupID: bone.GetValue(r, apiutil.IDKey),
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeListModulesByGroup(_ context.Context, r *http.Request) (any, error) {
+	base, err := apiutil.BuildPageMetadata(r)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := apiutil.ReadStringQuery(r, apiutil.NameKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	req := listModulesByGroupReq{
+		token:   apiutil.ExtractBearerToken(r),
+		groupID: bone.GetValue(r, apiutil.IDKey),
+		pageMetadata: rules.PageMetadata{
+			Offset: base.Offset,
+			Limit:  base.Limit,
+			Order:  base.Order,
+			Dir:    base.Dir,
+			Name:   name,
+		},
+	}
+
+	return req, nil
+}
+
+func decodeModuleReq(_ context.Context, r *http.Request) (any, error) {
+	req := moduleReq{
+		token: apiutil.ExtractBearerToken(r),
+		id:    bone.GetValue(r, apiutil.IDKey),
+	}
+
+	return req, nil
+}
+
+func decodeUpdateModule(_ context.Context, r *http.Request) (any, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), apiutil.ContentTypeJSON) {
+		return nil, apiutil.ErrUnsupportedContentType
+	}
+
+	req := updateModuleReq{
+		token: apiutil.ExtractBearerToken(r),
+		id:    bone.GetValue(r, apiutil.IDKey),
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeRemoveModules(_ context.Context, r *http.Request) (any, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), apiutil.ContentTypeJSON) {
+		return nil, apiutil.ErrUnsupportedContentType
+	}
+
+	req := removeModulesReq{
+		token: apiutil.ExtractBearerToken(r),
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
 func encodeResponse(_ context.Context, w http.ResponseWriter, response any) error {
 	w.Header().Set("Content-Type", apiutil.ContentTypeJSON)
 
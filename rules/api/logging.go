@@ -176,6 +176,20 @@ func (lm loggingMiddleware) UnassignRulesFromThing(ctx context.Context, thingID
 	return lm.svc.UnassignRulesFromThing(ctx, thingID)
 }
 
+func (lm loggingMiddleware) TestRule(ctx context.Context, token, groupID string, rule rules.Rule, msg protomfx.Message) (_ rules.RuleTestResult, err error) {
+	defer func(begin time.Time) {
+		email := authn.EmailFromToken(token)
+		message := fmt.Sprintf("Method test_rule by user %s, group id %s took %s to complete", email, groupID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.TestRule(ctx, token, groupID, rule, msg)
+}
+
 func (lm loggingMiddleware) ConsumeMessage(subject string, msg protomfx.Message) (err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method consume_message took %s to complete", time.Since(begin))
@@ -202,6 +216,19 @@ func (lm loggingMiddleware) ConsumeAlarm(subject string, alarm protomfx.Alarm) (
 	return lm.svc.ConsumeAlarm(subject, alarm)
 }
 
+func (lm loggingMiddleware) TransformMessage(subject string, msg protomfx.Message) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method transform_message took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.TransformMessage(subject, msg)
+}
+
 func (lm loggingMiddleware) CreateScripts(ctx context.Context, token, groupID string, scripts ...rules.LuaScript) (_ []rules.LuaScript, err error) {
 	defer func(begin time.Time) {
 		email := authn.EmailFromToken(token)
@@ -383,3 +410,213 @@ func (lm loggingMiddleware) RemoveScriptRuns(ctx context.Context, token string,
 
 	return lm.svc.RemoveScriptRuns(ctx, token, ids...)
 }
+
+func (lm loggingMiddleware) TestScript(ctx context.Context, token, groupID string, script rules.LuaScript, msg protomfx.Message) (_ []rules.ScriptTestResult, err error) {
+	defer func(begin time.Time) {
+		email := authn.EmailFromToken(token)
+		message := fmt.Sprintf("Method test_script by user %s, group id %s took %s to complete", email, groupID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.TestScript(ctx, token, groupID, script, msg)
+}
+
+func (lm loggingMiddleware) CreateModules(ctx context.Context, token, groupID string, modules ...rules.LuaModule) (_ []rules.LuaModule, err error) {
+	defer func(begin time.Time) {
+		email := authn.EmailFromToken(token)
+		message := fmt.Sprintf("Method create_modules by user %s, group id %s took %s to complete", email, groupID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.CreateModules(ctx, token, groupID, modules...)
+}
+
+func (lm loggingMiddleware) ListModulesByGroup(ctx context.Context, token, groupID string, pm rules.PageMetadata) (_ rules.LuaModulesPage, err error) {
+	defer func(begin time.Time) {
+		email := authn.EmailFromToken(token)
+		message := fmt.Sprintf("Method list_modules_by_group by user %s, group id %s took %s to complete", email, groupID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListModulesByGroup(ctx, token, groupID, pm)
+}
+
+func (lm loggingMiddleware) ViewModule(ctx context.Context, token, id string) (_ rules.LuaModule, err error) {
+	defer func(begin time.Time) {
+		email := authn.EmailFromToken(token)
+		message := fmt.Sprintf("Method view_module by user %s, module id %s took %s to complete", email, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewModule(ctx, token, id)
+}
+
+func (lm loggingMiddleware) UpdateModule(ctx context.Context, token string, module rules.LuaModule) (err error) {
+	defer func(begin time.Time) {
+		email := authn.EmailFromToken(token)
+		message := fmt.Sprintf("Method update_module by user %s, module id %s took %s to complete", email, module.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.UpdateModule(ctx, token, module)
+}
+
+func (lm loggingMiddleware) RemoveModules(ctx context.Context, token string, ids ...string) (err error) {
+	defer func(begin time.Time) {
+		email := authn.EmailFromToken(token)
+		message := fmt.Sprintf("Method remove_modules by user %s, module ids %v took %s to complete", email, ids, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveModules(ctx, token, ids...)
+}
+
+func (lm loggingMiddleware) RemoveModulesByGroup(ctx context.Context, groupID string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_modules_by_group for group id %s took %s to complete", groupID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveModulesByGroup(ctx, groupID)
+}
+
+func (lm loggingMiddleware) LoadAndScheduleScripts(ctx context.Context) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method load_and_schedule_scripts took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.LoadAndScheduleScripts(ctx)
+}
+
+func (lm loggingMiddleware) ListScriptVersions(ctx context.Context, token, scriptID string, pm rules.PageMetadata) (_ rules.ScriptVersionsPage, err error) {
+	defer func(begin time.Time) {
+		email := authn.EmailFromToken(token)
+		message := fmt.Sprintf("Method list_script_versions by user %s, script id %s took %s to complete", email, scriptID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListScriptVersions(ctx, token, scriptID, pm)
+}
+
+func (lm loggingMiddleware) RollbackScript(ctx context.Context, token, scriptID string, version uint64) (err error) {
+	defer func(begin time.Time) {
+		email := authn.EmailFromToken(token)
+		message := fmt.Sprintf("Method rollback_script by user %s, script id %s and version %d took %s to complete", email, scriptID, version, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RollbackScript(ctx, token, scriptID, version)
+}
+
+func (lm loggingMiddleware) EnableScript(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		email := authn.EmailFromToken(token)
+		message := fmt.Sprintf("Method enable_script by user %s, script id %s took %s to complete", email, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.EnableScript(ctx, token, id)
+}
+
+func (lm loggingMiddleware) DisableScript(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		email := authn.EmailFromToken(token)
+		message := fmt.Sprintf("Method disable_script by user %s, script id %s took %s to complete", email, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.DisableScript(ctx, token, id)
+}
+
+func (lm loggingMiddleware) ViewScriptStats(ctx context.Context, token, id string) (_ rules.ScriptStats, err error) {
+	defer func(begin time.Time) {
+		email := authn.EmailFromToken(token)
+		message := fmt.Sprintf("Method view_script_stats by user %s, script id %s took %s to complete", email, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewScriptStats(ctx, token, id)
+}
+
+func (lm loggingMiddleware) EnableThingScripts(ctx context.Context, token, thingID string, scriptIDs ...string) (err error) {
+	defer func(begin time.Time) {
+		email := authn.EmailFromToken(token)
+		message := fmt.Sprintf("Method enable_thing_scripts by user %s, thing id %s and script ids %v took %s to complete", email, thingID, scriptIDs, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.EnableThingScripts(ctx, token, thingID, scriptIDs...)
+}
+
+func (lm loggingMiddleware) DisableThingScripts(ctx context.Context, token, thingID string, scriptIDs ...string) (err error) {
+	defer func(begin time.Time) {
+		email := authn.EmailFromToken(token)
+		message := fmt.Sprintf("Method disable_thing_scripts by user %s, thing id %s and script ids %v took %s to complete", email, thingID, scriptIDs, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.DisableThingScripts(ctx, token, thingID, scriptIDs...)
+}
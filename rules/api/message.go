@@ -0,0 +1,56 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/MainfluxLabs/mainflux/pkg/apiutil"
+	"github.com/MainfluxLabs/mainflux/pkg/messaging"
+	protomfx "github.com/MainfluxLabs/mainflux/pkg/proto"
+)
+
+// Message represents a sample message rules and scripts are tested against.
+type Message struct {
+	Publisher   string          `json:"publisher,omitempty"`
+	Subtopic    string          `json:"subtopic,omitempty"`
+	ContentType string          `json:"content_type,omitempty"`
+	Created     int64           `json:"created,omitempty"`
+	Payload     json.RawMessage `json:"payload"`
+}
+
+// Validate validates the sample message.
+func (m Message) Validate() error {
+	if len(m.Payload) == 0 {
+		return apiutil.ErrInvalidSampleMessage
+	}
+
+	switch m.ContentType {
+	case "", messaging.JSONContentType, messaging.SenMLContentType:
+		return nil
+	default:
+		return apiutil.ErrInvalidSampleMessage
+	}
+}
+
+// Proto converts the sample message to a protomfx.Message, defaulting to the JSON
+// content type and to the current time as the creation time.
+func (m Message) Proto() protomfx.Message {
+	msg := protomfx.Message{
+		Publisher:   m.Publisher,
+		Subtopic:    m.Subtopic,
+		ContentType: m.ContentType,
+		Created:     m.Created,
+		Payload:     m.Payload,
+	}
+	if msg.ContentType == "" {
+		msg.ContentType = messaging.JSONContentType
+	}
+	if msg.Created == 0 {
+		msg.Created = time.Now().UnixNano()
+	}
+
+	return msg
+}
@@ -126,6 +126,15 @@ func (ms metricsMiddleware) UnassignRulesFromThing(ctx context.Context, thingID
 	return ms.svc.UnassignRulesFromThing(ctx, thingID)
 }
 
+func (ms metricsMiddleware) TestRule(ctx context.Context, token, groupID string, rule rules.Rule, msg protomfx.Message) (rules.RuleTestResult, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "test_rule").Add(1)
+		ms.latency.With("method", "test_rule").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.TestRule(ctx, token, groupID, rule, msg)
+}
+
 func (ms metricsMiddleware) ConsumeMessage(subject string, msg protomfx.Message) error {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "consume_message").Add(1)
@@ -144,6 +153,15 @@ func (ms metricsMiddleware) ConsumeAlarm(subject string, alarm protomfx.Alarm) e
 	return ms.svc.ConsumeAlarm(subject, alarm)
 }
 
+func (ms metricsMiddleware) TransformMessage(subject string, msg protomfx.Message) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "transform_message").Add(1)
+		ms.latency.With("method", "transform_message").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.TransformMessage(subject, msg)
+}
+
 func (ms metricsMiddleware) CreateScripts(ctx context.Context, token, groupID string, scripts ...rules.LuaScript) ([]rules.LuaScript, error) {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "create_scripts").Add(1)
@@ -260,3 +278,138 @@ func (ms metricsMiddleware) RemoveScriptRuns(ctx context.Context, token string,
 
 	return ms.svc.RemoveScriptRuns(ctx, token, ids...)
 }
+
+func (ms metricsMiddleware) TestScript(ctx context.Context, token, groupID string, script rules.LuaScript, msg protomfx.Message) ([]rules.ScriptTestResult, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "test_script").Add(1)
+		ms.latency.With("method", "test_script").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.TestScript(ctx, token, groupID, script, msg)
+}
+
+func (ms metricsMiddleware) CreateModules(ctx context.Context, token, groupID string, modules ...rules.LuaModule) ([]rules.LuaModule, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "create_modules").Add(1)
+		ms.latency.With("method", "create_modules").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CreateModules(ctx, token, groupID, modules...)
+}
+
+func (ms metricsMiddleware) ListModulesByGroup(ctx context.Context, token, groupID string, pm rules.PageMetadata) (rules.LuaModulesPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_modules_by_group").Add(1)
+		ms.latency.With("method", "list_modules_by_group").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListModulesByGroup(ctx, token, groupID, pm)
+}
+
+func (ms metricsMiddleware) ViewModule(ctx context.Context, token, id string) (rules.LuaModule, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_module").Add(1)
+		ms.latency.With("method", "view_module").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewModule(ctx, token, id)
+}
+
+func (ms metricsMiddleware) UpdateModule(ctx context.Context, token string, module rules.LuaModule) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "update_module").Add(1)
+		ms.latency.With("method", "update_module").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UpdateModule(ctx, token, module)
+}
+
+func (ms metricsMiddleware) RemoveModules(ctx context.Context, token string, ids ...string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_modules").Add(1)
+		ms.latency.With("method", "remove_modules").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveModules(ctx, token, ids...)
+}
+
+func (ms metricsMiddleware) RemoveModulesByGroup(ctx context.Context, groupID string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_modules_by_group").Add(1)
+		ms.latency.With("method", "remove_modules_by_group").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveModulesByGroup(ctx, groupID)
+}
+
+func (ms metricsMiddleware) LoadAndScheduleScripts(ctx context.Context) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "load_and_schedule_scripts").Add(1)
+		ms.latency.With("method", "load_and_schedule_scripts").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.LoadAndScheduleScripts(ctx)
+}
+
+func (ms metricsMiddleware) ListScriptVersions(ctx context.Context, token, scriptID string, pm rules.PageMetadata) (rules.ScriptVersionsPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_script_versions").Add(1)
+		ms.latency.With("method", "list_script_versions").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListScriptVersions(ctx, token, scriptID, pm)
+}
+
+func (ms metricsMiddleware) RollbackScript(ctx context.Context, token, scriptID string, version uint64) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "rollback_script").Add(1)
+		ms.latency.With("method", "rollback_script").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RollbackScript(ctx, token, scriptID, version)
+}
+
+func (ms metricsMiddleware) EnableScript(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "enable_script").Add(1)
+		ms.latency.With("method", "enable_script").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.EnableScript(ctx, token, id)
+}
+
+func (ms metricsMiddleware) DisableScript(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "disable_script").Add(1)
+		ms.latency.With("method", "disable_script").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.DisableScript(ctx, token, id)
+}
+
+func (ms metricsMiddleware) ViewScriptStats(ctx context.Context, token, id string) (rules.ScriptStats, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_script_stats").Add(1)
+		ms.latency.With("method", "view_script_stats").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewScriptStats(ctx, token, id)
+}
+
+func (ms metricsMiddleware) EnableThingScripts(ctx context.Context, token, thingID string, scriptIDs ...string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "enable_thing_scripts").Add(1)
+		ms.latency.With("method", "enable_thing_scripts").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.EnableThingScripts(ctx, token, thingID, scriptIDs...)
+}
+
+func (ms metricsMiddleware) DisableThingScripts(ctx context.Context, token, thingID string, scriptIDs ...string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "disable_thing_scripts").Add(1)
+		ms.latency.With("method", "disable_thing_scripts").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.DisableThingScripts(ctx, token, thingID, scriptIDs...)
+}
@@ -4,10 +4,20 @@
 package api
 
 import (
+	"regexp"
+
 	"github.com/MainfluxLabs/mainflux/pkg/apiutil"
 	"github.com/MainfluxLabs/mainflux/rules"
 )
 
+const (
+	minConditions = 1
+	// maxConditionDuration is the maximum condition duration in seconds (7 days).
+	maxConditionDuration = 7 * 24 * 60 * 60
+	// maxExpressionDepth is the maximum nesting depth of condition groups.
+	maxExpressionDepth = 5
+)
+
 // ValidatePageMetadata validates the rules page metadata.
 func ValidatePageMetadata(pm rules.PageMetadata, maxLimitSize, maxNameSize int) error {
 	common := apiutil.PageMetadata{Offset: pm.Offset, Limit: pm.Limit, Order: pm.Order, Dir: pm.Dir}
@@ -29,3 +39,184 @@ func ValidatePageMetadata(pm rules.PageMetadata, maxLimitSize, maxNameSize int)
 
 	return nil
 }
+
+// ValidateConditions validates the rule conditions and the logical operator applied across them,
+// or the nested condition expression, which replaces the flat conditions when provided.
+func ValidateConditions(inputType string, conditions []rules.Condition, operator string, expression *rules.ConditionGroup) error {
+	if expression != nil {
+		if len(conditions) > 0 {
+			return apiutil.ErrInvalidConditionExpression
+		}
+		return validateConditionGroup(inputType, *expression, 1)
+	}
+
+	if len(conditions) < minConditions {
+		return apiutil.ErrEmptyList
+	}
+	for _, condition := range conditions {
+		if err := validateCondition(inputType, condition); err != nil {
+			return err
+		}
+	}
+	return validateOperator(len(conditions), operator)
+}
+
+func validateConditionGroup(inputType string, group rules.ConditionGroup, depth int) error {
+	if depth > maxExpressionDepth {
+		return apiutil.ErrInvalidConditionExpression
+	}
+
+	items := len(group.Conditions) + len(group.Groups)
+	if items < minConditions {
+		return apiutil.ErrInvalidConditionExpression
+	}
+
+	for _, condition := range group.Conditions {
+		if err := validateCondition(inputType, condition); err != nil {
+			return err
+		}
+	}
+	for _, subgroup := range group.Groups {
+		if err := validateConditionGroup(inputType, subgroup, depth+1); err != nil {
+			return err
+		}
+	}
+
+	return validateOperator(items, group.Operator)
+}
+
+func validateOperator(items int, operator string) error {
+	if items > minConditions && operator != rules.OperatorAND && operator != rules.OperatorOR {
+		return apiutil.ErrInvalidOperator
+	}
+	return nil
+}
+
+func validateCondition(inputType string, condition rules.Condition) error {
+	if condition.Field == "" {
+		return apiutil.ErrMissingConditionField
+	}
+
+	switch condition.Comparator {
+	case rules.ComparatorGT, rules.ComparatorLT, rules.ComparatorGTE, rules.ComparatorLTE:
+		if condition.Threshold == nil {
+			return apiutil.ErrMissingConditionThreshold
+		}
+	case rules.ComparatorEQ, rules.ComparatorNEQ:
+		if condition.Threshold == nil && !isScalar(condition.Value) {
+			return apiutil.ErrMissingConditionThreshold
+		}
+	case rules.ComparatorContains:
+		if !isScalar(condition.Value) {
+			return apiutil.ErrInvalidConditionValue
+		}
+	case rules.ComparatorIn, rules.ComparatorNotIn:
+		values, ok := condition.Value.([]any)
+		if !ok || len(values) == 0 {
+			return apiutil.ErrInvalidConditionValue
+		}
+		for _, v := range values {
+			if !isScalar(v) {
+				return apiutil.ErrInvalidConditionValue
+			}
+		}
+	case rules.ComparatorMatches:
+		pattern, ok := condition.Value.(string)
+		if !ok {
+			return apiutil.ErrInvalidConditionValue
+		}
+		if _, err := regexp.Compile(pattern); err != nil {
+			return apiutil.ErrInvalidConditionValue
+		}
+	default:
+		return apiutil.ErrInvalidConditionComparator
+	}
+
+	if err := validateAggregation(inputType, condition); err != nil {
+		return err
+	}
+
+	return validateStatefulCondition(inputType, condition)
+}
+
+func validateAggregation(inputType string, condition rules.Condition) error {
+	agg := condition.Aggregation
+	if agg == nil {
+		return nil
+	}
+
+	switch agg.Type {
+	case rules.AggregationAvg, rules.AggregationMin, rules.AggregationMax, rules.AggregationSum, rules.AggregationCount:
+	default:
+		return apiutil.ErrInvalidConditionAggregation
+	}
+
+	if agg.Count == 0 && agg.Period == 0 {
+		return apiutil.ErrInvalidConditionAggregation
+	}
+	if agg.Count > rules.MaxWindowSize || agg.Period > maxConditionDuration {
+		return apiutil.ErrInvalidConditionAggregation
+	}
+
+	// Aggregates are numeric and are compared against the threshold, as values rather than changes.
+	if condition.Threshold == nil || condition.Mode != rules.ConditionModeValue {
+		return apiutil.ErrInvalidConditionAggregation
+	}
+
+	// Alarm inputs aren't windowed, since alarms are evaluated one by one.
+	if inputType == rules.InputTypeAlarm {
+		return apiutil.ErrInvalidInputType
+	}
+
+	return nil
+}
+
+func validateStatefulCondition(inputType string, condition rules.Condition) error {
+	switch condition.Mode {
+	case rules.ConditionModeValue:
+	case rules.ConditionModeDelta, rules.ConditionModeRate:
+		if condition.Threshold == nil {
+			return apiutil.ErrInvalidConditionMode
+		}
+	default:
+		return apiutil.ErrInvalidConditionMode
+	}
+
+	if condition.Duration > maxConditionDuration {
+		return apiutil.ErrInvalidConditionDuration
+	}
+
+	if ct := condition.ClearThreshold; ct != nil {
+		if condition.Threshold == nil {
+			return apiutil.ErrInvalidClearThreshold
+		}
+		switch condition.Comparator {
+		case rules.ComparatorGT, rules.ComparatorGTE:
+			if *ct > *condition.Threshold {
+				return apiutil.ErrInvalidClearThreshold
+			}
+		case rules.ComparatorLT, rules.ComparatorLTE:
+			if *ct < *condition.Threshold {
+				return apiutil.ErrInvalidClearThreshold
+			}
+		default:
+			return apiutil.ErrInvalidClearThreshold
+		}
+	}
+
+	// Alarm inputs are evaluated without keeping state between alarms.
+	if inputType == rules.InputTypeAlarm && (condition.Mode != rules.ConditionModeValue || condition.Duration > 0 || condition.ClearThreshold != nil) {
+		return apiutil.ErrInvalidInputType
+	}
+
+	return nil
+}
+
+func isScalar(value any) bool {
+	switch value.(type) {
+	case string, bool, float64:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"text/template"
+
+	"github.com/MainfluxLabs/mainflux/pkg/domain"
+	"github.com/MainfluxLabs/mainflux/pkg/errors"
+	"github.com/MainfluxLabs/mainflux/pkg/lru"
+	"github.com/MainfluxLabs/mainflux/pkg/messaging/nats"
+	protomfx "github.com/MainfluxLabs/mainflux/pkg/proto"
+)
+
+const (
+	ActionTargetThing = "thing"
+	ActionTargetGroup = "group"
+)
+
+var (
+	// ErrInvalidActionTarget indicates a command or shadow action with an unknown target.
+	ErrInvalidActionTarget = errors.New("invalid action target")
+
+	// ErrInvalidShadowPatch indicates a shadow action payload that isn't a JSON object.
+	ErrInvalidShadowPatch = errors.New("shadow patch must be a JSON object")
+)
+
+// templateCacheSize is the number of parsed action payload templates kept by the service.
+const templateCacheSize = 1000
+
+// ParsePayloadTemplate parses a command payload template. Templates use the text/template
+// syntax and are executed against the thing_id, subtopic, created and payload of the
+// message that triggered the rule, e.g. {"fan": "on", "temp": {{.payload.temperature}}}.
+func ParsePayloadTemplate(text string) (*template.Template, error) {
+	return template.New("payload").Option("missingkey=error").Parse(text)
+}
+
+// renderPayload renders the action payload template, or returns the message payload if the action has none.
+// The parsed template is cached in templates, unless it's nil.
+func renderPayload(action Action, msg *protomfx.Message, payload any, templates *lru.Cache[string, *template.Template]) ([]byte, error) {
+	if action.Payload == "" {
+		return msg.Payload, nil
+	}
+
+	var t *template.Template
+	if templates != nil {
+		t, _ = templates.Get(action.Payload)
+	}
+	if t == nil {
+		parsed, err := ParsePayloadTemplate(action.Payload)
+		if err != nil {
+			return nil, err
+		}
+		t = parsed
+		if templates != nil {
+			templates.Add(action.Payload, t)
+		}
+	}
+
+	data := map[string]any{
+		"thing_id": msg.Publisher,
+		"subtopic": msg.Subtopic,
+		"created":  msg.Created,
+		"payload":  payload,
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// sendCommand publishes the command of a command action on behalf of the thing that
+// published msg, provided the thing is allowed to command the action target.
+func (rs *rulesService) sendCommand(ctx context.Context, msg *protomfx.Message, payload any, action Action) error {
+	cmdPayload, err := renderPayload(action, msg, payload, rs.templates)
+	if err != nil {
+		return err
+	}
+
+	return rs.publishCommand(ctx, msg, action, cmdPayload)
+}
+
+// publishCommand publishes the command payload to the target of the command action on behalf
+// of the thing that published msg, provided the thing is allowed to command the target.
+func (rs *rulesService) publishCommand(ctx context.Context, msg *protomfx.Message, action Action, cmdPayload []byte) error {
+	cmd := protomfx.Command{
+		Publisher: msg.Publisher,
+		Subtopic:  action.Subtopic,
+		Payload:   cmdPayload,
+		Protocol:  msg.Protocol,
+		Created:   msg.Created,
+	}
+
+	switch action.Target {
+	case ActionTargetThing:
+		if err := rs.things.CanThingCommand(ctx, domain.ThingCommandReq{PublisherID: msg.Publisher, RecipientID: action.ID}); err != nil {
+			return err
+		}
+
+		cmd.RecipientID = action.ID
+		return rs.pub.PublishCommand(nats.GetThingCommandsSubject(action.ID, cmd.Subtopic), cmd)
+	case ActionTargetGroup:
+		if err := rs.things.CanThingGroupCommand(ctx, domain.ThingGroupCommandReq{PublisherID: msg.Publisher, GroupID: action.ID}); err != nil {
+			return err
+		}
+
+		return rs.pub.PublishCommand(nats.GetGroupCommandsSubject(action.ID, cmd.Subtopic), cmd)
+	default:
+		return ErrInvalidActionTarget
+	}
+}
+
+// updateShadow publishes the desired state patch of a shadow action to the shadows service,
+// for the target thing or for every thing of the target group. Targets must belong to the rule group.
+func (rs *rulesService) updateShadow(ctx context.Context, rule Rule, msg *protomfx.Message, payload any, action Action) error {
+	patch, err := renderPayload(action, msg, payload, rs.templates)
+	if err != nil {
+		return err
+	}
+
+	var state map[string]any
+	if err := json.Unmarshal(patch, &state); err != nil || state == nil {
+		return ErrInvalidShadowPatch
+	}
+
+	var thingIDs []string
+	switch action.Target {
+	case ActionTargetThing:
+		grID, err := rs.things.GetGroupIDByThing(ctx, action.ID)
+		if err != nil {
+			return err
+		}
+		if grID != rule.GroupID {
+			return errors.ErrAuthorization
+		}
+		thingIDs = []string{action.ID}
+	case ActionTargetGroup:
+		if action.ID != rule.GroupID {
+			return errors.ErrAuthorization
+		}
+		if thingIDs, err = rs.things.GetThingIDsByGroup(ctx, action.ID); err != nil {
+			return err
+		}
+	default:
+		return ErrInvalidActionTarget
+	}
+
+	for _, thingID := range thingIDs {
+		cmd := protomfx.Command{
+			Publisher:   msg.Publisher,
+			RecipientID: thingID,
+			Subtopic:    action.Subtopic,
+			Payload:     patch,
+			Protocol:    msg.Protocol,
+			Created:     msg.Created,
+		}
+		if err := rs.pub.PublishCommand(nats.GetShadowsSubject(thingID), cmd); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/MainfluxLabs/mainflux/pkg/errors"
+	protomfx "github.com/MainfluxLabs/mainflux/pkg/proto"
+)
+
+// RuleTestResult represents the outcome of evaluating a rule against a sample message.
+type RuleTestResult struct {
+	// Triggered reports whether the rule condition expression is met.
+	Triggered bool
+	// Conditions lists the rule conditions, in depth-first order, along with whether they are met.
+	Conditions []ConditionResult
+	// Actions lists the actions the rule would trigger. It is empty if the rule isn't triggered.
+	Actions []ActionResult
+}
+
+// ConditionResult reports whether a rule condition is met by a sample message.
+type ConditionResult struct {
+	Condition Condition
+	Met       bool
+}
+
+// ActionResult describes an action a rule would trigger.
+type ActionResult struct {
+	Action Action
+	// Payload is the rendered command payload or shadow desired state patch.
+	Payload string
+	// Error describes why the action would fail, e.g. a template referencing a missing field.
+	Error string
+}
+
+// ScriptTestResult represents the outcome of running a Lua script against a sample message payload.
+type ScriptTestResult struct {
+	Status string
+	Logs   []string
+	// Error is the runtime error of the script, if any.
+	Error string
+	// InstructionCount is the number of executed Lua VM instructions,
+	// counted in steps of 10,000 instructions.
+	InstructionCount uint
+	// Actions lists the actions the script would trigger through the mfx API.
+	Actions []Action
+}
+
+// testRule evaluates the rule against msg. Stateful and aggregate conditions are evaluated
+// as if msg were the first message observed, and no state is persisted.
+func testRule(rule Rule, msg *protomfx.Message, payload any) (RuleTestResult, error) {
+	expr := rule.expression()
+	ev := evaluator{
+		contentType: msg.ContentType,
+		now:         msg.Created,
+		met:         make([]bool, countConditions(expr)),
+	}
+	if rule.IsStateful() {
+		ev.state = &RuleState{RuleID: rule.ID, ThingID: msg.Publisher}
+	}
+	if rule.hasAggregation() {
+		ev.windows = newWindowStore(windowCacheSize, 0)
+		ev.ruleID, ev.thingID, ev.subtopic = rule.ID, msg.Publisher, msg.Subtopic
+	}
+
+	triggered, err := processPayload(payload, expr, ev)
+	if err != nil {
+		return RuleTestResult{}, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	res := RuleTestResult{Triggered: triggered}
+	walkConditions(expr, func(idx int, c Condition) {
+		res.Conditions = append(res.Conditions, ConditionResult{Condition: c, Met: ev.met[idx]})
+	})
+
+	if !triggered {
+		return res, nil
+	}
+
+	for _, action := range rule.Actions {
+		ar := ActionResult{Action: action}
+		switch action.Type {
+		case ActionTypeCommand, ActionTypeShadow:
+			rendered, err := renderPayload(action, msg, payload, nil)
+			if err != nil {
+				ar.Error = err.Error()
+				break
+			}
+			ar.Payload = string(rendered)
+
+			var patch map[string]any
+			if action.Type == ActionTypeShadow && (json.Unmarshal(rendered, &patch) != nil || patch == nil) {
+				ar.Error = ErrInvalidShadowPatch.Error()
+			}
+		}
+		res.Actions = append(res.Actions, ar)
+	}
+
+	return res, nil
+}
+
+// testScript runs the script against every payload of msg. API functions with side effects
+// only record the actions they would trigger.
+func (rs *rulesService) testScript(script LuaScript, msg *protomfx.Message, payload any) ([]ScriptTestResult, error) {
+	payloads := rs.scriptPayloads(payload)
+	if len(payloads) == 0 {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, errors.ErrInvalidPayload)
+	}
+
+	var results []ScriptTestResult
+	for _, p := range payloads {
+		trigger := map[string]any{
+			"type":     TriggerTypeMessage,
+			"subtopic": msg.Subtopic,
+		}
+		env, err := NewLuaEnv(rs, &script, msg, p, trigger, luaAPISetStandard...)
+		if err != nil {
+			return nil, err
+		}
+		env.dryRun = true
+
+		run, err := env.execute()
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, ScriptTestResult{
+			Status:           run.Status,
+			Logs:             run.Logs,
+			Error:            run.Error,
+			InstructionCount: env.instructionCount,
+			Actions:          env.actions,
+		})
+	}
+
+	return results, nil
+}
+
+// validateSample ensures that the sample message publisher, if set, belongs to the group.
+func (rs *rulesService) validateSample(ctx context.Context, groupID string, msg protomfx.Message) error {
+	if msg.Publisher == "" {
+		return nil
+	}
+
+	grID, err := rs.things.GetGroupIDByThing(ctx, msg.Publisher)
+	if err != nil {
+		return err
+	}
+	if grID != groupID {
+		return errors.ErrAuthorization
+	}
+
+	return nil
+}
@@ -35,6 +35,9 @@ func (h *eventHandler) Handle(ctx context.Context, event events.Event) error {
 		if err := h.svc.RemoveScriptsByGroup(ctx, e.ID); err != nil {
 			return err
 		}
+		if err := h.svc.RemoveModulesByGroup(ctx, e.ID); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/MainfluxLabs/mainflux/pkg/domain"
 	"github.com/MainfluxLabs/mainflux/pkg/errors"
 	protomfx "github.com/MainfluxLabs/mainflux/pkg/proto"
 	"github.com/Shopify/go-lua"
@@ -15,6 +16,9 @@ const (
 	luaAPIRootTableName       = "mfx"
 	maxLuaInstructions        = 1_000_000
 	debugHookInstructionCount = 10_000
+	// scriptTimeout is the time limit of a script run, including the time spent in API
+	// functions, which bounds the time scripts hold up the consumption of messages.
+	scriptTimeout = 2 * time.Second
 
 	maxLogLineLength = 2_048
 	maxLogLineCount  = 256
@@ -30,6 +34,49 @@ type LuaScript struct {
 	Script      string
 	Name        string
 	Description string
+	// Triggers lists the events that run the script. If empty, the script runs on every message.
+	Triggers []Trigger
+	// Version is the number of the script version holding the current content.
+	Version uint64
+	// Status is either enabled or disabled. Disabled scripts don't run.
+	Status string
+	// ThingStatus is the status of the assignment of the script to a thing. It is set only
+	// when scripts are retrieved by thing.
+	ThingStatus string
+}
+
+// ScriptVersion represents an immutable revision of the content of a Lua script.
+type ScriptVersion struct {
+	ScriptID  string
+	Version   uint64
+	Script    string
+	CreatedAt time.Time
+}
+
+type ScriptVersionsPage struct {
+	Total    uint64
+	Versions []ScriptVersion
+}
+
+// ScriptStats aggregates the runs of a Lua script. The stats are kept when runs are removed.
+type ScriptStats struct {
+	ScriptID            string
+	Succeeded           uint64
+	Failed              uint64
+	AvgDuration         time.Duration
+	AvgInstructionCount uint64
+	LastRunAt           time.Time
+}
+
+// ScriptRunTotals holds the totals of runs of a Lua script which are added to its stats
+// at once, rather than run by run.
+type ScriptRunTotals struct {
+	ScriptID         string
+	Succeeded        uint64
+	Failed           uint64
+	Duration         time.Duration
+	InstructionCount uint64
+	LastRunAt        time.Time
 }
 
 type LuaScriptsPage struct {
@@ -46,6 +93,10 @@ type ScriptRun struct {
 	StartedAt  time.Time
 	FinishedAt time.Time
 	Status     string
+	// Version is the version of the script that ran.
+	Version uint64
+	// InstructionCount is the approximate number of Lua VM instructions executed during the run.
+	InstructionCount uint
 	// Human-readable string representing a runtime error during the execution of the lua script. May be an empty string in case of no error.
 	Error string
 	// Error value returned by lua.DoString
@@ -57,11 +108,25 @@ type ScriptRunsPage struct {
 	Runs  []ScriptRun
 }
 
+// ScriptValue represents a value that a Lua script keeps between its runs for a specific thing.
+type ScriptValue struct {
+	ScriptID string
+	ThingID  string
+	Key      string
+	// Value is the JSON encoded value.
+	Value []byte
+}
+
 const (
 	ScriptRunStatusSuccess = "success"
 	ScriptRunStatusFail    = "fail"
 )
 
+const (
+	ScriptEnabledStatus  = domain.EnabledStatusKey
+	ScriptDisabledStatus = domain.DisabledStatusKey
+)
+
 // luaEnv represents an isolated environment for executing a Lua script.
 type luaEnv struct {
 	service *rulesService
@@ -72,12 +137,28 @@ type luaEnv struct {
 
 	message protomfx.Message
 	payload map[string]any
+	// trigger describes the event that runs the script.
+	trigger map[string]any
 
 	// The total number of Lua VM instructions executed in the associated Lua State
 	instructionCount uint
+	// maxInstructions is the instruction limit of the script.
+	maxInstructions uint
+	// deadline is the time limit of the script, if set.
+	deadline time.Time
 
 	// Log messages produced by the script.
 	logs []string
+
+	// dryRun reports whether the script is being tested against a sample message, in which case
+	// API functions record the actions they would trigger in actions instead of performing them.
+	dryRun  bool
+	actions []Action
+	// values holds the values stored by the script during a dry run, with deleted keys mapped to nil.
+	values map[string][]byte
+
+	// loadingModules holds the names of the modules being loaded, used to detect require cycles.
+	loadingModules map[string]bool
 }
 
 // Exposes Golang functions to the Lua scripting API under the mfx table namespace.
@@ -87,6 +168,7 @@ func (env *luaEnv) bindLuaAPIFuncs(funcs ...luaAPIFunc) {
 
 	for _, apiFunc := range funcs {
 		luaFunc := apiFunc.fun(env)
+		luaFunc = env.toBudgetedLuaFunc(luaFunc, apiFunc.cost)
 		luaFunc = toInvocationLimitedLuaFunc(luaFunc, apiFunc.maxInvocations)
 
 		env.ls.PushGoFunction(luaFunc)
@@ -97,9 +179,22 @@ func (env *luaEnv) bindLuaAPIFuncs(funcs ...luaAPIFunc) {
 func (env *luaEnv) debugHook(ls *lua.State, record lua.Debug) {
 	env.instructionCount += debugHookInstructionCount
 
-	if env.instructionCount >= maxLuaInstructions {
+	if env.instructionCount >= env.maxInstructions {
 		lua.Errorf(ls, "instruction count limit exceeded")
 	}
+
+	if !env.deadline.IsZero() && time.Now().After(env.deadline) {
+		lua.Errorf(ls, "time limit exceeded")
+	}
+}
+
+// context returns a context bounded by the deadline of the script, so that the time API
+// functions spend waiting on other services counts against the time limit of the script.
+func (env *luaEnv) context() (context.Context, context.CancelFunc) {
+	if env.deadline.IsZero() {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithDeadline(context.Background(), env.deadline)
 }
 
 // luaAPIFunc represents a Golang function exposed to the Lua scripting API.
@@ -111,6 +206,10 @@ type luaAPIFunc struct {
 	// The maximum number of invocations allowed per Lua script.
 	maxInvocations uint
 
+	// The number of instructions charged to the instruction budget of the script per invocation,
+	// accounting for the work done outside of the Lua VM.
+	cost uint
+
 	// The function's identifier in the mfx Lua API namespace.
 	identifier string
 }
@@ -118,8 +217,11 @@ type luaAPIFunc struct {
 // Initializes and returns a new script environment associated with a specific Lua script and Mainflux message.
 // The following is made available to the Lua environment as part of an "mfx" table in the global namespace:
 //  1. The associated Message payload, subtopic, creation timestamp, and publisher ID
-//  2. API functions
-func NewLuaEnv(service *rulesService, script *LuaScript, message *protomfx.Message, payload map[string]any, functions ...luaAPIFunc) (*luaEnv, error) {
+//  2. The trigger that runs the script
+//  3. API functions
+//
+// The require function is made available in the global namespace, loading the Lua modules of the group of the script.
+func NewLuaEnv(service *rulesService, script *LuaScript, message *protomfx.Message, payload, trigger map[string]any, functions ...luaAPIFunc) (*luaEnv, error) {
 	state := lua.NewState()
 
 	env := &luaEnv{
@@ -127,8 +229,14 @@ func NewLuaEnv(service *rulesService, script *LuaScript, message *protomfx.Messa
 		script:  script,
 		message: *message,
 		payload: payload,
+		trigger: trigger,
 		ls:      state,
 		logs:    make([]string, 0, 16),
+		values:  make(map[string][]byte),
+
+		loadingModules:  make(map[string]bool),
+		maxInstructions: maxLuaInstructions,
+		deadline:        time.Now().Add(scriptTimeout),
 	}
 
 	lua.SetDebugHook(state, env.debugHook, lua.MaskCount, debugHookInstructionCount)
@@ -153,6 +261,9 @@ func NewLuaEnv(service *rulesService, script *LuaScript, message *protomfx.Messa
 	pushMfxMessageTable(state, message, payload)
 	state.SetField(-2, "message")
 
+	luautil.DeepPush(state, trigger)
+	state.SetField(-2, "trigger")
+
 	state.SetGlobal(luaAPIRootTableName)
 
 	// Bind all passed API functions
@@ -162,6 +273,13 @@ func NewLuaEnv(service *rulesService, script *LuaScript, message *protomfx.Messa
 	state.PushNil()
 	state.SetGlobal("print")
 
+	// Expose require(), which only loads the modules of the group of the script
+	state.NewTable()
+	state.SetField(lua.RegistryIndex, loadedModulesKey)
+
+	state.PushGoFunction(env.require)
+	state.SetGlobal("require")
+
 	return env, nil
 }
 
@@ -178,12 +296,14 @@ func (env *luaEnv) execute() (ScriptRun, error) {
 		ThingID:   env.message.Publisher,
 		StartedAt: time.Now(),
 		Status:    ScriptRunStatusSuccess,
+		Version:   env.script.Version,
 	}
 
 	err = lua.DoString(env.ls, env.script.Script)
 
 	run.FinishedAt = time.Now()
 	run.Logs = env.logs
+	run.InstructionCount = env.instructionCount
 	run.err = err
 
 	if run.err != nil {
@@ -234,32 +354,41 @@ func toInvocationLimitedLuaFunc(luaFunc lua.Function, maxInvocations uint) lua.F
 	return limitedFunc
 }
 
+// Returns luaFunc decorated with a function that charges `cost` instructions to the instruction
+// budget of the environment on every invocation, and raises an error once the budget is exceeded.
+func (env *luaEnv) toBudgetedLuaFunc(luaFunc lua.Function, cost uint) lua.Function {
+	if cost == 0 {
+		return luaFunc
+	}
+
+	return func(ls *lua.State) int {
+		env.instructionCount += cost
+		if env.instructionCount >= env.maxInstructions {
+			lua.Errorf(ls, "instruction count limit exceeded")
+		}
+
+		return luaFunc(ls)
+	}
+}
+
 // For each passed Lua script, create a new Lua environment and execute the associated script which processes the `msg` Mainflux message.
 // msg.Payload is ignored. parsedPayload represents the entire parsed payload of the associated message.
+// trigger describes the event that runs the scripts, and is exposed to them as the mfx.trigger table.
+// Scripts triggered by alarms can't create alarms.
 // For each of the passed Lua scripts:
 // - If parsedPayload represents a top-level JSON object, it is passed to the Lua script environment in its entirety.
 // - If parsedPayload represents a top-level JSON array, a separate Lua script environment is created for each of its children (which must be JSON objects).
-func (rs *rulesService) processLuaScripts(ctx context.Context, msg *protomfx.Message, parsedPayload any, scripts ...LuaScript) {
-	var payloads []map[string]any
+func (rs *rulesService) processLuaScripts(ctx context.Context, msg *protomfx.Message, parsedPayload any, trigger map[string]any, scripts ...LuaScript) {
+	payloads := rs.scriptPayloads(parsedPayload)
 
-	switch payload := parsedPayload.(type) {
-	case map[string]any:
-		payloads = append(payloads, payload)
-	case []any:
-		for _, subPayload := range payload {
-			subObjPayload, ok := subPayload.(map[string]any)
-			if !ok {
-				rs.logger.Error("malformed payload array")
-				continue
-			}
-
-			payloads = append(payloads, subObjPayload)
-		}
+	apiSet := luaAPISetStandard
+	if trigger["type"] == TriggerTypeAlarm {
+		apiSet = luaAPISetAlarm
 	}
 
 	for _, script := range scripts {
 		for _, subPayload := range payloads {
-			env, err := NewLuaEnv(rs, &script, msg, subPayload, luaAPISetStandard...)
+			env, err := NewLuaEnv(rs, &script, msg, subPayload, trigger, apiSet...)
 			if err != nil {
 				rs.logger.Error(fmt.Sprintf("creating lua environment for script with id %s failed with error: %v", script.ID, err))
 				continue
@@ -273,6 +402,30 @@ func (rs *rulesService) processLuaScripts(ctx context.Context, msg *protomfx.Mes
 			if _, err := rs.rules.SaveScriptRuns(ctx, run); err != nil {
 				rs.logger.Error(fmt.Sprintf("preserving script run to database failed with error: %v", err))
 			}
+			rs.scriptStats.add(run)
 		}
 	}
 }
+
+// scriptPayloads returns the payloads a script is executed against: the parsed payload if it is
+// a JSON object, or each of its children if it is a JSON array.
+func (rs *rulesService) scriptPayloads(parsedPayload any) []map[string]any {
+	var payloads []map[string]any
+
+	switch payload := parsedPayload.(type) {
+	case map[string]any:
+		payloads = append(payloads, payload)
+	case []any:
+		for _, subPayload := range payload {
+			subObjPayload, ok := subPayload.(map[string]any)
+			if !ok {
+				rs.logger.Error("malformed payload array")
+				continue
+			}
+
+			payloads = append(payloads, subObjPayload)
+		}
+	}
+
+	return payloads
+}
@@ -4,31 +4,119 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/MainfluxLabs/mainflux/pkg/apiutil"
+	"github.com/MainfluxLabs/mainflux/pkg/dbutil"
 	"github.com/MainfluxLabs/mainflux/pkg/domain"
+	"github.com/MainfluxLabs/mainflux/pkg/errors"
+	"github.com/MainfluxLabs/mainflux/pkg/messaging/nats"
 	protomfx "github.com/MainfluxLabs/mainflux/pkg/proto"
 	"github.com/MainfluxLabs/mainflux/pkg/transformers/senml"
 	"github.com/Shopify/go-lua"
 	luautil "github.com/Shopify/goluago/util"
 )
 
-var luaAPISetStandard = []luaAPIFunc{luaSMTPNotify, luaAlarmCreate, luaLog, luaReaderListMessages}
+var luaAPISetStandard = []luaAPIFunc{
+	luaSMTPNotify, luaSMPPNotify, luaWebhookNotify, luaAlarmCreate, luaLog, luaReaderListMessages,
+	luaValueGet, luaValueSet, luaValueDelete, luaShadowGet, luaShadowPatch, luaCommandSend, luaHTTPRequest,
+}
+
+// luaAPISetAlarm lists the API functions available to scripts triggered by alarms. It lacks create_alarm,
+// since alarms created by scripts would trigger the scripts again.
+var luaAPISetAlarm = []luaAPIFunc{
+	luaSMTPNotify, luaSMPPNotify, luaWebhookNotify, luaLog, luaReaderListMessages,
+	luaValueGet, luaValueSet, luaValueDelete, luaShadowGet, luaShadowPatch, luaCommandSend, luaHTTPRequest,
+}
+
+const (
+	// ActionTypeHTTP identifies HTTP requests sent by Lua scripts in the actions recorded
+	// by script test runs. It isn't a rule action type.
+	ActionTypeHTTP = "http"
+
+	maxValueKeyLength = 128
+	maxValueSize      = 16 * 1024
+	maxLuaValueDepth  = 32
+
+	// apiCallCost is the instruction cost of API functions accessing the database or other services.
+	apiCallCost = 10_000
+	// httpRequestCost is the instruction cost of outbound HTTP requests.
+	httpRequestCost = 50_000
+)
 
 // Trigger a registered SMTP notifier by ID.
 // Lua signature:
 // mfx.smtp_notify(smtp_notifier_id) (bool, msg)
 // On success it returns true, nil. On failure, it returns (false, <error_message>)
-var luaSMTPNotify = luaAPIFunc{
-	fun: func(env *luaEnv) lua.Function {
-		return func(ls *lua.State) int {
-			notifierID, ok := ls.ToString(1)
-			if !ok {
-				ls.PushBoolean(false)
+var luaSMTPNotify = luaNotifier(ActionTypeSMTP, subjectSMTP, "smtp_notify")
+
+// Trigger a registered SMPP notifier by ID.
+// Lua signature:
+// mfx.smpp_notify(smpp_notifier_id) (bool, msg)
+// On success it returns true, nil. On failure, it returns (false, <error_message>)
+var luaSMPPNotify = luaNotifier(ActionTypeSMPP, subjectSMPP, "smpp_notify")
+
+// luaNotifier returns an API function that publishes the current payload as a notification
+// to the notifier identified by its first argument.
+func luaNotifier(actionType, subjectPrefix, identifier string) luaAPIFunc {
+	return luaAPIFunc{
+		fun: func(env *luaEnv) lua.Function {
+			return func(ls *lua.State) int {
+				notifierID, ok := ls.ToString(1)
+				if !ok {
+					ls.PushBoolean(false)
+					return 1
+				}
+
+				// Marshal current payload, create a protomfx.Notification, and publish it to the notifier NATS subject
+				encodedPayload, err := json.Marshal(env.payload)
+				if err != nil {
+					ls.PushBoolean(false)
+					ls.PushString(err.Error())
+					return 2
+				}
+
+				if env.dryRun {
+					env.actions = append(env.actions, Action{Type: actionType, ID: notifierID})
+					ls.PushBoolean(true)
+					return 1
+				}
+
+				subject := fmt.Sprintf("%s.%s", subjectPrefix, notifierID)
+				notification := protomfx.Notification{
+					ThingId:  env.message.Publisher,
+					Subtopic: env.message.Subtopic,
+					Protocol: env.message.Protocol,
+					Payload:  encodedPayload,
+					Created:  env.message.Created,
+				}
+
+				if err := env.service.pub.PublishNotification(subject, notification); err != nil {
+					ls.PushBoolean(false)
+					ls.PushString(err.Error())
+					return 2
+				}
+
+				ls.PushBoolean(true)
 				return 1
 			}
+		},
+		identifier:     identifier,
+		maxInvocations: 2,
+	}
+}
+
+// Forward the current payload to a webhook of the thing by ID, regardless of the webhook filter.
+// Lua signature:
+// mfx.webhook_notify(webhook_id) (bool, msg)
+// On success it returns true, nil. On failure, it returns (false, <error_message>)
+var luaWebhookNotify = luaAPIFunc{
+	fun: func(env *luaEnv) lua.Function {
+		return func(ls *lua.State) int {
+			webhookID := lua.CheckString(ls, 1)
 
-			// Marshal current payload, create a protomfx.Notification, and publish it to the SMTP NATS subject
 			encodedPayload, err := json.Marshal(env.payload)
 			if err != nil {
 				ls.PushBoolean(false)
@@ -36,16 +124,20 @@ var luaSMTPNotify = luaAPIFunc{
 				return 2
 			}
 
-			subject := fmt.Sprintf("%s.%s", subjectSMTP, notifierID)
-			notification := protomfx.Notification{
-				ThingId:  env.message.Publisher,
-				Subtopic: env.message.Subtopic,
-				Protocol: env.message.Protocol,
-				Payload:  encodedPayload,
-				Created:  env.message.Created,
+			if env.dryRun {
+				env.actions = append(env.actions, Action{Type: ActionTypeWebhook, ID: webhookID})
+				ls.PushBoolean(true)
+				return 1
 			}
 
-			if err := env.service.pub.PublishNotification(subject, notification); err != nil {
+			webhook := protomfx.Webhook{
+				ThingId:   env.message.Publisher,
+				Payload:   encodedPayload,
+				Created:   env.message.Created,
+				Subtopic:  env.message.Subtopic,
+				WebhookId: webhookID,
+			}
+			if err := env.service.pub.PublishWebhook(subjectWebhooks, webhook); err != nil {
 				ls.PushBoolean(false)
 				ls.PushString(err.Error())
 				return 2
@@ -55,7 +147,7 @@ var luaSMTPNotify = luaAPIFunc{
 			return 1
 		}
 	},
-	identifier:     "smtp_notify",
+	identifier:     "webhook_notify",
 	maxInvocations: 2,
 }
 
@@ -73,6 +165,12 @@ var luaAlarmCreate = luaAPIFunc{
 				return 2
 			}
 
+			if env.dryRun {
+				env.actions = append(env.actions, Action{Type: ActionTypeAlarm, Level: int32(level)})
+				ls.PushBoolean(true)
+				return 1
+			}
+
 			subject := fmt.Sprintf("%s.%s", subjectAlarms, domain.AlarmOriginScript)
 			if err := env.service.pub.PublishAlarm(subject, protomfx.Alarm{
 				ThingId:  env.message.Publisher,
@@ -238,6 +336,481 @@ var luaReaderListMessages = luaAPIFunc{
 	identifier: "list_messages",
 }
 
+// Retrieve a value stored by the script for the thing that published the current message.
+// Lua signature:
+// mfx.kv_get(key) (value, msg)
+// On success it returns the value, or nil if no value is stored under the key. On failure, it returns (nil, <error_message>)
+var luaValueGet = luaAPIFunc{
+	fun: func(env *luaEnv) lua.Function {
+		return func(ls *lua.State) int {
+			key := lua.CheckString(ls, 1)
+
+			var data []byte
+			switch stored, ok := env.values[key]; {
+			case ok:
+				data = stored
+			case !env.dryRun:
+				value, err := env.service.rules.RetrieveScriptValue(context.Background(), env.script.ID, env.message.Publisher, key)
+				switch {
+				case errors.Contains(err, dbutil.ErrNotFound):
+				case err != nil:
+					ls.PushNil()
+					ls.PushString(err.Error())
+					return 2
+				default:
+					data = value.Value
+				}
+			}
+
+			if data == nil {
+				ls.PushNil()
+				return 1
+			}
+
+			var value any
+			if err := json.Unmarshal(data, &value); err != nil {
+				ls.PushNil()
+				ls.PushString(err.Error())
+				return 2
+			}
+
+			luautil.DeepPush(ls, value)
+			return 1
+		}
+	},
+	identifier:     "kv_get",
+	maxInvocations: 64,
+	cost:           apiCallCost,
+}
+
+// Store a value for the thing that published the current message, to be retrieved by later runs of the script.
+// The value may be a string, number, boolean or table, and its JSON encoding is limited to 16 KiB.
+// Lua signature:
+// mfx.kv_set(key, value) (bool, msg)
+// On success it returns true, nil. On failure, it returns (false, <error_message>)
+var luaValueSet = luaAPIFunc{
+	fun: func(env *luaEnv) lua.Function {
+		return func(ls *lua.State) int {
+			key := lua.CheckString(ls, 1)
+			lua.CheckAny(ls, 2)
+
+			if len(key) == 0 || len(key) > maxValueKeyLength {
+				ls.PushBoolean(false)
+				ls.PushString(fmt.Sprintf("key length must be between 1 and %d", maxValueKeyLength))
+				return 2
+			}
+
+			value, err := luaToGoValue(ls, 2, 0)
+			if err != nil {
+				ls.PushBoolean(false)
+				ls.PushString(err.Error())
+				return 2
+			}
+
+			data, err := json.Marshal(value)
+			if err != nil {
+				ls.PushBoolean(false)
+				ls.PushString(err.Error())
+				return 2
+			}
+
+			if len(data) > maxValueSize {
+				ls.PushBoolean(false)
+				ls.PushString("value exceeds maximum size")
+				return 2
+			}
+
+			if env.dryRun {
+				env.values[key] = data
+				ls.PushBoolean(true)
+				return 1
+			}
+
+			sv := ScriptValue{
+				ScriptID: env.script.ID,
+				ThingID:  env.message.Publisher,
+				Key:      key,
+				Value:    data,
+			}
+			if err := env.service.rules.SaveScriptValue(context.Background(), sv); err != nil {
+				ls.PushBoolean(false)
+				ls.PushString(err.Error())
+				return 2
+			}
+
+			ls.PushBoolean(true)
+			return 1
+		}
+	},
+	identifier:     "kv_set",
+	maxInvocations: 32,
+	cost:           apiCallCost,
+}
+
+// Remove a value stored by the script for the thing that published the current message.
+// Lua signature:
+// mfx.kv_delete(key) (bool, msg)
+// On success it returns true, nil. On failure, it returns (false, <error_message>)
+var luaValueDelete = luaAPIFunc{
+	fun: func(env *luaEnv) lua.Function {
+		return func(ls *lua.State) int {
+			key := lua.CheckString(ls, 1)
+
+			if env.dryRun {
+				env.values[key] = nil
+				ls.PushBoolean(true)
+				return 1
+			}
+
+			if err := env.service.rules.RemoveScriptValue(context.Background(), env.script.ID, env.message.Publisher, key); err != nil {
+				ls.PushBoolean(false)
+				ls.PushString(err.Error())
+				return 2
+			}
+
+			ls.PushBoolean(true)
+			return 1
+		}
+	},
+	identifier:     "kv_delete",
+	maxInvocations: 32,
+	cost:           apiCallCost,
+}
+
+// Retrieve a shadow of the thing that published the current message.
+// Lua signature:
+// mfx.get_shadow([name]) (shadow, msg)
+// Where name defaults to the default shadow.
+// On success it returns a table of the following structure: { desired = {...}, reported = {...}, delta = {...}, version = <number> }.
+// On failure, it returns (nil, <error_message>)
+var luaShadowGet = luaAPIFunc{
+	fun: func(env *luaEnv) lua.Function {
+		return func(ls *lua.State) int {
+			name := lua.OptString(ls, 1, "")
+
+			shadow, err := env.service.shadows.ViewShadow(context.Background(), env.message.Publisher, name)
+			if err != nil {
+				ls.PushNil()
+				ls.PushString(err.Error())
+				return 2
+			}
+
+			ls.NewTable()
+			for field, state := range map[string]map[string]any{
+				"desired":  shadow.Desired,
+				"reported": shadow.Reported,
+				"delta":    shadow.Delta,
+			} {
+				if state == nil {
+					state = map[string]any{}
+				}
+				luautil.DeepPush(ls, state)
+				ls.SetField(-2, field)
+			}
+			ls.PushNumber(float64(shadow.Version))
+			ls.SetField(-2, "version")
+
+			return 1
+		}
+	},
+	identifier:     "get_shadow",
+	maxInvocations: 4,
+	cost:           apiCallCost,
+}
+
+// Patch the desired state of a shadow of the thing that published the current message.
+// Lua signature:
+// mfx.patch_shadow(patch, [name]) (bool, msg)
+// Where patch is a table merged into the desired state, and name defaults to the default shadow.
+// On success it returns true, nil. On failure, it returns (false, <error_message>)
+var luaShadowPatch = luaAPIFunc{
+	fun: func(env *luaEnv) lua.Function {
+		return func(ls *lua.State) int {
+			lua.CheckType(ls, 1, lua.TypeTable)
+			name := lua.OptString(ls, 2, "")
+
+			patch, err := luaToJSON(ls, 1)
+			if err != nil {
+				ls.PushBoolean(false)
+				ls.PushString(err.Error())
+				return 2
+			}
+
+			var state map[string]any
+			if err := json.Unmarshal(patch, &state); err != nil || state == nil {
+				ls.PushBoolean(false)
+				ls.PushString(ErrInvalidShadowPatch.Error())
+				return 2
+			}
+
+			if env.dryRun {
+				env.actions = append(env.actions, Action{
+					Type:     ActionTypeShadow,
+					Target:   ActionTargetThing,
+					ID:       env.message.Publisher,
+					Subtopic: name,
+					Payload:  string(patch),
+				})
+				ls.PushBoolean(true)
+				return 1
+			}
+
+			cmd := protomfx.Command{
+				Publisher:   env.message.Publisher,
+				RecipientID: env.message.Publisher,
+				Subtopic:    name,
+				Payload:     patch,
+				Protocol:    env.message.Protocol,
+				Created:     env.message.Created,
+			}
+			if err := env.service.pub.PublishCommand(nats.GetShadowsSubject(env.message.Publisher), cmd); err != nil {
+				ls.PushBoolean(false)
+				ls.PushString(err.Error())
+				return 2
+			}
+
+			ls.PushBoolean(true)
+			return 1
+		}
+	},
+	identifier:     "patch_shadow",
+	maxInvocations: 4,
+	cost:           apiCallCost,
+}
+
+// Send a command on behalf of the thing that published the current message.
+// Lua signature:
+// mfx.send_command(target, id, payload, [subtopic]) (bool, msg)
+// Where target is one of "thing" or "group", and payload is a string or a table sent as JSON.
+// On success it returns true, nil. On failure, it returns (false, <error_message>)
+var luaCommandSend = luaAPIFunc{
+	fun: func(env *luaEnv) lua.Function {
+		return func(ls *lua.State) int {
+			target := lua.CheckString(ls, 1)
+			id := lua.CheckString(ls, 2)
+			lua.CheckAny(ls, 3)
+			subtopic := lua.OptString(ls, 4, "")
+
+			if target != ActionTargetThing && target != ActionTargetGroup {
+				lua.ArgumentError(ls, 1, `expected "thing" or "group"`)
+				panic("unreachable")
+			}
+
+			payload, err := luaToJSON(ls, 3)
+			if err != nil {
+				ls.PushBoolean(false)
+				ls.PushString(err.Error())
+				return 2
+			}
+
+			action := Action{
+				Type:     ActionTypeCommand,
+				Target:   target,
+				ID:       id,
+				Subtopic: subtopic,
+				Payload:  string(payload),
+			}
+
+			if env.dryRun {
+				env.actions = append(env.actions, action)
+				ls.PushBoolean(true)
+				return 1
+			}
+
+			if err := env.service.publishCommand(context.Background(), &env.message, action, payload); err != nil {
+				ls.PushBoolean(false)
+				ls.PushString(err.Error())
+				return 2
+			}
+
+			ls.PushBoolean(true)
+			return 1
+		}
+	},
+	identifier:     "send_command",
+	maxInvocations: 4,
+	cost:           apiCallCost,
+}
+
+// Send an HTTP request to a host allowed by the service configuration.
+// Lua signature:
+// mfx.http_request(method, url, [body], [headers]) (response, msg)
+// Where body is a string or a table sent as JSON, and headers is a table of strings.
+// On success it returns a table of the following structure: { status = <number>, body = <string>, headers = {...} },
+// with lowercase header names and the body truncated to 64 KiB. On failure, it returns (nil, <error_message>)
+var luaHTTPRequest = luaAPIFunc{
+	fun: func(env *luaEnv) lua.Function {
+		return func(ls *lua.State) int {
+			method := strings.ToUpper(lua.CheckString(ls, 1))
+			rawURL := lua.CheckString(ls, 2)
+
+			var body []byte
+			if !ls.IsNoneOrNil(3) {
+				b, err := luaToJSON(ls, 3)
+				if err != nil {
+					ls.PushNil()
+					ls.PushString(err.Error())
+					return 2
+				}
+				body = b
+			}
+
+			headers := map[string]string{}
+			if !ls.IsNoneOrNil(4) {
+				lua.CheckType(ls, 4, lua.TypeTable)
+				ls.PushNil()
+				for ls.Next(4) {
+					// Types are checked before converting, since converting a number key in place would break the iteration.
+					if ls.TypeOf(-2) != lua.TypeString || ls.TypeOf(-1) != lua.TypeString {
+						ls.Pop(2)
+						lua.ArgumentError(ls, 4, "headers must be a table of strings")
+						panic("unreachable")
+					}
+					k, _ := ls.ToString(-2)
+					v, _ := ls.ToString(-1)
+					headers[k] = v
+					ls.Pop(1)
+				}
+			}
+
+			if env.dryRun {
+				u, err := url.Parse(rawURL)
+				if err == nil {
+					err = env.service.scriptsHTTP.checkURL(u)
+				}
+				if err != nil {
+					ls.PushNil()
+					ls.PushString(err.Error())
+					return 2
+				}
+
+				env.actions = append(env.actions, Action{Type: ActionTypeHTTP, ID: rawURL, Payload: string(body)})
+				ls.PushNil()
+				ls.PushString("request not sent in test runs")
+				return 2
+			}
+
+			ctx, cancel := env.context()
+			defer cancel()
+
+			res, err := env.service.scriptsHTTP.do(ctx, method, rawURL, body, headers)
+			if err != nil {
+				ls.PushNil()
+				ls.PushString(err.Error())
+				return 2
+			}
+
+			ls.NewTable()
+			ls.PushInteger(res.StatusCode)
+			ls.SetField(-2, "status")
+			ls.PushString(string(res.Body))
+			ls.SetField(-2, "body")
+			ls.NewTable()
+			for k, v := range res.Headers {
+				ls.PushString(v)
+				ls.SetField(-2, k)
+			}
+			ls.SetField(-2, "headers")
+
+			return 1
+		}
+	},
+	identifier:     "http_request",
+	maxInvocations: 4,
+	cost:           httpRequestCost,
+}
+
+// luaToJSON returns the value at idx as JSON. Strings are returned as they are, and other values are encoded.
+func luaToJSON(ls *lua.State, idx int) ([]byte, error) {
+	if ls.TypeOf(idx) == lua.TypeString {
+		s, _ := ls.ToString(idx)
+		return []byte(s), nil
+	}
+
+	value, err := luaToGoValue(ls, idx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(value)
+}
+
+// luaToGoValue converts the Lua value at idx into a value that can be encoded as JSON. Tables with
+// consecutive integer keys starting from 1 are converted into slices, and other tables into maps.
+func luaToGoValue(ls *lua.State, idx, depth int) (any, error) {
+	if depth > maxLuaValueDepth {
+		return nil, errors.New("table nesting too deep")
+	}
+
+	switch ls.TypeOf(idx) {
+	case lua.TypeNil, lua.TypeNone:
+		return nil, nil
+	case lua.TypeBoolean:
+		return ls.ToBoolean(idx), nil
+	case lua.TypeNumber:
+		n, _ := ls.ToNumber(idx)
+		return n, nil
+	case lua.TypeString:
+		s, _ := ls.ToString(idx)
+		return s, nil
+	case lua.TypeTable:
+	default:
+		return nil, fmt.Errorf("unsupported value type %s", lua.TypeNameOf(ls, idx))
+	}
+
+	idx = ls.AbsIndex(idx)
+	length := ls.RawLength(idx)
+
+	count := 0
+	ls.PushNil()
+	for ls.Next(idx) {
+		count++
+		ls.Pop(1)
+	}
+
+	if length > 0 && count == length {
+		arr := make([]any, 0, length)
+		for i := 1; i <= length; i++ {
+			ls.RawGetInt(idx, i)
+			v, err := luaToGoValue(ls, -1, depth+1)
+			ls.Pop(1)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+		return arr, nil
+	}
+
+	obj := make(map[string]any, count)
+	ls.PushNil()
+	for ls.Next(idx) {
+		var key string
+		switch ls.TypeOf(-2) {
+		case lua.TypeString:
+			key, _ = ls.ToString(-2)
+		case lua.TypeNumber:
+			// Number keys are formatted without converting the key in place, which would break the iteration.
+			n, _ := ls.ToNumber(-2)
+			key = strconv.FormatFloat(n, 'f', -1, 64)
+		default:
+			typeName := lua.TypeNameOf(ls, -2)
+			ls.Pop(2)
+			return nil, fmt.Errorf("unsupported table key type %s", typeName)
+		}
+
+		v, err := luaToGoValue(ls, -1, depth+1)
+		if err != nil {
+			ls.Pop(2)
+			return nil, err
+		}
+		obj[key] = v
+		ls.Pop(1)
+	}
+
+	return obj, nil
+}
+
 // Helper that parses a Lua table at tblIdx into a domain.JSONPageMetadata. On error, namely if the value of a key
 // in the table doesn't match that of the associated struct field, an error is thrown in the Lua environment,
 // and the function doesn't return.
@@ -0,0 +1,123 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/MainfluxLabs/mainflux/pkg/errors"
+)
+
+const (
+	scriptHTTPTimeout      = 5 * time.Second
+	maxScriptHTTPRedirects = 3
+	// maxScriptHTTPResponseSize is the size limit of the response bodies returned to scripts.
+	maxScriptHTTPResponseSize = 64 * 1024
+)
+
+var (
+	// ErrHostNotAllowed indicates a script HTTP request to a host missing from the allowlist.
+	ErrHostNotAllowed = errors.New("host not allowed")
+
+	errInvalidURLScheme = errors.New("url scheme must be http or https")
+	errTooManyRedirects = errors.New("too many redirects")
+)
+
+// scriptHTTPResponse represents the response to an HTTP request sent by a script.
+type scriptHTTPResponse struct {
+	StatusCode int
+	// Body is the response body, truncated to 64 KiB.
+	Body    []byte
+	Headers map[string]string
+}
+
+// scriptHTTPClient sends the HTTP requests of Lua scripts to the allowlisted hosts.
+type scriptHTTPClient struct {
+	client    *http.Client
+	allowlist []string
+}
+
+func newScriptHTTPClient(allowlist []string) *scriptHTTPClient {
+	c := &scriptHTTPClient{allowlist: allowlist}
+	c.client = &http.Client{
+		Timeout: scriptHTTPTimeout,
+		// Redirects are followed only to allowlisted hosts.
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxScriptHTTPRedirects {
+				return errTooManyRedirects
+			}
+			return c.checkURL(req.URL)
+		},
+	}
+
+	return c
+}
+
+// checkURL ensures that the URL has an HTTP scheme and an allowlisted host.
+func (c *scriptHTTPClient) checkURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errInvalidURLScheme
+	}
+
+	host := strings.ToLower(u.Hostname())
+	allowed := slices.ContainsFunc(c.allowlist, func(pattern string) bool {
+		pattern = strings.ToLower(pattern)
+		if suffix, ok := strings.CutPrefix(pattern, "*"); ok {
+			return strings.HasPrefix(suffix, ".") && strings.HasSuffix(host, suffix)
+		}
+		return host == pattern
+	})
+	if !allowed {
+		return ErrHostNotAllowed
+	}
+
+	return nil
+}
+
+func (c *scriptHTTPClient) do(ctx context.Context, method, rawURL string, body []byte, headers map[string]string) (scriptHTTPResponse, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return scriptHTTPResponse{}, err
+	}
+	if err := c.checkURL(u); err != nil {
+		return scriptHTTPResponse{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return scriptHTTPResponse{}, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return scriptHTTPResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	resBody, err := io.ReadAll(io.LimitReader(resp.Body, maxScriptHTTPResponseSize))
+	if err != nil {
+		return scriptHTTPResponse{}, err
+	}
+
+	res := scriptHTTPResponse{
+		StatusCode: resp.StatusCode,
+		Body:       resBody,
+		Headers:    make(map[string]string, len(resp.Header)),
+	}
+	for k := range resp.Header {
+		res.Headers[strings.ToLower(k)] = resp.Header.Get(k)
+	}
+
+	return res, nil
+}
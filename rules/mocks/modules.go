@@ -0,0 +1,136 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/MainfluxLabs/mainflux/pkg/dbutil"
+	"github.com/MainfluxLabs/mainflux/rules"
+)
+
+func (rrm *ruleRepositoryMock) SaveModules(_ context.Context, modules ...rules.LuaModule) ([]rules.LuaModule, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	for _, m := range modules {
+		if rrm.moduleNameTaken(m) {
+			return []rules.LuaModule{}, dbutil.ErrConflict
+		}
+		rrm.modules[m.ID] = m
+	}
+
+	return modules, nil
+}
+
+func (rrm *ruleRepositoryMock) RetrieveModuleByID(_ context.Context, id string) (rules.LuaModule, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	m, ok := rrm.modules[id]
+	if !ok {
+		return rules.LuaModule{}, dbutil.ErrNotFound
+	}
+
+	return m, nil
+}
+
+func (rrm *ruleRepositoryMock) RetrieveModuleByName(_ context.Context, groupID, name string) (rules.LuaModule, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	for _, m := range rrm.modules {
+		if m.GroupID == groupID && m.Name == name {
+			return m, nil
+		}
+	}
+
+	return rules.LuaModule{}, dbutil.ErrNotFound
+}
+
+func (rrm *ruleRepositoryMock) RetrieveModulesByGroup(_ context.Context, groupID string, pm rules.PageMetadata) (rules.LuaModulesPage, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	var all []rules.LuaModule
+	for _, m := range rrm.modules {
+		if m.GroupID == groupID && strings.Contains(m.Name, pm.Name) {
+			all = append(all, m)
+		}
+	}
+
+	slices.SortFunc(all, func(a, b rules.LuaModule) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	items := all
+	if pm.Offset >= uint64(len(items)) {
+		items = nil
+	} else {
+		items = items[pm.Offset:]
+	}
+	if pm.Limit > 0 && pm.Limit < uint64(len(items)) {
+		items = items[:pm.Limit]
+	}
+
+	return rules.LuaModulesPage{
+		Total:   uint64(len(all)),
+		Modules: items,
+	}, nil
+}
+
+func (rrm *ruleRepositoryMock) UpdateModule(_ context.Context, module rules.LuaModule) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	m, ok := rrm.modules[module.ID]
+	if !ok {
+		return dbutil.ErrNotFound
+	}
+
+	module.GroupID = m.GroupID
+	if rrm.moduleNameTaken(module) {
+		return dbutil.ErrConflict
+	}
+	rrm.modules[module.ID] = module
+
+	return nil
+}
+
+func (rrm *ruleRepositoryMock) RemoveModules(_ context.Context, ids ...string) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	for _, id := range ids {
+		delete(rrm.modules, id)
+	}
+
+	return nil
+}
+
+func (rrm *ruleRepositoryMock) RemoveModulesByGroup(_ context.Context, groupID string) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	for id, m := range rrm.modules {
+		if m.GroupID == groupID {
+			delete(rrm.modules, id)
+		}
+	}
+
+	return nil
+}
+
+// moduleNameTaken reports whether another module of the group has the name of the module.
+func (rrm *ruleRepositoryMock) moduleNameTaken(module rules.LuaModule) bool {
+	for _, m := range rrm.modules {
+		if m.ID != module.ID && m.GroupID == module.GroupID && m.Name == module.Name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -4,6 +4,8 @@
 package mocks
 
 import (
+	"sync"
+
 	"github.com/MainfluxLabs/mainflux/pkg/messaging"
 	protomfx "github.com/MainfluxLabs/mainflux/pkg/proto"
 	"github.com/MainfluxLabs/mainflux/rules"
@@ -12,7 +14,13 @@ import (
 var _ rules.Publisher = (*mockPublisher)(nil)
 
 type mockPublisher struct {
-	fail bool
+	mu       sync.Mutex
+	fail     bool
+	alarms   []protomfx.Alarm
+	cleared  []protomfx.Alarm
+	commands []protomfx.Command
+	webhooks []protomfx.Webhook
+	messages []protomfx.Message
 }
 
 // NewPublisher returns a mock Publisher that succeeds by default.
@@ -25,10 +33,96 @@ func NewFailingPublisher() rules.Publisher {
 	return &mockPublisher{fail: true}
 }
 
-func (ps *mockPublisher) PublishAlarm(string, protomfx.Alarm) error {
+// PublishedAlarms returns the alarms raised through a mock Publisher.
+func PublishedAlarms(pub rules.Publisher) []protomfx.Alarm {
+	ps, ok := pub.(*mockPublisher)
+	if !ok {
+		return nil
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	return append([]protomfx.Alarm{}, ps.alarms...)
+}
+
+// ClearedAlarms returns the alarm clearances published through a mock Publisher.
+func ClearedAlarms(pub rules.Publisher) []protomfx.Alarm {
+	ps, ok := pub.(*mockPublisher)
+	if !ok {
+		return nil
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	return append([]protomfx.Alarm{}, ps.cleared...)
+}
+
+// PublishedCommands returns the commands published through a mock Publisher.
+func PublishedCommands(pub rules.Publisher) []protomfx.Command {
+	ps, ok := pub.(*mockPublisher)
+	if !ok {
+		return nil
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	return append([]protomfx.Command{}, ps.commands...)
+}
+
+// PublishedWebhooks returns the webhook messages published through a mock Publisher.
+func PublishedWebhooks(pub rules.Publisher) []protomfx.Webhook {
+	ps, ok := pub.(*mockPublisher)
+	if !ok {
+		return nil
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	return append([]protomfx.Webhook{}, ps.webhooks...)
+}
+
+// PublishedMessages returns the messages published through a mock Publisher.
+func PublishedMessages(pub rules.Publisher) []protomfx.Message {
+	ps, ok := pub.(*mockPublisher)
+	if !ok {
+		return nil
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	return append([]protomfx.Message{}, ps.messages...)
+}
+
+func (ps *mockPublisher) Publish(_ string, msg protomfx.Message) error {
 	if ps.fail {
 		return messaging.ErrPublishMessage
 	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.messages = append(ps.messages, msg)
+
+	return nil
+}
+
+func (ps *mockPublisher) PublishAlarm(_ string, alarm protomfx.Alarm) error {
+	if ps.fail {
+		return messaging.ErrPublishMessage
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if alarm.Cleared {
+		ps.cleared = append(ps.cleared, alarm)
+		return nil
+	}
+	ps.alarms = append(ps.alarms, alarm)
+
 	return nil
 }
 
@@ -39,9 +133,26 @@ func (ps *mockPublisher) PublishNotification(string, protomfx.Notification) erro
 	return nil
 }
 
-func (ps *mockPublisher) PublishWebhook(string, protomfx.Webhook) error {
+func (ps *mockPublisher) PublishWebhook(_ string, webhook protomfx.Webhook) error {
 	if ps.fail {
 		return messaging.ErrPublishMessage
 	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.webhooks = append(ps.webhooks, webhook)
+
+	return nil
+}
+
+func (ps *mockPublisher) PublishCommand(_ string, cmd protomfx.Command) error {
+	if ps.fail {
+		return messaging.ErrPublishMessage
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.commands = append(ps.commands, cmd)
+
 	return nil
 }
@@ -7,6 +7,7 @@ import (
 	"context"
 	"slices"
 	"sync"
+	"time"
 
 	"github.com/MainfluxLabs/mainflux/pkg/dbutil"
 	"github.com/MainfluxLabs/mainflux/pkg/uuid"
@@ -21,7 +22,14 @@ type ruleRepositoryMock struct {
 	ruleAssignments   map[string][]string // thingID -> []ruleID
 	scripts           map[string]rules.LuaScript
 	scriptAssignments map[string][]string // thingID -> []scriptID
+	disabledScripts   map[string]bool     // thingID+scriptID -> disabled
+	scriptVersions    map[string][]rules.ScriptVersion
+	scriptStats       map[string]scriptStats
 	scriptRuns        map[string]rules.ScriptRun
+	scheduledRuns     map[string]time.Time         // scriptID.trigger -> scheduled time of the last claimed run
+	states            map[string]rules.RuleState   // ruleID+thingID+subtopic -> state
+	scriptValues      map[string]rules.ScriptValue // scriptID+thingID+key -> value
+	modules           map[string]rules.LuaModule
 }
 
 // NewRuleRepository creates in-memory rule repository used for testing.
@@ -31,7 +39,14 @@ func NewRuleRepository() rules.Repository {
 		ruleAssignments:   make(map[string][]string),
 		scripts:           make(map[string]rules.LuaScript),
 		scriptAssignments: make(map[string][]string),
+		disabledScripts:   make(map[string]bool),
+		scriptVersions:    make(map[string][]rules.ScriptVersion),
+		scriptStats:       make(map[string]scriptStats),
 		scriptRuns:        make(map[string]rules.ScriptRun),
+		scheduledRuns:     make(map[string]time.Time),
+		states:            make(map[string]rules.RuleState),
+		scriptValues:      make(map[string]rules.ScriptValue),
+		modules:           make(map[string]rules.LuaModule),
 	}
 }
 
@@ -217,6 +232,47 @@ func (rrm *ruleRepositoryMock) UnassignRulesFromThing(_ context.Context, thingID
 	return nil
 }
 
+func (rrm *ruleRepositoryMock) RetrieveState(_ context.Context, ruleID, thingID, subtopic string) (rules.RuleState, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	st, ok := rrm.states[ruleID+thingID+subtopic]
+	if !ok {
+		return rules.RuleState{RuleID: ruleID, ThingID: thingID, Subtopic: subtopic}, nil
+	}
+
+	st.Conditions = slices.Clone(st.Conditions)
+
+	return st, nil
+}
+
+func (rrm *ruleRepositoryMock) SaveState(_ context.Context, state rules.RuleState) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	if _, ok := rrm.rules[state.RuleID]; !ok {
+		return dbutil.ErrNotFound
+	}
+
+	state.Conditions = slices.Clone(state.Conditions)
+	rrm.states[state.RuleID+state.ThingID+state.Subtopic] = state
+
+	return nil
+}
+
+func (rrm *ruleRepositoryMock) RemoveStates(_ context.Context, ruleID string) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	for key, st := range rrm.states {
+		if st.RuleID == ruleID {
+			delete(rrm.states, key)
+		}
+	}
+
+	return nil
+}
+
 func (rrm *ruleRepositoryMock) unassignThingsFromRule(ruleID string) {
 	for thingID, ruleIDs := range rrm.ruleAssignments {
 		var filtered []string
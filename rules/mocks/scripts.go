@@ -5,6 +5,10 @@ package mocks
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/MainfluxLabs/mainflux/pkg/dbutil"
 	"github.com/MainfluxLabs/mainflux/pkg/uuid"
@@ -17,6 +21,7 @@ func (rrm *ruleRepositoryMock) SaveScripts(_ context.Context, scripts ...rules.L
 
 	for _, s := range scripts {
 		rrm.scripts[s.ID] = s
+		rrm.scriptVersions[s.ID] = []rules.ScriptVersion{{ScriptID: s.ID, Version: s.Version, Script: s.Script, CreatedAt: time.Now()}}
 	}
 
 	return scripts, nil
@@ -49,6 +54,15 @@ func (rrm *ruleRepositoryMock) RetrieveScriptsByThing(_ context.Context, thingID
 		if !ok {
 			continue
 		}
+
+		s.ThingStatus = rules.ScriptEnabledStatus
+		if rrm.disabledScripts[thingID+sID] {
+			s.ThingStatus = rules.ScriptDisabledStatus
+		}
+		if pm.Status != "" && (s.Status != pm.Status || s.ThingStatus != pm.Status) {
+			continue
+		}
+
 		all = append(all, s)
 		id := uuid.ParseID(s.ID)
 		if pm.Limit == 0 || (id >= first && id < last) {
@@ -86,13 +100,35 @@ func (rrm *ruleRepositoryMock) RetrieveScriptsByGroup(_ context.Context, groupID
 	}, nil
 }
 
-func (rrm *ruleRepositoryMock) RetrieveThingIDsByScript(_ context.Context, scriptID string) ([]string, error) {
+func (rrm *ruleRepositoryMock) RetrieveScheduledScripts(_ context.Context) ([]rules.LuaScript, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	var scripts []rules.LuaScript
+	for _, s := range rrm.scripts {
+		for _, t := range s.Triggers {
+			if t.Type == rules.TriggerTypeSchedule {
+				scripts = append(scripts, s)
+				break
+			}
+		}
+	}
+
+	return scripts, nil
+}
+
+func (rrm *ruleRepositoryMock) RetrieveThingIDsByScript(_ context.Context, scriptID, status string) ([]string, error) {
 	rrm.mu.Lock()
 	defer rrm.mu.Unlock()
 
 	var thingIDs []string
 	for thingID, sIDs := range rrm.scriptAssignments {
 		for _, sID := range sIDs {
+			disabled := rrm.disabledScripts[thingID+sID]
+			if status == rules.ScriptEnabledStatus && disabled || status == rules.ScriptDisabledStatus && !disabled {
+				continue
+			}
+
 			if sID == scriptID {
 				thingIDs = append(thingIDs, thingID)
 				break
@@ -107,21 +143,93 @@ func (rrm *ruleRepositoryMock) UpdateScript(_ context.Context, script rules.LuaS
 	rrm.mu.Lock()
 	defer rrm.mu.Unlock()
 
-	if _, ok := rrm.scripts[script.ID]; !ok {
+	existing, ok := rrm.scripts[script.ID]
+	if !ok {
 		return dbutil.ErrNotFound
 	}
 
+	script.GroupID = existing.GroupID
+	script.Status = existing.Status
+	script.Version = existing.Version
+	if script.Script != existing.Script {
+		script.Version++
+		rrm.scriptVersions[script.ID] = append(rrm.scriptVersions[script.ID], rules.ScriptVersion{
+			ScriptID:  script.ID,
+			Version:   script.Version,
+			Script:    script.Script,
+			CreatedAt: time.Now(),
+		})
+	}
 	rrm.scripts[script.ID] = script
 
 	return nil
 }
 
+func (rrm *ruleRepositoryMock) UpdateScriptStatus(_ context.Context, id, status string) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	s, ok := rrm.scripts[id]
+	if !ok {
+		return dbutil.ErrNotFound
+	}
+
+	s.Status = status
+	rrm.scripts[id] = s
+
+	return nil
+}
+
+func (rrm *ruleRepositoryMock) RetrieveScriptVersions(_ context.Context, scriptID string, pm rules.PageMetadata) (rules.ScriptVersionsPage, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	all := rrm.scriptVersions[scriptID]
+
+	// Versions are listed from the latest one.
+	var items []rules.ScriptVersion
+	for i := len(all) - 1; i >= 0; i-- {
+		items = append(items, all[i])
+	}
+
+	start := min(pm.Offset, uint64(len(items)))
+	end := uint64(len(items))
+	if pm.Limit > 0 {
+		end = min(start+pm.Limit, end)
+	}
+
+	return rules.ScriptVersionsPage{
+		Total:    uint64(len(all)),
+		Versions: items[start:end],
+	}, nil
+}
+
+func (rrm *ruleRepositoryMock) RetrieveScriptVersion(_ context.Context, scriptID string, version uint64) (rules.ScriptVersion, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	for _, v := range rrm.scriptVersions[scriptID] {
+		if v.Version == version {
+			return v, nil
+		}
+	}
+
+	return rules.ScriptVersion{}, dbutil.ErrNotFound
+}
+
 func (rrm *ruleRepositoryMock) RemoveScripts(_ context.Context, ids ...string) error {
 	rrm.mu.Lock()
 	defer rrm.mu.Unlock()
 
 	for _, id := range ids {
 		delete(rrm.scripts, id)
+		delete(rrm.scriptVersions, id)
+		delete(rrm.scriptStats, id)
+		for key := range rrm.scheduledRuns {
+			if strings.HasPrefix(key, id+".") {
+				delete(rrm.scheduledRuns, key)
+			}
+		}
 	}
 
 	return nil
@@ -175,6 +283,10 @@ func (rrm *ruleRepositoryMock) UnassignScripts(_ context.Context, thingID string
 	}
 	rrm.scriptAssignments[thingID] = remaining
 
+	for id := range remove {
+		delete(rrm.disabledScripts, thingID+id)
+	}
+
 	return nil
 }
 
@@ -182,11 +294,29 @@ func (rrm *ruleRepositoryMock) UnassignScriptsFromThing(_ context.Context, thing
 	rrm.mu.Lock()
 	defer rrm.mu.Unlock()
 
+	for _, id := range rrm.scriptAssignments[thingID] {
+		delete(rrm.disabledScripts, thingID+id)
+	}
 	delete(rrm.scriptAssignments, thingID)
 
 	return nil
 }
 
+func (rrm *ruleRepositoryMock) UpdateThingScriptsStatus(_ context.Context, thingID, status string, scriptIDs ...string) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	for _, id := range scriptIDs {
+		if !slices.Contains(rrm.scriptAssignments[thingID], id) {
+			return dbutil.ErrNotFound
+		}
+
+		rrm.disabledScripts[thingID+id] = status == rules.ScriptDisabledStatus
+	}
+
+	return nil
+}
+
 func (rrm *ruleRepositoryMock) SaveScriptRuns(_ context.Context, runs ...rules.ScriptRun) ([]rules.ScriptRun, error) {
 	rrm.mu.Lock()
 	defer rrm.mu.Unlock()
@@ -244,3 +374,130 @@ func (rrm *ruleRepositoryMock) RemoveScriptRuns(_ context.Context, ids ...string
 
 	return nil
 }
+
+func (rrm *ruleRepositoryMock) RetrieveScriptValue(_ context.Context, scriptID, thingID, key string) (rules.ScriptValue, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	v, ok := rrm.scriptValues[scriptID+thingID+key]
+	if !ok {
+		return rules.ScriptValue{}, dbutil.ErrNotFound
+	}
+
+	return v, nil
+}
+
+func (rrm *ruleRepositoryMock) SaveScriptValue(_ context.Context, value rules.ScriptValue) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	rrm.scriptValues[value.ScriptID+value.ThingID+value.Key] = value
+
+	return nil
+}
+
+func (rrm *ruleRepositoryMock) RemoveScriptValue(_ context.Context, scriptID, thingID, key string) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	delete(rrm.scriptValues, scriptID+thingID+key)
+
+	return nil
+}
+
+func (rrm *ruleRepositoryMock) PruneScriptRuns(_ context.Context, before time.Time, keep uint64) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	groups := make(map[string][]rules.ScriptRun)
+	for id, run := range rrm.scriptRuns {
+		if !before.IsZero() && run.StartedAt.Before(before) {
+			delete(rrm.scriptRuns, id)
+			continue
+		}
+		groups[run.ScriptID+run.ThingID] = append(groups[run.ScriptID+run.ThingID], run)
+	}
+
+	if keep == 0 {
+		return nil
+	}
+
+	for _, runs := range groups {
+		if uint64(len(runs)) <= keep {
+			continue
+		}
+
+		slices.SortFunc(runs, func(a, b rules.ScriptRun) int {
+			return b.StartedAt.Compare(a.StartedAt)
+		})
+		for _, run := range runs[keep:] {
+			delete(rrm.scriptRuns, run.ID)
+		}
+	}
+
+	return nil
+}
+
+func (rrm *ruleRepositoryMock) RetrieveScriptStats(_ context.Context, scriptID string) (rules.ScriptStats, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	stats := rrm.scriptStats[scriptID]
+	res := rules.ScriptStats{
+		ScriptID:  scriptID,
+		Succeeded: stats.succeeded,
+		Failed:    stats.failed,
+		LastRunAt: stats.lastRunAt,
+	}
+
+	if runs := stats.succeeded + stats.failed; runs > 0 {
+		res.AvgDuration = stats.duration / time.Duration(runs)
+		res.AvgInstructionCount = stats.instructions / runs
+	}
+
+	return res, nil
+}
+
+func (rrm *ruleRepositoryMock) ClaimScheduledRun(_ context.Context, scriptID string, trigger int, scheduledAt time.Time) (bool, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	if _, ok := rrm.scripts[scriptID]; !ok {
+		return false, dbutil.ErrNotFound
+	}
+
+	key := fmt.Sprintf("%s.%d", scriptID, trigger)
+	if last, ok := rrm.scheduledRuns[key]; ok && !last.Before(scheduledAt) {
+		return false, nil
+	}
+	rrm.scheduledRuns[key] = scheduledAt
+
+	return true, nil
+}
+
+func (rrm *ruleRepositoryMock) SaveScriptRunTotals(_ context.Context, totals ...rules.ScriptRunTotals) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	for _, t := range totals {
+		stats := rrm.scriptStats[t.ScriptID]
+		stats.succeeded += t.Succeeded
+		stats.failed += t.Failed
+		stats.duration += t.Duration
+		stats.instructions += t.InstructionCount
+		if t.LastRunAt.After(stats.lastRunAt) {
+			stats.lastRunAt = t.LastRunAt
+		}
+		rrm.scriptStats[t.ScriptID] = stats
+	}
+
+	return nil
+}
+
+type scriptStats struct {
+	succeeded    uint64
+	failed       uint64
+	duration     time.Duration
+	instructions uint64
+	lastRunAt    time.Time
+}
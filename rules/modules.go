@@ -0,0 +1,163 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"context"
+
+	"github.com/MainfluxLabs/mainflux/pkg/dbutil"
+	"github.com/MainfluxLabs/mainflux/pkg/domain"
+	"github.com/MainfluxLabs/mainflux/pkg/errors"
+	"github.com/Shopify/go-lua"
+)
+
+// loadedModulesKey is the registry key of the table holding the modules loaded by a Lua script.
+const loadedModulesKey = "_MFX_LOADED"
+
+// LuaModule represents a Lua library module shared by the scripts of a group.
+// Scripts load modules by their name, with require.
+type LuaModule struct {
+	ID      string
+	GroupID string
+	// Name is unique within the group.
+	Name        string
+	Description string
+	// Lua module content
+	Script string
+}
+
+type LuaModulesPage struct {
+	Total   uint64
+	Modules []LuaModule
+}
+
+func (rs *rulesService) CreateModules(ctx context.Context, token, groupID string, modules ...LuaModule) ([]LuaModule, error) {
+	if err := rs.things.CanUserAccessGroup(ctx, domain.UserAccessReq{Token: token, ID: groupID, Action: domain.GroupEditor}); err != nil {
+		return []LuaModule{}, err
+	}
+
+	for i := range modules {
+		modules[i].GroupID = groupID
+
+		id, err := rs.idProvider.ID()
+		if err != nil {
+			return []LuaModule{}, err
+		}
+		modules[i].ID = id
+	}
+
+	return rs.rules.SaveModules(ctx, modules...)
+}
+
+func (rs *rulesService) ListModulesByGroup(ctx context.Context, token, groupID string, pm PageMetadata) (LuaModulesPage, error) {
+	if err := rs.things.CanUserAccessGroup(ctx, domain.UserAccessReq{Token: token, ID: groupID, Action: domain.GroupViewer}); err != nil {
+		return LuaModulesPage{}, err
+	}
+
+	return rs.rules.RetrieveModulesByGroup(ctx, groupID, pm)
+}
+
+func (rs *rulesService) ViewModule(ctx context.Context, token, id string) (LuaModule, error) {
+	module, err := rs.rules.RetrieveModuleByID(ctx, id)
+	if err != nil {
+		return LuaModule{}, err
+	}
+
+	if err := rs.things.CanUserAccessGroup(ctx, domain.UserAccessReq{Token: token, ID: module.GroupID, Action: domain.GroupViewer}); err != nil {
+		return LuaModule{}, err
+	}
+
+	return module, nil
+}
+
+func (rs *rulesService) UpdateModule(ctx context.Context, token string, module LuaModule) error {
+	existing, err := rs.rules.RetrieveModuleByID(ctx, module.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := rs.things.CanUserAccessGroup(ctx, domain.UserAccessReq{Token: token, ID: existing.GroupID, Action: domain.GroupEditor}); err != nil {
+		return err
+	}
+
+	return rs.rules.UpdateModule(ctx, module)
+}
+
+func (rs *rulesService) RemoveModules(ctx context.Context, token string, ids ...string) error {
+	for _, id := range ids {
+		module, err := rs.rules.RetrieveModuleByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := rs.things.CanUserAccessGroup(ctx, domain.UserAccessReq{Token: token, ID: module.GroupID, Action: domain.GroupEditor}); err != nil {
+			return err
+		}
+	}
+
+	return rs.rules.RemoveModules(ctx, ids...)
+}
+
+func (rs *rulesService) RemoveModulesByGroup(ctx context.Context, groupID string) error {
+	return rs.rules.RemoveModulesByGroup(ctx, groupID)
+}
+
+// Load a Lua module of the group of the script by name, and return the value returned by the module.
+// Modules are loaded once per environment, and are run in the global environment of the script.
+// Loading a module charges the instruction budget of the script, as does running it.
+// Lua signature:
+// require(name) (value)
+// Errors are raised if the module doesn't exist, fails to compile or run, or requires itself
+// directly or through other modules.
+func (env *luaEnv) require(ls *lua.State) int {
+	name := lua.CheckString(ls, 1)
+
+	ls.Field(lua.RegistryIndex, loadedModulesKey)
+	ls.Field(-1, name)
+	if !ls.IsNil(-1) {
+		return 1
+	}
+	ls.Pop(2)
+
+	if env.loadingModules[name] {
+		lua.Errorf(ls, "module '%s' is required in a cycle", name)
+	}
+
+	env.instructionCount += apiCallCost
+	if env.instructionCount >= env.maxInstructions {
+		lua.Errorf(ls, "instruction count limit exceeded")
+	}
+
+	module, err := env.service.rules.RetrieveModuleByName(context.Background(), env.script.GroupID, name)
+	switch {
+	case errors.Contains(err, dbutil.ErrNotFound):
+		lua.Errorf(ls, "module '%s' not found", name)
+	case err != nil:
+		lua.Errorf(ls, "loading module '%s' failed: %s", name, err.Error())
+	}
+
+	if err := lua.LoadBuffer(ls, module.Script, "="+name, "t"); err != nil {
+		msg, _ := ls.ToString(-1)
+		lua.Errorf(ls, "loading module '%s' failed: %s", name, msg)
+	}
+
+	env.loadingModules[name] = true
+	defer delete(env.loadingModules, name)
+
+	ls.PushString(name)
+	ls.Call(1, 1)
+
+	// Modules not returning a value are marked as loaded with true, as in the standard require.
+	if ls.IsNil(-1) {
+		ls.Pop(1)
+		ls.PushBoolean(true)
+	}
+
+	ls.Field(lua.RegistryIndex, loadedModulesKey)
+	ls.PushValue(-2)
+	ls.SetField(-2, name)
+	ls.Pop(1)
+
+	return 1
+}
@@ -147,6 +147,177 @@ func migrateDB(db *sqlx.DB) error {
 					`ALTER TABLE rules DROP COLUMN IF EXISTS input_config`,
 				},
 			},
+			{
+				Id: "rules_8",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS rule_states (
+						rule_id    UUID NOT NULL,
+						thing_id   UUID NOT NULL,
+						conditions JSONB NOT NULL,
+						updated    BIGINT NOT NULL,
+						PRIMARY KEY (rule_id, thing_id),
+						FOREIGN KEY (rule_id, thing_id) REFERENCES rules_things (rule_id, thing_id) ON DELETE CASCADE
+					)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS rule_states`,
+				},
+			},
+			{
+				Id: "rules_9",
+				Up: []string{
+					`ALTER TABLE rules ADD COLUMN IF NOT EXISTS expression JSONB`,
+				},
+				Down: []string{
+					`ALTER TABLE rules DROP COLUMN IF EXISTS expression`,
+				},
+			},
+			{
+				Id: "rules_10",
+				Up: []string{
+					`ALTER TABLE rules ADD COLUMN IF NOT EXISTS cooldown BIGINT NOT NULL DEFAULT 0`,
+					`ALTER TABLE rules ADD COLUMN IF NOT EXISTS on_transition BOOLEAN NOT NULL DEFAULT FALSE`,
+					`ALTER TABLE rule_states ADD COLUMN IF NOT EXISTS triggered BOOLEAN NOT NULL DEFAULT FALSE`,
+					`ALTER TABLE rule_states ADD COLUMN IF NOT EXISTS fired BIGINT NOT NULL DEFAULT 0`,
+				},
+				Down: []string{
+					`ALTER TABLE rule_states DROP COLUMN IF EXISTS fired`,
+					`ALTER TABLE rule_states DROP COLUMN IF EXISTS triggered`,
+					`ALTER TABLE rules DROP COLUMN IF EXISTS on_transition`,
+					`ALTER TABLE rules DROP COLUMN IF EXISTS cooldown`,
+				},
+			},
+			{
+				Id: "rules_11",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS lua_script_values (
+						script_id UUID NOT NULL,
+						thing_id  UUID NOT NULL,
+						key       VARCHAR(128) NOT NULL,
+						value     JSONB NOT NULL,
+						PRIMARY KEY (script_id, thing_id, key),
+						FOREIGN KEY (script_id) REFERENCES lua_scripts (id) ON DELETE CASCADE
+					)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS lua_script_values`,
+				},
+			},
+			{
+				Id: "rules_12",
+				Up: []string{
+					`ALTER TABLE lua_scripts ADD COLUMN IF NOT EXISTS triggers JSONB NOT NULL DEFAULT '[]'`,
+				},
+				Down: []string{
+					`ALTER TABLE lua_scripts DROP COLUMN IF EXISTS triggers`,
+				},
+			},
+			{
+				Id: "rules_13",
+				Up: []string{
+					`ALTER TABLE lua_scripts ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1`,
+					`ALTER TABLE lua_scripts ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'enabled'`,
+					`ALTER TABLE lua_scripts_things ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'enabled'`,
+					`CREATE TABLE IF NOT EXISTS lua_script_versions (
+						script_id  UUID NOT NULL,
+						version    BIGINT NOT NULL,
+						script     VARCHAR(65535) NOT NULL,
+						created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+						PRIMARY KEY (script_id, version),
+						FOREIGN KEY (script_id) REFERENCES lua_scripts (id) ON DELETE CASCADE
+					)`,
+					`INSERT INTO lua_script_versions (script_id, version, script)
+						SELECT id, version, script FROM lua_scripts
+						ON CONFLICT (script_id, version) DO NOTHING`,
+					`ALTER TABLE lua_script_runs ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 0`,
+					`ALTER TABLE lua_script_runs ADD COLUMN IF NOT EXISTS instruction_count BIGINT NOT NULL DEFAULT 0`,
+					`CREATE INDEX IF NOT EXISTS idx_lua_script_runs_script_thing_started ON lua_script_runs (script_id, thing_id, started_at)`,
+					`CREATE TABLE IF NOT EXISTS lua_script_stats (
+						script_id          UUID NOT NULL,
+						succeeded          BIGINT NOT NULL DEFAULT 0,
+						failed             BIGINT NOT NULL DEFAULT 0,
+						total_duration     BIGINT NOT NULL DEFAULT 0,
+						total_instructions BIGINT NOT NULL DEFAULT 0,
+						last_run_at        TIMESTAMPTZ NULL,
+						PRIMARY KEY (script_id),
+						FOREIGN KEY (script_id) REFERENCES lua_scripts (id) ON DELETE CASCADE
+					)`,
+					`INSERT INTO lua_script_stats (script_id, succeeded, failed, total_duration, last_run_at)
+						SELECT script_id,
+							COUNT(*) FILTER (WHERE status = 'success'),
+							COUNT(*) FILTER (WHERE status <> 'success'),
+							COALESCE(SUM(EXTRACT(EPOCH FROM finished_at - started_at) * 1000000000), 0)::BIGINT,
+							MAX(finished_at)
+						FROM lua_script_runs
+						GROUP BY script_id
+						ON CONFLICT (script_id) DO NOTHING`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS lua_script_stats`,
+					`DROP INDEX IF EXISTS idx_lua_script_runs_script_thing_started`,
+					`ALTER TABLE lua_script_runs DROP COLUMN IF EXISTS instruction_count`,
+					`ALTER TABLE lua_script_runs DROP COLUMN IF EXISTS version`,
+					`DROP TABLE IF EXISTS lua_script_versions`,
+					`ALTER TABLE lua_scripts_things DROP COLUMN IF EXISTS status`,
+					`ALTER TABLE lua_scripts DROP COLUMN IF EXISTS status`,
+					`ALTER TABLE lua_scripts DROP COLUMN IF EXISTS version`,
+				},
+			},
+			{
+				Id: "rules_14",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS lua_modules (
+						id          UUID NOT NULL,
+						group_id    UUID NOT NULL,
+						name        VARCHAR(254) NOT NULL,
+						description VARCHAR NOT NULL,
+						script      VARCHAR(65535) NOT NULL,
+						PRIMARY KEY (id),
+						UNIQUE (group_id, name)
+					)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS lua_modules`,
+				},
+			},
+			{
+				Id: "rules_15",
+				Up: []string{
+					`ALTER TABLE rule_states ADD COLUMN IF NOT EXISTS subtopic VARCHAR NOT NULL DEFAULT ''`,
+					`ALTER TABLE rule_states DROP CONSTRAINT IF EXISTS rule_states_pkey`,
+					`ALTER TABLE rule_states ADD PRIMARY KEY (rule_id, thing_id, subtopic)`,
+				},
+				Down: []string{
+					`DELETE FROM rule_states WHERE subtopic <> ''`,
+					`ALTER TABLE rule_states DROP CONSTRAINT IF EXISTS rule_states_pkey`,
+					`ALTER TABLE rule_states ADD PRIMARY KEY (rule_id, thing_id)`,
+					`ALTER TABLE rule_states DROP COLUMN IF EXISTS subtopic`,
+				},
+			},
+			{
+				Id: "rules_16",
+				Up: []string{
+					`CREATE INDEX IF NOT EXISTS idx_lua_script_runs_started ON lua_script_runs (started_at)`,
+				},
+				Down: []string{
+					`DROP INDEX IF EXISTS idx_lua_script_runs_started`,
+				},
+			},
+			{
+				Id: "rules_17",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS lua_script_schedules (
+						script_id    UUID NOT NULL,
+						trigger_idx  INTEGER NOT NULL,
+						scheduled_at TIMESTAMPTZ NOT NULL,
+						PRIMARY KEY (script_id, trigger_idx),
+						FOREIGN KEY (script_id) REFERENCES lua_scripts (id) ON DELETE CASCADE
+					)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS lua_script_schedules`,
+				},
+			},
 		},
 	}
 	_, err := migrate.Exec(db.DB, "postgres", migrations, migrate.Up)
@@ -0,0 +1,213 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/MainfluxLabs/mainflux/pkg/dbutil"
+	"github.com/MainfluxLabs/mainflux/pkg/errors"
+	"github.com/MainfluxLabs/mainflux/rules"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func (rr ruleRepository) SaveModules(ctx context.Context, modules ...rules.LuaModule) ([]rules.LuaModule, error) {
+	tx, err := rr.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return []rules.LuaModule{}, errors.Wrap(dbutil.ErrCreateEntity, err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO lua_modules (id, group_id, name, description, script)
+		VALUES (:id, :group_id, :name, :description, :script);
+	`
+
+	for _, module := range modules {
+		if _, err := tx.NamedExecContext(ctx, query, dbLuaModule(module)); err != nil {
+			pgErr, ok := err.(*pgconn.PgError)
+			if ok {
+				switch pgErr.Code {
+				case pgerrcode.InvalidTextRepresentation:
+					return []rules.LuaModule{}, errors.Wrap(dbutil.ErrMalformedEntity, err)
+				case pgerrcode.UniqueViolation:
+					return []rules.LuaModule{}, errors.Wrap(dbutil.ErrConflict, err)
+				case pgerrcode.StringDataRightTruncationDataException:
+					return []rules.LuaModule{}, errors.Wrap(dbutil.ErrMalformedEntity, err)
+				}
+			}
+
+			return []rules.LuaModule{}, errors.Wrap(dbutil.ErrCreateEntity, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return []rules.LuaModule{}, errors.Wrap(dbutil.ErrCreateEntity, err)
+	}
+	return modules, nil
+}
+
+func (rr ruleRepository) RetrieveModuleByID(ctx context.Context, id string) (rules.LuaModule, error) {
+	query := `
+		SELECT id, group_id, name, description, script
+		FROM lua_modules
+		WHERE id = $1;
+	`
+
+	return rr.retrieveModule(ctx, query, id)
+}
+
+func (rr ruleRepository) RetrieveModuleByName(ctx context.Context, groupID, name string) (rules.LuaModule, error) {
+	query := `
+		SELECT id, group_id, name, description, script
+		FROM lua_modules
+		WHERE group_id = $1 AND name = $2;
+	`
+
+	return rr.retrieveModule(ctx, query, groupID, name)
+}
+
+func (rr ruleRepository) retrieveModule(ctx context.Context, query string, args ...any) (rules.LuaModule, error) {
+	var dbm dbLuaModule
+	if err := rr.db.QueryRowxContext(ctx, query, args...).StructScan(&dbm); err != nil {
+		if err == sql.ErrNoRows {
+			return rules.LuaModule{}, errors.Wrap(dbutil.ErrNotFound, err)
+		}
+
+		pgErr, ok := err.(*pgconn.PgError)
+		if ok && pgErr.Code == pgerrcode.InvalidTextRepresentation {
+			return rules.LuaModule{}, errors.Wrap(dbutil.ErrMalformedEntity, err)
+		}
+		return rules.LuaModule{}, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+	}
+
+	return rules.LuaModule(dbm), nil
+}
+
+func (rr ruleRepository) RetrieveModulesByGroup(ctx context.Context, groupID string, pm rules.PageMetadata) (rules.LuaModulesPage, error) {
+	oq := dbutil.GetOrderQuery(pm.Order, rules.RuleOrderFields)
+	dq := dbutil.GetDirQuery(pm.Dir)
+	olq := dbutil.GetOffsetLimitQuery(pm.Limit)
+
+	gq := "group_id = :group_id"
+	nq, name := dbutil.GetNameQuery(pm.Name)
+
+	whereClause := dbutil.BuildWhereClause(gq, nq)
+
+	query := `
+		SELECT id, group_id, name, description, script
+		FROM lua_modules %s ORDER BY %s %s %s;
+	`
+
+	queryCount := `SELECT COUNT(*) FROM lua_modules %s;`
+
+	query = fmt.Sprintf(query, whereClause, oq, dq, olq)
+	queryCount = fmt.Sprintf(queryCount, whereClause)
+
+	params := map[string]any{
+		"group_id": groupID,
+		"name":     name,
+		"limit":    pm.Limit,
+		"offset":   pm.Offset,
+	}
+
+	rows, err := rr.db.NamedQueryContext(ctx, query, params)
+	if err != nil {
+		return rules.LuaModulesPage{}, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+	}
+	defer rows.Close()
+
+	var modules []rules.LuaModule
+	for rows.Next() {
+		var dbm dbLuaModule
+		if err = rows.StructScan(&dbm); err != nil {
+			return rules.LuaModulesPage{}, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+		}
+
+		modules = append(modules, rules.LuaModule(dbm))
+	}
+
+	total, err := dbutil.Total(ctx, rr.db, queryCount, params)
+	if err != nil {
+		return rules.LuaModulesPage{}, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+	}
+
+	return rules.LuaModulesPage{
+		Modules: modules,
+		Total:   total,
+	}, nil
+}
+
+func (rr ruleRepository) UpdateModule(ctx context.Context, module rules.LuaModule) error {
+	query := `
+		UPDATE lua_modules
+		SET name = :name, description = :description, script = :script
+		WHERE id = :id;
+	`
+
+	res, err := rr.db.NamedExecContext(ctx, query, dbLuaModule(module))
+	if err != nil {
+		pgErr, ok := err.(*pgconn.PgError)
+		if ok {
+			switch pgErr.Code {
+			case pgerrcode.InvalidTextRepresentation,
+				pgerrcode.StringDataRightTruncationDataException:
+				return errors.Wrap(dbutil.ErrMalformedEntity, err)
+			case pgerrcode.UniqueViolation:
+				return errors.Wrap(dbutil.ErrConflict, err)
+			}
+		}
+
+		return errors.Wrap(dbutil.ErrUpdateEntity, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(dbutil.ErrUpdateEntity, err)
+	}
+
+	if cnt == 0 {
+		return dbutil.ErrNotFound
+	}
+
+	return nil
+}
+
+func (rr ruleRepository) RemoveModules(ctx context.Context, ids ...string) error {
+	query := `
+		DELETE FROM lua_modules
+		WHERE id = :id
+	`
+
+	for _, id := range ids {
+		dbm := dbLuaModule{ID: id}
+		if _, err := rr.db.NamedExecContext(ctx, query, dbm); err != nil {
+			return errors.Wrap(dbutil.ErrRemoveEntity, err)
+		}
+	}
+
+	return nil
+}
+
+func (rr ruleRepository) RemoveModulesByGroup(ctx context.Context, groupID string) error {
+	query := `
+		DELETE FROM lua_modules
+		WHERE group_id = :group_id
+	`
+
+	params := map[string]any{"group_id": groupID}
+	if _, err := rr.db.NamedExecContext(ctx, query, params); err != nil {
+		return errors.Wrap(dbutil.ErrRemoveEntity, err)
+	}
+
+	return nil
+}
+
+type dbLuaModule struct {
+	ID          string `db:"id"`
+	GroupID     string `db:"group_id"`
+	Name        string `db:"name"`
+	Description string `db:"description"`
+	Script      string `db:"script"`
+}
@@ -36,8 +36,8 @@ func (rr ruleRepository) Save(ctx context.Context, rls ...rules.Rule) ([]rules.R
 	}
 	defer tx.Rollback()
 
-	rq := `INSERT INTO rules (id, group_id, name, description, input_type, input_config, conditions, operator, actions)
-		VALUES (:id, :group_id, :name, :description, :input_type, :input_config, :conditions, :operator, :actions);`
+	rq := `INSERT INTO rules (id, group_id, name, description, input_type, input_config, conditions, operator, expression, actions, cooldown, on_transition)
+		VALUES (:id, :group_id, :name, :description, :input_type, :input_config, :conditions, :operator, :expression, :actions, :cooldown, :on_transition);`
 
 	for _, rule := range rls {
 		dbr, err := toDBRule(rule)
@@ -88,7 +88,7 @@ func (rr ruleRepository) RetrieveByGroup(ctx context.Context, groupID string, pm
 	}
 	whereClause := dbutil.BuildWhereClause(gq, nq, itq)
 
-	q := fmt.Sprintf(`SELECT id, group_id, name, description, input_type, input_config, conditions, operator, actions
+	q := fmt.Sprintf(`SELECT id, group_id, name, description, input_type, input_config, conditions, operator, expression, actions, cooldown, on_transition
 		FROM rules %s
 		ORDER BY %s %s %s;`, whereClause, oq, dq, olq)
 
@@ -126,7 +126,7 @@ func (rr ruleRepository) RetrieveByThing(ctx context.Context, thingID string, pm
 	countClause := dbutil.BuildWhereClause(tq, itq)
 
 	q := fmt.Sprintf(`SELECT r.id, r.group_id, r.name, r.description,
-		r.input_type, r.input_config, r.conditions, r.operator, r.actions
+		r.input_type, r.input_config, r.conditions, r.operator, r.expression, r.actions, r.cooldown, r.on_transition
 		FROM rules r %s %s
 		ORDER BY %s %s %s;`, joinClause, whereClause, oq, dq, olq)
 
@@ -144,7 +144,7 @@ func (rr ruleRepository) RetrieveByThing(ctx context.Context, thingID string, pm
 }
 
 func (rr ruleRepository) RetrieveByID(ctx context.Context, id string) (rules.Rule, error) {
-	q := `SELECT id, group_id, name, description, input_type, input_config, conditions, operator, actions
+	q := `SELECT id, group_id, name, description, input_type, input_config, conditions, operator, expression, actions, cooldown, on_transition
 		FROM rules
 		WHERE id = $1;`
 
@@ -168,7 +168,8 @@ func (rr ruleRepository) RetrieveByID(ctx context.Context, id string) (rules.Rul
 func (rr ruleRepository) Update(ctx context.Context, r rules.Rule) error {
 	uq := `UPDATE rules
 		SET name = :name, description = :description, input_type = :input_type,
-		input_config = :input_config, conditions = :conditions, operator = :operator, actions = :actions
+		input_config = :input_config, conditions = :conditions, operator = :operator, expression = :expression, actions = :actions,
+		cooldown = :cooldown, on_transition = :on_transition
 		WHERE id = :id;`
 
 	dbr, err := toDBRule(r)
@@ -270,6 +271,61 @@ func (rr ruleRepository) UnassignThings(ctx context.Context, ruleID string, thin
 	return nil
 }
 
+func (rr ruleRepository) RetrieveState(ctx context.Context, ruleID, thingID, subtopic string) (rules.RuleState, error) {
+	q := `SELECT rule_id, thing_id, subtopic, conditions, triggered, fired, updated FROM rule_states
+		WHERE rule_id = $1 AND thing_id = $2 AND subtopic = $3;`
+
+	var dbs dbRuleState
+	if err := rr.db.QueryRowxContext(ctx, q, ruleID, thingID, subtopic).StructScan(&dbs); err != nil {
+		if err == sql.ErrNoRows {
+			return rules.RuleState{RuleID: ruleID, ThingID: thingID, Subtopic: subtopic}, nil
+		}
+		pgErr, ok := err.(*pgconn.PgError)
+		if ok && pgerrcode.InvalidTextRepresentation == pgErr.Code {
+			return rules.RuleState{}, errors.Wrap(dbutil.ErrNotFound, err)
+		}
+		return rules.RuleState{}, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+	}
+
+	return toRuleState(dbs)
+}
+
+func (rr ruleRepository) SaveState(ctx context.Context, state rules.RuleState) error {
+	q := `INSERT INTO rule_states (rule_id, thing_id, subtopic, conditions, triggered, fired, updated)
+		VALUES (:rule_id, :thing_id, :subtopic, :conditions, :triggered, :fired, :updated)
+		ON CONFLICT (rule_id, thing_id, subtopic) DO UPDATE SET conditions = :conditions, triggered = :triggered, fired = :fired, updated = :updated;`
+
+	dbs, err := toDBRuleState(state)
+	if err != nil {
+		return errors.Wrap(dbutil.ErrCreateEntity, err)
+	}
+
+	if _, err := rr.db.NamedExecContext(ctx, q, dbs); err != nil {
+		pgErr, ok := err.(*pgconn.PgError)
+		if ok {
+			switch pgErr.Code {
+			case pgerrcode.InvalidTextRepresentation:
+				return errors.Wrap(dbutil.ErrMalformedEntity, err)
+			case pgerrcode.ForeignKeyViolation:
+				return errors.Wrap(dbutil.ErrNotFound, err)
+			}
+		}
+		return errors.Wrap(dbutil.ErrCreateEntity, err)
+	}
+
+	return nil
+}
+
+func (rr ruleRepository) RemoveStates(ctx context.Context, ruleID string) error {
+	q := `DELETE FROM rule_states WHERE rule_id = :rule_id;`
+
+	if _, err := rr.db.NamedExecContext(ctx, q, map[string]any{"rule_id": ruleID}); err != nil {
+		return errors.Wrap(dbutil.ErrRemoveEntity, err)
+	}
+
+	return nil
+}
+
 func (rr ruleRepository) retrieveRules(ctx context.Context, query, cquery string, params map[string]any) (rules.RulesPage, error) {
 	rows, err := rr.db.NamedQueryContext(ctx, query, params)
 	if err != nil {
@@ -358,15 +414,18 @@ func insertRulesThings(ctx context.Context, tx *sqlx.Tx, ruleID string, thingIDs
 }
 
 type dbRule struct {
-	ID          string `db:"id"`
-	GroupID     string `db:"group_id"`
-	Name        string `db:"name"`
-	Description string `db:"description"`
-	InputType   string `db:"input_type"`
-	InputConfig []byte `db:"input_config"`
-	Conditions  []byte `db:"conditions"`
-	Operator    string `db:"operator"`
-	Actions     []byte `db:"actions"`
+	ID           string `db:"id"`
+	GroupID      string `db:"group_id"`
+	Name         string `db:"name"`
+	Description  string `db:"description"`
+	InputType    string `db:"input_type"`
+	InputConfig  []byte `db:"input_config"`
+	Conditions   []byte `db:"conditions"`
+	Operator     string `db:"operator"`
+	Expression   []byte `db:"expression"`
+	Actions      []byte `db:"actions"`
+	Cooldown     uint64 `db:"cooldown"`
+	OnTransition bool   `db:"on_transition"`
 }
 
 func toDBRule(r rules.Rule) (dbRule, error) {
@@ -384,21 +443,31 @@ func toDBRule(r rules.Rule) (dbRule, error) {
 		return dbRule{}, errors.Wrap(dbutil.ErrMalformedEntity, err)
 	}
 
+	var expression []byte
+	if r.Expression != nil {
+		if expression, err = json.Marshal(r.Expression); err != nil {
+			return dbRule{}, errors.Wrap(dbutil.ErrMalformedEntity, err)
+		}
+	}
+
 	actions, err := json.Marshal(r.Actions)
 	if err != nil {
 		return dbRule{}, errors.Wrap(dbutil.ErrMalformedEntity, err)
 	}
 
 	return dbRule{
-		ID:          r.ID,
-		GroupID:     r.GroupID,
-		Name:        r.Name,
-		Description: r.Description,
-		InputType:   r.Input.Type,
-		InputConfig: inputConfig,
-		Conditions:  conditions,
-		Operator:    r.Operator,
-		Actions:     actions,
+		ID:           r.ID,
+		GroupID:      r.GroupID,
+		Name:         r.Name,
+		Description:  r.Description,
+		InputType:    r.Input.Type,
+		InputConfig:  inputConfig,
+		Conditions:   conditions,
+		Operator:     r.Operator,
+		Expression:   expression,
+		Actions:      actions,
+		Cooldown:     r.Cooldown,
+		OnTransition: r.OnTransition,
 	}, nil
 }
 
@@ -418,14 +487,73 @@ func toRule(dbr dbRule, thingIDs []string) (rules.Rule, error) {
 		return rules.Rule{}, errors.Wrap(dbutil.ErrMalformedEntity, err)
 	}
 
+	var expression *rules.ConditionGroup
+	if len(dbr.Expression) > 0 {
+		expression = &rules.ConditionGroup{}
+		if err := json.Unmarshal(dbr.Expression, expression); err != nil {
+			return rules.Rule{}, errors.Wrap(dbutil.ErrMalformedEntity, err)
+		}
+	}
+
 	return rules.Rule{
-		ID:          dbr.ID,
-		GroupID:     dbr.GroupID,
-		Name:        dbr.Name,
-		Description: dbr.Description,
-		Input:       rules.Input{Type: dbr.InputType, ThingIDs: thingIDs, Config: inputConfig},
-		Conditions:  conditions,
-		Operator:    dbr.Operator,
-		Actions:     actions,
+		ID:           dbr.ID,
+		GroupID:      dbr.GroupID,
+		Name:         dbr.Name,
+		Description:  dbr.Description,
+		Input:        rules.Input{Type: dbr.InputType, ThingIDs: thingIDs, Config: inputConfig},
+		Conditions:   conditions,
+		Operator:     dbr.Operator,
+		Expression:   expression,
+		Actions:      actions,
+		Cooldown:     dbr.Cooldown,
+		OnTransition: dbr.OnTransition,
+	}, nil
+}
+
+type dbRuleState struct {
+	RuleID     string `db:"rule_id"`
+	ThingID    string `db:"thing_id"`
+	Subtopic   string `db:"subtopic"`
+	Conditions []byte `db:"conditions"`
+	Triggered  bool   `db:"triggered"`
+	Fired      int64  `db:"fired"`
+	Updated    int64  `db:"updated"`
+}
+
+func toDBRuleState(s rules.RuleState) (dbRuleState, error) {
+	conditions := []byte("[]")
+	if len(s.Conditions) > 0 {
+		b, err := json.Marshal(s.Conditions)
+		if err != nil {
+			return dbRuleState{}, errors.Wrap(dbutil.ErrMalformedEntity, err)
+		}
+		conditions = b
+	}
+
+	return dbRuleState{
+		RuleID:     s.RuleID,
+		ThingID:    s.ThingID,
+		Subtopic:   s.Subtopic,
+		Conditions: conditions,
+		Triggered:  s.Triggered,
+		Fired:      s.Fired,
+		Updated:    s.Updated,
+	}, nil
+}
+
+func toRuleState(dbs dbRuleState) (rules.RuleState, error) {
+	var conditions []rules.ConditionState
+	if err := json.Unmarshal(dbs.Conditions, &conditions); err != nil {
+		return rules.RuleState{}, errors.Wrap(dbutil.ErrMalformedEntity, err)
+	}
+
+	return rules.RuleState{
+		RuleID:     dbs.RuleID,
+		ThingID:    dbs.ThingID,
+		Subtopic:   dbs.Subtopic,
+		Conditions: conditions,
+		Triggered:  dbs.Triggered,
+		Fired:      dbs.Fired,
+		Updated:    dbs.Updated,
 	}, nil
 }
@@ -22,12 +22,21 @@ func (rr ruleRepository) SaveScripts(ctx context.Context, scripts ...rules.LuaSc
 	defer tx.Rollback()
 
 	query := `
-		INSERT INTO lua_scripts (id, group_id, script, name, description) 
-		VALUES (:id, :group_id, :script, :name, :description);
+		INSERT INTO lua_scripts (id, group_id, script, name, description, triggers, version, status) 
+		VALUES (:id, :group_id, :script, :name, :description, :triggers, :version, :status);
+	`
+
+	versionQuery := `
+		INSERT INTO lua_script_versions (script_id, version, script)
+		VALUES (:id, :version, :script);
 	`
 
 	for _, script := range scripts {
-		dbScript := toDBLuaScript(script)
+		dbScript, err := toDBLuaScript(script)
+		if err != nil {
+			return []rules.LuaScript{}, err
+		}
+
 		if _, err := tx.NamedExecContext(ctx, query, dbScript); err != nil {
 			pgErr, ok := err.(*pgconn.PgError)
 			if ok {
@@ -43,6 +52,10 @@ func (rr ruleRepository) SaveScripts(ctx context.Context, scripts ...rules.LuaSc
 
 			return []rules.LuaScript{}, errors.Wrap(dbutil.ErrCreateEntity, err)
 		}
+
+		if _, err := tx.NamedExecContext(ctx, versionQuery, dbScript); err != nil {
+			return []rules.LuaScript{}, errors.Wrap(dbutil.ErrCreateEntity, err)
+		}
 	}
 
 	if err = tx.Commit(); err != nil {
@@ -53,7 +66,7 @@ func (rr ruleRepository) SaveScripts(ctx context.Context, scripts ...rules.LuaSc
 
 func (rr ruleRepository) RetrieveScriptByID(ctx context.Context, id string) (rules.LuaScript, error) {
 	query := `
-		SELECT id, group_id, script, name, description
+		SELECT id, group_id, script, name, description, triggers, version, status
 		FROM lua_scripts
 		WHERE id = $1;
 	`
@@ -74,7 +87,7 @@ func (rr ruleRepository) RetrieveScriptByID(ctx context.Context, id string) (rul
 		return rules.LuaScript{}, errors.Wrap(dbutil.ErrRetrieveEntity, err)
 	}
 
-	return toLuaScript(dbs), nil
+	return toLuaScript(dbs)
 }
 
 func (rr ruleRepository) RetrieveScriptsByThing(ctx context.Context, thingID string, pm rules.PageMetadata) (rules.LuaScriptsPage, error) {
@@ -84,11 +97,15 @@ func (rr ruleRepository) RetrieveScriptsByThing(ctx context.Context, thingID str
 
 	thingQuery := "lst.thing_id = :thing_id"
 	nameQuery, name := dbutil.GetNameQuery(pm.Name)
+	statusQuery := ""
+	if pm.Status != "" {
+		statusQuery = "ls.status = :status AND lst.status = :status"
+	}
 
-	whereClause := dbutil.BuildWhereClause(thingQuery, nameQuery)
+	whereClause := dbutil.BuildWhereClause(thingQuery, nameQuery, statusQuery)
 
 	query := `
-		SELECT ls.id, ls.group_id, ls.script, ls.name, ls.description
+		SELECT ls.id, ls.group_id, ls.script, ls.name, ls.description, ls.triggers, ls.version, ls.status, lst.status AS thing_status
 		FROM lua_scripts ls
 		INNER JOIN lua_scripts_things lst ON ls.id = lst.lua_script_id
 		%s
@@ -109,6 +126,7 @@ func (rr ruleRepository) RetrieveScriptsByThing(ctx context.Context, thingID str
 	params := map[string]any{
 		"thing_id": thingID,
 		"name":     name,
+		"status":   pm.Status,
 		"limit":    pm.Limit,
 		"offset":   pm.Offset,
 	}
@@ -126,7 +144,12 @@ func (rr ruleRepository) RetrieveScriptsByThing(ctx context.Context, thingID str
 			return rules.LuaScriptsPage{}, errors.Wrap(dbutil.ErrRetrieveEntity, err)
 		}
 
-		scripts = append(scripts, toLuaScript(dba))
+		script, err := toLuaScript(dba)
+		if err != nil {
+			return rules.LuaScriptsPage{}, err
+		}
+
+		scripts = append(scripts, script)
 	}
 
 	total, err := dbutil.Total(ctx, rr.db, queryCount, params)
@@ -149,11 +172,15 @@ func (rr ruleRepository) RetrieveScriptsByGroup(ctx context.Context, groupID str
 
 	gq := "group_id = :group_id"
 	nq, name := dbutil.GetNameQuery(pm.Name)
+	sq := ""
+	if pm.Status != "" {
+		sq = "status = :status"
+	}
 
-	whereClause := dbutil.BuildWhereClause(gq, nq)
+	whereClause := dbutil.BuildWhereClause(gq, nq, sq)
 
 	query := `
-		SELECT id, group_id, script, name, description
+		SELECT id, group_id, script, name, description, triggers, version, status
 		FROM lua_scripts %s ORDER BY %s %s %s;
 	`
 
@@ -165,6 +192,7 @@ func (rr ruleRepository) RetrieveScriptsByGroup(ctx context.Context, groupID str
 	params := map[string]any{
 		"group_id": groupID,
 		"name":     name,
+		"status":   pm.Status,
 		"limit":    pm.Limit,
 		"offset":   pm.Offset,
 	}
@@ -182,7 +210,12 @@ func (rr ruleRepository) RetrieveScriptsByGroup(ctx context.Context, groupID str
 			return rules.LuaScriptsPage{}, errors.Wrap(dbutil.ErrRetrieveEntity, err)
 		}
 
-		scripts = append(scripts, toLuaScript(dba))
+		script, err := toLuaScript(dba)
+		if err != nil {
+			return rules.LuaScriptsPage{}, err
+		}
+
+		scripts = append(scripts, script)
 	}
 
 	total, err := dbutil.Total(ctx, rr.db, queryCount, params)
@@ -198,15 +231,50 @@ func (rr ruleRepository) RetrieveScriptsByGroup(ctx context.Context, groupID str
 	return page, nil
 }
 
-func (rr ruleRepository) RetrieveThingIDsByScript(ctx context.Context, scriptID string) ([]string, error) {
+func (rr ruleRepository) RetrieveScheduledScripts(ctx context.Context) ([]rules.LuaScript, error) {
+	query := `
+		SELECT id, group_id, script, name, description, triggers, version, status
+		FROM lua_scripts
+		WHERE triggers @> CAST(:triggers AS JSONB);
+	`
+
+	params := map[string]any{
+		"triggers": fmt.Sprintf(`[{"type": "%s"}]`, rules.TriggerTypeSchedule),
+	}
+
+	rows, err := rr.db.NamedQueryContext(ctx, query, params)
+	if err != nil {
+		return nil, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+	}
+	defer rows.Close()
+
+	var scripts []rules.LuaScript
+	for rows.Next() {
+		var dba dbLuaScript
+		if err = rows.StructScan(&dba); err != nil {
+			return nil, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+		}
+
+		script, err := toLuaScript(dba)
+		if err != nil {
+			return nil, err
+		}
+
+		scripts = append(scripts, script)
+	}
+
+	return scripts, nil
+}
+
+func (rr ruleRepository) RetrieveThingIDsByScript(ctx context.Context, scriptID, status string) ([]string, error) {
 	query := `
 		SELECT thing_id
 		FROM lua_scripts_things
-		WHERE lua_script_id = $1
+		WHERE lua_script_id = $1 AND ($2 = '' OR status = $2)
 	`
 
 	thingIDs := []string{}
-	if err := rr.db.SelectContext(ctx, &thingIDs, query, scriptID); err != nil {
+	if err := rr.db.SelectContext(ctx, &thingIDs, query, scriptID, status); err != nil {
 		return nil, err
 	}
 
@@ -214,15 +282,33 @@ func (rr ruleRepository) RetrieveThingIDsByScript(ctx context.Context, scriptID
 }
 
 func (rr ruleRepository) UpdateScript(ctx context.Context, script rules.LuaScript) error {
+	// The version is incremented only if the content changes, in which case saving the version
+	// of the updated script creates a new one.
 	query := `
 		UPDATE lua_scripts
-		SET script = :script, name = :name, description = :description
+		SET script = :script, name = :name, description = :description, triggers = :triggers,
+			version = CASE WHEN script = :script THEN version ELSE version + 1 END
 		WHERE id = :id;
 	`
 
-	dbScript := toDBLuaScript(script)
+	versionQuery := `
+		INSERT INTO lua_script_versions (script_id, version, script)
+		SELECT id, version, script FROM lua_scripts WHERE id = :id
+		ON CONFLICT (script_id, version) DO NOTHING;
+	`
+
+	dbScript, err := toDBLuaScript(script)
+	if err != nil {
+		return err
+	}
 
-	res, errdb := rr.db.NamedExecContext(ctx, query, dbScript)
+	tx, err := rr.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(dbutil.ErrUpdateEntity, err)
+	}
+	defer tx.Rollback()
+
+	res, errdb := tx.NamedExecContext(ctx, query, dbScript)
 	if errdb != nil {
 		pgErr, ok := errdb.(*pgconn.PgError)
 		if ok {
@@ -245,9 +331,110 @@ func (rr ruleRepository) UpdateScript(ctx context.Context, script rules.LuaScrip
 		return dbutil.ErrNotFound
 	}
 
+	if _, err := tx.NamedExecContext(ctx, versionQuery, dbScript); err != nil {
+		return errors.Wrap(dbutil.ErrUpdateEntity, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(dbutil.ErrUpdateEntity, err)
+	}
+
+	return nil
+}
+
+func (rr ruleRepository) UpdateScriptStatus(ctx context.Context, id, status string) error {
+	query := `UPDATE lua_scripts SET status = :status WHERE id = :id;`
+
+	params := map[string]any{
+		"id":     id,
+		"status": status,
+	}
+
+	res, err := rr.db.NamedExecContext(ctx, query, params)
+	if err != nil {
+		pgErr, ok := err.(*pgconn.PgError)
+		if ok && pgErr.Code == pgerrcode.InvalidTextRepresentation {
+			return errors.Wrap(dbutil.ErrMalformedEntity, err)
+		}
+		return errors.Wrap(dbutil.ErrUpdateEntity, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(dbutil.ErrUpdateEntity, err)
+	}
+
+	if cnt == 0 {
+		return dbutil.ErrNotFound
+	}
+
 	return nil
 }
 
+func (rr ruleRepository) RetrieveScriptVersions(ctx context.Context, scriptID string, pm rules.PageMetadata) (rules.ScriptVersionsPage, error) {
+	olq := dbutil.GetOffsetLimitQuery(pm.Limit)
+
+	query := fmt.Sprintf(`
+		SELECT script_id, version, script, created_at
+		FROM lua_script_versions
+		WHERE script_id = :script_id
+		ORDER BY version DESC %s;
+	`, olq)
+
+	queryCount := `SELECT COUNT(*) FROM lua_script_versions WHERE script_id = :script_id;`
+
+	params := map[string]any{
+		"script_id": scriptID,
+		"limit":     pm.Limit,
+		"offset":    pm.Offset,
+	}
+
+	rows, err := rr.db.NamedQueryContext(ctx, query, params)
+	if err != nil {
+		return rules.ScriptVersionsPage{}, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+	}
+	defer rows.Close()
+
+	var versions []rules.ScriptVersion
+	for rows.Next() {
+		var dbv dbScriptVersion
+		if err = rows.StructScan(&dbv); err != nil {
+			return rules.ScriptVersionsPage{}, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+		}
+
+		versions = append(versions, rules.ScriptVersion(dbv))
+	}
+
+	total, err := dbutil.Total(ctx, rr.db, queryCount, params)
+	if err != nil {
+		return rules.ScriptVersionsPage{}, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+	}
+
+	return rules.ScriptVersionsPage{
+		Versions: versions,
+		Total:    total,
+	}, nil
+}
+
+func (rr ruleRepository) RetrieveScriptVersion(ctx context.Context, scriptID string, version uint64) (rules.ScriptVersion, error) {
+	query := `
+		SELECT script_id, version, script, created_at
+		FROM lua_script_versions
+		WHERE script_id = $1 AND version = $2;
+	`
+
+	var dbv dbScriptVersion
+	if err := rr.db.QueryRowxContext(ctx, query, scriptID, version).StructScan(&dbv); err != nil {
+		pgErr, ok := err.(*pgconn.PgError)
+		if err == sql.ErrNoRows || ok && pgerrcode.InvalidTextRepresentation == pgErr.Code {
+			return rules.ScriptVersion{}, errors.Wrap(dbutil.ErrNotFound, err)
+		}
+		return rules.ScriptVersion{}, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+	}
+
+	return rules.ScriptVersion(dbv), nil
+}
+
 func (rr ruleRepository) RemoveScripts(ctx context.Context, ids ...string) error {
 	query := `
 		DELETE FROM lua_scripts
@@ -351,6 +538,53 @@ func (rr ruleRepository) UnassignScriptsFromThing(ctx context.Context, thingID s
 	return nil
 }
 
+func (rr ruleRepository) UpdateThingScriptsStatus(ctx context.Context, thingID, status string, scriptIDs ...string) error {
+	query := `
+		UPDATE lua_scripts_things SET status = :status
+		WHERE lua_script_id = :lua_script_id AND thing_id = :thing_id
+	`
+
+	for _, scriptID := range scriptIDs {
+		params := map[string]any{
+			"lua_script_id": scriptID,
+			"thing_id":      thingID,
+			"status":        status,
+		}
+
+		res, err := rr.db.NamedExecContext(ctx, query, params)
+		if err != nil {
+			pgErr, ok := err.(*pgconn.PgError)
+			if ok && pgErr.Code == pgerrcode.InvalidTextRepresentation {
+				return errors.Wrap(dbutil.ErrMalformedEntity, err)
+			}
+			return errors.Wrap(dbutil.ErrUpdateEntity, err)
+		}
+
+		cnt, err := res.RowsAffected()
+		if err != nil {
+			return errors.Wrap(dbutil.ErrUpdateEntity, err)
+		}
+
+		if cnt == 0 {
+			return dbutil.ErrNotFound
+		}
+	}
+
+	return nil
+}
+
+// addScriptStatsQuery adds the run totals to the stats of the script.
+const addScriptStatsQuery = `
+	INSERT INTO lua_script_stats (script_id, succeeded, failed, total_duration, total_instructions, last_run_at)
+	VALUES (:script_id, :succeeded, :failed, :total_duration, :total_instructions, :last_run_at)
+	ON CONFLICT (script_id) DO UPDATE SET
+		succeeded = lua_script_stats.succeeded + EXCLUDED.succeeded,
+		failed = lua_script_stats.failed + EXCLUDED.failed,
+		total_duration = lua_script_stats.total_duration + EXCLUDED.total_duration,
+		total_instructions = lua_script_stats.total_instructions + EXCLUDED.total_instructions,
+		last_run_at = GREATEST(lua_script_stats.last_run_at, EXCLUDED.last_run_at);
+`
+
 func (rr ruleRepository) SaveScriptRuns(ctx context.Context, runs ...rules.ScriptRun) ([]rules.ScriptRun, error) {
 	tx, err := rr.db.BeginTxx(ctx, nil)
 	if err != nil {
@@ -359,8 +593,8 @@ func (rr ruleRepository) SaveScriptRuns(ctx context.Context, runs ...rules.Scrip
 	defer tx.Rollback()
 
 	query := `
-		INSERT INTO lua_script_runs (id, script_id, thing_id, logs, started_at, finished_at, status, error) 
-		VALUES (:id, :script_id, :thing_id, :logs, :started_at, :finished_at, :status, :error);
+		INSERT INTO lua_script_runs (id, script_id, thing_id, logs, started_at, finished_at, status, error, version, instruction_count) 
+		VALUES (:id, :script_id, :thing_id, :logs, :started_at, :finished_at, :status, :error, :version, :instruction_count);
 	`
 
 	for _, run := range runs {
@@ -395,7 +629,7 @@ func (rr ruleRepository) SaveScriptRuns(ctx context.Context, runs ...rules.Scrip
 
 func (rr ruleRepository) RetrieveScriptRunByID(ctx context.Context, id string) (rules.ScriptRun, error) {
 	query := `
-		SELECT id, script_id, thing_id, logs, started_at, finished_at, status, error
+		SELECT id, script_id, thing_id, logs, started_at, finished_at, status, error, version, instruction_count
 		FROM lua_script_runs
 		WHERE id = $1;
 	`
@@ -440,6 +674,110 @@ func (rr ruleRepository) RemoveScriptRuns(ctx context.Context, ids ...string) er
 	return nil
 }
 
+func (rr ruleRepository) PruneScriptRuns(ctx context.Context, before time.Time, keep uint64) error {
+	if !before.IsZero() {
+		query := `
+			DELETE FROM lua_script_runs
+			WHERE id IN (
+				SELECT id FROM lua_script_runs WHERE started_at < :before LIMIT :batch
+			)
+		`
+
+		if err := dbutil.DeleteInBatches(ctx, rr.db, query, map[string]any{"before": before}); err != nil {
+			return errors.Wrap(dbutil.ErrRemoveEntity, err)
+		}
+	}
+
+	if keep > 0 {
+		// The runs of each assigned script and thing are walked newest first on their index, skipping the kept ones.
+		query := `
+			DELETE FROM lua_script_runs
+			WHERE id IN (
+				SELECT r.id FROM lua_scripts_things st, LATERAL (
+					SELECT id FROM lua_script_runs
+					WHERE script_id = st.lua_script_id AND thing_id = st.thing_id
+					ORDER BY started_at DESC
+					OFFSET :keep
+				) r
+				LIMIT :batch
+			)
+		`
+
+		if err := dbutil.DeleteInBatches(ctx, rr.db, query, map[string]any{"keep": keep}); err != nil {
+			return errors.Wrap(dbutil.ErrRemoveEntity, err)
+		}
+	}
+
+	return nil
+}
+
+func (rr ruleRepository) ClaimScheduledRun(ctx context.Context, scriptID string, trigger int, scheduledAt time.Time) (bool, error) {
+	q := `INSERT INTO lua_script_schedules (script_id, trigger_idx, scheduled_at) VALUES (:script_id, :trigger_idx, :scheduled_at)
+	      ON CONFLICT (script_id, trigger_idx) DO UPDATE SET scheduled_at = EXCLUDED.scheduled_at
+	      WHERE lua_script_schedules.scheduled_at < EXCLUDED.scheduled_at;`
+
+	params := map[string]any{
+		"script_id":    scriptID,
+		"trigger_idx":  trigger,
+		"scheduled_at": scheduledAt,
+	}
+
+	res, err := rr.db.NamedExecContext(ctx, q, params)
+	if err != nil {
+		pgErr, ok := err.(*pgconn.PgError)
+		if ok && pgErr.Code == pgerrcode.ForeignKeyViolation {
+			return false, errors.Wrap(dbutil.ErrNotFound, err)
+		}
+		return false, errors.Wrap(dbutil.ErrUpdateEntity, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(dbutil.ErrUpdateEntity, err)
+	}
+
+	return cnt > 0, nil
+}
+
+func (rr ruleRepository) RetrieveScriptStats(ctx context.Context, scriptID string) (rules.ScriptStats, error) {
+	query := `
+		SELECT script_id, succeeded, failed, total_duration, total_instructions, last_run_at
+		FROM lua_script_stats
+		WHERE script_id = $1;
+	`
+
+	var dbs dbScriptStats
+	if err := rr.db.QueryRowxContext(ctx, query, scriptID).StructScan(&dbs); err != nil {
+		// Scripts that haven't run yet have no stats.
+		if err == sql.ErrNoRows {
+			return rules.ScriptStats{ScriptID: scriptID}, nil
+		}
+
+		pgErr, ok := err.(*pgconn.PgError)
+		if ok && pgErr.Code == pgerrcode.InvalidTextRepresentation {
+			return rules.ScriptStats{}, errors.Wrap(dbutil.ErrMalformedEntity, err)
+		}
+		return rules.ScriptStats{}, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+	}
+
+	return toScriptStats(dbs), nil
+}
+
+func (rr ruleRepository) SaveScriptRunTotals(ctx context.Context, totals ...rules.ScriptRunTotals) error {
+	for _, t := range totals {
+		if _, err := rr.db.NamedExecContext(ctx, addScriptStatsQuery, toDBScriptRunTotals(t)); err != nil {
+			pgErr, ok := err.(*pgconn.PgError)
+			// The totals of scripts removed since they ran are dropped.
+			if ok && pgErr.Code == pgerrcode.ForeignKeyViolation {
+				continue
+			}
+			return errors.Wrap(dbutil.ErrCreateEntity, err)
+		}
+	}
+
+	return nil
+}
+
 func (rr ruleRepository) RetrieveScriptRunsByThing(ctx context.Context, thingID string, pm rules.PageMetadata) (rules.ScriptRunsPage, error) {
 	oq := dbutil.GetOrderQuery(pm.Order, rules.RuleOrderFields)
 	dq := dbutil.GetDirQuery(pm.Dir)
@@ -463,7 +801,7 @@ func (rr ruleRepository) RetrieveScriptRunsByThing(ctx context.Context, thingID
 	whereClause := dbutil.BuildWhereClause(thingQuery, statusQuery, fromQuery, toQuery)
 
 	query := `
-		SELECT id, script_id, thing_id, logs, started_at, finished_at, status, error
+		SELECT id, script_id, thing_id, logs, started_at, finished_at, status, error, version, instruction_count
 		FROM lua_script_runs %s ORDER BY %s %s %s;
 	`
 
@@ -515,43 +853,128 @@ func (rr ruleRepository) RetrieveScriptRunsByThing(ctx context.Context, thingID
 	return page, nil
 }
 
+func (rr ruleRepository) RetrieveScriptValue(ctx context.Context, scriptID, thingID, key string) (rules.ScriptValue, error) {
+	query := `
+		SELECT script_id, thing_id, key, value
+		FROM lua_script_values
+		WHERE script_id = $1 AND thing_id = $2 AND key = $3;
+	`
+
+	var dbv dbScriptValue
+	if err := rr.db.QueryRowxContext(ctx, query, scriptID, thingID, key).StructScan(&dbv); err != nil {
+		pgErr, ok := err.(*pgconn.PgError)
+		if err == sql.ErrNoRows || ok && pgerrcode.InvalidTextRepresentation == pgErr.Code {
+			return rules.ScriptValue{}, errors.Wrap(dbutil.ErrNotFound, err)
+		}
+		return rules.ScriptValue{}, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+	}
+
+	return rules.ScriptValue(dbv), nil
+}
+
+func (rr ruleRepository) SaveScriptValue(ctx context.Context, value rules.ScriptValue) error {
+	query := `
+		INSERT INTO lua_script_values (script_id, thing_id, key, value)
+		VALUES (:script_id, :thing_id, :key, :value)
+		ON CONFLICT (script_id, thing_id, key) DO UPDATE SET value = :value;
+	`
+
+	if _, err := rr.db.NamedExecContext(ctx, query, dbScriptValue(value)); err != nil {
+		pgErr, ok := err.(*pgconn.PgError)
+		if ok {
+			switch pgErr.Code {
+			case pgerrcode.InvalidTextRepresentation:
+				return errors.Wrap(dbutil.ErrMalformedEntity, err)
+			case pgerrcode.ForeignKeyViolation:
+				return errors.Wrap(dbutil.ErrNotFound, err)
+			}
+		}
+		return errors.Wrap(dbutil.ErrCreateEntity, err)
+	}
+
+	return nil
+}
+
+func (rr ruleRepository) RemoveScriptValue(ctx context.Context, scriptID, thingID, key string) error {
+	query := `
+		DELETE FROM lua_script_values
+		WHERE script_id = :script_id AND thing_id = :thing_id AND key = :key
+	`
+
+	dbv := dbScriptValue{ScriptID: scriptID, ThingID: thingID, Key: key}
+	if _, err := rr.db.NamedExecContext(ctx, query, dbv); err != nil {
+		return errors.Wrap(dbutil.ErrRemoveEntity, err)
+	}
+
+	return nil
+}
+
 type dbLuaScript struct {
-	ID          string `db:"id"`
-	GroupID     string `db:"group_id"`
-	Script      string `db:"script"`
-	Name        string `db:"name"`
-	Description string `db:"description"`
+	ID          string         `db:"id"`
+	GroupID     string         `db:"group_id"`
+	Script      string         `db:"script"`
+	Name        string         `db:"name"`
+	Description string         `db:"description"`
+	Triggers    []byte         `db:"triggers"`
+	Version     uint64         `db:"version"`
+	Status      string         `db:"status"`
+	ThingStatus sql.NullString `db:"thing_status"`
 }
 
-func toDBLuaScript(script rules.LuaScript) dbLuaScript {
+func toDBLuaScript(script rules.LuaScript) (dbLuaScript, error) {
+	triggers := []byte("[]")
+	if len(script.Triggers) > 0 {
+		b, err := json.Marshal(script.Triggers)
+		if err != nil {
+			return dbLuaScript{}, errors.Wrap(dbutil.ErrMalformedEntity, err)
+		}
+		triggers = b
+	}
+
 	return dbLuaScript{
 		ID:          script.ID,
 		GroupID:     script.GroupID,
 		Script:      script.Script,
 		Name:        script.Name,
 		Description: script.Description,
-	}
+		Triggers:    triggers,
+		Version:     script.Version,
+		Status:      script.Status,
+	}, nil
 }
 
-func toLuaScript(dbScript dbLuaScript) rules.LuaScript {
+func toLuaScript(dbScript dbLuaScript) (rules.LuaScript, error) {
+	var triggers []rules.Trigger
+	if len(dbScript.Triggers) > 0 {
+		if err := json.Unmarshal(dbScript.Triggers, &triggers); err != nil {
+			return rules.LuaScript{}, errors.Wrap(dbutil.ErrMalformedEntity, err)
+		}
+	}
+
 	return rules.LuaScript{
 		ID:          dbScript.ID,
 		GroupID:     dbScript.GroupID,
 		Script:      dbScript.Script,
 		Name:        dbScript.Name,
 		Description: dbScript.Description,
-	}
+		Triggers:    triggers,
+		Version:     dbScript.Version,
+		Status:      dbScript.Status,
+		ThingStatus: dbScript.ThingStatus.String,
+	}, nil
 }
 
 type dbScriptRun struct {
-	ID         string         `db:"id"`
-	ScriptID   string         `db:"script_id"`
-	ThingID    string         `db:"thing_id"`
-	Logs       []byte         `db:"logs"`
-	StartedAt  time.Time      `db:"started_at"`
-	FinishedAt time.Time      `db:"finished_at"`
-	Status     string         `db:"status"`
-	Error      sql.NullString `db:"error"`
+	ID               string         `db:"id"`
+	ScriptID         string         `db:"script_id"`
+	ThingID          string         `db:"thing_id"`
+	Logs             []byte         `db:"logs"`
+	StartedAt        time.Time      `db:"started_at"`
+	FinishedAt       time.Time      `db:"finished_at"`
+	Status           string         `db:"status"`
+	Error            sql.NullString `db:"error"`
+	Version          uint64         `db:"version"`
+	InstructionCount uint64         `db:"instruction_count"`
 }
 
 func toDBScriptRun(run rules.ScriptRun) (dbScriptRun, error) {
@@ -572,14 +995,16 @@ func toDBScriptRun(run rules.ScriptRun) (dbScriptRun, error) {
 	}
 
 	return dbScriptRun{
-		ID:         run.ID,
-		ScriptID:   run.ScriptID,
-		ThingID:    run.ThingID,
-		Logs:       logsBytes,
-		StartedAt:  run.StartedAt,
-		FinishedAt: run.FinishedAt,
-		Status:     run.Status,
-		Error:      errorField,
+		ID:               run.ID,
+		ScriptID:         run.ScriptID,
+		ThingID:          run.ThingID,
+		Logs:             logsBytes,
+		StartedAt:        run.StartedAt,
+		FinishedAt:       run.FinishedAt,
+		Status:           run.Status,
+		Error:            errorField,
+		Version:          run.Version,
+		InstructionCount: uint64(run.InstructionCount),
 	}, nil
 }
 
@@ -595,13 +1020,65 @@ func toScriptRun(dbs dbScriptRun) (rules.ScriptRun, error) {
 	}
 
 	return rules.ScriptRun{
-		ID:         dbs.ID,
-		ScriptID:   dbs.ScriptID,
-		ThingID:    dbs.ThingID,
-		Logs:       logs,
-		StartedAt:  dbs.StartedAt,
-		FinishedAt: dbs.FinishedAt,
-		Status:     dbs.Status,
-		Error:      errorField,
+		ID:               dbs.ID,
+		ScriptID:         dbs.ScriptID,
+		ThingID:          dbs.ThingID,
+		Logs:             logs,
+		StartedAt:        dbs.StartedAt,
+		FinishedAt:       dbs.FinishedAt,
+		Status:           dbs.Status,
+		Error:            errorField,
+		Version:          dbs.Version,
+		InstructionCount: uint(dbs.InstructionCount),
 	}, nil
 }
+
+type dbScriptValue struct {
+	ScriptID string `db:"script_id"`
+	ThingID  string `db:"thing_id"`
+	Key      string `db:"key"`
+	Value    []byte `db:"value"`
+}
+
+type dbScriptVersion struct {
+	ScriptID  string    `db:"script_id"`
+	Version   uint64    `db:"version"`
+	Script    string    `db:"script"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type dbScriptStats struct {
+	ScriptID          string       `db:"script_id"`
+	Succeeded         uint64       `db:"succeeded"`
+	Failed            uint64       `db:"failed"`
+	TotalDuration     int64        `db:"total_duration"`
+	TotalInstructions uint64       `db:"total_instructions"`
+	LastRunAt         sql.NullTime `db:"last_run_at"`
+}
+
+func toDBScriptRunTotals(t rules.ScriptRunTotals) dbScriptStats {
+	return dbScriptStats{
+		ScriptID:          t.ScriptID,
+		Succeeded:         t.Succeeded,
+		Failed:            t.Failed,
+		TotalDuration:     int64(t.Duration),
+		TotalInstructions: t.InstructionCount,
+		LastRunAt:         sql.NullTime{Time: t.LastRunAt, Valid: true},
+	}
+}
+
+func toScriptStats(dbs dbScriptStats) rules.ScriptStats {
+	stats := rules.ScriptStats{
+		ScriptID:  dbs.ScriptID,
+		Succeeded: dbs.Succeeded,
+		Failed:    dbs.Failed,
+		LastRunAt: dbs.LastRunAt.Time,
+	}
+
+	if runs := dbs.Succeeded + dbs.Failed; runs > 0 {
+		stats.AvgDuration = time.Duration(dbs.TotalDuration / int64(runs))
+		stats.AvgInstructionCount = dbs.TotalInstructions / runs
+	}
+
+	return stats
+}
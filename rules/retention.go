@@ -0,0 +1,44 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// pruneInterval is the interval at which the script runs exceeding the retention limits are removed.
+const pruneInterval = time.Hour
+
+func (rs *rulesService) pruneScriptRunsLoop(ctx context.Context) {
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			rs.pruneScriptRuns(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// pruneScriptRuns removes the script runs older than the configured max age, and all but
+// the configured number of the latest runs of each script and thing.
+func (rs *rulesService) pruneScriptRuns(ctx context.Context) {
+	if rs.scriptsConfig.RunsMaxAge == 0 && rs.scriptsConfig.RunsMaxCount == 0 {
+		return
+	}
+
+	var before time.Time
+	if rs.scriptsConfig.RunsMaxAge > 0 {
+		before = time.Now().Add(-rs.scriptsConfig.RunsMaxAge)
+	}
+
+	if err := rs.rules.PruneScriptRuns(ctx, before, rs.scriptsConfig.RunsMaxCount); err != nil {
+		rs.logger.Error(fmt.Sprintf("removing script runs exceeding the retention limits failed with error: %v", err))
+	}
+}
@@ -1,14 +1,14 @@
 package rules
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"strconv"
-	"strings"
 
 	"github.com/MainfluxLabs/mainflux/pkg/domain"
 	"github.com/MainfluxLabs/mainflux/pkg/errors"
 	"github.com/MainfluxLabs/mainflux/pkg/messaging"
+	"github.com/MainfluxLabs/mainflux/pkg/predicate"
 	protomfx "github.com/MainfluxLabs/mainflux/pkg/proto"
 )
 
@@ -38,15 +38,41 @@ type Rule struct {
 	Input       Input
 	Conditions  []Condition
 	Operator    string
-	Actions     []Action
+	// Expression is a nested condition expression evaluated instead of Conditions and Operator.
+	Expression *ConditionGroup
+	Actions    []Action
+	// Cooldown is the minimum number of seconds between two consecutive firings of the rule for the same thing.
+	Cooldown uint64
+	// OnTransition reports whether the rule fires only when its conditions become met,
+	// rather than on every message that meets them.
+	OnTransition bool
 }
 
 type Condition = domain.Condition
 
+type ConditionGroup = domain.ConditionGroup
+
+// expression returns the rule condition expression. Flat rules are represented
+// as a single group of their conditions joined by the rule operator.
+func (r Rule) expression() ConditionGroup {
+	if r.Expression != nil {
+		return *r.Expression
+	}
+	return ConditionGroup{Operator: r.Operator, Conditions: r.Conditions}
+}
+
 type Action struct {
 	ID    string `json:"id"`
 	Type  string `json:"type"`
 	Level int32  `json:"level,omitempty"`
+	// Target is the kind of command or shadow action recipient identified by ID, a thing or a group.
+	Target string `json:"target,omitempty"`
+	// Subtopic is the subtopic the command is published to. For shadow actions,
+	// it is the name of the updated shadow, and the default shadow if empty.
+	Subtopic string `json:"subtopic,omitempty"`
+	// Payload is the command payload or shadow desired state patch template.
+	// If empty, the message payload is sent.
+	Payload string `json:"payload,omitempty"`
 }
 
 type RulesPage struct {
@@ -59,22 +85,65 @@ const (
 	ActionTypeSMPP    = "smpp"
 	ActionTypeAlarm   = "alarm"
 	ActionTypeWebhook = "webhook"
+	ActionTypeCommand = "command"
+	ActionTypeShadow  = "shadow"
 
-	OperatorAND = "AND"
-	OperatorOR  = "OR"
+	OperatorAND = predicate.OperatorAND
+	OperatorOR  = predicate.OperatorOR
 
-	ComparatorEQ  = "=="
-	ComparatorGTE = ">="
-	ComparatorLTE = "<="
-	ComparatorGT  = ">"
-	ComparatorLT  = "<"
+	ComparatorEQ       = predicate.ComparatorEQ
+	ComparatorNEQ      = predicate.ComparatorNEQ
+	ComparatorGTE      = predicate.ComparatorGTE
+	ComparatorLTE      = predicate.ComparatorLTE
+	ComparatorGT       = predicate.ComparatorGT
+	ComparatorLT       = predicate.ComparatorLT
+	ComparatorContains = predicate.ComparatorContains
+	ComparatorIn       = predicate.ComparatorIn
+	ComparatorNotIn    = predicate.ComparatorNotIn
+	ComparatorMatches  = predicate.ComparatorMatches
 )
 
-func (rs *rulesService) processRule(msg *protomfx.Message, parsedPayload any, rule Rule) error {
-	triggered, err := processPayload(parsedPayload, rule.Conditions, rule.Operator, msg.ContentType)
+func (rs *rulesService) processRule(ctx context.Context, msg *protomfx.Message, parsedPayload any, rule Rule) error {
+	var state *RuleState
+	var prev RuleState
+	if rule.IsStateful() || rule.suppressesFiring() || rule.raisesAlarms() {
+		st, err := rs.retrieveState(ctx, rule.ID, msg.Publisher, msg.Subtopic)
+		if err != nil {
+			return err
+		}
+		prev = st
+		st.Updated = msg.Created
+		state = &st
+	}
+
+	ev := evaluator{contentType: msg.ContentType, now: msg.Created, patterns: rs.patterns}
+	if rule.IsStateful() {
+		ev.state = state
+	}
+	if rule.hasAggregation() {
+		rs.loadWindows(ctx, rule, msg)
+		ev.windows = rs.windows
+		ev.ruleID, ev.thingID, ev.subtopic = rule.ID, msg.Publisher, msg.Subtopic
+	}
+
+	triggered, err := processPayload(parsedPayload, rule.expression(), ev)
 	if err != nil {
 		return err
 	}
+
+	cleared := false
+	if state != nil {
+		cleared = state.Triggered && !triggered
+		triggered = state.fire(rule, triggered, msg.Created)
+		if err := rs.saveState(ctx, prev, *state); err != nil {
+			return err
+		}
+	}
+
+	if cleared && rule.raisesAlarms() {
+		return rs.clearAlarm(msg, rule)
+	}
+
 	if !triggered {
 		return nil
 	}
@@ -82,7 +151,7 @@ func (rs *rulesService) processRule(msg *protomfx.Message, parsedPayload any, ru
 	for _, action := range rule.Actions {
 		switch action.Type {
 		case ActionTypeAlarm:
-			ruleInfo, err := json.Marshal(domain.RuleInfo{Conditions: rule.Conditions, Operator: rule.Operator})
+			ruleInfo, err := json.Marshal(domain.RuleInfo{Conditions: rule.Conditions, Operator: rule.Operator, Expression: rule.Expression})
 			if err != nil {
 				return err
 			}
@@ -110,154 +179,261 @@ func (rs *rulesService) processRule(msg *protomfx.Message, parsedPayload any, ru
 			}
 		case ActionTypeWebhook:
 			webhook := protomfx.Webhook{
-				ThingId: msg.Publisher,
-				Payload: msg.Payload,
-				Created: msg.Created,
+				ThingId:  msg.Publisher,
+				Payload:  msg.Payload,
+				Created:  msg.Created,
+				Subtopic: msg.Subtopic,
 			}
 			if err := rs.pub.PublishWebhook(subjectWebhooks, webhook); err != nil {
 				return err
 			}
+		case ActionTypeCommand:
+			if err := rs.sendCommand(ctx, msg, parsedPayload, action); err != nil {
+				return err
+			}
+		case ActionTypeShadow:
+			if err := rs.updateShadow(ctx, rule, msg, parsedPayload, action); err != nil {
+				return err
+			}
 		}
 	}
 
 	return nil
 }
 
-func processPayload(payload any, conditions []Condition, operator string, contentType string) (bool, error) {
+// raisesAlarms reports whether any of the rule actions is an alarm action.
+func (r Rule) raisesAlarms() bool {
+	for _, action := range r.Actions {
+		if action.Type == ActionTypeAlarm {
+			return true
+		}
+	}
+	return false
+}
+
+// clearAlarm notifies the alarms service that the rule conditions are no longer met
+// for the thing and subtopic of msg, so that the alarm raised by the rule is cleared.
+func (rs *rulesService) clearAlarm(msg *protomfx.Message, rule Rule) error {
+	return rs.pub.PublishAlarm(fmt.Sprintf("%s.%s", subjectAlarms, domain.AlarmOriginRule), protomfx.Alarm{
+		ThingId:  msg.Publisher,
+		Subtopic: msg.Subtopic,
+		Protocol: msg.Protocol,
+		Created:  msg.Created,
+		RuleId:   rule.ID,
+		Cleared:  true,
+	})
+}
+
+// processPayload evaluates the condition expression against the payload. If the evaluator
+// keeps state or aggregation windows, or records met conditions, every item of an array
+// payload is evaluated so that they reflect the whole message.
+func processPayload(payload any, expr ConditionGroup, ev evaluator) (bool, error) {
+	ev.count = countConditions(expr)
+
 	switch data := payload.(type) {
 	case []any:
+		triggered := false
 		for _, item := range data {
 			obj, ok := item.(map[string]any)
 			if !ok {
 				continue
 			}
-			triggered, err := checkConditionsMet(obj, conditions, operator, contentType)
-			if err != nil {
-				return false, err
-			}
-			if triggered {
+			met := ev.evaluate(obj, expr)
+			if met && !ev.tracksHistory() && ev.met == nil {
 				return true, nil
 			}
+			triggered = triggered || met
 		}
-		return false, nil
+		return triggered, nil
 	case map[string]any:
-		return checkConditionsMet(data, conditions, operator, contentType)
+		return ev.evaluate(data, expr), nil
 	default:
 		return false, errors.ErrInvalidPayload
 	}
 }
 
-func checkConditionsMet(payloadMap map[string]any, conditions []Condition, operator, contentType string) (bool, error) {
-	results := make([]bool, len(conditions))
+// evaluator evaluates a condition expression against a single payload object.
+type evaluator struct {
+	payload     map[string]any
+	contentType string
+	// now is the creation time (unix nanoseconds) of the evaluated message.
+	now   int64
+	state *RuleState
+	// windows holds the aggregation windows of the rule identified by ruleID, for thingID and subtopic.
+	windows  *windowStore
+	ruleID   string
+	thingID  string
+	subtopic string
+	// count is the total number of conditions in the expression and next is the
+	// position of the next condition, in depth-first order, used to index its state.
+	count int
+	next  int
+	// met, if set, records which conditions have been met, indexed by condition position.
+	met []bool
+	// patterns caches the compiled patterns of matches conditions. Patterns are compiled
+	// on every evaluation if it's nil.
+	patterns *predicate.Patterns
+}
 
-	for i, condition := range conditions {
-		value := findPayloadParam(payloadMap, condition.Field, contentType)
-		if value == nil {
-			results[i] = false
-			continue
-		}
+func (ev *evaluator) evaluate(payload map[string]any, expr ConditionGroup) bool {
+	ev.payload, ev.next = payload, 0
+	return ev.evaluateGroup(expr)
+}
 
-		var payloadValue float64
-		switch v := value.(type) {
-		case string:
-			val, err := strconv.ParseFloat(v, 64)
-			if err != nil {
-				return false, err
-			}
-			payloadValue = val
-		case float64:
-			payloadValue = v
-		case int:
-			payloadValue = float64(v)
-		case int32:
-			payloadValue = float64(v)
-		case int64:
-			payloadValue = float64(v)
-		case uint:
-			payloadValue = float64(v)
-		case uint64:
-			payloadValue = float64(v)
-		default:
-			results[i] = false
-			continue
-		}
+// tracksHistory reports whether the evaluation depends on previously evaluated payloads.
+func (ev *evaluator) tracksHistory() bool {
+	return ev.state != nil || ev.windows != nil
+}
+
+// evaluateGroup evaluates all conditions and subgroups of the group, without
+// short-circuiting so that the state of every stateful condition gets updated.
+func (ev *evaluator) evaluateGroup(group ConditionGroup) bool {
+	results := make([]bool, 0, len(group.Conditions)+len(group.Groups))
 
-		results[i] = isConditionMet(condition.Comparator, payloadValue, *condition.Threshold)
+	for _, condition := range group.Conditions {
+		results = append(results, ev.evaluateCondition(condition))
+	}
+	for _, subgroup := range group.Groups {
+		results = append(results, ev.evaluateGroup(subgroup))
 	}
 
-	if operator == OperatorOR {
+	if group.Operator == OperatorOR {
 		for _, r := range results {
 			if r {
-				return true, nil
+				return true
 			}
 		}
-		return false, nil
+		return false
 	}
 
+	// An empty AND group is met, as are the rules without conditions.
 	for _, r := range results {
 		if !r {
-			return false, nil
+			return false
+		}
+	}
+	return true
+}
+
+func (ev *evaluator) evaluateCondition(condition Condition) bool {
+	idx := ev.next
+	ev.next++
+
+	met := ev.conditionMet(idx, condition)
+	if met && ev.met != nil {
+		ev.met[idx] = true
+	}
+
+	return met
+}
+
+func (ev *evaluator) conditionMet(idx int, condition Condition) bool {
+	value := findPayloadParam(ev.payload, condition.Field, ev.contentType)
+	if value == nil {
+		return false
+	}
+
+	if condition.Aggregation != nil {
+		agg, ok := ev.aggregate(idx, condition, value)
+		if !ok {
+			return false
 		}
+		value = agg
+	}
+
+	if ev.state != nil && isStatefulCondition(condition) {
+		cs := ev.state.conditionState(idx, ev.count)
+		return evaluateStatefulCondition(condition, value, cs, ev.now, ev.patterns)
+	}
+
+	return isConditionMet(condition, value, ev.patterns)
+}
+
+// aggregate adds the value to the window of the condition at index idx and returns the window aggregate.
+func (ev *evaluator) aggregate(idx int, condition Condition, value any) (float64, bool) {
+	if ev.windows == nil {
+		return 0, false
+	}
+
+	s, ok := newSample(*condition.Aggregation, value, ev.now)
+	if !ok {
+		return 0, false
 	}
-	return true, nil
+
+	key := windowKey{ruleID: ev.ruleID, thingID: ev.thingID, subtopic: ev.subtopic, idx: idx}
+	return ev.windows.observe(key, *condition.Aggregation, s)
+}
+
+func countConditions(group ConditionGroup) int {
+	n := len(group.Conditions)
+	for _, subgroup := range group.Groups {
+		n += countConditions(subgroup)
+	}
+	return n
 }
 
-func isConditionMet(comparator string, val1, val2 float64) bool {
-	switch comparator {
+// isConditionMet compares a payload value against the condition. Numeric comparators
+// are used when the condition has a threshold, otherwise the condition value is compared.
+func isConditionMet(condition Condition, value any, patterns *predicate.Patterns) bool {
+	switch condition.Comparator {
+	case ComparatorContains:
+		return predicate.Contains(value, condition.Value)
+	case ComparatorIn:
+		return predicate.In(value, condition.Value)
+	case ComparatorNotIn:
+		return predicate.NotIn(value, condition.Value)
+	case ComparatorMatches:
+		return patterns.Matches(value, condition.Value)
+	}
+
+	if condition.Threshold != nil {
+		payloadValue, ok := predicate.ToFloat(value)
+		if !ok {
+			return false
+		}
+		return predicate.CompareNumbers(condition.Comparator, payloadValue, *condition.Threshold)
+	}
+
+	switch condition.Comparator {
 	case ComparatorEQ:
-		return val1 == val2
-	case ComparatorGTE:
-		return val1 >= val2
-	case ComparatorLTE:
-		return val1 <= val2
-	case ComparatorGT:
-		return val1 > val2
-	case ComparatorLT:
-		return val1 < val2
+		return predicate.Equal(value, condition.Value)
+	case ComparatorNEQ:
+		return !predicate.Equal(value, condition.Value)
 	default:
 		return false
 	}
 }
 
+// patternCacheSize is the number of compiled matches condition patterns kept by the service.
+const patternCacheSize = 1000
+
+// senMLValueKeys lists the keys holding a SenML record value, in both normalized and raw SenML form.
+var senMLValueKeys = []string{"value", "string_value", "bool_value", "data_value", "v", "vs", "vb", "vd"}
+
 func findPayloadParam(payload map[string]any, param string, contentType string) any {
 	switch contentType {
 	case messaging.SenMLContentType:
-		if name, ok := payload["name"].(string); ok && name == param {
-			if value, exists := payload["value"]; exists {
+		name, ok := payload["name"].(string)
+		if !ok {
+			name, ok = payload["n"].(string)
+		}
+		if !ok || name != param {
+			return nil
+		}
+		for _, key := range senMLValueKeys {
+			if value, exists := payload[key]; exists {
 				return value
 			}
 		}
 		return nil
 	case messaging.JSONContentType:
-		return findParam(payload, param)
+		value, _ := predicate.Field(payload, param)
+		return value
 	default:
 		return nil
 	}
 }
 
-func findParam(payload map[string]any, param string) any {
-	if param == "" {
-		return nil
-	}
-
-	parts := strings.Split(param, ".")
-	current := payload
-
-	for _, key := range parts[:len(parts)-1] {
-		nested, ok := current[key].(map[string]any)
-		if !ok {
-			return nil
-		}
-		current = nested
-	}
-
-	val, ok := current[parts[len(parts)-1]]
-	if !ok {
-		return nil
-	}
-	return val
-}
-
 // RuleOrderFields maps API-facing order keys to SQL column expressions for the rules table.
 var RuleOrderFields = map[string]string{
 	"id":   "id",
@@ -7,13 +7,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"text/template"
 	"time"
 
 	"github.com/MainfluxLabs/mainflux/consumers"
 	"github.com/MainfluxLabs/mainflux/logger"
 	"github.com/MainfluxLabs/mainflux/pkg/domain"
 	"github.com/MainfluxLabs/mainflux/pkg/errors"
+	"github.com/MainfluxLabs/mainflux/pkg/lru"
 	"github.com/MainfluxLabs/mainflux/pkg/messaging"
+	"github.com/MainfluxLabs/mainflux/pkg/predicate"
 	protomfx "github.com/MainfluxLabs/mainflux/pkg/proto"
 	"github.com/MainfluxLabs/mainflux/pkg/uuid"
 )
@@ -32,7 +35,6 @@ type PageMetadata struct {
 	To        time.Time `json:"to,omitempty"`
 }
 
-
 // Service specifies an API that must be fullfiled by the domain service
 // implementation, and all of its decorators (e.g. logging & metrics).
 // All methods that accept a token parameter use it to identify and authorize
@@ -43,6 +45,10 @@ type Service interface {
 
 	consumers.MessageConsumer
 	consumers.AlarmConsumer
+
+	// TransformMessage transforms the message with the transform script identified by the subject,
+	// and publishes it for storage, unless the script drops it.
+	TransformMessage(subject string, msg protomfx.Message) error
 }
 
 type ServiceScripts interface {
@@ -61,9 +67,25 @@ type ServiceScripts interface {
 	// ViewScript retrieves a specific Script by its ID.
 	ViewScript(ctx context.Context, token, id string) (LuaScript, error)
 
-	// UpdateScript updates the Script identified by the provided ID.
+	// UpdateScript updates the Script identified by the provided ID. A new version of the
+	// Script is created if its content changes.
 	UpdateScript(ctx context.Context, token string, script LuaScript) error
 
+	// ListScriptVersions retrieves a list of versions of a specific Script.
+	ListScriptVersions(ctx context.Context, token, scriptID string, pm PageMetadata) (ScriptVersionsPage, error)
+
+	// RollbackScript creates a new version of the Script having the content of the provided version.
+	RollbackScript(ctx context.Context, token, scriptID string, version uint64) error
+
+	// EnableScript enables the Script identified by the provided ID.
+	EnableScript(ctx context.Context, token, id string) error
+
+	// DisableScript disables the Script identified by the provided ID, so that it doesn't run.
+	DisableScript(ctx context.Context, token, id string) error
+
+	// ViewScriptStats retrieves the run statistics of a specific Script.
+	ViewScriptStats(ctx context.Context, token, id string) (ScriptStats, error)
+
 	// RemoveScripts removes the Scripts identified by the provided IDs.
 	RemoveScripts(ctx context.Context, token string, ids ...string) error
 
@@ -79,11 +101,43 @@ type ServiceScripts interface {
 	// UnassignScriptsFromThing unassigns all scripts from a specific Thing.
 	UnassignScriptsFromThing(ctx context.Context, thingID string) error
 
+	// EnableThingScripts enables one or more Scripts assigned to a specific Thing for that Thing.
+	EnableThingScripts(ctx context.Context, token, thingID string, scriptIDs ...string) error
+
+	// DisableThingScripts disables one or more Scripts assigned to a specific Thing for that Thing.
+	DisableThingScripts(ctx context.Context, token, thingID string, scriptIDs ...string) error
+
 	// ListScriptRunsByThing retrieves a list of Script Runs associated with a specific Thing.
 	ListScriptRunsByThing(ctx context.Context, token, thingID string, pm PageMetadata) (ScriptRunsPage, error)
 
 	// RemoveScriptRuns removes the Runs identified by the provided IDs.
 	RemoveScriptRuns(ctx context.Context, token string, ids ...string) error
+
+	// TestScript runs the script against a sample message of the group, without publishing
+	// anything or persisting the runs.
+	TestScript(ctx context.Context, token, groupID string, script LuaScript, msg protomfx.Message) ([]ScriptTestResult, error)
+
+	// CreateModules persists multiple Lua modules of a specific Group.
+	CreateModules(ctx context.Context, token, groupID string, modules ...LuaModule) ([]LuaModule, error)
+
+	// ListModulesByGroup retrieves a list of Lua modules belonging to a specific Group.
+	ListModulesByGroup(ctx context.Context, token, groupID string, pm PageMetadata) (LuaModulesPage, error)
+
+	// ViewModule retrieves a specific Lua module by its ID.
+	ViewModule(ctx context.Context, token, id string) (LuaModule, error)
+
+	// UpdateModule updates the Lua module identified by the provided ID.
+	UpdateModule(ctx context.Context, token string, module LuaModule) error
+
+	// RemoveModules removes the Lua modules identified by the provided IDs.
+	RemoveModules(ctx context.Context, token string, ids ...string) error
+
+	// RemoveModulesByGroup removes all Lua modules belonging to a specific Group.
+	RemoveModulesByGroup(ctx context.Context, groupID string) error
+
+	// LoadAndScheduleScripts loads the scripts having schedule triggers and schedules their runs,
+	// and periodically removes the script runs exceeding the retention limits.
+	LoadAndScheduleScripts(ctx context.Context) error
 }
 
 type ServiceRules interface {
@@ -119,6 +173,10 @@ type ServiceRules interface {
 
 	// UnassignRulesFromThing unassigns all rules from the given thing.
 	UnassignRulesFromThing(ctx context.Context, thingID string) error
+
+	// TestRule evaluates the rule against a sample message of the group and reports the met
+	// conditions and the actions that would be triggered, without publishing anything.
+	TestRule(ctx context.Context, token, groupID string, rule Rule, msg protomfx.Message) (RuleTestResult, error)
 }
 
 const (
@@ -126,6 +184,8 @@ const (
 	subjectAlarms = "alarms"
 	// subjectSMTP represents subject used to publish messages that trigger an SMTP notification.
 	subjectSMTP = "smtp"
+	// subjectSMPP represents subject used to publish messages that trigger an SMPP notification.
+	subjectSMPP = "smpp"
 	// subjectWebhooks represents subject used to publish messages that trigger webhook forwarding.
 	subjectWebhooks = "webhooks"
 )
@@ -135,29 +195,71 @@ type Publisher interface {
 	messaging.AlarmPublisher
 	messaging.NotificationPublisher
 	messaging.WebhookPublisher
+	messaging.CommandPublisher
+
+	// Publish publishes the message to the subject.
+	Publish(subject string, msg protomfx.Message) error
+}
+
+// ScriptsConfig configures the Lua scripting engine.
+type ScriptsConfig struct {
+	Enabled bool
+	// HTTPAllowlist lists the hosts scripts may send HTTP requests to. A host
+	// starting with "*." matches its subdomains. Scripts can't send HTTP
+	// requests if it is empty.
+	HTTPAllowlist []string
+	// RunsMaxAge is the age after which script runs are removed. Runs are kept
+	// regardless of their age if it is zero.
+	RunsMaxAge time.Duration
+	// RunsMaxCount is the number of the latest runs kept per script and thing.
+	// Runs are kept regardless of their count if it is zero.
+	RunsMaxCount uint64
 }
 
 type rulesService struct {
-	rules          Repository
-	things         domain.ThingsClient
-	readers        domain.ReadersClient
-	pub            Publisher
-	idProvider     uuid.IDProvider
-	logger         logger.Logger
-	scriptsEnabled bool
+	rules         Repository
+	things        domain.ThingsClient
+	readers       domain.ReadersClient
+	shadows       domain.ShadowsClient
+	pub           Publisher
+	windows       *windowStore
+	states        *lru.Cache[stateKey, RuleState]
+	patterns      *predicate.Patterns
+	templates     *lru.Cache[string, *template.Template]
+	idProvider    uuid.IDProvider
+	logger        logger.Logger
+	scriptsConfig ScriptsConfig
+	scriptsHTTP   *scriptHTTPClient
+	scheduler     *scriptScheduler
+	scriptStats   *scriptStatsBuffer
+	// transformScripts and thingGroups cache the lookups made for every transformed message.
+	transformScripts *lru.Cache[string, LuaScript]
+	thingGroups      *lru.Cache[string, string]
 }
 
 var _ Service = (*rulesService)(nil)
 
 // New instantiates the rules service implementation.
-func New(rules Repository, things domain.ThingsClient, readers domain.ReadersClient, pub Publisher, idp uuid.IDProvider, logger logger.Logger, scriptsEnabled bool) Service {
+func New(rules Repository, things domain.ThingsClient, readers domain.ReadersClient, shadows domain.ShadowsClient, pub Publisher, idp uuid.IDProvider, logger logger.Logger, scriptsConfig ScriptsConfig) Service {
 	return &rulesService{
-		rules:          rules,
-		things:         things,
-		pub:            pub,
-		idProvider:     idp,
-		logger:         logger,
-		scriptsEnabled: scriptsEnabled,
+		rules:         rules,
+		things:        things,
+		readers:       readers,
+		shadows:       shadows,
+		pub:           pub,
+		windows:       newWindowStore(windowCacheSize, windowTTL),
+		states:        lru.New[stateKey, RuleState](stateCacheSize, stateCacheTTL),
+		patterns:      predicate.NewPatterns(patternCacheSize),
+		templates:     lru.New[string, *template.Template](templateCacheSize, 0),
+		idProvider:    idp,
+		logger:        logger,
+		scriptsConfig: scriptsConfig,
+		scriptsHTTP:   newScriptHTTPClient(scriptsConfig.HTTPAllowlist),
+		scheduler:     newScriptScheduler(),
+		scriptStats:   newScriptStatsBuffer(),
+
+		transformScripts: lru.New[string, LuaScript](transformCacheSize, transformCacheTTL),
+		thingGroups:      lru.New[string, string](transformCacheSize, transformCacheTTL),
 	}
 }
 
@@ -244,7 +346,14 @@ func (rs *rulesService) UpdateRule(ctx context.Context, token string, rule Rule)
 		return err
 	}
 
-	return rs.rules.Update(ctx, rule)
+	if err := rs.rules.Update(ctx, rule); err != nil {
+		return err
+	}
+
+	// Conditions may have changed, so previously accumulated state no longer applies.
+	rs.windows.remove(rule.ID)
+	rs.removeStates(rule.ID)
+	return rs.rules.RemoveStates(ctx, rule.ID)
 }
 
 func (rs *rulesService) AssignThings(ctx context.Context, token, ruleID string, thingIDs ...string) error {
@@ -280,7 +389,13 @@ func (rs *rulesService) UnassignThings(ctx context.Context, token, ruleID string
 		return err
 	}
 
-	return rs.rules.UnassignThings(ctx, ruleID, thingIDs...)
+	if err := rs.rules.UnassignThings(ctx, ruleID, thingIDs...); err != nil {
+		return err
+	}
+
+	rs.windows.removeRuleThings(ruleID, thingIDs...)
+	rs.removeStates(ruleID, thingIDs...)
+	return nil
 }
 
 func (rs *rulesService) RemoveRules(ctx context.Context, token string, ids ...string) error {
@@ -295,15 +410,62 @@ func (rs *rulesService) RemoveRules(ctx context.Context, token string, ids ...st
 		}
 	}
 
-	return rs.rules.Remove(ctx, ids...)
+	if err := rs.rules.Remove(ctx, ids...); err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		rs.windows.remove(id)
+		rs.removeStates(id)
+	}
+
+	return nil
 }
 
 func (rs *rulesService) RemoveRulesByGroup(ctx context.Context, groupID string) error {
-	return rs.rules.RemoveByGroup(ctx, groupID)
+	page, err := rs.rules.RetrieveByGroup(ctx, groupID, PageMetadata{})
+	if err != nil {
+		return err
+	}
+
+	if err := rs.rules.RemoveByGroup(ctx, groupID); err != nil {
+		return err
+	}
+
+	for _, rule := range page.Rules {
+		rs.windows.remove(rule.ID)
+		rs.removeStates(rule.ID)
+	}
+
+	return nil
 }
 
 func (rs *rulesService) UnassignRulesFromThing(ctx context.Context, thingID string) error {
-	return rs.rules.UnassignRulesFromThing(ctx, thingID)
+	if err := rs.rules.UnassignRulesFromThing(ctx, thingID); err != nil {
+		return err
+	}
+
+	rs.windows.removeThing(thingID)
+	rs.removeThingStates(thingID)
+	return nil
+}
+
+func (rs *rulesService) TestRule(ctx context.Context, token, groupID string, rule Rule, msg protomfx.Message) (RuleTestResult, error) {
+	if err := rs.things.CanUserAccessGroup(ctx, domain.UserAccessReq{Token: token, ID: groupID, Action: domain.GroupViewer}); err != nil {
+		return RuleTestResult{}, err
+	}
+
+	if err := rs.validateSample(ctx, groupID, msg); err != nil {
+		return RuleTestResult{}, err
+	}
+
+	var payload any
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return RuleTestResult{}, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	rule.GroupID = groupID
+	return testRule(rule, &msg, payload)
 }
 
 func (rs *rulesService) CreateScripts(ctx context.Context, token, groupID string, scripts ...LuaScript) ([]LuaScript, error) {
@@ -314,6 +476,8 @@ func (rs *rulesService) CreateScripts(ctx context.Context, token, groupID string
 
 	for i := range scripts {
 		scripts[i].GroupID = groupID
+		scripts[i].Version = 1
+		scripts[i].Status = ScriptEnabledStatus
 
 		id, err := rs.idProvider.ID()
 		if err != nil {
@@ -322,7 +486,18 @@ func (rs *rulesService) CreateScripts(ctx context.Context, token, groupID string
 		scripts[i].ID = id
 	}
 
-	return rs.rules.SaveScripts(ctx, scripts...)
+	saved, err := rs.rules.SaveScripts(ctx, scripts...)
+	if err != nil {
+		return []LuaScript{}, err
+	}
+
+	for _, script := range saved {
+		if err := rs.scheduleScript(script); err != nil {
+			rs.logger.Error(fmt.Sprintf("scheduling script with id %s failed with error: %v", script.ID, err))
+		}
+	}
+
+	return saved, nil
 }
 
 func (rs *rulesService) ListScriptsByThing(ctx context.Context, token, thingID string, pm PageMetadata) (LuaScriptsPage, error) {
@@ -353,7 +528,7 @@ func (rs *rulesService) ListThingIDsByScript(ctx context.Context, token, scriptI
 		return []string{}, err
 	}
 
-	return rs.rules.RetrieveThingIDsByScript(ctx, scriptID)
+	return rs.rules.RetrieveThingIDsByScript(ctx, scriptID, "")
 }
 
 func (rs *rulesService) ViewScript(ctx context.Context, token, id string) (LuaScript, error) {
@@ -379,7 +554,92 @@ func (rs *rulesService) UpdateScript(ctx context.Context, token string, script L
 		return err
 	}
 
-	return rs.rules.UpdateScript(ctx, script)
+	if err := rs.rules.UpdateScript(ctx, script); err != nil {
+		return err
+	}
+	rs.transformScripts.Remove(script.ID)
+
+	script.GroupID = existingScript.GroupID
+	script.Status = existingScript.Status
+	if err := rs.scheduleScript(script); err != nil {
+		rs.logger.Error(fmt.Sprintf("scheduling script with id %s failed with error: %v", script.ID, err))
+	}
+
+	return nil
+}
+
+func (rs *rulesService) ListScriptVersions(ctx context.Context, token, scriptID string, pm PageMetadata) (ScriptVersionsPage, error) {
+	if _, err := rs.ViewScript(ctx, token, scriptID); err != nil {
+		return ScriptVersionsPage{}, err
+	}
+
+	return rs.rules.RetrieveScriptVersions(ctx, scriptID, pm)
+}
+
+func (rs *rulesService) RollbackScript(ctx context.Context, token, scriptID string, version uint64) error {
+	script, err := rs.rules.RetrieveScriptByID(ctx, scriptID)
+	if err != nil {
+		return err
+	}
+
+	if err := rs.things.CanUserAccessGroup(ctx, domain.UserAccessReq{Token: token, ID: script.GroupID, Action: domain.GroupEditor}); err != nil {
+		return err
+	}
+
+	sv, err := rs.rules.RetrieveScriptVersion(ctx, scriptID, version)
+	if err != nil {
+		return err
+	}
+
+	script.Script = sv.Script
+	if err := rs.rules.UpdateScript(ctx, script); err != nil {
+		return err
+	}
+	rs.transformScripts.Remove(scriptID)
+
+	return nil
+}
+
+func (rs *rulesService) EnableScript(ctx context.Context, token, id string) error {
+	return rs.changeScriptStatus(ctx, token, id, ScriptEnabledStatus)
+}
+
+func (rs *rulesService) DisableScript(ctx context.Context, token, id string) error {
+	return rs.changeScriptStatus(ctx, token, id, ScriptDisabledStatus)
+}
+
+func (rs *rulesService) changeScriptStatus(ctx context.Context, token, id, status string) error {
+	script, err := rs.rules.RetrieveScriptByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := rs.things.CanUserAccessGroup(ctx, domain.UserAccessReq{Token: token, ID: script.GroupID, Action: domain.GroupEditor}); err != nil {
+		return err
+	}
+
+	if err := rs.rules.UpdateScriptStatus(ctx, id, status); err != nil {
+		return err
+	}
+	rs.transformScripts.Remove(id)
+
+	script.Status = status
+	if err := rs.scheduleScript(script); err != nil {
+		rs.logger.Error(fmt.Sprintf("scheduling script with id %s failed with error: %v", script.ID, err))
+	}
+
+	return nil
+}
+
+func (rs *rulesService) ViewScriptStats(ctx context.Context, token, id string) (ScriptStats, error) {
+	if _, err := rs.ViewScript(ctx, token, id); err != nil {
+		return ScriptStats{}, err
+	}
+
+	// The stats include the runs buffered by this instance.
+	rs.flushScriptStats(ctx)
+
+	return rs.rules.RetrieveScriptStats(ctx, id)
 }
 
 func (rs *rulesService) RemoveScripts(ctx context.Context, token string, ids ...string) error {
@@ -394,11 +654,26 @@ func (rs *rulesService) RemoveScripts(ctx context.Context, token string, ids ...
 		}
 	}
 
-	return rs.rules.RemoveScripts(ctx, ids...)
+	if err := rs.rules.RemoveScripts(ctx, ids...); err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		rs.unscheduleScript(id)
+		rs.transformScripts.Remove(id)
+	}
+
+	return nil
 }
 
 func (rs *rulesService) RemoveScriptsByGroup(ctx context.Context, groupID string) error {
-	return rs.rules.RemoveScriptsByGroup(ctx, groupID)
+	if err := rs.rules.RemoveScriptsByGroup(ctx, groupID); err != nil {
+		return err
+	}
+
+	rs.unscheduleGroupScripts(groupID)
+
+	return nil
 }
 
 func (rs *rulesService) AssignScripts(ctx context.Context, token, thingID string, scriptIDs ...string) error {
@@ -445,6 +720,22 @@ func (rs *rulesService) UnassignScriptsFromThing(ctx context.Context, thingID st
 	return rs.rules.UnassignScriptsFromThing(ctx, thingID)
 }
 
+func (rs *rulesService) EnableThingScripts(ctx context.Context, token, thingID string, scriptIDs ...string) error {
+	if err := rs.things.CanUserAccessThing(ctx, domain.UserAccessReq{Token: token, ID: thingID, Action: domain.GroupEditor}); err != nil {
+		return err
+	}
+
+	return rs.rules.UpdateThingScriptsStatus(ctx, thingID, ScriptEnabledStatus, scriptIDs...)
+}
+
+func (rs *rulesService) DisableThingScripts(ctx context.Context, token, thingID string, scriptIDs ...string) error {
+	if err := rs.things.CanUserAccessThing(ctx, domain.UserAccessReq{Token: token, ID: thingID, Action: domain.GroupEditor}); err != nil {
+		return err
+	}
+
+	return rs.rules.UpdateThingScriptsStatus(ctx, thingID, ScriptDisabledStatus, scriptIDs...)
+}
+
 func (rs *rulesService) ListScriptRunsByThing(ctx context.Context, token, thingID string, pm PageMetadata) (ScriptRunsPage, error) {
 	if err := rs.things.CanUserAccessThing(ctx, domain.UserAccessReq{Token: token, ID: thingID, Action: domain.GroupViewer}); err != nil {
 		return ScriptRunsPage{}, err
@@ -468,6 +759,24 @@ func (rs *rulesService) RemoveScriptRuns(ctx context.Context, token string, ids
 	return rs.rules.RemoveScriptRuns(ctx, ids...)
 }
 
+func (rs *rulesService) TestScript(ctx context.Context, token, groupID string, script LuaScript, msg protomfx.Message) ([]ScriptTestResult, error) {
+	if err := rs.things.CanUserAccessGroup(ctx, domain.UserAccessReq{Token: token, ID: groupID, Action: domain.GroupViewer}); err != nil {
+		return nil, err
+	}
+
+	if err := rs.validateSample(ctx, groupID, msg); err != nil {
+		return nil, err
+	}
+
+	var payload any
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	script.GroupID = groupID
+	return rs.testScript(script, &msg, payload)
+}
+
 func (rs *rulesService) ConsumeMessage(_ string, msg protomfx.Message) error {
 	ctx := context.Background()
 
@@ -485,26 +794,27 @@ func (rs *rulesService) ConsumeMessage(_ string, msg protomfx.Message) error {
 		if sub := rule.Input.Config.Subtopic(); sub != "" && sub != msg.Subtopic {
 			continue
 		}
-		if err := rs.processRule(&msg, payload, rule); err != nil {
+		if err := rs.processRule(ctx, &msg, payload, rule); err != nil {
 			rs.logger.Error(fmt.Sprintf("processing rule with id %s failed with error: %v", rule.ID, err))
 		}
 	}
 
-	if !rs.scriptsEnabled {
+	if !rs.scriptsConfig.Enabled {
 		return nil
 	}
 
-	scriptsPage, err := rs.rules.RetrieveScriptsByThing(ctx, msg.Publisher, PageMetadata{})
-	if err != nil {
-		return err
-	}
-
-	rs.processLuaScripts(ctx, &msg, payload, scriptsPage.Scripts...)
-
-	return nil
+	return rs.processTriggeredScripts(ctx, &msg, payload, map[string]any{
+		"type":     TriggerTypeMessage,
+		"subtopic": msg.Subtopic,
+	})
 }
 
 func (rs *rulesService) ConsumeAlarm(_ string, alarm protomfx.Alarm) error {
+	// Alarm clearances aren't raised alarms and don't trigger alarm input rules.
+	if alarm.Cleared {
+		return nil
+	}
+
 	ctx := context.Background()
 
 	page, err := rs.rules.RetrieveByThing(ctx, alarm.ThingId, PageMetadata{InputType: InputTypeAlarm})
@@ -539,11 +849,17 @@ func (rs *rulesService) ConsumeAlarm(_ string, alarm protomfx.Alarm) error {
 			continue
 		}
 
-		triggered, err := processPayload(body, rule.Conditions, rule.Operator, messaging.JSONContentType)
+		triggered, err := processPayload(body, rule.expression(), evaluator{contentType: messaging.JSONContentType, patterns: rs.patterns})
 		if err != nil {
 			rs.logger.Error(fmt.Sprintf("evaluating alarm rule with id %s failed with error: %v", rule.ID, err))
 			continue
 		}
+		if rule.suppressesFiring() {
+			if triggered, err = rs.fireAlarmRule(ctx, rule, alarm, triggered); err != nil {
+				rs.logger.Error(fmt.Sprintf("updating state of alarm rule with id %s failed with error: %v", rule.ID, err))
+				continue
+			}
+		}
 		if !triggered {
 			continue
 		}
@@ -563,11 +879,16 @@ func (rs *rulesService) ConsumeAlarm(_ string, alarm protomfx.Alarm) error {
 				err = rs.pub.PublishNotification(subject, notification)
 			case ActionTypeWebhook:
 				webhook := protomfx.Webhook{
-					ThingId: msg.Publisher,
-					Payload: msg.Payload,
-					Created: msg.Created,
+					ThingId:  msg.Publisher,
+					Payload:  msg.Payload,
+					Created:  msg.Created,
+					Subtopic: msg.Subtopic,
 				}
 				err = rs.pub.PublishWebhook(subjectWebhooks, webhook)
+			case ActionTypeCommand:
+				err = rs.sendCommand(ctx, &msg, body, action)
+			case ActionTypeShadow:
+				err = rs.updateShadow(ctx, rule, &msg, body, action)
 			default:
 				continue
 			}
@@ -577,9 +898,64 @@ func (rs *rulesService) ConsumeAlarm(_ string, alarm protomfx.Alarm) error {
 		}
 	}
 
+	if !rs.scriptsConfig.Enabled {
+		return nil
+	}
+
+	// Scripts get the alarm as a plain payload, with the rule info decoded.
+	var scriptPayload any
+	if err := json.Unmarshal(payload, &scriptPayload); err != nil {
+		return err
+	}
+
+	return rs.processTriggeredScripts(ctx, &msg, scriptPayload, map[string]any{
+		"type":     TriggerTypeAlarm,
+		"subtopic": alarm.Subtopic,
+		"level":    alarm.Level,
+		"rule_id":  alarm.RuleId,
+	})
+}
+
+// processTriggeredScripts runs the scripts of the thing that published msg which are triggered by
+// the event described by trigger.
+func (rs *rulesService) processTriggeredScripts(ctx context.Context, msg *protomfx.Message, payload any, trigger map[string]any) error {
+	page, err := rs.rules.RetrieveScriptsByThing(ctx, msg.Publisher, PageMetadata{Status: ScriptEnabledStatus})
+	if err != nil {
+		return err
+	}
+
+	triggerType, _ := trigger["type"].(string)
+
+	var scripts []LuaScript
+	for _, script := range page.Scripts {
+		if script.triggeredBy(triggerType, msg.Subtopic) {
+			scripts = append(scripts, script)
+		}
+	}
+
+	rs.processLuaScripts(ctx, msg, payload, trigger, scripts...)
+
 	return nil
 }
 
+// fireAlarmRule applies the firing policy of the alarm rule to its evaluation result
+// and persists the rule state.
+func (rs *rulesService) fireAlarmRule(ctx context.Context, rule Rule, alarm protomfx.Alarm, triggered bool) (bool, error) {
+	state, err := rs.retrieveState(ctx, rule.ID, alarm.ThingId, alarm.Subtopic)
+	if err != nil {
+		return false, err
+	}
+	prev := state
+	state.Updated = alarm.Created
+
+	fired := state.fire(rule, triggered, alarm.Created)
+	if err := rs.saveState(ctx, prev, state); err != nil {
+		return false, err
+	}
+
+	return fired, nil
+}
+
 type Repository interface {
 	RepositoryRules
 	RepositoryScripts
@@ -621,6 +997,16 @@ type RepositoryRules interface {
 
 	// UnassignRulesFromThing unassigns all rules from the given thing.
 	UnassignRulesFromThing(ctx context.Context, thingID string) error
+
+	// RetrieveState retrieves the evaluation state of a rule for the given thing and subtopic.
+	// If no state has been saved yet, an empty state is returned.
+	RetrieveState(ctx context.Context, ruleID, thingID, subtopic string) (RuleState, error)
+
+	// SaveState persists the evaluation state of a rule for a thing and subtopic, replacing any existing one.
+	SaveState(ctx context.Context, state RuleState) error
+
+	// RemoveStates removes the evaluation states of a rule for all things.
+	RemoveStates(ctx context.Context, ruleID string) error
 }
 
 type RepositoryScripts interface {
@@ -636,12 +1022,26 @@ type RepositoryScripts interface {
 	// RetrieveScriptsByGroup retrieves a list of Lua scripts belonging to a specific Group.
 	RetrieveScriptsByGroup(ctx context.Context, groupID string, pm PageMetadata) (LuaScriptsPage, error)
 
+	// RetrieveScheduledScripts retrieves all Lua scripts having schedule triggers.
+	RetrieveScheduledScripts(ctx context.Context) ([]LuaScript, error)
+
 	// RetrieveThingIDsByScript retrieves a list of Thing IDs to which the specific Lua script is assigned.
-	RetrieveThingIDsByScript(ctx context.Context, scriptID string) ([]string, error)
+	// If status is not empty, only the Things whose assignment has the status are retrieved.
+	RetrieveThingIDsByScript(ctx context.Context, scriptID, status string) ([]string, error)
 
-	// UpdateScript updates the script denoted by script.ID.
+	// UpdateScript updates the script denoted by script.ID. If the content of the script
+	// changes, its version is incremented and a new script version is saved.
 	UpdateScript(ctx context.Context, script LuaScript) error
 
+	// UpdateScriptStatus changes the status of the Lua script denoted by ID.
+	UpdateScriptStatus(ctx context.Context, id, status string) error
+
+	// RetrieveScriptVersions retrieves a list of versions of a specific Lua script.
+	RetrieveScriptVersions(ctx context.Context, scriptID string, pm PageMetadata) (ScriptVersionsPage, error)
+
+	// RetrieveScriptVersion retrieves a specific version of a Lua script.
+	RetrieveScriptVersion(ctx context.Context, scriptID string, version uint64) (ScriptVersion, error)
+
 	// RemoveScripts removes Lua scripts with the provided ids.
 	RemoveScripts(ctx context.Context, ids ...string) error
 
@@ -657,6 +1057,9 @@ type RepositoryScripts interface {
 	// UnassignScriptsFromThing unassigns all scripts from a specific Thing.
 	UnassignScriptsFromThing(ctx context.Context, thingID string) error
 
+	// UpdateThingScriptsStatus changes the status of the assignments of one or more Lua scripts to a specific Thing.
+	UpdateThingScriptsStatus(ctx context.Context, thingID, status string, scriptIDs ...string) error
+
 	// SaveScriptRuns preserves multiple ScriptRuns.
 	SaveScriptRuns(ctx context.Context, runs ...ScriptRun) ([]ScriptRun, error)
 
@@ -668,4 +1071,49 @@ type RepositoryScripts interface {
 
 	// RemoveScriptRuns removes one or more Script runs by IDs.
 	RemoveScriptRuns(ctx context.Context, ids ...string) error
+
+	// PruneScriptRuns removes the Script runs started before the provided time, and all but the latest
+	// keep runs of each Script and Thing. A zero time or keep count disables the respective limit.
+	PruneScriptRuns(ctx context.Context, before time.Time, keep uint64) error
+
+	// ClaimScheduledRun records that the run of the script scheduled by its trigger at the given index
+	// for the given time has started. It reports false if the run was already claimed, e.g. by another
+	// service instance.
+	ClaimScheduledRun(ctx context.Context, scriptID string, trigger int, scheduledAt time.Time) (bool, error)
+
+	// RetrieveScriptStats retrieves the run statistics of a specific Lua script.
+	RetrieveScriptStats(ctx context.Context, scriptID string) (ScriptStats, error)
+
+	// SaveScriptRunTotals adds the run totals to the stats of their scripts.
+	SaveScriptRunTotals(ctx context.Context, totals ...ScriptRunTotals) error
+
+	// SaveModules persists multiple Lua modules.
+	SaveModules(ctx context.Context, modules ...LuaModule) ([]LuaModule, error)
+
+	// RetrieveModuleByID retrieves a single Lua module denoted by ID.
+	RetrieveModuleByID(ctx context.Context, id string) (LuaModule, error)
+
+	// RetrieveModuleByName retrieves the Lua module of a specific Group denoted by name.
+	RetrieveModuleByName(ctx context.Context, groupID, name string) (LuaModule, error)
+
+	// RetrieveModulesByGroup retrieves a list of Lua modules belonging to a specific Group.
+	RetrieveModulesByGroup(ctx context.Context, groupID string, pm PageMetadata) (LuaModulesPage, error)
+
+	// UpdateModule updates the Lua module denoted by module.ID.
+	UpdateModule(ctx context.Context, module LuaModule) error
+
+	// RemoveModules removes Lua modules with the provided ids.
+	RemoveModules(ctx context.Context, ids ...string) error
+
+	// RemoveModulesByGroup removes all Lua modules belonging to a specific Group.
+	RemoveModulesByGroup(ctx context.Context, groupID string) error
+
+	// RetrieveScriptValue retrieves the value stored under the key by a Lua script for a specific Thing.
+	RetrieveScriptValue(ctx context.Context, scriptID, thingID, key string) (ScriptValue, error)
+
+	// SaveScriptValue persists a value of a Lua script for a specific Thing, replacing any existing one.
+	SaveScriptValue(ctx context.Context, value ScriptValue) error
+
+	// RemoveScriptValue removes the value stored under the key by a Lua script for a specific Thing.
+	RemoveScriptValue(ctx context.Context, scriptID, thingID, key string) error
 }
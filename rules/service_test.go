@@ -7,11 +7,19 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
 	"testing"
+	"time"
 
 	"github.com/MainfluxLabs/mainflux/logger"
 	"github.com/MainfluxLabs/mainflux/pkg/dbutil"
+	"github.com/MainfluxLabs/mainflux/pkg/domain"
 	"github.com/MainfluxLabs/mainflux/pkg/errors"
+	"github.com/MainfluxLabs/mainflux/pkg/messaging"
 	authmock "github.com/MainfluxLabs/mainflux/pkg/mocks"
 	protomfx "github.com/MainfluxLabs/mainflux/pkg/proto"
 	"github.com/MainfluxLabs/mainflux/pkg/uuid"
@@ -37,6 +45,18 @@ func newService() rules.Service {
 }
 
 func newServiceWithPub(pub rules.Publisher) rules.Service {
+	return newServiceWithReaders(pub, authmock.NewReadersClient())
+}
+
+func newServiceWithReaders(pub rules.Publisher, readers domain.ReadersClient) rules.Service {
+	return newScriptsService(pub, readers, authmock.NewShadowsClient(), rules.ScriptsConfig{Enabled: true})
+}
+
+func newScriptsService(pub rules.Publisher, readers domain.ReadersClient, shadows domain.ShadowsClient, config rules.ScriptsConfig) rules.Service {
+	return newServiceWithRepository(mocks.NewRuleRepository(), pub, readers, shadows, config)
+}
+
+func newServiceWithRepository(rulesRepo rules.Repository, pub rules.Publisher, readers domain.ReadersClient, shadows domain.ShadowsClient, config rules.ScriptsConfig) rules.Service {
 	ths := authmock.NewThingsServiceClient(
 		nil,
 		map[string]things.Thing{
@@ -48,11 +68,10 @@ func newServiceWithPub(pub rules.Publisher) rules.Service {
 		},
 	)
 
-	rulesRepo := mocks.NewRuleRepository()
 	idp := uuid.NewMock()
 	log := logger.NewMock()
 
-	return rules.New(rulesRepo, ths, authmock.NewReadersClient(), pub, idp, log, true)
+	return rules.New(rulesRepo, ths, readers, shadows, pub, idp, log, config)
 }
 
 func saveRules(t *testing.T, svc rules.Service, n int) []rules.Rule {
@@ -72,199 +91,1965 @@ func saveRules(t *testing.T, svc rules.Service, n int) []rules.Rule {
 		})
 	}
 
-	saved, err := svc.CreateRules(context.Background(), token, groupID, rs...)
-	require.Nil(t, err)
-	require.Len(t, saved, n)
-
-	return saved
-}
+	saved, err := svc.CreateRules(context.Background(), token, groupID, rs...)
+	require.Nil(t, err)
+	require.Len(t, saved, n)
+
+	return saved
+}
+
+func mustMarshal(t *testing.T, v any) []byte {
+	t.Helper()
+
+	b, err := json.Marshal(v)
+	require.Nil(t, err)
+
+	return b
+}
+
+func TestConsumeMessage(t *testing.T) {
+	defaultConditions := []rules.Condition{
+		{Field: "temperature", Comparator: ">", Threshold: threshold(25)},
+	}
+
+	cases := []struct {
+		desc       string
+		conditions []rules.Condition
+		operator   string
+		msg        protomfx.Message
+		err        error
+	}{
+		{
+			desc:       "valid JSON message triggers rule",
+			conditions: defaultConditions,
+			operator:   rules.OperatorAND,
+			msg: protomfx.Message{
+				Publisher:   thingID,
+				Payload:     mustMarshal(t, map[string]any{"temperature": float64(30)}),
+				ContentType: "application/json",
+			},
+			err: nil,
+		},
+		{
+			desc:       "JSON message below threshold does not trigger",
+			conditions: defaultConditions,
+			operator:   rules.OperatorAND,
+			msg: protomfx.Message{
+				Publisher:   thingID,
+				Payload:     mustMarshal(t, map[string]any{"temperature": float64(20)}),
+				ContentType: "application/json",
+			},
+			err: nil,
+		},
+		{
+			desc:       "JSON array payload with partial match",
+			conditions: defaultConditions,
+			operator:   rules.OperatorAND,
+			msg: protomfx.Message{
+				Publisher: thingID,
+				Payload: mustMarshal(t, []any{
+					map[string]any{"temperature": float64(30)},
+					map[string]any{"temperature": float64(10)},
+				}),
+				ContentType: "application/json",
+			},
+			err: nil,
+		},
+		{
+			desc:       "SenML payload triggers rule",
+			conditions: defaultConditions,
+			operator:   rules.OperatorAND,
+			msg: protomfx.Message{
+				Publisher: thingID,
+				Payload: mustMarshal(t, []any{
+					map[string]any{"name": "temperature", "value": float64(30)},
+				}),
+				ContentType: "application/senml+json",
+			},
+			err: nil,
+		},
+		// AND / OR operators
+		{
+			desc: "AND operator: all conditions met",
+			conditions: []rules.Condition{
+				{Field: "temperature", Comparator: ">", Threshold: threshold(20)},
+				{Field: "humidity", Comparator: "<", Threshold: threshold(30)},
+			},
+			operator: rules.OperatorAND,
+			msg: protomfx.Message{
+				Publisher:   thingID,
+				Payload:     mustMarshal(t, map[string]any{"temperature": float64(25), "humidity": float64(10)}),
+				ContentType: "application/json",
+			},
+			err: nil,
+		},
+		{
+			desc: "AND operator: one condition not met",
+			conditions: []rules.Condition{
+				{Field: "temperature", Comparator: ">", Threshold: threshold(30)},
+				{Field: "humidity", Comparator: "<", Threshold: threshold(30)},
+			},
+			operator: rules.OperatorAND,
+			msg: protomfx.Message{
+				Publisher:   thingID,
+				Payload:     mustMarshal(t, map[string]any{"temperature": float64(25), "humidity": float64(10)}),
+				ContentType: "application/json",
+			},
+			err: nil,
+		},
+		{
+			desc: "OR operator: one condition met",
+			conditions: []rules.Condition{
+				{Field: "temperature", Comparator: ">", Threshold: threshold(30)},
+				{Field: "humidity", Comparator: "<", Threshold: threshold(30)},
+			},
+			operator: rules.OperatorOR,
+			msg: protomfx.Message{
+				Publisher:   thingID,
+				Payload:     mustMarshal(t, map[string]any{"temperature": float64(25), "humidity": float64(10)}),
+				ContentType: "application/json",
+			},
+			err: nil,
+		},
+		{
+			desc: "OR operator: no condition met",
+			conditions: []rules.Condition{
+				{Field: "temperature", Comparator: ">", Threshold: threshold(30)},
+				{Field: "humidity", Comparator: "<", Threshold: threshold(20)},
+			},
+			operator: rules.OperatorOR,
+			msg: protomfx.Message{
+				Publisher:   thingID,
+				Payload:     mustMarshal(t, map[string]any{"temperature": float64(25), "humidity": float64(25)}),
+				ContentType: "application/json",
+			},
+			err: nil,
+		},
+		// comparators
+		{
+			desc: "== comparator: exact match",
+			conditions: []rules.Condition{
+				{Field: "temperature", Comparator: "==", Threshold: threshold(25)},
+			},
+			operator: rules.OperatorAND,
+			msg: protomfx.Message{
+				Publisher:   thingID,
+				Payload:     mustMarshal(t, map[string]any{"temperature": float64(25)}),
+				ContentType: "application/json",
+			},
+			err: nil,
+		},
+		{
+			desc: ">= comparator: equal value",
+			conditions: []rules.Condition{
+				{Field: "temperature", Comparator: ">=", Threshold: threshold(25)},
+			},
+			operator: rules.OperatorAND,
+			msg: protomfx.Message{
+				Publisher:   thingID,
+				Payload:     mustMarshal(t, map[string]any{"temperature": float64(25)}),
+				ContentType: "application/json",
+			},
+			err: nil,
+		},
+		{
+			desc: "<= comparator: equal value",
+			conditions: []rules.Condition{
+				{Field: "temperature", Comparator: "<=", Threshold: threshold(25)},
+			},
+			operator: rules.OperatorAND,
+			msg: protomfx.Message{
+				Publisher:   thingID,
+				Payload:     mustMarshal(t, map[string]any{"temperature": float64(25)}),
+				ContentType: "application/json",
+			},
+			err: nil,
+		},
+	}
+
+	for _, tc := range cases {
+		svc := newService()
+
+		if len(tc.conditions) > 0 {
+			_, err := svc.CreateRules(context.Background(), token, groupID, rules.Rule{
+				Name:       "test-rule",
+				Input:      rules.Input{Type: rules.InputTypeMessage, ThingIDs: []string{thingID}},
+				Conditions: tc.conditions,
+				Operator:   tc.operator,
+				Actions:    []rules.Action{{Type: rules.ActionTypeAlarm}},
+			})
+			require.Nil(t, err)
+		}
+
+		err := svc.ConsumeMessage(subject, tc.msg)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+	}
+}
+
+func TestConsumeMessageComparators(t *testing.T) {
+	jsonPayload := map[string]any{"status": "FAULT", "door_open": true, "temperature": float64(25), "tags": []any{"a", "b"}}
+	senmlPayload := []any{
+		map[string]any{"name": "status", "string_value": "FAULT"},
+		map[string]any{"name": "door_open", "bool_value": true},
+	}
+
+	cases := []struct {
+		desc        string
+		condition   rules.Condition
+		payload     any
+		contentType string
+		triggered   bool
+	}{
+		{
+			desc:        "string equality",
+			condition:   rules.Condition{Field: "status", Comparator: rules.ComparatorEQ, Value: "FAULT"},
+			payload:     jsonPayload,
+			contentType: messaging.JSONContentType,
+			triggered:   true,
+		},
+		{
+			desc:        "string inequality",
+			condition:   rules.Condition{Field: "status", Comparator: rules.ComparatorNEQ, Value: "FAULT"},
+			payload:     jsonPayload,
+			contentType: messaging.JSONContentType,
+			triggered:   false,
+		},
+		{
+			desc:        "numeric inequality",
+			condition:   rules.Condition{Field: "temperature", Comparator: rules.ComparatorNEQ, Threshold: threshold(20)},
+			payload:     jsonPayload,
+			contentType: messaging.JSONContentType,
+			triggered:   true,
+		},
+		{
+			desc:        "boolean equality",
+			condition:   rules.Condition{Field: "door_open", Comparator: rules.ComparatorEQ, Value: true},
+			payload:     jsonPayload,
+			contentType: messaging.JSONContentType,
+			triggered:   true,
+		},
+		{
+			desc:        "string contains",
+			condition:   rules.Condition{Field: "status", Comparator: rules.ComparatorContains, Value: "AUL"},
+			payload:     jsonPayload,
+			contentType: messaging.JSONContentType,
+			triggered:   true,
+		},
+		{
+			desc:        "array contains",
+			condition:   rules.Condition{Field: "tags", Comparator: rules.ComparatorContains, Value: "c"},
+			payload:     jsonPayload,
+			contentType: messaging.JSONContentType,
+			triggered:   false,
+		},
+		{
+			desc:        "value in set",
+			condition:   rules.Condition{Field: "status", Comparator: rules.ComparatorIn, Value: []any{"FAULT", "ERROR"}},
+			payload:     jsonPayload,
+			contentType: messaging.JSONContentType,
+			triggered:   true,
+		},
+		{
+			desc:        "value not in set",
+			condition:   rules.Condition{Field: "status", Comparator: rules.ComparatorNotIn, Value: []any{"FAULT", "ERROR"}},
+			payload:     jsonPayload,
+			contentType: messaging.JSONContentType,
+			triggered:   false,
+		},
+		{
+			desc:        "regex match",
+			condition:   rules.Condition{Field: "status", Comparator: rules.ComparatorMatches, Value: "^FA.LT$"},
+			payload:     jsonPayload,
+			contentType: messaging.JSONContentType,
+			triggered:   true,
+		},
+		{
+			desc:        "numeric comparator against non-numeric string",
+			condition:   rules.Condition{Field: "status", Comparator: rules.ComparatorGT, Threshold: threshold(1)},
+			payload:     jsonPayload,
+			contentType: messaging.JSONContentType,
+			triggered:   false,
+		},
+		{
+			desc:        "SenML string value equality",
+			condition:   rules.Condition{Field: "status", Comparator: rules.ComparatorEQ, Value: "FAULT"},
+			payload:     senmlPayload,
+			contentType: messaging.SenMLContentType,
+			triggered:   true,
+		},
+		{
+			desc:        "SenML boolean value equality",
+			condition:   rules.Condition{Field: "door_open", Comparator: rules.ComparatorEQ, Value: false},
+			payload:     senmlPayload,
+			contentType: messaging.SenMLContentType,
+			triggered:   false,
+		},
+	}
+
+	for _, tc := range cases {
+		pub := mocks.NewPublisher()
+		svc := newServiceWithPub(pub)
+
+		_, err := svc.CreateRules(context.Background(), token, groupID, rules.Rule{
+			Name:       "comparator-rule",
+			Input:      rules.Input{Type: rules.InputTypeMessage, ThingIDs: []string{thingID}},
+			Conditions: []rules.Condition{tc.condition},
+			Actions:    []rules.Action{{Type: rules.ActionTypeAlarm, Level: 1}},
+		})
+		require.Nil(t, err)
+
+		err = svc.ConsumeMessage(subject, protomfx.Message{
+			Publisher:   thingID,
+			Payload:     mustMarshal(t, tc.payload),
+			ContentType: tc.contentType,
+		})
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+
+		triggered := len(mocks.PublishedAlarms(pub)) > 0
+		assert.Equal(t, tc.triggered, triggered, fmt.Sprintf("%s: expected triggered %t got %t", tc.desc, tc.triggered, triggered))
+	}
+}
+
+func TestConsumeMessageExpression(t *testing.T) {
+	// (temperature > 80 AND humidity > 60) OR smoke == 1
+	expression := rules.ConditionGroup{
+		Operator: rules.OperatorOR,
+		Conditions: []rules.Condition{
+			{Field: "smoke", Comparator: rules.ComparatorEQ, Threshold: threshold(1)},
+		},
+		Groups: []rules.ConditionGroup{
+			{
+				Operator: rules.OperatorAND,
+				Conditions: []rules.Condition{
+					{Field: "temperature", Comparator: rules.ComparatorGT, Threshold: threshold(80)},
+					{Field: "humidity", Comparator: rules.ComparatorGT, Threshold: threshold(60)},
+				},
+			},
+		},
+	}
+
+	cases := []struct {
+		desc      string
+		payload   map[string]any
+		triggered bool
+	}{
+		{
+			desc:      "nested group met",
+			payload:   map[string]any{"temperature": float64(85), "humidity": float64(65), "smoke": float64(0)},
+			triggered: true,
+		},
+		{
+			desc:      "nested group partially met",
+			payload:   map[string]any{"temperature": float64(85), "humidity": float64(50), "smoke": float64(0)},
+			triggered: false,
+		},
+		{
+			desc:      "top-level condition met",
+			payload:   map[string]any{"temperature": float64(20), "humidity": float64(50), "smoke": float64(1)},
+			triggered: true,
+		},
+	}
+
+	for _, tc := range cases {
+		pub := mocks.NewPublisher()
+		svc := newServiceWithPub(pub)
+
+		_, err := svc.CreateRules(context.Background(), token, groupID, rules.Rule{
+			Name:       "expression-rule",
+			Input:      rules.Input{Type: rules.InputTypeMessage, ThingIDs: []string{thingID}},
+			Expression: &expression,
+			Actions:    []rules.Action{{Type: rules.ActionTypeAlarm, Level: 1}},
+		})
+		require.Nil(t, err)
+
+		err = svc.ConsumeMessage(subject, protomfx.Message{
+			Publisher:   thingID,
+			Payload:     mustMarshal(t, tc.payload),
+			ContentType: messaging.JSONContentType,
+		})
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+
+		alarms := mocks.PublishedAlarms(pub)
+		assert.Equal(t, tc.triggered, len(alarms) > 0, fmt.Sprintf("%s: expected triggered %t got %t", tc.desc, tc.triggered, len(alarms) > 0))
+		if len(alarms) > 0 {
+			var ri domain.RuleInfo
+			require.Nil(t, json.Unmarshal(alarms[0].RuleInfo, &ri))
+			assert.Equal(t, &expression, ri.Expression, fmt.Sprintf("%s: expected rule info expression %v got %v", tc.desc, expression, ri.Expression))
+			assert.NotContains(t, string(alarms[0].RuleInfo), `"conditions":null`, fmt.Sprintf("%s: expected rule info without null conditions", tc.desc))
+		}
+	}
+}
+
+func TestConsumeMessageEmptyGroup(t *testing.T) {
+	// An empty AND group is met, so the OR expression holding it is met regardless of the payload.
+	expression := rules.ConditionGroup{
+		Operator: rules.OperatorOR,
+		Conditions: []rules.Condition{
+			{Field: "smoke", Comparator: rules.ComparatorEQ, Threshold: threshold(1)},
+		},
+		Groups: []rules.ConditionGroup{{Operator: rules.OperatorAND}},
+	}
+
+	pub := mocks.NewPublisher()
+	svc := newServiceWithPub(pub)
+
+	_, err := svc.CreateRules(context.Background(), token, groupID, rules.Rule{
+		Name:       "empty-group-rule",
+		Input:      rules.Input{Type: rules.InputTypeMessage, ThingIDs: []string{thingID}},
+		Expression: &expression,
+		Actions:    []rules.Action{{Type: rules.ActionTypeAlarm, Level: 1}},
+	})
+	require.Nil(t, err)
+
+	err = svc.ConsumeMessage(subject, protomfx.Message{
+		Publisher:   thingID,
+		Payload:     mustMarshal(t, map[string]any{"smoke": float64(0)}),
+		ContentType: messaging.JSONContentType,
+	})
+	assert.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	assert.Len(t, mocks.PublishedAlarms(pub), 1, "expected the rule with an empty AND group to trigger")
+}
+
+func TestConsumeMessageStatefulConditions(t *testing.T) {
+	second := int64(time.Second)
+	start := time.Now().UnixNano()
+
+	cases := []struct {
+		desc      string
+		condition rules.Condition
+		values    []float64
+		times     []int64
+		alarms    int
+	}{
+		{
+			desc:      "duration condition fires only after holding for the duration",
+			condition: rules.Condition{Field: "temperature", Comparator: ">", Threshold: threshold(80), Duration: 60},
+			values:    []float64{85, 90, 70, 85, 86, 87},
+			times:     []int64{0, 60 * second, 90 * second, 100 * second, 130 * second, 160 * second},
+			alarms:    2,
+		},
+		{
+			desc:      "hysteresis condition stays set until clear threshold is crossed",
+			condition: rules.Condition{Field: "temperature", Comparator: ">", Threshold: threshold(80), ClearThreshold: threshold(75)},
+			values:    []float64{79, 81, 78, 76, 75, 78},
+			times:     []int64{0, second, 2 * second, 3 * second, 4 * second, 5 * second},
+			alarms:    3,
+		},
+		{
+			desc:      "delta condition compares difference to previous value",
+			condition: rules.Condition{Field: "temperature", Comparator: ">", Threshold: threshold(5), Mode: rules.ConditionModeDelta},
+			values:    []float64{10, 20, 22, 30},
+			times:     []int64{0, second, 2 * second, 3 * second},
+			alarms:    2,
+		},
+		{
+			desc:      "rate condition compares change per second",
+			condition: rules.Condition{Field: "temperature", Comparator: ">=", Threshold: threshold(1), Mode: rules.ConditionModeRate},
+			values:    []float64{10, 20, 25, 26},
+			times:     []int64{0, 10 * second, 20 * second, 30 * second},
+			alarms:    1,
+		},
+	}
+
+	for _, tc := range cases {
+		pub := mocks.NewPublisher()
+		svc := newServiceWithPub(pub)
+
+		_, err := svc.CreateRules(context.Background(), token, groupID, rules.Rule{
+			Name:       "stateful-rule",
+			Input:      rules.Input{Type: rules.InputTypeMessage, ThingIDs: []string{thingID}},
+			Conditions: []rules.Condition{tc.condition},
+			Actions:    []rules.Action{{Type: rules.ActionTypeAlarm, Level: 1}},
+		})
+		require.Nil(t, err)
+
+		for i, v := range tc.values {
+			err := svc.ConsumeMessage(subject, protomfx.Message{
+				Publisher:   thingID,
+				Payload:     mustMarshal(t, map[string]any{"temperature": v}),
+				ContentType: "application/json",
+				Created:     start + tc.times[i],
+			})
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		}
+
+		alarms := len(mocks.PublishedAlarms(pub))
+		assert.Equal(t, tc.alarms, alarms, fmt.Sprintf("%s: expected %d alarms got %d", tc.desc, tc.alarms, alarms))
+	}
+}
+
+func TestConsumeMessageRuleFiring(t *testing.T) {
+	second := int64(time.Second)
+	start := time.Now().UnixNano()
+
+	cases := []struct {
+		desc         string
+		cooldown     uint64
+		onTransition bool
+		values       []float64
+		times        []int64
+		alarms       int
+		// saves is the number of state writes, made only when the rule becomes triggered or cleared, or fires.
+		saves int
+	}{
+		{
+			desc:   "rule without cooldown fires on every matching message",
+			values: []float64{85, 86, 87, 70, 88},
+			times:  []int64{0, second, 2 * second, 3 * second, 4 * second},
+			alarms: 4,
+			saves:  5,
+		},
+		{
+			desc:     "rule with cooldown doesn't fire again until cooldown has passed",
+			cooldown: 60,
+			values:   []float64{85, 86, 70, 87, 88},
+			times:    []int64{0, 30 * second, 40 * second, 50 * second, 60 * second},
+			alarms:   2,
+			saves:    4,
+		},
+		{
+			desc:         "rule firing on transition fires only when conditions become met",
+			onTransition: true,
+			values:       []float64{85, 86, 70, 87, 88},
+			times:        []int64{0, second, 2 * second, 3 * second, 4 * second},
+			alarms:       2,
+			saves:        3,
+		},
+		{
+			desc:         "rule firing on transition with cooldown suppresses transitions within cooldown",
+			cooldown:     60,
+			onTransition: true,
+			values:       []float64{85, 70, 86, 70, 87},
+			times:        []int64{0, 10 * second, 20 * second, 30 * second, 90 * second},
+			alarms:       2,
+			saves:        5,
+		},
+	}
+
+	for _, tc := range cases {
+		pub := mocks.NewPublisher()
+		repo := &stateSavesRepo{Repository: mocks.NewRuleRepository()}
+		svc := newServiceWithRepository(repo, pub, authmock.NewReadersClient(), authmock.NewShadowsClient(), rules.ScriptsConfig{Enabled: true})
+
+		_, err := svc.CreateRules(context.Background(), token, groupID, rules.Rule{
+			Name:         "firing-rule",
+			Input:        rules.Input{Type: rules.InputTypeMessage, ThingIDs: []string{thingID}},
+			Conditions:   []rules.Condition{{Field: "temperature", Comparator: ">", Threshold: threshold(80)}},
+			Actions:      []rules.Action{{Type: rules.ActionTypeAlarm, Level: 1}},
+			Cooldown:     tc.cooldown,
+			OnTransition: tc.onTransition,
+		})
+		require.Nil(t, err)
+
+		for i, v := range tc.values {
+			err := svc.ConsumeMessage(subject, protomfx.Message{
+				Publisher:   thingID,
+				Payload:     mustMarshal(t, map[string]any{"temperature": v}),
+				ContentType: "application/json",
+				Created:     start + tc.times[i],
+			})
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		}
+
+		alarms := len(mocks.PublishedAlarms(pub))
+		assert.Equal(t, tc.alarms, alarms, fmt.Sprintf("%s: expected %d alarms got %d", tc.desc, tc.alarms, alarms))
+		assert.Equal(t, tc.saves, repo.saves, fmt.Sprintf("%s: expected %d state saves got %d", tc.desc, tc.saves, repo.saves))
+	}
+}
+
+func TestConsumeMessageClearAlarm(t *testing.T) {
+	pub := mocks.NewPublisher()
+	svc := newServiceWithPub(pub)
+
+	rs, err := svc.CreateRules(context.Background(), token, groupID, rules.Rule{
+		Name:       "alarm-rule",
+		Input:      rules.Input{Type: rules.InputTypeMessage, ThingIDs: []string{thingID}},
+		Conditions: []rules.Condition{{Field: "temperature", Comparator: ">", Threshold: threshold(80)}},
+		Actions:    []rules.Action{{Type: rules.ActionTypeAlarm, Level: 1}},
+	})
+	require.Nil(t, err)
+
+	cases := []struct {
+		desc    string
+		value   float64
+		alarms  int
+		cleared int
+	}{
+		{
+			desc:    "message not meeting conditions of untriggered rule",
+			value:   70,
+			alarms:  0,
+			cleared: 0,
+		},
+		{
+			desc:    "message meeting conditions",
+			value:   85,
+			alarms:  1,
+			cleared: 0,
+		},
+		{
+			desc:    "message no longer meeting conditions",
+			value:   75,
+			alarms:  1,
+			cleared: 1,
+		},
+		{
+			desc:    "another message not meeting conditions",
+			value:   72,
+			alarms:  1,
+			cleared: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.ConsumeMessage(subject, protomfx.Message{
+			Publisher:   thingID,
+			Subtopic:    "temp",
+			Payload:     mustMarshal(t, map[string]any{"temperature": tc.value}),
+			ContentType: "application/json",
+			Created:     time.Now().UnixNano(),
+		})
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+
+		alarms := len(mocks.PublishedAlarms(pub))
+		assert.Equal(t, tc.alarms, alarms, fmt.Sprintf("%s: expected %d alarms got %d", tc.desc, tc.alarms, alarms))
+		cleared := mocks.ClearedAlarms(pub)
+		assert.Equal(t, tc.cleared, len(cleared), fmt.Sprintf("%s: expected %d cleared alarms got %d", tc.desc, tc.cleared, len(cleared)))
+	}
+
+	cleared := mocks.ClearedAlarms(pub)[0]
+	assert.Equal(t, rs[0].ID, cleared.RuleId, fmt.Sprintf("expected cleared alarm of rule %s got %s", rs[0].ID, cleared.RuleId))
+	assert.Equal(t, "temp", cleared.Subtopic, fmt.Sprintf("expected cleared alarm subtopic temp got %s", cleared.Subtopic))
+}
+
+func TestConsumeMessageClearAlarmSubtopics(t *testing.T) {
+	pub := mocks.NewPublisher()
+	svc := newServiceWithPub(pub)
+
+	_, err := svc.CreateRules(context.Background(), token, groupID, rules.Rule{
+		Name:       "alarm-rule",
+		Input:      rules.Input{Type: rules.InputTypeMessage, ThingIDs: []string{thingID}},
+		Conditions: []rules.Condition{{Field: "temperature", Comparator: ">", Threshold: threshold(80)}},
+		Actions:    []rules.Action{{Type: rules.ActionTypeAlarm, Level: 1}},
+	})
+	require.Nil(t, err)
+
+	cases := []struct {
+		desc     string
+		subtopic string
+		value    float64
+		alarms   []string
+		cleared  []string
+	}{
+		{
+			desc:     "message meeting conditions on first subtopic",
+			subtopic: "room1",
+			value:    85,
+			alarms:   []string{"room1"},
+			cleared:  []string{},
+		},
+		{
+			desc:     "message not meeting conditions on second subtopic",
+			subtopic: "room2",
+			value:    70,
+			alarms:   []string{"room1"},
+			cleared:  []string{},
+		},
+		{
+			desc:     "message meeting conditions on second subtopic",
+			subtopic: "room2",
+			value:    85,
+			alarms:   []string{"room1", "room2"},
+			cleared:  []string{},
+		},
+		{
+			desc:     "message no longer meeting conditions on first subtopic",
+			subtopic: "room1",
+			value:    75,
+			alarms:   []string{"room1", "room2"},
+			cleared:  []string{"room1"},
+		},
+		{
+			desc:     "message no longer meeting conditions on second subtopic",
+			subtopic: "room2",
+			value:    75,
+			alarms:   []string{"room1", "room2"},
+			cleared:  []string{"room1", "room2"},
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.ConsumeMessage(subject, protomfx.Message{
+			Publisher:   thingID,
+			Subtopic:    tc.subtopic,
+			Payload:     mustMarshal(t, map[string]any{"temperature": tc.value}),
+			ContentType: "application/json",
+			Created:     time.Now().UnixNano(),
+		})
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+
+		alarms := []string{}
+		for _, alarm := range mocks.PublishedAlarms(pub) {
+			alarms = append(alarms, alarm.Subtopic)
+		}
+		assert.Equal(t, tc.alarms, alarms, fmt.Sprintf("%s: expected alarms on %v got %v", tc.desc, tc.alarms, alarms))
+
+		cleared := []string{}
+		for _, alarm := range mocks.ClearedAlarms(pub) {
+			cleared = append(cleared, alarm.Subtopic)
+		}
+		assert.Equal(t, tc.cleared, cleared, fmt.Sprintf("%s: expected cleared alarms on %v got %v", tc.desc, tc.cleared, cleared))
+	}
+}
+
+// readersStub returns the same stored JSON messages for every query.
+type readersStub struct {
+	messages []domain.Message
+}
+
+func (rs readersStub) ListJSONMessages(_ context.Context, _ domain.ThingKey, _ domain.JSONPageMetadata) (domain.JSONMessagesPage, error) {
+	return domain.JSONMessagesPage{MessagesPage: domain.MessagesPage{Total: uint64(len(rs.messages)), Messages: rs.messages}}, nil
+}
+
+func (rs readersStub) ListSenMLMessages(_ context.Context, _ domain.ThingKey, _ domain.SenMLPageMetadata) (domain.SenMLMessagesPage, error) {
+	return domain.SenMLMessagesPage{}, nil
+}
+
+// stateSavesRepo counts the rule states saved to the wrapped repository.
+type stateSavesRepo struct {
+	rules.Repository
+	saves int
+}
+
+func (sr *stateSavesRepo) SaveState(ctx context.Context, state rules.RuleState) error {
+	sr.saves++
+	return sr.Repository.SaveState(ctx, state)
+}
+
+func TestConsumeMessageAggregateConditions(t *testing.T) {
+	second := int64(time.Second)
+	start := time.Now().UnixNano()
+
+	cases := []struct {
+		desc      string
+		condition rules.Condition
+		stored    []float64
+		values    []float64
+		times     []int64
+		subtopics []string
+		alarms    int
+	}{
+		{
+			desc:      "average over last messages",
+			condition: rules.Condition{Field: "temperature", Comparator: ">", Threshold: threshold(80), Aggregation: &rules.Aggregation{Type: rules.AggregationAvg, Count: 3}},
+			values:    []float64{90, 60, 95, 100, 40},
+			times:     []int64{0, second, 2 * second, 3 * second, 4 * second},
+			alarms:    3,
+		},
+		{
+			desc:      "maximum over time window",
+			condition: rules.Condition{Field: "temperature", Comparator: ">=", Threshold: threshold(90), Aggregation: &rules.Aggregation{Type: rules.AggregationMax, Period: 60}},
+			values:    []float64{95, 20, 30, 40},
+			times:     []int64{0, 30 * second, 61 * second, 70 * second},
+			alarms:    2,
+		},
+		{
+			desc:      "minimum over last messages",
+			condition: rules.Condition{Field: "temperature", Comparator: "<", Threshold: threshold(10), Aggregation: &rules.Aggregation{Type: rules.AggregationMin, Count: 2}},
+			values:    []float64{5, 20, 30},
+			times:     []int64{0, second, 2 * second},
+			alarms:    2,
+		},
+		{
+			desc:      "sum over time window",
+			condition: rules.Condition{Field: "temperature", Comparator: ">", Threshold: threshold(100), Aggregation: &rules.Aggregation{Type: rules.AggregationSum, Period: 10}},
+			values:    []float64{50, 40, 30, 20},
+			times:     []int64{0, second, 2 * second, 20 * second},
+			alarms:    1,
+		},
+		{
+			desc:      "count over time window",
+			condition: rules.Condition{Field: "temperature", Comparator: ">=", Threshold: threshold(3), Aggregation: &rules.Aggregation{Type: rules.AggregationCount, Period: 10}},
+			values:    []float64{1, 1, 1, 1, 1},
+			times:     []int64{0, second, 2 * second, 3 * second, 30 * second},
+			alarms:    2,
+		},
+		{
+			desc:      "average over window rebuilt from stored messages",
+			condition: rules.Condition{Field: "temperature", Comparator: ">", Threshold: threshold(80), Aggregation: &rules.Aggregation{Type: rules.AggregationAvg, Count: 3}},
+			stored:    []float64{100, 90},
+			values:    []float64{60},
+			times:     []int64{3 * second},
+			alarms:    1,
+		},
+		{
+			desc:      "average over window rebuilt from stored messages including the current one",
+			condition: rules.Condition{Field: "temperature", Comparator: ">", Threshold: threshold(80), Aggregation: &rules.Aggregation{Type: rules.AggregationAvg, Count: 3}},
+			stored:    []float64{100, 90, 60},
+			values:    []float64{60},
+			times:     []int64{2 * second},
+			alarms:    1,
+		},
+		{
+			desc:      "sum over last messages kept per subtopic",
+			condition: rules.Condition{Field: "temperature", Comparator: ">", Threshold: threshold(100), Aggregation: &rules.Aggregation{Type: rules.AggregationSum, Count: 2}},
+			values:    []float64{60, 60, 60},
+			times:     []int64{0, second, 2 * second},
+			subtopics: []string{"room1", "room2", "room1"},
+			alarms:    1,
+		},
+	}
+
+	for _, tc := range cases {
+		var stored []domain.Message
+		// Stored messages are returned newest first.
+		for i := len(tc.stored) - 1; i >= 0; i-- {
+			stored = append(stored, map[string]any{
+				"publisher": thingID,
+				"created":   start + int64(i)*second,
+				"payload":   map[string]any{"temperature": tc.stored[i]},
+			})
+		}
+
+		pub := mocks.NewPublisher()
+		svc := newServiceWithReaders(pub, readersStub{messages: stored})
+
+		_, err := svc.CreateRules(context.Background(), token, groupID, rules.Rule{
+			Name:       "aggregate-rule",
+			Input:      rules.Input{Type: rules.InputTypeMessage, ThingIDs: []string{thingID}},
+			Conditions: []rules.Condition{tc.condition},
+			Actions:    []rules.Action{{Type: rules.ActionTypeAlarm, Level: 1}},
+		})
+		require.Nil(t, err)
+
+		for i, v := range tc.values {
+			var subtopic string
+			if tc.subtopics != nil {
+				subtopic = tc.subtopics[i]
+			}
+			err := svc.ConsumeMessage(subject, protomfx.Message{
+				Publisher:   thingID,
+				Subtopic:    subtopic,
+				Payload:     mustMarshal(t, map[string]any{"temperature": v}),
+				ContentType: "application/json",
+				Created:     start + tc.times[i],
+			})
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		}
+
+		alarms := len(mocks.PublishedAlarms(pub))
+		assert.Equal(t, tc.alarms, alarms, fmt.Sprintf("%s: expected %d alarms got %d", tc.desc, tc.alarms, alarms))
+	}
+}
+
+func TestConsumeMessageCommandAction(t *testing.T) {
+	actuatorID := "2f0e4a8c-6a34-4b7e-9a39-1d7c1d7fd2a1"
+	otherThingID := "8e3b3c55-62f4-4b5c-8c2e-5b1cd9a3f0d4"
+	otherGroupID := "c9f4e0a9-6f5e-4f1a-a6f2-4a4b3d2a1c10"
+
+	ths := authmock.NewThingsServiceClient(
+		nil,
+		map[string]things.Thing{
+			token:        {ID: thingID, GroupID: groupID, Type: things.ThingTypeController},
+			thingID:      {ID: thingID, GroupID: groupID, Type: things.ThingTypeController},
+			actuatorID:   {ID: actuatorID, GroupID: groupID, Type: things.ThingTypeActuator},
+			otherThingID: {ID: otherThingID, GroupID: otherGroupID, Type: things.ThingTypeActuator},
+		},
+		map[string]things.Group{
+			token: {ID: groupID},
+		},
+	)
+
+	cases := []struct {
+		desc    string
+		action  rules.Action
+		payload string
+		count   int
+	}{
+		{
+			desc:    "send templated command to thing",
+			action:  rules.Action{Type: rules.ActionTypeCommand, Target: rules.ActionTargetThing, ID: actuatorID, Subtopic: "fan", Payload: `{"fan":"on","temperature":{{.payload.temperature}}}`},
+			payload: `{"fan":"on","temperature":85}`,
+			count:   1,
+		},
+		{
+			desc:    "send command with message payload to group",
+			action:  rules.Action{Type: rules.ActionTypeCommand, Target: rules.ActionTargetGroup, ID: groupID},
+			payload: `{"temperature":85}`,
+			count:   1,
+		},
+		{
+			desc:   "send command to thing from another group",
+			action: rules.Action{Type: rules.ActionTypeCommand, Target: rules.ActionTargetThing, ID: otherThingID},
+			count:  0,
+		},
+		{
+			desc:   "send command to another group",
+			action: rules.Action{Type: rules.ActionTypeCommand, Target: rules.ActionTargetGroup, ID: otherGroupID},
+			count:  0,
+		},
+		{
+			desc:   "send command with template referencing missing field",
+			action: rules.Action{Type: rules.ActionTypeCommand, Target: rules.ActionTargetThing, ID: actuatorID, Payload: `{"humidity":{{.payload.humidity}}}`},
+			count:  0,
+		},
+	}
+
+	for _, tc := range cases {
+		pub := mocks.NewPublisher()
+		svc := rules.New(mocks.NewRuleRepository(), ths, authmock.NewReadersClient(), authmock.NewShadowsClient(), pub, uuid.NewMock(), logger.NewMock(), rules.ScriptsConfig{})
+
+		_, err := svc.CreateRules(context.Background(), token, groupID, rules.Rule{
+			Name:       "command-rule",
+			Input:      rules.Input{Type: rules.InputTypeMessage, ThingIDs: []string{thingID}},
+			Conditions: []rules.Condition{{Field: "temperature", Comparator: ">", Threshold: threshold(80)}},
+			Actions:    []rules.Action{tc.action},
+		})
+		require.Nil(t, err)
+
+		err = svc.ConsumeMessage(subject, protomfx.Message{
+			Publisher:   thingID,
+			Payload:     []byte(`{"temperature":85}`),
+			ContentType: "application/json",
+		})
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+
+		cmds := mocks.PublishedCommands(pub)
+		require.Equal(t, tc.count, len(cmds), fmt.Sprintf("%s: expected %d commands got %d", tc.desc, tc.count, len(cmds)))
+		if tc.count == 0 {
+			continue
+		}
+		assert.Equal(t, thingID, cmds[0].Publisher, fmt.Sprintf("%s: expected publisher %s got %s", tc.desc, thingID, cmds[0].Publisher))
+		assert.Equal(t, tc.action.Subtopic, cmds[0].Subtopic, fmt.Sprintf("%s: expected subtopic %s got %s", tc.desc, tc.action.Subtopic, cmds[0].Subtopic))
+		assert.JSONEq(t, tc.payload, string(cmds[0].Payload), fmt.Sprintf("%s: unexpected command payload", tc.desc))
+	}
+}
+
+func TestConsumeMessageShadowAction(t *testing.T) {
+	actuatorID := "2f0e4a8c-6a34-4b7e-9a39-1d7c1d7fd2a1"
+	otherThingID := "8e3b3c55-62f4-4b5c-8c2e-5b1cd9a3f0d4"
+	otherGroupID := "c9f4e0a9-6f5e-4f1a-a6f2-4a4b3d2a1c10"
+
+	ths := authmock.NewThingsServiceClient(
+		nil,
+		map[string]things.Thing{
+			token:        {ID: thingID, GroupID: groupID},
+			thingID:      {ID: thingID, GroupID: groupID},
+			actuatorID:   {ID: actuatorID, GroupID: groupID},
+			otherThingID: {ID: otherThingID, GroupID: otherGroupID},
+		},
+		map[string]things.Group{
+			token: {ID: groupID},
+		},
+	)
+
+	cases := []struct {
+		desc       string
+		action     rules.Action
+		recipients []string
+		patch      string
+	}{
+		{
+			desc:       "update desired state of thing",
+			action:     rules.Action{Type: rules.ActionTypeShadow, Target: rules.ActionTargetThing, ID: actuatorID, Payload: `{"fan":"on","setpoint":{{.payload.temperature}}}`},
+			recipients: []string{actuatorID},
+			patch:      `{"fan":"on","setpoint":85}`,
+		},
+		{
+			desc:       "update desired state of group things",
+			action:     rules.Action{Type: rules.ActionTypeShadow, Target: rules.ActionTargetGroup, ID: groupID, Payload: `{"fan":"on"}`},
+			recipients: []string{thingID, actuatorID},
+			patch:      `{"fan":"on"}`,
+		},
+		{
+			desc:   "update desired state of thing from another group",
+			action: rules.Action{Type: rules.ActionTypeShadow, Target: rules.ActionTargetThing, ID: otherThingID, Payload: `{"fan":"on"}`},
+		},
+		{
+			desc:   "update desired state of another group",
+			action: rules.Action{Type: rules.ActionTypeShadow, Target: rules.ActionTargetGroup, ID: otherGroupID, Payload: `{"fan":"on"}`},
+		},
+		{
+			desc:   "update desired state with non-object patch",
+			action: rules.Action{Type: rules.ActionTypeShadow, Target: rules.ActionTargetThing, ID: actuatorID, Payload: `"on"`},
+		},
+	}
+
+	for _, tc := range cases {
+		pub := mocks.NewPublisher()
+		svc := rules.New(mocks.NewRuleRepository(), ths, authmock.NewReadersClient(), authmock.NewShadowsClient(), pub, uuid.NewMock(), logger.NewMock(), rules.ScriptsConfig{})
+
+		_, err := svc.CreateRules(context.Background(), token, groupID, rules.Rule{
+			Name:       "shadow-rule",
+			Input:      rules.Input{Type: rules.InputTypeMessage, ThingIDs: []string{thingID}},
+			Conditions: []rules.Condition{{Field: "temperature", Comparator: ">", Threshold: threshold(80)}},
+			Actions:    []rules.Action{tc.action},
+		})
+		require.Nil(t, err)
+
+		err = svc.ConsumeMessage(subject, protomfx.Message{
+			Publisher:   thingID,
+			Payload:     []byte(`{"temperature":85}`),
+			ContentType: "application/json",
+		})
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+
+		cmds := mocks.PublishedCommands(pub)
+		var recipients []string
+		for _, cmd := range cmds {
+			recipients = append(recipients, cmd.RecipientID)
+			assert.JSONEq(t, tc.patch, string(cmd.Payload), fmt.Sprintf("%s: unexpected desired state patch", tc.desc))
+		}
+		assert.ElementsMatch(t, tc.recipients, recipients, fmt.Sprintf("%s: expected recipients %v got %v", tc.desc, tc.recipients, recipients))
+	}
+}
+
+func TestConsumeMessageScriptAPI(t *testing.T) {
+	actuatorID := "2f0e4a8c-6a34-4b7e-9a39-1d7c1d7fd2a1"
+	webhookID := "b1c2d3e4-f5a6-4b7c-8d9e-0f1a2b3c4d5e"
+
+	ths := authmock.NewThingsServiceClient(
+		nil,
+		map[string]things.Thing{
+			token:      {ID: thingID, GroupID: groupID, Type: things.ThingTypeController},
+			thingID:    {ID: thingID, GroupID: groupID, Type: things.ThingTypeController},
+			actuatorID: {ID: actuatorID, GroupID: groupID, Type: things.ThingTypeActuator},
+		},
+		map[string]things.Group{
+			token: {ID: groupID},
+		},
+	)
+
+	shadows := authmock.NewShadowsClient(domain.Shadow{
+		ThingID:  thingID,
+		Desired:  map[string]any{"fan": "on"},
+		Reported: map[string]any{"fan": "off"},
+		Delta:    map[string]any{"fan": "on"},
+		Version:  3,
+	})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Method", r.Method)
+		w.WriteHeader(http.StatusCreated)
+		w.Write(body)
+	}))
+	defer ts.Close()
+
+	tsURL, err := url.Parse(ts.URL)
+	require.Nil(t, err)
+	notAllowedURL := fmt.Sprintf("http://localhost:%s", tsURL.Port())
+
+	cases := []struct {
+		desc     string
+		script   string
+		runs     int
+		logs     []string
+		commands []protomfx.Command
+		webhooks []protomfx.Webhook
+	}{
+		{
+			desc:   "keep value between runs",
+			script: `local n = mfx.kv_get("count") or 0 mfx.kv_set("count", n + 1) mfx.log(tostring(n + 1))`,
+			runs:   2,
+			logs:   []string{"1", "2"},
+		},
+		{
+			desc:   "store table value",
+			script: `mfx.kv_set("cfg", {name = "fan", limits = {10, 20}}) local v = mfx.kv_get("cfg") mfx.log(v.name .. v.limits[2])`,
+			runs:   1,
+			logs:   []string{"fan20"},
+		},
+		{
+			desc:   "delete value",
+			script: `mfx.kv_set("key", "value") mfx.kv_delete("key") mfx.log(tostring(mfx.kv_get("key")))`,
+			runs:   1,
+			logs:   []string{"nil"},
+		},
+		{
+			desc:   "store value with too long key",
+			script: `local ok, err = mfx.kv_set(string.rep("k", 129), 1) mfx.log(err)`,
+			runs:   1,
+			logs:   []string{"key length must be between 1 and 128"},
+		},
+		{
+			desc:   "get shadow",
+			script: `local s = mfx.get_shadow() mfx.log(s.delta.fan .. s.reported.fan .. s.version)`,
+			runs:   1,
+			logs:   []string{"onoff3"},
+		},
+		{
+			desc:   "get missing shadow",
+			script: `local s, err = mfx.get_shadow("missing") mfx.log(tostring(s))`,
+			runs:   1,
+			logs:   []string{"nil"},
+		},
+		{
+			desc:   "patch shadow",
+			script: `mfx.patch_shadow({fan = "off"}, "climate")`,
+			runs:   1,
+			logs:   []string{},
+			commands: []protomfx.Command{
+				{Publisher: thingID, RecipientID: thingID, Subtopic: "climate", Payload: []byte(`{"fan":"off"}`)},
+			},
+		},
+		{
+			desc:   "send command to thing",
+			script: `mfx.send_command("thing", "` + actuatorID + `", {fan = "on"}, "fan")`,
+			runs:   1,
+			logs:   []string{},
+			commands: []protomfx.Command{
+				{Publisher: thingID, RecipientID: actuatorID, Subtopic: "fan", Payload: []byte(`{"fan":"on"}`)},
+			},
+		},
+		{
+			desc:   "send command to thing above invocation limit",
+			script: `for i = 1, 5 do mfx.send_command("thing", "` + actuatorID + `", "on") end`,
+			runs:   1,
+			logs:   []string{},
+			commands: []protomfx.Command{
+				{Publisher: thingID, RecipientID: actuatorID, Payload: []byte("on")},
+				{Publisher: thingID, RecipientID: actuatorID, Payload: []byte("on")},
+				{Publisher: thingID, RecipientID: actuatorID, Payload: []byte("on")},
+				{Publisher: thingID, RecipientID: actuatorID, Payload: []byte("on")},
+			},
+		},
+		{
+			desc:   "send command to thing that can't be commanded",
+			script: `local ok, err = mfx.send_command("thing", "` + thingID + `", "on") mfx.log(tostring(ok))`,
+			runs:   1,
+			logs:   []string{"false"},
+		},
+		{
+			desc:   "notify webhook",
+			script: `mfx.webhook_notify("` + webhookID + `")`,
+			runs:   1,
+			logs:   []string{},
+			webhooks: []protomfx.Webhook{
+				{ThingId: thingID, Payload: []byte(`{"temperature":85}`), WebhookId: webhookID},
+			},
+		},
+		{
+			desc:   "send http request to allowed host",
+			script: `local res = mfx.http_request("put", "` + ts.URL + `", {fan = "on"}) mfx.log(res.status .. res.headers["x-method"] .. res.body)`,
+			runs:   1,
+			logs:   []string{`201PUT{"fan":"on"}`},
+		},
+		{
+			desc:   "send http request to host that is not allowed",
+			script: `local res, err = mfx.http_request("GET", "` + notAllowedURL + `") mfx.log(err)`,
+			runs:   1,
+			logs:   []string{rules.ErrHostNotAllowed.Error()},
+		},
+		{
+			desc:   "send http request with non-string headers",
+			script: `local ok, err = pcall(mfx.http_request, "GET", "` + ts.URL + `", nil, {1, accept = "json"}) mfx.log(tostring(ok) .. " " .. tostring(string.find(err, "headers must be a table of strings", 1, true) ~= nil))`,
+			runs:   1,
+			logs:   []string{"false true"},
+		},
+	}
+
+	for _, tc := range cases {
+		pub := mocks.NewPublisher()
+		svc := rules.New(mocks.NewRuleRepository(), ths, authmock.NewReadersClient(), shadows, pub, uuid.NewMock(), logger.NewMock(), rules.ScriptsConfig{Enabled: true, HTTPAllowlist: []string{tsURL.Hostname()}})
+
+		scripts, err := svc.CreateScripts(context.Background(), token, groupID, rules.LuaScript{Name: "script", Script: tc.script})
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		err = svc.AssignScripts(context.Background(), token, thingID, scripts[0].ID)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+
+		for range tc.runs {
+			err = svc.ConsumeMessage(subject, protomfx.Message{
+				Publisher:   thingID,
+				Payload:     []byte(`{"temperature":85}`),
+				ContentType: "application/json",
+			})
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		}
+
+		page, err := svc.ListScriptRunsByThing(context.Background(), token, thingID, rules.PageMetadata{})
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		require.Len(t, page.Runs, tc.runs, fmt.Sprintf("%s: expected %d runs got %d", tc.desc, tc.runs, len(page.Runs)))
+
+		runs := page.Runs
+		sort.Slice(runs, func(i, j int) bool { return runs[i].StartedAt.Before(runs[j].StartedAt) })
+		logs := []string{}
+		for _, run := range runs {
+			assert.Equal(t, rules.ScriptRunStatusSuccess, run.Status, fmt.Sprintf("%s: unexpected run error %s", tc.desc, run.Error))
+			logs = append(logs, run.Logs...)
+		}
+		assert.Equal(t, tc.logs, logs, fmt.Sprintf("%s: expected logs %v got %v", tc.desc, tc.logs, logs))
+
+		cmds := mocks.PublishedCommands(pub)
+		assert.Equal(t, len(tc.commands), len(cmds), fmt.Sprintf("%s: expected %d commands got %d", tc.desc, len(tc.commands), len(cmds)))
+		for i := range min(len(tc.commands), len(cmds)) {
+			assert.Equal(t, tc.commands[i].RecipientID, cmds[i].RecipientID, fmt.Sprintf("%s: unexpected command recipient", tc.desc))
+			assert.Equal(t, tc.commands[i].Subtopic, cmds[i].Subtopic, fmt.Sprintf("%s: unexpected command subtopic", tc.desc))
+			assert.Equal(t, string(tc.commands[i].Payload), string(cmds[i].Payload), fmt.Sprintf("%s: unexpected command payload", tc.desc))
+		}
+
+		whs := mocks.PublishedWebhooks(pub)
+		assert.Equal(t, len(tc.webhooks), len(whs), fmt.Sprintf("%s: expected %d webhook messages got %d", tc.desc, len(tc.webhooks), len(whs)))
+		for i := range min(len(tc.webhooks), len(whs)) {
+			assert.Equal(t, tc.webhooks[i].WebhookId, whs[i].WebhookId, fmt.Sprintf("%s: unexpected webhook ID", tc.desc))
+			assert.JSONEq(t, string(tc.webhooks[i].Payload), string(whs[i].Payload), fmt.Sprintf("%s: unexpected webhook payload", tc.desc))
+		}
+	}
+}
+
+func TestConsumeScriptTriggers(t *testing.T) {
+	logTrigger := `mfx.log(mfx.trigger.type .. ":" .. (mfx.trigger.subtopic or ""))`
+	msg := protomfx.Message{
+		Publisher:   thingID,
+		Subtopic:    "temperature",
+		Payload:     []byte(`{"temperature":85}`),
+		ContentType: "application/json",
+	}
+	alarm := protomfx.Alarm{
+		ThingId:  thingID,
+		Subtopic: "temperature",
+		Level:    2,
+		RuleId:   "2f0e4a8c-6a34-4b7e-9a39-1d7c1d7fd2a1",
+	}
+
+	cases := []struct {
+		desc     string
+		script   string
+		triggers []rules.Trigger
+		alarm    bool
+		logs     []string
+	}{
+		{
+			desc:   "run script without triggers on message",
+			script: logTrigger,
+			logs:   []string{"message:temperature"},
+		},
+		{
+			desc:   "skip script without triggers on alarm",
+			script: logTrigger,
+			alarm:  true,
+			logs:   []string{},
+		},
+		{
+			desc:     "run script on message of subtopic",
+			script:   logTrigger,
+			triggers: []rules.Trigger{{Type: rules.TriggerTypeMessage, Subtopic: "temperature"}},
+			logs:     []string{"message:temperature"},
+		},
+		{
+			desc:     "skip script on message of other subtopic",
+			script:   logTrigger,
+			triggers: []rules.Trigger{{Type: rules.TriggerTypeMessage, Subtopic: "humidity"}},
+			logs:     []string{},
+		},
+		{
+			desc:     "skip alarm triggered script on message",
+			script:   logTrigger,
+			triggers: []rules.Trigger{{Type: rules.TriggerTypeAlarm}},
+			logs:     []string{},
+		},
+		{
+			desc:     "run script on alarm",
+			script:   `mfx.log(mfx.trigger.type .. mfx.trigger.level .. mfx.trigger.rule_id .. mfx.message.payload.level)`,
+			triggers: []rules.Trigger{{Type: rules.TriggerTypeAlarm, Subtopic: "temperature"}},
+			alarm:    true,
+			logs:     []string{"alarm2" + alarm.RuleId + "2"},
+		},
+		{
+			desc:     "skip script on alarm of other subtopic",
+			script:   logTrigger,
+			triggers: []rules.Trigger{{Type: rules.TriggerTypeAlarm, Subtopic: "humidity"}},
+			alarm:    true,
+			logs:     []string{},
+		},
+	}
+
+	for _, tc := range cases {
+		svc := newService()
+
+		scripts, err := svc.CreateScripts(context.Background(), token, groupID, rules.LuaScript{Name: "script", Script: tc.script, Triggers: tc.triggers})
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		err = svc.AssignScripts(context.Background(), token, thingID, scripts[0].ID)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+
+		switch tc.alarm {
+		case true:
+			err = svc.ConsumeAlarm(subject, alarm)
+		default:
+			err = svc.ConsumeMessage(subject, msg)
+		}
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+
+		page, err := svc.ListScriptRunsByThing(context.Background(), token, thingID, rules.PageMetadata{})
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+
+		logs := []string{}
+		for _, run := range page.Runs {
+			assert.Equal(t, rules.ScriptRunStatusSuccess, run.Status, fmt.Sprintf("%s: unexpected run error %s", tc.desc, run.Error))
+			logs = append(logs, run.Logs...)
+		}
+		assert.Equal(t, tc.logs, logs, fmt.Sprintf("%s: expected logs %v got %v", tc.desc, tc.logs, logs))
+	}
+}
+
+func TestConsumeAlarmScriptLoop(t *testing.T) {
+	pub := mocks.NewPublisher()
+	svc := newServiceWithPub(pub)
+
+	script := rules.LuaScript{
+		Name:     "script",
+		Script:   `if mfx.create_alarm then mfx.create_alarm(3) else mfx.log("create_alarm unavailable") end`,
+		Triggers: []rules.Trigger{{Type: rules.TriggerTypeMessage}, {Type: rules.TriggerTypeAlarm}},
+	}
+	scripts, err := svc.CreateScripts(context.Background(), token, groupID, script)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	err = svc.AssignScripts(context.Background(), token, thingID, scripts[0].ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	err = svc.ConsumeMessage(subject, protomfx.Message{
+		Publisher:   thingID,
+		Subtopic:    "temperature",
+		Payload:     []byte(`{"temperature":85}`),
+		ContentType: "application/json",
+	})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	alarms := mocks.PublishedAlarms(pub)
+	require.Equal(t, 1, len(alarms), fmt.Sprintf("expected 1 alarm got %d", len(alarms)))
+
+	// Consume the alarm raised by the script, as the service does when it receives it back.
+	err = svc.ConsumeAlarm(subject, alarms[0])
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	alarms = mocks.PublishedAlarms(pub)
+	assert.Equal(t, 1, len(alarms), fmt.Sprintf("expected no alarm raised by alarm triggered script got %d alarms", len(alarms)))
+
+	page, err := svc.ListScriptRunsByThing(context.Background(), token, thingID, rules.PageMetadata{})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	require.Equal(t, uint64(2), page.Total, fmt.Sprintf("expected 2 runs got %d", page.Total))
+
+	var logs []string
+	for _, run := range page.Runs {
+		logs = append(logs, run.Logs...)
+	}
+	assert.Equal(t, []string{"create_alarm unavailable"}, logs, fmt.Sprintf("expected alarm triggered run to lack create_alarm got logs %v", logs))
+}
+
+func TestRollbackScript(t *testing.T) {
+	svc := newService()
+
+	scripts, err := svc.CreateScripts(context.Background(), token, groupID, rules.LuaScript{Name: "script", Script: `mfx.log("v1")`})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	sc := scripts[0]
+
+	for _, src := range []string{`mfx.log("v2")`, `mfx.log("v2")`, `mfx.log("v3")`} {
+		sc.Script = src
+		err = svc.UpdateScript(context.Background(), token, sc)
+		require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	}
+
+	cases := []struct {
+		desc    string
+		token   string
+		version uint64
+		script  string
+		latest  uint64
+		err     error
+	}{
+		{
+			desc:    "rollback script to first version",
+			token:   token,
+			version: 1,
+			script:  `mfx.log("v1")`,
+			latest:  4,
+			err:     nil,
+		},
+		{
+			desc:    "rollback script to non-existent version",
+			token:   token,
+			version: 10,
+			script:  `mfx.log("v1")`,
+			latest:  4,
+			err:     dbutil.ErrNotFound,
+		},
+		{
+			desc:    "rollback script with invalid auth token",
+			token:   wrongValue,
+			version: 2,
+			script:  `mfx.log("v1")`,
+			latest:  4,
+			err:     errors.ErrAuthentication,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.RollbackScript(context.Background(), tc.token, sc.ID, tc.version)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+
+		script, err := svc.ViewScript(context.Background(), token, sc.ID)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.script, script.Script, fmt.Sprintf("%s: expected script %s got %s", tc.desc, tc.script, script.Script))
+
+		page, err := svc.ListScriptVersions(context.Background(), token, sc.ID, rules.PageMetadata{})
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.latest, page.Total, fmt.Sprintf("%s: expected %d versions got %d", tc.desc, tc.latest, page.Total))
+		assert.Equal(t, tc.latest, page.Versions[0].Version, fmt.Sprintf("%s: expected latest version %d got %d", tc.desc, tc.latest, page.Versions[0].Version))
+		assert.Equal(t, tc.latest, script.Version, fmt.Sprintf("%s: expected script version %d got %d", tc.desc, tc.latest, script.Version))
+	}
+
+	msg := protomfx.Message{Publisher: thingID, Payload: []byte(`{"temperature":25}`), ContentType: messaging.JSONContentType}
+	err = svc.AssignScripts(context.Background(), token, thingID, sc.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	err = svc.ConsumeMessage(subject, msg)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	runs, err := svc.ListScriptRunsByThing(context.Background(), token, thingID, rules.PageMetadata{})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	require.Len(t, runs.Runs, 1)
+	assert.Equal(t, uint64(4), runs.Runs[0].Version, fmt.Sprintf("expected run of version 4 got %d", runs.Runs[0].Version))
+	assert.Equal(t, []string{"v1"}, runs.Runs[0].Logs, fmt.Sprintf("expected logs of version 1 got %v", runs.Runs[0].Logs))
+}
+
+func TestScriptStatus(t *testing.T) {
+	msg := protomfx.Message{Publisher: thingID, Payload: []byte(`{"temperature":25}`), ContentType: messaging.JSONContentType}
+
+	cases := []struct {
+		desc          string
+		disableScript bool
+		disableThing  bool
+		thingStatus   string
+		runs          uint64
+		reenable      bool
+	}{
+		{
+			desc:        "run enabled script",
+			thingStatus: rules.ScriptEnabledStatus,
+			runs:        1,
+		},
+		{
+			desc:          "skip disabled script",
+			disableScript: true,
+			thingStatus:   rules.ScriptEnabledStatus,
+			runs:          0,
+		},
+		{
+			desc:         "skip script disabled for thing",
+			disableThing: true,
+			thingStatus:  rules.ScriptDisabledStatus,
+			runs:         0,
+		},
+		{
+			desc:          "run re-enabled script",
+			disableScript: true,
+			disableThing:  true,
+			thingStatus:   rules.ScriptEnabledStatus,
+			runs:          1,
+			reenable:      true,
+		},
+	}
+
+	for _, tc := range cases {
+		svc := newService()
+
+		scripts, err := svc.CreateScripts(context.Background(), token, groupID, rules.LuaScript{Name: "script", Script: `mfx.log("ok")`})
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		id := scripts[0].ID
+		err = svc.AssignScripts(context.Background(), token, thingID, id)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+
+		if tc.disableScript {
+			err = svc.DisableScript(context.Background(), token, id)
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		}
+		if tc.disableThing {
+			err = svc.DisableThingScripts(context.Background(), token, thingID, id)
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		}
+		if tc.reenable {
+			err = svc.EnableScript(context.Background(), token, id)
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+			err = svc.EnableThingScripts(context.Background(), token, thingID, id)
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		}
+
+		page, err := svc.ListScriptsByThing(context.Background(), token, thingID, rules.PageMetadata{})
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.thingStatus, page.Scripts[0].ThingStatus, fmt.Sprintf("%s: expected thing status %s got %s", tc.desc, tc.thingStatus, page.Scripts[0].ThingStatus))
+
+		err = svc.ConsumeMessage(subject, msg)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+
+		runs, err := svc.ListScriptRunsByThing(context.Background(), token, thingID, rules.PageMetadata{})
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.runs, runs.Total, fmt.Sprintf("%s: expected %d runs got %d", tc.desc, tc.runs, runs.Total))
+	}
+}
+
+func TestViewScriptStats(t *testing.T) {
+	svc := newService()
+	msg := protomfx.Message{Publisher: thingID, Payload: []byte(`{"temperature":25}`), ContentType: messaging.JSONContentType}
+
+	scripts, err := svc.CreateScripts(context.Background(), token, groupID,
+		rules.LuaScript{Name: "ok", Script: `mfx.log("ok")`},
+		rules.LuaScript{Name: "fail", Script: `error("failed")`},
+		rules.LuaScript{Name: "idle", Script: `mfx.log("idle")`},
+	)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	err = svc.AssignScripts(context.Background(), token, thingID, scripts[0].ID, scripts[1].ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	for range 2 {
+		err = svc.ConsumeMessage(subject, msg)
+		require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	}
+
+	runs, err := svc.ListScriptRunsByThing(context.Background(), token, thingID, rules.PageMetadata{})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	var ids []string
+	for _, run := range runs.Runs {
+		ids = append(ids, run.ID)
+	}
+	err = svc.RemoveScriptRuns(context.Background(), token, ids...)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	cases := []struct {
+		desc      string
+		token     string
+		id        string
+		succeeded uint64
+		failed    uint64
+		err       error
+	}{
+		{
+			desc:      "view stats of succeeding script",
+			token:     token,
+			id:        scripts[0].ID,
+			succeeded: 2,
+		},
+		{
+			desc:   "view stats of failing script",
+			token:  token,
+			id:     scripts[1].ID,
+			failed: 2,
+		},
+		{
+			desc:  "view stats of script without runs",
+			token: token,
+			id:    scripts[2].ID,
+		},
+		{
+			desc:  "view stats with invalid auth token",
+			token: wrongValue,
+			id:    scripts[0].ID,
+			err:   errors.ErrAuthentication,
+		},
+		{
+			desc:  "view stats of non-existing script",
+			token: token,
+			id:    wrongValue,
+			err:   dbutil.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		stats, err := svc.ViewScriptStats(context.Background(), tc.token, tc.id)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		assert.Equal(t, tc.succeeded, stats.Succeeded, fmt.Sprintf("%s: expected %d succeeded runs got %d", tc.desc, tc.succeeded, stats.Succeeded))
+		assert.Equal(t, tc.failed, stats.Failed, fmt.Sprintf("%s: expected %d failed runs got %d", tc.desc, tc.failed, stats.Failed))
+	}
+}
+
+func TestPruneScriptRuns(t *testing.T) {
+	msg := protomfx.Message{Publisher: thingID, Payload: []byte(`{"temperature":25}`), ContentType: messaging.JSONContentType}
+
+	cases := []struct {
+		desc   string
+		config rules.ScriptsConfig
+		runs   uint64
+	}{
+		{
+			desc:   "keep runs without retention limits",
+			config: rules.ScriptsConfig{Enabled: true},
+			runs:   3,
+		},
+		{
+			desc:   "keep latest runs",
+			config: rules.ScriptsConfig{Enabled: true, RunsMaxCount: 2},
+			runs:   2,
+		},
+		{
+			desc:   "remove expired runs",
+			config: rules.ScriptsConfig{Enabled: true, RunsMaxAge: time.Nanosecond},
+			runs:   0,
+		},
+	}
+
+	for _, tc := range cases {
+		svc := newScriptsService(mocks.NewPublisher(), authmock.NewReadersClient(), authmock.NewShadowsClient(), tc.config)
+
+		scripts, err := svc.CreateScripts(context.Background(), token, groupID, rules.LuaScript{Name: "script", Script: `mfx.log("ok")`})
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		err = svc.AssignScripts(context.Background(), token, thingID, scripts[0].ID)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+
+		for range 3 {
+			err = svc.ConsumeMessage(subject, msg)
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		err = svc.LoadAndScheduleScripts(ctx)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		cancel()
+
+		page, err := svc.ListScriptRunsByThing(context.Background(), token, thingID, rules.PageMetadata{})
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.runs, page.Total, fmt.Sprintf("%s: expected %d runs got %d", tc.desc, tc.runs, page.Total))
+
+		stats, err := svc.ViewScriptStats(context.Background(), token, scripts[0].ID)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, uint64(3), stats.Succeeded, fmt.Sprintf("%s: expected stats of 3 runs got %d", tc.desc, stats.Succeeded))
+	}
+}
+
+func TestRequireModules(t *testing.T) {
+	svc := newService()
+
+	modules := []rules.LuaModule{
+		{Name: "convert", Script: `local M = {} function M.c_to_f(c) return c * 9 / 5 + 32 end return M`},
+		{Name: "vendor.parse", Script: `local convert = require("convert") mfx.log("parse loaded") return {f = function(p) return convert.c_to_f(p.temperature) end}`},
+		{Name: "cycle_a", Script: `return require("cycle_b")`},
+		{Name: "cycle_b", Script: `return require("cycle_a")`},
+		{Name: "no_value", Script: `mfx.log("no value")`},
+		{Name: "broken", Script: `return {`},
+		{Name: "busy", Script: `while true do end`},
+		{Name: "failing", Script: `error("failing module")`},
+	}
+	_, err := svc.CreateModules(context.Background(), token, groupID, modules...)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	_, err = svc.CreateModules(context.Background(), token, groupID, rules.LuaModule{Name: "convert", Script: `return {}`})
+	assert.True(t, errors.Contains(err, dbutil.ErrConflict), fmt.Sprintf("creating module with taken name: expected %s got %s", dbutil.ErrConflict, err))
+
+	cases := []struct {
+		desc   string
+		script string
+		status string
+		logs   []string
+		err    string
+	}{
+		{
+			desc:   "require module",
+			script: `local convert = require("convert") mfx.log(tostring(convert.c_to_f(mfx.message.payload.temperature)))`,
+			status: rules.ScriptRunStatusSuccess,
+			logs:   []string{"95"},
+		},
+		{
+			desc:   "require module requiring another module",
+			script: `local parse = require("vendor.parse") mfx.log(tostring(parse.f(mfx.message.payload)))`,
+			status: rules.ScriptRunStatusSuccess,
+			logs:   []string{"parse loaded", "95"},
+		},
+		{
+			desc:   "require module multiple times",
+			script: `local a = require("vendor.parse") local b = require("vendor.parse") mfx.log(tostring(a == b))`,
+			status: rules.ScriptRunStatusSuccess,
+			logs:   []string{"parse loaded", "true"},
+		},
+		{
+			desc:   "require module not returning a value",
+			script: `mfx.log(tostring(require("no_value")))`,
+			status: rules.ScriptRunStatusSuccess,
+			logs:   []string{"no value", "true"},
+		},
+		{
+			desc:   "require non-existent module",
+			script: `require("missing")`,
+			status: rules.ScriptRunStatusFail,
+			logs:   []string{},
+			err:    "module 'missing' not found",
+		},
+		{
+			desc:   "require modules in a cycle",
+			script: `require("cycle_a")`,
+			status: rules.ScriptRunStatusFail,
+			logs:   []string{},
+			err:    "module 'cycle_a' is required in a cycle",
+		},
+		{
+			desc:   "require module after failed require",
+			script: `local ok = pcall(require, "failing") mfx.log(tostring(ok)) require("failing")`,
+			status: rules.ScriptRunStatusFail,
+			logs:   []string{"false"},
+			err:    "failing module",
+		},
+		{
+			desc:   "require module with syntax error",
+			script: `require("broken")`,
+			status: rules.ScriptRunStatusFail,
+			logs:   []string{},
+			err:    "loading module 'broken' failed",
+		},
+		{
+			desc:   "require module exceeding instruction limit",
+			script: `require("busy")`,
+			status: rules.ScriptRunStatusFail,
+			logs:   []string{},
+			err:    "instruction count limit exceeded",
+		},
+	}
+
+	msg := protomfx.Message{Publisher: thingID, Payload: []byte(`{"temperature":35}`), ContentType: messaging.JSONContentType}
+	for _, tc := range cases {
+		results, err := svc.TestScript(context.Background(), token, groupID, rules.LuaScript{Script: tc.script}, msg)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		require.Len(t, results, 1)
 
-func mustMarshal(t *testing.T, v any) []byte {
-	t.Helper()
+		res := results[0]
+		assert.Equal(t, tc.status, res.Status, fmt.Sprintf("%s: expected status %s got %s", tc.desc, tc.status, res.Status))
+		assert.Equal(t, tc.logs, res.Logs, fmt.Sprintf("%s: expected logs %v got %v", tc.desc, tc.logs, res.Logs))
+		assert.Contains(t, res.Error, tc.err, fmt.Sprintf("%s: expected error %s got %s", tc.desc, tc.err, res.Error))
+		assert.GreaterOrEqual(t, res.InstructionCount, uint(10_000), fmt.Sprintf("%s: expected loading modules to count toward the instruction budget", tc.desc))
+	}
 
-	b, err := json.Marshal(v)
-	require.Nil(t, err)
+	err = svc.RemoveModulesByGroup(context.Background(), groupID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
 
-	return b
+	results, err := svc.TestScript(context.Background(), token, groupID, rules.LuaScript{Script: `require("convert")`}, msg)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	assert.Contains(t, results[0].Error, "module 'convert' not found", fmt.Sprintf("expected removed module not to be found got %s", results[0].Error))
 }
 
-func TestConsumeMessage(t *testing.T) {
-	defaultConditions := []rules.Condition{
-		{Field: "temperature", Comparator: ">", Threshold: threshold(25)},
-	}
-
+func TestTransformMessage(t *testing.T) {
 	cases := []struct {
-		desc       string
-		conditions []rules.Condition
-		operator   string
-		msg        protomfx.Message
-		err        error
+		desc    string
+		script  string
+		payload string
+		status  string
+		res     string
 	}{
 		{
-			desc:       "valid JSON message triggers rule",
-			conditions: defaultConditions,
-			operator:   rules.OperatorAND,
-			msg: protomfx.Message{
-				Publisher:   thingID,
-				Payload:     mustMarshal(t, map[string]any{"temperature": float64(30)}),
-				ContentType: "application/json",
-			},
-			err: nil,
+			desc:    "transform payload",
+			script:  `local p = mfx.message.payload p.temperature_f = p.temperature * 9 / 5 + 32 p.temperature = nil return p`,
+			payload: `{"temperature":85}`,
+			status:  rules.ScriptRunStatusSuccess,
+			res:     `{"temperature_f":185}`,
 		},
 		{
-			desc:       "JSON message below threshold does not trigger",
-			conditions: defaultConditions,
-			operator:   rules.OperatorAND,
-			msg: protomfx.Message{
-				Publisher:   thingID,
-				Payload:     mustMarshal(t, map[string]any{"temperature": float64(20)}),
-				ContentType: "application/json",
-			},
-			err: nil,
+			desc:    "drop payload",
+			script:  `return nil`,
+			payload: `{"temperature":85}`,
+			status:  rules.ScriptRunStatusSuccess,
+			res:     "",
 		},
 		{
-			desc:       "JSON array payload with partial match",
-			conditions: defaultConditions,
-			operator:   rules.OperatorAND,
-			msg: protomfx.Message{
-				Publisher: thingID,
-				Payload: mustMarshal(t, []any{
-					map[string]any{"temperature": float64(30)},
-					map[string]any{"temperature": float64(10)},
-				}),
-				ContentType: "application/json",
-			},
-			err: nil,
+			desc:    "transform array payload",
+			script:  `local p = mfx.message.payload if p.temperature < 0 then return nil end return {celsius = p.temperature}`,
+			payload: `[{"temperature":85},{"temperature":-300},{"temperature":20}]`,
+			status:  rules.ScriptRunStatusSuccess,
+			res:     `[{"celsius":85},{"celsius":20}]`,
 		},
 		{
-			desc:       "SenML payload triggers rule",
-			conditions: defaultConditions,
-			operator:   rules.OperatorAND,
-			msg: protomfx.Message{
-				Publisher: thingID,
-				Payload: mustMarshal(t, []any{
-					map[string]any{"name": "temperature", "value": float64(30)},
-				}),
-				ContentType: "application/senml+json",
-			},
-			err: nil,
+			desc:    "drop array payload",
+			script:  `return nil`,
+			payload: `[{"temperature":85},{"temperature":20}]`,
+			status:  rules.ScriptRunStatusSuccess,
+			res:     "",
 		},
-		// AND / OR operators
 		{
-			desc: "AND operator: all conditions met",
-			conditions: []rules.Condition{
-				{Field: "temperature", Comparator: ">", Threshold: threshold(20)},
-				{Field: "humidity", Comparator: "<", Threshold: threshold(30)},
-			},
-			operator: rules.OperatorAND,
-			msg: protomfx.Message{
-				Publisher:   thingID,
-				Payload:     mustMarshal(t, map[string]any{"temperature": float64(25), "humidity": float64(10)}),
-				ContentType: "application/json",
-			},
-			err: nil,
+			desc:    "transform payload with invalid result",
+			script:  `return "temperature"`,
+			payload: `{"temperature":85}`,
+			status:  rules.ScriptRunStatusFail,
+			res:     `{"temperature":85}`,
 		},
 		{
-			desc: "AND operator: one condition not met",
-			conditions: []rules.Condition{
-				{Field: "temperature", Comparator: ">", Threshold: threshold(30)},
-				{Field: "humidity", Comparator: "<", Threshold: threshold(30)},
-			},
-			operator: rules.OperatorAND,
-			msg: protomfx.Message{
-				Publisher:   thingID,
-				Payload:     mustMarshal(t, map[string]any{"temperature": float64(25), "humidity": float64(10)}),
-				ContentType: "application/json",
-			},
-			err: nil,
+			desc:    "transform payload with runtime error",
+			script:  `error("failed")`,
+			payload: `{"temperature":85}`,
+			status:  rules.ScriptRunStatusFail,
+			res:     `{"temperature":85}`,
 		},
 		{
-			desc: "OR operator: one condition met",
-			conditions: []rules.Condition{
-				{Field: "temperature", Comparator: ">", Threshold: threshold(30)},
-				{Field: "humidity", Comparator: "<", Threshold: threshold(30)},
-			},
-			operator: rules.OperatorOR,
-			msg: protomfx.Message{
-				Publisher:   thingID,
-				Payload:     mustMarshal(t, map[string]any{"temperature": float64(25), "humidity": float64(10)}),
-				ContentType: "application/json",
-			},
-			err: nil,
+			desc:    "transform payload exceeding instruction limit",
+			script:  `local n = 0 for i = 1, 1000000 do n = n + i end return {n = n}`,
+			payload: `{"temperature":85}`,
+			status:  rules.ScriptRunStatusFail,
+			res:     `{"temperature":85}`,
+		},
+	}
+
+	for _, tc := range cases {
+		pub := mocks.NewPublisher()
+		svc := newServiceWithPub(pub)
+
+		scripts, err := svc.CreateScripts(context.Background(), token, groupID, rules.LuaScript{Name: "transform", Script: tc.script})
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+
+		err = svc.TransformMessage("transforms."+scripts[0].ID, protomfx.Message{
+			Publisher:   thingID,
+			Payload:     []byte(tc.payload),
+			ContentType: "application/json",
+		})
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+
+		msgs := mocks.PublishedMessages(pub)
+		switch tc.res {
+		case "":
+			assert.Empty(t, msgs, fmt.Sprintf("%s: expected dropped message got %v", tc.desc, msgs))
+		default:
+			require.Len(t, msgs, 1, fmt.Sprintf("%s: expected 1 message got %d", tc.desc, len(msgs)))
+			assert.JSONEq(t, tc.res, string(msgs[0].Payload), fmt.Sprintf("%s: unexpected payload", tc.desc))
+		}
+
+		// Only failed transform runs are saved, while all of them are added to the script stats.
+		page, err := svc.ListScriptRunsByThing(context.Background(), token, thingID, rules.PageMetadata{})
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		stats, err := svc.ViewScriptStats(context.Background(), token, scripts[0].ID)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		switch tc.status {
+		case rules.ScriptRunStatusSuccess:
+			assert.Empty(t, page.Runs, fmt.Sprintf("%s: expected no saved runs got %d", tc.desc, len(page.Runs)))
+			assert.NotZero(t, stats.Succeeded, fmt.Sprintf("%s: expected succeeded runs in stats", tc.desc))
+		default:
+			require.Len(t, page.Runs, 1, fmt.Sprintf("%s: expected 1 saved run got %d", tc.desc, len(page.Runs)))
+			assert.Equal(t, tc.status, page.Runs[0].Status, fmt.Sprintf("%s: expected run status %s got %s", tc.desc, tc.status, page.Runs[0].Status))
+			assert.Equal(t, uint64(1), stats.Failed, fmt.Sprintf("%s: expected 1 failed run in stats got %d", tc.desc, stats.Failed))
+		}
+	}
+
+	// Updating a transform script applies to the following messages, although the script is cached.
+	pub := mocks.NewPublisher()
+	svc := newServiceWithPub(pub)
+	scripts, err := svc.CreateScripts(context.Background(), token, groupID, rules.LuaScript{Name: "transform", Script: `return {version = 1}`})
+	require.Nil(t, err, fmt.Sprintf("transform payload with updated script: unexpected error %s", err))
+	for _, src := range []string{`return {version = 1}`, `return {version = 2}`} {
+		sc := scripts[0]
+		sc.Script = src
+		err = svc.UpdateScript(context.Background(), token, sc)
+		require.Nil(t, err, fmt.Sprintf("transform payload with updated script: unexpected error %s", err))
+		err = svc.TransformMessage("transforms."+sc.ID, protomfx.Message{Publisher: thingID, Payload: []byte(`{"temperature":85}`)})
+		require.Nil(t, err, fmt.Sprintf("transform payload with updated script: unexpected error %s", err))
+	}
+	msgs := mocks.PublishedMessages(pub)
+	require.Len(t, msgs, 2, fmt.Sprintf("transform payload with updated script: expected 2 messages got %d", len(msgs)))
+	assert.JSONEq(t, `{"version":2}`, string(msgs[1].Payload), "transform payload with updated script: unexpected payload")
+
+	pub = mocks.NewPublisher()
+	svc = newServiceWithPub(pub)
+	err = svc.TransformMessage("transforms."+wrongValue, protomfx.Message{Publisher: thingID, Payload: []byte(`{"temperature":85}`)})
+	require.Nil(t, err, fmt.Sprintf("transform payload with unknown script: unexpected error %s", err))
+	msgs = mocks.PublishedMessages(pub)
+	require.Len(t, msgs, 1, fmt.Sprintf("transform payload with unknown script: expected 1 message got %d", len(msgs)))
+	assert.JSONEq(t, `{"temperature":85}`, string(msgs[0].Payload), "transform payload with unknown script: unexpected payload")
+}
+
+func TestTestRule(t *testing.T) {
+	pub := mocks.NewPublisher()
+	svc := newServiceWithPub(pub)
+
+	rule := rules.Rule{
+		Input: rules.Input{Type: rules.InputTypeMessage},
+		Expression: &rules.ConditionGroup{
+			Operator:   rules.OperatorAND,
+			Conditions: []rules.Condition{{Field: "temperature", Comparator: ">", Threshold: threshold(30)}},
+			Groups: []rules.ConditionGroup{{
+				Operator: rules.OperatorOR,
+				Conditions: []rules.Condition{
+					{Field: "humidity", Comparator: "<", Threshold: threshold(20)},
+					{Field: "status", Comparator: "==", Value: "alarm"},
+				},
+			}},
+		},
+		Actions: []rules.Action{
+			{Type: rules.ActionTypeAlarm, Level: 3},
+			{Type: rules.ActionTypeCommand, Target: rules.ActionTargetThing, ID: thingID, Payload: `{"temperature":{{.payload.temperature}}}`},
+			{Type: rules.ActionTypeCommand, Target: rules.ActionTargetThing, ID: thingID, Payload: `{"pressure":{{.payload.pressure}}}`},
 		},
+	}
+
+	cases := []struct {
+		desc      string
+		token     string
+		publisher string
+		payload   string
+		triggered bool
+		met       []bool
+		actions   []rules.ActionResult
+		err       error
+	}{
 		{
-			desc: "OR operator: no condition met",
-			conditions: []rules.Condition{
-				{Field: "temperature", Comparator: ">", Threshold: threshold(30)},
-				{Field: "humidity", Comparator: "<", Threshold: threshold(20)},
-			},
-			operator: rules.OperatorOR,
-			msg: protomfx.Message{
-				Publisher:   thingID,
-				Payload:     mustMarshal(t, map[string]any{"temperature": float64(25), "humidity": float64(25)}),
-				ContentType: "application/json",
+			desc:      "test triggered rule",
+			token:     token,
+			publisher: thingID,
+			payload:   `{"temperature":35,"humidity":50,"status":"alarm"}`,
+			triggered: true,
+			met:       []bool{true, false, true},
+			actions: []rules.ActionResult{
+				{Action: rule.Actions[0]},
+				{Action: rule.Actions[1], Payload: `{"temperature":35}`},
+				{Action: rule.Actions[2], Error: `template: payload:1:22: executing "payload" at <.payload.pressure>: map has no entry for key "pressure"`},
 			},
 			err: nil,
 		},
-		// comparators
 		{
-			desc: "== comparator: exact match",
-			conditions: []rules.Condition{
-				{Field: "temperature", Comparator: "==", Threshold: threshold(25)},
-			},
-			operator: rules.OperatorAND,
-			msg: protomfx.Message{
-				Publisher:   thingID,
-				Payload:     mustMarshal(t, map[string]any{"temperature": float64(25)}),
-				ContentType: "application/json",
-			},
-			err: nil,
+			desc:      "test rule not triggered",
+			token:     token,
+			payload:   `{"temperature":25,"humidity":10}`,
+			triggered: false,
+			met:       []bool{false, true, false},
+			err:       nil,
 		},
 		{
-			desc: ">= comparator: equal value",
-			conditions: []rules.Condition{
-				{Field: "temperature", Comparator: ">=", Threshold: threshold(25)},
-			},
-			operator: rules.OperatorAND,
-			msg: protomfx.Message{
-				Publisher:   thingID,
-				Payload:     mustMarshal(t, map[string]any{"temperature": float64(25)}),
-				ContentType: "application/json",
-			},
-			err: nil,
+			desc:      "test rule against array payload",
+			token:     token,
+			payload:   `[{"temperature":25,"humidity":10},{"temperature":35,"status":"ok"}]`,
+			triggered: false,
+			met:       []bool{true, true, false},
+			err:       nil,
 		},
 		{
-			desc: "<= comparator: equal value",
-			conditions: []rules.Condition{
-				{Field: "temperature", Comparator: "<=", Threshold: threshold(25)},
-			},
-			operator: rules.OperatorAND,
-			msg: protomfx.Message{
-				Publisher:   thingID,
-				Payload:     mustMarshal(t, map[string]any{"temperature": float64(25)}),
-				ContentType: "application/json",
-			},
-			err: nil,
+			desc:    "test rule with invalid payload",
+			token:   token,
+			payload: `5`,
+			err:     errors.ErrMalformedEntity,
+		},
+		{
+			desc:      "test rule with publisher from unknown thing",
+			token:     token,
+			publisher: wrongValue,
+			payload:   `{"temperature":35}`,
+			err:       dbutil.ErrNotFound,
+		},
+		{
+			desc:    "test rule with invalid auth token",
+			token:   wrongValue,
+			payload: `{"temperature":35}`,
+			err:     errors.ErrAuthentication,
 		},
 	}
 
 	for _, tc := range cases {
-		svc := newService()
-
-		if len(tc.conditions) > 0 {
-			_, err := svc.CreateRules(context.Background(), token, groupID, rules.Rule{
-				Name:       "test-rule",
-				Input:      rules.Input{Type: rules.InputTypeMessage, ThingIDs: []string{thingID}},
-				Conditions: tc.conditions,
-				Operator:   tc.operator,
-				Actions:    []rules.Action{{Type: rules.ActionTypeAlarm}},
-			})
-			require.Nil(t, err)
+		msg := protomfx.Message{Publisher: tc.publisher, Payload: []byte(tc.payload), ContentType: messaging.JSONContentType}
+		res, err := svc.TestRule(context.Background(), tc.token, groupID, rule, msg)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		if tc.err != nil {
+			continue
 		}
 
-		err := svc.ConsumeMessage(subject, tc.msg)
-		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		assert.Equal(t, tc.triggered, res.Triggered, fmt.Sprintf("%s: expected triggered %t got %t", tc.desc, tc.triggered, res.Triggered))
+		var met []bool
+		for _, c := range res.Conditions {
+			met = append(met, c.Met)
+		}
+		assert.Equal(t, tc.met, met, fmt.Sprintf("%s: expected met conditions %v got %v", tc.desc, tc.met, met))
+		assert.Equal(t, tc.actions, res.Actions, fmt.Sprintf("%s: expected actions %v got %v", tc.desc, tc.actions, res.Actions))
 	}
+
+	alarms := mocks.PublishedAlarms(pub)
+	assert.Empty(t, alarms, fmt.Sprintf("expected no published alarms got %d", len(alarms)))
+	cmds := mocks.PublishedCommands(pub)
+	assert.Empty(t, cmds, fmt.Sprintf("expected no published commands got %d", len(cmds)))
 }
 
 func TestCreateRules(t *testing.T) {
@@ -713,3 +2498,159 @@ func TestUnassignRulesFromThing(t *testing.T) {
 		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
 	}
 }
+
+func TestUnassignRulesFromThingAggregation(t *testing.T) {
+	pub := mocks.NewPublisher()
+	svc := newServiceWithPub(pub)
+
+	rs, err := svc.CreateRules(context.Background(), token, groupID, rules.Rule{
+		Name:       "aggregate-rule",
+		Input:      rules.Input{Type: rules.InputTypeMessage, ThingIDs: []string{thingID}},
+		Conditions: []rules.Condition{{Field: "temperature", Comparator: ">", Threshold: threshold(100), Aggregation: &rules.Aggregation{Type: rules.AggregationSum, Count: 2}}},
+		Actions:    []rules.Action{{Type: rules.ActionTypeAlarm, Level: 1}},
+	})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	consume := func() {
+		err := svc.ConsumeMessage(subject, protomfx.Message{
+			Publisher:   thingID,
+			Payload:     mustMarshal(t, map[string]any{"temperature": 60}),
+			ContentType: "application/json",
+			Created:     time.Now().UnixNano(),
+		})
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	consume()
+	err = svc.UnassignRulesFromThing(context.Background(), thingID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.AssignThings(context.Background(), token, rs[0].ID, thingID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	// The window of the unassigned thing is removed, so the sum only covers the message consumed after reassigning it.
+	consume()
+	alarms := len(mocks.PublishedAlarms(pub))
+	assert.Equal(t, 0, alarms, fmt.Sprintf("expected no alarms got %d", alarms))
+}
+
+func TestUnassignThingsAggregation(t *testing.T) {
+	pub := mocks.NewPublisher()
+	svc := newServiceWithPub(pub)
+
+	rs, err := svc.CreateRules(context.Background(), token, groupID, rules.Rule{
+		Name:       "aggregate-rule",
+		Input:      rules.Input{Type: rules.InputTypeMessage, ThingIDs: []string{thingID}},
+		Conditions: []rules.Condition{{Field: "temperature", Comparator: ">", Threshold: threshold(100), Aggregation: &rules.Aggregation{Type: rules.AggregationSum, Count: 2}}},
+		Actions:    []rules.Action{{Type: rules.ActionTypeAlarm, Level: 1}},
+	})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	consume := func() {
+		err := svc.ConsumeMessage(subject, protomfx.Message{
+			Publisher:   thingID,
+			Payload:     mustMarshal(t, map[string]any{"temperature": 60}),
+			ContentType: "application/json",
+			Created:     time.Now().UnixNano(),
+		})
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	consume()
+	err = svc.UnassignThings(context.Background(), token, rs[0].ID, thingID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.AssignThings(context.Background(), token, rs[0].ID, thingID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	// The window of the unassigned thing is removed, so the sum only covers the message consumed after reassigning it.
+	consume()
+	alarms := len(mocks.PublishedAlarms(pub))
+	assert.Equal(t, 0, alarms, fmt.Sprintf("expected no alarms got %d", alarms))
+}
+
+func TestTestScript(t *testing.T) {
+	pub := mocks.NewPublisher()
+	svc := newServiceWithPub(pub)
+
+	cases := []struct {
+		desc    string
+		token   string
+		script  string
+		payload string
+		results []rules.ScriptTestResult
+		err     error
+	}{
+		{
+			desc:    "test script triggering an alarm",
+			token:   token,
+			script:  `if mfx.message.payload.temperature > 30 then mfx.log("hot") mfx.create_alarm(4) end`,
+			payload: `{"temperature":35}`,
+			results: []rules.ScriptTestResult{
+				{Status: rules.ScriptRunStatusSuccess, Logs: []string{"hot"}, Actions: []rules.Action{{Type: rules.ActionTypeAlarm, Level: 4}}},
+			},
+			err: nil,
+		},
+		{
+			desc:    "test script against array payload",
+			token:   token,
+			script:  `mfx.log(tostring(mfx.message.payload.temperature))`,
+			payload: `[{"temperature":35},{"temperature":20}]`,
+			results: []rules.ScriptTestResult{
+				{Status: rules.ScriptRunStatusSuccess, Logs: []string{"35"}},
+				{Status: rules.ScriptRunStatusSuccess, Logs: []string{"20"}},
+			},
+			err: nil,
+		},
+		{
+			desc:    "test script using key-value state, commands and http requests",
+			token:   token,
+			script:  `mfx.kv_set("n", 1) mfx.log(tostring(mfx.kv_get("n"))) mfx.send_command("group", "` + groupID + `", {fan = "on"}) mfx.patch_shadow({fan = "off"}) local res, err = mfx.http_request("GET", "http://example.com") mfx.log(err)`,
+			payload: `{"temperature":35}`,
+			results: []rules.ScriptTestResult{
+				{
+					Status:           rules.ScriptRunStatusSuccess,
+					Logs:             []string{"1", rules.ErrHostNotAllowed.Error()},
+					InstructionCount: 90000,
+					Actions: []rules.Action{
+						{Type: rules.ActionTypeCommand, Target: rules.ActionTargetGroup, ID: groupID, Payload: `{"fan":"on"}`},
+						{Type: rules.ActionTypeShadow, Target: rules.ActionTargetThing, ID: thingID, Payload: `{"fan":"off"}`},
+					},
+				},
+			},
+			err: nil,
+		},
+		{
+			desc:    "test script with runtime error",
+			token:   token,
+			script:  `error("failed")`,
+			payload: `{"temperature":35}`,
+			results: []rules.ScriptTestResult{
+				{Status: rules.ScriptRunStatusFail, Logs: []string{}, Error: "runtime error: [string \"error(\"failed\")\"]:1: failed"},
+			},
+			err: nil,
+		},
+		{
+			desc:    "test script with invalid payload",
+			token:   token,
+			script:  `mfx.log("ok")`,
+			payload: `"temperature"`,
+			err:     errors.ErrMalformedEntity,
+		},
+		{
+			desc:    "test script with invalid auth token",
+			token:   wrongValue,
+			script:  `mfx.log("ok")`,
+			payload: `{"temperature":35}`,
+			err:     errors.ErrAuthentication,
+		},
+	}
+
+	for _, tc := range cases {
+		msg := protomfx.Message{Publisher: thingID, Payload: []byte(tc.payload), ContentType: messaging.JSONContentType}
+		results, err := svc.TestScript(context.Background(), tc.token, groupID, rules.LuaScript{Script: tc.script}, msg)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		assert.Equal(t, tc.results, results, fmt.Sprintf("%s: expected results %v got %v", tc.desc, tc.results, results))
+	}
+
+	alarms := mocks.PublishedAlarms(pub)
+	assert.Empty(t, alarms, fmt.Sprintf("expected no published alarms got %d", len(alarms)))
+}
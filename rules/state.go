@@ -0,0 +1,243 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/MainfluxLabs/mainflux/pkg/predicate"
+)
+
+const (
+	ConditionModeValue = ""
+	ConditionModeDelta = "delta"
+	ConditionModeRate  = "rate"
+
+	// stateCacheSize is the number of rule states cached by the service.
+	stateCacheSize = 10_000
+	// stateCacheTTL bounds the time state changes made through other instances of the
+	// service take to apply.
+	stateCacheTTL = 10 * time.Second
+)
+
+// RuleState represents the evaluation state of a rule for a single thing and subtopic.
+// It is kept for rules with stateful conditions and persisted between evaluations.
+type RuleState struct {
+	RuleID  string
+	ThingID string
+	// Subtopic is the subtopic of the evaluated messages, so that alarms raised on a subtopic
+	// are cleared by messages of the same subtopic.
+	Subtopic string
+	// Conditions holds the state of each rule condition, indexed by the condition position
+	// in the depth-first traversal of the rule expression.
+	Conditions []ConditionState
+	// Triggered reports whether the rule conditions were met by the last evaluated message.
+	Triggered bool
+	// Fired is the time (unix nanoseconds) the rule last fired.
+	Fired int64
+	// Updated is the creation time (unix nanoseconds) of the last evaluated message.
+	Updated int64
+}
+
+// stateKey identifies the evaluation state of a rule for a thing and subtopic.
+type stateKey struct {
+	ruleID   string
+	thingID  string
+	subtopic string
+}
+
+// ConditionState represents the evaluation state of a single stateful condition.
+type ConditionState struct {
+	// Active reports whether a hysteresis condition is currently set.
+	Active bool `json:"active,omitempty"`
+	// MetSince is the time (unix nanoseconds) since the condition has been continuously met.
+	MetSince int64 `json:"met_since,omitempty"`
+	// Value is the last observed field value, used by delta and rate conditions.
+	Value *float64 `json:"value,omitempty"`
+	// Created is the time (unix nanoseconds) the last value was observed.
+	Created int64 `json:"created,omitempty"`
+}
+
+// isStatefulCondition reports whether evaluating the condition depends on previous messages.
+func isStatefulCondition(c Condition) bool {
+	return c.ClearThreshold != nil || c.Duration > 0 || c.Mode != ConditionModeValue
+}
+
+// IsStateful reports whether any of the rule conditions depends on previous messages.
+func (r Rule) IsStateful() bool {
+	return isStatefulGroup(r.expression())
+}
+
+func isStatefulGroup(group ConditionGroup) bool {
+	for _, c := range group.Conditions {
+		if isStatefulCondition(c) {
+			return true
+		}
+	}
+	for _, g := range group.Groups {
+		if isStatefulGroup(g) {
+			return true
+		}
+	}
+	return false
+}
+
+// suppressesFiring reports whether the rule may be kept from firing although its conditions are met.
+func (r Rule) suppressesFiring() bool {
+	return r.Cooldown > 0 || r.OnTransition
+}
+
+// fire reports whether the rule fires, given whether its conditions are met at time now,
+// and records the outcome. Rules firing on transition only fire when their conditions
+// become met, and no rule fires again before its cooldown has passed.
+func (s *RuleState) fire(rule Rule, triggered bool, now int64) bool {
+	transition := !s.Triggered
+	s.Triggered = triggered
+
+	if !triggered || (rule.OnTransition && !transition) {
+		return false
+	}
+	if rule.Cooldown > 0 && s.Fired > 0 && time.Duration(now-s.Fired) < time.Duration(rule.Cooldown)*time.Second {
+		return false
+	}
+
+	s.Fired = now
+	return true
+}
+
+// retrieveState returns the evaluation state of the rule for the thing and subtopic,
+// retrieving it from the repository unless it's cached.
+func (rs *rulesService) retrieveState(ctx context.Context, ruleID, thingID, subtopic string) (RuleState, error) {
+	key := stateKey{ruleID: ruleID, thingID: thingID, subtopic: subtopic}
+	if st, ok := rs.states.Get(key); ok {
+		// Condition states are updated in place, so the cached ones must not be shared.
+		st.Conditions = slices.Clone(st.Conditions)
+		return st, nil
+	}
+
+	st, err := rs.rules.RetrieveState(ctx, ruleID, thingID, subtopic)
+	if err != nil {
+		return RuleState{}, err
+	}
+	rs.states.Add(key, st)
+
+	return st, nil
+}
+
+// saveState caches the state, and persists it if it changed since prev. Condition states
+// change with every evaluated message, while rules without stateful conditions only change
+// their state when they become triggered or cleared, or fire, so that they aren't persisted
+// on every message.
+func (rs *rulesService) saveState(ctx context.Context, prev, state RuleState) error {
+	key := stateKey{ruleID: state.RuleID, thingID: state.ThingID, subtopic: state.Subtopic}
+	if len(state.Conditions) > 0 || state.Triggered != prev.Triggered || state.Fired != prev.Fired {
+		if err := rs.rules.SaveState(ctx, state); err != nil {
+			rs.states.Remove(key)
+			return err
+		}
+	}
+	rs.states.Add(key, state)
+
+	return nil
+}
+
+// removeStates removes the cached states of the rule for the given things, or for all
+// things if none are given.
+func (rs *rulesService) removeStates(ruleID string, thingIDs ...string) {
+	rs.states.RemoveFunc(func(key stateKey) bool {
+		return key.ruleID == ruleID && (len(thingIDs) == 0 || slices.Contains(thingIDs, key.thingID))
+	})
+}
+
+// removeThingStates removes the cached states of all rules for the thing.
+func (rs *rulesService) removeThingStates(thingID string) {
+	rs.states.RemoveFunc(func(key stateKey) bool {
+		return key.thingID == thingID
+	})
+}
+
+// conditionState returns the state of the condition at index i, resetting the
+// state if it doesn't match the number of conditions (e.g. after a rule update).
+func (s *RuleState) conditionState(i, count int) *ConditionState {
+	if len(s.Conditions) != count {
+		s.Conditions = make([]ConditionState, count)
+	}
+	return &s.Conditions[i]
+}
+
+// evaluateStatefulCondition evaluates the condition against value observed at time now,
+// updating the condition state accordingly.
+func evaluateStatefulCondition(condition Condition, value any, cs *ConditionState, now int64, patterns *predicate.Patterns) bool {
+	if condition.Mode != ConditionModeValue {
+		change, ok := valueChange(condition.Mode, value, cs, now)
+		if !ok {
+			return holdsFor(condition, false, cs, now)
+		}
+		value = change
+	}
+
+	if condition.ClearThreshold == nil {
+		return holdsFor(condition, isConditionMet(condition, value, patterns), cs, now)
+	}
+
+	payloadValue, ok := predicate.ToFloat(value)
+	if !ok {
+		return holdsFor(condition, false, cs, now)
+	}
+
+	threshold := *condition.Threshold
+	if cs.Active {
+		threshold = *condition.ClearThreshold
+	}
+	cs.Active = predicate.CompareNumbers(condition.Comparator, payloadValue, threshold)
+
+	return holdsFor(condition, cs.Active, cs, now)
+}
+
+// valueChange returns the difference, or the change per second, between value and
+// the previously observed value, and records value as the latest observation.
+func valueChange(mode string, value any, cs *ConditionState, now int64) (float64, bool) {
+	current, ok := predicate.ToFloat(value)
+	if !ok {
+		return 0, false
+	}
+
+	prev, prevCreated := cs.Value, cs.Created
+	cs.Value, cs.Created = &current, now
+	if prev == nil {
+		return 0, false
+	}
+
+	change := current - *prev
+	if mode == ConditionModeDelta {
+		return change, true
+	}
+
+	elapsed := time.Duration(now - prevCreated).Seconds()
+	if elapsed <= 0 {
+		return 0, false
+	}
+
+	return change / elapsed, true
+}
+
+// holdsFor applies the condition duration: a met condition is reported as met
+// only once it has been met continuously for at least the condition duration.
+func holdsFor(condition Condition, met bool, cs *ConditionState, now int64) bool {
+	if condition.Duration == 0 {
+		return met
+	}
+
+	if !met {
+		cs.MetSince = 0
+		return false
+	}
+	if cs.MetSince == 0 {
+		cs.MetSince = now
+	}
+
+	return time.Duration(now-cs.MetSince) >= time.Duration(condition.Duration)*time.Second
+}
@@ -0,0 +1,108 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// statsFlushInterval is the interval at which the buffered script run stats are saved.
+const statsFlushInterval = 10 * time.Second
+
+// scriptStatsBuffer aggregates the stats of the script runs which aren't saved one by one,
+// so that they are saved with a single update per script.
+type scriptStatsBuffer struct {
+	mu     sync.Mutex
+	totals map[string]ScriptRunTotals
+}
+
+func newScriptStatsBuffer() *scriptStatsBuffer {
+	return &scriptStatsBuffer{
+		totals: make(map[string]ScriptRunTotals),
+	}
+}
+
+// add adds the run to the totals of its script.
+func (b *scriptStatsBuffer) add(run ScriptRun) {
+	t := ScriptRunTotals{
+		ScriptID:         run.ScriptID,
+		Duration:         run.FinishedAt.Sub(run.StartedAt),
+		InstructionCount: uint64(run.InstructionCount),
+		LastRunAt:        run.FinishedAt,
+	}
+
+	switch run.Status {
+	case ScriptRunStatusSuccess:
+		t.Succeeded = 1
+	default:
+		t.Failed = 1
+	}
+
+	b.merge(t)
+}
+
+// merge adds the totals to the buffered totals of their scripts.
+func (b *scriptStatsBuffer) merge(totals ...ScriptRunTotals) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, t := range totals {
+		bt := b.totals[t.ScriptID]
+		bt.ScriptID = t.ScriptID
+		bt.Succeeded += t.Succeeded
+		bt.Failed += t.Failed
+		bt.Duration += t.Duration
+		bt.InstructionCount += t.InstructionCount
+		if t.LastRunAt.After(bt.LastRunAt) {
+			bt.LastRunAt = t.LastRunAt
+		}
+		b.totals[t.ScriptID] = bt
+	}
+}
+
+// take removes the buffered totals and returns them.
+func (b *scriptStatsBuffer) take() []ScriptRunTotals {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var totals []ScriptRunTotals
+	for id, t := range b.totals {
+		totals = append(totals, t)
+		delete(b.totals, id)
+	}
+
+	return totals
+}
+
+func (rs *rulesService) flushScriptStatsLoop(ctx context.Context) {
+	ticker := time.NewTicker(statsFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			rs.flushScriptStats(ctx)
+		case <-ctx.Done():
+			rs.flushScriptStats(context.Background())
+			return
+		}
+	}
+}
+
+// flushScriptStats saves the buffered script run stats. Stats that can't be saved are kept
+// in the buffer, to be saved with the next flush.
+func (rs *rulesService) flushScriptStats(ctx context.Context) {
+	totals := rs.scriptStats.take()
+	if len(totals) == 0 {
+		return
+	}
+
+	if err := rs.rules.SaveScriptRunTotals(ctx, totals...); err != nil {
+		rs.logger.Error(fmt.Sprintf("saving script run stats failed with error: %v", err))
+		rs.scriptStats.merge(totals...)
+	}
+}
@@ -0,0 +1,75 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/MainfluxLabs/mainflux/pkg/dbutil"
+	"github.com/MainfluxLabs/mainflux/rules"
+	"github.com/opentracing/opentracing-go"
+)
+
+const (
+	saveModules            = "save_modules"
+	retrieveModuleByID     = "retrieve_module_by_id"
+	retrieveModuleByName   = "retrieve_module_by_name"
+	retrieveModulesByGroup = "retrieve_modules_by_group"
+	updateModule           = "update_module"
+	removeModules          = "remove_modules"
+	removeModulesByGroup   = "remove_modules_by_group"
+)
+
+func (rpm ruleRepositoryMiddleware) SaveModules(ctx context.Context, modules ...rules.LuaModule) ([]rules.LuaModule, error) {
+	span := dbutil.CreateSpan(ctx, rpm.tracer, saveModules)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rpm.repo.SaveModules(ctx, modules...)
+}
+
+func (rpm ruleRepositoryMiddleware) RetrieveModuleByID(ctx context.Context, id string) (rules.LuaModule, error) {
+	span := dbutil.CreateSpan(ctx, rpm.tracer, retrieveModuleByID)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rpm.repo.RetrieveModuleByID(ctx, id)
+}
+
+func (rpm ruleRepositoryMiddleware) RetrieveModuleByName(ctx context.Context, groupID, name string) (rules.LuaModule, error) {
+	span := dbutil.CreateSpan(ctx, rpm.tracer, retrieveModuleByName)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rpm.repo.RetrieveModuleByName(ctx, groupID, name)
+}
+
+func (rpm ruleRepositoryMiddleware) RetrieveModulesByGroup(ctx context.Context, groupID string, pm rules.PageMetadata) (rules.LuaModulesPage, error) {
+	span := dbutil.CreateSpan(ctx, rpm.tracer, retrieveModulesByGroup)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rpm.repo.RetrieveModulesByGroup(ctx, groupID, pm)
+}
+
+func (rpm ruleRepositoryMiddleware) UpdateModule(ctx context.Context, module rules.LuaModule) error {
+	span := dbutil.CreateSpan(ctx, rpm.tracer, updateModule)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rpm.repo.UpdateModule(ctx, module)
+}
+
+func (rpm ruleRepositoryMiddleware) RemoveModules(ctx context.Context, ids ...string) error {
+	span := dbutil.CreateSpan(ctx, rpm.tracer, removeModules)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rpm.repo.RemoveModules(ctx, ids...)
+}
+
+func (rpm ruleRepositoryMiddleware) RemoveModulesByGroup(ctx context.Context, groupID string) error {
+	span := dbutil.CreateSpan(ctx, rpm.tracer, removeModulesByGroup)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rpm.repo.RemoveModulesByGroup(ctx, groupID)
+}
@@ -19,6 +19,9 @@ const (
 	removeRules            = "remove_rules"
 	removeRulesByGroup     = "remove_rules_by_group"
 	unassignRulesFromThing = "unassign_rules_from_thing"
+	retrieveRuleState      = "retrieve_rule_state"
+	saveRuleState          = "save_rule_state"
+	removeRuleStates       = "remove_rule_states"
 )
 
 var (
@@ -117,3 +120,27 @@ func (rpm ruleRepositoryMiddleware) UnassignRulesFromThing(ctx context.Context,
 
 	return rpm.repo.UnassignRulesFromThing(ctx, thingID)
 }
+
+func (rpm ruleRepositoryMiddleware) RetrieveState(ctx context.Context, ruleID, thingID, subtopic string) (rules.RuleState, error) {
+	span := dbutil.CreateSpan(ctx, rpm.tracer, retrieveRuleState)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rpm.repo.RetrieveState(ctx, ruleID, thingID, subtopic)
+}
+
+func (rpm ruleRepositoryMiddleware) SaveState(ctx context.Context, state rules.RuleState) error {
+	span := dbutil.CreateSpan(ctx, rpm.tracer, saveRuleState)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rpm.repo.SaveState(ctx, state)
+}
+
+func (rpm ruleRepositoryMiddleware) RemoveStates(ctx context.Context, ruleID string) error {
+	span := dbutil.CreateSpan(ctx, rpm.tracer, removeRuleStates)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rpm.repo.RemoveStates(ctx, ruleID)
+}
@@ -2,6 +2,7 @@ package tracing
 
 import (
 	"context"
+	"time"
 
 	"github.com/MainfluxLabs/mainflux/pkg/dbutil"
 	"github.com/MainfluxLabs/mainflux/rules"
@@ -13,6 +14,7 @@ const (
 	retrieveScriptByID        = "retrieve_script_by_id"
 	retrieveScriptsByThing    = "retrieve_scripts_by_thing"
 	retrieveScriptsByGroup    = "retrieve_scripts_by_group"
+	retrieveScheduledScripts  = "retrieve_scheduled_scripts"
 	retrieveThingIDsByScript  = "retrieve_thing_ids_by_script"
 	updateScript              = "update_script"
 	removeScripts             = "remove_scripts"
@@ -24,6 +26,17 @@ const (
 	retrieveScriptRunsByThing = "retrieve_script_runs_by_thing"
 	removeScriptRuns          = "remove_script_runs"
 	retrieveScriptRunByID     = "retrieve_script_run_by_id"
+	retrieveScriptValue       = "retrieve_script_value"
+	saveScriptValue           = "save_script_value"
+	removeScriptValue         = "remove_script_value"
+	updateScriptStatus        = "update_script_status"
+	retrieveScriptVersions    = "retrieve_script_versions"
+	retrieveScriptVersion     = "retrieve_script_version"
+	updateThingScriptsStatus  = "update_thing_scripts_status"
+	pruneScriptRuns           = "prune_script_runs"
+	claimScheduledRun         = "claim_scheduled_run"
+	retrieveScriptStats       = "retrieve_script_stats"
+	saveScriptRunTotals       = "save_script_run_totals"
 )
 
 func (rpm ruleRepositoryMiddleware) SaveScripts(ctx context.Context, scripts ...rules.LuaScript) ([]rules.LuaScript, error) {
@@ -58,12 +71,20 @@ func (rpm ruleRepositoryMiddleware) RetrieveScriptsByGroup(ctx context.Context,
 	return rpm.repo.RetrieveScriptsByGroup(ctx, groupID, pm)
 }
 
-func (rpm ruleRepositoryMiddleware) RetrieveThingIDsByScript(ctx context.Context, scriptID string) ([]string, error) {
+func (rpm ruleRepositoryMiddleware) RetrieveScheduledScripts(ctx context.Context) ([]rules.LuaScript, error) {
+	span := dbutil.CreateSpan(ctx, rpm.tracer, retrieveScheduledScripts)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rpm.repo.RetrieveScheduledScripts(ctx)
+}
+
+func (rpm ruleRepositoryMiddleware) RetrieveThingIDsByScript(ctx context.Context, scriptID, status string) ([]string, error) {
 	span := dbutil.CreateSpan(ctx, rpm.tracer, retrieveThingIDsByScript)
 	defer span.Finish()
 	ctx = opentracing.ContextWithSpan(ctx, span)
 
-	return rpm.repo.RetrieveThingIDsByScript(ctx, scriptID)
+	return rpm.repo.RetrieveThingIDsByScript(ctx, scriptID, status)
 }
 
 func (rpm ruleRepositoryMiddleware) UpdateScript(ctx context.Context, script rules.LuaScript) error {
@@ -145,3 +166,91 @@ func (rpm ruleRepositoryMiddleware) RetrieveScriptRunByID(ctx context.Context, i
 
 	return rpm.repo.RetrieveScriptRunByID(ctx, id)
 }
+
+func (rpm ruleRepositoryMiddleware) RetrieveScriptValue(ctx context.Context, scriptID, thingID, key string) (rules.ScriptValue, error) {
+	span := dbutil.CreateSpan(ctx, rpm.tracer, retrieveScriptValue)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rpm.repo.RetrieveScriptValue(ctx, scriptID, thingID, key)
+}
+
+func (rpm ruleRepositoryMiddleware) SaveScriptValue(ctx context.Context, value rules.ScriptValue) error {
+	span := dbutil.CreateSpan(ctx, rpm.tracer, saveScriptValue)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rpm.repo.SaveScriptValue(ctx, value)
+}
+
+func (rpm ruleRepositoryMiddleware) RemoveScriptValue(ctx context.Context, scriptID, thingID, key string) error {
+	span := dbutil.CreateSpan(ctx, rpm.tracer, removeScriptValue)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rpm.repo.RemoveScriptValue(ctx, scriptID, thingID, key)
+}
+
+func (rpm ruleRepositoryMiddleware) UpdateScriptStatus(ctx context.Context, id, status string) error {
+	span := dbutil.CreateSpan(ctx, rpm.tracer, updateScriptStatus)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rpm.repo.UpdateScriptStatus(ctx, id, status)
+}
+
+func (rpm ruleRepositoryMiddleware) RetrieveScriptVersions(ctx context.Context, scriptID string, pm rules.PageMetadata) (rules.ScriptVersionsPage, error) {
+	span := dbutil.CreateSpan(ctx, rpm.tracer, retrieveScriptVersions)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rpm.repo.RetrieveScriptVersions(ctx, scriptID, pm)
+}
+
+func (rpm ruleRepositoryMiddleware) RetrieveScriptVersion(ctx context.Context, scriptID string, version uint64) (rules.ScriptVersion, error) {
+	span := dbutil.CreateSpan(ctx, rpm.tracer, retrieveScriptVersion)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rpm.repo.RetrieveScriptVersion(ctx, scriptID, version)
+}
+
+func (rpm ruleRepositoryMiddleware) UpdateThingScriptsStatus(ctx context.Context, thingID, status string, scriptIDs ...string) error {
+	span := dbutil.CreateSpan(ctx, rpm.tracer, updateThingScriptsStatus)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rpm.repo.UpdateThingScriptsStatus(ctx, thingID, status, scriptIDs...)
+}
+
+func (rpm ruleRepositoryMiddleware) PruneScriptRuns(ctx context.Context, before time.Time, keep uint64) error {
+	span := dbutil.CreateSpan(ctx, rpm.tracer, pruneScriptRuns)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rpm.repo.PruneScriptRuns(ctx, before, keep)
+}
+
+func (rpm ruleRepositoryMiddleware) ClaimScheduledRun(ctx context.Context, scriptID string, trigger int, scheduledAt time.Time) (bool, error) {
+	span := dbutil.CreateSpan(ctx, rpm.tracer, claimScheduledRun)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rpm.repo.ClaimScheduledRun(ctx, scriptID, trigger, scheduledAt)
+}
+
+func (rpm ruleRepositoryMiddleware) RetrieveScriptStats(ctx context.Context, scriptID string) (rules.ScriptStats, error) {
+	span := dbutil.CreateSpan(ctx, rpm.tracer, retrieveScriptStats)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rpm.repo.RetrieveScriptStats(ctx, scriptID)
+}
+
+func (rpm ruleRepositoryMiddleware) SaveScriptRunTotals(ctx context.Context, totals ...rules.ScriptRunTotals) error {
+	span := dbutil.CreateSpan(ctx, rpm.tracer, saveScriptRunTotals)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rpm.repo.SaveScriptRunTotals(ctx, totals...)
+}
@@ -0,0 +1,211 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MainfluxLabs/mainflux/pkg/errors"
+	"github.com/MainfluxLabs/mainflux/pkg/messaging/nats"
+	protomfx "github.com/MainfluxLabs/mainflux/pkg/proto"
+	"github.com/Shopify/go-lua"
+)
+
+const (
+	// Transform scripts run before every message of the profile is stored, so they are
+	// limited much more strictly than the other scripts.
+	maxTransformInstructions = 100_000
+	transformTimeout         = 100 * time.Millisecond
+
+	transformTriggerType = "transform"
+
+	// transformCacheSize is the number of transform scripts, and of thing groups, cached by the service.
+	transformCacheSize = 10_000
+	// transformCacheTTL bounds the time changes made through other instances of the service take
+	// to apply to transforms.
+	transformCacheTTL = 10 * time.Second
+)
+
+var errTransformResult = errors.New("transform script must return a table or nil")
+
+// luaAPISetTransform lists the API functions available to transform scripts.
+var luaAPISetTransform = []luaAPIFunc{luaLog}
+
+func (rs *rulesService) TransformMessage(subject string, msg protomfx.Message) error {
+	ctx := context.Background()
+	scriptID := subject[strings.LastIndex(subject, ".")+1:]
+
+	payload, err := rs.transformPayload(ctx, scriptID, msg)
+	if err != nil {
+		// Messages that can't be transformed are stored as they are.
+		rs.logger.Error(fmt.Sprintf("transforming message with script with id %s failed with error: %v", scriptID, err))
+		payload = msg.Payload
+	}
+
+	// The script dropped the message.
+	if payload == nil {
+		return nil
+	}
+
+	msg.Payload = payload
+	return rs.pub.Publish(nats.GetMessagesSubject(msg.Publisher, msg.Subtopic), msg)
+}
+
+// transformPayload runs the transform script against the message payload, and returns the
+// transformed payload, or nil if the message is dropped. Each object of an array payload is
+// transformed separately, and the message is dropped only if all of them are.
+func (rs *rulesService) transformPayload(ctx context.Context, scriptID string, msg protomfx.Message) ([]byte, error) {
+	if !rs.scriptsConfig.Enabled {
+		return msg.Payload, nil
+	}
+
+	script, err := rs.transformScript(ctx, scriptID)
+	if err != nil {
+		return nil, err
+	}
+	if script.Status == ScriptDisabledStatus {
+		return msg.Payload, nil
+	}
+
+	groupID, err := rs.thingGroup(ctx, msg.Publisher)
+	if err != nil {
+		return nil, err
+	}
+	if groupID != script.GroupID {
+		return nil, errors.ErrAuthorization
+	}
+
+	var payload any
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return nil, err
+	}
+
+	switch p := payload.(type) {
+	case map[string]any:
+		res, err := rs.runTransform(ctx, script, msg, p)
+		if err != nil || res == nil {
+			return nil, err
+		}
+		return json.Marshal(res)
+	case []any:
+		var results []any
+		for _, item := range p {
+			obj, ok := item.(map[string]any)
+			if !ok {
+				results = append(results, item)
+				continue
+			}
+
+			res, err := rs.runTransform(ctx, script, msg, obj)
+			if err != nil {
+				return nil, err
+			}
+
+			switch r := res.(type) {
+			case nil:
+			case []any:
+				results = append(results, r...)
+			default:
+				results = append(results, r)
+			}
+		}
+
+		if len(results) == 0 {
+			return nil, nil
+		}
+		return json.Marshal(results)
+	default:
+		return msg.Payload, nil
+	}
+}
+
+func (rs *rulesService) transformScript(ctx context.Context, id string) (LuaScript, error) {
+	if script, ok := rs.transformScripts.Get(id); ok {
+		return script, nil
+	}
+
+	script, err := rs.rules.RetrieveScriptByID(ctx, id)
+	if err != nil {
+		return LuaScript{}, err
+	}
+	rs.transformScripts.Add(id, script)
+
+	return script, nil
+}
+
+func (rs *rulesService) thingGroup(ctx context.Context, thingID string) (string, error) {
+	if groupID, ok := rs.thingGroups.Get(thingID); ok {
+		return groupID, nil
+	}
+
+	groupID, err := rs.things.GetGroupIDByThing(ctx, thingID)
+	if err != nil {
+		return "", err
+	}
+	rs.thingGroups.Add(thingID, groupID)
+
+	return groupID, nil
+}
+
+// runTransform runs the transform script against the payload object and returns the table
+// returned by the script, converted to a map or a slice, or nil if the script returns nil.
+func (rs *rulesService) runTransform(ctx context.Context, script LuaScript, msg protomfx.Message, payload map[string]any) (any, error) {
+	trigger := map[string]any{
+		"type":     transformTriggerType,
+		"subtopic": msg.Subtopic,
+	}
+
+	env, err := NewLuaEnv(rs, &script, &msg, payload, trigger, luaAPISetTransform...)
+	if err != nil {
+		return nil, err
+	}
+	env.maxInstructions = maxTransformInstructions
+	env.deadline = time.Now().Add(transformTimeout)
+
+	run, err := env.execute()
+	if err != nil {
+		return nil, err
+	}
+
+	var res any
+	if run.err == nil {
+		res, err = transformResult(env.ls)
+		if err != nil {
+			run.err = err
+			run.Error = err.Error()
+			run.Status = ScriptRunStatusFail
+		}
+	}
+
+	// Transforms run for every stored message, so only failed runs are saved, while all
+	// of them are added to the script stats.
+	if run.Status != ScriptRunStatusSuccess {
+		if _, err := rs.rules.SaveScriptRuns(ctx, run); err != nil {
+			rs.logger.Error(fmt.Sprintf("preserving script run to database failed with error: %v", err))
+		}
+	}
+	rs.scriptStats.add(run)
+
+	return res, run.err
+}
+
+// transformResult returns the first value returned by the script, which is left on the stack.
+func transformResult(ls *lua.State) (any, error) {
+	if ls.Top() == 0 {
+		return nil, nil
+	}
+
+	switch ls.TypeOf(1) {
+	case lua.TypeNil:
+		return nil, nil
+	case lua.TypeTable:
+		return luaToGoValue(ls, 1, 0)
+	default:
+		return nil, errTransformResult
+	}
+}
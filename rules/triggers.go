@@ -0,0 +1,234 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/MainfluxLabs/mainflux/pkg/cron"
+	protomfx "github.com/MainfluxLabs/mainflux/pkg/proto"
+)
+
+const (
+	TriggerTypeMessage  = "message"
+	TriggerTypeAlarm    = "alarm"
+	TriggerTypeSchedule = "schedule"
+)
+
+// Trigger describes an event that runs a Lua script for the things it is assigned to.
+type Trigger struct {
+	Type string `json:"type"`
+	// Subtopic restricts message and alarm triggers to the messages and alarms of the subtopic.
+	// If empty, the script runs on all of them.
+	Subtopic string `json:"subtopic,omitempty"`
+	// Scheduler is the schedule of schedule triggers.
+	Scheduler *cron.Scheduler `json:"scheduler,omitempty"`
+}
+
+// triggeredBy reports whether the script runs on the input of the trigger type published to the subtopic.
+// Scripts without triggers run on every message.
+func (ls LuaScript) triggeredBy(triggerType, subtopic string) bool {
+	if len(ls.Triggers) == 0 {
+		return triggerType == TriggerTypeMessage
+	}
+
+	for _, t := range ls.Triggers {
+		if t.Type == triggerType && (t.Subtopic == "" || t.Subtopic == subtopic) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// scheduledScript represents the schedule triggers of a scheduled script.
+type scheduledScript struct {
+	groupID  string
+	triggers []Trigger
+}
+
+// scriptScheduler runs the scripts having schedule triggers on their schedules.
+type scriptScheduler struct {
+	mu      sync.Mutex
+	manager *cron.ScheduleManager
+	scripts map[string]scheduledScript
+}
+
+func newScriptScheduler() *scriptScheduler {
+	return &scriptScheduler{
+		manager: cron.NewScheduleManager(),
+		scripts: make(map[string]scheduledScript),
+	}
+}
+
+func (rs *rulesService) LoadAndScheduleScripts(ctx context.Context) error {
+	if !rs.scriptsConfig.Enabled {
+		return nil
+	}
+
+	scripts, err := rs.rules.RetrieveScheduledScripts(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, script := range scripts {
+		if err := rs.scheduleScript(script); err != nil {
+			return err
+		}
+	}
+
+	rs.pruneScriptRuns(ctx)
+	go rs.pruneScriptRunsLoop(ctx)
+	go rs.flushScriptStatsLoop(ctx)
+
+	go func() {
+		<-ctx.Done()
+		rs.scheduler.manager.Stop()
+	}()
+
+	return nil
+}
+
+// scheduleScript schedules the runs of the script on its schedule triggers, replacing the
+// schedules of its previous version. One-time schedules in the past are skipped.
+func (rs *rulesService) scheduleScript(script LuaScript) error {
+	rs.unscheduleScript(script.ID)
+
+	if !rs.scriptsConfig.Enabled || script.Status == ScriptDisabledStatus {
+		return nil
+	}
+
+	var triggers []Trigger
+	for _, t := range script.Triggers {
+		if t.Type != TriggerTypeSchedule || t.Scheduler == nil {
+			continue
+		}
+
+		if t.Scheduler.Frequency == cron.OnceFreq {
+			scheduledDateTime, err := cron.ParseTime(cron.DateTimeLayout, t.Scheduler.DateTime, t.Scheduler.TimeZone)
+			if err != nil {
+				return err
+			}
+			if !scheduledDateTime.After(time.Now().In(scheduledDateTime.Location())) {
+				continue
+			}
+		}
+
+		triggers = append(triggers, t)
+	}
+
+	if len(triggers) == 0 {
+		return nil
+	}
+
+	rs.scheduler.mu.Lock()
+	defer rs.scheduler.mu.Unlock()
+
+	for i, t := range triggers {
+		task := func() { rs.runScheduledScript(script.ID, i) }
+		entityID := scheduleEntityID(script.ID, i)
+
+		var err error
+		switch t.Scheduler.Frequency {
+		case cron.OnceFreq:
+			err = rs.scheduler.manager.ScheduleOneTimeTask(task, *t.Scheduler, entityID)
+		default:
+			err = rs.scheduler.manager.ScheduleRepeatingTask(task, *t.Scheduler, entityID)
+		}
+		if err != nil {
+			rs.removeSchedules(script.ID, triggers[:i])
+			return err
+		}
+	}
+	rs.scheduler.scripts[script.ID] = scheduledScript{groupID: script.GroupID, triggers: triggers}
+
+	return nil
+}
+
+// unscheduleScript stops the scheduled runs of the script, if any.
+func (rs *rulesService) unscheduleScript(id string) {
+	rs.scheduler.mu.Lock()
+	defer rs.scheduler.mu.Unlock()
+
+	if s, ok := rs.scheduler.scripts[id]; ok {
+		rs.removeSchedules(id, s.triggers)
+		delete(rs.scheduler.scripts, id)
+	}
+}
+
+// unscheduleGroupScripts stops the scheduled runs of all scripts of the group.
+func (rs *rulesService) unscheduleGroupScripts(groupID string) {
+	rs.scheduler.mu.Lock()
+	defer rs.scheduler.mu.Unlock()
+
+	for id, s := range rs.scheduler.scripts {
+		if s.groupID == groupID {
+			rs.removeSchedules(id, s.triggers)
+			delete(rs.scheduler.scripts, id)
+		}
+	}
+}
+
+func (rs *rulesService) removeSchedules(scriptID string, triggers []Trigger) {
+	for i, t := range triggers {
+		entityID := scheduleEntityID(scriptID, i)
+		switch t.Scheduler.Frequency {
+		case cron.OnceFreq:
+			rs.scheduler.manager.RemoveTimer(entityID)
+		default:
+			rs.scheduler.manager.RemoveCronEntry(entityID, t.Scheduler.TimeZone)
+		}
+	}
+}
+
+// runScheduledScript runs the latest version of the script for each thing it is assigned to,
+// on the schedule of the trigger at the given index. Every instance of the service schedules
+// the script, and the run is claimed so that only one of them runs it.
+func (rs *rulesService) runScheduledScript(scriptID string, idx int) {
+	ctx := context.Background()
+
+	script, err := rs.rules.RetrieveScriptByID(ctx, scriptID)
+	if err != nil {
+		rs.logger.Error(fmt.Sprintf("retrieving scheduled script with id %s failed with error: %v", scriptID, err))
+		return
+	}
+
+	if script.Status == ScriptDisabledStatus {
+		return
+	}
+
+	// Schedules have a one minute resolution, so the instances fire within the same minute.
+	claimed, err := rs.rules.ClaimScheduledRun(ctx, scriptID, idx, time.Now().Truncate(time.Minute))
+	if err != nil {
+		rs.logger.Error(fmt.Sprintf("claiming scheduled run of script with id %s failed with error: %v", scriptID, err))
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	thingIDs, err := rs.rules.RetrieveThingIDsByScript(ctx, scriptID, ScriptEnabledStatus)
+	if err != nil {
+		rs.logger.Error(fmt.Sprintf("retrieving things of scheduled script with id %s failed with error: %v", scriptID, err))
+		return
+	}
+
+	now := time.Now().UnixNano()
+	trigger := map[string]any{
+		"type": TriggerTypeSchedule,
+		"time": now,
+	}
+
+	for _, thingID := range thingIDs {
+		msg := protomfx.Message{Publisher: thingID, Created: now}
+		rs.processLuaScripts(ctx, &msg, map[string]any{}, trigger, script)
+	}
+}
+
+func scheduleEntityID(scriptID string, idx int) string {
+	return fmt.Sprintf("%s.%d", scriptID, idx)
+}
@@ -0,0 +1,389 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/MainfluxLabs/mainflux/pkg/domain"
+	"github.com/MainfluxLabs/mainflux/pkg/lru"
+	"github.com/MainfluxLabs/mainflux/pkg/messaging"
+	"github.com/MainfluxLabs/mainflux/pkg/predicate"
+	protomfx "github.com/MainfluxLabs/mainflux/pkg/proto"
+	"github.com/MainfluxLabs/mainflux/pkg/transformers/senml"
+)
+
+const (
+	AggregationAvg   = "avg"
+	AggregationMin   = "min"
+	AggregationMax   = "max"
+	AggregationSum   = "sum"
+	AggregationCount = "count"
+
+	// MaxWindowSize is the maximum number of samples kept in an aggregation window.
+	MaxWindowSize = 1000
+
+	// windowCacheSize is the number of aggregation windows kept by the service.
+	windowCacheSize = 10000
+	// windowTTL is the time after which an idle window is dropped. Dropped windows
+	// are rebuilt from the stored messages once they are observed again.
+	windowTTL = time.Hour
+)
+
+type Aggregation = domain.Aggregation
+
+// sample is a field value observed at the given time (unix nanoseconds).
+type sample struct {
+	value   float64
+	created int64
+}
+
+// newSample converts the field value into a sample. Count aggregations accept any value,
+// while the other aggregations require a numeric one.
+func newSample(agg Aggregation, value any, created int64) (sample, bool) {
+	v, ok := predicate.ToFloat(value)
+	if !ok && agg.Type != AggregationCount {
+		return sample{}, false
+	}
+
+	return sample{value: v, created: created}, true
+}
+
+// window is a fixed-capacity ring buffer holding the most recent samples of an aggregate condition.
+type window struct {
+	samples []sample
+	start   int
+	size    int
+}
+
+func newWindow(agg Aggregation) *window {
+	return &window{samples: make([]sample, windowSize(agg))}
+}
+
+// windowSize returns the window capacity: the window count, capped at MaxWindowSize.
+func windowSize(agg Aggregation) int {
+	if agg.Count > 0 && agg.Count < MaxWindowSize {
+		return int(agg.Count)
+	}
+	return MaxWindowSize
+}
+
+// push appends the sample, overwriting the oldest one once the window is full.
+func (w *window) push(s sample) {
+	if w.size < len(w.samples) {
+		w.samples[(w.start+w.size)%len(w.samples)] = s
+		w.size++
+		return
+	}
+
+	w.samples[w.start] = s
+	w.start = (w.start + 1) % len(w.samples)
+}
+
+// evict removes the samples created before the given time.
+func (w *window) evict(before int64) {
+	for w.size > 0 && w.samples[w.start].created < before {
+		w.start = (w.start + 1) % len(w.samples)
+		w.size--
+	}
+}
+
+// aggregate computes the aggregate of the samples in the window. It reports false
+// if the window is empty and the aggregate is undefined.
+func (w *window) aggregate(typ string) (float64, bool) {
+	if typ == AggregationCount {
+		return float64(w.size), true
+	}
+	if w.size == 0 {
+		return 0, false
+	}
+
+	result := w.samples[w.start].value
+	for i := 1; i < w.size; i++ {
+		v := w.samples[(w.start+i)%len(w.samples)].value
+		switch typ {
+		case AggregationMin:
+			if v < result {
+				result = v
+			}
+		case AggregationMax:
+			if v > result {
+				result = v
+			}
+		default:
+			result += v
+		}
+	}
+
+	if typ == AggregationAvg {
+		result /= float64(w.size)
+	}
+
+	return result, true
+}
+
+// windowKey identifies the aggregation window of the condition at position idx, kept
+// per thing and subtopic like the rule state.
+type windowKey struct {
+	ruleID   string
+	thingID  string
+	subtopic string
+	idx      int
+}
+
+// windowStore keeps the aggregation windows in memory, bounded in number and dropped once idle.
+type windowStore struct {
+	// mu guards the samples of the windows, which are updated in place.
+	mu      sync.Mutex
+	windows *lru.Cache[windowKey, *window]
+}
+
+func newWindowStore(size int, ttl time.Duration) *windowStore {
+	return &windowStore{windows: lru.New[windowKey, *window](size, ttl)}
+}
+
+func (ws *windowStore) has(key windowKey) bool {
+	_, ok := ws.windows.Get(key)
+	return ok
+}
+
+// load creates the window from previously observed samples, ordered from oldest to newest,
+// unless the window already exists.
+func (ws *windowStore) load(key windowKey, agg Aggregation, samples []sample) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if _, ok := ws.windows.Get(key); ok {
+		return
+	}
+
+	w := newWindow(agg)
+	for _, s := range samples {
+		w.push(s)
+	}
+	ws.windows.Add(key, w)
+}
+
+// observe adds the sample to the window and returns the aggregate over the window.
+func (ws *windowStore) observe(key windowKey, agg Aggregation, s sample) (float64, bool) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	w, ok := ws.windows.Get(key)
+	if !ok {
+		w = newWindow(agg)
+	}
+	// Adding the window again postpones its expiry, so that only idle windows are dropped.
+	ws.windows.Add(key, w)
+
+	w.push(s)
+	if agg.Period > 0 {
+		w.evict(s.created - int64(time.Duration(agg.Period)*time.Second))
+	}
+
+	return w.aggregate(agg.Type)
+}
+
+// remove removes all windows of the rule.
+func (ws *windowStore) remove(ruleID string) {
+	ws.windows.RemoveFunc(func(key windowKey) bool {
+		return key.ruleID == ruleID
+	})
+}
+
+// removeRuleThings removes the windows of the rule for the given things.
+func (ws *windowStore) removeRuleThings(ruleID string, thingIDs ...string) {
+	ws.windows.RemoveFunc(func(key windowKey) bool {
+		return key.ruleID == ruleID && slices.Contains(thingIDs, key.thingID)
+	})
+}
+
+// removeThing removes all windows of the thing.
+func (ws *windowStore) removeThing(thingID string) {
+	ws.windows.RemoveFunc(func(key windowKey) bool {
+		return key.thingID == thingID
+	})
+}
+
+// hasAggregation reports whether any of the rule conditions is an aggregate condition.
+func (r Rule) hasAggregation() bool {
+	found := false
+	walkConditions(r.expression(), func(_ int, c Condition) {
+		found = found || c.Aggregation != nil
+	})
+	return found
+}
+
+// walkConditions calls fn for every condition of the group, in the depth-first
+// order used to index condition state.
+func walkConditions(group ConditionGroup, fn func(idx int, c Condition)) {
+	idx := 0
+	var walk func(g ConditionGroup)
+	walk = func(g ConditionGroup) {
+		for _, c := range g.Conditions {
+			fn(idx, c)
+			idx++
+		}
+		for _, sub := range g.Groups {
+			walk(sub)
+		}
+	}
+	walk(group)
+}
+
+// loadWindows rebuilds the aggregation windows of the rule that aren't kept in memory,
+// e.g. after a service restart, from the messages stored by the readers service.
+func (rs *rulesService) loadWindows(ctx context.Context, rule Rule, msg *protomfx.Message) {
+	walkConditions(rule.expression(), func(idx int, c Condition) {
+		if c.Aggregation == nil {
+			return
+		}
+
+		key := windowKey{ruleID: rule.ID, thingID: msg.Publisher, subtopic: msg.Subtopic, idx: idx}
+		if rs.windows.has(key) {
+			return
+		}
+
+		samples, err := rs.retrieveSamples(ctx, rule, c, msg)
+		if err != nil {
+			rs.logger.Warn(fmt.Sprintf("failed to load aggregation window of rule %s: %v", rule.ID, err))
+		}
+		rs.windows.load(key, *c.Aggregation, samples)
+	})
+}
+
+// retrieveSamples retrieves the field values of the messages that precede msg
+// and fall within the condition window, ordered from oldest to newest.
+func (rs *rulesService) retrieveSamples(ctx context.Context, rule Rule, condition Condition, msg *protomfx.Message) ([]sample, error) {
+	key, err := rs.things.GetKeyByThingID(ctx, msg.Publisher)
+	if err != nil {
+		return nil, err
+	}
+
+	// msg is observed by the window once it's loaded, so it must not be part of the samples,
+	// even if it's already stored. Windows are kept per subtopic, which the rule subtopic,
+	// if any, equals.
+	bound := storedBefore(msg)
+	agg := *condition.Aggregation
+	pm := domain.MessagesPageMetadata{
+		Limit:    uint64(windowSize(agg)),
+		Subtopic: msg.Subtopic,
+		To:       bound,
+	}
+	if agg.Period > 0 {
+		pm.From = msg.Created - int64(time.Duration(agg.Period)*time.Second)
+	}
+
+	var samples []sample
+	switch msg.ContentType {
+	case messaging.SenMLContentType:
+		page, err := rs.readers.ListSenMLMessages(ctx, key, domain.SenMLPageMetadata{MessagesPageMetadata: pm, Name: condition.Field})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range page.Messages {
+			sm, ok := m.(senml.Message)
+			if !ok {
+				continue
+			}
+			if sm.Time >= bound {
+				continue
+			}
+			if s, ok := newSample(agg, senmlValue(sm), sm.Time); ok {
+				samples = append(samples, s)
+			}
+		}
+	default:
+		page, err := rs.readers.ListJSONMessages(ctx, key, domain.JSONPageMetadata{MessagesPageMetadata: pm})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range page.Messages {
+			jm, ok := m.(map[string]any)
+			if !ok {
+				continue
+			}
+			payload, ok := jm["payload"].(map[string]any)
+			if !ok {
+				continue
+			}
+			value := findPayloadParam(payload, condition.Field, messaging.JSONContentType)
+			if value == nil {
+				continue
+			}
+			created, _ := jm["created"].(int64)
+			if created >= bound {
+				continue
+			}
+			if s, ok := newSample(agg, value, created); ok {
+				samples = append(samples, s)
+			}
+		}
+	}
+
+	// Messages are retrieved newest first.
+	for i, j := 0, len(samples)-1; i < j; i, j = i+1, j-1 {
+		samples[i], samples[j] = samples[j], samples[i]
+	}
+
+	return samples, nil
+}
+
+// storedBefore returns the time before which the messages preceding msg are stored. SenML
+// records are stored with their own time, which may precede the creation time of msg.
+func storedBefore(msg *protomfx.Message) int64 {
+	bound := msg.Created
+	if msg.ContentType != messaging.SenMLContentType {
+		return bound
+	}
+
+	var records []map[string]any
+	if err := json.Unmarshal(msg.Payload, &records); err != nil {
+		return bound
+	}
+
+	var base float64
+	for _, r := range records {
+		var t int64
+		switch {
+		case r["time"] != nil:
+			tm, _ := r["time"].(float64)
+			t = int64(tm)
+		default:
+			if bt, ok := r["bt"].(float64); ok {
+				base = bt
+			}
+			tm, _ := r["t"].(float64)
+			t = int64((base + tm) * 1e9)
+		}
+
+		if t > 0 && t < bound {
+			bound = t
+		}
+	}
+
+	return bound
+}
+
+func senmlValue(msg senml.Message) any {
+	switch {
+	case msg.Value != nil:
+		return *msg.Value
+	case msg.StringValue != nil:
+		return *msg.StringValue
+	case msg.BoolValue != nil:
+		return *msg.BoolValue
+	case msg.DataValue != nil:
+		return *msg.DataValue
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/MainfluxLabs/mainflux/pkg/domain"
+	protomfx "github.com/MainfluxLabs/mainflux/pkg/proto"
+	"github.com/go-kit/kit/endpoint"
+	kitot "github.com/go-kit/kit/tracing/opentracing"
+	kitgrpc "github.com/go-kit/kit/transport/grpc"
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc"
+)
+
+var _ domain.ShadowsClient = (*grpcClient)(nil)
+
+type grpcClient struct {
+	timeout   time.Duration
+	getShadow endpoint.Endpoint
+}
+
+// NewClient returns new gRPC client instance implementing domain.ShadowsClient.
+func NewClient(conn *grpc.ClientConn, tracer opentracing.Tracer, timeout time.Duration) domain.ShadowsClient {
+	svcName := "protomfx.ShadowsService"
+
+	return &grpcClient{
+		timeout: timeout,
+		getShadow: kitot.TraceClient(tracer, "get_shadow")(kitgrpc.NewClient(
+			conn,
+			svcName,
+			"GetShadow",
+			encodeGetShadowRequest,
+			decodeGetShadowResponse,
+			protomfx.ShadowRes{},
+		).Endpoint()),
+	}
+}
+
+func (c grpcClient) ViewShadow(ctx context.Context, thingID, name string) (domain.Shadow, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	res, err := c.getShadow(ctx, getShadowReq{thingID: thingID, name: name})
+	if err != nil {
+		return domain.Shadow{}, err
+	}
+
+	sh := res.(getShadowRes).shadow
+	sh.ThingID, sh.Name = thingID, name
+	return sh, nil
+}
+
+func encodeGetShadowRequest(_ context.Context, grpcReq any) (any, error) {
+	req := grpcReq.(getShadowReq)
+	return &protomfx.ShadowReq{ThingId: req.thingID, Name: req.name}, nil
+}
+
+func decodeGetShadowResponse(_ context.Context, grpcRes any) (any, error) {
+	res := grpcRes.(*protomfx.ShadowRes)
+
+	var sh domain.Shadow
+	for _, state := range []struct {
+		data []byte
+		dest *map[string]any
+	}{
+		{res.GetDesired(), &sh.Desired},
+		{res.GetReported(), &sh.Reported},
+		{res.GetDelta(), &sh.Delta},
+	} {
+		if len(state.data) == 0 {
+			continue
+		}
+		if err := json.Unmarshal(state.data, state.dest); err != nil {
+			return nil, err
+		}
+	}
+	sh.Version = res.GetVersion()
+	sh.ReportedAt = res.GetReportedAt()
+	sh.UpdatedAt = res.GetUpdatedAt()
+
+	return getShadowRes{shadow: sh}, nil
+}
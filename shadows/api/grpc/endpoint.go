@@ -0,0 +1,35 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package grpc
+
+import (
+	"context"
+
+	"github.com/MainfluxLabs/mainflux/pkg/domain"
+	"github.com/MainfluxLabs/mainflux/shadows"
+	"github.com/go-kit/kit/endpoint"
+)
+
+func getShadowEndpoint(svc shadows.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(getShadowReq)
+		sh, err := svc.GetShadow(ctx, req.thingID, req.name)
+		if err != nil {
+			return getShadowRes{}, err
+		}
+
+		return getShadowRes{
+			shadow: domain.Shadow{
+				ThingID:    sh.ThingID,
+				Name:       sh.Name,
+				Desired:    sh.Desired,
+				Reported:   sh.Reported,
+				Delta:      sh.Delta,
+				Version:    sh.Version,
+				ReportedAt: sh.ReportedAt,
+				UpdatedAt:  sh.UpdatedAt,
+			},
+		}, nil
+	}
+}
@@ -0,0 +1,10 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package grpc
+
+import "github.com/MainfluxLabs/mainflux/pkg/domain"
+
+type getShadowRes struct {
+	shadow domain.Shadow
+}
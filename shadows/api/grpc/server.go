@@ -0,0 +1,90 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/MainfluxLabs/mainflux/pkg/dbutil"
+	"github.com/MainfluxLabs/mainflux/pkg/errors"
+	protomfx "github.com/MainfluxLabs/mainflux/pkg/proto"
+	"github.com/MainfluxLabs/mainflux/shadows"
+	kitot "github.com/go-kit/kit/tracing/opentracing"
+	kitgrpc "github.com/go-kit/kit/transport/grpc"
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ protomfx.ShadowsServiceServer = (*grpcServer)(nil)
+
+type grpcServer struct {
+	getShadow kitgrpc.Handler
+}
+
+// NewServer returns new ShadowsServiceServer instance.
+func NewServer(tracer opentracing.Tracer, svc shadows.Service) protomfx.ShadowsServiceServer {
+	return &grpcServer{
+		getShadow: kitgrpc.NewServer(
+			kitot.TraceServer(tracer, "get_shadow")(getShadowEndpoint(svc)),
+			decodeGetShadowRequest,
+			encodeGetShadowResponse,
+		),
+	}
+}
+
+func (gs *grpcServer) GetShadow(ctx context.Context, req *protomfx.ShadowReq) (*protomfx.ShadowRes, error) {
+	_, res, err := gs.getShadow.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+	return res.(*protomfx.ShadowRes), nil
+}
+
+func decodeGetShadowRequest(_ context.Context, grpcReq any) (any, error) {
+	req := grpcReq.(*protomfx.ShadowReq)
+	return getShadowReq{thingID: req.GetThingId(), name: req.GetName()}, nil
+}
+
+func encodeGetShadowResponse(_ context.Context, grpcRes any) (any, error) {
+	res := grpcRes.(getShadowRes)
+
+	desired, err := json.Marshal(res.shadow.Desired)
+	if err != nil {
+		return nil, err
+	}
+	reported, err := json.Marshal(res.shadow.Reported)
+	if err != nil {
+		return nil, err
+	}
+	delta, err := json.Marshal(res.shadow.Delta)
+	if err != nil {
+		return nil, err
+	}
+
+	return &protomfx.ShadowRes{
+		Desired:    desired,
+		Reported:   reported,
+		Delta:      delta,
+		Version:    res.shadow.Version,
+		ReportedAt: res.shadow.ReportedAt,
+		UpdatedAt:  res.shadow.UpdatedAt,
+	}, nil
+}
+
+func encodeError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	switch {
+	case err == nil:
+		return nil
+	case errors.Contains(err, dbutil.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	default:
+		return status.Error(codes.Internal, "internal server error")
+	}
+}
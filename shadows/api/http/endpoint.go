@@ -17,7 +17,7 @@ func updateDesiredStateEndpoint(svc shadows.Service) endpoint.Endpoint {
 			return nil, err
 		}
 
-		sh, err := svc.UpdateDesiredState(ctx, req.token, req.thingID, req.Desired)
+		sh, err := svc.UpdateDesiredState(ctx, req.token, req.thingID, req.name, req.Desired, req.version)
 		if err != nil {
 			return nil, err
 		}
@@ -26,6 +26,78 @@ func updateDesiredStateEndpoint(svc shadows.Service) endpoint.Endpoint {
 	}
 }
 
+func patchDesiredStateEndpoint(svc shadows.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(patchDesiredStateReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		patch := toPatch(req.merge, req.operations)
+		sh, err := svc.PatchDesiredState(ctx, req.token, req.thingID, req.name, patch, req.version)
+		if err != nil {
+			return nil, err
+		}
+
+		return buildShadowResponse(sh), nil
+	}
+}
+
+func patchGroupDesiredStateEndpoint(svc shadows.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(patchGroupDesiredStateReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		patch := toPatch(req.merge, req.operations)
+		bu, err := svc.PatchGroupDesiredState(ctx, req.token, req.groupID, req.name, patch)
+		if err != nil {
+			return nil, err
+		}
+
+		return buildBulkUpdateResponse(bu), nil
+	}
+}
+
+func patchProfileDesiredStateEndpoint(svc shadows.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(patchProfileDesiredStateReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		patch := toPatch(req.merge, req.operations)
+		bu, err := svc.PatchProfileDesiredState(ctx, req.token, req.profileID, req.name, patch)
+		if err != nil {
+			return nil, err
+		}
+
+		return buildBulkUpdateResponse(bu), nil
+	}
+}
+
+func viewGroupSyncStatusEndpoint(svc shadows.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(syncStatusReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		status, err := svc.ViewGroupSyncStatus(ctx, req.token, req.groupID, req.name)
+		if err != nil {
+			return nil, err
+		}
+
+		return syncStatusRes{
+			Total:       status.Total,
+			InSync:      status.InSync,
+			Pending:     status.Pending,
+			PendingKeys: status.PendingKeys,
+		}, nil
+	}
+}
+
 func viewShadowEndpoint(svc shadows.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request any) (any, error) {
 		req := request.(shadowReq)
@@ -33,7 +105,7 @@ func viewShadowEndpoint(svc shadows.Service) endpoint.Endpoint {
 			return nil, err
 		}
 
-		sh, err := svc.ViewShadow(ctx, req.token, req.thingID)
+		sh, err := svc.ViewShadow(ctx, req.token, req.thingID, req.name)
 		if err != nil {
 			return nil, err
 		}
@@ -42,6 +114,22 @@ func viewShadowEndpoint(svc shadows.Service) endpoint.Endpoint {
 	}
 }
 
+func listShadowHistoryEndpoint(svc shadows.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(listShadowHistoryReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		hp, err := svc.ListShadowHistory(ctx, req.token, req.thingID, req.name, req.pageMetadata)
+		if err != nil {
+			return nil, err
+		}
+
+		return buildHistoryPageResponse(hp, req.pageMetadata), nil
+	}
+}
+
 func removeShadowEndpoint(svc shadows.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request any) (any, error) {
 		req := request.(shadowReq)
@@ -49,10 +137,23 @@ func removeShadowEndpoint(svc shadows.Service) endpoint.Endpoint {
 			return nil, err
 		}
 
-		if err := svc.RemoveShadow(ctx, req.token, req.thingID); err != nil {
+		if err := svc.RemoveShadow(ctx, req.token, req.thingID, req.name); err != nil {
 			return nil, err
 		}
 
 		return removeRes{}, nil
 	}
 }
+
+func toPatch(merge shadows.State, operations []patchOperation) shadows.Patch {
+	patch := shadows.Patch{Merge: merge}
+	for _, op := range operations {
+		patch.Operations = append(patch.Operations, shadows.PatchOperation{
+			Op:    op.Op,
+			Path:  op.Path,
+			From:  op.From,
+			Value: op.Value,
+		})
+	}
+	return patch
+}
@@ -16,6 +16,8 @@ import (
 	"github.com/MainfluxLabs/mainflux/auth"
 	"github.com/MainfluxLabs/mainflux/logger"
 	"github.com/MainfluxLabs/mainflux/pkg/apiutil"
+	"github.com/MainfluxLabs/mainflux/pkg/dbutil"
+	"github.com/MainfluxLabs/mainflux/pkg/errors"
 	pkgmocks "github.com/MainfluxLabs/mainflux/pkg/mocks"
 	"github.com/MainfluxLabs/mainflux/shadows"
 	httpapi "github.com/MainfluxLabs/mainflux/shadows/api/http"
@@ -34,7 +36,9 @@ const (
 	contentType = "application/json"
 	thingID     = "5384fb1c-d0ae-4cbe-be52-c54223150fe0"
 	groupID     = "574106f7-030e-4881-8ab0-151195c29f94"
+	profileID   = "e4b1bb43-2f02-4c9e-8d79-5e0c2cbad1a3"
 	wrongID     = "wrong-id"
+	shadowName  = "firmware"
 )
 
 var (
@@ -59,6 +63,7 @@ type testRequest struct {
 	url         string
 	contentType string
 	token       string
+	ifMatch     string
 	body        io.Reader
 }
 
@@ -76,6 +81,10 @@ func (tr testRequest) make() (*http.Response, error) {
 		req.Header.Set("Content-Type", tr.contentType)
 	}
 
+	if tr.ifMatch != "" {
+		req.Header.Set("If-Match", tr.ifMatch)
+	}
+
 	return tr.client.Do(req)
 }
 
@@ -85,19 +94,69 @@ type stateRes struct {
 	Delta    shadows.State `json:"delta,omitempty"`
 }
 
+type deliveryRes struct {
+	PendingSince int64  `json:"pending_since"`
+	LastSentAt   int64  `json:"last_sent_at"`
+	Attempts     uint64 `json:"attempts"`
+	Acked        bool   `json:"acked"`
+}
+
 type shadowRes struct {
-	ThingID    string   `json:"thing_id"`
-	State      stateRes `json:"state"`
-	ReportedAt int64    `json:"reported_at"`
-	UpdatedAt  int64    `json:"updated_at"`
+	ThingID    string       `json:"thing_id"`
+	Name       string       `json:"name"`
+	State      stateRes     `json:"state"`
+	ReportedAt int64        `json:"reported_at"`
+	UpdatedAt  int64        `json:"updated_at"`
+	Version    uint64       `json:"version"`
+	Delivery   *deliveryRes `json:"delivery"`
+}
+
+type stateChangeRes struct {
+	Type    string        `json:"type"`
+	State   shadows.State `json:"state"`
+	Version uint64        `json:"version"`
+	Created int64         `json:"created"`
+}
+
+type historyPageRes struct {
+	Total   uint64           `json:"total"`
+	Offset  uint64           `json:"offset"`
+	Limit   uint64           `json:"limit"`
+	History []stateChangeRes `json:"history"`
+}
+
+type updateFailureRes struct {
+	ThingID string `json:"thing_id"`
+	Error   string `json:"error"`
+}
+
+type bulkUpdateRes struct {
+	Total   uint64             `json:"total"`
+	Updated uint64             `json:"updated"`
+	Failed  []updateFailureRes `json:"failed"`
+}
+
+type syncStatusRes struct {
+	Total       uint64            `json:"total"`
+	InSync      uint64            `json:"in_sync"`
+	Pending     uint64            `json:"pending"`
+	PendingKeys map[string]uint64 `json:"pending_keys"`
+}
+
+// namePath returns the URL path segment of the named shadow.
+func namePath(name string) string {
+	if name == shadows.DefaultName {
+		return ""
+	}
+	return "/" + name
 }
 
 func newService() shadows.Service {
 	thingsSvc := pkgmocks.NewThingsServiceClient(
-		nil,
+		map[string]things.Profile{token: {ID: profileID, GroupID: groupID}},
 		map[string]things.Thing{
 			token:   {ID: thingID, GroupID: groupID},
-			thingID: {ID: thingID, GroupID: groupID},
+			thingID: {ID: thingID, GroupID: groupID, ProfileID: profileID},
 		},
 		map[string]things.Group{token: {ID: groupID}},
 	)
@@ -105,7 +164,7 @@ func newService() shadows.Service {
 	pub := shmocks.NewCommandPublisher()
 	log := logger.NewMock()
 
-	return shadows.New(thingsSvc, repo, pub, log)
+	return shadows.New(thingsSvc, repo, pub, shadows.HistoryConfig{}, log)
 }
 
 func newHTTPServer(svc shadows.Service) *httptest.Server {
@@ -125,9 +184,12 @@ func TestUpdateDesiredState(t *testing.T) {
 		desc        string
 		body        string
 		thingID     string
+		name        string
 		contentType string
 		token       string
+		ifMatch     string
 		status      int
+		version     uint64
 	}{
 		{
 			desc:        "update desired state with valid request",
@@ -136,6 +198,73 @@ func TestUpdateDesiredState(t *testing.T) {
 			contentType: contentType,
 			token:       token,
 			status:      http.StatusOK,
+			version:     1,
+		},
+		{
+			desc:        "update desired state with matching version",
+			body:        validUpdateBody,
+			thingID:     thingID,
+			contentType: contentType,
+			token:       token,
+			ifMatch:     `"1"`,
+			status:      http.StatusOK,
+			version:     2,
+		},
+		{
+			desc:        "update desired state with stale version",
+			body:        validUpdateBody,
+			thingID:     thingID,
+			contentType: contentType,
+			token:       token,
+			ifMatch:     `"1"`,
+			status:      http.StatusConflict,
+		},
+		{
+			desc:        "update desired state with any version",
+			body:        validUpdateBody,
+			thingID:     thingID,
+			contentType: contentType,
+			token:       token,
+			ifMatch:     "*",
+			status:      http.StatusOK,
+			version:     3,
+		},
+		{
+			desc:        "update desired state with invalid version",
+			body:        validUpdateBody,
+			thingID:     thingID,
+			contentType: contentType,
+			token:       token,
+			ifMatch:     `"invalid"`,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "update desired state of named shadow",
+			body:        validUpdateBody,
+			thingID:     thingID,
+			name:        shadowName,
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusOK,
+			version:     1,
+		},
+		{
+			desc:        "update desired state of shadow with invalid name",
+			body:        validUpdateBody,
+			thingID:     thingID,
+			name:        "invalid.name",
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "update desired state of shadow with reserved name",
+			body:        validUpdateBody,
+			thingID:     thingID,
+			name:        "history",
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusBadRequest,
 		},
 		{
 			desc:        "update desired state without content type",
@@ -191,9 +320,10 @@ func TestUpdateDesiredState(t *testing.T) {
 		req := testRequest{
 			client:      ts.Client(),
 			method:      http.MethodPut,
-			url:         fmt.Sprintf("%s/things/%s/shadows", ts.URL, tc.thingID),
+			url:         fmt.Sprintf("%s/things/%s/shadows%s", ts.URL, tc.thingID, namePath(tc.name)),
 			contentType: tc.contentType,
 			token:       tc.token,
+			ifMatch:     tc.ifMatch,
 			body:        strings.NewReader(tc.body),
 		}
 		res, err := req.make()
@@ -204,8 +334,405 @@ func TestUpdateDesiredState(t *testing.T) {
 			var body shadowRes
 			json.NewDecoder(res.Body).Decode(&body)
 			assert.Equal(t, tc.thingID, body.ThingID, fmt.Sprintf("%s: expected thing ID %s got %s", tc.desc, tc.thingID, body.ThingID))
+			assert.Equal(t, tc.name, body.Name, fmt.Sprintf("%s: expected name %s got %s", tc.desc, tc.name, body.Name))
 			// Nothing has been reported yet, so the desired state is the delta.
 			assert.Equal(t, desiredState, body.State.Delta, fmt.Sprintf("%s: expected delta %v got %v", tc.desc, desiredState, body.State.Delta))
+			assert.Equal(t, tc.version, body.Version, fmt.Sprintf("%s: expected version %d got %d", tc.desc, tc.version, body.Version))
+			etag := fmt.Sprintf(`"%d"`, tc.version)
+			assert.Equal(t, etag, res.Header.Get("ETag"), fmt.Sprintf("%s: expected ETag %s got %s", tc.desc, etag, res.Header.Get("ETag")))
+			// Every desired state update sends its delta once.
+			require.NotNil(t, body.Delivery, fmt.Sprintf("%s: expected delta delivery", tc.desc))
+			assert.Equal(t, uint64(1), body.Delivery.Attempts, fmt.Sprintf("%s: expected 1 delivery attempt got %d", tc.desc, body.Delivery.Attempts))
+		}
+	}
+}
+
+func TestPatchDesiredState(t *testing.T) {
+	svc := newService()
+	ts := newHTTPServer(svc)
+	defer ts.Close()
+
+	_, err := svc.UpdateDesiredState(context.Background(), token, thingID, shadows.DefaultName, shadows.State{"led": "on", "network": map[string]any{"ssid": "home"}}, 0)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc        string
+		body        string
+		thingID     string
+		contentType string
+		token       string
+		ifMatch     string
+		status      int
+		desired     shadows.State
+	}{
+		{
+			desc:        "patch desired state with merge patch",
+			body:        `{"network": {"ssid": "office"}}`,
+			thingID:     thingID,
+			contentType: "application/merge-patch+json",
+			token:       token,
+			status:      http.StatusOK,
+			desired:     shadows.State{"led": "on", "network": map[string]any{"ssid": "office"}},
+		},
+		{
+			desc:        "patch desired state with JSON content type",
+			body:        `{"led": null}`,
+			thingID:     thingID,
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusOK,
+			desired:     shadows.State{"network": map[string]any{"ssid": "office"}},
+		},
+		{
+			desc:        "patch desired state with JSON patch",
+			body:        `[{"op": "add", "path": "/led", "value": "off"}, {"op": "remove", "path": "/network/ssid"}]`,
+			thingID:     thingID,
+			contentType: "application/json-patch+json",
+			token:       token,
+			ifMatch:     `"3"`,
+			status:      http.StatusOK,
+			desired:     shadows.State{"led": "off", "network": map[string]any{}},
+		},
+		{
+			desc:        "patch desired state with failing JSON patch test",
+			body:        `[{"op": "test", "path": "/led", "value": "on"}]`,
+			thingID:     thingID,
+			contentType: "application/json-patch+json",
+			token:       token,
+			status:      http.StatusConflict,
+		},
+		{
+			desc:        "patch desired state with invalid JSON patch path",
+			body:        `[{"op": "replace", "path": "/missing", "value": "on"}]`,
+			thingID:     thingID,
+			contentType: "application/json-patch+json",
+			token:       token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "patch desired state with stale version",
+			body:        `{"led": "on"}`,
+			thingID:     thingID,
+			contentType: "application/merge-patch+json",
+			token:       token,
+			ifMatch:     `"1"`,
+			status:      http.StatusConflict,
+		},
+		{
+			desc:        "patch desired state with empty patch",
+			body:        `{}`,
+			thingID:     thingID,
+			contentType: "application/merge-patch+json",
+			token:       token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "patch desired state with invalid JSON",
+			body:        `}{`,
+			thingID:     thingID,
+			contentType: "application/json-patch+json",
+			token:       token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "patch desired state with unsupported content type",
+			body:        `{"led": "on"}`,
+			thingID:     thingID,
+			contentType: "text/plain",
+			token:       token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+		{
+			desc:        "patch desired state with empty token",
+			body:        `{"led": "on"}`,
+			thingID:     thingID,
+			contentType: contentType,
+			token:       emptyValue,
+			status:      http.StatusUnauthorized,
+		},
+		{
+			desc:        "patch desired state with wrong thing ID",
+			body:        `{"led": "on"}`,
+			thingID:     wrongID,
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPatch,
+			url:         fmt.Sprintf("%s/things/%s/shadows", ts.URL, tc.thingID),
+			contentType: tc.contentType,
+			token:       tc.token,
+			ifMatch:     tc.ifMatch,
+			body:        strings.NewReader(tc.body),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		if tc.status == http.StatusOK {
+			var body shadowRes
+			json.NewDecoder(res.Body).Decode(&body)
+			assert.Equal(t, tc.desired, body.State.Desired, fmt.Sprintf("%s: expected desired %v got %v", tc.desc, tc.desired, body.State.Desired))
+		}
+	}
+}
+
+func TestPatchGroupDesiredState(t *testing.T) {
+	svc := newService()
+	ts := newHTTPServer(svc)
+	defer ts.Close()
+
+	_, err := svc.UpdateDesiredState(context.Background(), token, thingID, shadows.DefaultName, desiredState, 0)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc        string
+		body        string
+		groupID     string
+		name        string
+		contentType string
+		token       string
+		status      int
+		res         bulkUpdateRes
+	}{
+		{
+			desc:        "patch group desired state with merge patch",
+			body:        `{"led": "off"}`,
+			groupID:     groupID,
+			name:        shadows.DefaultName,
+			contentType: "application/merge-patch+json",
+			token:       token,
+			status:      http.StatusOK,
+			res:         bulkUpdateRes{Total: 1, Updated: 1, Failed: []updateFailureRes{}},
+		},
+		{
+			desc:        "patch group named desired state with JSON patch",
+			body:        `[{"op": "add", "path": "/version", "value": "1.2.0"}]`,
+			groupID:     groupID,
+			name:        shadowName,
+			contentType: "application/json-patch+json",
+			token:       token,
+			status:      http.StatusOK,
+			res:         bulkUpdateRes{Total: 1, Updated: 1, Failed: []updateFailureRes{}},
+		},
+		{
+			desc:        "patch group desired state with failing JSON patch test",
+			body:        `[{"op": "test", "path": "/led", "value": "on"}]`,
+			groupID:     groupID,
+			name:        shadows.DefaultName,
+			contentType: "application/json-patch+json",
+			token:       token,
+			status:      http.StatusOK,
+			res: bulkUpdateRes{
+				Total:  1,
+				Failed: []updateFailureRes{{ThingID: thingID, Error: errors.Wrap(dbutil.ErrConflict, shadows.ErrPatchTestFailed).Error()}},
+			},
+		},
+		{
+			desc:        "patch group desired state with empty patch",
+			body:        `{}`,
+			groupID:     groupID,
+			name:        shadows.DefaultName,
+			contentType: "application/merge-patch+json",
+			token:       token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "patch group desired state with invalid shadow name",
+			body:        `{"led": "on"}`,
+			groupID:     groupID,
+			name:        "invalid.name",
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "patch group desired state with unsupported content type",
+			body:        `{"led": "on"}`,
+			groupID:     groupID,
+			name:        shadows.DefaultName,
+			contentType: "text/plain",
+			token:       token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+		{
+			desc:        "patch group desired state with empty token",
+			body:        `{"led": "on"}`,
+			groupID:     groupID,
+			name:        shadows.DefaultName,
+			contentType: contentType,
+			token:       emptyValue,
+			status:      http.StatusUnauthorized,
+		},
+		{
+			desc:        "patch group desired state with wrong group ID",
+			body:        `{"led": "on"}`,
+			groupID:     wrongID,
+			name:        shadows.DefaultName,
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPatch,
+			url:         fmt.Sprintf("%s/groups/%s/shadows%s", ts.URL, tc.groupID, namePath(tc.name)),
+			contentType: tc.contentType,
+			token:       tc.token,
+			body:        strings.NewReader(tc.body),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		if tc.status == http.StatusOK {
+			var body bulkUpdateRes
+			json.NewDecoder(res.Body).Decode(&body)
+			assert.Equal(t, tc.res, body, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.res, body))
+		}
+	}
+}
+
+func TestPatchProfileDesiredState(t *testing.T) {
+	svc := newService()
+	ts := newHTTPServer(svc)
+	defer ts.Close()
+
+	cases := []struct {
+		desc      string
+		body      string
+		profileID string
+		token     string
+		status    int
+		res       bulkUpdateRes
+	}{
+		{
+			desc:      "patch profile desired state with valid token",
+			body:      `{"led": "on"}`,
+			profileID: profileID,
+			token:     token,
+			status:    http.StatusOK,
+			res:       bulkUpdateRes{Total: 1, Updated: 1, Failed: []updateFailureRes{}},
+		},
+		{
+			desc:      "patch profile desired state with empty patch",
+			body:      `{}`,
+			profileID: profileID,
+			token:     token,
+			status:    http.StatusBadRequest,
+		},
+		{
+			desc:      "patch profile desired state with empty token",
+			body:      `{"led": "on"}`,
+			profileID: profileID,
+			token:     emptyValue,
+			status:    http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPatch,
+			url:         fmt.Sprintf("%s/profiles/%s/shadows", ts.URL, tc.profileID),
+			contentType: contentType,
+			token:       tc.token,
+			body:        strings.NewReader(tc.body),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		if tc.status == http.StatusOK {
+			var body bulkUpdateRes
+			json.NewDecoder(res.Body).Decode(&body)
+			assert.Equal(t, tc.res, body, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.res, body))
+		}
+	}
+}
+
+func TestViewGroupSyncStatus(t *testing.T) {
+	svc := newService()
+	ts := newHTTPServer(svc)
+	defer ts.Close()
+
+	_, err := svc.UpdateDesiredState(context.Background(), token, thingID, shadows.DefaultName, shadows.State{"led": "on", "temp": "20"}, 0)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc    string
+		groupID string
+		name    string
+		token   string
+		status  int
+		res     syncStatusRes
+	}{
+		{
+			desc:    "view group sync status with valid token",
+			groupID: groupID,
+			name:    shadows.DefaultName,
+			token:   token,
+			status:  http.StatusOK,
+			res: syncStatusRes{
+				Total:       1,
+				Pending:     1,
+				PendingKeys: map[string]uint64{"/led": 1, "/temp": 1},
+			},
+		},
+		{
+			desc:    "view group named sync status without shadows",
+			groupID: groupID,
+			name:    shadowName,
+			token:   token,
+			status:  http.StatusOK,
+			res:     syncStatusRes{Total: 1, InSync: 1, PendingKeys: map[string]uint64{}},
+		},
+		{
+			desc:    "view group sync status with invalid shadow name",
+			groupID: groupID,
+			name:    "invalid.name",
+			token:   token,
+			status:  http.StatusBadRequest,
+		},
+		{
+			desc:    "view group sync status with empty token",
+			groupID: groupID,
+			name:    shadows.DefaultName,
+			token:   emptyValue,
+			status:  http.StatusUnauthorized,
+		},
+		{
+			desc:    "view group sync status with wrong group ID",
+			groupID: wrongID,
+			name:    shadows.DefaultName,
+			token:   token,
+			status:  http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/groups/%s/shadows%s/sync", ts.URL, tc.groupID, namePath(tc.name)),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		if tc.status == http.StatusOK {
+			var body syncStatusRes
+			json.NewDecoder(res.Body).Decode(&body)
+			assert.Equal(t, tc.res, body, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.res, body))
 		}
 	}
 }
@@ -215,21 +742,42 @@ func TestViewShadow(t *testing.T) {
 	ts := newHTTPServer(svc)
 	defer ts.Close()
 
-	_, err := svc.UpdateDesiredState(context.Background(), token, thingID, desiredState)
+	_, err := svc.UpdateDesiredState(context.Background(), token, thingID, shadows.DefaultName, desiredState, 0)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	namedState := shadows.State{"version": "1.2"}
+	_, err = svc.UpdateDesiredState(context.Background(), token, thingID, shadowName, namedState, 0)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
 		desc    string
 		token   string
 		thingID string
+		name    string
+		desired shadows.State
 		status  int
 	}{
 		{
 			desc:    "view shadow",
 			token:   token,
 			thingID: thingID,
+			desired: desiredState,
 			status:  http.StatusOK,
 		},
+		{
+			desc:    "view named shadow",
+			token:   token,
+			thingID: thingID,
+			name:    shadowName,
+			desired: namedState,
+			status:  http.StatusOK,
+		},
+		{
+			desc:    "view shadow with invalid name",
+			token:   token,
+			thingID: thingID,
+			name:    "invalid.name",
+			status:  http.StatusBadRequest,
+		},
 		{
 			desc:    "view shadow with empty token",
 			token:   emptyValue,
@@ -248,7 +796,7 @@ func TestViewShadow(t *testing.T) {
 		req := testRequest{
 			client: ts.Client(),
 			method: http.MethodGet,
-			url:    fmt.Sprintf("%s/things/%s/shadows", ts.URL, tc.thingID),
+			url:    fmt.Sprintf("%s/things/%s/shadows%s", ts.URL, tc.thingID, namePath(tc.name)),
 			token:  tc.token,
 		}
 		res, err := req.make()
@@ -259,7 +807,108 @@ func TestViewShadow(t *testing.T) {
 			var body shadowRes
 			json.NewDecoder(res.Body).Decode(&body)
 			assert.Equal(t, tc.thingID, body.ThingID, fmt.Sprintf("%s: expected thing ID %s got %s", tc.desc, tc.thingID, body.ThingID))
-			assert.Equal(t, desiredState, body.State.Desired, fmt.Sprintf("%s: expected desired %v got %v", tc.desc, desiredState, body.State.Desired))
+			assert.Equal(t, tc.name, body.Name, fmt.Sprintf("%s: expected name %s got %s", tc.desc, tc.name, body.Name))
+			assert.Equal(t, tc.desired, body.State.Desired, fmt.Sprintf("%s: expected desired %v got %v", tc.desc, tc.desired, body.State.Desired))
+		}
+	}
+}
+
+func TestListShadowHistory(t *testing.T) {
+	svc := newService()
+	ts := newHTTPServer(svc)
+	defer ts.Close()
+
+	_, err := svc.UpdateDesiredState(context.Background(), token, thingID, shadows.DefaultName, desiredState, 0)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	_, err = svc.UpdateDesiredState(context.Background(), token, thingID, shadows.DefaultName, shadows.State{"led": "off"}, 0)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc    string
+		token   string
+		thingID string
+		query   string
+		status  int
+		total   uint64
+		size    int
+	}{
+		{
+			desc:    "list shadow history",
+			token:   token,
+			thingID: thingID,
+			status:  http.StatusOK,
+			total:   2,
+			size:    2,
+		},
+		{
+			desc:    "list shadow history with limit",
+			token:   token,
+			thingID: thingID,
+			query:   "?limit=1",
+			status:  http.StatusOK,
+			total:   2,
+			size:    1,
+		},
+		{
+			desc:    "list shadow history filtered by type",
+			token:   token,
+			thingID: thingID,
+			query:   "?type=reported",
+			status:  http.StatusOK,
+			total:   0,
+			size:    0,
+		},
+		{
+			desc:    "list shadow history with invalid type",
+			token:   token,
+			thingID: thingID,
+			query:   "?type=invalid",
+			status:  http.StatusBadRequest,
+		},
+		{
+			desc:    "list shadow history with invalid direction",
+			token:   token,
+			thingID: thingID,
+			query:   "?dir=invalid",
+			status:  http.StatusBadRequest,
+		},
+		{
+			desc:    "list shadow history with limit greater than max",
+			token:   token,
+			thingID: thingID,
+			query:   "?limit=201",
+			status:  http.StatusBadRequest,
+		},
+		{
+			desc:    "list shadow history with empty token",
+			token:   emptyValue,
+			thingID: thingID,
+			status:  http.StatusUnauthorized,
+		},
+		{
+			desc:    "list shadow history with wrong thing ID",
+			token:   token,
+			thingID: wrongID,
+			status:  http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/things/%s/shadows/history%s", ts.URL, tc.thingID, tc.query),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		if tc.status == http.StatusOK {
+			var body historyPageRes
+			json.NewDecoder(res.Body).Decode(&body)
+			assert.Equal(t, tc.total, body.Total, fmt.Sprintf("%s: expected total %d got %d", tc.desc, tc.total, body.Total))
+			assert.Len(t, body.History, tc.size, fmt.Sprintf("%s: expected %d state changes got %d", tc.desc, tc.size, len(body.History)))
 		}
 	}
 }
@@ -269,7 +918,7 @@ func TestRemoveShadow(t *testing.T) {
 	ts := newHTTPServer(svc)
 	defer ts.Close()
 
-	_, err := svc.UpdateDesiredState(context.Background(), token, thingID, desiredState)
+	_, err := svc.UpdateDesiredState(context.Background(), token, thingID, shadows.DefaultName, desiredState, 0)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -8,9 +8,13 @@ import (
 	"github.com/MainfluxLabs/mainflux/shadows"
 )
 
+const maxLimitSize = 200
+
 type updateDesiredStateReq struct {
 	token   string
 	thingID string
+	name    string
+	version uint64
 	Desired shadows.State `json:"desired"`
 }
 
@@ -23,6 +27,10 @@ func (req updateDesiredStateReq) validate() error {
 		return apiutil.ErrMissingThingID
 	}
 
+	if !shadows.IsValidName(req.name) {
+		return apiutil.ErrInvalidShadowName
+	}
+
 	if len(req.Desired) == 0 {
 		return apiutil.ErrEmptyState
 	}
@@ -30,9 +38,124 @@ func (req updateDesiredStateReq) validate() error {
 	return nil
 }
 
+type patchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	From  string `json:"from,omitempty"`
+	Value any    `json:"value,omitempty"`
+}
+
+type patchDesiredStateReq struct {
+	token      string
+	thingID    string
+	name       string
+	version    uint64
+	merge      shadows.State
+	operations []patchOperation
+}
+
+func (req patchDesiredStateReq) validate() error {
+	if req.token == "" {
+		return apiutil.ErrBearerToken
+	}
+
+	if req.thingID == "" {
+		return apiutil.ErrMissingThingID
+	}
+
+	if !shadows.IsValidName(req.name) {
+		return apiutil.ErrInvalidShadowName
+	}
+
+	if len(req.merge) == 0 && len(req.operations) == 0 {
+		return apiutil.ErrEmptyState
+	}
+
+	return nil
+}
+
+type patchGroupDesiredStateReq struct {
+	token      string
+	groupID    string
+	name       string
+	merge      shadows.State
+	operations []patchOperation
+}
+
+func (req patchGroupDesiredStateReq) validate() error {
+	if req.token == "" {
+		return apiutil.ErrBearerToken
+	}
+
+	if req.groupID == "" {
+		return apiutil.ErrMissingGroupID
+	}
+
+	if !shadows.IsValidName(req.name) {
+		return apiutil.ErrInvalidShadowName
+	}
+
+	if len(req.merge) == 0 && len(req.operations) == 0 {
+		return apiutil.ErrEmptyState
+	}
+
+	return nil
+}
+
+type patchProfileDesiredStateReq struct {
+	token      string
+	profileID  string
+	name       string
+	merge      shadows.State
+	operations []patchOperation
+}
+
+func (req patchProfileDesiredStateReq) validate() error {
+	if req.token == "" {
+		return apiutil.ErrBearerToken
+	}
+
+	if req.profileID == "" {
+		return apiutil.ErrMissingProfileID
+	}
+
+	if !shadows.IsValidName(req.name) {
+		return apiutil.ErrInvalidShadowName
+	}
+
+	if len(req.merge) == 0 && len(req.operations) == 0 {
+		return apiutil.ErrEmptyState
+	}
+
+	return nil
+}
+
+type syncStatusReq struct {
+	token   string
+	groupID string
+	name    string
+}
+
+func (req syncStatusReq) validate() error {
+	if req.token == "" {
+		return apiutil.ErrBearerToken
+	}
+
+	if req.groupID == "" {
+		return apiutil.ErrMissingGroupID
+	}
+
+	if !shadows.IsValidName(req.name) {
+		return apiutil.ErrInvalidShadowName
+	}
+
+	return nil
+}
+
 type shadowReq struct {
 	token   string
 	thingID string
+	name    string
 }
 
 func (req shadowReq) validate() error {
@@ -44,5 +167,45 @@ func (req shadowReq) validate() error {
 		return apiutil.ErrMissingThingID
 	}
 
+	if !shadows.IsValidName(req.name) {
+		return apiutil.ErrInvalidShadowName
+	}
+
 	return nil
 }
+
+type listShadowHistoryReq struct {
+	token        string
+	thingID      string
+	name         string
+	pageMetadata shadows.PageMetadata
+}
+
+func (req listShadowHistoryReq) validate() error {
+	if req.token == "" {
+		return apiutil.ErrBearerToken
+	}
+
+	if req.thingID == "" {
+		return apiutil.ErrMissingThingID
+	}
+
+	if !shadows.IsValidName(req.name) {
+		return apiutil.ErrInvalidShadowName
+	}
+
+	if req.pageMetadata.Limit > maxLimitSize {
+		return apiutil.ErrLimitSize
+	}
+
+	if req.pageMetadata.Dir != "" && req.pageMetadata.Dir != apiutil.AscDir && req.pageMetadata.Dir != apiutil.DescDir {
+		return apiutil.ErrInvalidDirection
+	}
+
+	switch req.pageMetadata.Type {
+	case "", shadows.StateTypeDesired, shadows.StateTypeReported:
+		return nil
+	default:
+		return apiutil.ErrInvalidQueryParams
+	}
+}
@@ -4,7 +4,9 @@
 package http
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/MainfluxLabs/mainflux/pkg/apiutil"
 	"github.com/MainfluxLabs/mainflux/shadows"
@@ -13,6 +15,9 @@ import (
 var (
 	_ apiutil.Response = (*shadowRes)(nil)
 	_ apiutil.Response = (*removeRes)(nil)
+	_ apiutil.Response = (*historyPageRes)(nil)
+	_ apiutil.Response = (*bulkUpdateRes)(nil)
+	_ apiutil.Response = (*syncStatusRes)(nil)
 )
 
 type stateRes struct {
@@ -21,11 +26,21 @@ type stateRes struct {
 	Delta    shadows.State `json:"delta,omitempty"`
 }
 
+type deliveryRes struct {
+	PendingSince int64  `json:"pending_since"`
+	LastSentAt   int64  `json:"last_sent_at"`
+	Attempts     uint64 `json:"attempts"`
+	Acked        bool   `json:"acked"`
+}
+
 type shadowRes struct {
-	ThingID    string   `json:"thing_id"`
-	State      stateRes `json:"state"`
-	ReportedAt int64    `json:"reported_at"`
-	UpdatedAt  int64    `json:"updated_at"`
+	ThingID    string       `json:"thing_id"`
+	Name       string       `json:"name,omitempty"`
+	State      stateRes     `json:"state"`
+	ReportedAt int64        `json:"reported_at"`
+	UpdatedAt  int64        `json:"updated_at"`
+	Version    uint64       `json:"version"`
+	Delivery   *deliveryRes `json:"delivery,omitempty"`
 }
 
 func (res shadowRes) Code() int {
@@ -33,13 +48,41 @@ func (res shadowRes) Code() int {
 }
 
 func (res shadowRes) Headers() map[string]string {
-	return map[string]string{}
+	return map[string]string{
+		"ETag": fmt.Sprintf("%q", strconv.FormatUint(res.Version, 10)),
+	}
 }
 
 func (res shadowRes) Empty() bool {
 	return false
 }
 
+type stateChangeRes struct {
+	Type    string        `json:"type"`
+	State   shadows.State `json:"state"`
+	Version uint64        `json:"version"`
+	Created int64         `json:"created"`
+}
+
+type historyPageRes struct {
+	Total   uint64           `json:"total"`
+	Offset  uint64           `json:"offset"`
+	Limit   uint64           `json:"limit"`
+	History []stateChangeRes `json:"history"`
+}
+
+func (res historyPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res historyPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res historyPageRes) Empty() bool {
+	return false
+}
+
 type removeRes struct{}
 
 func (res removeRes) Code() int {
@@ -54,9 +97,52 @@ func (res removeRes) Empty() bool {
 	return true
 }
 
+type updateFailureRes struct {
+	ThingID string `json:"thing_id"`
+	Error   string `json:"error"`
+}
+
+type bulkUpdateRes struct {
+	Total   uint64             `json:"total"`
+	Updated uint64             `json:"updated"`
+	Failed  []updateFailureRes `json:"failed"`
+}
+
+func (res bulkUpdateRes) Code() int {
+	return http.StatusOK
+}
+
+func (res bulkUpdateRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res bulkUpdateRes) Empty() bool {
+	return false
+}
+
+type syncStatusRes struct {
+	Total       uint64            `json:"total"`
+	InSync      uint64            `json:"in_sync"`
+	Pending     uint64            `json:"pending"`
+	PendingKeys map[string]uint64 `json:"pending_keys"`
+}
+
+func (res syncStatusRes) Code() int {
+	return http.StatusOK
+}
+
+func (res syncStatusRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res syncStatusRes) Empty() bool {
+	return false
+}
+
 func buildShadowResponse(sh shadows.Shadow) shadowRes {
-	return shadowRes{
+	res := shadowRes{
 		ThingID: sh.ThingID,
+		Name:    sh.Name,
 		State: stateRes{
 			Desired:  sh.Desired,
 			Reported: sh.Reported,
@@ -64,5 +150,55 @@ func buildShadowResponse(sh shadows.Shadow) shadowRes {
 		},
 		ReportedAt: sh.ReportedAt,
 		UpdatedAt:  sh.UpdatedAt,
+		Version:    sh.Version,
+	}
+
+	// Only the delivery of the current, still pending, delta is exposed.
+	if d := sh.Delivery; len(sh.Delta) > 0 && d.Version == sh.Version && d.Attempts > 0 {
+		res.Delivery = &deliveryRes{
+			PendingSince: d.PendingSince,
+			LastSentAt:   d.SentAt,
+			Attempts:     d.Attempts,
+			Acked:        d.Acked,
+		}
+	}
+
+	return res
+}
+
+func buildHistoryPageResponse(hp shadows.HistoryPage, pm shadows.PageMetadata) historyPageRes {
+	res := historyPageRes{
+		Total:   hp.Total,
+		Offset:  pm.Offset,
+		Limit:   pm.Limit,
+		History: []stateChangeRes{},
+	}
+
+	for _, sc := range hp.History {
+		res.History = append(res.History, stateChangeRes{
+			Type:    sc.Type,
+			State:   sc.State,
+			Version: sc.Version,
+			Created: sc.Created,
+		})
 	}
+
+	return res
+}
+
+func buildBulkUpdateResponse(bu shadows.BulkUpdate) bulkUpdateRes {
+	res := bulkUpdateRes{
+		Total:   bu.Total,
+		Updated: bu.Updated,
+		Failed:  []updateFailureRes{},
+	}
+
+	for _, f := range bu.Failed {
+		res.Failed = append(res.Failed, updateFailureRes{
+			ThingID: f.ThingID,
+			Error:   f.Err.Error(),
+		})
+	}
+
+	return res
 }
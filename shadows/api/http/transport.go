@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/MainfluxLabs/mainflux"
@@ -24,6 +25,15 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+const (
+	nameKey       = "name"
+	typeKey       = "type"
+	ifMatchHeader = "If-Match"
+
+	jsonPatchContentType  = "application/json-patch+json"
+	mergePatchContentType = "application/merge-patch+json"
+)
+
 // MakeHandler returns a HTTP handler for API endpoints.
 func MakeHandler(tracer opentracing.Tracer, svc shadows.Service, ac domain.AuthClient, logger log.Logger) http.Handler {
 	opts := []kithttp.ServerOption{
@@ -44,6 +54,15 @@ func MakeHandler(tracer opentracing.Tracer, svc shadows.Service, ac domain.AuthC
 		encodeResponse,
 		opts...,
 	))
+	r.Patch("/things/:id/shadows", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "patch_desired_state"),
+			withIdentity,
+		)(patchDesiredStateEndpoint(svc)),
+		decodePatchDesiredState,
+		encodeResponse,
+		opts...,
+	))
 	r.Get("/things/:id/shadows", kithttp.NewServer(
 		endpoint.Chain(
 			kitot.TraceServer(tracer, "view_shadow"),
@@ -53,6 +72,15 @@ func MakeHandler(tracer opentracing.Tracer, svc shadows.Service, ac domain.AuthC
 		encodeResponse,
 		opts...,
 	))
+	r.Get("/things/:id/shadows/history", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "list_shadow_history"),
+			withIdentity,
+		)(listShadowHistoryEndpoint(svc)),
+		decodeListShadowHistory,
+		encodeResponse,
+		opts...,
+	))
 	r.Delete("/things/:id/shadows", kithttp.NewServer(
 		endpoint.Chain(
 			kitot.TraceServer(tracer, "remove_shadow"),
@@ -63,6 +91,107 @@ func MakeHandler(tracer opentracing.Tracer, svc shadows.Service, ac domain.AuthC
 		opts...,
 	))
 
+	r.Put("/things/:id/shadows/:name", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "update_named_desired_state"),
+			withIdentity,
+		)(updateDesiredStateEndpoint(svc)),
+		decodeUpdateDesiredState,
+		encodeResponse,
+		opts...,
+	))
+	r.Patch("/things/:id/shadows/:name", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "patch_named_desired_state"),
+			withIdentity,
+		)(patchDesiredStateEndpoint(svc)),
+		decodePatchDesiredState,
+		encodeResponse,
+		opts...,
+	))
+	r.Get("/things/:id/shadows/:name", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "view_named_shadow"),
+			withIdentity,
+		)(viewShadowEndpoint(svc)),
+		decodeShadowReq,
+		encodeResponse,
+		opts...,
+	))
+	r.Get("/things/:id/shadows/:name/history", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "list_named_shadow_history"),
+			withIdentity,
+		)(listShadowHistoryEndpoint(svc)),
+		decodeListShadowHistory,
+		encodeResponse,
+		opts...,
+	))
+	r.Delete("/things/:id/shadows/:name", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "remove_named_shadow"),
+			withIdentity,
+		)(removeShadowEndpoint(svc)),
+		decodeShadowReq,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Patch("/groups/:id/shadows", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "patch_group_desired_state"),
+			withIdentity,
+		)(patchGroupDesiredStateEndpoint(svc)),
+		decodePatchGroupDesiredState,
+		encodeResponse,
+		opts...,
+	))
+	r.Patch("/groups/:id/shadows/:name", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "patch_group_named_desired_state"),
+			withIdentity,
+		)(patchGroupDesiredStateEndpoint(svc)),
+		decodePatchGroupDesiredState,
+		encodeResponse,
+		opts...,
+	))
+	r.Patch("/profiles/:id/shadows", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "patch_profile_desired_state"),
+			withIdentity,
+		)(patchProfileDesiredStateEndpoint(svc)),
+		decodePatchProfileDesiredState,
+		encodeResponse,
+		opts...,
+	))
+	r.Patch("/profiles/:id/shadows/:name", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "patch_profile_named_desired_state"),
+			withIdentity,
+		)(patchProfileDesiredStateEndpoint(svc)),
+		decodePatchProfileDesiredState,
+		encodeResponse,
+		opts...,
+	))
+	r.Get("/groups/:id/shadows/sync", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "view_group_sync_status"),
+			withIdentity,
+		)(viewGroupSyncStatusEndpoint(svc)),
+		decodeSyncStatus,
+		encodeResponse,
+		opts...,
+	))
+	r.Get("/groups/:id/shadows/:name/sync", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "view_group_named_sync_status"),
+			withIdentity,
+		)(viewGroupSyncStatusEndpoint(svc)),
+		decodeSyncStatus,
+		encodeResponse,
+		opts...,
+	))
+
 	r.GetFunc("/health", mainflux.Health("shadows"))
 	r.Handle("/metrics", promhttp.Handler())
 
@@ -74,9 +203,16 @@ func decodeUpdateDesiredState(_ context.Context, r *http.Request) (any, error) {
 		return nil, apiutil.ErrUnsupportedContentType
 	}
 
+	version, err := readVersion(r)
+	if err != nil {
+		return nil, err
+	}
+
 	req := updateDesiredStateReq{
 		token:   apiutil.ExtractBearerToken(r),
 		thingID: bone.GetValue(r, apiutil.IDKey),
+		name:    bone.GetValue(r, nameKey),
+		version: version,
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
@@ -85,15 +221,147 @@ func decodeUpdateDesiredState(_ context.Context, r *http.Request) (any, error) {
 	return req, nil
 }
 
+// decodePatchDesiredState decodes a JSON Patch or, for the merge patch and
+// JSON content types, a JSON Merge Patch of the desired state.
+func decodePatchDesiredState(_ context.Context, r *http.Request) (any, error) {
+	version, err := readVersion(r)
+	if err != nil {
+		return nil, err
+	}
+
+	req := patchDesiredStateReq{
+		token:   apiutil.ExtractBearerToken(r),
+		thingID: bone.GetValue(r, apiutil.IDKey),
+		name:    bone.GetValue(r, nameKey),
+		version: version,
+	}
+	if err := decodePatch(r, &req.merge, &req.operations); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodePatchGroupDesiredState(_ context.Context, r *http.Request) (any, error) {
+	req := patchGroupDesiredStateReq{
+		token:   apiutil.ExtractBearerToken(r),
+		groupID: bone.GetValue(r, apiutil.IDKey),
+		name:    bone.GetValue(r, nameKey),
+	}
+	if err := decodePatch(r, &req.merge, &req.operations); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodePatchProfileDesiredState(_ context.Context, r *http.Request) (any, error) {
+	req := patchProfileDesiredStateReq{
+		token:     apiutil.ExtractBearerToken(r),
+		profileID: bone.GetValue(r, apiutil.IDKey),
+		name:      bone.GetValue(r, nameKey),
+	}
+	if err := decodePatch(r, &req.merge, &req.operations); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// decodePatch decodes the request body into a JSON Patch or, for the merge
+// patch and JSON content types, into a JSON Merge Patch.
+func decodePatch(r *http.Request, merge *shadows.State, operations *[]patchOperation) error {
+	var body any
+	ct := r.Header.Get("Content-Type")
+	switch {
+	case strings.Contains(ct, jsonPatchContentType):
+		body = operations
+	case strings.Contains(ct, mergePatchContentType), strings.Contains(ct, apiutil.ContentTypeJSON):
+		body = merge
+	default:
+		return apiutil.ErrUnsupportedContentType
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+		return errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	return nil
+}
+
 func decodeShadowReq(_ context.Context, r *http.Request) (any, error) {
 	req := shadowReq{
 		token:   apiutil.ExtractBearerToken(r),
 		thingID: bone.GetValue(r, apiutil.IDKey),
+		name:    bone.GetValue(r, nameKey),
 	}
 
 	return req, nil
 }
 
+func decodeSyncStatus(_ context.Context, r *http.Request) (any, error) {
+	req := syncStatusReq{
+		token:   apiutil.ExtractBearerToken(r),
+		groupID: bone.GetValue(r, apiutil.IDKey),
+		name:    bone.GetValue(r, nameKey),
+	}
+
+	return req, nil
+}
+
+func decodeListShadowHistory(_ context.Context, r *http.Request) (any, error) {
+	o, err := apiutil.ReadUintQuery(r, apiutil.OffsetKey, apiutil.DefOffset)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := apiutil.ReadLimitQuery(r, apiutil.LimitKey, apiutil.DefLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := apiutil.ReadStringQuery(r, apiutil.DirKey, apiutil.DescDir)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := apiutil.ReadStringQuery(r, typeKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	req := listShadowHistoryReq{
+		token:   apiutil.ExtractBearerToken(r),
+		thingID: bone.GetValue(r, apiutil.IDKey),
+		name:    bone.GetValue(r, nameKey),
+		pageMetadata: shadows.PageMetadata{
+			Offset: o,
+			Limit:  l,
+			Dir:    d,
+			Type:   t,
+		},
+	}
+
+	return req, nil
+}
+
+// readVersion returns the shadow version expected by the If-Match header.
+// A missing header or "*" makes the update unconditional.
+func readVersion(r *http.Request) (uint64, error) {
+	v := strings.TrimPrefix(strings.TrimSpace(r.Header.Get(ifMatchHeader)), "W/")
+	v = strings.Trim(v, `"`)
+	if v == "" || v == "*" {
+		return 0, nil
+	}
+
+	version, err := strconv.ParseUint(v, 10, 64)
+	if err != nil || version == 0 {
+		return 0, apiutil.ErrInvalidShadowVersion
+	}
+
+	return version, nil
+}
+
 func encodeResponse(_ context.Context, w http.ResponseWriter, response any) error {
 	w.Header().Set("Content-Type", apiutil.ContentTypeJSON)
 
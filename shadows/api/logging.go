@@ -29,10 +29,10 @@ func LoggingMiddleware(svc shadows.Service, logger log.Logger) shadows.Service {
 	return &loggingMiddleware{logger, svc}
 }
 
-func (lm *loggingMiddleware) UpdateDesiredState(ctx context.Context, token, thingID string, desired shadows.State) (response shadows.Shadow, err error) {
+func (lm *loggingMiddleware) UpdateDesiredState(ctx context.Context, token, thingID, name string, desired shadows.State, version uint64) (response shadows.Shadow, err error) {
 	defer func(begin time.Time) {
 		email := authn.EmailFromToken(token)
-		message := fmt.Sprintf("Method update_desired_state by user %s, thing id %s took %s to complete", email, thingID, time.Since(begin))
+		message := fmt.Sprintf("Method update_desired_state by user %s, thing id %s, shadow name %q took %s to complete", email, thingID, name, time.Since(begin))
 		if err != nil {
 			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
 			return
@@ -40,13 +40,13 @@ func (lm *loggingMiddleware) UpdateDesiredState(ctx context.Context, token, thin
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.UpdateDesiredState(ctx, token, thingID, desired)
+	return lm.svc.UpdateDesiredState(ctx, token, thingID, name, desired, version)
 }
 
-func (lm *loggingMiddleware) ViewShadow(ctx context.Context, token, thingID string) (response shadows.Shadow, err error) {
+func (lm *loggingMiddleware) PatchDesiredState(ctx context.Context, token, thingID, name string, patch shadows.Patch, version uint64) (response shadows.Shadow, err error) {
 	defer func(begin time.Time) {
 		email := authn.EmailFromToken(token)
-		message := fmt.Sprintf("Method view_shadow by user %s, thing id %s took %s to complete", email, thingID, time.Since(begin))
+		message := fmt.Sprintf("Method patch_desired_state by user %s, thing id %s, shadow name %q took %s to complete", email, thingID, name, time.Since(begin))
 		if err != nil {
 			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
 			return
@@ -54,13 +54,13 @@ func (lm *loggingMiddleware) ViewShadow(ctx context.Context, token, thingID stri
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.ViewShadow(ctx, token, thingID)
+	return lm.svc.PatchDesiredState(ctx, token, thingID, name, patch, version)
 }
 
-func (lm *loggingMiddleware) RemoveShadow(ctx context.Context, token, thingID string) (err error) {
+func (lm *loggingMiddleware) PatchGroupDesiredState(ctx context.Context, token, groupID, name string, patch shadows.Patch) (response shadows.BulkUpdate, err error) {
 	defer func(begin time.Time) {
 		email := authn.EmailFromToken(token)
-		message := fmt.Sprintf("Method remove_shadow by user %s, thing id %s took %s to complete", email, thingID, time.Since(begin))
+		message := fmt.Sprintf("Method patch_group_desired_state by user %s, group id %s, shadow name %q took %s to complete", email, groupID, name, time.Since(begin))
 		if err != nil {
 			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
 			return
@@ -68,7 +68,90 @@ func (lm *loggingMiddleware) RemoveShadow(ctx context.Context, token, thingID st
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.RemoveShadow(ctx, token, thingID)
+	return lm.svc.PatchGroupDesiredState(ctx, token, groupID, name, patch)
+}
+
+func (lm *loggingMiddleware) PatchProfileDesiredState(ctx context.Context, token, profileID, name string, patch shadows.Patch) (response shadows.BulkUpdate, err error) {
+	defer func(begin time.Time) {
+		email := authn.EmailFromToken(token)
+		message := fmt.Sprintf("Method patch_profile_desired_state by user %s, profile id %s, shadow name %q took %s to complete", email, profileID, name, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.PatchProfileDesiredState(ctx, token, profileID, name, patch)
+}
+
+func (lm *loggingMiddleware) ViewGroupSyncStatus(ctx context.Context, token, groupID, name string) (response shadows.SyncStatus, err error) {
+	defer func(begin time.Time) {
+		email := authn.EmailFromToken(token)
+		message := fmt.Sprintf("Method view_group_sync_status by user %s, group id %s, shadow name %q took %s to complete", email, groupID, name, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewGroupSyncStatus(ctx, token, groupID, name)
+}
+
+func (lm *loggingMiddleware) ViewShadow(ctx context.Context, token, thingID, name string) (response shadows.Shadow, err error) {
+	defer func(begin time.Time) {
+		email := authn.EmailFromToken(token)
+		message := fmt.Sprintf("Method view_shadow by user %s, thing id %s, shadow name %q took %s to complete", email, thingID, name, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewShadow(ctx, token, thingID, name)
+}
+
+func (lm *loggingMiddleware) ListShadowHistory(ctx context.Context, token, thingID, name string, pm shadows.PageMetadata) (response shadows.HistoryPage, err error) {
+	defer func(begin time.Time) {
+		email := authn.EmailFromToken(token)
+		message := fmt.Sprintf("Method list_shadow_history by user %s, thing id %s, shadow name %q took %s to complete", email, thingID, name, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListShadowHistory(ctx, token, thingID, name, pm)
+}
+
+func (lm *loggingMiddleware) RemoveShadow(ctx context.Context, token, thingID, name string) (err error) {
+	defer func(begin time.Time) {
+		email := authn.EmailFromToken(token)
+		message := fmt.Sprintf("Method remove_shadow by user %s, thing id %s, shadow name %q took %s to complete", email, thingID, name, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveShadow(ctx, token, thingID, name)
+}
+
+func (lm *loggingMiddleware) GetShadow(ctx context.Context, thingID, name string) (response shadows.Shadow, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method get_shadow for thing id %s, shadow name %q took %s to complete", thingID, name, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.GetShadow(ctx, thingID, name)
 }
 
 func (lm *loggingMiddleware) RemoveByThing(ctx context.Context, thingID string) (err error) {
@@ -84,6 +167,32 @@ func (lm *loggingMiddleware) RemoveByThing(ctx context.Context, thingID string)
 	return lm.svc.RemoveByThing(ctx, thingID)
 }
 
+func (lm *loggingMiddleware) ProcessHistory(ctx context.Context) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method process_history took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ProcessHistory(ctx)
+}
+
+func (lm *loggingMiddleware) RedeliverDeltas(ctx context.Context, thingID string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method redeliver_deltas for thing id %s took %s to complete", thingID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RedeliverDeltas(ctx, thingID)
+}
+
 func (lm *loggingMiddleware) ConsumeMessage(subject string, msg protomfx.Message) (err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method consume_message for thing id %s took %s to complete", msg.Publisher, time.Since(begin))
@@ -96,3 +205,16 @@ func (lm *loggingMiddleware) ConsumeMessage(subject string, msg protomfx.Message
 
 	return lm.svc.ConsumeMessage(subject, msg)
 }
+
+func (lm *loggingMiddleware) ConsumeCommand(subject string, cmd protomfx.Command) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method consume_command for thing id %s took %s to complete", cmd.RecipientID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ConsumeCommand(subject, cmd)
+}
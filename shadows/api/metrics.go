@@ -32,31 +32,85 @@ func MetricsMiddleware(svc shadows.Service, counter metrics.Counter, latency met
 	}
 }
 
-func (ms *metricsMiddleware) UpdateDesiredState(ctx context.Context, token, thingID string, desired shadows.State) (shadows.Shadow, error) {
+func (ms *metricsMiddleware) UpdateDesiredState(ctx context.Context, token, thingID, name string, desired shadows.State, version uint64) (shadows.Shadow, error) {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "update_desired_state").Add(1)
 		ms.latency.With("method", "update_desired_state").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.UpdateDesiredState(ctx, token, thingID, desired)
+	return ms.svc.UpdateDesiredState(ctx, token, thingID, name, desired, version)
 }
 
-func (ms *metricsMiddleware) ViewShadow(ctx context.Context, token, thingID string) (shadows.Shadow, error) {
+func (ms *metricsMiddleware) PatchDesiredState(ctx context.Context, token, thingID, name string, patch shadows.Patch, version uint64) (shadows.Shadow, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "patch_desired_state").Add(1)
+		ms.latency.With("method", "patch_desired_state").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.PatchDesiredState(ctx, token, thingID, name, patch, version)
+}
+
+func (ms *metricsMiddleware) PatchGroupDesiredState(ctx context.Context, token, groupID, name string, patch shadows.Patch) (shadows.BulkUpdate, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "patch_group_desired_state").Add(1)
+		ms.latency.With("method", "patch_group_desired_state").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.PatchGroupDesiredState(ctx, token, groupID, name, patch)
+}
+
+func (ms *metricsMiddleware) PatchProfileDesiredState(ctx context.Context, token, profileID, name string, patch shadows.Patch) (shadows.BulkUpdate, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "patch_profile_desired_state").Add(1)
+		ms.latency.With("method", "patch_profile_desired_state").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.PatchProfileDesiredState(ctx, token, profileID, name, patch)
+}
+
+func (ms *metricsMiddleware) ViewGroupSyncStatus(ctx context.Context, token, groupID, name string) (shadows.SyncStatus, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_group_sync_status").Add(1)
+		ms.latency.With("method", "view_group_sync_status").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewGroupSyncStatus(ctx, token, groupID, name)
+}
+
+func (ms *metricsMiddleware) ViewShadow(ctx context.Context, token, thingID, name string) (shadows.Shadow, error) {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "view_shadow").Add(1)
 		ms.latency.With("method", "view_shadow").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.ViewShadow(ctx, token, thingID)
+	return ms.svc.ViewShadow(ctx, token, thingID, name)
 }
 
-func (ms *metricsMiddleware) RemoveShadow(ctx context.Context, token, thingID string) error {
+func (ms *metricsMiddleware) ListShadowHistory(ctx context.Context, token, thingID, name string, pm shadows.PageMetadata) (shadows.HistoryPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_shadow_history").Add(1)
+		ms.latency.With("method", "list_shadow_history").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListShadowHistory(ctx, token, thingID, name, pm)
+}
+
+func (ms *metricsMiddleware) RemoveShadow(ctx context.Context, token, thingID, name string) error {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "remove_shadow").Add(1)
 		ms.latency.With("method", "remove_shadow").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.RemoveShadow(ctx, token, thingID)
+	return ms.svc.RemoveShadow(ctx, token, thingID, name)
+}
+
+func (ms *metricsMiddleware) GetShadow(ctx context.Context, thingID, name string) (shadows.Shadow, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "get_shadow").Add(1)
+		ms.latency.With("method", "get_shadow").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.GetShadow(ctx, thingID, name)
 }
 
 func (ms *metricsMiddleware) RemoveByThing(ctx context.Context, thingID string) error {
@@ -68,6 +122,24 @@ func (ms *metricsMiddleware) RemoveByThing(ctx context.Context, thingID string)
 	return ms.svc.RemoveByThing(ctx, thingID)
 }
 
+func (ms *metricsMiddleware) ProcessHistory(ctx context.Context) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "process_history").Add(1)
+		ms.latency.With("method", "process_history").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ProcessHistory(ctx)
+}
+
+func (ms *metricsMiddleware) RedeliverDeltas(ctx context.Context, thingID string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "redeliver_deltas").Add(1)
+		ms.latency.With("method", "redeliver_deltas").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RedeliverDeltas(ctx, thingID)
+}
+
 func (ms *metricsMiddleware) ConsumeMessage(subject string, msg protomfx.Message) error {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "consume_message").Add(1)
@@ -76,3 +148,12 @@ func (ms *metricsMiddleware) ConsumeMessage(subject string, msg protomfx.Message
 
 	return ms.svc.ConsumeMessage(subject, msg)
 }
+
+func (ms *metricsMiddleware) ConsumeCommand(subject string, cmd protomfx.Command) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "consume_command").Add(1)
+		ms.latency.With("method", "consume_command").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ConsumeCommand(subject, cmd)
+}
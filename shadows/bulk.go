@@ -0,0 +1,154 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package shadows
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/MainfluxLabs/mainflux/pkg/domain"
+	"github.com/MainfluxLabs/mainflux/pkg/errors"
+)
+
+// bulkConcurrency is the number of shadows updated at once by a bulk desired state update.
+const bulkConcurrency = 10
+
+// BulkUpdate reports the outcome of a desired state update of the shadows
+// of multiple things.
+type BulkUpdate struct {
+	// Total is the number of things the update was applied to.
+	Total uint64
+	// Updated is the number of shadows updated successfully, including the
+	// shadows the update didn't change.
+	Updated uint64
+	Failed  []UpdateFailure
+}
+
+// UpdateFailure represents a failed desired state update of a thing's shadow.
+type UpdateFailure struct {
+	ThingID string
+	Err     error
+}
+
+// SyncStatus aggregates the sync state of the shadows of multiple things.
+type SyncStatus struct {
+	// Total is the number of things, including things without a shadow.
+	Total uint64
+	// InSync is the number of things whose reported state matches the desired state.
+	InSync uint64
+	// Pending is the number of things with a non-empty delta.
+	Pending uint64
+	// PendingKeys maps the JSON Pointer of each pending desired leaf to the
+	// number of things it is pending for.
+	PendingKeys map[string]uint64
+}
+
+func (ss *shadowsService) PatchGroupDesiredState(ctx context.Context, token, groupID, name string, patch Patch) (BulkUpdate, error) {
+	if err := ss.things.CanUserAccessGroup(ctx, domain.UserAccessReq{Token: token, ID: groupID, Action: domain.GroupEditor}); err != nil {
+		return BulkUpdate{}, errors.Wrap(errors.ErrAuthorization, err)
+	}
+
+	thingIDs, err := ss.things.GetThingIDsByGroup(ctx, groupID)
+	if err != nil {
+		return BulkUpdate{}, err
+	}
+
+	return ss.patchDesiredStates(ctx, thingIDs, name, patch), nil
+}
+
+func (ss *shadowsService) PatchProfileDesiredState(ctx context.Context, token, profileID, name string, patch Patch) (BulkUpdate, error) {
+	if err := ss.things.CanUserAccessProfile(ctx, domain.UserAccessReq{Token: token, ID: profileID, Action: domain.GroupEditor}); err != nil {
+		return BulkUpdate{}, errors.Wrap(errors.ErrAuthorization, err)
+	}
+
+	thingIDs, err := ss.things.GetThingIDsByProfile(ctx, profileID)
+	if err != nil {
+		return BulkUpdate{}, err
+	}
+
+	return ss.patchDesiredStates(ctx, thingIDs, name, patch), nil
+}
+
+// patchDesiredStates applies the patch onto the desired state of the named
+// shadow of each thing, updating up to bulkConcurrency shadows at once.
+// A failed update doesn't stop the remaining ones.
+func (ss *shadowsService) patchDesiredStates(ctx context.Context, thingIDs []string, name string, patch Patch) BulkUpdate {
+	errs := make([]error, len(thingIDs))
+	sem := make(chan struct{}, bulkConcurrency)
+	var wg sync.WaitGroup
+
+	for i, thingID := range thingIDs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(idx int, thingID string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			_, errs[idx] = ss.patchDesiredState(ctx, thingID, name, patch, 0)
+		}(i, thingID)
+	}
+
+	wg.Wait()
+
+	bu := BulkUpdate{Total: uint64(len(thingIDs))}
+	for i, err := range errs {
+		if err != nil {
+			bu.Failed = append(bu.Failed, UpdateFailure{ThingID: thingIDs[i], Err: err})
+			continue
+		}
+		bu.Updated++
+	}
+
+	return bu
+}
+
+func (ss *shadowsService) ViewGroupSyncStatus(ctx context.Context, token, groupID, name string) (SyncStatus, error) {
+	if err := ss.things.CanUserAccessGroup(ctx, domain.UserAccessReq{Token: token, ID: groupID, Action: domain.GroupViewer}); err != nil {
+		return SyncStatus{}, errors.Wrap(errors.ErrAuthorization, err)
+	}
+
+	thingIDs, err := ss.things.GetThingIDsByGroup(ctx, groupID)
+	if err != nil {
+		return SyncStatus{}, err
+	}
+
+	shs, err := ss.shadows.RetrieveByThings(ctx, thingIDs, name)
+	if err != nil {
+		return SyncStatus{}, err
+	}
+
+	status := SyncStatus{
+		Total:       uint64(len(thingIDs)),
+		PendingKeys: map[string]uint64{},
+	}
+	for _, sh := range shs {
+		delta := computeDelta(sh.Desired, sh.Reported)
+		if len(delta) == 0 {
+			continue
+		}
+
+		status.Pending++
+		countLeaves(map[string]any(delta), "", status.PendingKeys)
+	}
+	status.InSync = status.Total - status.Pending
+
+	return status, nil
+}
+
+// countLeaves increments the count of the JSON Pointer of each leaf of v,
+// prefixed by pointer. Arrays are counted as leaves, the same as they are
+// compared as a whole.
+func countLeaves(v any, pointer string, counts map[string]uint64) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		counts[pointer]++
+		return
+	}
+
+	for k, e := range m {
+		token := strings.ReplaceAll(strings.ReplaceAll(k, "~", "~0"), "/", "~1")
+		countLeaves(e, pointer+"/"+token, counts)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package shadows
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// ackSubtopic is the subtopic token a device publishes on, after the
+	// shadow subtopic (shadow.ack or shadow.<name>.ack), to acknowledge
+	// the last delta it received.
+	ackSubtopic = "ack"
+
+	// redeliveryInterval is the delay before an unacknowledged delta is first
+	// resent on the device's telemetry. The delay doubles with every attempt,
+	// up to maxRedeliveryInterval.
+	redeliveryInterval    = 10 * time.Second
+	maxRedeliveryInterval = 10 * time.Minute
+)
+
+func (ss *shadowsService) RedeliverDeltas(ctx context.Context, thingID string) error {
+	shs, err := ss.shadows.RetrieveAllByThing(ctx, thingID)
+	if err != nil {
+		return err
+	}
+
+	for _, sh := range shs {
+		ss.deliver(ctx, sh, computeDelta(sh.Desired, sh.Reported), true)
+	}
+
+	return nil
+}
+
+// deliver publishes the shadow's delta, records the delivery attempt and
+// returns the resulting delivery. The delta of a new shadow version is
+// always sent. Otherwise, an unacknowledged delta is resent on reconnect,
+// or once the redelivery backoff has passed. An empty delta completes the
+// delivery.
+func (ss *shadowsService) deliver(ctx context.Context, sh Shadow, delta State, reconnect bool) Delivery {
+	d := sh.Delivery
+	if len(delta) == 0 {
+		if d != (Delivery{}) {
+			ss.updateDelivery(ctx, sh, Delivery{})
+		}
+		return Delivery{}
+	}
+
+	now := time.Now().Unix()
+	switch {
+	case d.Version != sh.Version:
+		d = Delivery{Version: sh.Version, PendingSince: now}
+	case d.Acked:
+		return d
+	case !reconnect && time.Duration(now-d.SentAt)*time.Second < redeliveryBackoff(d.Attempts):
+		return d
+	}
+
+	if err := ss.publish(sh.ThingID, sh.Name, delta); err != nil {
+		ss.logger.Warn(fmt.Sprintf("failed to push delta to thing %s: %s", sh.ThingID, err))
+		return sh.Delivery
+	}
+
+	d.Attempts++
+	d.SentAt = now
+	ss.updateDelivery(ctx, sh, d)
+	return d
+}
+
+// ackDelta marks the delta last sent to the device as acknowledged, which
+// stops its redelivery.
+func (ss *shadowsService) ackDelta(ctx context.Context, thingID, name string) error {
+	sh, err := ss.shadows.RetrieveByThing(ctx, thingID, name)
+	if err != nil {
+		return err
+	}
+
+	d := sh.Delivery
+	if d.Version != sh.Version || d.Attempts == 0 || d.Acked {
+		return nil
+	}
+
+	d.Acked = true
+	return ss.shadows.UpdateDelivery(ctx, thingID, name, d)
+}
+
+func (ss *shadowsService) updateDelivery(ctx context.Context, sh Shadow, d Delivery) {
+	if err := ss.shadows.UpdateDelivery(ctx, sh.ThingID, sh.Name, d); err != nil {
+		ss.logger.Warn(fmt.Sprintf("failed to update delta delivery of thing %s: %s", sh.ThingID, err))
+	}
+}
+
+// redeliveryBackoff returns the delay between the given delivery attempt and the next one.
+func redeliveryBackoff(attempts uint64) time.Duration {
+	backoff := redeliveryInterval
+	for i := uint64(1); i < attempts && backoff < maxRedeliveryInterval; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxRedeliveryInterval)
+}
+
+// ackShadowName reports whether a message published on the subtopic
+// acknowledges a delta, and returns the name of the acknowledged shadow.
+func ackShadowName(subtopic string) (string, bool) {
+	if subtopic == fmt.Sprintf("%s.%s", shadowSubtopic, ackSubtopic) {
+		return DefaultName, true
+	}
+
+	name, ok := strings.CutPrefix(subtopic, shadowSubtopic+".")
+	if !ok {
+		return "", false
+	}
+	name, ok = strings.CutSuffix(name, "."+ackSubtopic)
+	if !ok || name == DefaultName || !IsValidName(name) {
+		return "", false
+	}
+	return name, true
+}
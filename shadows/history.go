@@ -0,0 +1,54 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package shadows
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// pruneInterval is the interval at which the state changes exceeding the retention limits are removed.
+const pruneInterval = time.Hour
+
+// HistoryConfig contains the retention limits of the shadow history.
+type HistoryConfig struct {
+	// MaxAge is the age after which state changes are removed. Zero keeps them.
+	MaxAge time.Duration
+	// MaxCount is the number of the latest state changes kept per shadow. Zero keeps all of them.
+	MaxCount uint64
+}
+
+func (ss *shadowsService) ProcessHistory(ctx context.Context) error {
+	ss.pruneHistory(ctx)
+
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ss.pruneHistory(ctx)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// pruneHistory removes the state changes older than the configured max age, and all but
+// the configured number of the latest state changes of each shadow.
+func (ss *shadowsService) pruneHistory(ctx context.Context) {
+	if ss.history.MaxAge == 0 && ss.history.MaxCount == 0 {
+		return
+	}
+
+	var before time.Time
+	if ss.history.MaxAge > 0 {
+		before = time.Now().Add(-ss.history.MaxAge)
+	}
+
+	if err := ss.shadows.PruneHistory(ctx, before, ss.history.MaxCount); err != nil {
+		ss.logger.Error(fmt.Sprintf("failed to remove shadow history exceeding the retention limits: %s", err))
+	}
+}
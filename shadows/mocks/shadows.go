@@ -5,57 +5,95 @@ package mocks
 
 import (
 	"context"
+	"slices"
 	"sync"
+	"time"
 
+	"github.com/MainfluxLabs/mainflux/pkg/dbutil"
+	"github.com/MainfluxLabs/mainflux/pkg/errors"
 	"github.com/MainfluxLabs/mainflux/shadows"
 )
 
 var _ shadows.ShadowRepository = (*shadowRepositoryMock)(nil)
 
+type shadowKey struct {
+	thingID string
+	name    string
+}
+
 type shadowRepositoryMock struct {
 	mu      sync.Mutex
-	shadows map[string]shadows.Shadow
+	shadows map[shadowKey]shadows.Shadow
+	history map[shadowKey][]shadows.StateChange
 }
 
 // NewShadowRepository creates an in-memory shadow repository.
 func NewShadowRepository() shadows.ShadowRepository {
 	return &shadowRepositoryMock{
-		shadows: make(map[string]shadows.Shadow),
+		shadows: make(map[shadowKey]shadows.Shadow),
+		history: make(map[shadowKey][]shadows.StateChange),
 	}
 }
 
-func (srm *shadowRepositoryMock) UpsertDesiredState(_ context.Context, thingID string, desired shadows.State, updatedAt int64) (shadows.Shadow, error) {
+func (srm *shadowRepositoryMock) UpsertDesiredState(_ context.Context, thingID, name string, desired shadows.State, updatedAt int64, version uint64) (shadows.Shadow, error) {
 	srm.mu.Lock()
 	defer srm.mu.Unlock()
 
-	sh := srm.shadows[thingID]
+	key := shadowKey{thingID: thingID, name: name}
+	sh, ok := srm.shadows[key]
+	if version > 0 && (!ok || sh.Version != version) {
+		return shadows.Shadow{}, errors.Wrap(dbutil.ErrConflict, shadows.ErrVersionConflict)
+	}
+
 	sh.ThingID = thingID
+	sh.Name = name
 	sh.Desired = desired
 	sh.UpdatedAt = updatedAt
-	srm.shadows[thingID] = sh
+	sh.Version++
+	srm.shadows[key] = sh
+	srm.record(key, shadows.StateTypeDesired, desired, sh.Version, updatedAt)
 	return sh, nil
 }
 
-func (srm *shadowRepositoryMock) UpsertReportedState(_ context.Context, thingID string, reported shadows.State, reportedAt int64) error {
+func (srm *shadowRepositoryMock) UpsertReportedState(_ context.Context, thingID, name string, reported shadows.State, reportedAt int64) error {
 	srm.mu.Lock()
 	defer srm.mu.Unlock()
 
-	sh := srm.shadows[thingID]
+	key := shadowKey{thingID: thingID, name: name}
+	sh := srm.shadows[key]
 	sh.ThingID = thingID
+	sh.Name = name
 	sh.Reported = reported
 	sh.ReportedAt = reportedAt
-	srm.shadows[thingID] = sh
+	srm.shadows[key] = sh
+	srm.record(key, shadows.StateTypeReported, reported, sh.Version, reportedAt)
+	return nil
+}
+
+func (srm *shadowRepositoryMock) UpdateDelivery(_ context.Context, thingID, name string, delivery shadows.Delivery) error {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	key := shadowKey{thingID: thingID, name: name}
+	sh, ok := srm.shadows[key]
+	if !ok {
+		return nil
+	}
+
+	sh.Delivery = delivery
+	srm.shadows[key] = sh
 	return nil
 }
 
-func (srm *shadowRepositoryMock) RetrieveByThing(_ context.Context, thingID string) (shadows.Shadow, error) {
+func (srm *shadowRepositoryMock) RetrieveByThing(_ context.Context, thingID, name string) (shadows.Shadow, error) {
 	srm.mu.Lock()
 	defer srm.mu.Unlock()
 
-	sh, ok := srm.shadows[thingID]
+	sh, ok := srm.shadows[shadowKey{thingID: thingID, name: name}]
 	if !ok {
 		return shadows.Shadow{
 			ThingID:  thingID,
+			Name:     name,
 			Desired:  shadows.State{},
 			Reported: shadows.State{},
 		}, nil
@@ -64,10 +102,118 @@ func (srm *shadowRepositoryMock) RetrieveByThing(_ context.Context, thingID stri
 	return sh, nil
 }
 
-func (srm *shadowRepositoryMock) Remove(_ context.Context, thingID string) error {
+func (srm *shadowRepositoryMock) RetrieveByThings(_ context.Context, thingIDs []string, name string) ([]shadows.Shadow, error) {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	var shs []shadows.Shadow
+	for _, thingID := range thingIDs {
+		if sh, ok := srm.shadows[shadowKey{thingID: thingID, name: name}]; ok {
+			shs = append(shs, sh)
+		}
+	}
+
+	return shs, nil
+}
+
+func (srm *shadowRepositoryMock) RetrieveAllByThing(_ context.Context, thingID string) ([]shadows.Shadow, error) {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	var shs []shadows.Shadow
+	for key, sh := range srm.shadows {
+		if key.thingID == thingID {
+			shs = append(shs, sh)
+		}
+	}
+
+	return shs, nil
+}
+
+func (srm *shadowRepositoryMock) RetrieveHistory(_ context.Context, thingID, name string, pm shadows.PageMetadata) (shadows.HistoryPage, error) {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	var items []shadows.StateChange
+	for _, sc := range srm.history[shadowKey{thingID: thingID, name: name}] {
+		if pm.Type == "" || sc.Type == pm.Type {
+			items = append(items, sc)
+		}
+	}
+
+	// History is kept in ascending order, but is listed in descending order by default.
+	if pm.Dir != "asc" {
+		slices.Reverse(items)
+	}
+
+	total := uint64(len(items))
+	if pm.Offset >= total {
+		return shadows.HistoryPage{Total: total}, nil
+	}
+	end := total
+	if pm.Limit > 0 && pm.Offset+pm.Limit < total {
+		end = pm.Offset + pm.Limit
+	}
+
+	return shadows.HistoryPage{
+		Total:   total,
+		History: items[pm.Offset:end],
+	}, nil
+}
+
+func (srm *shadowRepositoryMock) Remove(_ context.Context, thingID, name string) error {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	key := shadowKey{thingID: thingID, name: name}
+	delete(srm.shadows, key)
+	delete(srm.history, key)
+	return nil
+}
+
+func (srm *shadowRepositoryMock) RemoveByThing(_ context.Context, thingID string) error {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	for key := range srm.shadows {
+		if key.thingID == thingID {
+			delete(srm.shadows, key)
+		}
+	}
+	for key := range srm.history {
+		if key.thingID == thingID {
+			delete(srm.history, key)
+		}
+	}
+	return nil
+}
+
+func (srm *shadowRepositoryMock) PruneHistory(_ context.Context, before time.Time, keep uint64) error {
 	srm.mu.Lock()
 	defer srm.mu.Unlock()
 
-	delete(srm.shadows, thingID)
+	for key, history := range srm.history {
+		if !before.IsZero() {
+			history = slices.DeleteFunc(history, func(sc shadows.StateChange) bool {
+				return sc.Created < before.Unix()
+			})
+		}
+		// History is kept in ascending order, so the latest state changes are at its end.
+		if keep > 0 && uint64(len(history)) > keep {
+			history = history[uint64(len(history))-keep:]
+		}
+		srm.history[key] = history
+	}
 	return nil
 }
+
+func (srm *shadowRepositoryMock) record(key shadowKey, typ string, state shadows.State, version uint64, created int64) {
+	srm.history[key] = append(srm.history[key], shadows.StateChange{
+		ThingID: key.thingID,
+		Name:    key.name,
+		Type:    typ,
+		State:   state,
+		Version: version,
+		Created: created,
+	})
+}
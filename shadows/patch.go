@@ -0,0 +1,344 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package shadows
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/MainfluxLabs/mainflux/pkg/dbutil"
+	"github.com/MainfluxLabs/mainflux/pkg/errors"
+)
+
+const (
+	PatchOpAdd     = "add"
+	PatchOpRemove  = "remove"
+	PatchOpReplace = "replace"
+	PatchOpMove    = "move"
+	PatchOpCopy    = "copy"
+	PatchOpTest    = "test"
+)
+
+var (
+	// ErrInvalidPatch indicates a malformed patch operation, or an operation
+	// referencing a location that doesn't exist in the state.
+	ErrInvalidPatch = errors.New("invalid shadow state patch")
+
+	// ErrPatchTestFailed indicates a patch test operation whose value doesn't
+	// match the state.
+	ErrPatchTestFailed = errors.New("shadow state patch test failed")
+)
+
+// Patch represents a change of the desired state. Exactly one of Merge and
+// Operations is expected to be set.
+type Patch struct {
+	// Merge is an RFC 7386 (JSON Merge Patch) patch, merged deeply into the state.
+	Merge State
+	// Operations is an RFC 6902 (JSON Patch) patch, applied in order.
+	Operations []PatchOperation
+}
+
+// PatchOperation is a single RFC 6902 (JSON Patch) operation. Path and From
+// are RFC 6901 JSON Pointers into the state.
+type PatchOperation struct {
+	Op    string
+	Path  string
+	From  string
+	Value any
+}
+
+// apply applies the patch onto base and reports whether anything changed.
+// base is not mutated; the patched copy is returned.
+func (p Patch) apply(base State) (State, bool, error) {
+	if p.Operations == nil {
+		merged, changed := mergeState(base, p.Merge)
+		return merged, changed, nil
+	}
+
+	patched, err := applyOperations(base, p.Operations)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return patched, !equalState(map[string]any(base), map[string]any(patched)), nil
+}
+
+// applyOperations applies JSON Patch operations onto a copy of base. Since a
+// nil value deletes a key in merge patches, keys that end up holding a nil
+// value are removed from the result as well.
+func applyOperations(base State, ops []PatchOperation) (State, error) {
+	var doc any = copyValue(map[string]any(base))
+	if doc.(map[string]any) == nil {
+		doc = map[string]any{}
+	}
+
+	for _, op := range ops {
+		var err error
+		if doc, err = applyOperation(doc, op); err != nil {
+			return nil, err
+		}
+	}
+
+	root, ok := doc.(map[string]any)
+	if !ok {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, ErrInvalidPatch)
+	}
+
+	return State(pruneNil(root).(map[string]any)), nil
+}
+
+func applyOperation(doc any, op PatchOperation) (any, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case PatchOpAdd:
+		return addValue(doc, path, copyValue(op.Value))
+	case PatchOpRemove:
+		doc, _, err := removeValue(doc, path)
+		return doc, err
+	case PatchOpReplace:
+		if len(path) == 0 {
+			return copyValue(op.Value), nil
+		}
+		if _, err := getValue(doc, path); err != nil {
+			return nil, err
+		}
+		if doc, _, err = removeValue(doc, path); err != nil {
+			return nil, err
+		}
+		return addValue(doc, path, copyValue(op.Value))
+	case PatchOpMove:
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		// A location can't be moved into one of its children.
+		if len(path) > len(from) && isPrefix(from, path) {
+			return nil, errors.Wrap(errors.ErrMalformedEntity, ErrInvalidPatch)
+		}
+		doc, v, err := removeValue(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(doc, path, v)
+	case PatchOpCopy:
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		v, err := getValue(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(doc, path, copyValue(v))
+	case PatchOpTest:
+		v, err := getValue(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !equalState(v, op.Value) {
+			return nil, errors.Wrap(dbutil.ErrConflict, ErrPatchTestFailed)
+		}
+		return doc, nil
+	default:
+		return nil, errors.Wrap(errors.ErrMalformedEntity, ErrInvalidPatch)
+	}
+}
+
+// parsePointer splits an RFC 6901 JSON Pointer into its unescaped reference tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, ErrInvalidPatch)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func isPrefix(prefix, path []string) bool {
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func getValue(doc any, path []string) (any, error) {
+	for _, token := range path {
+		switch n := doc.(type) {
+		case map[string]any:
+			v, ok := n[token]
+			if !ok {
+				return nil, errors.Wrap(errors.ErrMalformedEntity, ErrInvalidPatch)
+			}
+			doc = v
+		case []any:
+			i, err := arrayIndex(token, len(n)-1)
+			if err != nil {
+				return nil, err
+			}
+			doc = n[i]
+		default:
+			return nil, errors.Wrap(errors.ErrMalformedEntity, ErrInvalidPatch)
+		}
+	}
+	return doc, nil
+}
+
+// addValue adds value at path and returns the updated document. The parent
+// of the target location must exist. An array element is inserted before the
+// element at the index, or appended if the last token is "-".
+func addValue(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	return updateParent(doc, path, func(parent any, token string) (any, error) {
+		switch n := parent.(type) {
+		case map[string]any:
+			n[token] = value
+			return n, nil
+		case []any:
+			i := len(n)
+			if token != "-" {
+				var err error
+				if i, err = arrayIndex(token, len(n)); err != nil {
+					return nil, err
+				}
+			}
+			return append(n[:i], append([]any{value}, n[i:]...)...), nil
+		default:
+			return nil, errors.Wrap(errors.ErrMalformedEntity, ErrInvalidPatch)
+		}
+	})
+}
+
+// removeValue removes the value at path and returns the updated document
+// along with the removed value.
+func removeValue(doc any, path []string) (any, any, error) {
+	if len(path) == 0 {
+		return nil, nil, errors.Wrap(errors.ErrMalformedEntity, ErrInvalidPatch)
+	}
+
+	var removed any
+	doc, err := updateParent(doc, path, func(parent any, token string) (any, error) {
+		switch n := parent.(type) {
+		case map[string]any:
+			v, ok := n[token]
+			if !ok {
+				return nil, errors.Wrap(errors.ErrMalformedEntity, ErrInvalidPatch)
+			}
+			removed = v
+			delete(n, token)
+			return n, nil
+		case []any:
+			i, err := arrayIndex(token, len(n)-1)
+			if err != nil {
+				return nil, err
+			}
+			removed = n[i]
+			return append(n[:i], n[i+1:]...), nil
+		default:
+			return nil, errors.Wrap(errors.ErrMalformedEntity, ErrInvalidPatch)
+		}
+	})
+
+	return doc, removed, err
+}
+
+// updateParent walks doc down to the parent of the location at path, replaces
+// the parent with the result of fn, and returns the updated document. Arrays
+// may be reallocated by fn, so every container on the way is reassigned.
+func updateParent(doc any, path []string, fn func(parent any, token string) (any, error)) (any, error) {
+	if len(path) == 1 {
+		return fn(doc, path[0])
+	}
+
+	child, err := getValue(doc, path[:1])
+	if err != nil {
+		return nil, err
+	}
+
+	child, err = updateParent(child, path[1:], fn)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n := doc.(type) {
+	case map[string]any:
+		n[path[0]] = child
+	case []any:
+		i, _ := arrayIndex(path[0], len(n)-1)
+		n[i] = child
+	}
+	return doc, nil
+}
+
+// arrayIndex parses an array index token, which must not exceed max.
+func arrayIndex(token string, max int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, errors.Wrap(errors.ErrMalformedEntity, ErrInvalidPatch)
+	}
+
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i > max {
+		return 0, errors.Wrap(errors.ErrMalformedEntity, ErrInvalidPatch)
+	}
+	return i, nil
+}
+
+// copyValue returns a deep copy of a JSON-shaped value.
+func copyValue(v any) any {
+	switch n := v.(type) {
+	case map[string]any:
+		if n == nil {
+			return n
+		}
+		c := make(map[string]any, len(n))
+		for k, e := range n {
+			c[k] = copyValue(e)
+		}
+		return c
+	case []any:
+		c := make([]any, len(n))
+		for i, e := range n {
+			c[i] = copyValue(e)
+		}
+		return c
+	default:
+		return v
+	}
+}
+
+// pruneNil removes the object keys holding a nil value.
+func pruneNil(v any) any {
+	switch n := v.(type) {
+	case map[string]any:
+		for k, e := range n {
+			if e == nil {
+				delete(n, k)
+				continue
+			}
+			n[k] = pruneNil(e)
+		}
+		return n
+	case []any:
+		for i, e := range n {
+			n[i] = pruneNil(e)
+		}
+		return n
+	default:
+		return v
+	}
+}
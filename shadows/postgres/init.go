@@ -50,6 +50,72 @@ func migrateDB(db *sqlx.DB) error {
 				},
 				Down: []string{"DROP TABLE shadows"},
 			},
+			{
+				Id: "shadows_2",
+				Up: []string{
+					`ALTER TABLE IF EXISTS shadows ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 0`,
+					`UPDATE shadows SET version = 1 WHERE desired <> '{}'`,
+					`CREATE TABLE IF NOT EXISTS shadow_history (
+						id       BIGSERIAL PRIMARY KEY,
+						thing_id UUID        NOT NULL,
+						type     VARCHAR(10) NOT NULL CHECK (type IN ('desired', 'reported')),
+						state    JSONB       NOT NULL,
+						version  BIGINT      NOT NULL,
+						created  BIGINT      NOT NULL
+					)`,
+					`CREATE INDEX IF NOT EXISTS shadow_history_thing_id_idx ON shadow_history (thing_id, id)`,
+				},
+				Down: []string{
+					"DROP TABLE shadow_history",
+					"ALTER TABLE shadows DROP COLUMN version",
+				},
+			},
+			{
+				Id: "shadows_3",
+				Up: []string{
+					`ALTER TABLE IF EXISTS shadows ADD COLUMN IF NOT EXISTS name VARCHAR(64) NOT NULL DEFAULT ''`,
+					`ALTER TABLE IF EXISTS shadows DROP CONSTRAINT IF EXISTS shadows_pkey`,
+					`ALTER TABLE IF EXISTS shadows ADD PRIMARY KEY (thing_id, name)`,
+					`ALTER TABLE IF EXISTS shadow_history ADD COLUMN IF NOT EXISTS name VARCHAR(64) NOT NULL DEFAULT ''`,
+					`DROP INDEX IF EXISTS shadow_history_thing_id_idx`,
+					`CREATE INDEX IF NOT EXISTS shadow_history_thing_id_name_idx ON shadow_history (thing_id, name, id)`,
+				},
+				Down: []string{
+					"DROP INDEX shadow_history_thing_id_name_idx",
+					"ALTER TABLE shadow_history DROP COLUMN name",
+					"DELETE FROM shadows WHERE name <> ''",
+					"ALTER TABLE shadows DROP CONSTRAINT shadows_pkey",
+					"ALTER TABLE shadows ADD PRIMARY KEY (thing_id)",
+					"ALTER TABLE shadows DROP COLUMN name",
+					"CREATE INDEX shadow_history_thing_id_idx ON shadow_history (thing_id, id)",
+				},
+			},
+			{
+				Id: "shadows_4",
+				Up: []string{
+					`ALTER TABLE IF EXISTS shadows ADD COLUMN IF NOT EXISTS delivery_version       BIGINT  NOT NULL DEFAULT 0`,
+					`ALTER TABLE IF EXISTS shadows ADD COLUMN IF NOT EXISTS delivery_pending_since BIGINT  NOT NULL DEFAULT 0`,
+					`ALTER TABLE IF EXISTS shadows ADD COLUMN IF NOT EXISTS delivery_sent_at       BIGINT  NOT NULL DEFAULT 0`,
+					`ALTER TABLE IF EXISTS shadows ADD COLUMN IF NOT EXISTS delivery_attempts      BIGINT  NOT NULL DEFAULT 0`,
+					`ALTER TABLE IF EXISTS shadows ADD COLUMN IF NOT EXISTS delivery_acked         BOOLEAN NOT NULL DEFAULT FALSE`,
+				},
+				Down: []string{
+					"ALTER TABLE shadows DROP COLUMN delivery_version",
+					"ALTER TABLE shadows DROP COLUMN delivery_pending_since",
+					"ALTER TABLE shadows DROP COLUMN delivery_sent_at",
+					"ALTER TABLE shadows DROP COLUMN delivery_attempts",
+					"ALTER TABLE shadows DROP COLUMN delivery_acked",
+				},
+			},
+			{
+				Id: "shadows_5",
+				Up: []string{
+					`CREATE INDEX IF NOT EXISTS shadow_history_created_idx ON shadow_history (created)`,
+				},
+				Down: []string{
+					"DROP INDEX shadow_history_created_idx",
+				},
+			},
 		},
 	}
 	_, err := migrate.Exec(db.DB, "postgres", migrations, migrate.Up)
@@ -4,10 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"fmt"
+	"time"
 
 	"github.com/MainfluxLabs/mainflux/pkg/dbutil"
 	"github.com/MainfluxLabs/mainflux/pkg/errors"
 	"github.com/MainfluxLabs/mainflux/shadows"
+	"github.com/gofrs/uuid"
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5/pgconn"
 	_ "github.com/jackc/pgx/v5/stdlib"
@@ -15,6 +18,9 @@ import (
 
 var _ shadows.ShadowRepository = (*shadowRepository)(nil)
 
+const shadowColumns = `thing_id, name, desired, reported, reported_at, updated_at, version,
+	delivery_version, delivery_pending_since, delivery_sent_at, delivery_attempts, delivery_acked`
+
 type shadowRepository struct {
 	db dbutil.Database
 }
@@ -26,23 +32,40 @@ func NewShadowRepository(db dbutil.Database) shadows.ShadowRepository {
 	}
 }
 
-func (sr shadowRepository) UpsertDesiredState(ctx context.Context, thingID string, desired shadows.State, updatedAt int64) (shadows.Shadow, error) {
+func (sr shadowRepository) UpsertDesiredState(ctx context.Context, thingID, name string, desired shadows.State, updatedAt int64, version uint64) (shadows.Shadow, error) {
 	desiredB, err := marshalState(desired)
 	if err != nil {
 		return shadows.Shadow{}, errors.Wrap(dbutil.ErrMalformedEntity, err)
 	}
 
-	q := `INSERT INTO shadows (thing_id, desired, updated_at)
-	      VALUES (:thing_id, :desired, :updated_at)
-	      ON CONFLICT (thing_id) DO UPDATE SET
-	          desired    = EXCLUDED.desired,
-	          updated_at = EXCLUDED.updated_at
-	      RETURNING thing_id, desired, reported, reported_at, updated_at;`
+	// A conditional update only matches the shadow having the expected version,
+	// while an unconditional one creates the shadow if it doesn't exist.
+	uq := `INSERT INTO shadows (thing_id, name, desired, updated_at, version)
+	       VALUES (:thing_id, :name, :desired, :updated_at, 1)
+	       ON CONFLICT (thing_id, name) DO UPDATE SET
+	           desired    = EXCLUDED.desired,
+	           updated_at = EXCLUDED.updated_at,
+	           version    = shadows.version + 1`
+	if version > 0 {
+		uq = `UPDATE shadows SET desired = :desired, updated_at = :updated_at, version = version + 1
+		      WHERE thing_id = :thing_id AND name = :name AND version = :version`
+	}
+
+	q := fmt.Sprintf(`WITH s AS (
+	          %s
+	          RETURNING %s
+	      ), h AS (
+	          INSERT INTO shadow_history (thing_id, name, type, state, version, created)
+	          SELECT thing_id, name, '%s', desired, version, updated_at FROM s
+	      )
+	      SELECT %s FROM s;`, uq, shadowColumns, shadows.StateTypeDesired, shadowColumns)
 
 	row, err := sr.db.NamedQueryContext(ctx, q, dbShadow{
 		ThingID:   thingID,
+		Name:      name,
 		Desired:   desiredB,
 		UpdatedAt: updatedAt,
+		Version:   version,
 	})
 	if err != nil {
 		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == pgerrcode.InvalidTextRepresentation {
@@ -52,7 +75,13 @@ func (sr shadowRepository) UpsertDesiredState(ctx context.Context, thingID strin
 	}
 	defer row.Close()
 
-	row.Next()
+	if !row.Next() {
+		if err := row.Err(); err != nil {
+			return shadows.Shadow{}, errors.Wrap(dbutil.ErrCreateEntity, err)
+		}
+		return shadows.Shadow{}, errors.Wrap(dbutil.ErrConflict, shadows.ErrVersionConflict)
+	}
+
 	dbSh := dbShadow{}
 	if err := row.StructScan(&dbSh); err != nil {
 		return shadows.Shadow{}, errors.Wrap(dbutil.ErrCreateEntity, err)
@@ -61,20 +90,26 @@ func (sr shadowRepository) UpsertDesiredState(ctx context.Context, thingID strin
 	return toShadow(dbSh)
 }
 
-func (sr shadowRepository) UpsertReportedState(ctx context.Context, thingID string, reported shadows.State, reportedAt int64) error {
+func (sr shadowRepository) UpsertReportedState(ctx context.Context, thingID, name string, reported shadows.State, reportedAt int64) error {
 	reportedB, err := marshalState(reported)
 	if err != nil {
 		return errors.Wrap(dbutil.ErrMalformedEntity, err)
 	}
 
-	q := `INSERT INTO shadows (thing_id, reported, reported_at)
-	      VALUES (:thing_id, :reported, :reported_at)
-	      ON CONFLICT (thing_id) DO UPDATE SET
-	          reported    = EXCLUDED.reported,
-	          reported_at = EXCLUDED.reported_at;`
+	q := fmt.Sprintf(`WITH s AS (
+	          INSERT INTO shadows (thing_id, name, reported, reported_at)
+	          VALUES (:thing_id, :name, :reported, :reported_at)
+	          ON CONFLICT (thing_id, name) DO UPDATE SET
+	              reported    = EXCLUDED.reported,
+	              reported_at = EXCLUDED.reported_at
+	          RETURNING thing_id, name, reported, reported_at, version
+	      )
+	      INSERT INTO shadow_history (thing_id, name, type, state, version, created)
+	      SELECT thing_id, name, '%s', reported, version, reported_at FROM s;`, shadows.StateTypeReported)
 
 	if _, err := sr.db.NamedExecContext(ctx, q, dbShadow{
 		ThingID:    thingID,
+		Name:       name,
 		Reported:   reportedB,
 		ReportedAt: reportedAt,
 	}); err != nil {
@@ -86,18 +121,37 @@ func (sr shadowRepository) UpsertReportedState(ctx context.Context, thingID stri
 	return nil
 }
 
-func (sr shadowRepository) RetrieveByThing(ctx context.Context, thingID string) (shadows.Shadow, error) {
-	q := `SELECT thing_id, desired, reported, reported_at, updated_at
-	      FROM shadows WHERE thing_id = $1;`
+func (sr shadowRepository) UpdateDelivery(ctx context.Context, thingID, name string, delivery shadows.Delivery) error {
+	q := `UPDATE shadows SET delivery_version = :delivery_version, delivery_pending_since = :delivery_pending_since,
+	          delivery_sent_at = :delivery_sent_at, delivery_attempts = :delivery_attempts, delivery_acked = :delivery_acked
+	      WHERE thing_id = :thing_id AND name = :name;`
+
+	if _, err := sr.db.NamedExecContext(ctx, q, dbShadow{
+		ThingID:              thingID,
+		Name:                 name,
+		DeliveryVersion:      delivery.Version,
+		DeliveryPendingSince: delivery.PendingSince,
+		DeliverySentAt:       delivery.SentAt,
+		DeliveryAttempts:     delivery.Attempts,
+		DeliveryAcked:        delivery.Acked,
+	}); err != nil {
+		return errors.Wrap(dbutil.ErrUpdateEntity, err)
+	}
+	return nil
+}
+
+func (sr shadowRepository) RetrieveByThing(ctx context.Context, thingID, name string) (shadows.Shadow, error) {
+	q := fmt.Sprintf(`SELECT %s FROM shadows WHERE thing_id = $1 AND name = $2;`, shadowColumns)
 
 	dbSh := dbShadow{}
-	if err := sr.db.QueryRowxContext(ctx, q, thingID).StructScan(&dbSh); err != nil {
+	if err := sr.db.QueryRowxContext(ctx, q, thingID, name).StructScan(&dbSh); err != nil {
 		pgErr, ok := err.(*pgconn.PgError)
 		// A thing's shadow always exists conceptually: a missing row
 		// reads back as an empty shadow rather than an error.
 		if err == sql.ErrNoRows || (ok && pgerrcode.InvalidTextRepresentation == pgErr.Code) {
 			return shadows.Shadow{
 				ThingID:  thingID,
+				Name:     name,
 				Desired:  shadows.State{},
 				Reported: shadows.State{},
 			}, nil
@@ -108,7 +162,136 @@ func (sr shadowRepository) RetrieveByThing(ctx context.Context, thingID string)
 	return toShadow(dbSh)
 }
 
-func (sr shadowRepository) Remove(ctx context.Context, thingID string) error {
+func (sr shadowRepository) RetrieveByThings(ctx context.Context, thingIDs []string, name string) ([]shadows.Shadow, error) {
+	if len(thingIDs) == 0 {
+		return nil, nil
+	}
+
+	q := fmt.Sprintf(`SELECT %s FROM shadows WHERE thing_id = ANY($1) AND name = $2;`, shadowColumns)
+
+	var dbShs []dbShadow
+	if err := sr.db.SelectContext(ctx, &dbShs, q, thingIDs, name); err != nil {
+		return nil, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+	}
+
+	var items []shadows.Shadow
+	for _, dbSh := range dbShs {
+		sh, err := toShadow(dbSh)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, sh)
+	}
+
+	return items, nil
+}
+
+func (sr shadowRepository) RetrieveAllByThing(ctx context.Context, thingID string) ([]shadows.Shadow, error) {
+	if _, err := uuid.FromString(thingID); err != nil {
+		return nil, nil
+	}
+
+	q := fmt.Sprintf(`SELECT %s FROM shadows WHERE thing_id = $1 ORDER BY name;`, shadowColumns)
+
+	var dbShs []dbShadow
+	if err := sr.db.SelectContext(ctx, &dbShs, q, thingID); err != nil {
+		return nil, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+	}
+
+	var items []shadows.Shadow
+	for _, dbSh := range dbShs {
+		sh, err := toShadow(dbSh)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, sh)
+	}
+
+	return items, nil
+}
+
+func (sr shadowRepository) RetrieveHistory(ctx context.Context, thingID, name string, pm shadows.PageMetadata) (shadows.HistoryPage, error) {
+	if _, err := uuid.FromString(thingID); err != nil {
+		return shadows.HistoryPage{}, nil
+	}
+
+	dq := dbutil.GetDirQuery(pm.Dir)
+	olq := dbutil.GetOffsetLimitQuery(pm.Limit)
+	tq := ""
+	if pm.Type != "" {
+		tq = "type = :type"
+	}
+	whereClause := dbutil.BuildWhereClause("thing_id = :thing_id", "name = :name", tq)
+
+	q := fmt.Sprintf(`SELECT thing_id, name, type, state, version, created FROM shadow_history %s ORDER BY id %s %s;`, whereClause, dq, olq)
+	qc := fmt.Sprintf(`SELECT COUNT(*) FROM shadow_history %s;`, whereClause)
+
+	params := map[string]any{
+		"thing_id": thingID,
+		"name":     name,
+		"type":     pm.Type,
+		"limit":    pm.Limit,
+		"offset":   pm.Offset,
+	}
+
+	rows, err := sr.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return shadows.HistoryPage{}, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+	}
+	defer rows.Close()
+
+	var items []shadows.StateChange
+	for rows.Next() {
+		var dbsc dbStateChange
+		if err := rows.StructScan(&dbsc); err != nil {
+			return shadows.HistoryPage{}, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+		}
+
+		var state shadows.State
+		if err := json.Unmarshal(dbsc.State, &state); err != nil {
+			return shadows.HistoryPage{}, errors.Wrap(dbutil.ErrMalformedEntity, err)
+		}
+
+		items = append(items, shadows.StateChange{
+			ThingID: dbsc.ThingID,
+			Name:    dbsc.Name,
+			Type:    dbsc.Type,
+			State:   state,
+			Version: dbsc.Version,
+			Created: dbsc.Created,
+		})
+	}
+
+	total, err := dbutil.Total(ctx, sr.db, qc, params)
+	if err != nil {
+		return shadows.HistoryPage{}, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+	}
+
+	return shadows.HistoryPage{
+		Total:   total,
+		History: items,
+	}, nil
+}
+
+func (sr shadowRepository) Remove(ctx context.Context, thingID, name string) error {
+	qh := `DELETE FROM shadow_history WHERE thing_id = :thing_id AND name = :name;`
+	if _, err := sr.db.NamedExecContext(ctx, qh, dbShadow{ThingID: thingID, Name: name}); err != nil {
+		return errors.Wrap(dbutil.ErrRemoveEntity, err)
+	}
+
+	q := `DELETE FROM shadows WHERE thing_id = :thing_id AND name = :name;`
+	if _, err := sr.db.NamedExecContext(ctx, q, dbShadow{ThingID: thingID, Name: name}); err != nil {
+		return errors.Wrap(dbutil.ErrRemoveEntity, err)
+	}
+	return nil
+}
+
+func (sr shadowRepository) RemoveByThing(ctx context.Context, thingID string) error {
+	qh := `DELETE FROM shadow_history WHERE thing_id = :thing_id;`
+	if _, err := sr.db.NamedExecContext(ctx, qh, dbShadow{ThingID: thingID}); err != nil {
+		return errors.Wrap(dbutil.ErrRemoveEntity, err)
+	}
+
 	q := `DELETE FROM shadows WHERE thing_id = :thing_id;`
 	if _, err := sr.db.NamedExecContext(ctx, q, dbShadow{ThingID: thingID}); err != nil {
 		return errors.Wrap(dbutil.ErrRemoveEntity, err)
@@ -116,12 +299,60 @@ func (sr shadowRepository) Remove(ctx context.Context, thingID string) error {
 	return nil
 }
 
+func (sr shadowRepository) PruneHistory(ctx context.Context, before time.Time, keep uint64) error {
+	if !before.IsZero() {
+		q := `DELETE FROM shadow_history WHERE id IN (
+			SELECT id FROM shadow_history WHERE created < :before LIMIT :batch
+		);`
+
+		if err := dbutil.DeleteInBatches(ctx, sr.db, q, map[string]any{"before": before.Unix()}); err != nil {
+			return errors.Wrap(dbutil.ErrRemoveEntity, err)
+		}
+	}
+
+	if keep > 0 {
+		// The state changes of each shadow are walked newest first on its index, skipping the kept ones.
+		q := `DELETE FROM shadow_history WHERE id IN (
+			SELECT h.id FROM shadows s, LATERAL (
+				SELECT id FROM shadow_history
+				WHERE thing_id = s.thing_id AND name = s.name
+				ORDER BY id DESC
+				OFFSET :keep
+			) h
+			LIMIT :batch
+		);`
+
+		if err := dbutil.DeleteInBatches(ctx, sr.db, q, map[string]any{"keep": keep}); err != nil {
+			return errors.Wrap(dbutil.ErrRemoveEntity, err)
+		}
+	}
+
+	return nil
+}
+
 type dbShadow struct {
 	ThingID    string `db:"thing_id"`
+	Name       string `db:"name"`
 	Desired    []byte `db:"desired"`
 	Reported   []byte `db:"reported"`
 	ReportedAt int64  `db:"reported_at"`
 	UpdatedAt  int64  `db:"updated_at"`
+	Version    uint64 `db:"version"`
+
+	DeliveryVersion      uint64 `db:"delivery_version"`
+	DeliveryPendingSince int64  `db:"delivery_pending_since"`
+	DeliverySentAt       int64  `db:"delivery_sent_at"`
+	DeliveryAttempts     uint64 `db:"delivery_attempts"`
+	DeliveryAcked        bool   `db:"delivery_acked"`
+}
+
+type dbStateChange struct {
+	ThingID string `db:"thing_id"`
+	Name    string `db:"name"`
+	Type    string `db:"type"`
+	State   []byte `db:"state"`
+	Version uint64 `db:"version"`
+	Created int64  `db:"created"`
 }
 
 func marshalState(s map[string]any) ([]byte, error) {
@@ -144,9 +375,18 @@ func toShadow(dbSh dbShadow) (shadows.Shadow, error) {
 
 	return shadows.Shadow{
 		ThingID:    dbSh.ThingID,
+		Name:       dbSh.Name,
 		Desired:    desired,
 		Reported:   reported,
 		ReportedAt: dbSh.ReportedAt,
 		UpdatedAt:  dbSh.UpdatedAt,
+		Version:    dbSh.Version,
+		Delivery: shadows.Delivery{
+			Version:      dbSh.DeliveryVersion,
+			PendingSince: dbSh.DeliveryPendingSince,
+			SentAt:       dbSh.DeliverySentAt,
+			Attempts:     dbSh.DeliveryAttempts,
+			Acked:        dbSh.DeliveryAcked,
+		},
 	}, nil
 }
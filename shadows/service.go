@@ -7,10 +7,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/MainfluxLabs/mainflux/consumers"
 	"github.com/MainfluxLabs/mainflux/logger"
+	"github.com/MainfluxLabs/mainflux/pkg/dbutil"
 	"github.com/MainfluxLabs/mainflux/pkg/domain"
 	"github.com/MainfluxLabs/mainflux/pkg/errors"
 	"github.com/MainfluxLabs/mainflux/pkg/messaging"
@@ -19,71 +21,172 @@ import (
 )
 
 // shadowSubtopic routes shadow commands on the thing's command subject
-// (things.<id>.commands.shadow).
+// (things.<id>.commands.shadow). Commands of named shadows are routed on
+// a per-name subtopic under it (things.<id>.commands.shadow.<name>).
 const shadowSubtopic = "shadow"
 
+// maxConflictRetries is the number of times a desired state patch is applied
+// after a concurrent desired state update.
+const maxConflictRetries = 3
+
 // Service specifies the API offered by the shadows service. All methods that
 // accept a token use it to identify and authorize the user.
+// Shadows are identified by the thing ID and the shadow name, which is
+// DefaultName for the thing's default shadow.
 type Service interface {
-	// UpdateDesiredState replaces the thing's desired state, pushes the
+	// UpdateDesiredState replaces the shadow's desired state, pushes the
 	// resulting delta to the device, and returns the updated shadow.
-	UpdateDesiredState(ctx context.Context, token, thingID string, desired State) (Shadow, error)
+	// A non-zero version makes the update conditional: it fails with
+	// ErrVersionConflict unless the shadow still has that version.
+	UpdateDesiredState(ctx context.Context, token, thingID, name string, desired State, version uint64) (Shadow, error)
+
+	// PatchDesiredState applies the patch onto the shadow's desired state, pushes
+	// the resulting delta to the device, and returns the updated shadow.
+	// A non-zero version makes the update conditional, as in UpdateDesiredState.
+	PatchDesiredState(ctx context.Context, token, thingID, name string, patch Patch, version uint64) (Shadow, error)
+
+	// PatchGroupDesiredState applies the patch onto the desired state of the
+	// named shadow of every thing of the group, pushes the resulting deltas to
+	// the devices, and reports the outcome of each update.
+	PatchGroupDesiredState(ctx context.Context, token, groupID, name string, patch Patch) (BulkUpdate, error)
+
+	// PatchProfileDesiredState applies the patch onto the desired state of the
+	// named shadow of every thing of the profile, as in PatchGroupDesiredState.
+	PatchProfileDesiredState(ctx context.Context, token, profileID, name string, patch Patch) (BulkUpdate, error)
 
-	// ViewShadow returns the thing's shadow with its delta populated.
-	ViewShadow(ctx context.Context, token, thingID string) (Shadow, error)
+	// ViewGroupSyncStatus returns the number of things of the group whose
+	// named shadow is in sync or pending, along with the pending keys.
+	ViewGroupSyncStatus(ctx context.Context, token, groupID, name string) (SyncStatus, error)
 
-	// RemoveShadow removes the thing's shadow.
-	RemoveShadow(ctx context.Context, token, thingID string) error
+	// ViewShadow returns the shadow with its delta populated.
+	ViewShadow(ctx context.Context, token, thingID, name string) (Shadow, error)
 
-	// RemoveByThing removes the shadow for the given thing without an auth check.
+	// GetShadow returns the shadow with its delta populated, without an auth check.
+	GetShadow(ctx context.Context, thingID, name string) (Shadow, error)
+
+	// ListShadowHistory returns a page of the desired and reported state
+	// changes of the shadow.
+	ListShadowHistory(ctx context.Context, token, thingID, name string, pm PageMetadata) (HistoryPage, error)
+
+	// RemoveShadow removes the shadow.
+	RemoveShadow(ctx context.Context, token, thingID, name string) error
+
+	// RemoveByThing removes all the shadows of the given thing without an auth check.
 	RemoveByThing(ctx context.Context, thingID string) error
 
+	// ProcessHistory periodically removes the shadow state changes exceeding the
+	// retention limits until the context is done.
+	ProcessHistory(ctx context.Context) error
+
+	// RedeliverDeltas resends the unacknowledged deltas of all the shadows of
+	// the given thing, e.g. once the thing reconnects.
+	RedeliverDeltas(ctx context.Context, thingID string) error
+
+	// ConsumeMessage merges the thing's telemetry into the reported state of
+	// its default shadow, or of the named shadow for messages published on
+	// the shadow's subtopic (shadow.<name>). Messages published on the ack
+	// subtopic (shadow.ack or shadow.<name>.ack) acknowledge the delta last
+	// sent to the device.
 	consumers.MessageConsumer
+
+	// ConsumeCommand merges a desired state patch published to the thing's
+	// shadows subject, e.g. by a rule action, into the desired state of the
+	// shadow named by the command subtopic, or of the default shadow.
+	consumers.CommandConsumer
 }
 
 type shadowsService struct {
 	things    domain.ThingsClient
 	shadows   ShadowRepository
 	publisher messaging.CommandPublisher
+	history   HistoryConfig
 	logger    logger.Logger
 }
 
 var _ Service = (*shadowsService)(nil)
 
 // New instantiates the shadows service.
-func New(things domain.ThingsClient, shadows ShadowRepository, pub messaging.CommandPublisher, logger logger.Logger) Service {
+func New(things domain.ThingsClient, shadows ShadowRepository, pub messaging.CommandPublisher, history HistoryConfig, logger logger.Logger) Service {
 	return &shadowsService{
 		things:    things,
 		shadows:   shadows,
 		publisher: pub,
+		history:   history,
 		logger:    logger,
 	}
 }
 
-func (ss *shadowsService) UpdateDesiredState(ctx context.Context, token, thingID string, desired State) (Shadow, error) {
+func (ss *shadowsService) UpdateDesiredState(ctx context.Context, token, thingID, name string, desired State, version uint64) (Shadow, error) {
 	if err := ss.things.CanUserAccessThing(ctx, domain.UserAccessReq{Token: token, ID: thingID, Action: domain.GroupEditor}); err != nil {
 		return Shadow{}, errors.Wrap(errors.ErrAuthorization, err)
 	}
 
-	stored, err := ss.shadows.UpsertDesiredState(ctx, thingID, desired, time.Now().Unix())
+	return ss.updateDesiredState(ctx, thingID, name, desired, version)
+}
+
+// updateDesiredState stores the desired state and pushes the resulting delta to the device.
+func (ss *shadowsService) updateDesiredState(ctx context.Context, thingID, name string, desired State, version uint64) (Shadow, error) {
+	stored, err := ss.shadows.UpsertDesiredState(ctx, thingID, name, desired, time.Now().Unix(), version)
 	if err != nil {
 		return Shadow{}, err
 	}
 
 	stored.Delta = computeDelta(stored.Desired, stored.Reported)
-	if err := ss.publish(thingID, stored.Delta); err != nil {
-		ss.logger.Warn(fmt.Sprintf("failed to push delta to thing %s: %s", thingID, err))
-	}
+	stored.Delivery = ss.deliver(ctx, stored, stored.Delta, false)
 
 	return stored, nil
 }
 
-func (ss *shadowsService) ViewShadow(ctx context.Context, token, thingID string) (Shadow, error) {
+func (ss *shadowsService) PatchDesiredState(ctx context.Context, token, thingID, name string, patch Patch, version uint64) (Shadow, error) {
+	if err := ss.things.CanUserAccessThing(ctx, domain.UserAccessReq{Token: token, ID: thingID, Action: domain.GroupEditor}); err != nil {
+		return Shadow{}, errors.Wrap(errors.ErrAuthorization, err)
+	}
+
+	return ss.patchDesiredState(ctx, thingID, name, patch, version)
+}
+
+// patchDesiredState applies the patch onto the current desired state and stores the result.
+// Unless the update is conditional, the patch is reapplied after a concurrent desired state update.
+func (ss *shadowsService) patchDesiredState(ctx context.Context, thingID, name string, patch Patch, version uint64) (Shadow, error) {
+	for range maxConflictRetries {
+		current, err := ss.shadows.RetrieveByThing(ctx, thingID, name)
+		if err != nil {
+			return Shadow{}, err
+		}
+		if version > 0 && current.Version != version {
+			return Shadow{}, errors.Wrap(dbutil.ErrConflict, ErrVersionConflict)
+		}
+
+		desired, changed, err := patch.apply(current.Desired)
+		if err != nil {
+			return Shadow{}, err
+		}
+		if !changed {
+			current.Delta = computeDelta(current.Desired, current.Reported)
+			return current, nil
+		}
+
+		// The update is conditional on the version the patch was applied to,
+		// so that a concurrent desired state update isn't overwritten.
+		sh, err := ss.updateDesiredState(ctx, thingID, name, desired, current.Version)
+		if version > 0 || !errors.Contains(err, ErrVersionConflict) {
+			return sh, err
+		}
+	}
+
+	return Shadow{}, errors.Wrap(dbutil.ErrConflict, ErrVersionConflict)
+}
+
+func (ss *shadowsService) ViewShadow(ctx context.Context, token, thingID, name string) (Shadow, error) {
 	if err := ss.things.CanUserAccessThing(ctx, domain.UserAccessReq{Token: token, ID: thingID, Action: domain.GroupViewer}); err != nil {
 		return Shadow{}, errors.Wrap(errors.ErrAuthorization, err)
 	}
 
-	shadow, err := ss.shadows.RetrieveByThing(ctx, thingID)
+	return ss.GetShadow(ctx, thingID, name)
+}
+
+func (ss *shadowsService) GetShadow(ctx context.Context, thingID, name string) (Shadow, error) {
+	shadow, err := ss.shadows.RetrieveByThing(ctx, thingID, name)
 	if err != nil {
 		return Shadow{}, err
 	}
@@ -92,52 +195,96 @@ func (ss *shadowsService) ViewShadow(ctx context.Context, token, thingID string)
 	return shadow, nil
 }
 
-func (ss *shadowsService) RemoveShadow(ctx context.Context, token, thingID string) error {
+func (ss *shadowsService) ListShadowHistory(ctx context.Context, token, thingID, name string, pm PageMetadata) (HistoryPage, error) {
+	if err := ss.things.CanUserAccessThing(ctx, domain.UserAccessReq{Token: token, ID: thingID, Action: domain.GroupViewer}); err != nil {
+		return HistoryPage{}, errors.Wrap(errors.ErrAuthorization, err)
+	}
+
+	return ss.shadows.RetrieveHistory(ctx, thingID, name, pm)
+}
+
+func (ss *shadowsService) RemoveShadow(ctx context.Context, token, thingID, name string) error {
 	if err := ss.things.CanUserAccessThing(ctx, domain.UserAccessReq{Token: token, ID: thingID, Action: domain.GroupEditor}); err != nil {
 		return errors.Wrap(errors.ErrAuthorization, err)
 	}
 
-	return ss.shadows.Remove(ctx, thingID)
+	return ss.shadows.Remove(ctx, thingID, name)
 }
 
 func (ss *shadowsService) RemoveByThing(ctx context.Context, thingID string) error {
-	return ss.shadows.Remove(ctx, thingID)
+	return ss.shadows.RemoveByThing(ctx, thingID)
 }
 
 // ConsumeMessage merges a thing's telemetry into reported (skipping no-op
-// writes) and re-publishes any pending delta, so a reconnecting device
-// receives missed commands.
+// writes) and resends any pending delta that is due for redelivery, so a
+// device receives commands it missed.
 func (ss *shadowsService) ConsumeMessage(_ string, msg protomfx.Message) error {
+	ctx := context.Background()
+	if name, ok := ackShadowName(msg.Subtopic); ok {
+		return ss.ackDelta(ctx, msg.Publisher, name)
+	}
+
 	patch, ok := decodeState(msg)
 	if !ok || len(patch) == 0 {
 		return nil
 	}
 
-	ctx := context.Background()
-	current, err := ss.shadows.RetrieveByThing(ctx, msg.Publisher)
+	name := shadowName(msg.Subtopic)
+	current, err := ss.shadows.RetrieveByThing(ctx, msg.Publisher, name)
 	if err != nil {
 		return err
 	}
 
 	merged, changed := mergeState(current.Reported, patch)
 	if changed {
-		if err := ss.shadows.UpsertReportedState(ctx, msg.Publisher, merged, time.Now().Unix()); err != nil {
+		if err := ss.shadows.UpsertReportedState(ctx, msg.Publisher, name, merged, time.Now().Unix()); err != nil {
 			return err
 		}
 	}
 
-	if delta := computeDelta(current.Desired, merged); len(delta) > 0 {
-		if err := ss.publish(msg.Publisher, delta); err != nil {
-			ss.logger.Warn(fmt.Sprintf("failed to push delta to thing %s: %s", msg.Publisher, err))
-		}
-	}
+	ss.deliver(ctx, current, computeDelta(current.Desired, merged), false)
 
 	return nil
 }
 
-// publish publishes the delta to the thing's command subject.
+func (ss *shadowsService) ConsumeCommand(_ string, cmd protomfx.Command) error {
+	var patch State
+	if err := json.Unmarshal(cmd.Payload, &patch); err != nil {
+		return err
+	}
+	if len(patch) == 0 || cmd.RecipientID == "" {
+		return nil
+	}
+	if !IsValidName(cmd.Subtopic) {
+		return errors.Wrap(errors.ErrMalformedEntity, ErrInvalidName)
+	}
+
+	_, err := ss.patchDesiredState(context.Background(), cmd.RecipientID, cmd.Subtopic, Patch{Merge: patch}, 0)
+	return err
+}
+
+// shadowName returns the name of the shadow a message published on the
+// subtopic reports the state of: the name following the shadow subtopic,
+// or the default shadow for any other subtopic.
+func shadowName(subtopic string) string {
+	name, ok := strings.CutPrefix(subtopic, shadowSubtopic+".")
+	if !ok || !IsValidName(name) {
+		return DefaultName
+	}
+	return name
+}
+
+// commandSubtopic returns the subtopic the commands of the named shadow are routed on.
+func commandSubtopic(name string) string {
+	if name == DefaultName {
+		return shadowSubtopic
+	}
+	return fmt.Sprintf("%s.%s", shadowSubtopic, name)
+}
+
+// publish publishes the delta to the shadow's command subject.
 // An empty delta is not published.
-func (ss *shadowsService) publish(thingID string, delta State) error {
+func (ss *shadowsService) publish(thingID, name string, delta State) error {
 	if len(delta) == 0 {
 		return nil
 	}
@@ -147,15 +294,16 @@ func (ss *shadowsService) publish(thingID string, delta State) error {
 		return err
 	}
 
+	subtopic := commandSubtopic(name)
 	cmd := protomfx.Command{
 		Publisher: thingID,
-		Subtopic:  shadowSubtopic,
+		Subtopic:  subtopic,
 		Protocol:  "shadows",
 		Payload:   payload,
 		Created:   time.Now().UnixNano(),
 	}
 
-	subject := nats.GetThingCommandsSubject(thingID, shadowSubtopic)
+	subject := nats.GetThingCommandsSubject(thingID, subtopic)
 	return ss.publisher.PublishCommand(subject, cmd)
 }
 
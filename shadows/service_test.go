@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/MainfluxLabs/mainflux/logger"
 	"github.com/MainfluxLabs/mainflux/pkg/errors"
@@ -21,11 +22,15 @@ import (
 )
 
 const (
-	email   = "admin@example.com"
-	token   = email
-	thingID = "5384fb1c-d0ae-4cbe-be52-c54223150fe0"
-	groupID = "574106f7-030e-4881-8ab0-151195c29f94"
-	wrongID = "wrong-id"
+	email     = "admin@example.com"
+	token     = email
+	thingID   = "5384fb1c-d0ae-4cbe-be52-c54223150fe0"
+	thingID2  = "a8a6c9b2-4b5c-45ea-9e3a-9bd0e1c03c52"
+	profileID = "e4b1bb43-2f02-4c9e-8d79-5e0c2cbad1a3"
+	groupID   = "574106f7-030e-4881-8ab0-151195c29f94"
+	wrongID   = "wrong-id"
+
+	shadowName = "firmware"
 )
 
 var desiredState = shadows.State{"led": "on"}
@@ -40,7 +45,7 @@ func newService() shadows.Service {
 	pub := shmocks.NewCommandPublisher()
 	log := logger.NewMock()
 
-	return shadows.New(thingsSvc, repo, pub, log)
+	return shadows.New(thingsSvc, repo, pub, shadows.HistoryConfig{}, log)
 }
 
 func TestUpdateDesiredState(t *testing.T) {
@@ -50,7 +55,9 @@ func TestUpdateDesiredState(t *testing.T) {
 		desc    string
 		token   string
 		thingID string
+		name    string
 		desired shadows.State
+		version uint64
 		delta   shadows.State
 		err     error
 	}{
@@ -62,6 +69,32 @@ func TestUpdateDesiredState(t *testing.T) {
 			delta:   desiredState,
 			err:     nil,
 		},
+		{
+			desc:    "update desired state with matching version",
+			token:   token,
+			thingID: thingID,
+			desired: shadows.State{"led": "off"},
+			version: 1,
+			delta:   shadows.State{"led": "off"},
+			err:     nil,
+		},
+		{
+			desc:    "update desired state with stale version",
+			token:   token,
+			thingID: thingID,
+			desired: desiredState,
+			version: 1,
+			err:     shadows.ErrVersionConflict,
+		},
+		{
+			desc:    "update desired state of named shadow",
+			token:   token,
+			thingID: thingID,
+			name:    shadowName,
+			desired: shadows.State{"mode": "eco"},
+			delta:   shadows.State{"mode": "eco"},
+			err:     nil,
+		},
 		{
 			desc:    "update desired state with empty token",
 			token:   "",
@@ -79,7 +112,7 @@ func TestUpdateDesiredState(t *testing.T) {
 	}
 
 	for _, tc := range cases {
-		sh, err := svc.UpdateDesiredState(context.Background(), tc.token, tc.thingID, tc.desired)
+		sh, err := svc.UpdateDesiredState(context.Background(), tc.token, tc.thingID, tc.name, tc.desired, tc.version)
 		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
 		if tc.err == nil {
 			assert.Equal(t, tc.desired, sh.Desired, fmt.Sprintf("%s: expected desired %v got %v", tc.desc, tc.desired, sh.Desired))
@@ -89,10 +122,139 @@ func TestUpdateDesiredState(t *testing.T) {
 	}
 }
 
+func TestPatchDesiredState(t *testing.T) {
+	svc := newService()
+
+	initial := shadows.State{
+		"led":     "on",
+		"network": map[string]any{"wifi": map[string]any{"ssid": "home", "channel": float64(6)}},
+		"modes":   []any{"eco", "boost"},
+	}
+	_, err := svc.UpdateDesiredState(context.Background(), token, thingID, shadows.DefaultName, initial, 0)
+	require.Nil(t, err, fmt.Sprintf("unexpected error setting desired state: %s", err))
+
+	cases := []struct {
+		desc    string
+		token   string
+		thingID string
+		patch   shadows.Patch
+		version uint64
+		desired shadows.State
+		err     error
+	}{
+		{
+			desc:    "patch desired state with nested merge patch",
+			token:   token,
+			thingID: thingID,
+			patch:   shadows.Patch{Merge: shadows.State{"network": map[string]any{"wifi": map[string]any{"ssid": "office", "channel": nil}}}},
+			desired: shadows.State{
+				"led":     "on",
+				"network": map[string]any{"wifi": map[string]any{"ssid": "office"}},
+				"modes":   []any{"eco", "boost"},
+			},
+			err: nil,
+		},
+		{
+			desc:    "patch desired state with JSON patch",
+			token:   token,
+			thingID: thingID,
+			patch: shadows.Patch{Operations: []shadows.PatchOperation{
+				{Op: shadows.PatchOpTest, Path: "/network/wifi/ssid", Value: "office"},
+				{Op: shadows.PatchOpReplace, Path: "/led", Value: "off"},
+				{Op: shadows.PatchOpAdd, Path: "/modes/1", Value: "night"},
+				{Op: shadows.PatchOpCopy, From: "/network/wifi/ssid", Path: "/network/ssid"},
+				{Op: shadows.PatchOpMove, From: "/network/wifi", Path: "/wifi"},
+				{Op: shadows.PatchOpRemove, Path: "/modes/0"},
+			}},
+			version: 2,
+			desired: shadows.State{
+				"led":     "off",
+				"network": map[string]any{"ssid": "office"},
+				"wifi":    map[string]any{"ssid": "office"},
+				"modes":   []any{"night", "boost"},
+			},
+			err: nil,
+		},
+		{
+			desc:    "patch desired state with JSON patch adding a nil value",
+			token:   token,
+			thingID: thingID,
+			patch:   shadows.Patch{Operations: []shadows.PatchOperation{{Op: shadows.PatchOpAdd, Path: "/led", Value: nil}}},
+			desired: shadows.State{
+				"network": map[string]any{"ssid": "office"},
+				"wifi":    map[string]any{"ssid": "office"},
+				"modes":   []any{"night", "boost"},
+			},
+			err: nil,
+		},
+		{
+			desc:    "patch desired state with failing test operation",
+			token:   token,
+			thingID: thingID,
+			patch: shadows.Patch{Operations: []shadows.PatchOperation{
+				{Op: shadows.PatchOpTest, Path: "/wifi/ssid", Value: "home"},
+				{Op: shadows.PatchOpRemove, Path: "/wifi"},
+			}},
+			err: shadows.ErrPatchTestFailed,
+		},
+		{
+			desc:    "patch desired state with missing path",
+			token:   token,
+			thingID: thingID,
+			patch:   shadows.Patch{Operations: []shadows.PatchOperation{{Op: shadows.PatchOpRemove, Path: "/missing"}}},
+			err:     shadows.ErrInvalidPatch,
+		},
+		{
+			desc:    "patch desired state with invalid array index",
+			token:   token,
+			thingID: thingID,
+			patch:   shadows.Patch{Operations: []shadows.PatchOperation{{Op: shadows.PatchOpReplace, Path: "/modes/5", Value: "eco"}}},
+			err:     shadows.ErrInvalidPatch,
+		},
+		{
+			desc:    "patch desired state with invalid operation",
+			token:   token,
+			thingID: thingID,
+			patch:   shadows.Patch{Operations: []shadows.PatchOperation{{Op: "invalid", Path: "/led"}}},
+			err:     shadows.ErrInvalidPatch,
+		},
+		{
+			desc:    "patch desired state with stale version",
+			token:   token,
+			thingID: thingID,
+			patch:   shadows.Patch{Merge: shadows.State{"led": "on"}},
+			version: 1,
+			err:     shadows.ErrVersionConflict,
+		},
+		{
+			desc:    "patch desired state with empty token",
+			token:   "",
+			thingID: thingID,
+			patch:   shadows.Patch{Merge: shadows.State{"led": "on"}},
+			err:     errors.ErrAuthorization,
+		},
+		{
+			desc:    "patch desired state for wrong thing ID",
+			token:   token,
+			thingID: wrongID,
+			patch:   shadows.Patch{Merge: shadows.State{"led": "on"}},
+			err:     errors.ErrAuthorization,
+		},
+	}
+
+	for _, tc := range cases {
+		sh, err := svc.PatchDesiredState(context.Background(), tc.token, tc.thingID, shadows.DefaultName, tc.patch, tc.version)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		if tc.err == nil {
+			assert.Equal(t, tc.desired, sh.Desired, fmt.Sprintf("%s: expected desired %v got %v", tc.desc, tc.desired, sh.Desired))
+		}
+	}
+}
+
 func TestViewShadow(t *testing.T) {
 	svc := newService()
 
-	_, err := svc.UpdateDesiredState(context.Background(), token, thingID, shadows.State{"led": "on", "temp": "20"})
+	_, err := svc.UpdateDesiredState(context.Background(), token, thingID, shadows.DefaultName, shadows.State{"led": "on", "temp": "20"}, 0)
 	require.Nil(t, err, fmt.Sprintf("unexpected error setting desired state: %s", err))
 
 	// The thing reports one of the two desired values, the other stays pending in the delta
@@ -132,7 +294,7 @@ func TestViewShadow(t *testing.T) {
 	}
 
 	for _, tc := range cases {
-		sh, err := svc.ViewShadow(context.Background(), tc.token, tc.thingID)
+		sh, err := svc.ViewShadow(context.Background(), tc.token, tc.thingID, shadows.DefaultName)
 		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
 		if tc.err == nil {
 			assert.Equal(t, tc.desired, sh.Desired, fmt.Sprintf("%s: expected desired %v got %v", tc.desc, tc.desired, sh.Desired))
@@ -142,10 +304,144 @@ func TestViewShadow(t *testing.T) {
 	}
 }
 
+func TestListShadowHistory(t *testing.T) {
+	svc := newService()
+
+	_, err := svc.UpdateDesiredState(context.Background(), token, thingID, shadows.DefaultName, desiredState, 0)
+	require.Nil(t, err, fmt.Sprintf("unexpected error setting desired state: %s", err))
+	err = svc.ConsumeMessage("", protomfx.Message{Publisher: thingID, Payload: toPayload(desiredState)})
+	require.Nil(t, err, fmt.Sprintf("unexpected error reporting state: %s", err))
+	_, err = svc.UpdateDesiredState(context.Background(), token, thingID, shadows.DefaultName, shadows.State{"led": "off"}, 1)
+	require.Nil(t, err, fmt.Sprintf("unexpected error setting desired state: %s", err))
+
+	cases := []struct {
+		desc    string
+		token   string
+		thingID string
+		pm      shadows.PageMetadata
+		total   uint64
+		types   []string
+		err     error
+	}{
+		{
+			desc:    "list shadow history",
+			token:   token,
+			thingID: thingID,
+			pm:      shadows.PageMetadata{Limit: 10},
+			total:   3,
+			types:   []string{shadows.StateTypeDesired, shadows.StateTypeReported, shadows.StateTypeDesired},
+			err:     nil,
+		},
+		{
+			desc:    "list shadow history in ascending order with limit",
+			token:   token,
+			thingID: thingID,
+			pm:      shadows.PageMetadata{Limit: 2, Dir: "asc"},
+			total:   3,
+			types:   []string{shadows.StateTypeDesired, shadows.StateTypeReported},
+			err:     nil,
+		},
+		{
+			desc:    "list shadow history filtered by type",
+			token:   token,
+			thingID: thingID,
+			pm:      shadows.PageMetadata{Limit: 10, Type: shadows.StateTypeReported},
+			total:   1,
+			types:   []string{shadows.StateTypeReported},
+			err:     nil,
+		},
+		{
+			desc:    "list shadow history with empty token",
+			token:   "",
+			thingID: thingID,
+			pm:      shadows.PageMetadata{Limit: 10},
+			err:     errors.ErrAuthorization,
+		},
+		{
+			desc:    "list shadow history for wrong thing ID",
+			token:   token,
+			thingID: wrongID,
+			pm:      shadows.PageMetadata{Limit: 10},
+			err:     errors.ErrAuthorization,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := svc.ListShadowHistory(context.Background(), tc.token, tc.thingID, shadows.DefaultName, tc.pm)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		if tc.err == nil {
+			assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected total %d got %d", tc.desc, tc.total, page.Total))
+			var types []string
+			for _, sc := range page.History {
+				types = append(types, sc.Type)
+			}
+			assert.Equal(t, tc.types, types, fmt.Sprintf("%s: expected types %v got %v", tc.desc, tc.types, types))
+		}
+	}
+}
+
+func TestProcessHistory(t *testing.T) {
+	thingsSvc := pkgmocks.NewThingsServiceClient(
+		nil,
+		map[string]things.Thing{token: {ID: thingID, GroupID: groupID}},
+		map[string]things.Group{token: {ID: groupID}},
+	)
+	pub := shmocks.NewCommandPublisher()
+	log := logger.NewMock()
+
+	cases := []struct {
+		desc   string
+		config shadows.HistoryConfig
+		total  uint64
+	}{
+		{
+			desc:   "process history without retention limits",
+			config: shadows.HistoryConfig{},
+			total:  5,
+		},
+		{
+			desc:   "process history with max age",
+			config: shadows.HistoryConfig{MaxAge: time.Hour},
+			total:  3,
+		},
+		{
+			desc:   "process history with max count",
+			config: shadows.HistoryConfig{MaxCount: 2},
+			total:  2,
+		},
+		{
+			desc:   "process history with max age and max count",
+			config: shadows.HistoryConfig{MaxAge: time.Hour, MaxCount: 4},
+			total:  3,
+		},
+	}
+
+	for _, tc := range cases {
+		repo := shmocks.NewShadowRepository()
+		svc := shadows.New(thingsSvc, repo, pub, tc.config, log)
+
+		now := time.Now()
+		for _, created := range []time.Time{now.Add(-3 * time.Hour), now.Add(-2 * time.Hour), now, now, now} {
+			_, err := repo.UpsertDesiredState(context.Background(), thingID, shadows.DefaultName, desiredState, created.Unix(), 0)
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error setting desired state: %s", tc.desc, err))
+		}
+
+		// A done context makes ProcessHistory return after pruning once.
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := svc.ProcessHistory(ctx)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+
+		page, err := svc.ListShadowHistory(context.Background(), token, thingID, shadows.DefaultName, shadows.PageMetadata{Limit: 10})
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error listing history: %s", tc.desc, err))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected total %d got %d", tc.desc, tc.total, page.Total))
+	}
+}
+
 func TestRemoveShadow(t *testing.T) {
 	svc := newService()
 
-	_, err := svc.UpdateDesiredState(context.Background(), token, thingID, desiredState)
+	_, err := svc.UpdateDesiredState(context.Background(), token, thingID, shadows.DefaultName, desiredState, 0)
 	require.Nil(t, err, fmt.Sprintf("unexpected error setting desired state: %s", err))
 
 	cases := []struct {
@@ -175,7 +471,7 @@ func TestRemoveShadow(t *testing.T) {
 	}
 
 	for _, tc := range cases {
-		err := svc.RemoveShadow(context.Background(), tc.token, tc.thingID)
+		err := svc.RemoveShadow(context.Background(), tc.token, tc.thingID, shadows.DefaultName)
 		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
 	}
 }
@@ -183,8 +479,10 @@ func TestRemoveShadow(t *testing.T) {
 func TestRemoveByThing(t *testing.T) {
 	svc := newService()
 
-	_, err := svc.UpdateDesiredState(context.Background(), token, thingID, desiredState)
-	require.Nil(t, err, fmt.Sprintf("unexpected error setting desired state: %s", err))
+	for _, name := range []string{shadows.DefaultName, shadowName} {
+		_, err := svc.UpdateDesiredState(context.Background(), token, thingID, name, desiredState, 0)
+		require.Nil(t, err, fmt.Sprintf("unexpected error setting desired state of shadow %q: %s", name, err))
+	}
 
 	cases := []struct {
 		desc    string
@@ -207,6 +505,12 @@ func TestRemoveByThing(t *testing.T) {
 		err := svc.RemoveByThing(context.Background(), tc.thingID)
 		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
 	}
+
+	for _, name := range []string{shadows.DefaultName, shadowName} {
+		sh, err := svc.ViewShadow(context.Background(), token, thingID, name)
+		require.Nil(t, err, fmt.Sprintf("unexpected error viewing shadow %q: %s", name, err))
+		assert.Empty(t, sh.Desired, fmt.Sprintf("expected shadow %q to be removed, got desired %v", name, sh.Desired))
+	}
 }
 
 func toPayload(s shadows.State) []byte {
@@ -217,7 +521,7 @@ func toPayload(s shadows.State) []byte {
 func TestConsumeMessage(t *testing.T) {
 	svc := newService()
 
-	_, err := svc.UpdateDesiredState(context.Background(), token, thingID, desiredState)
+	_, err := svc.UpdateDesiredState(context.Background(), token, thingID, shadows.DefaultName, desiredState, 0)
 	require.Nil(t, err, fmt.Sprintf("unexpected error setting desired state: %s", err))
 
 	cases := []struct {
@@ -250,6 +554,18 @@ func TestConsumeMessage(t *testing.T) {
 			reported: shadows.State{"led": "off", "temp": "20"},
 			delta:    shadows.State{"led": "on"},
 		},
+		{
+			desc:     "consume message with nested object merges into reported state",
+			payload:  []byte(`{"led": "on", "network": {"wifi": {"ssid": "home"}}}`),
+			reported: shadows.State{"led": "on", "temp": "20", "network": map[string]any{"wifi": map[string]any{"ssid": "home"}}},
+			delta:    nil,
+		},
+		{
+			desc:     "consume message with nested object keeps sibling keys",
+			payload:  []byte(`{"network": {"wifi": {"channel": 6}, "ip": "10.0.0.2"}}`),
+			reported: shadows.State{"led": "on", "temp": "20", "network": map[string]any{"wifi": map[string]any{"ssid": "home", "channel": float64(6)}, "ip": "10.0.0.2"}},
+			delta:    nil,
+		},
 	}
 
 	for _, tc := range cases {
@@ -261,9 +577,454 @@ func TestConsumeMessage(t *testing.T) {
 		err := svc.ConsumeMessage("", msg)
 		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
 
-		sh, err := svc.ViewShadow(context.Background(), token, thingID)
+		sh, err := svc.ViewShadow(context.Background(), token, thingID, shadows.DefaultName)
 		require.Nil(t, err, fmt.Sprintf("%s: unexpected error viewing shadow: %s", tc.desc, err))
 		assert.Equal(t, tc.reported, sh.Reported, fmt.Sprintf("%s: expected reported %v got %v", tc.desc, tc.reported, sh.Reported))
 		assert.Equal(t, tc.delta, sh.Delta, fmt.Sprintf("%s: expected delta %v got %v", tc.desc, tc.delta, sh.Delta))
 	}
 }
+
+func TestConsumeMessageNestedDelta(t *testing.T) {
+	svc := newService()
+
+	desired := shadows.State{"network": map[string]any{"wifi": map[string]any{"ssid": "office", "channel": float64(6)}}, "modes": []any{"eco"}}
+	_, err := svc.UpdateDesiredState(context.Background(), token, thingID, shadows.DefaultName, desired, 0)
+	require.Nil(t, err, fmt.Sprintf("unexpected error setting desired state: %s", err))
+
+	cases := []struct {
+		desc    string
+		payload []byte
+		delta   shadows.State
+	}{
+		{
+			desc:    "consume message matching one nested leaf keeps only the other leaf in the delta",
+			payload: []byte(`{"network": {"wifi": {"ssid": "home", "channel": 6}}}`),
+			delta:   shadows.State{"network": map[string]any{"wifi": map[string]any{"ssid": "office"}}, "modes": []any{"eco"}},
+		},
+		{
+			desc:    "consume message with partially matching array keeps the whole array in the delta",
+			payload: []byte(`{"modes": ["eco", "boost"]}`),
+			delta:   shadows.State{"network": map[string]any{"wifi": map[string]any{"ssid": "office"}}, "modes": []any{"eco"}},
+		},
+		{
+			desc:    "consume message matching every leaf clears the delta",
+			payload: []byte(`{"network": {"wifi": {"ssid": "office"}}, "modes": ["eco"]}`),
+			delta:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.ConsumeMessage("", protomfx.Message{Publisher: thingID, Payload: tc.payload})
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+
+		sh, err := svc.ViewShadow(context.Background(), token, thingID, shadows.DefaultName)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error viewing shadow: %s", tc.desc, err))
+		assert.Equal(t, tc.delta, sh.Delta, fmt.Sprintf("%s: expected delta %v got %v", tc.desc, tc.delta, sh.Delta))
+	}
+}
+
+func TestConsumeCommand(t *testing.T) {
+	svc := newService()
+
+	_, err := svc.UpdateDesiredState(context.Background(), token, thingID, shadows.DefaultName, desiredState, 0)
+	require.Nil(t, err, fmt.Sprintf("unexpected error setting desired state: %s", err))
+
+	cases := []struct {
+		desc    string
+		payload []byte
+		desired shadows.State
+		fails   bool
+	}{
+		{
+			desc:    "consume command with patch merges into desired state",
+			payload: toPayload(shadows.State{"heater": "on"}),
+			desired: shadows.State{"led": "on", "heater": "on"},
+		},
+		{
+			desc:    "consume command with null value removes the key from desired state",
+			payload: []byte(`{"led": null}`),
+			desired: shadows.State{"heater": "on"},
+		},
+		{
+			desc:    "consume command with unchanged patch keeps desired state",
+			payload: toPayload(shadows.State{"heater": "on"}),
+			desired: shadows.State{"heater": "on"},
+		},
+		{
+			desc:    "consume command with invalid payload",
+			payload: []byte(`"on"`),
+			desired: shadows.State{"heater": "on"},
+			fails:   true,
+		},
+	}
+
+	for _, tc := range cases {
+		cmd := protomfx.Command{
+			RecipientID: thingID,
+			Payload:     tc.payload,
+		}
+
+		err := svc.ConsumeCommand("", cmd)
+		assert.Equal(t, tc.fails, err != nil, fmt.Sprintf("%s: unexpected error result: %v", tc.desc, err))
+
+		sh, err := svc.ViewShadow(context.Background(), token, thingID, shadows.DefaultName)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error viewing shadow: %s", tc.desc, err))
+		assert.Equal(t, tc.desired, sh.Desired, fmt.Sprintf("%s: expected desired %v got %v", tc.desc, tc.desired, sh.Desired))
+	}
+}
+
+func TestConsumeNamedShadow(t *testing.T) {
+	svc := newService()
+
+	_, err := svc.UpdateDesiredState(context.Background(), token, thingID, shadows.DefaultName, desiredState, 0)
+	require.Nil(t, err, fmt.Sprintf("unexpected error setting desired state: %s", err))
+
+	cases := []struct {
+		desc     string
+		msg      protomfx.Message
+		cmd      protomfx.Command
+		name     string
+		desired  shadows.State
+		reported shadows.State
+	}{
+		{
+			desc:    "consume command with shadow name subtopic updates the named shadow",
+			cmd:     protomfx.Command{RecipientID: thingID, Subtopic: shadowName, Payload: toPayload(shadows.State{"version": "1.2"})},
+			name:    shadowName,
+			desired: shadows.State{"version": "1.2"},
+		},
+		{
+			desc:     "consume message on named shadow subtopic reports the named shadow state",
+			msg:      protomfx.Message{Publisher: thingID, Subtopic: "shadow." + shadowName, Payload: toPayload(shadows.State{"version": "1.1"})},
+			name:     shadowName,
+			desired:  shadows.State{"version": "1.2"},
+			reported: shadows.State{"version": "1.1"},
+		},
+		{
+			desc:     "consume message on other subtopic reports the default shadow state",
+			msg:      protomfx.Message{Publisher: thingID, Subtopic: "telemetry", Payload: toPayload(shadows.State{"led": "off"})},
+			name:     shadows.DefaultName,
+			desired:  desiredState,
+			reported: shadows.State{"led": "off"},
+		},
+		{
+			desc:     "named shadow is kept apart from the default shadow",
+			name:     shadowName,
+			desired:  shadows.State{"version": "1.2"},
+			reported: shadows.State{"version": "1.1"},
+		},
+	}
+
+	for _, tc := range cases {
+		if tc.cmd.RecipientID != "" {
+			err := svc.ConsumeCommand("", tc.cmd)
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		}
+		if tc.msg.Publisher != "" {
+			err := svc.ConsumeMessage("", tc.msg)
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		}
+
+		sh, err := svc.ViewShadow(context.Background(), token, thingID, tc.name)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error viewing shadow: %s", tc.desc, err))
+		assert.Equal(t, tc.desired, sh.Desired, fmt.Sprintf("%s: expected desired %v got %v", tc.desc, tc.desired, sh.Desired))
+		assert.Equal(t, tc.reported, sh.Reported, fmt.Sprintf("%s: expected reported %v got %v", tc.desc, tc.reported, sh.Reported))
+	}
+
+	err = svc.ConsumeCommand("", protomfx.Command{RecipientID: thingID, Subtopic: "history", Payload: toPayload(desiredState)})
+	assert.True(t, errors.Contains(err, shadows.ErrInvalidName), fmt.Sprintf("consume command with reserved shadow name: expected %s got %s", shadows.ErrInvalidName, err))
+}
+
+func TestDeltaDelivery(t *testing.T) {
+	svc := newService()
+
+	cases := []struct {
+		desc     string
+		update   func() error
+		attempts uint64
+		acked    bool
+		version  uint64
+	}{
+		{
+			desc: "update desired state sends the delta",
+			update: func() error {
+				_, err := svc.UpdateDesiredState(context.Background(), token, thingID, shadows.DefaultName, desiredState, 0)
+				return err
+			},
+			attempts: 1,
+			version:  1,
+		},
+		{
+			desc: "consume diverging message before the redelivery backoff passed",
+			update: func() error {
+				return svc.ConsumeMessage("", protomfx.Message{Publisher: thingID, Payload: toPayload(shadows.State{"led": "off"})})
+			},
+			attempts: 1,
+			version:  1,
+		},
+		{
+			desc: "redeliver deltas on reconnect resends the delta",
+			update: func() error {
+				return svc.RedeliverDeltas(context.Background(), thingID)
+			},
+			attempts: 2,
+			version:  1,
+		},
+		{
+			desc: "consume message on ack subtopic acknowledges the delta",
+			update: func() error {
+				return svc.ConsumeMessage("", protomfx.Message{Publisher: thingID, Subtopic: "shadow.ack"})
+			},
+			attempts: 2,
+			acked:    true,
+			version:  1,
+		},
+		{
+			desc: "redeliver deltas on reconnect skips the acknowledged delta",
+			update: func() error {
+				return svc.RedeliverDeltas(context.Background(), thingID)
+			},
+			attempts: 2,
+			acked:    true,
+			version:  1,
+		},
+		{
+			desc: "update desired state starts a new delivery",
+			update: func() error {
+				_, err := svc.UpdateDesiredState(context.Background(), token, thingID, shadows.DefaultName, shadows.State{"led": "blink"}, 0)
+				return err
+			},
+			attempts: 1,
+			version:  2,
+		},
+		{
+			desc: "consume message matching desired state completes the delivery",
+			update: func() error {
+				return svc.ConsumeMessage("", protomfx.Message{Publisher: thingID, Payload: toPayload(shadows.State{"led": "blink"})})
+			},
+			attempts: 0,
+			version:  0,
+		},
+	}
+
+	for _, tc := range cases {
+		err := tc.update()
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+
+		sh, err := svc.ViewShadow(context.Background(), token, thingID, shadows.DefaultName)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error viewing shadow: %s", tc.desc, err))
+		d := sh.Delivery
+		assert.Equal(t, tc.attempts, d.Attempts, fmt.Sprintf("%s: expected %d attempts got %d", tc.desc, tc.attempts, d.Attempts))
+		assert.Equal(t, tc.acked, d.Acked, fmt.Sprintf("%s: expected acked %t got %t", tc.desc, tc.acked, d.Acked))
+		assert.Equal(t, tc.version, d.Version, fmt.Sprintf("%s: expected delivery version %d got %d", tc.desc, tc.version, d.Version))
+		assert.Equal(t, tc.attempts > 0, d.PendingSince > 0, fmt.Sprintf("%s: unexpected pending since %d", tc.desc, d.PendingSince))
+	}
+}
+
+func newBulkService() shadows.Service {
+	thingsSvc := pkgmocks.NewThingsServiceClient(
+		map[string]things.Profile{token: {ID: profileID, GroupID: groupID}},
+		map[string]things.Thing{
+			token:    {ID: thingID, GroupID: groupID},
+			thingID:  {ID: thingID, GroupID: groupID, ProfileID: profileID},
+			thingID2: {ID: thingID2, GroupID: groupID},
+		},
+		map[string]things.Group{token: {ID: groupID}},
+	)
+	repo := shmocks.NewShadowRepository()
+	pub := shmocks.NewCommandPublisher()
+	log := logger.NewMock()
+
+	return shadows.New(thingsSvc, repo, pub, shadows.HistoryConfig{}, log)
+}
+
+func TestPatchGroupDesiredState(t *testing.T) {
+	svc := newBulkService()
+
+	_, err := svc.UpdateDesiredState(context.Background(), token, thingID, shadows.DefaultName, desiredState, 0)
+	require.Nil(t, err, fmt.Sprintf("unexpected error setting desired state: %s", err))
+
+	cases := []struct {
+		desc    string
+		token   string
+		groupID string
+		patch   shadows.Patch
+		total   uint64
+		updated uint64
+		failed  []string
+		err     error
+	}{
+		{
+			desc:    "patch group desired state with merge patch",
+			token:   token,
+			groupID: groupID,
+			patch:   shadows.Patch{Merge: shadows.State{"rate": float64(5)}},
+			total:   2,
+			updated: 2,
+		},
+		{
+			desc:    "patch group desired state with operations failing for one thing",
+			token:   token,
+			groupID: groupID,
+			patch: shadows.Patch{Operations: []shadows.PatchOperation{
+				{Op: shadows.PatchOpTest, Path: "/led", Value: "on"},
+				{Op: shadows.PatchOpReplace, Path: "/led", Value: "off"},
+			}},
+			total:   2,
+			updated: 1,
+			failed:  []string{thingID2},
+		},
+		{
+			desc:    "patch group desired state with empty token",
+			token:   "",
+			groupID: groupID,
+			patch:   shadows.Patch{Merge: desiredState},
+			err:     errors.ErrAuthorization,
+		},
+		{
+			desc:    "patch group desired state for wrong group ID",
+			token:   token,
+			groupID: wrongID,
+			patch:   shadows.Patch{Merge: desiredState},
+			err:     errors.ErrAuthorization,
+		},
+	}
+
+	for _, tc := range cases {
+		bu, err := svc.PatchGroupDesiredState(context.Background(), tc.token, tc.groupID, shadows.DefaultName, tc.patch)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		if tc.err != nil {
+			continue
+		}
+
+		assert.Equal(t, tc.total, bu.Total, fmt.Sprintf("%s: expected total %d got %d", tc.desc, tc.total, bu.Total))
+		assert.Equal(t, tc.updated, bu.Updated, fmt.Sprintf("%s: expected %d updated got %d", tc.desc, tc.updated, bu.Updated))
+		var failed []string
+		for _, f := range bu.Failed {
+			failed = append(failed, f.ThingID)
+		}
+		assert.Equal(t, tc.failed, failed, fmt.Sprintf("%s: expected failed %v got %v", tc.desc, tc.failed, failed))
+	}
+
+	sh, err := svc.ViewShadow(context.Background(), token, thingID, shadows.DefaultName)
+	require.Nil(t, err, fmt.Sprintf("unexpected error viewing shadow: %s", err))
+	expected := shadows.State{"led": "off", "rate": float64(5)}
+	assert.Equal(t, expected, sh.Desired, fmt.Sprintf("expected desired %v got %v", expected, sh.Desired))
+}
+
+func TestPatchProfileDesiredState(t *testing.T) {
+	svc := newBulkService()
+
+	cases := []struct {
+		desc      string
+		token     string
+		profileID string
+		total     uint64
+		updated   uint64
+		err       error
+	}{
+		{
+			desc:      "patch profile desired state with valid token",
+			token:     token,
+			profileID: profileID,
+			total:     1,
+			updated:   1,
+		},
+		{
+			desc:      "patch profile desired state with empty token",
+			token:     "",
+			profileID: profileID,
+			err:       errors.ErrAuthorization,
+		},
+	}
+
+	for _, tc := range cases {
+		bu, err := svc.PatchProfileDesiredState(context.Background(), tc.token, tc.profileID, shadowName, shadows.Patch{Merge: desiredState})
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		assert.Equal(t, tc.total, bu.Total, fmt.Sprintf("%s: expected total %d got %d", tc.desc, tc.total, bu.Total))
+		assert.Equal(t, tc.updated, bu.Updated, fmt.Sprintf("%s: expected %d updated got %d", tc.desc, tc.updated, bu.Updated))
+	}
+
+	sh, err := svc.ViewShadow(context.Background(), token, thingID, shadowName)
+	require.Nil(t, err, fmt.Sprintf("unexpected error viewing shadow: %s", err))
+	assert.Equal(t, desiredState, sh.Desired, fmt.Sprintf("expected desired %v got %v", desiredState, sh.Desired))
+}
+
+func TestViewGroupSyncStatus(t *testing.T) {
+	svc := newBulkService()
+
+	patch := shadows.Patch{Merge: shadows.State{"led": "on", "cfg": map[string]any{"rate": float64(5)}}}
+	_, err := svc.PatchGroupDesiredState(context.Background(), token, groupID, shadows.DefaultName, patch)
+	require.Nil(t, err, fmt.Sprintf("unexpected error patching group desired state: %s", err))
+
+	cases := []struct {
+		desc    string
+		token   string
+		groupID string
+		report  func() error
+		status  shadows.SyncStatus
+		err     error
+	}{
+		{
+			desc:    "view sync status of group with all deltas pending",
+			token:   token,
+			groupID: groupID,
+			status: shadows.SyncStatus{
+				Total:       2,
+				Pending:     2,
+				PendingKeys: map[string]uint64{"/led": 2, "/cfg/rate": 2},
+			},
+		},
+		{
+			desc:    "view sync status of group with partially reported state",
+			token:   token,
+			groupID: groupID,
+			report: func() error {
+				return svc.ConsumeMessage("", protomfx.Message{Publisher: thingID, Payload: toPayload(shadows.State{"led": "on"})})
+			},
+			status: shadows.SyncStatus{
+				Total:       2,
+				Pending:     2,
+				PendingKeys: map[string]uint64{"/led": 1, "/cfg/rate": 2},
+			},
+		},
+		{
+			desc:    "view sync status of group with one thing in sync",
+			token:   token,
+			groupID: groupID,
+			report: func() error {
+				state := shadows.State{"led": "on", "cfg": map[string]any{"rate": float64(5)}}
+				return svc.ConsumeMessage("", protomfx.Message{Publisher: thingID2, Payload: toPayload(state)})
+			},
+			status: shadows.SyncStatus{
+				Total:       2,
+				InSync:      1,
+				Pending:     1,
+				PendingKeys: map[string]uint64{"/cfg/rate": 1},
+			},
+		},
+		{
+			desc:    "view sync status of group with empty token",
+			token:   "",
+			groupID: groupID,
+			err:     errors.ErrAuthorization,
+		},
+		{
+			desc:    "view sync status of wrong group ID",
+			token:   token,
+			groupID: wrongID,
+			err:     errors.ErrAuthorization,
+		},
+	}
+
+	for _, tc := range cases {
+		if tc.report != nil {
+			err := tc.report()
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error reporting state: %s", tc.desc, err))
+		}
+
+		status, err := svc.ViewGroupSyncStatus(context.Background(), tc.token, tc.groupID, shadows.DefaultName)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		assert.Equal(t, tc.status, status, fmt.Sprintf("%s: expected status %v got %v", tc.desc, tc.status, status))
+	}
+}
@@ -6,36 +6,155 @@ package shadows
 import (
 	"context"
 	"maps"
+	"time"
+
+	"github.com/MainfluxLabs/mainflux/pkg/errors"
+)
+
+const (
+	StateTypeDesired  = "desired"
+	StateTypeReported = "reported"
+
+	// DefaultName is the name of the default, unnamed, shadow of a thing.
+	DefaultName = ""
+	// historyName is reserved, since it clashes with the shadow history API route.
+	historyName = "history"
+	// ackName is reserved, since it clashes with the default shadow's ack subtopic.
+	ackName       = "ack"
+	maxNameLength = 64
+)
+
+var (
+	// ErrVersionConflict indicates a conditional desired state update of a shadow
+	// whose version no longer matches the expected one.
+	ErrVersionConflict = errors.New("shadow version conflict")
+
+	// ErrInvalidName indicates an invalid shadow name.
+	ErrInvalidName = errors.New("invalid shadow name")
 )
 
 // State is a free-form set of key/value pairs describing device state.
 type State map[string]any
 
-// Shadow is the persisted state of a single thing.
-// Delta is derived from Desired and Reported and populated
-// by the service on read.
+// Shadow is the persisted state of a single thing, or of one of its
+// components in case of a named shadow. Delta is derived from Desired
+// and Reported and populated by the service on read.
 type Shadow struct {
-	ThingID    string
+	ThingID string
+	// Name identifies one of the shadows of the thing. It is empty for the default shadow.
+	Name       string
 	Desired    State
 	Reported   State
 	Delta      State
 	ReportedAt int64
 	UpdatedAt  int64
+	// Version is incremented on every desired state update.
+	Version uint64
+	// Delivery tracks the delivery of the delta to the device.
+	Delivery Delivery
+}
+
+// Delivery tracks the delivery of a shadow's delta to the device. A delta
+// is identified by the shadow version it was computed for, so every desired
+// state update starts a new delivery.
+type Delivery struct {
+	// Version is the shadow version of the delivered delta.
+	Version uint64
+	// PendingSince is the time (unix seconds) the delta was first sent.
+	PendingSince int64
+	// SentAt is the time (unix seconds) the delta was last sent.
+	SentAt int64
+	// Attempts is the number of times the delta was sent.
+	Attempts uint64
+	// Acked reports whether the device acknowledged the delta.
+	Acked bool
+}
+
+// StateChange records a change of the desired or reported state of a shadow.
+type StateChange struct {
+	ThingID string
+	Name    string
+	// Type is the changed state, desired or reported.
+	Type  string
+	State State
+	// Version is the shadow version after the change.
+	Version uint64
+	Created int64
 }
 
-// ShadowRepository specifies the persistence API for shadows.
+// HistoryPage contains a page of shadow state changes.
+type HistoryPage struct {
+	Total   uint64
+	History []StateChange
+}
+
+// PageMetadata contains page metadata that helps navigation.
+type PageMetadata struct {
+	Total  uint64
+	Offset uint64
+	Limit  uint64
+	Dir    string
+	// Type filters the state changes by the changed state, desired or reported.
+	Type string
+}
+
+// ShadowRepository specifies the persistence API for shadows. Shadows are
+// identified by the thing ID and the shadow name.
 type ShadowRepository interface {
-	// UpsertDesiredState sets the thing's desired state.
-	UpsertDesiredState(ctx context.Context, thingID string, desired State, updatedAt int64) (Shadow, error)
+	// UpsertDesiredState sets the shadow's desired state, increments the shadow version
+	// and records the change in the shadow history. A non-zero version makes the update
+	// conditional: it fails with ErrVersionConflict unless the shadow has that version.
+	UpsertDesiredState(ctx context.Context, thingID, name string, desired State, updatedAt int64, version uint64) (Shadow, error)
+
+	// UpsertReportedState sets the shadow's reported state and records the change
+	// in the shadow history.
+	UpsertReportedState(ctx context.Context, thingID, name string, reported State, reportedAt int64) error
+
+	// UpdateDelivery sets the delta delivery of the thing's shadow having the given name.
+	UpdateDelivery(ctx context.Context, thingID, name string, delivery Delivery) error
+
+	// RetrieveByThing returns the thing's shadow having the given name.
+	RetrieveByThing(ctx context.Context, thingID, name string) (Shadow, error)
+
+	// RetrieveByThings returns the stored shadows having the given name of the given things.
+	RetrieveByThings(ctx context.Context, thingIDs []string, name string) ([]Shadow, error)
+
+	// RetrieveAllByThing returns all the stored shadows of the thing.
+	RetrieveAllByThing(ctx context.Context, thingID string) ([]Shadow, error)
+
+	// RetrieveHistory returns a page of the state changes of the thing's shadow having the given name.
+	RetrieveHistory(ctx context.Context, thingID, name string, pm PageMetadata) (HistoryPage, error)
 
-	// UpsertReportedState sets the thing's reported state.
-	UpsertReportedState(ctx context.Context, thingID string, reported State, reportedAt int64) error
+	// Remove deletes the thing's shadow having the given name, along with its history.
+	Remove(ctx context.Context, thingID, name string) error
 
-	// RetrieveByThing returns the shadow for the given thing ID.
-	RetrieveByThing(ctx context.Context, thingID string) (Shadow, error)
+	// RemoveByThing deletes all the shadows of the thing, along with their history.
+	RemoveByThing(ctx context.Context, thingID string) error
 
-	// Remove deletes the shadow for the given thing ID.
-	Remove(ctx context.Context, thingID string) error
+	// PruneHistory removes the state changes recorded before the given time, unless it's
+	// zero, and all but the latest keep state changes of each shadow, unless keep is zero.
+	PruneHistory(ctx context.Context, before time.Time, keep uint64) error
+}
+
+// IsValidName reports whether name is a valid shadow name. Names are used
+// as a subtopic token, so they may only contain letters, digits, '-' and '_'.
+// The empty name identifies the default shadow.
+func IsValidName(name string) bool {
+	if name == DefaultName {
+		return true
+	}
+	if len(name) > maxNameLength || name == historyName || name == ackName {
+		return false
+	}
+
+	for _, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_':
+		default:
+			return false
+		}
+	}
+	return true
 }
 
 // equalState reports whether two JSON-shaped shadow values are deeply equal.
@@ -70,33 +189,70 @@ func equalState(a, b any) bool {
 }
 
 // computeDelta returns the subset of desired that the reported state has not
-// yet matched: keys present in desired whose value differs from, or is absent
-// in, reported. Keys present only in reported are not part of the delta. The
-// result is nil when desired and reported already agree on every desired key.
+// yet matched. Nested objects are compared leaf by leaf, so the delta only
+// holds the desired leaves whose value differs from, or is absent in, reported,
+// along with their parent objects. Arrays are compared as a whole. Keys present
+// only in reported are not part of the delta. The result is nil when desired
+// and reported already agree on every desired leaf.
 func computeDelta(desired, reported State) State {
-	var delta State
-	for k, dv := range desired {
-		rv, ok := reported[k]
-		if ok && equalState(dv, rv) {
-			continue
+	delta, ok := diffValue(map[string]any(desired), map[string]any(reported))
+	if !ok {
+		return nil
+	}
+	return State(delta.(map[string]any))
+}
+
+// diffValue returns the part of desired that differs from reported, and
+// reports whether there is any difference.
+func diffValue(desired, reported any) (any, bool) {
+	dm, dok := desired.(map[string]any)
+	rm, rok := reported.(map[string]any)
+	if !dok || !rok {
+		return desired, !equalState(desired, reported)
+	}
+
+	var delta map[string]any
+	for k, dv := range dm {
+		rv, ok := rm[k]
+		if ok {
+			d, diff := diffValue(dv, rv)
+			if !diff {
+				continue
+			}
+			dv = d
 		}
 		if delta == nil {
-			delta = State{}
+			delta = map[string]any{}
 		}
 		delta[k] = dv
 	}
-	return delta
+	return delta, delta != nil
 }
 
 // mergeState applies patch onto base and reports whether anything changed.
-// A nil value in patch deletes the corresponding key. The merge is shallow:
-// a non-nil value replaces the existing value for that key outright. base is
-// not mutated; the merged copy is returned.
+// The merge is deep, following RFC 7386 (JSON Merge Patch): a nested object
+// in patch is merged into the corresponding object of base, a nil value
+// deletes the corresponding key, and any other value replaces the existing
+// value for that key outright. base is not mutated; the merged copy is returned.
 func mergeState(base, patch State) (merged State, changed bool) {
-	merged = State{}
-	maps.Copy(merged, base)
+	m, changed := mergeValue(map[string]any(base), map[string]any(patch))
+	return State(m.(map[string]any)), changed
+}
+
+// mergeValue merges patch into cur and reports whether cur changed.
+// A non-object cur is replaced by an object when patch is an object.
+func mergeValue(cur, patch any) (any, bool) {
+	pm, ok := patch.(map[string]any)
+	if !ok {
+		return patch, !equalState(cur, patch)
+	}
 
-	for k, v := range patch {
+	cm, ok := cur.(map[string]any)
+	changed := !ok
+	merged := map[string]any{}
+	maps.Copy(merged, cm)
+
+	for k, v := range pm {
 		if v == nil {
 			if _, ok := merged[k]; ok {
 				delete(merged, k)
@@ -104,8 +260,10 @@ func mergeState(base, patch State) (merged State, changed bool) {
 			}
 			continue
 		}
-		if cur, ok := merged[k]; !ok || !equalState(cur, v) {
-			merged[k] = v
+
+		mv, c := mergeValue(merged[k], v)
+		if c {
+			merged[k] = mv
 			changed = true
 		}
 	}
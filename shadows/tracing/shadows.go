@@ -2,6 +2,7 @@ package tracing
 
 import (
 	"context"
+	"time"
 
 	"github.com/MainfluxLabs/mainflux/pkg/dbutil"
 	"github.com/MainfluxLabs/mainflux/shadows"
@@ -11,8 +12,14 @@ import (
 const (
 	upsertDesiredState    = "upsert_desired_state"
 	upsertReportedState   = "upsert_reported_state"
+	updateDelivery        = "update_delivery"
 	retrieveShadowByThing = "retrieve_shadow_by_thing"
+	retrieveAllByThing    = "retrieve_all_shadows_by_thing"
+	retrieveByThings      = "retrieve_shadows_by_things"
+	retrieveShadowHistory = "retrieve_shadow_history"
 	removeShadow          = "remove_shadow"
+	removeShadowsByThing  = "remove_shadows_by_thing"
+	pruneShadowHistory    = "prune_shadow_history"
 )
 
 var _ shadows.ShadowRepository = (*shadowRepositoryMiddleware)(nil)
@@ -30,34 +37,82 @@ func ShadowRepositoryMiddleware(tracer opentracing.Tracer, repo shadows.ShadowRe
 	}
 }
 
-func (srm shadowRepositoryMiddleware) UpsertDesiredState(ctx context.Context, thingID string, desired shadows.State, updatedAt int64) (shadows.Shadow, error) {
+func (srm shadowRepositoryMiddleware) UpsertDesiredState(ctx context.Context, thingID, name string, desired shadows.State, updatedAt int64, version uint64) (shadows.Shadow, error) {
 	span := dbutil.CreateSpan(ctx, srm.tracer, upsertDesiredState)
 	defer span.Finish()
 	ctx = opentracing.ContextWithSpan(ctx, span)
 
-	return srm.repo.UpsertDesiredState(ctx, thingID, desired, updatedAt)
+	return srm.repo.UpsertDesiredState(ctx, thingID, name, desired, updatedAt, version)
 }
 
-func (srm shadowRepositoryMiddleware) UpsertReportedState(ctx context.Context, thingID string, reported shadows.State, reportedAt int64) error {
+func (srm shadowRepositoryMiddleware) UpsertReportedState(ctx context.Context, thingID, name string, reported shadows.State, reportedAt int64) error {
 	span := dbutil.CreateSpan(ctx, srm.tracer, upsertReportedState)
 	defer span.Finish()
 	ctx = opentracing.ContextWithSpan(ctx, span)
 
-	return srm.repo.UpsertReportedState(ctx, thingID, reported, reportedAt)
+	return srm.repo.UpsertReportedState(ctx, thingID, name, reported, reportedAt)
 }
 
-func (srm shadowRepositoryMiddleware) RetrieveByThing(ctx context.Context, thingID string) (shadows.Shadow, error) {
+func (srm shadowRepositoryMiddleware) UpdateDelivery(ctx context.Context, thingID, name string, delivery shadows.Delivery) error {
+	span := dbutil.CreateSpan(ctx, srm.tracer, updateDelivery)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return srm.repo.UpdateDelivery(ctx, thingID, name, delivery)
+}
+
+func (srm shadowRepositoryMiddleware) RetrieveByThings(ctx context.Context, thingIDs []string, name string) ([]shadows.Shadow, error) {
+	span := dbutil.CreateSpan(ctx, srm.tracer, retrieveByThings)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return srm.repo.RetrieveByThings(ctx, thingIDs, name)
+}
+
+func (srm shadowRepositoryMiddleware) RetrieveAllByThing(ctx context.Context, thingID string) ([]shadows.Shadow, error) {
+	span := dbutil.CreateSpan(ctx, srm.tracer, retrieveAllByThing)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return srm.repo.RetrieveAllByThing(ctx, thingID)
+}
+
+func (srm shadowRepositoryMiddleware) RetrieveByThing(ctx context.Context, thingID, name string) (shadows.Shadow, error) {
 	span := dbutil.CreateSpan(ctx, srm.tracer, retrieveShadowByThing)
 	defer span.Finish()
 	ctx = opentracing.ContextWithSpan(ctx, span)
 
-	return srm.repo.RetrieveByThing(ctx, thingID)
+	return srm.repo.RetrieveByThing(ctx, thingID, name)
 }
 
-func (srm shadowRepositoryMiddleware) Remove(ctx context.Context, thingID string) error {
+func (srm shadowRepositoryMiddleware) RetrieveHistory(ctx context.Context, thingID, name string, pm shadows.PageMetadata) (shadows.HistoryPage, error) {
+	span := dbutil.CreateSpan(ctx, srm.tracer, retrieveShadowHistory)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return srm.repo.RetrieveHistory(ctx, thingID, name, pm)
+}
+
+func (srm shadowRepositoryMiddleware) Remove(ctx context.Context, thingID, name string) error {
 	span := dbutil.CreateSpan(ctx, srm.tracer, removeShadow)
 	defer span.Finish()
 	ctx = opentracing.ContextWithSpan(ctx, span)
 
-	return srm.repo.Remove(ctx, thingID)
+	return srm.repo.Remove(ctx, thingID, name)
+}
+
+func (srm shadowRepositoryMiddleware) RemoveByThing(ctx context.Context, thingID string) error {
+	span := dbutil.CreateSpan(ctx, srm.tracer, removeShadowsByThing)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return srm.repo.RemoveByThing(ctx, thingID)
+}
+
+func (srm shadowRepositoryMiddleware) PruneHistory(ctx context.Context, before time.Time, keep uint64) error {
+	span := dbutil.CreateSpan(ctx, srm.tracer, pruneShadowHistory)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return srm.repo.PruneHistory(ctx, before, keep)
 }
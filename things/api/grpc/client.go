@@ -35,6 +35,7 @@ type grpcClient struct {
 	getGroupIDByProfile    endpoint.Endpoint
 	getGroupIDsByOrg       endpoint.Endpoint
 	getThingIDsByProfile   endpoint.Endpoint
+	getThingIDsByGroup     endpoint.Endpoint
 	createGroupMemberships endpoint.Endpoint
 	getGroup               endpoint.Endpoint
 	getKeyByThingID        endpoint.Endpoint
@@ -150,6 +151,14 @@ func NewClient(conn *grpc.ClientConn, tracer opentracing.Tracer, timeout time.Du
 			decodeGetThingIDsResponse,
 			protomfx.ThingIDs{},
 		).Endpoint()),
+		getThingIDsByGroup: kitot.TraceClient(tracer, "get_thing_ids_by_group")(kitgrpc.NewClient(
+			conn,
+			svcName,
+			"GetThingIDsByGroup",
+			encodeGetThingIDsByGroupRequest,
+			decodeGetThingIDsResponse,
+			protomfx.ThingIDs{},
+		).Endpoint()),
 		createGroupMemberships: kitot.TraceClient(tracer, "create_group_memebrships")(kitgrpc.NewClient(
 			conn,
 			svcName,
@@ -316,6 +325,20 @@ func (client grpcClient) GetThingIDsByProfile(ctx context.Context, profileID str
 	return ids.thingIDs, nil
 }
 
+func (client grpcClient) GetThingIDsByGroup(ctx context.Context, groupID string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, client.timeout)
+	defer cancel()
+
+	res, err := client.getThingIDsByGroup(ctx, groupIDReq{groupID: groupID})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := res.(thingIDsRes)
+
+	return ids.thingIDs, nil
+}
+
 func (client grpcClient) CreateGroupMemberships(ctx context.Context, memberships ...domain.GroupMembership) error {
 	ctx, cancel := context.WithTimeout(ctx, client.timeout)
 	defer cancel()
@@ -432,6 +455,13 @@ func encodeGetThingIDsByProfileRequest(_ context.Context, grpcReq any) (any, err
 	}, nil
 }
 
+func encodeGetThingIDsByGroupRequest(_ context.Context, grpcReq any) (any, error) {
+	req := grpcReq.(groupIDReq)
+	return &protomfx.GroupID{
+		Value: req.groupID,
+	}, nil
+}
+
 func encodeCreateGroupMembershipsRequest(_ context.Context, grpcReq any) (any, error) {
 	req := grpcReq.(createGroupMembershipsReq)
 
@@ -156,6 +156,18 @@ func (req profileIDReq) validate() error {
 	return nil
 }
 
+type groupIDReq struct {
+	groupID string
+}
+
+func (req groupIDReq) validate() error {
+	if req.groupID == "" {
+		return apiutil.ErrMissingGroupID
+	}
+
+	return nil
+}
+
 type orgAccessReq struct {
 	orgID string
 	token string
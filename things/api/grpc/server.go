@@ -36,6 +36,7 @@ type grpcServer struct {
 	getGroupIDByProfile    kitgrpc.Handler
 	getGroupIDsByOrg       kitgrpc.Handler
 	getThingIDsByProfile   kitgrpc.Handler
+	getThingIDsByGroup     kitgrpc.Handler
 	createGroupMemberships kitgrpc.Handler
 	getGroup               kitgrpc.Handler
 	getKeyByThingID        kitgrpc.Handler
@@ -107,7 +108,12 @@ func NewServer(tracer opentracing.Tracer, svc things.Service) protomfx.ThingsSer
 		getThingIDsByProfile: kitgrpc.NewServer(
 			kitot.TraceServer(tracer, "get_thing_ids_by_profile")(getThingIDsByProfileEndpoint(svc)),
 			decodeGetThingIDsByProfileRequest,
-			encodeGetThingIDsByProfileResponse,
+			encodeGetThingIDsResponse,
+		),
+		getThingIDsByGroup: kitgrpc.NewServer(
+			kitot.TraceServer(tracer, "get_thing_ids_by_group")(getThingIDsByGroupEndpoint(svc)),
+			decodeGetThingIDsByGroupRequest,
+			encodeGetThingIDsResponse,
 		),
 		createGroupMemberships: kitgrpc.NewServer(
 			kitot.TraceServer(tracer, "create_group_memberships")(createGroupMembershipsEndpoint(svc)),
@@ -241,6 +247,14 @@ func (gs *grpcServer) GetThingIDsByProfile(ctx context.Context, req *protomfx.Pr
 	return res.(*protomfx.ThingIDs), nil
 }
 
+func (gs *grpcServer) GetThingIDsByGroup(ctx context.Context, req *protomfx.GroupID) (*protomfx.ThingIDs, error) {
+	_, res, err := gs.getThingIDsByGroup.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+	return res.(*protomfx.ThingIDs), nil
+}
+
 func (gs *grpcServer) CreateGroupMemberships(ctx context.Context, req *protomfx.CreateGroupMembershipsReq) (*emptypb.Empty, error) {
 	_, res, err := gs.createGroupMemberships.ServeGRPC(ctx, req)
 	if err != nil {
@@ -339,6 +353,11 @@ func decodeGetThingIDsByProfileRequest(_ context.Context, grpcReq any) (any, err
 	return profileIDReq{profileID: req.GetValue()}, nil
 }
 
+func decodeGetThingIDsByGroupRequest(_ context.Context, grpcReq any) (any, error) {
+	req := grpcReq.(*protomfx.GroupID)
+	return groupIDReq{groupID: req.GetValue()}, nil
+}
+
 func decodeCreateGroupMembershipsRequest(_ context.Context, grpcReq any) (any, error) {
 	req := grpcReq.(*protomfx.CreateGroupMembershipsReq)
 	memberships := req.GetMemberships()
@@ -405,7 +424,7 @@ func encodeGetGroupIDsByOrgResponse(_ context.Context, grpcRes any) (any, error)
 	return &protomfx.GroupIDs{Ids: res.groupIDs}, nil
 }
 
-func encodeGetThingIDsByProfileResponse(_ context.Context, grpcRes any) (any, error) {
+func encodeGetThingIDsResponse(_ context.Context, grpcRes any) (any, error) {
 	res := grpcRes.(thingIDsRes)
 	return &protomfx.ThingIDs{Ids: res.thingIDs}, nil
 }
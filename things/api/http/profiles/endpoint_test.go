@@ -148,6 +148,7 @@ func TestCreateProfiles(t *testing.T) {
 
 	data := `[{"name": "1"}, {"name": "2"}]`
 	invalidData := fmt.Sprintf(`[{"name": "%s"}]`, invalidName)
+	invalidTransformData := `[{"name": "1", "config": {"transform_script_id": "invalid"}}]`
 
 	cases := []struct {
 		desc        string
@@ -220,6 +221,14 @@ func TestCreateProfiles(t *testing.T) {
 			status:      http.StatusBadRequest,
 			response:    emptyValue,
 		},
+		{
+			desc:        "create profile with invalid transform script ID",
+			data:        invalidTransformData,
+			contentType: contentTypeJSON,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			response:    emptyValue,
+		},
 	}
 
 	for _, tc := range cases {
@@ -51,6 +51,10 @@ func (req createProfilesReq) validate() error {
 		if profile.Name == "" || len(profile.Name) > maxNameSize {
 			return apiutil.ErrNameSize
 		}
+
+		if err := validateConfig(profile.Config); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -77,7 +81,15 @@ func (req updateProfileReq) validate() error {
 		return apiutil.ErrNameSize
 	}
 
-	return nil
+	return validateConfig(req.Config)
+}
+
+func validateConfig(config *domain.ProfileConfig) error {
+	if config == nil || config.TransformScriptID == "" {
+		return nil
+	}
+
+	return apiutil.ValidateUUID(config.TransformScriptID)
 }
 
 type viewByThingReq struct {
@@ -362,6 +362,9 @@ func toDBProfile(pr things.Profile) dbProfile {
 			"webhook_enabled": pr.Config.WebhookEnabled,
 			"rule_enabled":    pr.Config.RuleEnabled,
 		}
+		if pr.Config.TransformScriptID != "" {
+			config["transform_script_id"] = pr.Config.TransformScriptID
+		}
 	}
 
 	return dbProfile{
@@ -423,6 +426,10 @@ func toProfile(dbpr dbProfile) (things.Profile, error) {
 		if !ok {
 			cfg.RuleEnabled = true
 		}
+
+		if id, ok := dbpr.Config["transform_script_id"].(string); ok {
+			cfg.TransformScriptID = id
+		}
 	}
 
 	return things.Profile{
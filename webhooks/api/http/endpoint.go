@@ -26,6 +26,12 @@ func createWebhooksEndpoint(svc webhooks.Service) endpoint.Endpoint {
 				Url:      wReq.Url,
 				Headers:  wReq.Headers,
 				Metadata: wReq.Metadata,
+				Method:   wReq.Method,
+				Template: wReq.Template,
+				Format:   wReq.Format,
+				Filter:   wReq.Filter,
+				Batch:    wReq.Batch,
+				Secret:   wReq.Secret,
 			}
 			whs = append(whs, wh)
 		}
@@ -100,6 +106,11 @@ func updateWebhookEndpoint(svc webhooks.Service) endpoint.Endpoint {
 			Url:      req.Url,
 			Headers:  req.Headers,
 			Metadata: req.Metadata,
+			Method:   req.Method,
+			Template: req.Template,
+			Format:   req.Format,
+			Filter:   req.Filter,
+			Batch:    req.Batch,
 		}
 
 		if err := svc.UpdateWebhook(ctx, req.token, webhook); err != nil {
@@ -125,6 +136,103 @@ func removeWebhooksEndpoint(svc webhooks.Service) endpoint.Endpoint {
 	}
 }
 
+func enableWebhookEndpoint(svc webhooks.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(webhookReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.EnableWebhook(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return apiutil.EmptyRes{StatusCode: http.StatusNoContent}, nil
+	}
+}
+
+func disableWebhookEndpoint(svc webhooks.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(webhookReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.DisableWebhook(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return apiutil.EmptyRes{StatusCode: http.StatusNoContent}, nil
+	}
+}
+
+func rotateWebhookSecretEndpoint(svc webhooks.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(webhookReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		wh, err := svc.RotateWebhookSecret(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return secretRes{
+			Secret:                  wh.Secret,
+			PreviousSecretExpiresAt: wh.PreviousSecretExpiresAt,
+		}, nil
+	}
+}
+
+func listDeliveriesEndpoint(svc webhooks.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(listDeliveriesReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListDeliveries(ctx, req.token, req.id, req.pageMetadata)
+		if err != nil {
+			return nil, err
+		}
+
+		return buildDeliveriesPageResponse(page, req.pageMetadata), nil
+	}
+}
+
+func listDeadLettersEndpoint(svc webhooks.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(listDeliveriesReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListDeadLetters(ctx, req.token, req.id, req.pageMetadata)
+		if err != nil {
+			return nil, err
+		}
+
+		return buildDeadLettersPageResponse(page, req.pageMetadata), nil
+	}
+}
+
+func replayDeadLettersEndpoint(svc webhooks.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(replayDeadLettersReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res, err := svc.ReplayDeadLetters(ctx, req.token, req.id, req.DeadLetterIDs...)
+		if err != nil {
+			return nil, err
+		}
+
+		return replayRes{Total: res.Total, Delivered: res.Delivered, Remaining: res.Remaining}, nil
+	}
+}
+
 func buildWebhooksPageResponse(wp webhooks.WebhooksPage, pm webhooks.PageMetadata) WebhooksPageRes {
 	res := WebhooksPageRes{
 		pageRes: pageRes{
@@ -147,6 +255,13 @@ func buildWebhooksPageResponse(wp webhooks.WebhooksPage, pm webhooks.PageMetadat
 			Url:        wh.Url,
 			ResHeaders: wh.Headers,
 			Metadata:   wh.Metadata,
+			Method:     wh.Method,
+			Template:   wh.Template,
+			Format:     wh.Format,
+			Filter:     wh.Filter,
+			Batch:      wh.Batch,
+			Status:     wh.Status,
+			Failures:   wh.Failures,
 		}
 		res.Webhooks = append(res.Webhooks, webhook)
 	}
@@ -165,6 +280,14 @@ func buildWebhooksResponse(webhooks []webhooks.Webhook, created bool) webhooksRe
 			Url:        wh.Url,
 			ResHeaders: wh.Headers,
 			Metadata:   wh.Metadata,
+			Method:     wh.Method,
+			Template:   wh.Template,
+			Format:     wh.Format,
+			Filter:     wh.Filter,
+			Batch:      wh.Batch,
+			Status:     wh.Status,
+			Failures:   wh.Failures,
+			Secret:     wh.Secret,
 		}
 		res.Webhooks = append(res.Webhooks, webhook)
 	}
@@ -181,8 +304,69 @@ func buildWebhookResponse(webhook webhooks.Webhook, updated bool) webhookRespons
 		Url:        webhook.Url,
 		ResHeaders: webhook.Headers,
 		Metadata:   webhook.Metadata,
+		Method:     webhook.Method,
+		Template:   webhook.Template,
+		Format:     webhook.Format,
+		Filter:     webhook.Filter,
+		Batch:      webhook.Batch,
+		Status:     webhook.Status,
+		Failures:   webhook.Failures,
 		updated:    updated,
 	}
 
 	return wh
 }
+
+func buildDeliveriesPageResponse(dp webhooks.DeliveriesPage, pm webhooks.PageMetadata) deliveriesPageRes {
+	res := deliveriesPageRes{
+		pageRes: pageRes{
+			Total:  dp.Total,
+			Offset: pm.Offset,
+			Limit:  pm.Limit,
+			Dir:    pm.Dir,
+		},
+		Deliveries: []deliveryRes{},
+	}
+
+	for _, d := range dp.Deliveries {
+		res.Deliveries = append(res.Deliveries, deliveryRes{
+			ID:         d.ID,
+			ThingID:    d.ThingID,
+			Messages:   d.Messages,
+			Status:     d.Status,
+			StatusCode: d.StatusCode,
+			Latency:    d.Latency.Milliseconds(),
+			Attempts:   d.Attempts,
+			Response:   d.Response,
+			Error:      d.Error,
+			Created:    d.Created,
+		})
+	}
+
+	return res
+}
+
+func buildDeadLettersPageResponse(dlp webhooks.DeadLettersPage, pm webhooks.PageMetadata) deadLettersPageRes {
+	res := deadLettersPageRes{
+		pageRes: pageRes{
+			Total:  dlp.Total,
+			Offset: pm.Offset,
+			Limit:  pm.Limit,
+			Dir:    pm.Dir,
+		},
+		DeadLetters: []deadLetterRes{},
+	}
+
+	for _, dl := range dlp.DeadLetters {
+		res.DeadLetters = append(res.DeadLetters, deadLetterRes{
+			ID:       dl.ID,
+			ThingID:  dl.Message.ThingId,
+			Payload:  dl.Message.Payload,
+			Error:    dl.Error,
+			Attempts: dl.Attempts,
+			Created:  dl.Created,
+		})
+	}
+
+	return res
+}
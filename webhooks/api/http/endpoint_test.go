@@ -11,10 +11,13 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/MainfluxLabs/mainflux/logger"
 	"github.com/MainfluxLabs/mainflux/pkg/apiutil"
+	"github.com/MainfluxLabs/mainflux/pkg/dbutil"
 	"github.com/MainfluxLabs/mainflux/pkg/mocks"
+	protomfx "github.com/MainfluxLabs/mainflux/pkg/proto"
 	"github.com/MainfluxLabs/mainflux/pkg/uuid"
 	"github.com/MainfluxLabs/mainflux/things"
 	"github.com/MainfluxLabs/mainflux/webhooks"
@@ -52,6 +55,7 @@ const (
 )
 
 var (
+	deliveryConfig  = webhooks.DeliveryConfig{MaxAttempts: 3, RetryInterval: time.Millisecond, MaxRetryInterval: time.Millisecond, DisableAfter: 2, SecretGracePeriod: time.Hour}
 	headers         = map[string]string{"Content-Type:": "application/json"}
 	webhook         = webhooks.Webhook{Name: "test-webhook", Url: "https://test.webhook.com", Headers: headers, Metadata: map[string]any{"test": "data"}}
 	invalidIDRes    = toJSON(apiutil.ErrorRes{Err: httpapi.ErrMissingWebhookID.Error()})
@@ -74,10 +78,13 @@ func toJSON(data any) string {
 func newService() webhooks.Service {
 	ths := mocks.NewThingsServiceClient(nil, map[string]things.Thing{thingID: {ID: thingID, GroupID: groupID}, token: {ID: thingID, GroupID: groupID}}, map[string]things.Group{token: {ID: groupID}})
 	webhookRepo := whmocks.NewWebhookRepository()
+	deliveryRepo := whmocks.NewDeliveryRepository()
+	deadLetterRepo := whmocks.NewDeadLetterRepository()
+	pendingRepo := whmocks.NewPendingMessageRepository()
 	forwarder := whmocks.NewForwarder()
 	idProvider := uuid.NewMock()
 
-	return webhooks.New(ths, webhookRepo, forwarder, idProvider)
+	return webhooks.New(ths, webhookRepo, deliveryRepo, deadLetterRepo, pendingRepo, forwarder, idProvider, deliveryConfig, logger.NewMock())
 }
 
 type testRequest struct {
@@ -114,6 +121,18 @@ func TestCreateWebhooks(t *testing.T) {
 	validData := `[{"name":"value","url":"https://api.example.com","headers":{"Content-Type":"application/json"}}]`
 	invalidName := fmt.Sprintf(`[{"name":"%s","url":"https://api.example.com","headers":{"Content-Type":"application/json"}}]`, emptyValue)
 	invalidUrl := fmt.Sprintf(`[{"name":"value","url":"%s","headers":{"Content-Type":"application/json"}}]`, invalidUrl)
+	secretData := `[{"name":"secret-value","url":"https://api.example.com","secret":"whsec_0123456789abcdef"}]`
+	invalidSecret := `[{"name":"value","url":"https://api.example.com","secret":"short"}]`
+	templateData := `[{"name":"template-value","url":"https://api.example.com","method":"PUT","template":"{\"text\":\"{{.ThingID}}\"}","format":"xml"}]`
+	invalidMethod := `[{"name":"value","url":"https://api.example.com","method":"GET"}]`
+	invalidFormat := `[{"name":"value","url":"https://api.example.com","format":"yaml"}]`
+	invalidTemplate := `[{"name":"value","url":"https://api.example.com","template":"{{.ThingID"}]`
+	filterData := `[{"name":"filter-value","url":"https://api.example.com","filter":{"subtopics":["sensors/+/temp","alerts/#"],"conditions":[{"field":"temp","comparator":">","value":30}]}}]`
+	invalidSubtopicFilter := `[{"name":"value","url":"https://api.example.com","filter":{"subtopics":["alerts/#/fire"]}}]`
+	batchData := `[{"name":"batch-value","url":"https://api.example.com","batch":true}]`
+	invalidBatch := `[{"name":"value","url":"https://api.example.com","batch":true,"format":"xml"}]`
+	invalidConditionFilter := `[{"name":"value","url":"https://api.example.com","filter":{"conditions":[{"field":"temp","comparator":">","value":"high"}]}}]`
+	invalidPatternFilter := `[{"name":"value","url":"https://api.example.com","filter":{"conditions":[{"field":"device","comparator":"matches","value":"dev-("}]}}]`
 
 	cases := []struct {
 		desc        string
@@ -133,6 +152,114 @@ func TestCreateWebhooks(t *testing.T) {
 			status:      http.StatusCreated,
 			response:    emptyValue,
 		},
+		{
+			desc:        "create webhooks with secret",
+			data:        secretData,
+			thingID:     thingID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusCreated,
+			response:    emptyValue,
+		},
+		{
+			desc:        "create webhooks with invalid secret",
+			data:        invalidSecret,
+			thingID:     thingID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			response:    emptyValue,
+		},
+		{
+			desc:        "create webhooks with method, template and format",
+			data:        templateData,
+			thingID:     thingID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusCreated,
+			response:    emptyValue,
+		},
+		{
+			desc:        "create webhooks with invalid method",
+			data:        invalidMethod,
+			thingID:     thingID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			response:    emptyValue,
+		},
+		{
+			desc:        "create webhooks with invalid format",
+			data:        invalidFormat,
+			thingID:     thingID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			response:    emptyValue,
+		},
+		{
+			desc:        "create webhooks with invalid template",
+			data:        invalidTemplate,
+			thingID:     thingID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			response:    emptyValue,
+		},
+		{
+			desc:        "create webhooks with filter",
+			data:        filterData,
+			thingID:     thingID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusCreated,
+			response:    emptyValue,
+		},
+		{
+			desc:        "create webhooks with invalid subtopic filter",
+			data:        invalidSubtopicFilter,
+			thingID:     thingID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			response:    emptyValue,
+		},
+		{
+			desc:        "create webhooks with invalid condition filter",
+			data:        invalidConditionFilter,
+			thingID:     thingID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			response:    emptyValue,
+		},
+		{
+			desc:        "create webhooks with invalid pattern filter",
+			data:        invalidPatternFilter,
+			thingID:     thingID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			response:    emptyValue,
+		},
+		{
+			desc:        "create batched webhooks",
+			data:        batchData,
+			thingID:     thingID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusCreated,
+			response:    emptyValue,
+		},
+		{
+			desc:        "create batched webhooks with xml format",
+			data:        invalidBatch,
+			thingID:     thingID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			response:    emptyValue,
+		},
 		{
 			desc:        "create webhooks with empty request",
 			data:        emptyValue,
@@ -250,6 +377,13 @@ type webhookRes struct {
 	Url        string            `json:"url"`
 	ResHeaders map[string]string `json:"headers"`
 	Metadata   map[string]any    `json:"metadata,omitempty"`
+	Method     string            `json:"method"`
+	Template   string            `json:"template,omitempty"`
+	Format     string            `json:"format"`
+	Filter     webhooks.Filter   `json:"filter"`
+	Batch      bool              `json:"batch"`
+	Status     string            `json:"status"`
+	Failures   uint64            `json:"failures"`
 }
 
 type webhooksPageRes struct {
@@ -283,6 +417,13 @@ func TestListWebhooksByGroup(t *testing.T) {
 			Url:        w.Url,
 			ResHeaders: w.Headers,
 			Metadata:   w.Metadata,
+			Method:     w.Method,
+			Template:   w.Template,
+			Format:     w.Format,
+			Filter:     w.Filter,
+			Batch:      w.Batch,
+			Status:     w.Status,
+			Failures:   w.Failures,
 		}
 		data = append(data, whRes)
 	}
@@ -471,6 +612,13 @@ func TestSearchWebhooksByThing(t *testing.T) {
 			Url:        w.Url,
 			ResHeaders: w.Headers,
 			Metadata:   w.Metadata,
+			Method:     w.Method,
+			Template:   w.Template,
+			Format:     w.Format,
+			Filter:     w.Filter,
+			Batch:      w.Batch,
+			Status:     w.Status,
+			Failures:   w.Failures,
 		})
 	}
 
@@ -618,6 +766,13 @@ func TestSearchWebhooksByGroup(t *testing.T) {
 			Url:        w.Url,
 			ResHeaders: w.Headers,
 			Metadata:   w.Metadata,
+			Method:     w.Method,
+			Template:   w.Template,
+			Format:     w.Format,
+			Filter:     w.Filter,
+			Batch:      w.Batch,
+			Status:     w.Status,
+			Failures:   w.Failures,
 		})
 	}
 
@@ -868,6 +1023,13 @@ func TestViewWebhook(t *testing.T) {
 		Url:        wh.Url,
 		ResHeaders: wh.Headers,
 		Metadata:   wh.Metadata,
+		Method:     wh.Method,
+		Template:   wh.Template,
+		Format:     wh.Format,
+		Filter:     wh.Filter,
+		Batch:      wh.Batch,
+		Status:     wh.Status,
+		Failures:   wh.Failures,
 	})
 
 	cases := []struct {
@@ -993,3 +1155,366 @@ func TestRemoveWebhooks(t *testing.T) {
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
 	}
 }
+
+func TestEnableDisableWebhook(t *testing.T) {
+	svc := newService()
+	ts := newHTTPServer(svc)
+	defer ts.Close()
+
+	whs, err := svc.CreateWebhooks(context.Background(), token, thingID, webhook)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	wh := whs[0]
+
+	cases := []struct {
+		desc   string
+		id     string
+		action string
+		auth   string
+		status int
+	}{
+		{
+			desc:   "disable webhook",
+			id:     wh.ID,
+			action: "disable",
+			auth:   token,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "enable webhook",
+			id:     wh.ID,
+			action: "enable",
+			auth:   token,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "disable webhook with empty token",
+			id:     wh.ID,
+			action: "disable",
+			auth:   emptyValue,
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "disable webhook with invalid token",
+			id:     wh.ID,
+			action: "disable",
+			auth:   wrongValue,
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "enable non-existing webhook",
+			id:     wrongValue,
+			action: "enable",
+			auth:   token,
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodPost,
+			url:    fmt.Sprintf("%s/webhooks/%s/%s", ts.URL, tc.id, tc.action),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+type deliveriesPageRes struct {
+	Total      uint64 `json:"total"`
+	Deliveries []struct {
+		Status   string `json:"status"`
+		Attempts uint64 `json:"attempts"`
+	} `json:"deliveries"`
+}
+
+func TestListDeliveries(t *testing.T) {
+	svc := newService()
+	ts := newHTTPServer(svc)
+	defer ts.Close()
+
+	whs, err := svc.CreateWebhooks(context.Background(), token, thingID, webhook)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	wh := whs[0]
+
+	msg := protomfx.Webhook{ThingId: thingID, Payload: []byte(`{"key":"val"}`)}
+	for i := 0; i < 3; i++ {
+		err := svc.ConsumeWebhook(thingID, msg)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+	require.Eventually(t, func() bool {
+		dp, err := svc.ListDeliveries(context.Background(), token, wh.ID, webhooks.PageMetadata{})
+		return err == nil && dp.Total == 3
+	}, time.Second, time.Millisecond, "expected logged deliveries")
+
+	cases := []struct {
+		desc   string
+		url    string
+		auth   string
+		status int
+		size   int
+	}{
+		{
+			desc:   "list deliveries",
+			url:    fmt.Sprintf("%s/webhooks/%s/deliveries", ts.URL, wh.ID),
+			auth:   token,
+			status: http.StatusOK,
+			size:   3,
+		},
+		{
+			desc:   "list deliveries with limit",
+			url:    fmt.Sprintf("%s/webhooks/%s/deliveries?offset=%d&limit=%d&dir=%s", ts.URL, wh.ID, 1, 1, ascKey),
+			auth:   token,
+			status: http.StatusOK,
+			size:   1,
+		},
+		{
+			desc:   "list deliveries with invalid limit",
+			url:    fmt.Sprintf("%s/webhooks/%s/deliveries?limit=%d", ts.URL, wh.ID, 210),
+			auth:   token,
+			status: http.StatusBadRequest,
+			size:   0,
+		},
+		{
+			desc:   "list deliveries with invalid direction",
+			url:    fmt.Sprintf("%s/webhooks/%s/deliveries?dir=%s", ts.URL, wh.ID, wrongValue),
+			auth:   token,
+			status: http.StatusBadRequest,
+			size:   0,
+		},
+		{
+			desc:   "list deliveries with empty token",
+			url:    fmt.Sprintf("%s/webhooks/%s/deliveries", ts.URL, wh.ID),
+			auth:   emptyValue,
+			status: http.StatusUnauthorized,
+			size:   0,
+		},
+		{
+			desc:   "list deliveries of non-existing webhook",
+			url:    fmt.Sprintf("%s/webhooks/%s/deliveries", ts.URL, wrongValue),
+			auth:   token,
+			status: http.StatusNotFound,
+			size:   0,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    tc.url,
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body deliveriesPageRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.size, len(body.Deliveries), fmt.Sprintf("%s: expected %d deliveries got %d", tc.desc, tc.size, len(body.Deliveries)))
+		for _, d := range body.Deliveries {
+			assert.Equal(t, webhooks.DeliveryStatusDelivered, d.Status, fmt.Sprintf("%s: expected status %s got %s", tc.desc, webhooks.DeliveryStatusDelivered, d.Status))
+		}
+	}
+}
+
+type deadLettersPageRes struct {
+	Total       uint64 `json:"total"`
+	DeadLetters []struct {
+		ID      string `json:"id"`
+		Payload []byte `json:"payload"`
+	} `json:"dead_letters"`
+}
+
+func TestReplayDeadLetters(t *testing.T) {
+	svc := newService()
+	ts := newHTTPServer(svc)
+	defer ts.Close()
+
+	failingWh := webhook
+	failingWh.Url = whmocks.FailingURL
+	whs, err := svc.CreateWebhooks(context.Background(), token, thingID, failingWh)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	wh := whs[0]
+
+	msg := protomfx.Webhook{ThingId: thingID, Payload: []byte(`{"key":"val"}`)}
+	err = svc.ConsumeWebhook(thingID, msg)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	var page deadLettersPageRes
+	require.Eventually(t, func() bool {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/webhooks/%s/dead-letters", ts.URL, wh.ID),
+			token:  token,
+		}
+		res, err := req.make()
+		if err != nil || res.StatusCode != http.StatusOK {
+			return false
+		}
+		defer res.Body.Close()
+		return json.NewDecoder(res.Body).Decode(&page) == nil && page.Total == 1
+	}, time.Second, time.Millisecond, "expected a dead letter")
+	assert.Equal(t, msg.Payload, page.DeadLetters[0].Payload, fmt.Sprintf("expected payload %s got %s", msg.Payload, page.DeadLetters[0].Payload))
+
+	fixedWh := wh
+	fixedWh.Url = webhook.Url
+
+	tooManyIDs := make([]string, webhooks.MaxReplay+1)
+	for i := range tooManyIDs {
+		tooManyIDs[i] = page.DeadLetters[0].ID
+	}
+
+	cases := []struct {
+		desc        string
+		webhook     webhooks.Webhook
+		body        string
+		contentType string
+		auth        string
+		status      int
+		res         string
+	}{
+		{
+			desc:        "replay dead letter to failing webhook",
+			webhook:     wh,
+			body:        fmt.Sprintf(`{"dead_letter_ids":["%s"]}`, page.DeadLetters[0].ID),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+			res:         `{"total":1,"delivered":0,"remaining":0}`,
+		},
+		{
+			desc:        "replay non-existing dead letter",
+			webhook:     wh,
+			body:        fmt.Sprintf(`{"dead_letter_ids":["%s"]}`, wrongValue),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
+			res:         toJSON(apiutil.ErrorRes{Err: dbutil.ErrNotFound.Error()}),
+		},
+		{
+			desc:        "replay too many dead letters",
+			webhook:     wh,
+			body:        toJSON(map[string]any{"dead_letter_ids": tooManyIDs}),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			res:         toJSON(apiutil.ErrorRes{Err: apiutil.ErrLimitSize.Error()}),
+		},
+		{
+			desc:        "replay dead letters with invalid content type",
+			webhook:     wh,
+			body:        fmt.Sprintf(`{"dead_letter_ids":["%s"]}`, page.DeadLetters[0].ID),
+			contentType: wrongValue,
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+			res:         toJSON(apiutil.ErrorRes{Err: apiutil.ErrUnsupportedContentType.Error()}),
+		},
+		{
+			desc:    "replay dead letters with empty token",
+			webhook: wh,
+			auth:    emptyValue,
+			status:  http.StatusUnauthorized,
+			res:     missingTokRes,
+		},
+		{
+			desc:    "replay all dead letters to fixed webhook",
+			webhook: fixedWh,
+			auth:    token,
+			status:  http.StatusOK,
+			res:     `{"total":1,"delivered":1,"remaining":0}`,
+		},
+		{
+			desc:    "replay without dead letters",
+			webhook: fixedWh,
+			auth:    token,
+			status:  http.StatusOK,
+			res:     `{"total":0,"delivered":0,"remaining":0}`,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.UpdateWebhook(context.Background(), token, tc.webhook)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/webhooks/%s/dead-letters/replay", ts.URL, wh.ID),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.body),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		body, err := io.ReadAll(res.Body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		data := strings.Trim(string(body), "\n")
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.res, data, fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, data))
+	}
+}
+
+func TestRotateWebhookSecret(t *testing.T) {
+	svc := newService()
+	ts := newHTTPServer(svc)
+	defer ts.Close()
+
+	whs, err := svc.CreateWebhooks(context.Background(), token, thingID, webhook)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	wh := whs[0]
+
+	cases := []struct {
+		desc   string
+		id     string
+		auth   string
+		status int
+	}{
+		{
+			desc:   "rotate webhook secret",
+			id:     wh.ID,
+			auth:   token,
+			status: http.StatusOK,
+		},
+		{
+			desc:   "rotate webhook secret with empty token",
+			id:     wh.ID,
+			auth:   emptyValue,
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "rotate non-existing webhook secret",
+			id:     wrongValue,
+			auth:   token,
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodPost,
+			url:    fmt.Sprintf("%s/webhooks/%s/secret/rotate", ts.URL, tc.id),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status != http.StatusOK {
+			continue
+		}
+
+		var body struct {
+			Secret                  string `json:"secret"`
+			PreviousSecretExpiresAt int64  `json:"previous_secret_expires_at"`
+		}
+		err = json.NewDecoder(res.Body).Decode(&body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.NotEmpty(t, body.Secret, fmt.Sprintf("%s: expected a new secret", tc.desc))
+		assert.NotEqual(t, wh.Secret, body.Secret, fmt.Sprintf("%s: expected a new secret", tc.desc))
+		assert.NotZero(t, body.PreviousSecretExpiresAt, fmt.Sprintf("%s: expected a grace period", tc.desc))
+	}
+}
@@ -12,14 +12,26 @@ import (
 )
 
 const (
-	minLen       = 1
-	maxLimitSize = 200
-	maxNameSize  = 254
+	minLen        = 1
+	maxLimitSize  = 200
+	maxNameSize   = 254
+	minSecretSize = 16
+	maxSecretSize = 256
 )
 
 var (
 	ErrInvalidUrl       = errors.New("missing or invalid url")
 	ErrMissingWebhookID = errors.New("missing webhook id")
+	// ErrMissingDeadLetterID indicates an empty dead letter ID.
+	ErrMissingDeadLetterID = errors.New("missing dead letter id")
+	// ErrInvalidSecret indicates a signing secret that is too short or too long.
+	ErrInvalidSecret = errors.New("invalid webhook secret size")
+	// ErrInvalidMethod indicates an HTTP method messages can't be forwarded with.
+	ErrInvalidMethod = errors.New("invalid webhook method")
+	// ErrInvalidFormat indicates an unsupported body format.
+	ErrInvalidFormat = errors.New("invalid webhook format")
+	// ErrInvalidBatch indicates batching enabled for a format other than json.
+	ErrInvalidBatch = errors.New("only webhooks with json format can be batched")
 )
 
 // validatePageMetadata validates the webhooks page metadata.
@@ -42,6 +54,12 @@ type createWebhookReq struct {
 	Url      string            `json:"url"`
 	Headers  map[string]string `json:"headers,omitempty"`
 	Metadata map[string]any    `json:"metadata,omitempty"`
+	Method   string            `json:"method,omitempty"`
+	Template string            `json:"template,omitempty"`
+	Format   string            `json:"format,omitempty"`
+	Filter   webhooks.Filter   `json:"filter,omitempty"`
+	Batch    bool              `json:"batch,omitempty"`
+	Secret   string            `json:"secret,omitempty"`
 }
 
 type createWebhooksReq struct {
@@ -82,6 +100,39 @@ func (req createWebhookReq) validate() error {
 		return ErrInvalidUrl
 	}
 
+	// The secret is generated unless provided.
+	if req.Secret != "" && (len(req.Secret) < minSecretSize || len(req.Secret) > maxSecretSize) {
+		return ErrInvalidSecret
+	}
+
+	if err := validateBody(req.Method, req.Template, req.Format, req.Batch); err != nil {
+		return err
+	}
+
+	return req.Filter.Validate()
+}
+
+// validateBody validates the method, template, format and batching of the
+// forwarded requests. Empty values stand for the defaults.
+func validateBody(method, template, format string, batch bool) error {
+	if method != "" && !webhooks.IsValidMethod(method) {
+		return ErrInvalidMethod
+	}
+
+	if format != "" && !webhooks.IsValidFormat(format) {
+		return ErrInvalidFormat
+	}
+
+	if batch && format != "" && format != webhooks.FormatJSON {
+		return ErrInvalidBatch
+	}
+
+	if template != "" {
+		if _, err := webhooks.ParseTemplate(template); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -143,6 +194,11 @@ type updateWebhookReq struct {
 	Url      string            `json:"url"`
 	Headers  map[string]string `json:"headers,omitempty"`
 	Metadata map[string]any    `json:"metadata,omitempty"`
+	Method   string            `json:"method,omitempty"`
+	Template string            `json:"template,omitempty"`
+	Format   string            `json:"format,omitempty"`
+	Filter   webhooks.Filter   `json:"filter,omitempty"`
+	Batch    bool              `json:"batch,omitempty"`
 }
 
 func (req updateWebhookReq) validate() error {
@@ -163,7 +219,11 @@ func (req updateWebhookReq) validate() error {
 		return ErrInvalidUrl
 	}
 
-	return nil
+	if err := validateBody(req.Method, req.Template, req.Format, req.Batch); err != nil {
+		return err
+	}
+
+	return req.Filter.Validate()
 }
 
 type removeWebhooksReq struct {
@@ -188,3 +248,50 @@ func (req removeWebhooksReq) validate() error {
 
 	return nil
 }
+
+type listDeliveriesReq struct {
+	token        string
+	id           string
+	pageMetadata webhooks.PageMetadata
+}
+
+func (req listDeliveriesReq) validate() error {
+	if req.token == "" {
+		return apiutil.ErrBearerToken
+	}
+
+	if req.id == "" {
+		return ErrMissingWebhookID
+	}
+
+	common := apiutil.PageMetadata{Offset: req.pageMetadata.Offset, Limit: req.pageMetadata.Limit, Dir: req.pageMetadata.Dir}
+	return common.Validate(maxLimitSize, nil)
+}
+
+type replayDeadLettersReq struct {
+	token         string
+	id            string
+	DeadLetterIDs []string `json:"dead_letter_ids,omitempty"`
+}
+
+func (req replayDeadLettersReq) validate() error {
+	if req.token == "" {
+		return apiutil.ErrBearerToken
+	}
+
+	if req.id == "" {
+		return ErrMissingWebhookID
+	}
+
+	if len(req.DeadLetterIDs) > webhooks.MaxReplay {
+		return apiutil.ErrLimitSize
+	}
+
+	for _, id := range req.DeadLetterIDs {
+		if id == "" {
+			return ErrMissingDeadLetterID
+		}
+	}
+
+	return nil
+}
@@ -7,11 +7,16 @@ import (
 	"net/http"
 
 	"github.com/MainfluxLabs/mainflux/pkg/apiutil"
+	"github.com/MainfluxLabs/mainflux/webhooks"
 )
 
 var (
 	_ apiutil.Response = (*webhookResponse)(nil)
 	_ apiutil.Response = (*webhooksRes)(nil)
+	_ apiutil.Response = (*deliveriesPageRes)(nil)
+	_ apiutil.Response = (*deadLettersPageRes)(nil)
+	_ apiutil.Response = (*replayRes)(nil)
+	_ apiutil.Response = (*secretRes)(nil)
 )
 
 type pageRes struct {
@@ -31,7 +36,16 @@ type webhookResponse struct {
 	Url        string            `json:"url"`
 	ResHeaders map[string]string `json:"headers,omitempty"`
 	Metadata   map[string]any    `json:"metadata,omitempty"`
-	updated    bool
+	Method     string            `json:"method"`
+	Template   string            `json:"template,omitempty"`
+	Format     string            `json:"format"`
+	Filter     webhooks.Filter   `json:"filter"`
+	Batch      bool              `json:"batch"`
+	Status     string            `json:"status"`
+	Failures   uint64            `json:"failures"`
+	// Secret is only returned when the webhook is created.
+	Secret  string `json:"secret,omitempty"`
+	updated bool
 }
 
 func (res webhookResponse) Code() int {
@@ -83,3 +97,96 @@ func (res WebhooksPageRes) Headers() map[string]string {
 func (res WebhooksPageRes) Empty() bool {
 	return false
 }
+
+type deliveryRes struct {
+	ID         string `json:"id"`
+	ThingID    string `json:"thing_id"`
+	Messages   uint64 `json:"messages"`
+	Status     string `json:"status"`
+	StatusCode int    `json:"status_code"`
+	// Latency is the duration of the last attempt in milliseconds.
+	Latency  int64  `json:"latency"`
+	Attempts uint64 `json:"attempts"`
+	Response string `json:"response,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Created  int64  `json:"created"`
+}
+
+type deliveriesPageRes struct {
+	pageRes
+	Deliveries []deliveryRes `json:"deliveries"`
+}
+
+func (res deliveriesPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res deliveriesPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res deliveriesPageRes) Empty() bool {
+	return false
+}
+
+type deadLetterRes struct {
+	ID       string `json:"id"`
+	ThingID  string `json:"thing_id"`
+	Payload  []byte `json:"payload"`
+	Error    string `json:"error,omitempty"`
+	Attempts uint64 `json:"attempts"`
+	Created  int64  `json:"created"`
+}
+
+type deadLettersPageRes struct {
+	pageRes
+	DeadLetters []deadLetterRes `json:"dead_letters"`
+}
+
+func (res deadLettersPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res deadLettersPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res deadLettersPageRes) Empty() bool {
+	return false
+}
+
+type replayRes struct {
+	Total     uint64 `json:"total"`
+	Delivered uint64 `json:"delivered"`
+	Remaining uint64 `json:"remaining"`
+}
+
+func (res replayRes) Code() int {
+	return http.StatusOK
+}
+
+func (res replayRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res replayRes) Empty() bool {
+	return false
+}
+
+type secretRes struct {
+	Secret string `json:"secret"`
+	// PreviousSecretExpiresAt is the end of the grace period of the previous secret in unix nanoseconds.
+	PreviousSecretExpiresAt int64 `json:"previous_secret_expires_at,omitempty"`
+}
+
+func (res secretRes) Code() int {
+	return http.StatusOK
+}
+
+func (res secretRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res secretRes) Empty() bool {
+	return false
+}
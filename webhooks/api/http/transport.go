@@ -101,6 +101,60 @@ func MakeHandler(tracer opentracing.Tracer, svc webhooks.Service, ac domain.Auth
 		encodeResponse,
 		opts...,
 	))
+	r.Post("/webhooks/:id/enable", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "enable_webhook"),
+			withIdentity,
+		)(enableWebhookEndpoint(svc)),
+		decodeRequest,
+		encodeResponse,
+		opts...,
+	))
+	r.Post("/webhooks/:id/disable", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "disable_webhook"),
+			withIdentity,
+		)(disableWebhookEndpoint(svc)),
+		decodeRequest,
+		encodeResponse,
+		opts...,
+	))
+	r.Post("/webhooks/:id/secret/rotate", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "rotate_webhook_secret"),
+			withIdentity,
+		)(rotateWebhookSecretEndpoint(svc)),
+		decodeRequest,
+		encodeResponse,
+		opts...,
+	))
+	r.Get("/webhooks/:id/deliveries", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "list_deliveries"),
+			withIdentity,
+		)(listDeliveriesEndpoint(svc)),
+		decodeListDeliveries,
+		encodeResponse,
+		opts...,
+	))
+	r.Get("/webhooks/:id/dead-letters", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "list_dead_letters"),
+			withIdentity,
+		)(listDeadLettersEndpoint(svc)),
+		decodeListDeliveries,
+		encodeResponse,
+		opts...,
+	))
+	r.Post("/webhooks/:id/dead-letters/replay", kithttp.NewServer(
+		endpoint.Chain(
+			kitot.TraceServer(tracer, "replay_dead_letters"),
+			withIdentity,
+		)(replayDeadLettersEndpoint(svc)),
+		decodeReplayDeadLetters,
+		encodeResponse,
+		opts...,
+	))
 	r.Patch("/webhooks", kithttp.NewServer(
 		endpoint.Chain(
 			kitot.TraceServer(tracer, "remove_webhooks"),
@@ -279,6 +333,57 @@ func decodeUpdateWebhook(_ context.Context, r *http.Request) (any, error) {
 	return req, nil
 }
 
+func decodeListDeliveries(_ context.Context, r *http.Request) (any, error) {
+	o, err := apiutil.ReadUintQuery(r, apiutil.OffsetKey, apiutil.DefOffset)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := apiutil.ReadLimitQuery(r, apiutil.LimitKey, apiutil.DefLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := apiutil.ReadStringQuery(r, apiutil.DirKey, apiutil.DescDir)
+	if err != nil {
+		return nil, err
+	}
+
+	req := listDeliveriesReq{
+		token: apiutil.ExtractBearerToken(r),
+		id:    bone.GetValue(r, apiutil.IDKey),
+		pageMetadata: webhooks.PageMetadata{
+			Offset: o,
+			Limit:  l,
+			Dir:    d,
+		},
+	}
+
+	return req, nil
+}
+
+func decodeReplayDeadLetters(_ context.Context, r *http.Request) (any, error) {
+	req := replayDeadLettersReq{
+		token: apiutil.ExtractBearerToken(r),
+		id:    bone.GetValue(r, apiutil.IDKey),
+	}
+
+	// Without a body, all dead letters of the webhook are replayed.
+	if r.ContentLength == 0 {
+		return req, nil
+	}
+
+	if !strings.Contains(r.Header.Get("Content-Type"), apiutil.ContentTypeJSON) {
+		return nil, apiutil.ErrUnsupportedContentType
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
 func decodeRemoveWebhooks(_ context.Context, r *http.Request) (any, error) {
 	if !strings.Contains(r.Header.Get("Content-Type"), apiutil.ContentTypeJSON) {
 		return nil, apiutil.ErrUnsupportedContentType
@@ -316,7 +421,14 @@ func encodeResponse(_ context.Context, w http.ResponseWriter, response any) erro
 func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	switch {
 	case err == ErrInvalidUrl,
-		err == ErrMissingWebhookID:
+		err == ErrMissingWebhookID,
+		err == ErrMissingDeadLetterID,
+		err == ErrInvalidSecret,
+		err == ErrInvalidMethod,
+		err == ErrInvalidFormat,
+		err == ErrInvalidBatch,
+		errors.Contains(err, webhooks.ErrInvalidTemplate),
+		errors.Contains(err, webhooks.ErrInvalidFilter):
 		w.WriteHeader(http.StatusBadRequest)
 	case errors.Contains(err, uuid.ErrGeneratingID):
 		w.WriteHeader(http.StatusInternalServerError)
@@ -33,7 +33,7 @@ func LoggingMiddleware(svc webhooks.Service, logger log.Logger) webhooks.Service
 func (lm *loggingMiddleware) CreateWebhooks(ctx context.Context, token, thingID string, webhooks ...webhooks.Webhook) (response []webhooks.Webhook, err error) {
 	defer func(begin time.Time) {
 		email := authn.EmailFromToken(token)
-		message := fmt.Sprintf("Method create_webhooks by user %s, webhooks %s took %s to complete", email, response, time.Since(begin))
+		message := fmt.Sprintf("Method create_webhooks by user %s, webhooks %v took %s to complete", email, response, time.Since(begin))
 		if err != nil {
 			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
 			return
@@ -100,6 +100,90 @@ func (lm *loggingMiddleware) UpdateWebhook(ctx context.Context, token string, we
 	return lm.svc.UpdateWebhook(ctx, token, webhook)
 }
 
+func (lm *loggingMiddleware) EnableWebhook(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		email := authn.EmailFromToken(token)
+		message := fmt.Sprintf("Method enable_webhook by user %s, id %s took %s to complete", email, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.EnableWebhook(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) DisableWebhook(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		email := authn.EmailFromToken(token)
+		message := fmt.Sprintf("Method disable_webhook by user %s, id %s took %s to complete", email, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.DisableWebhook(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) RotateWebhookSecret(ctx context.Context, token, id string) (wh webhooks.Webhook, err error) {
+	defer func(begin time.Time) {
+		email := authn.EmailFromToken(token)
+		message := fmt.Sprintf("Method rotate_webhook_secret by user %s, id %s took %s to complete", email, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RotateWebhookSecret(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListDeliveries(ctx context.Context, token, webhookID string, pm webhooks.PageMetadata) (response webhooks.DeliveriesPage, err error) {
+	defer func(begin time.Time) {
+		email := authn.EmailFromToken(token)
+		message := fmt.Sprintf("Method list_deliveries by user %s, webhook id %s took %s to complete", email, webhookID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListDeliveries(ctx, token, webhookID, pm)
+}
+
+func (lm *loggingMiddleware) ListDeadLetters(ctx context.Context, token, webhookID string, pm webhooks.PageMetadata) (response webhooks.DeadLettersPage, err error) {
+	defer func(begin time.Time) {
+		email := authn.EmailFromToken(token)
+		message := fmt.Sprintf("Method list_dead_letters by user %s, webhook id %s took %s to complete", email, webhookID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListDeadLetters(ctx, token, webhookID, pm)
+}
+
+func (lm *loggingMiddleware) ReplayDeadLetters(ctx context.Context, token, webhookID string, ids ...string) (response webhooks.ReplayResult, err error) {
+	defer func(begin time.Time) {
+		email := authn.EmailFromToken(token)
+		message := fmt.Sprintf("Method replay_dead_letters by user %s, webhook id %s took %s to complete", email, webhookID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ReplayDeadLetters(ctx, token, webhookID, ids...)
+}
+
 func (lm *loggingMiddleware) RemoveWebhooks(ctx context.Context, token string, id ...string) (err error) {
 	defer func(begin time.Time) {
 		email := authn.EmailFromToken(token)
@@ -140,6 +224,19 @@ func (lm *loggingMiddleware) RemoveWebhooksByGroup(ctx context.Context, groupID
 	return lm.svc.RemoveWebhooksByGroup(ctx, groupID)
 }
 
+func (lm *loggingMiddleware) ProcessDeliveries(ctx context.Context) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method process_deliveries took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ProcessDeliveries(ctx)
+}
+
 func (lm *loggingMiddleware) ConsumeWebhook(subject string, webhook protomfx.Webhook) (err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method consume_webhook took %s to complete", time.Since(begin))
@@ -79,6 +79,60 @@ func (ms *metricsMiddleware) UpdateWebhook(ctx context.Context, token string, we
 	return ms.svc.UpdateWebhook(ctx, token, webhook)
 }
 
+func (ms *metricsMiddleware) EnableWebhook(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "enable_webhook").Add(1)
+		ms.latency.With("method", "enable_webhook").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.EnableWebhook(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) DisableWebhook(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "disable_webhook").Add(1)
+		ms.latency.With("method", "disable_webhook").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.DisableWebhook(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) RotateWebhookSecret(ctx context.Context, token, id string) (webhooks.Webhook, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "rotate_webhook_secret").Add(1)
+		ms.latency.With("method", "rotate_webhook_secret").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RotateWebhookSecret(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListDeliveries(ctx context.Context, token, webhookID string, pm webhooks.PageMetadata) (webhooks.DeliveriesPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_deliveries").Add(1)
+		ms.latency.With("method", "list_deliveries").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListDeliveries(ctx, token, webhookID, pm)
+}
+
+func (ms *metricsMiddleware) ListDeadLetters(ctx context.Context, token, webhookID string, pm webhooks.PageMetadata) (webhooks.DeadLettersPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_dead_letters").Add(1)
+		ms.latency.With("method", "list_dead_letters").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListDeadLetters(ctx, token, webhookID, pm)
+}
+
+func (ms *metricsMiddleware) ReplayDeadLetters(ctx context.Context, token, webhookID string, ids ...string) (webhooks.ReplayResult, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "replay_dead_letters").Add(1)
+		ms.latency.With("method", "replay_dead_letters").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ReplayDeadLetters(ctx, token, webhookID, ids...)
+}
+
 func (ms *metricsMiddleware) RemoveWebhooks(ctx context.Context, token string, id ...string) error {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "remove_webhooks").Add(1)
@@ -106,6 +160,15 @@ func (ms *metricsMiddleware) RemoveWebhooksByGroup(ctx context.Context, groupID
 	return ms.svc.RemoveWebhooksByGroup(ctx, groupID)
 }
 
+func (ms *metricsMiddleware) ProcessDeliveries(ctx context.Context) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "process_deliveries").Add(1)
+		ms.latency.With("method", "process_deliveries").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ProcessDeliveries(ctx)
+}
+
 func (ms *metricsMiddleware) ConsumeWebhook(subject string, webhook protomfx.Webhook) error {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "consume_webhook").Add(1)
@@ -0,0 +1,143 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package webhooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+	"text/template"
+	"time"
+
+	protomfx "github.com/MainfluxLabs/mainflux/pkg/proto"
+)
+
+// BatchConfig configures the batching of messages forwarded to webhooks that
+// have batching enabled. A batch is flushed once it reaches either size limit,
+// or once Interval elapses since its first message.
+type BatchConfig struct {
+	// MaxMessages is the maximum number of messages in a batch. Zero doesn't limit it.
+	MaxMessages int
+	// MaxBytes is the maximum total size of the message payloads in a batch. Zero doesn't limit it.
+	MaxBytes int
+	Interval time.Duration
+}
+
+type batch struct {
+	webhook Webhook
+	msgs    []protomfx.Webhook
+	pending []string
+	size    int
+	timer   *time.Timer
+}
+
+// batcher buffers messages per webhook, and flushes them in batches.
+type batcher struct {
+	mu      sync.Mutex
+	config  BatchConfig
+	batches map[string]*batch
+	flush   func(bt *batch)
+}
+
+func newBatcher(config BatchConfig, flush func(bt *batch)) *batcher {
+	return &batcher{
+		config:  config,
+		batches: make(map[string]*batch),
+		flush:   flush,
+	}
+}
+
+// add adds the pending message to the batch of the webhook, and flushes the
+// batch if it reached a size limit.
+func (b *batcher) add(wh Webhook, msg protomfx.Webhook, pendingID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	bt, ok := b.batches[wh.ID]
+	if !ok {
+		bt = &batch{}
+		bt.timer = time.AfterFunc(b.config.Interval, func() { b.expire(wh.ID, bt) })
+		b.batches[wh.ID] = bt
+	}
+
+	// The batch is forwarded to the webhook as of its last message.
+	bt.webhook = wh
+	bt.msgs = append(bt.msgs, msg)
+	bt.pending = append(bt.pending, pendingID)
+	bt.size += len(msg.Payload)
+
+	if b.config.MaxMessages > 0 && len(bt.msgs) >= b.config.MaxMessages ||
+		b.config.MaxBytes > 0 && bt.size >= b.config.MaxBytes {
+		bt.timer.Stop()
+		delete(b.batches, wh.ID)
+		go b.flush(bt)
+	}
+}
+
+// remove removes the buffered batch of the webhook without flushing it, and returns it.
+func (b *batcher) remove(id string) (*batch, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	bt, ok := b.batches[id]
+	if !ok {
+		return nil, false
+	}
+	bt.timer.Stop()
+	delete(b.batches, id)
+
+	return bt, true
+}
+
+// drain removes the buffered batches without flushing them.
+func (b *batcher) drain() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for id, bt := range b.batches {
+		bt.timer.Stop()
+		delete(b.batches, id)
+	}
+}
+
+// expire flushes the batch once its interval elapses, unless it was already
+// flushed for reaching a size limit.
+func (b *batcher) expire(id string, bt *batch) {
+	b.mu.Lock()
+	if b.batches[id] != bt {
+		b.mu.Unlock()
+		return
+	}
+	delete(b.batches, id)
+	b.mu.Unlock()
+
+	b.flush(bt)
+}
+
+// buildBatchBody returns a JSON array of the bodies of the messages. A body
+// that isn't valid JSON is added as a string.
+func buildBatchBody(msgs []protomfx.Webhook, wh Webhook, t *template.Template) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, msg := range msgs {
+		body, _, err := buildBody(msg, wh, t)
+		if err != nil {
+			return nil, err
+		}
+
+		if !json.Valid(body) {
+			if body, err = json.Marshal(string(body)); err != nil {
+				return nil, err
+			}
+		}
+
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(body)
+	}
+	buf.WriteByte(']')
+
+	return buf.Bytes(), nil
+}
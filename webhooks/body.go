@@ -0,0 +1,227 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package webhooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/MainfluxLabs/mainflux/pkg/errors"
+	protomfx "github.com/MainfluxLabs/mainflux/pkg/proto"
+)
+
+const (
+	// FormatJSON forwards the payload, or the rendered template, as is.
+	FormatJSON = "json"
+	// FormatForm forwards the JSON object as a form-encoded body.
+	FormatForm = "form"
+	// FormatXML forwards the JSON object as an XML document.
+	FormatXML = "xml"
+
+	contentTypeForm = "application/x-www-form-urlencoded"
+	contentTypeXML  = "application/xml"
+	xmlRootElement  = "message"
+)
+
+var (
+	// Methods are the HTTP methods messages can be forwarded with.
+	Methods = []string{http.MethodPost, http.MethodPut, http.MethodPatch}
+	// Formats are the body formats messages can be forwarded in.
+	Formats = []string{FormatJSON, FormatForm, FormatXML}
+
+	// ErrInvalidTemplate indicates a body template that can't be parsed or executed.
+	ErrInvalidTemplate = errors.New("invalid webhook body template")
+
+	// ErrInvalidObject indicates a body that can't be forwarded as a form or XML,
+	// as it isn't a JSON object.
+	ErrInvalidObject = errors.New("webhook body must be a JSON object")
+)
+
+// TemplateData is the data a body template is executed with.
+type TemplateData struct {
+	ThingID  string
+	GroupID  string
+	Subtopic string
+	// Created is the message creation time in unix nanoseconds.
+	Created int64
+	// Payload is the decoded JSON payload, or nil if the payload isn't JSON.
+	Payload any
+	// Raw is the payload as a string.
+	Raw string
+}
+
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// ParseTemplate parses the body template. Besides the builtin functions, the
+// template can use json, which encodes a value as JSON.
+func ParseTemplate(text string) (*template.Template, error) {
+	t, err := template.New("body").Funcs(templateFuncs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidTemplate, err)
+	}
+
+	return t, nil
+}
+
+// buildBody returns the body of the request forwarding the message to the
+// webhook, and its content type. t is the parsed template of the webhook, or
+// nil if the webhook has none.
+func buildBody(msg protomfx.Webhook, wh Webhook, t *template.Template) ([]byte, string, error) {
+	body := msg.Payload
+	if t != nil {
+		data := TemplateData{
+			ThingID:  msg.ThingId,
+			GroupID:  wh.GroupID,
+			Subtopic: msg.Subtopic,
+			Created:  msg.Created,
+			Raw:      string(msg.Payload),
+		}
+		// A non-JSON payload is only available raw.
+		_ = json.Unmarshal(msg.Payload, &data.Payload)
+
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, data); err != nil {
+			return nil, "", errors.Wrap(ErrInvalidTemplate, err)
+		}
+		body = buf.Bytes()
+	}
+
+	switch wh.Format {
+	case FormatForm:
+		obj, err := decodeObject(body)
+		if err != nil {
+			return nil, "", err
+		}
+		values := url.Values{}
+		flattenForm(values, "", obj)
+		return []byte(values.Encode()), contentTypeForm, nil
+	case FormatXML:
+		obj, err := decodeObject(body)
+		if err != nil {
+			return nil, "", err
+		}
+		var buf bytes.Buffer
+		buf.WriteString(xml.Header)
+		writeXML(&buf, xmlRootElement, obj)
+		return buf.Bytes(), contentTypeXML, nil
+	default:
+		return body, contentTypeJSON, nil
+	}
+}
+
+func decodeObject(body []byte) (map[string]any, error) {
+	var obj map[string]any
+	if err := json.Unmarshal(body, &obj); err != nil {
+		return nil, errors.Wrap(ErrInvalidObject, err)
+	}
+
+	return obj, nil
+}
+
+// flattenForm adds the value to the form values. Nested object keys are joined
+// with dots, and array elements are added as repeated values of the same key.
+func flattenForm(values url.Values, key string, v any) {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, e := range val {
+			if key != "" {
+				k = key + "." + k
+			}
+			flattenForm(values, k, e)
+		}
+	case []any:
+		for _, e := range val {
+			flattenForm(values, key, e)
+		}
+	case nil:
+		values.Add(key, "")
+	default:
+		values.Add(key, fmt.Sprint(val))
+	}
+}
+
+// writeXML writes the value as an element with the given name. Object keys
+// become child elements in sorted order, and array elements become repeated
+// elements of the same name.
+func writeXML(buf *bytes.Buffer, name string, v any) {
+	name = xmlName(name)
+	switch val := v.(type) {
+	case []any:
+		for _, e := range val {
+			writeXML(buf, name, e)
+		}
+		return
+	case map[string]any:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		fmt.Fprintf(buf, "<%s>", name)
+		for _, k := range keys {
+			writeXML(buf, k, val[k])
+		}
+		fmt.Fprintf(buf, "</%s>", name)
+	case nil:
+		fmt.Fprintf(buf, "<%s/>", name)
+	default:
+		fmt.Fprintf(buf, "<%s>", name)
+		xml.EscapeText(buf, []byte(fmt.Sprint(val)))
+		fmt.Fprintf(buf, "</%s>", name)
+	}
+}
+
+// xmlName replaces the characters that aren't allowed in XML element names.
+func xmlName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+
+	if name == "" || !(name[0] == '_' || name[0] >= 'a' && name[0] <= 'z' || name[0] >= 'A' && name[0] <= 'Z') {
+		name = "_" + name
+	}
+
+	return name
+}
+
+// withDefaults sets the default method and format of the webhook, unless set.
+func withDefaults(wh Webhook) Webhook {
+	if wh.Method == "" {
+		wh.Method = http.MethodPost
+	}
+	if wh.Format == "" {
+		wh.Format = FormatJSON
+	}
+
+	return wh
+}
+
+// IsValidMethod reports whether messages can be forwarded with the HTTP method.
+func IsValidMethod(method string) bool {
+	return slices.Contains(Methods, method)
+}
+
+// IsValidFormat reports whether messages can be forwarded in the body format.
+func IsValidFormat(format string) bool {
+	return slices.Contains(Formats, format)
+}
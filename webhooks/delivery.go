@@ -0,0 +1,494 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/MainfluxLabs/mainflux/pkg/dbutil"
+	"github.com/MainfluxLabs/mainflux/pkg/domain"
+	"github.com/MainfluxLabs/mainflux/pkg/errors"
+	protomfx "github.com/MainfluxLabs/mainflux/pkg/proto"
+)
+
+const (
+	DeliveryStatusDelivered = "delivered"
+	DeliveryStatusFailed    = "failed"
+
+	// MaxReplay is the maximum number of dead letters replayed at once.
+	MaxReplay = 100
+)
+
+// Delivery represents a logged delivery of a message to a webhook, including
+// its retries.
+type Delivery struct {
+	ID        string
+	WebhookID string
+	ThingID   string
+	// Messages is the number of forwarded messages, which is more than one
+	// only for batches.
+	Messages uint64
+	// Status is either delivered or failed.
+	Status string
+	// StatusCode is the response status of the last attempt, or zero if the
+	// endpoint didn't respond.
+	StatusCode int
+	// Latency is the duration of the last attempt.
+	Latency  time.Duration
+	Attempts uint64
+	// Response is the beginning of the response body of the last attempt.
+	Response string
+	// Error describes why the last attempt failed.
+	Error string
+	// Created is the delivery time in unix nanoseconds.
+	Created int64
+}
+
+// DeliveriesPage contains a page of deliveries.
+type DeliveriesPage struct {
+	Total      uint64
+	Deliveries []Delivery
+}
+
+// DeadLetter represents a message whose delivery to a webhook failed after
+// all attempts. It is kept until it is replayed successfully, the webhook is
+// removed, or it exceeds the retention limits.
+type DeadLetter struct {
+	ID        string
+	WebhookID string
+	Message   protomfx.Webhook
+	// Error describes why the last delivery attempt failed.
+	Error    string
+	Attempts uint64
+	// Created is the time the message was dead-lettered in unix nanoseconds.
+	Created int64
+}
+
+// DeadLettersPage contains a page of dead letters.
+type DeadLettersPage struct {
+	Total       uint64
+	DeadLetters []DeadLetter
+}
+
+// PendingMessage represents a message waiting for its delivery to a webhook. It is
+// kept until it is delivered or dead-lettered, and is leased to the instance of the
+// service delivering it, so that the messages of an instance that stopped or crashed
+// are delivered by another one once their lease expires.
+type PendingMessage struct {
+	ID        string
+	WebhookID string
+	Message   protomfx.Webhook
+	// Created is the time the message was consumed in unix nanoseconds.
+	Created int64
+}
+
+// ReplayResult reports the outcome of a dead letter replay.
+type ReplayResult struct {
+	// Total is the number of replayed dead letters.
+	Total uint64
+	// Delivered is the number of dead letters delivered, and thereby removed.
+	Delivered uint64
+	// Remaining is the number of dead letters of the webhook left to replay,
+	// when replaying all of them.
+	Remaining uint64
+}
+
+// DeliveryConfig configures the delivery of messages to webhooks.
+type DeliveryConfig struct {
+	// MaxAttempts is the number of attempts to deliver a message before it is dead-lettered.
+	MaxAttempts uint64
+	// RetryInterval is the delay before the first retry. The delay doubles with
+	// every retry, up to MaxRetryInterval.
+	RetryInterval    time.Duration
+	MaxRetryInterval time.Duration
+	// DisableAfter is the number of consecutive failed deliveries after which a
+	// webhook is disabled. Zero never disables webhooks.
+	DisableAfter uint64
+	// SecretGracePeriod is the period after a secret rotation during which
+	// messages are signed with the previous secret as well.
+	SecretGracePeriod time.Duration
+	// Batch configures the batching of messages forwarded to webhooks that
+	// have batching enabled.
+	Batch BatchConfig
+	// QueueSize is the number of deliveries waiting per webhook, beyond which
+	// messages are left to the broker to redeliver. Zero uses a size of 1000.
+	QueueSize int
+	// DeliveriesMaxAge is the age after which logged deliveries are removed.
+	// Zero keeps them regardless of their age.
+	DeliveriesMaxAge time.Duration
+	// DeliveriesMaxCount is the number of the latest logged deliveries kept per
+	// webhook. Zero doesn't limit it.
+	DeliveriesMaxCount uint64
+	// DeadLettersMaxAge is the age after which dead letters are removed.
+	// Zero keeps them regardless of their age.
+	DeadLettersMaxAge time.Duration
+	// DeadLettersMaxCount is the number of the latest dead letters kept per
+	// webhook. Zero doesn't limit it.
+	DeadLettersMaxCount uint64
+	// InstanceID identifies the instance of the service, which the pending messages
+	// it delivers are leased to.
+	InstanceID string
+}
+
+// forwardFunc makes a single attempt to forward messages to a webhook.
+type forwardFunc func(ctx context.Context) (Response, error)
+
+// DeliveryRepository specifies a delivery log persistence API.
+type DeliveryRepository interface {
+	// Save persists the delivery.
+	Save(ctx context.Context, d Delivery) error
+
+	// RetrieveByWebhook retrieves the deliveries to the webhook identified by the
+	// provided ID, newest first unless the page direction is ascending.
+	RetrieveByWebhook(ctx context.Context, webhookID string, pm PageMetadata) (DeliveriesPage, error)
+
+	// Prune removes the deliveries logged before the provided time, unless it is zero,
+	// and all but the latest keep deliveries of each webhook, unless keep is zero.
+	Prune(ctx context.Context, before time.Time, keep uint64) error
+}
+
+// DeadLetterRepository specifies a dead letter persistence API.
+type DeadLetterRepository interface {
+	// Save persists the dead letter.
+	Save(ctx context.Context, dl DeadLetter) error
+
+	// RetrieveByID retrieves the dead letter having the provided ID.
+	RetrieveByID(ctx context.Context, id string) (DeadLetter, error)
+
+	// RetrieveByWebhook retrieves the dead letters of the webhook identified by the
+	// provided ID, newest first unless the page direction is ascending.
+	RetrieveByWebhook(ctx context.Context, webhookID string, pm PageMetadata) (DeadLettersPage, error)
+
+	// Remove removes the dead letters identified with the provided IDs.
+	Remove(ctx context.Context, ids ...string) error
+
+	// Prune removes the dead letters created before the provided time, unless it is zero,
+	// and all but the latest keep dead letters of each webhook, unless keep is zero.
+	Prune(ctx context.Context, before time.Time, keep uint64) error
+}
+
+// PendingMessageRepository specifies a pending message persistence API.
+type PendingMessageRepository interface {
+	// Save persists the pending messages, leased to the instance identified by the
+	// provided ID until the provided time.
+	Save(ctx context.Context, instanceID string, leaseUntil time.Time, pms ...PendingMessage) error
+
+	// RenewLeases extends the leases of the pending messages of the instance identified
+	// by the provided ID until the provided time. A zero time releases them.
+	RenewLeases(ctx context.Context, instanceID string, leaseUntil time.Time) error
+
+	// Claim leases at most limit pending messages whose lease expired to the instance
+	// identified by the provided ID until the provided time, and returns them oldest first.
+	Claim(ctx context.Context, instanceID string, leaseUntil time.Time, limit uint64) ([]PendingMessage, error)
+
+	// Remove removes the pending messages identified with the provided IDs.
+	Remove(ctx context.Context, ids ...string) error
+}
+
+func (ws *webhooksService) ListDeliveries(ctx context.Context, token, webhookID string, pm PageMetadata) (DeliveriesPage, error) {
+	if _, err := ws.authorizeWebhook(ctx, token, webhookID, domain.GroupViewer); err != nil {
+		return DeliveriesPage{}, err
+	}
+
+	return ws.deliveries.RetrieveByWebhook(ctx, webhookID, pm)
+}
+
+func (ws *webhooksService) ListDeadLetters(ctx context.Context, token, webhookID string, pm PageMetadata) (DeadLettersPage, error) {
+	if _, err := ws.authorizeWebhook(ctx, token, webhookID, domain.GroupViewer); err != nil {
+		return DeadLettersPage{}, err
+	}
+
+	return ws.deadLetters.RetrieveByWebhook(ctx, webhookID, pm)
+}
+
+func (ws *webhooksService) ReplayDeadLetters(ctx context.Context, token, webhookID string, ids ...string) (ReplayResult, error) {
+	wh, err := ws.authorizeWebhook(ctx, token, webhookID, domain.GroupEditor)
+	if err != nil {
+		return ReplayResult{}, err
+	}
+
+	var dls []DeadLetter
+	var remaining uint64
+	switch len(ids) {
+	case 0:
+		page, err := ws.deadLetters.RetrieveByWebhook(ctx, webhookID, PageMetadata{Limit: MaxReplay, Dir: "asc"})
+		if err != nil {
+			return ReplayResult{}, err
+		}
+		dls = page.DeadLetters
+		remaining = page.Total - uint64(len(dls))
+	default:
+		for _, id := range ids {
+			dl, err := ws.deadLetters.RetrieveByID(ctx, id)
+			if err != nil {
+				return ReplayResult{}, err
+			}
+			if dl.WebhookID != webhookID {
+				return ReplayResult{}, dbutil.ErrNotFound
+			}
+			dls = append(dls, dl)
+		}
+	}
+
+	res := ReplayResult{Total: uint64(len(dls)), Remaining: remaining}
+	for _, dl := range dls {
+		msgs := []protomfx.Webhook{dl.Message}
+		d := ws.newDelivery(msgs, wh)
+		err := ws.attempt(ctx, ws.forwardMessage(dl.Message, wh), &d)
+		ws.saveDelivery(ctx, d)
+		if err != nil {
+			continue
+		}
+
+		if err := ws.deadLetters.Remove(ctx, dl.ID); err != nil {
+			return res, err
+		}
+		res.Delivered++
+	}
+
+	// A replayed delivery proves the endpoint works again.
+	if res.Delivered > 0 && wh.Failures > 0 {
+		if err := ws.webhooks.ResetFailures(ctx, wh.ID); err != nil {
+			return res, err
+		}
+	}
+
+	return res, nil
+}
+
+// deliver forwards the messages to the webhook, retrying failed attempts with an
+// exponential backoff, and logs the delivery. The webhook is retrieved before each
+// attempt, so that messages aren't forwarded to a webhook that was removed, and are
+// dead-lettered once it's disabled. Messages that can't be delivered are dead-lettered
+// one by one, and count towards disabling the webhook once. Messages whose retries are
+// interrupted by stopping the delivery stay pending, to be delivered by another instance.
+func (ws *webhooksService) deliver(job deliveryJob) {
+	ctx := context.Background()
+	wh := job.webhook
+	d := ws.newDelivery(job.msgs, wh)
+
+	var err error
+	for d.Attempts < max(ws.config.MaxAttempts, 1) {
+		if d.Attempts > 0 && !ws.queue.wait(ws.retryBackoff(d.Attempts)) {
+			ws.saveDelivery(ctx, d)
+			return
+		}
+
+		current, ok := ws.currentWebhook(ctx, job)
+		if !ok {
+			if d.Attempts > 0 {
+				ws.saveDelivery(ctx, d)
+			}
+			return
+		}
+		wh = current
+
+		if err = ws.attempt(ctx, ws.forwardJob(job, wh), &d); err == nil {
+			break
+		}
+	}
+	ws.saveDelivery(ctx, d)
+
+	if err == nil {
+		ws.removePending(ctx, job)
+		if wh.Failures > 0 {
+			if err := ws.webhooks.ResetFailures(ctx, wh.ID); err != nil {
+				ws.logger.Warn(fmt.Sprintf("failed to reset failures of webhook %s: %s", wh.ID, err))
+			}
+		}
+		return
+	}
+
+	ws.deadLetter(ctx, job, d.Error, d.Attempts)
+
+	updated, err := ws.webhooks.IncrementFailures(ctx, wh.ID, ws.config.DisableAfter)
+	if err != nil {
+		ws.logger.Warn(fmt.Sprintf("failed to increment failures of webhook %s: %s", wh.ID, err))
+		return
+	}
+	if wh.Status == EnabledStatus && updated.Status == DisabledStatus {
+		ws.logger.Warn(fmt.Sprintf("webhook %s disabled after %d consecutive failed deliveries", wh.ID, updated.Failures))
+		ws.dropDeliveries(ctx, wh.ID, true)
+	}
+}
+
+// currentWebhook retrieves the current state of the webhook of the job, and reports
+// whether its messages are to be forwarded. The messages of a removed webhook are
+// dropped, and those of a disabled webhook are dead-lettered. The webhook of the job
+// is used if it can't be retrieved.
+func (ws *webhooksService) currentWebhook(ctx context.Context, job deliveryJob) (Webhook, bool) {
+	wh, err := ws.webhooks.RetrieveByID(ctx, job.webhook.ID)
+	switch {
+	case errors.Contains(err, dbutil.ErrNotFound):
+		ws.removePending(ctx, job)
+		return Webhook{}, false
+	case err != nil:
+		ws.logger.Warn(fmt.Sprintf("failed to retrieve webhook %s: %s", job.webhook.ID, err))
+		return job.webhook, true
+	case wh.Status == DisabledStatus:
+		ws.abort(job, errWebhookDisabled)
+		return Webhook{}, false
+	default:
+		return wh, true
+	}
+}
+
+// dropDeliveries removes the buffered and queued messages of the webhook, and
+// dead-letters them if the webhook was disabled rather than removed.
+func (ws *webhooksService) dropDeliveries(ctx context.Context, id string, disabled bool) {
+	jobs := ws.queue.remove(id)
+	if bt, ok := ws.batcher.remove(id); ok {
+		jobs = append(jobs, deliveryJob{webhook: bt.webhook, msgs: bt.msgs, pending: bt.pending, batch: true})
+	}
+
+	for _, job := range jobs {
+		switch {
+		case disabled:
+			ws.abort(job, errWebhookDisabled)
+		default:
+			// The pending messages of removed webhooks are removed along with them.
+			ws.removePending(ctx, job)
+		}
+	}
+}
+
+// abort dead-letters the messages of a delivery that wasn't attempted.
+func (ws *webhooksService) abort(job deliveryJob, err error) {
+	ws.logger.Warn(fmt.Sprintf("failed to deliver %d messages to webhook %s: %s", len(job.msgs), job.webhook.ID, err))
+	ws.deadLetter(context.Background(), job, err.Error(), 0)
+}
+
+// deadLetter dead-letters the messages of the job one by one, and removes them from
+// the pending messages.
+func (ws *webhooksService) deadLetter(ctx context.Context, job deliveryJob, reason string, attempts uint64) {
+	for _, msg := range job.msgs {
+		dl := DeadLetter{
+			WebhookID: job.webhook.ID,
+			Message:   msg,
+			Error:     reason,
+			Attempts:  attempts,
+			Created:   time.Now().UnixNano(),
+		}
+		if err := ws.saveDeadLetter(ctx, dl); err != nil {
+			// The messages stay pending, and are delivered again once this instance releases them.
+			ws.logger.Error(fmt.Sprintf("failed to dead-letter message for webhook %s: %s", job.webhook.ID, err))
+			return
+		}
+	}
+
+	ws.removePending(ctx, job)
+}
+
+// removePending removes the messages of the job from the pending messages.
+func (ws *webhooksService) removePending(ctx context.Context, job deliveryJob) {
+	if err := ws.pending.Remove(ctx, job.pending...); err != nil {
+		ws.logger.Warn(fmt.Sprintf("failed to remove pending messages of webhook %s: %s", job.webhook.ID, err))
+	}
+}
+
+// deliverBatch queues the delivery of the batched messages to the webhook in a single request.
+// The batch is dead-lettered if it doesn't fit in the queue, since its messages were already
+// acknowledged to the broker, and stays pending if the delivery is stopped.
+func (ws *webhooksService) deliverBatch(bt *batch) {
+	job := deliveryJob{webhook: bt.webhook, msgs: bt.msgs, pending: bt.pending, batch: true}
+	if err := ws.queue.enqueue(job); err != nil && err != ErrStopped {
+		ws.abort(job, err)
+	}
+}
+
+// forwardJob returns a function making a single attempt to forward the messages of the job
+// to the webhook.
+func (ws *webhooksService) forwardJob(job deliveryJob, wh Webhook) forwardFunc {
+	if job.batch {
+		return func(ctx context.Context) (Response, error) {
+			return ws.forwarder.ForwardBatch(ctx, job.msgs, wh)
+		}
+	}
+
+	return ws.forwardMessage(job.msgs[0], wh)
+}
+
+func (ws *webhooksService) forwardMessage(msg protomfx.Webhook, wh Webhook) forwardFunc {
+	return func(ctx context.Context) (Response, error) {
+		return ws.forwarder.Forward(ctx, msg, wh)
+	}
+}
+
+func (ws *webhooksService) newDelivery(msgs []protomfx.Webhook, wh Webhook) Delivery {
+	return Delivery{
+		WebhookID: wh.ID,
+		ThingID:   msgs[0].ThingId,
+		Messages:  uint64(len(msgs)),
+		Status:    DeliveryStatusFailed,
+		Created:   time.Now().UnixNano(),
+	}
+}
+
+// attempt makes a single attempt to forward the messages, and records its
+// outcome in the delivery.
+func (ws *webhooksService) attempt(ctx context.Context, forward forwardFunc, d *Delivery) error {
+	start := time.Now()
+	res, err := forward(ctx)
+
+	d.Attempts++
+	d.Latency = time.Since(start)
+	d.StatusCode = res.StatusCode
+	d.Response = string(res.Body)
+	if err != nil {
+		d.Status = DeliveryStatusFailed
+		d.Error = err.Error()
+		return err
+	}
+
+	d.Status = DeliveryStatusDelivered
+	d.Error = ""
+	return nil
+}
+
+func (ws *webhooksService) saveDelivery(ctx context.Context, d Delivery) {
+	id, err := ws.idProvider.ID()
+	if err == nil {
+		d.ID = id
+		err = ws.deliveries.Save(ctx, d)
+	}
+	if err != nil {
+		ws.logger.Warn(fmt.Sprintf("failed to log delivery to webhook %s: %s", d.WebhookID, err))
+	}
+}
+
+func (ws *webhooksService) saveDeadLetter(ctx context.Context, dl DeadLetter) error {
+	id, err := ws.idProvider.ID()
+	if err != nil {
+		return err
+	}
+	dl.ID = id
+
+	return ws.deadLetters.Save(ctx, dl)
+}
+
+// retryBackoff returns the delay between the given delivery attempt and the next one.
+func (ws *webhooksService) retryBackoff(attempts uint64) time.Duration {
+	backoff := ws.config.RetryInterval
+	for i := uint64(1); i < attempts && backoff < ws.config.MaxRetryInterval; i++ {
+		backoff *= 2
+	}
+	return min(backoff, ws.config.MaxRetryInterval)
+}
+
+// authorizeWebhook retrieves the webhook identified by the provided ID, if the
+// user is allowed the action on its group.
+func (ws *webhooksService) authorizeWebhook(ctx context.Context, token, id, action string) (Webhook, error) {
+	wh, err := ws.webhooks.RetrieveByID(ctx, id)
+	if err != nil {
+		return Webhook{}, err
+	}
+
+	if err := ws.things.CanUserAccessGroup(ctx, domain.UserAccessReq{Token: token, ID: wh.GroupID, Action: action}); err != nil {
+		return Webhook{}, errors.Wrap(errors.ErrAuthorization, err)
+	}
+
+	return wh, nil
+}
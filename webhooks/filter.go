@@ -0,0 +1,247 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package webhooks
+
+import (
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/MainfluxLabs/mainflux/pkg/errors"
+	"github.com/MainfluxLabs/mainflux/pkg/predicate"
+	protomfx "github.com/MainfluxLabs/mainflux/pkg/proto"
+)
+
+const (
+	OperatorAND = predicate.OperatorAND
+	OperatorOR  = predicate.OperatorOR
+
+	ComparatorEQ       = predicate.ComparatorEQ
+	ComparatorNEQ      = predicate.ComparatorNEQ
+	ComparatorGTE      = predicate.ComparatorGTE
+	ComparatorLTE      = predicate.ComparatorLTE
+	ComparatorGT       = predicate.ComparatorGT
+	ComparatorLT       = predicate.ComparatorLT
+	ComparatorContains = predicate.ComparatorContains
+	ComparatorIn       = predicate.ComparatorIn
+	ComparatorNotIn    = predicate.ComparatorNotIn
+	ComparatorMatches  = predicate.ComparatorMatches
+	ComparatorExists   = "exists"
+
+	singleLevelWildcard = "+"
+	multiLevelWildcard  = "#"
+
+	// patternCacheSize is the number of compiled matches condition patterns kept by the service.
+	patternCacheSize = 1000
+)
+
+var (
+	// ErrInvalidFilter indicates a malformed webhook filter.
+	ErrInvalidFilter = errors.New("invalid webhook filter")
+
+	comparators = []string{
+		ComparatorEQ, ComparatorNEQ, ComparatorGTE, ComparatorLTE, ComparatorGT, ComparatorLT,
+		ComparatorContains, ComparatorIn, ComparatorNotIn, ComparatorMatches, ComparatorExists,
+	}
+	numericComparators = []string{ComparatorGTE, ComparatorLTE, ComparatorGT, ComparatorLT}
+)
+
+// Filter selects the messages forwarded to a webhook. A message is forwarded
+// if its subtopic matches one of the subtopic patterns and its payload meets
+// the conditions. An empty filter forwards all messages.
+type Filter struct {
+	// Subtopics are MQTT-style subtopic patterns, with levels separated by
+	// slashes or dots. A + matches a single level, and a trailing # matches
+	// any number of levels, including none.
+	Subtopics []string `json:"subtopics,omitempty"`
+	// Operator joins the conditions, AND by default.
+	Operator   string      `json:"operator,omitempty"`
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// Condition is a predicate on a field of the JSON payload.
+type Condition struct {
+	// Field is the dot-separated path of the payload field, e.g. sensor.temp.
+	Field      string `json:"field"`
+	Comparator string `json:"comparator"`
+	// Value is the compared operand: a number for numeric comparators, a
+	// substring or an array element for contains, a list for in and not in,
+	// and a regular expression for matches. It is ignored by exists.
+	Value any `json:"value,omitempty"`
+}
+
+// IsEmpty reports whether the filter forwards all messages.
+func (f Filter) IsEmpty() bool {
+	return len(f.Subtopics) == 0 && len(f.Conditions) == 0
+}
+
+// Validate checks that the subtopic patterns and conditions are well formed.
+func (f Filter) Validate() error {
+	for _, pattern := range f.Subtopics {
+		if err := validatePattern(pattern); err != nil {
+			return err
+		}
+	}
+
+	if f.Operator != "" && f.Operator != OperatorAND && f.Operator != OperatorOR {
+		return errors.Wrap(ErrInvalidFilter, errors.New("invalid operator"))
+	}
+
+	for _, c := range f.Conditions {
+		if err := c.validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validatePattern(pattern string) error {
+	levels := subtopicLevels(pattern)
+	if len(levels) == 0 {
+		return errors.Wrap(ErrInvalidFilter, errors.New("empty subtopic pattern"))
+	}
+
+	for i, level := range levels {
+		if level == singleLevelWildcard || level == multiLevelWildcard && i == len(levels)-1 {
+			continue
+		}
+		if strings.ContainsAny(level, singleLevelWildcard+multiLevelWildcard) {
+			return errors.Wrap(ErrInvalidFilter, errors.New("misplaced wildcard in subtopic pattern"))
+		}
+	}
+
+	return nil
+}
+
+func (c Condition) validate() error {
+	if c.Field == "" {
+		return errors.Wrap(ErrInvalidFilter, errors.New("missing condition field"))
+	}
+
+	switch {
+	case !slices.Contains(comparators, c.Comparator):
+		return errors.Wrap(ErrInvalidFilter, errors.New("invalid condition comparator"))
+	case slices.Contains(numericComparators, c.Comparator):
+		if _, ok := predicate.ToFloat(c.Value); !ok {
+			return errors.Wrap(ErrInvalidFilter, errors.New("condition value must be a number"))
+		}
+	case c.Comparator == ComparatorIn, c.Comparator == ComparatorNotIn:
+		if _, ok := c.Value.([]any); !ok {
+			return errors.Wrap(ErrInvalidFilter, errors.New("condition value must be a list"))
+		}
+	case c.Comparator == ComparatorMatches:
+		pattern, ok := c.Value.(string)
+		if !ok {
+			return errors.Wrap(ErrInvalidFilter, errors.New("condition value must be a regular expression"))
+		}
+		if _, err := regexp.Compile(pattern); err != nil {
+			return errors.Wrap(ErrInvalidFilter, err)
+		}
+	}
+
+	return nil
+}
+
+// Matches reports whether the message is forwarded by the filter. The compiled
+// patterns of matches conditions are cached in patterns, unless it's nil.
+func (f Filter) Matches(msg protomfx.Webhook, patterns *predicate.Patterns) bool {
+	if len(f.Subtopics) > 0 && !slices.ContainsFunc(f.Subtopics, func(pattern string) bool {
+		return matchSubtopic(pattern, msg.Subtopic)
+	}) {
+		return false
+	}
+
+	if len(f.Conditions) == 0 {
+		return true
+	}
+
+	var payload any
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return false
+	}
+
+	// A payload holding multiple messages matches if any of them does.
+	if objs, ok := payload.([]any); ok {
+		return slices.ContainsFunc(objs, func(obj any) bool { return f.matchesPayload(obj, patterns) })
+	}
+
+	return f.matchesPayload(payload, patterns)
+}
+
+func (f Filter) matchesPayload(payload any, patterns *predicate.Patterns) bool {
+	obj, ok := payload.(map[string]any)
+	if !ok {
+		return false
+	}
+
+	if f.Operator == OperatorOR {
+		return slices.ContainsFunc(f.Conditions, func(c Condition) bool { return c.isMet(obj, patterns) })
+	}
+
+	for _, c := range f.Conditions {
+		if !c.isMet(obj, patterns) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c Condition) isMet(payload map[string]any, patterns *predicate.Patterns) bool {
+	value, ok := predicate.Field(payload, c.Field)
+	if !ok {
+		return false
+	}
+
+	switch c.Comparator {
+	case ComparatorExists:
+		return true
+	case ComparatorEQ:
+		return predicate.Equal(value, c.Value)
+	case ComparatorNEQ:
+		return !predicate.Equal(value, c.Value)
+	case ComparatorContains:
+		return predicate.Contains(value, c.Value)
+	case ComparatorIn:
+		return predicate.In(value, c.Value)
+	case ComparatorNotIn:
+		return predicate.NotIn(value, c.Value)
+	case ComparatorMatches:
+		return patterns.Matches(value, c.Value)
+	}
+
+	val, ok := predicate.ToFloat(value)
+	if !ok {
+		return false
+	}
+	threshold, ok := predicate.ToFloat(c.Value)
+	if !ok {
+		return false
+	}
+
+	return predicate.CompareNumbers(c.Comparator, val, threshold)
+}
+
+// matchSubtopic reports whether the subtopic matches the MQTT-style pattern.
+func matchSubtopic(pattern, subtopic string) bool {
+	pl, sl := subtopicLevels(pattern), subtopicLevels(subtopic)
+
+	for i, level := range pl {
+		if level == multiLevelWildcard {
+			return true
+		}
+		if i >= len(sl) || level != singleLevelWildcard && level != sl[i] {
+			return false
+		}
+	}
+
+	return len(pl) == len(sl)
+}
+
+// subtopicLevels splits the subtopic into its levels, which are separated by
+// slashes in MQTT topics and dots in normalized subtopics.
+func subtopicLevels(subtopic string) []string {
+	return strings.FieldsFunc(subtopic, func(r rune) bool { return r == '/' || r == '.' })
+}
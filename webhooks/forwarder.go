@@ -1,33 +1,152 @@
 package webhooks
 
 import (
+	"bytes"
 	"context"
+	"io"
 	"net/http"
+	"text/template"
+	"time"
 
-	clientshttp "github.com/MainfluxLabs/mainflux/pkg/clients/http"
 	"github.com/MainfluxLabs/mainflux/pkg/errors"
+	"github.com/MainfluxLabs/mainflux/pkg/lru"
 	protomfx "github.com/MainfluxLabs/mainflux/pkg/proto"
 )
 
-var errForward = errors.New("failed to forward message")
+const (
+	contentTypeHeader = "Content-Type"
+	contentTypeJSON   = "application/json"
+	forwardTimeout    = 30 * time.Second
+	// templateCacheSize is the number of parsed body templates kept by the forwarder.
+	templateCacheSize = 1000
+	// maxResponseSize is the size of the response body snippet kept in the delivery log.
+	maxResponseSize = 1024
+)
+
+var (
+	errForward          = errors.New("failed to forward message")
+	errUnexpectedStatus = errors.New("unexpected response status")
+)
+
+// Response represents the response of a webhook endpoint to a forwarded message.
+type Response struct {
+	StatusCode int
+	// Body is the beginning of the response body, up to 1 KiB.
+	Body []byte
+}
 
 type Forwarder interface {
-	// Forward method is used to forward the received webhook message to a certain url
-	Forward(ctx context.Context, webhook protomfx.Webhook, wh Webhook) error
+	// Forward method is used to forward the received webhook message to a certain url.
+	// The request body is built according to the webhook's template and format.
+	// It makes a single attempt, and fails unless the endpoint responds with a 2xx status.
+	Forward(ctx context.Context, webhook protomfx.Webhook, wh Webhook) (Response, error)
+
+	// ForwardBatch forwards the messages to the webhook in a single request,
+	// with a JSON array of the bodies of the messages as the request body.
+	ForwardBatch(ctx context.Context, webhooks []protomfx.Webhook, wh Webhook) (Response, error)
 }
 
 var _ Forwarder = (*forwarder)(nil)
 
-type forwarder struct{}
+type forwarder struct {
+	client *http.Client
+	// templates holds the parsed body templates, keyed by their text.
+	templates *lru.Cache[string, *template.Template]
+}
 
 func NewForwarder() Forwarder {
-	return &forwarder{}
+	return &forwarder{
+		client:    &http.Client{Timeout: forwardTimeout},
+		templates: lru.New[string, *template.Template](templateCacheSize, 0),
+	}
+}
+
+func (fw *forwarder) Forward(ctx context.Context, webhook protomfx.Webhook, wh Webhook) (Response, error) {
+	t, err := fw.template(wh)
+	if err != nil {
+		return Response{}, errors.Wrap(errForward, err)
+	}
+
+	body, contentType, err := buildBody(webhook, wh, t)
+	if err != nil {
+		return Response{}, errors.Wrap(errForward, err)
+	}
+
+	return fw.send(ctx, wh, body, contentType)
+}
+
+func (fw *forwarder) ForwardBatch(ctx context.Context, webhooks []protomfx.Webhook, wh Webhook) (Response, error) {
+	t, err := fw.template(wh)
+	if err != nil {
+		return Response{}, errors.Wrap(errForward, err)
+	}
+
+	body, err := buildBatchBody(webhooks, wh, t)
+	if err != nil {
+		return Response{}, errors.Wrap(errForward, err)
+	}
+
+	return fw.send(ctx, wh, body, contentTypeJSON)
 }
 
-func (fw *forwarder) Forward(_ context.Context, webhook protomfx.Webhook, wh Webhook) error {
-	if _, err := clientshttp.SendRequest(http.MethodPost, wh.Url, webhook.Payload, wh.Headers); err != nil {
-		return errors.Wrap(errForward, err)
+// template returns the parsed body template of the webhook, or nil if the webhook has none.
+// Templates are parsed once and cached, as they are executed for every forwarded message.
+func (fw *forwarder) template(wh Webhook) (*template.Template, error) {
+	if wh.Template == "" {
+		return nil, nil
+	}
+
+	if t, ok := fw.templates.Get(wh.Template); ok {
+		return t, nil
+	}
+
+	t, err := ParseTemplate(wh.Template)
+	if err != nil {
+		return nil, err
+	}
+	fw.templates.Add(wh.Template, t)
+
+	return t, nil
+}
+
+// send makes a single attempt to send the body to the webhook.
+func (fw *forwarder) send(ctx context.Context, wh Webhook, body []byte, contentType string) (Response, error) {
+	method := wh.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, wh.Url, bytes.NewReader(body))
+	if err != nil {
+		return Response{}, errors.Wrap(errForward, err)
+	}
+
+	for k, v := range wh.Headers {
+		req.Header.Set(k, v)
+	}
+	if req.Header.Get(contentTypeHeader) == "" {
+		req.Header.Set(contentTypeHeader, contentType)
+	}
+	// Each attempt is signed anew, so that retries and replays pass the receiver's timestamp check.
+	if sig := signatureHeader(wh, time.Now(), body); sig != "" {
+		req.Header.Set(SignatureHeader, sig)
+	}
+
+	resp, err := fw.client.Do(req)
+	if err != nil {
+		return Response{}, errors.Wrap(errForward, err)
+	}
+	defer resp.Body.Close()
+
+	resBody, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return Response{StatusCode: resp.StatusCode}, errors.Wrap(errForward, err)
+	}
+
+	res := Response{StatusCode: resp.StatusCode, Body: resBody}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return res, errors.Wrap(errForward, errUnexpectedStatus)
 	}
 
-	return nil
+	return res, nil
 }
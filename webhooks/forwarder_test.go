@@ -0,0 +1,205 @@
+package webhooks_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MainfluxLabs/mainflux/pkg/errors"
+	protomfx "github.com/MainfluxLabs/mainflux/pkg/proto"
+	"github.com/MainfluxLabs/mainflux/webhooks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForwardSignature(t *testing.T) {
+	var header string
+	var body []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get(webhooks.SignatureHeader)
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer ts.Close()
+
+	msg := protomfx.Webhook{ThingId: thingID, Payload: []byte(`{"key":"val"}`)}
+	secret := "whsec_current"
+	previous := "whsec_previous"
+
+	cases := []struct {
+		desc    string
+		webhook webhooks.Webhook
+		secrets []string
+	}{
+		{
+			desc:    "forward message to webhook without secret",
+			webhook: webhooks.Webhook{Url: ts.URL},
+			secrets: nil,
+		},
+		{
+			desc:    "forward message to webhook with secret",
+			webhook: webhooks.Webhook{Url: ts.URL, Secret: secret},
+			secrets: []string{secret},
+		},
+		{
+			desc:    "forward message to webhook during secret rotation",
+			webhook: webhooks.Webhook{Url: ts.URL, Secret: secret, PreviousSecret: previous, PreviousSecretExpiresAt: time.Now().Add(time.Hour).UnixNano()},
+			secrets: []string{secret, previous},
+		},
+		{
+			desc:    "forward message to webhook after secret rotation",
+			webhook: webhooks.Webhook{Url: ts.URL, Secret: secret, PreviousSecret: previous, PreviousSecretExpiresAt: time.Now().Add(-time.Hour).UnixNano()},
+			secrets: []string{secret},
+		},
+	}
+
+	fwd := webhooks.NewForwarder()
+	for _, tc := range cases {
+		_, err := fwd.Forward(context.Background(), msg, tc.webhook)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, msg.Payload, body, fmt.Sprintf("%s: expected body %s got %s", tc.desc, msg.Payload, body))
+
+		if len(tc.secrets) == 0 {
+			assert.Empty(t, header, fmt.Sprintf("%s: expected no signature got %s", tc.desc, header))
+			continue
+		}
+
+		parts := strings.Split(header, ",")
+		require.Len(t, parts, len(tc.secrets)+1, fmt.Sprintf("%s: unexpected signature header %s", tc.desc, header))
+		timestamp, err := strconv.ParseInt(strings.TrimPrefix(parts[0], "t="), 10, 64)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		for i, s := range tc.secrets {
+			expected := fmt.Sprintf("%s=%s", webhooks.SignatureScheme, webhooks.Sign(s, timestamp, msg.Payload))
+			assert.Equal(t, expected, parts[i+1], fmt.Sprintf("%s: expected signature %s got %s", tc.desc, expected, parts[i+1]))
+		}
+	}
+}
+
+func TestForwardBody(t *testing.T) {
+	var method, contentType string
+	var body []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		contentType = r.Header.Get("Content-Type")
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer ts.Close()
+
+	msg := protomfx.Webhook{
+		ThingId:  thingID,
+		Subtopic: "sensors.temp",
+		Payload:  []byte(`{"temp":21.5,"unit":"C","tags":["a","b"],"loc":{"room":"lab"}}`),
+		Created:  1700000000000000000,
+	}
+	slack := `{"text":"{{.ThingID}} on {{.Subtopic}} reported {{.Payload.temp}}{{.Payload.unit}}","raw":{{json .Raw}}}`
+
+	cases := []struct {
+		desc        string
+		webhook     webhooks.Webhook
+		method      string
+		contentType string
+		body        string
+		err         error
+	}{
+		{
+			desc:        "forward raw payload",
+			webhook:     webhooks.Webhook{Url: ts.URL},
+			method:      http.MethodPost,
+			contentType: "application/json",
+			body:        string(msg.Payload),
+			err:         nil,
+		},
+		{
+			desc:        "forward rendered template with put",
+			webhook:     webhooks.Webhook{Url: ts.URL, Method: http.MethodPut, Template: slack},
+			method:      http.MethodPut,
+			contentType: "application/json",
+			body:        fmt.Sprintf(`{"text":"%s on sensors.temp reported 21.5C","raw":%q}`, thingID, msg.Payload),
+			err:         nil,
+		},
+		{
+			desc:        "forward payload as form",
+			webhook:     webhooks.Webhook{Url: ts.URL, Format: webhooks.FormatForm},
+			method:      http.MethodPost,
+			contentType: "application/x-www-form-urlencoded",
+			body:        "loc.room=lab&tags=a&tags=b&temp=21.5&unit=C",
+			err:         nil,
+		},
+		{
+			desc:        "forward rendered template as xml",
+			webhook:     webhooks.Webhook{Url: ts.URL, Method: http.MethodPatch, Template: `{"thing":"{{.ThingID}}","value":{{json .Payload.temp}},"tags":{{json .Payload.tags}}}`, Format: webhooks.FormatXML},
+			method:      http.MethodPatch,
+			contentType: "application/xml",
+			body:        fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>`+"\n"+`<message><tags>a</tags><tags>b</tags><thing>%s</thing><value>21.5</value></message>`, thingID),
+			err:         nil,
+		},
+		{
+			desc:    "forward non-object payload as form",
+			webhook: webhooks.Webhook{Url: ts.URL, Template: `[1,2]`, Format: webhooks.FormatForm},
+			err:     webhooks.ErrInvalidObject,
+		},
+		{
+			desc:    "forward with failing template",
+			webhook: webhooks.Webhook{Url: ts.URL, Template: `{{index .Payload.tags 5}}`},
+			err:     webhooks.ErrInvalidTemplate,
+		},
+	}
+
+	fwd := webhooks.NewForwarder()
+	for _, tc := range cases {
+		method, contentType, body = "", "", nil
+		_, err := fwd.Forward(context.Background(), msg, tc.webhook)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s got %s", tc.desc, tc.err, err))
+		if tc.err != nil {
+			continue
+		}
+		assert.Equal(t, tc.method, method, fmt.Sprintf("%s: expected method %s got %s", tc.desc, tc.method, method))
+		assert.Equal(t, tc.contentType, contentType, fmt.Sprintf("%s: expected content type %s got %s", tc.desc, tc.contentType, contentType))
+		assert.Equal(t, tc.body, string(body), fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.body, body))
+	}
+}
+
+func TestForwardBatch(t *testing.T) {
+	var contentType string
+	var body []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer ts.Close()
+
+	msgs := []protomfx.Webhook{
+		{ThingId: thingID, Subtopic: "temp", Payload: []byte(`{"temp":21.5}`)},
+		{ThingId: thingID, Subtopic: "status", Payload: []byte(`ok`)},
+	}
+
+	cases := []struct {
+		desc    string
+		webhook webhooks.Webhook
+		body    string
+	}{
+		{
+			desc:    "forward batch of payloads",
+			webhook: webhooks.Webhook{Url: ts.URL, Batch: true},
+			body:    `[{"temp":21.5},"ok"]`,
+		},
+		{
+			desc:    "forward batch of rendered templates",
+			webhook: webhooks.Webhook{Url: ts.URL, Batch: true, Template: `{"subtopic":"{{.Subtopic}}","raw":{{json .Raw}}}`},
+			body:    `[{"subtopic":"temp","raw":"{\"temp\":21.5}"},{"subtopic":"status","raw":"ok"}]`,
+		},
+	}
+
+	fw := webhooks.NewForwarder()
+	for _, tc := range cases {
+		_, err := fw.ForwardBatch(context.Background(), msgs, tc.webhook)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, "application/json", contentType, fmt.Sprintf("%s: expected content type application/json got %s", tc.desc, contentType))
+		assert.JSONEq(t, tc.body, string(body), fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.body, body))
+	}
+}
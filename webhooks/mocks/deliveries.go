@@ -0,0 +1,176 @@
+package mocks
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/MainfluxLabs/mainflux/pkg/dbutil"
+	"github.com/MainfluxLabs/mainflux/webhooks"
+)
+
+var (
+	_ webhooks.DeliveryRepository   = (*deliveryRepositoryMock)(nil)
+	_ webhooks.DeadLetterRepository = (*deadLetterRepositoryMock)(nil)
+)
+
+type deliveryRepositoryMock struct {
+	mu         sync.Mutex
+	deliveries []webhooks.Delivery
+}
+
+// NewDeliveryRepository creates an in-memory delivery repository.
+func NewDeliveryRepository() webhooks.DeliveryRepository {
+	return &deliveryRepositoryMock{}
+}
+
+func (drm *deliveryRepositoryMock) Save(_ context.Context, d webhooks.Delivery) error {
+	drm.mu.Lock()
+	defer drm.mu.Unlock()
+
+	drm.deliveries = append(drm.deliveries, d)
+	return nil
+}
+
+func (drm *deliveryRepositoryMock) RetrieveByWebhook(_ context.Context, webhookID string, pm webhooks.PageMetadata) (webhooks.DeliveriesPage, error) {
+	drm.mu.Lock()
+	defer drm.mu.Unlock()
+
+	var items []webhooks.Delivery
+	for _, d := range drm.deliveries {
+		if d.WebhookID == webhookID {
+			items = append(items, d)
+		}
+	}
+
+	return webhooks.DeliveriesPage{
+		Total:      uint64(len(items)),
+		Deliveries: page(items, pm),
+	}, nil
+}
+
+func (drm *deliveryRepositoryMock) Prune(_ context.Context, before time.Time, keep uint64) error {
+	drm.mu.Lock()
+	defer drm.mu.Unlock()
+
+	kept := map[string]uint64{}
+	var deliveries []webhooks.Delivery
+	// Deliveries are kept in ascending order, so the latest ones are walked first backwards.
+	for i := len(drm.deliveries) - 1; i >= 0; i-- {
+		d := drm.deliveries[i]
+		if !before.IsZero() && d.Created < before.UnixNano() {
+			continue
+		}
+		if keep > 0 && kept[d.WebhookID] >= keep {
+			continue
+		}
+		kept[d.WebhookID]++
+		deliveries = append(deliveries, d)
+	}
+	slices.Reverse(deliveries)
+	drm.deliveries = deliveries
+
+	return nil
+}
+
+type deadLetterRepositoryMock struct {
+	mu          sync.Mutex
+	deadLetters []webhooks.DeadLetter
+}
+
+// NewDeadLetterRepository creates an in-memory dead letter repository.
+func NewDeadLetterRepository() webhooks.DeadLetterRepository {
+	return &deadLetterRepositoryMock{}
+}
+
+func (dlrm *deadLetterRepositoryMock) Save(_ context.Context, dl webhooks.DeadLetter) error {
+	dlrm.mu.Lock()
+	defer dlrm.mu.Unlock()
+
+	dlrm.deadLetters = append(dlrm.deadLetters, dl)
+	return nil
+}
+
+func (dlrm *deadLetterRepositoryMock) RetrieveByID(_ context.Context, id string) (webhooks.DeadLetter, error) {
+	dlrm.mu.Lock()
+	defer dlrm.mu.Unlock()
+
+	for _, dl := range dlrm.deadLetters {
+		if dl.ID == id {
+			return dl, nil
+		}
+	}
+
+	return webhooks.DeadLetter{}, dbutil.ErrNotFound
+}
+
+func (dlrm *deadLetterRepositoryMock) RetrieveByWebhook(_ context.Context, webhookID string, pm webhooks.PageMetadata) (webhooks.DeadLettersPage, error) {
+	dlrm.mu.Lock()
+	defer dlrm.mu.Unlock()
+
+	var items []webhooks.DeadLetter
+	for _, dl := range dlrm.deadLetters {
+		if dl.WebhookID == webhookID {
+			items = append(items, dl)
+		}
+	}
+
+	return webhooks.DeadLettersPage{
+		Total:       uint64(len(items)),
+		DeadLetters: page(items, pm),
+	}, nil
+}
+
+func (dlrm *deadLetterRepositoryMock) Remove(_ context.Context, ids ...string) error {
+	dlrm.mu.Lock()
+	defer dlrm.mu.Unlock()
+
+	dlrm.deadLetters = slices.DeleteFunc(dlrm.deadLetters, func(dl webhooks.DeadLetter) bool {
+		return slices.Contains(ids, dl.ID)
+	})
+	return nil
+}
+
+func (dlrm *deadLetterRepositoryMock) Prune(_ context.Context, before time.Time, keep uint64) error {
+	dlrm.mu.Lock()
+	defer dlrm.mu.Unlock()
+
+	kept := map[string]uint64{}
+	var deadLetters []webhooks.DeadLetter
+	// Dead letters are kept in ascending order, so the latest ones are walked first backwards.
+	for i := len(dlrm.deadLetters) - 1; i >= 0; i-- {
+		dl := dlrm.deadLetters[i]
+		if !before.IsZero() && dl.Created < before.UnixNano() {
+			continue
+		}
+		if keep > 0 && kept[dl.WebhookID] >= keep {
+			continue
+		}
+		kept[dl.WebhookID]++
+		deadLetters = append(deadLetters, dl)
+	}
+	slices.Reverse(deadLetters)
+	dlrm.deadLetters = deadLetters
+
+	return nil
+}
+
+// page returns the page of items, which are kept in ascending order but are
+// listed in descending order by default.
+func page[T any](items []T, pm webhooks.PageMetadata) []T {
+	if pm.Dir != "asc" {
+		slices.Reverse(items)
+	}
+
+	total := uint64(len(items))
+	if pm.Offset >= total {
+		return nil
+	}
+	end := total
+	if pm.Limit > 0 && pm.Offset+pm.Limit < total {
+		end = pm.Offset + pm.Limit
+	}
+
+	return items[pm.Offset:end]
+}
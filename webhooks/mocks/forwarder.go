@@ -2,12 +2,21 @@ package mocks
 
 import (
 	"context"
+	"net/http"
 
+	"github.com/MainfluxLabs/mainflux/pkg/errors"
 	protomfx "github.com/MainfluxLabs/mainflux/pkg/proto"
 	"github.com/MainfluxLabs/mainflux/webhooks"
 )
 
-var _ webhooks.Forwarder = (*forwarder)(nil)
+// FailingURL is the webhook URL the mock forwarder fails to forward messages to.
+const FailingURL = "https://failing.webhook.com"
+
+var (
+	_ webhooks.Forwarder = (*forwarder)(nil)
+
+	errForward = errors.New("failed to forward message")
+)
 
 type forwarder struct{}
 
@@ -15,6 +24,14 @@ func NewForwarder() webhooks.Forwarder {
 	return &forwarder{}
 }
 
-func (mf *forwarder) Forward(context.Context, protomfx.Webhook, webhooks.Webhook) error {
-	return nil
+func (mf *forwarder) Forward(_ context.Context, _ protomfx.Webhook, wh webhooks.Webhook) (webhooks.Response, error) {
+	if wh.Url == FailingURL {
+		return webhooks.Response{StatusCode: http.StatusInternalServerError, Body: []byte("internal error")}, errForward
+	}
+
+	return webhooks.Response{StatusCode: http.StatusOK}, nil
+}
+
+func (mf *forwarder) ForwardBatch(ctx context.Context, _ []protomfx.Webhook, wh webhooks.Webhook) (webhooks.Response, error) {
+	return mf.Forward(ctx, protomfx.Webhook{}, wh)
 }
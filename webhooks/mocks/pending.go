@@ -0,0 +1,79 @@
+package mocks
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/MainfluxLabs/mainflux/webhooks"
+)
+
+var _ webhooks.PendingMessageRepository = (*pendingMessageRepositoryMock)(nil)
+
+type pendingMessage struct {
+	webhooks.PendingMessage
+	instanceID string
+	leaseUntil time.Time
+}
+
+type pendingMessageRepositoryMock struct {
+	mu       sync.Mutex
+	messages []pendingMessage
+}
+
+// NewPendingMessageRepository creates an in-memory pending message repository.
+func NewPendingMessageRepository() webhooks.PendingMessageRepository {
+	return &pendingMessageRepositoryMock{}
+}
+
+func (pmrm *pendingMessageRepositoryMock) Save(_ context.Context, instanceID string, leaseUntil time.Time, pms ...webhooks.PendingMessage) error {
+	pmrm.mu.Lock()
+	defer pmrm.mu.Unlock()
+
+	for _, pm := range pms {
+		pmrm.messages = append(pmrm.messages, pendingMessage{PendingMessage: pm, instanceID: instanceID, leaseUntil: leaseUntil})
+	}
+	return nil
+}
+
+func (pmrm *pendingMessageRepositoryMock) RenewLeases(_ context.Context, instanceID string, leaseUntil time.Time) error {
+	pmrm.mu.Lock()
+	defer pmrm.mu.Unlock()
+
+	for i := range pmrm.messages {
+		if pmrm.messages[i].instanceID == instanceID {
+			pmrm.messages[i].leaseUntil = leaseUntil
+		}
+	}
+	return nil
+}
+
+func (pmrm *pendingMessageRepositoryMock) Claim(_ context.Context, instanceID string, leaseUntil time.Time, limit uint64) ([]webhooks.PendingMessage, error) {
+	pmrm.mu.Lock()
+	defer pmrm.mu.Unlock()
+
+	now := time.Now()
+	var pms []webhooks.PendingMessage
+	for i := range pmrm.messages {
+		if uint64(len(pms)) >= limit {
+			break
+		}
+		if pmrm.messages[i].leaseUntil.Before(now) {
+			pmrm.messages[i].instanceID = instanceID
+			pmrm.messages[i].leaseUntil = leaseUntil
+			pms = append(pms, pmrm.messages[i].PendingMessage)
+		}
+	}
+	return pms, nil
+}
+
+func (pmrm *pendingMessageRepositoryMock) Remove(_ context.Context, ids ...string) error {
+	pmrm.mu.Lock()
+	defer pmrm.mu.Unlock()
+
+	pmrm.messages = slices.DeleteFunc(pmrm.messages, func(pm pendingMessage) bool {
+		return slices.Contains(ids, pm.ID)
+	})
+	return nil
+}
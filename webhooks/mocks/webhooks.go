@@ -102,14 +102,82 @@ func (wrm *webhookRepositoryMock) Update(_ context.Context, w webhooks.Webhook)
 	wrm.mu.Lock()
 	defer wrm.mu.Unlock()
 
-	if _, ok := wrm.webhooks[w.ID]; !ok {
+	wh, ok := wrm.webhooks[w.ID]
+	if !ok {
 		return dbutil.ErrNotFound
 	}
+	w.Status = wh.Status
+	w.Failures = wh.Failures
+	w.Secret = wh.Secret
+	w.PreviousSecret = wh.PreviousSecret
+	w.PreviousSecretExpiresAt = wh.PreviousSecretExpiresAt
 	wrm.webhooks[w.ID] = w
 
 	return nil
 }
 
+func (wrm *webhookRepositoryMock) UpdateSecret(_ context.Context, w webhooks.Webhook) error {
+	wrm.mu.Lock()
+	defer wrm.mu.Unlock()
+
+	wh, ok := wrm.webhooks[w.ID]
+	if !ok {
+		return dbutil.ErrNotFound
+	}
+	wh.Secret = w.Secret
+	wh.PreviousSecret = w.PreviousSecret
+	wh.PreviousSecretExpiresAt = w.PreviousSecretExpiresAt
+	wrm.webhooks[w.ID] = wh
+
+	return nil
+}
+
+func (wrm *webhookRepositoryMock) UpdateStatus(_ context.Context, id, status string) error {
+	wrm.mu.Lock()
+	defer wrm.mu.Unlock()
+
+	wh, ok := wrm.webhooks[id]
+	if !ok {
+		return dbutil.ErrNotFound
+	}
+	wh.Status = status
+	wh.Failures = 0
+	wrm.webhooks[id] = wh
+
+	return nil
+}
+
+func (wrm *webhookRepositoryMock) IncrementFailures(_ context.Context, id string, disableAfter uint64) (webhooks.Webhook, error) {
+	wrm.mu.Lock()
+	defer wrm.mu.Unlock()
+
+	wh, ok := wrm.webhooks[id]
+	if !ok {
+		return webhooks.Webhook{}, dbutil.ErrNotFound
+	}
+	wh.Failures++
+	if disableAfter > 0 && wh.Failures >= disableAfter {
+		wh.Status = webhooks.DisabledStatus
+	}
+	wrm.webhooks[id] = wh
+
+	return wh, nil
+}
+
+func (wrm *webhookRepositoryMock) ResetFailures(_ context.Context, id string) error {
+	wrm.mu.Lock()
+	defer wrm.mu.Unlock()
+
+	wh, ok := wrm.webhooks[id]
+	if !ok {
+		return dbutil.ErrNotFound
+	}
+	wh.Failures = 0
+	wrm.webhooks[id] = wh
+
+	return nil
+}
+
 func (wrm *webhookRepositoryMock) Remove(_ context.Context, ids ...string) error {
 	wrm.mu.Lock()
 	defer wrm.mu.Unlock()
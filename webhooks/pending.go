@@ -0,0 +1,107 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/MainfluxLabs/mainflux/pkg/dbutil"
+	"github.com/MainfluxLabs/mainflux/pkg/errors"
+	protomfx "github.com/MainfluxLabs/mainflux/pkg/proto"
+)
+
+const (
+	// leaseDuration is the time the pending messages stay leased to an instance of the
+	// service without their lease being renewed.
+	leaseDuration = time.Minute
+	// leaseInterval is the interval at which an instance renews the leases of its pending
+	// messages, and claims the pending messages whose lease expired.
+	leaseInterval = leaseDuration / 3
+)
+
+// savePending persists the message as pending for each of the webhooks, and returns the
+// pending messages in the order of the webhooks.
+func (ws *webhooksService) savePending(ctx context.Context, msg protomfx.Webhook, whs []Webhook) ([]PendingMessage, error) {
+	pms := make([]PendingMessage, 0, len(whs))
+	for _, wh := range whs {
+		id, err := ws.idProvider.ID()
+		if err != nil {
+			return nil, err
+		}
+		pms = append(pms, PendingMessage{
+			ID:        id,
+			WebhookID: wh.ID,
+			Message:   msg,
+			Created:   time.Now().UnixNano(),
+		})
+	}
+
+	if err := ws.pending.Save(ctx, ws.config.InstanceID, time.Now().Add(leaseDuration), pms...); err != nil {
+		return nil, err
+	}
+
+	return pms, nil
+}
+
+// renewPending extends the leases of the pending messages of this instance, and queues the
+// delivery of the pending messages whose lease expired, e.g. because their instance crashed.
+func (ws *webhooksService) renewPending(ctx context.Context) {
+	until := time.Now().Add(leaseDuration)
+	if err := ws.pending.RenewLeases(ctx, ws.config.InstanceID, until); err != nil {
+		ws.logger.Error(fmt.Sprintf("failed to renew leases of pending webhook messages: %s", err))
+		return
+	}
+
+	pms, err := ws.pending.Claim(ctx, ws.config.InstanceID, until, uint64(ws.queue.size))
+	if err != nil {
+		ws.logger.Error(fmt.Sprintf("failed to claim pending webhook messages: %s", err))
+		return
+	}
+
+	whs := map[string]Webhook{}
+	for _, pm := range pms {
+		wh, ok := whs[pm.WebhookID]
+		if !ok {
+			wh = ws.claimedWebhook(ctx, pm.WebhookID)
+			whs[pm.WebhookID] = wh
+		}
+
+		job := deliveryJob{webhook: wh, msgs: []protomfx.Webhook{pm.Message}, pending: []string{pm.ID}}
+		switch {
+		case wh.Status == DisabledStatus:
+			ws.abort(job, errWebhookDisabled)
+		case wh.Batch:
+			ws.batcher.add(wh, pm.Message, pm.ID)
+		default:
+			if err := ws.queue.enqueue(job); err != nil && err != ErrStopped {
+				ws.abort(job, err)
+			}
+		}
+	}
+}
+
+// claimedWebhook retrieves the webhook of claimed pending messages. A webhook that
+// can't be retrieved is assumed to be enabled, since its messages are only forwarded
+// once it's retrieved again before the delivery attempt.
+func (ws *webhooksService) claimedWebhook(ctx context.Context, id string) Webhook {
+	wh, err := ws.webhooks.RetrieveByID(ctx, id)
+	if err != nil {
+		if !errors.Contains(err, dbutil.ErrNotFound) {
+			ws.logger.Warn(fmt.Sprintf("failed to retrieve webhook %s: %s", id, err))
+		}
+		return Webhook{ID: id, Status: EnabledStatus}
+	}
+
+	return wh
+}
+
+// releasePending releases the leases of the pending messages of this instance, so that
+// other instances deliver them.
+func (ws *webhooksService) releasePending(ctx context.Context) {
+	if err := ws.pending.RenewLeases(ctx, ws.config.InstanceID, time.Time{}); err != nil {
+		ws.logger.Error(fmt.Sprintf("failed to release pending webhook messages: %s", err))
+	}
+}
@@ -0,0 +1,319 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/MainfluxLabs/mainflux/pkg/dbutil"
+	"github.com/MainfluxLabs/mainflux/pkg/errors"
+	protomfx "github.com/MainfluxLabs/mainflux/pkg/proto"
+	"github.com/MainfluxLabs/mainflux/webhooks"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	_ webhooks.DeliveryRepository   = (*deliveryRepository)(nil)
+	_ webhooks.DeadLetterRepository = (*deadLetterRepository)(nil)
+)
+
+type deliveryRepository struct {
+	db dbutil.Database
+}
+
+// NewDeliveryRepository instantiates a PostgreSQL implementation of delivery repository.
+func NewDeliveryRepository(db dbutil.Database) webhooks.DeliveryRepository {
+	return &deliveryRepository{
+		db: db,
+	}
+}
+
+func (dr deliveryRepository) Save(ctx context.Context, d webhooks.Delivery) error {
+	q := `INSERT INTO webhook_deliveries (id, webhook_id, thing_id, messages, status, status_code, latency, attempts, response, error, created)
+		VALUES (:id, :webhook_id, :thing_id, :messages, :status, :status_code, :latency, :attempts, :response, :error, :created);`
+
+	if _, err := dr.db.NamedExecContext(ctx, q, toDBDelivery(d)); err != nil {
+		return errors.Wrap(dbutil.ErrCreateEntity, err)
+	}
+
+	return nil
+}
+
+func (dr deliveryRepository) RetrieveByWebhook(ctx context.Context, webhookID string, pm webhooks.PageMetadata) (webhooks.DeliveriesPage, error) {
+	olq := dbutil.GetOffsetLimitQuery(pm.Limit)
+	q := fmt.Sprintf(`SELECT id, webhook_id, thing_id, messages, status, status_code, latency, attempts, response, error, created
+		FROM webhook_deliveries WHERE webhook_id = :webhook_id ORDER BY created %s %s;`, dbutil.GetDirQuery(pm.Dir), olq)
+	qc := `SELECT COUNT(*) FROM webhook_deliveries WHERE webhook_id = :webhook_id;`
+
+	params := map[string]any{
+		"webhook_id": webhookID,
+		"limit":      pm.Limit,
+		"offset":     pm.Offset,
+	}
+
+	rows, err := dr.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return webhooks.DeliveriesPage{}, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+	}
+	defer rows.Close()
+
+	var items []webhooks.Delivery
+	for rows.Next() {
+		var dbd dbDelivery
+		if err := rows.StructScan(&dbd); err != nil {
+			return webhooks.DeliveriesPage{}, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+		}
+		items = append(items, toDelivery(dbd))
+	}
+
+	total, err := dbutil.Total(ctx, dr.db, qc, params)
+	if err != nil {
+		return webhooks.DeliveriesPage{}, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+	}
+
+	return webhooks.DeliveriesPage{
+		Total:      total,
+		Deliveries: items,
+	}, nil
+}
+
+func (dr deliveryRepository) Prune(ctx context.Context, before time.Time, keep uint64) error {
+	if !before.IsZero() {
+		q := `DELETE FROM webhook_deliveries WHERE id IN (
+			SELECT id FROM webhook_deliveries WHERE created < :before LIMIT :batch
+		);`
+
+		if err := dbutil.DeleteInBatches(ctx, dr.db, q, map[string]any{"before": before.UnixNano()}); err != nil {
+			return errors.Wrap(dbutil.ErrRemoveEntity, err)
+		}
+	}
+
+	if keep > 0 {
+		// The deliveries of each webhook are walked newest first on its index, skipping the kept ones.
+		q := `DELETE FROM webhook_deliveries WHERE id IN (
+			SELECT d.id FROM webhooks w, LATERAL (
+				SELECT id FROM webhook_deliveries
+				WHERE webhook_id = w.id
+				ORDER BY created DESC
+				OFFSET :keep
+			) d
+			LIMIT :batch
+		);`
+
+		if err := dbutil.DeleteInBatches(ctx, dr.db, q, map[string]any{"keep": keep}); err != nil {
+			return errors.Wrap(dbutil.ErrRemoveEntity, err)
+		}
+	}
+
+	return nil
+}
+
+type deadLetterRepository struct {
+	db dbutil.Database
+}
+
+// NewDeadLetterRepository instantiates a PostgreSQL implementation of dead letter repository.
+func NewDeadLetterRepository(db dbutil.Database) webhooks.DeadLetterRepository {
+	return &deadLetterRepository{
+		db: db,
+	}
+}
+
+func (dlr deadLetterRepository) Save(ctx context.Context, dl webhooks.DeadLetter) error {
+	q := `INSERT INTO webhook_dead_letters (id, webhook_id, thing_id, subtopic, payload, message_created, error, attempts, created)
+		VALUES (:id, :webhook_id, :thing_id, :subtopic, :payload, :message_created, :error, :attempts, :created);`
+
+	if _, err := dlr.db.NamedExecContext(ctx, q, toDBDeadLetter(dl)); err != nil {
+		return errors.Wrap(dbutil.ErrCreateEntity, err)
+	}
+
+	return nil
+}
+
+func (dlr deadLetterRepository) RetrieveByID(ctx context.Context, id string) (webhooks.DeadLetter, error) {
+	q := `SELECT id, webhook_id, thing_id, subtopic, payload, message_created, error, attempts, created FROM webhook_dead_letters WHERE id = $1;`
+
+	var dbdl dbDeadLetter
+	if err := dlr.db.QueryRowxContext(ctx, q, id).StructScan(&dbdl); err != nil {
+		pgErr, ok := err.(*pgconn.PgError)
+		//  If there is no result or ID is in an invalid format, return ErrNotFound.
+		if err == sql.ErrNoRows || ok && pgerrcode.InvalidTextRepresentation == pgErr.Code {
+			return webhooks.DeadLetter{}, errors.Wrap(dbutil.ErrNotFound, err)
+		}
+		return webhooks.DeadLetter{}, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+	}
+
+	return toDeadLetter(dbdl), nil
+}
+
+func (dlr deadLetterRepository) RetrieveByWebhook(ctx context.Context, webhookID string, pm webhooks.PageMetadata) (webhooks.DeadLettersPage, error) {
+	olq := dbutil.GetOffsetLimitQuery(pm.Limit)
+	q := fmt.Sprintf(`SELECT id, webhook_id, thing_id, subtopic, payload, message_created, error, attempts, created
+		FROM webhook_dead_letters WHERE webhook_id = :webhook_id ORDER BY created %s %s;`, dbutil.GetDirQuery(pm.Dir), olq)
+	qc := `SELECT COUNT(*) FROM webhook_dead_letters WHERE webhook_id = :webhook_id;`
+
+	params := map[string]any{
+		"webhook_id": webhookID,
+		"limit":      pm.Limit,
+		"offset":     pm.Offset,
+	}
+
+	rows, err := dlr.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return webhooks.DeadLettersPage{}, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+	}
+	defer rows.Close()
+
+	var items []webhooks.DeadLetter
+	for rows.Next() {
+		var dbdl dbDeadLetter
+		if err := rows.StructScan(&dbdl); err != nil {
+			return webhooks.DeadLettersPage{}, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+		}
+		items = append(items, toDeadLetter(dbdl))
+	}
+
+	total, err := dbutil.Total(ctx, dlr.db, qc, params)
+	if err != nil {
+		return webhooks.DeadLettersPage{}, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+	}
+
+	return webhooks.DeadLettersPage{
+		Total:       total,
+		DeadLetters: items,
+	}, nil
+}
+
+func (dlr deadLetterRepository) Prune(ctx context.Context, before time.Time, keep uint64) error {
+	if !before.IsZero() {
+		q := `DELETE FROM webhook_dead_letters WHERE id IN (
+			SELECT id FROM webhook_dead_letters WHERE created < :before LIMIT :batch
+		);`
+
+		if err := dbutil.DeleteInBatches(ctx, dlr.db, q, map[string]any{"before": before.UnixNano()}); err != nil {
+			return errors.Wrap(dbutil.ErrRemoveEntity, err)
+		}
+	}
+
+	if keep > 0 {
+		// The dead letters of each webhook are walked newest first on its index, skipping the kept ones.
+		q := `DELETE FROM webhook_dead_letters WHERE id IN (
+			SELECT dl.id FROM webhooks w, LATERAL (
+				SELECT id FROM webhook_dead_letters
+				WHERE webhook_id = w.id
+				ORDER BY created DESC
+				OFFSET :keep
+			) dl
+			LIMIT :batch
+		);`
+
+		if err := dbutil.DeleteInBatches(ctx, dlr.db, q, map[string]any{"keep": keep}); err != nil {
+			return errors.Wrap(dbutil.ErrRemoveEntity, err)
+		}
+	}
+
+	return nil
+}
+
+func (dlr deadLetterRepository) Remove(ctx context.Context, ids ...string) error {
+	q := `DELETE FROM webhook_dead_letters WHERE id = :id;`
+
+	for _, id := range ids {
+		if _, err := dlr.db.NamedExecContext(ctx, q, dbDeadLetter{ID: id}); err != nil {
+			return errors.Wrap(dbutil.ErrRemoveEntity, err)
+		}
+	}
+
+	return nil
+}
+
+type dbDelivery struct {
+	ID         string `db:"id"`
+	WebhookID  string `db:"webhook_id"`
+	ThingID    string `db:"thing_id"`
+	Messages   uint64 `db:"messages"`
+	Status     string `db:"status"`
+	StatusCode int    `db:"status_code"`
+	// Latency is stored in milliseconds.
+	Latency  int64  `db:"latency"`
+	Attempts uint64 `db:"attempts"`
+	Response string `db:"response"`
+	Error    string `db:"error"`
+	Created  int64  `db:"created"`
+}
+
+func toDBDelivery(d webhooks.Delivery) dbDelivery {
+	return dbDelivery{
+		ID:         d.ID,
+		WebhookID:  d.WebhookID,
+		ThingID:    d.ThingID,
+		Messages:   d.Messages,
+		Status:     d.Status,
+		StatusCode: d.StatusCode,
+		Latency:    d.Latency.Milliseconds(),
+		Attempts:   d.Attempts,
+		Response:   d.Response,
+		Error:      d.Error,
+		Created:    d.Created,
+	}
+}
+
+func toDelivery(dbd dbDelivery) webhooks.Delivery {
+	return webhooks.Delivery{
+		ID:         dbd.ID,
+		WebhookID:  dbd.WebhookID,
+		ThingID:    dbd.ThingID,
+		Messages:   dbd.Messages,
+		Status:     dbd.Status,
+		StatusCode: dbd.StatusCode,
+		Latency:    time.Duration(dbd.Latency) * time.Millisecond,
+		Attempts:   dbd.Attempts,
+		Response:   dbd.Response,
+		Error:      dbd.Error,
+		Created:    dbd.Created,
+	}
+}
+
+type dbDeadLetter struct {
+	ID             string `db:"id"`
+	WebhookID      string `db:"webhook_id"`
+	ThingID        string `db:"thing_id"`
+	Subtopic       string `db:"subtopic"`
+	Payload        []byte `db:"payload"`
+	MessageCreated int64  `db:"message_created"`
+	Error          string `db:"error"`
+	Attempts       uint64 `db:"attempts"`
+	Created        int64  `db:"created"`
+}
+
+func toDBDeadLetter(dl webhooks.DeadLetter) dbDeadLetter {
+	return dbDeadLetter{
+		ID:             dl.ID,
+		WebhookID:      dl.WebhookID,
+		ThingID:        dl.Message.ThingId,
+		Subtopic:       dl.Message.Subtopic,
+		Payload:        dl.Message.Payload,
+		MessageCreated: dl.Message.Created,
+		Error:          dl.Error,
+		Attempts:       dl.Attempts,
+		Created:        dl.Created,
+	}
+}
+
+func toDeadLetter(dbdl dbDeadLetter) webhooks.DeadLetter {
+	return webhooks.DeadLetter{
+		ID:        dbdl.ID,
+		WebhookID: dbdl.WebhookID,
+		Message: protomfx.Webhook{
+			ThingId:  dbdl.ThingID,
+			Subtopic: dbdl.Subtopic,
+			Payload:  dbdl.Payload,
+			Created:  dbdl.MessageCreated,
+		},
+		Error:    dbdl.Error,
+		Attempts: dbdl.Attempts,
+		Created:  dbdl.Created,
+	}
+}
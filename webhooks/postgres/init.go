@@ -58,6 +58,130 @@ func migrateDB(db *sqlx.DB) error {
 				},
 				Down: []string{"DROP TABLE webhooks"},
 			},
+			{
+				Id: "webhooks_2",
+				Up: []string{
+					`ALTER TABLE webhooks ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'enabled'`,
+					`ALTER TABLE webhooks ADD COLUMN failures BIGINT NOT NULL DEFAULT 0`,
+					`CREATE TABLE IF NOT EXISTS webhook_deliveries (
+						id          UUID PRIMARY KEY,
+						webhook_id  UUID NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+						thing_id    UUID NOT NULL,
+						status      VARCHAR(16) NOT NULL,
+						status_code INTEGER NOT NULL,
+						latency     BIGINT NOT NULL,
+						attempts    BIGINT NOT NULL,
+						response    TEXT,
+						error       TEXT,
+						created     BIGINT NOT NULL
+					)`,
+					`CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_created ON webhook_deliveries (webhook_id, created)`,
+					`CREATE TABLE IF NOT EXISTS webhook_dead_letters (
+						id              UUID PRIMARY KEY,
+						webhook_id      UUID NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+						thing_id        UUID NOT NULL,
+						payload         BYTEA,
+						message_created BIGINT NOT NULL,
+						error           TEXT,
+						attempts        BIGINT NOT NULL,
+						created         BIGINT NOT NULL
+					)`,
+					`CREATE INDEX IF NOT EXISTS idx_webhook_dead_letters_webhook_created ON webhook_dead_letters (webhook_id, created)`,
+				},
+				Down: []string{
+					"DROP TABLE webhook_dead_letters",
+					"DROP TABLE webhook_deliveries",
+					"ALTER TABLE webhooks DROP COLUMN failures",
+					"ALTER TABLE webhooks DROP COLUMN status",
+				},
+			},
+			{
+				Id: "webhooks_3",
+				Up: []string{
+					`ALTER TABLE webhooks ADD COLUMN secret TEXT NOT NULL DEFAULT ''`,
+					`ALTER TABLE webhooks ADD COLUMN previous_secret TEXT NOT NULL DEFAULT ''`,
+					`ALTER TABLE webhooks ADD COLUMN previous_secret_expires_at BIGINT NOT NULL DEFAULT 0`,
+				},
+				Down: []string{
+					"ALTER TABLE webhooks DROP COLUMN previous_secret_expires_at",
+					"ALTER TABLE webhooks DROP COLUMN previous_secret",
+					"ALTER TABLE webhooks DROP COLUMN secret",
+				},
+			},
+			{
+				Id: "webhooks_4",
+				Up: []string{
+					`ALTER TABLE webhooks ADD COLUMN method VARCHAR(8) NOT NULL DEFAULT 'POST'`,
+					`ALTER TABLE webhooks ADD COLUMN template TEXT NOT NULL DEFAULT ''`,
+					`ALTER TABLE webhooks ADD COLUMN format VARCHAR(8) NOT NULL DEFAULT 'json'`,
+					`ALTER TABLE webhook_dead_letters ADD COLUMN subtopic VARCHAR(254) NOT NULL DEFAULT ''`,
+				},
+				Down: []string{
+					"ALTER TABLE webhook_dead_letters DROP COLUMN subtopic",
+					"ALTER TABLE webhooks DROP COLUMN format",
+					"ALTER TABLE webhooks DROP COLUMN template",
+					"ALTER TABLE webhooks DROP COLUMN method",
+				},
+			},
+			{
+				Id: "webhooks_5",
+				Up: []string{
+					`ALTER TABLE webhooks ADD COLUMN filter JSONB NOT NULL DEFAULT '{}'`,
+				},
+				Down: []string{
+					"ALTER TABLE webhooks DROP COLUMN filter",
+				},
+			},
+			{
+				Id: "webhooks_6",
+				Up: []string{
+					`ALTER TABLE webhooks ADD COLUMN batch BOOLEAN NOT NULL DEFAULT FALSE`,
+					`ALTER TABLE webhook_deliveries ADD COLUMN messages BIGINT NOT NULL DEFAULT 1`,
+				},
+				Down: []string{
+					"ALTER TABLE webhook_deliveries DROP COLUMN messages",
+					"ALTER TABLE webhooks DROP COLUMN batch",
+				},
+			},
+			{
+				Id: "webhooks_7",
+				Up: []string{
+					`CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_created ON webhook_deliveries (created)`,
+				},
+				Down: []string{
+					"DROP INDEX IF EXISTS idx_webhook_deliveries_created",
+				},
+			},
+			{
+				Id: "webhooks_8",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS webhook_pending_messages (
+						id              UUID PRIMARY KEY,
+						webhook_id      UUID NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+						thing_id        UUID NOT NULL,
+						subtopic        VARCHAR(254) NOT NULL DEFAULT '',
+						payload         BYTEA,
+						message_created BIGINT NOT NULL,
+						instance_id     VARCHAR(36) NOT NULL,
+						lease_until     BIGINT NOT NULL,
+						created         BIGINT NOT NULL
+					)`,
+					`CREATE INDEX IF NOT EXISTS idx_webhook_pending_messages_instance ON webhook_pending_messages (instance_id)`,
+					`CREATE INDEX IF NOT EXISTS idx_webhook_pending_messages_lease ON webhook_pending_messages (lease_until)`,
+				},
+				Down: []string{
+					"DROP TABLE webhook_pending_messages",
+				},
+			},
+			{
+				Id: "webhooks_9",
+				Up: []string{
+					`CREATE INDEX IF NOT EXISTS idx_webhook_dead_letters_created ON webhook_dead_letters (created)`,
+				},
+				Down: []string{
+					"DROP INDEX IF EXISTS idx_webhook_dead_letters_created",
+				},
+			},
 		},
 	}
 	_, err := migrate.Exec(db.DB, "postgres", migrations, migrate.Up)
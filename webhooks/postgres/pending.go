@@ -0,0 +1,161 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/MainfluxLabs/mainflux/pkg/dbutil"
+	"github.com/MainfluxLabs/mainflux/pkg/errors"
+	protomfx "github.com/MainfluxLabs/mainflux/pkg/proto"
+	"github.com/MainfluxLabs/mainflux/webhooks"
+)
+
+var _ webhooks.PendingMessageRepository = (*pendingMessageRepository)(nil)
+
+type pendingMessageRepository struct {
+	db dbutil.Database
+}
+
+// NewPendingMessageRepository instantiates a PostgreSQL implementation of pending message repository.
+func NewPendingMessageRepository(db dbutil.Database) webhooks.PendingMessageRepository {
+	return &pendingMessageRepository{
+		db: db,
+	}
+}
+
+func (pmr pendingMessageRepository) Save(ctx context.Context, instanceID string, leaseUntil time.Time, pms ...webhooks.PendingMessage) error {
+	tx, err := pmr.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(dbutil.ErrCreateEntity, err)
+	}
+
+	q := `INSERT INTO webhook_pending_messages (id, webhook_id, thing_id, subtopic, payload, message_created, instance_id, lease_until, created)
+		VALUES (:id, :webhook_id, :thing_id, :subtopic, :payload, :message_created, :instance_id, :lease_until, :created);`
+
+	for _, pm := range pms {
+		dbpm := toDBPendingMessage(pm)
+		dbpm.InstanceID = instanceID
+		dbpm.LeaseUntil = leaseUntil.UnixNano()
+
+		if _, err := tx.NamedExecContext(ctx, q, dbpm); err != nil {
+			tx.Rollback()
+			return errors.Wrap(dbutil.ErrCreateEntity, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(dbutil.ErrCreateEntity, err)
+	}
+
+	return nil
+}
+
+func (pmr pendingMessageRepository) RenewLeases(ctx context.Context, instanceID string, leaseUntil time.Time) error {
+	q := `UPDATE webhook_pending_messages SET lease_until = :lease_until WHERE instance_id = :instance_id;`
+
+	var until int64
+	if !leaseUntil.IsZero() {
+		until = leaseUntil.UnixNano()
+	}
+
+	params := map[string]any{
+		"instance_id": instanceID,
+		"lease_until": until,
+	}
+	if _, err := pmr.db.NamedExecContext(ctx, q, params); err != nil {
+		return errors.Wrap(dbutil.ErrUpdateEntity, err)
+	}
+
+	return nil
+}
+
+func (pmr pendingMessageRepository) Claim(ctx context.Context, instanceID string, leaseUntil time.Time, limit uint64) ([]webhooks.PendingMessage, error) {
+	// Messages being claimed by another instance are skipped rather than waited for.
+	q := `UPDATE webhook_pending_messages SET instance_id = :instance_id, lease_until = :lease_until
+		WHERE id IN (
+			SELECT id FROM webhook_pending_messages WHERE lease_until < :now
+			ORDER BY created LIMIT :limit FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, webhook_id, thing_id, subtopic, payload, message_created, instance_id, lease_until, created;`
+
+	params := map[string]any{
+		"instance_id": instanceID,
+		"lease_until": leaseUntil.UnixNano(),
+		"now":         time.Now().UnixNano(),
+		"limit":       limit,
+	}
+
+	rows, err := pmr.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return nil, errors.Wrap(dbutil.ErrUpdateEntity, err)
+	}
+	defer rows.Close()
+
+	var pms []webhooks.PendingMessage
+	for rows.Next() {
+		var dbpm dbPendingMessage
+		if err := rows.StructScan(&dbpm); err != nil {
+			return nil, errors.Wrap(dbutil.ErrRetrieveEntity, err)
+		}
+		pms = append(pms, toPendingMessage(dbpm))
+	}
+
+	// RETURNING doesn't preserve the order of the subquery.
+	slices.SortFunc(pms, func(a, b webhooks.PendingMessage) int {
+		return cmp.Compare(a.Created, b.Created)
+	})
+
+	return pms, nil
+}
+
+func (pmr pendingMessageRepository) Remove(ctx context.Context, ids ...string) error {
+	q := `DELETE FROM webhook_pending_messages WHERE id = :id;`
+
+	for _, id := range ids {
+		if _, err := pmr.db.NamedExecContext(ctx, q, dbPendingMessage{ID: id}); err != nil {
+			return errors.Wrap(dbutil.ErrRemoveEntity, err)
+		}
+	}
+
+	return nil
+}
+
+type dbPendingMessage struct {
+	ID             string `db:"id"`
+	WebhookID      string `db:"webhook_id"`
+	ThingID        string `db:"thing_id"`
+	Subtopic       string `db:"subtopic"`
+	Payload        []byte `db:"payload"`
+	MessageCreated int64  `db:"message_created"`
+	InstanceID     string `db:"instance_id"`
+	LeaseUntil     int64  `db:"lease_until"`
+	Created        int64  `db:"created"`
+}
+
+func toDBPendingMessage(pm webhooks.PendingMessage) dbPendingMessage {
+	return dbPendingMessage{
+		ID:             pm.ID,
+		WebhookID:      pm.WebhookID,
+		ThingID:        pm.Message.ThingId,
+		Subtopic:       pm.Message.Subtopic,
+		Payload:        pm.Message.Payload,
+		MessageCreated: pm.Message.Created,
+		Created:        pm.Created,
+	}
+}
+
+func toPendingMessage(dbpm dbPendingMessage) webhooks.PendingMessage {
+	return webhooks.PendingMessage{
+		ID:        dbpm.ID,
+		WebhookID: dbpm.WebhookID,
+		Message: protomfx.Webhook{
+			ThingId:  dbpm.ThingID,
+			Subtopic: dbpm.Subtopic,
+			Payload:  dbpm.Payload,
+			Created:  dbpm.MessageCreated,
+		},
+		Created: dbpm.Created,
+	}
+}
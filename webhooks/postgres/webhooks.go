@@ -34,7 +34,8 @@ func (wr webhookRepository) Save(ctx context.Context, whs ...webhooks.Webhook) (
 		return []webhooks.Webhook{}, errors.Wrap(dbutil.ErrCreateEntity, err)
 	}
 
-	q := `INSERT INTO webhooks (id, thing_id, group_id, name, url, headers, metadata) VALUES (:id, :thing_id, :group_id, :name, :url, :headers, :metadata);`
+	q := `INSERT INTO webhooks (id, thing_id, group_id, name, url, headers, metadata, method, template, format, filter, batch, status, secret)
+		VALUES (:id, :thing_id, :group_id, :name, :url, :headers, :metadata, :method, :template, :format, :filter, :batch, :status, :secret);`
 
 	for _, webhook := range whs {
 		dbWh, err := toDBWebhook(webhook)
@@ -83,7 +84,7 @@ func (wr webhookRepository) RetrieveByGroup(ctx context.Context, groupID string,
 	}
 	whereClause := dbutil.BuildWhereClause(gq, nq, urlq, mq)
 
-	q := fmt.Sprintf(`SELECT id, thing_id, group_id, name, url, headers, metadata FROM webhooks %s ORDER BY %s %s %s;`, whereClause, oq, dq, olq)
+	q := fmt.Sprintf(`SELECT id, thing_id, group_id, name, url, headers, metadata, method, template, format, filter, batch, status, failures, secret, previous_secret, previous_secret_expires_at FROM webhooks %s ORDER BY %s %s %s;`, whereClause, oq, dq, olq)
 	qc := fmt.Sprintf(`SELECT COUNT(*) FROM webhooks %s;`, whereClause)
 
 	params := map[string]any{
@@ -115,7 +116,7 @@ func (wr webhookRepository) RetrieveByThing(ctx context.Context, thingID string,
 	}
 	whereClause := dbutil.BuildWhereClause(tq, nq, urlq, mq)
 
-	q := fmt.Sprintf(`SELECT id, thing_id, group_id, name, url, headers, metadata FROM webhooks %s ORDER BY %s %s %s;`, whereClause, oq, dq, olq)
+	q := fmt.Sprintf(`SELECT id, thing_id, group_id, name, url, headers, metadata, method, template, format, filter, batch, status, failures, secret, previous_secret, previous_secret_expires_at FROM webhooks %s ORDER BY %s %s %s;`, whereClause, oq, dq, olq)
 	qc := fmt.Sprintf(`SELECT COUNT(*) FROM webhooks %s;`, whereClause)
 
 	params := map[string]any{
@@ -131,7 +132,7 @@ func (wr webhookRepository) RetrieveByThing(ctx context.Context, thingID string,
 }
 
 func (wr webhookRepository) RetrieveByID(ctx context.Context, id string) (webhooks.Webhook, error) {
-	q := `SELECT id, thing_id, group_id, name, url, headers, metadata FROM webhooks WHERE id = $1;`
+	q := `SELECT id, thing_id, group_id, name, url, headers, metadata, method, template, format, filter, batch, status, failures, secret, previous_secret, previous_secret_expires_at FROM webhooks WHERE id = $1;`
 
 	dbwh := dbWebhook{ID: id}
 	if err := wr.db.QueryRowxContext(ctx, q, id).StructScan(&dbwh); err != nil {
@@ -147,7 +148,8 @@ func (wr webhookRepository) RetrieveByID(ctx context.Context, id string) (webhoo
 }
 
 func (wr webhookRepository) Update(ctx context.Context, w webhooks.Webhook) error {
-	q := `UPDATE webhooks SET name = :name, url = :url, headers = :headers, metadata = :metadata WHERE id = :id;`
+	q := `UPDATE webhooks SET name = :name, url = :url, headers = :headers, metadata = :metadata,
+		method = :method, template = :template, format = :format, filter = :filter, batch = :batch WHERE id = :id;`
 
 	dbwh, err := toDBWebhook(w)
 	if err != nil {
@@ -181,6 +183,89 @@ func (wr webhookRepository) Update(ctx context.Context, w webhooks.Webhook) erro
 	return nil
 }
 
+func (wr webhookRepository) UpdateStatus(ctx context.Context, id, status string) error {
+	q := `UPDATE webhooks SET status = :status, failures = 0 WHERE id = :id;`
+
+	dbwh := dbWebhook{ID: id, Status: status}
+	res, err := wr.db.NamedExecContext(ctx, q, dbwh)
+	if err != nil {
+		pgErr, ok := err.(*pgconn.PgError)
+		if ok && pgErr.Code == pgerrcode.InvalidTextRepresentation {
+			return errors.Wrap(dbutil.ErrNotFound, err)
+		}
+		return errors.Wrap(dbutil.ErrUpdateEntity, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(dbutil.ErrUpdateEntity, err)
+	}
+
+	if cnt == 0 {
+		return dbutil.ErrNotFound
+	}
+
+	return nil
+}
+
+func (wr webhookRepository) IncrementFailures(ctx context.Context, id string, disableAfter uint64) (webhooks.Webhook, error) {
+	q := `UPDATE webhooks SET failures = failures + 1,
+		status = CASE WHEN $2::BIGINT > 0 AND failures + 1 >= $2::BIGINT THEN $3 ELSE status END
+		WHERE id = $1
+		RETURNING id, thing_id, group_id, name, url, headers, metadata, method, template, format, filter, batch, status, failures, secret, previous_secret, previous_secret_expires_at;`
+
+	var dbwh dbWebhook
+	if err := wr.db.QueryRowxContext(ctx, q, id, disableAfter, webhooks.DisabledStatus).StructScan(&dbwh); err != nil {
+		if err == sql.ErrNoRows {
+			return webhooks.Webhook{}, errors.Wrap(dbutil.ErrNotFound, err)
+		}
+		return webhooks.Webhook{}, errors.Wrap(dbutil.ErrUpdateEntity, err)
+	}
+
+	return toWebhook(dbwh)
+}
+
+func (wr webhookRepository) ResetFailures(ctx context.Context, id string) error {
+	q := `UPDATE webhooks SET failures = 0 WHERE id = :id;`
+
+	dbwh := dbWebhook{ID: id}
+	if _, err := wr.db.NamedExecContext(ctx, q, dbwh); err != nil {
+		return errors.Wrap(dbutil.ErrUpdateEntity, err)
+	}
+
+	return nil
+}
+
+func (wr webhookRepository) UpdateSecret(ctx context.Context, w webhooks.Webhook) error {
+	q := `UPDATE webhooks SET secret = :secret, previous_secret = :previous_secret, previous_secret_expires_at = :previous_secret_expires_at WHERE id = :id;`
+
+	dbwh := dbWebhook{
+		ID:                      w.ID,
+		Secret:                  w.Secret,
+		PreviousSecret:          w.PreviousSecret,
+		PreviousSecretExpiresAt: w.PreviousSecretExpiresAt,
+	}
+	res, err := wr.db.NamedExecContext(ctx, q, dbwh)
+	if err != nil {
+		pgErr, ok := err.(*pgconn.PgError)
+		if ok && pgErr.Code == pgerrcode.InvalidTextRepresentation {
+			return errors.Wrap(dbutil.ErrNotFound, err)
+		}
+		return errors.Wrap(dbutil.ErrUpdateEntity, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(dbutil.ErrUpdateEntity, err)
+	}
+
+	if cnt == 0 {
+		return dbutil.ErrNotFound
+	}
+
+	return nil
+}
+
 func (wr webhookRepository) Remove(ctx context.Context, ids ...string) error {
 	for _, id := range ids {
 		dbwh := dbWebhook{ID: id}
@@ -252,13 +337,23 @@ func (wr webhookRepository) retrieve(ctx context.Context, query, cquery string,
 }
 
 type dbWebhook struct {
-	ID       string `db:"id"`
-	ThingID  string `db:"thing_id"`
-	GroupID  string `db:"group_id"`
-	Name     string `db:"name"`
-	Url      string `db:"url"`
-	Headers  []byte `db:"headers"`
-	Metadata []byte `db:"metadata"`
+	ID                      string `db:"id"`
+	ThingID                 string `db:"thing_id"`
+	GroupID                 string `db:"group_id"`
+	Name                    string `db:"name"`
+	Url                     string `db:"url"`
+	Headers                 []byte `db:"headers"`
+	Metadata                []byte `db:"metadata"`
+	Method                  string `db:"method"`
+	Template                string `db:"template"`
+	Format                  string `db:"format"`
+	Filter                  []byte `db:"filter"`
+	Batch                   bool   `db:"batch"`
+	Status                  string `db:"status"`
+	Failures                uint64 `db:"failures"`
+	Secret                  string `db:"secret"`
+	PreviousSecret          string `db:"previous_secret"`
+	PreviousSecretExpiresAt int64  `db:"previous_secret_expires_at"`
 }
 
 func toDBWebhook(wh webhooks.Webhook) (dbWebhook, error) {
@@ -280,6 +375,15 @@ func toDBWebhook(wh webhooks.Webhook) (dbWebhook, error) {
 		metadata = b
 	}
 
+	filter := []byte("{}")
+	if !wh.Filter.IsEmpty() {
+		b, err := json.Marshal(wh.Filter)
+		if err != nil {
+			return dbWebhook{}, errors.Wrap(dbutil.ErrMalformedEntity, err)
+		}
+		filter = b
+	}
+
 	return dbWebhook{
 		ID:       wh.ID,
 		ThingID:  wh.ThingID,
@@ -288,6 +392,13 @@ func toDBWebhook(wh webhooks.Webhook) (dbWebhook, error) {
 		Url:      wh.Url,
 		Headers:  headers,
 		Metadata: metadata,
+		Method:   wh.Method,
+		Template: wh.Template,
+		Format:   wh.Format,
+		Filter:   filter,
+		Batch:    wh.Batch,
+		Status:   wh.Status,
+		Secret:   wh.Secret,
 	}, nil
 }
 
@@ -302,13 +413,28 @@ func toWebhook(dbW dbWebhook) (webhooks.Webhook, error) {
 		return webhooks.Webhook{}, errors.Wrap(dbutil.ErrMalformedEntity, err)
 	}
 
+	var filter webhooks.Filter
+	if err := json.Unmarshal(dbW.Filter, &filter); err != nil {
+		return webhooks.Webhook{}, errors.Wrap(dbutil.ErrMalformedEntity, err)
+	}
+
 	return webhooks.Webhook{
-		ID:       dbW.ID,
-		ThingID:  dbW.ThingID,
-		GroupID:  dbW.GroupID,
-		Name:     dbW.Name,
-		Url:      dbW.Url,
-		Headers:  headers,
-		Metadata: metadata,
+		ID:                      dbW.ID,
+		ThingID:                 dbW.ThingID,
+		GroupID:                 dbW.GroupID,
+		Name:                    dbW.Name,
+		Url:                     dbW.Url,
+		Headers:                 headers,
+		Metadata:                metadata,
+		Method:                  dbW.Method,
+		Template:                dbW.Template,
+		Format:                  dbW.Format,
+		Filter:                  filter,
+		Batch:                   dbW.Batch,
+		Status:                  dbW.Status,
+		Failures:                dbW.Failures,
+		Secret:                  dbW.Secret,
+		PreviousSecret:          dbW.PreviousSecret,
+		PreviousSecretExpiresAt: dbW.PreviousSecretExpiresAt,
 	}, nil
 }
@@ -0,0 +1,181 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package webhooks
+
+import (
+	"sync"
+	"time"
+
+	"github.com/MainfluxLabs/mainflux/pkg/errors"
+	protomfx "github.com/MainfluxLabs/mainflux/pkg/proto"
+)
+
+const (
+	// queueIdleTimeout is the time after which the worker of a webhook without queued deliveries stops.
+	queueIdleTimeout = time.Minute
+	// defQueueSize is the size of the queues when it isn't configured.
+	defQueueSize = 1000
+)
+
+var (
+	// ErrQueueFull indicates that a message can't be queued for delivery because the
+	// queue of the webhook is full.
+	ErrQueueFull = errors.New("webhook delivery queue is full")
+
+	// ErrStopped indicates that a message can't be queued for delivery because the
+	// delivery is stopped.
+	ErrStopped = errors.New("webhook delivery stopped")
+
+	errWebhookDisabled = errors.New("webhook disabled")
+)
+
+// deliveryJob is a delivery of messages to a webhook waiting in the queue of the webhook.
+type deliveryJob struct {
+	webhook Webhook
+	msgs    []protomfx.Webhook
+	// pending holds the IDs of the pending messages, which are removed once the job is
+	// delivered or dead-lettered.
+	pending []string
+	// batch reports whether the messages are forwarded as a batch, even if there is only one.
+	batch bool
+}
+
+// deliveryQueue delivers the messages of each webhook one delivery at a time, from a queue
+// of bounded size per webhook, so that a slow or failing endpoint holds back neither the
+// other webhooks nor an unbounded number of goroutines.
+type deliveryQueue struct {
+	mu      sync.Mutex
+	size    int
+	queues  map[string]chan deliveryJob
+	stopped bool
+	done    chan struct{}
+	wg      sync.WaitGroup
+	deliver func(job deliveryJob)
+}
+
+func newDeliveryQueue(size int, deliver func(job deliveryJob)) *deliveryQueue {
+	if size <= 0 {
+		size = defQueueSize
+	}
+
+	return &deliveryQueue{
+		size:    size,
+		queues:  make(map[string]chan deliveryJob),
+		done:    make(chan struct{}),
+		deliver: deliver,
+	}
+}
+
+// enqueue adds the job to the queue of its webhook, starting the worker of the webhook if
+// it isn't running. It fails if the queue is full or the delivery is stopped.
+func (q *deliveryQueue) enqueue(job deliveryJob) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.stopped {
+		return ErrStopped
+	}
+
+	id := job.webhook.ID
+	jobs, ok := q.queues[id]
+	if !ok {
+		jobs = make(chan deliveryJob, q.size)
+		q.queues[id] = jobs
+		q.wg.Add(1)
+		go q.work(id, jobs)
+	}
+
+	select {
+	case jobs <- job:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// remove removes the jobs queued for the webhook, and returns them. The job being delivered,
+// if any, isn't interrupted.
+func (q *deliveryQueue) remove(id string) []deliveryJob {
+	q.mu.Lock()
+	jobs, ok := q.queues[id]
+	q.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	var removed []deliveryJob
+	for {
+		select {
+		case job := <-jobs:
+			removed = append(removed, job)
+		default:
+			return removed
+		}
+	}
+}
+
+// work delivers the queued jobs of the webhook until the delivery is stopped, or the
+// queue stays empty for queueIdleTimeout.
+func (q *deliveryQueue) work(id string, jobs chan deliveryJob) {
+	defer q.wg.Done()
+
+	idle := time.NewTimer(queueIdleTimeout)
+	defer idle.Stop()
+
+	for {
+		// Stopping takes precedence over the queued jobs, whose messages stay pending.
+		select {
+		case <-q.done:
+			return
+		default:
+		}
+
+		select {
+		case job := <-jobs:
+			q.deliver(job)
+			idle.Reset(queueIdleTimeout)
+		case <-idle.C:
+			// Jobs are only added while holding the lock, so the queue can't receive any
+			// once it's found empty and removed.
+			q.mu.Lock()
+			if len(jobs) == 0 {
+				delete(q.queues, id)
+				q.mu.Unlock()
+				return
+			}
+			q.mu.Unlock()
+			idle.Reset(queueIdleTimeout)
+		case <-q.done:
+			return
+		}
+	}
+}
+
+// wait waits for the duration, and reports whether the delivery wasn't stopped meanwhile.
+func (q *deliveryQueue) wait(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-q.done:
+		return false
+	}
+}
+
+// stop stops accepting jobs, interrupts the retries of the ongoing deliveries, discards the
+// queued jobs, and waits for the workers to finish.
+func (q *deliveryQueue) stop() {
+	q.mu.Lock()
+	if q.stopped {
+		q.mu.Unlock()
+		return
+	}
+	q.stopped = true
+	close(q.done)
+	q.mu.Unlock()
+
+	q.wg.Wait()
+}
@@ -0,0 +1,79 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// pruneInterval is the interval at which the logged deliveries and the dead letters exceeding the retention limits are removed.
+const pruneInterval = time.Hour
+
+func (ws *webhooksService) ProcessDeliveries(ctx context.Context) error {
+	ws.pruneDeliveries(ctx)
+	ws.pruneDeadLetters(ctx)
+	ws.renewPending(ctx)
+
+	pruneTicker := time.NewTicker(pruneInterval)
+	defer pruneTicker.Stop()
+	leaseTicker := time.NewTicker(leaseInterval)
+	defer leaseTicker.Stop()
+
+	for {
+		select {
+		case <-pruneTicker.C:
+			ws.pruneDeliveries(ctx)
+			ws.pruneDeadLetters(ctx)
+		case <-leaseTicker.C:
+			ws.renewPending(ctx)
+		case <-ctx.Done():
+			ws.stopDeliveries()
+			return nil
+		}
+	}
+}
+
+// pruneDeliveries removes the deliveries older than the configured max age, and all but
+// the configured number of the latest deliveries of each webhook.
+func (ws *webhooksService) pruneDeliveries(ctx context.Context) {
+	if ws.config.DeliveriesMaxAge == 0 && ws.config.DeliveriesMaxCount == 0 {
+		return
+	}
+
+	var before time.Time
+	if ws.config.DeliveriesMaxAge > 0 {
+		before = time.Now().Add(-ws.config.DeliveriesMaxAge)
+	}
+
+	if err := ws.deliveries.Prune(ctx, before, ws.config.DeliveriesMaxCount); err != nil {
+		ws.logger.Error(fmt.Sprintf("failed to remove deliveries exceeding the retention limits: %s", err))
+	}
+}
+
+// pruneDeadLetters removes the dead letters older than the configured max age, and all but
+// the configured number of the latest dead letters of each webhook.
+func (ws *webhooksService) pruneDeadLetters(ctx context.Context) {
+	if ws.config.DeadLettersMaxAge == 0 && ws.config.DeadLettersMaxCount == 0 {
+		return
+	}
+
+	var before time.Time
+	if ws.config.DeadLettersMaxAge > 0 {
+		before = time.Now().Add(-ws.config.DeadLettersMaxAge)
+	}
+
+	if err := ws.deadLetters.Prune(ctx, before, ws.config.DeadLettersMaxCount); err != nil {
+		ws.logger.Error(fmt.Sprintf("failed to remove dead letters exceeding the retention limits: %s", err))
+	}
+}
+
+// stopDeliveries discards the buffered batches and the queued deliveries, waits for the
+// ongoing deliveries to finish, and releases the pending messages left undelivered.
+func (ws *webhooksService) stopDeliveries() {
+	ws.batcher.drain()
+	ws.queue.stop()
+	ws.releasePending(context.Background())
+}
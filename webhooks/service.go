@@ -7,8 +7,10 @@ import (
 	"context"
 
 	"github.com/MainfluxLabs/mainflux/consumers"
+	"github.com/MainfluxLabs/mainflux/logger"
 	"github.com/MainfluxLabs/mainflux/pkg/domain"
 	"github.com/MainfluxLabs/mainflux/pkg/errors"
+	"github.com/MainfluxLabs/mainflux/pkg/predicate"
 	protomfx "github.com/MainfluxLabs/mainflux/pkg/proto"
 	"github.com/MainfluxLabs/mainflux/pkg/uuid"
 )
@@ -47,6 +49,32 @@ type Service interface {
 	// UpdateWebhook updates the webhook identified by the provided ID.
 	UpdateWebhook(ctx context.Context, token string, webhook Webhook) error
 
+	// EnableWebhook enables the webhook identified by the provided ID, and resets
+	// its consecutive delivery failures.
+	EnableWebhook(ctx context.Context, token, id string) error
+
+	// DisableWebhook disables the webhook identified by the provided ID.
+	// Messages aren't forwarded to disabled webhooks.
+	DisableWebhook(ctx context.Context, token, id string) error
+
+	// RotateWebhookSecret replaces the signing secret of the webhook identified by
+	// the provided ID with a new one. Messages are signed with the previous secret
+	// as well until the grace period ends. The updated webhook is returned.
+	RotateWebhookSecret(ctx context.Context, token, id string) (Webhook, error)
+
+	// ListDeliveries retrieves a subset of the logged deliveries to the webhook
+	// identified by the provided ID.
+	ListDeliveries(ctx context.Context, token, webhookID string, pm PageMetadata) (DeliveriesPage, error)
+
+	// ListDeadLetters retrieves a subset of the dead letters of the webhook
+	// identified by the provided ID.
+	ListDeadLetters(ctx context.Context, token, webhookID string, pm PageMetadata) (DeadLettersPage, error)
+
+	// ReplayDeadLetters makes a single delivery attempt of the dead letters identified
+	// with the provided IDs, or of up to MaxReplay of the oldest dead letters of the
+	// webhook if none are provided. Delivered dead letters are removed.
+	ReplayDeadLetters(ctx context.Context, token, webhookID string, ids ...string) (ReplayResult, error)
+
 	// RemoveWebhooks removes the webhooks identified with the provided IDs.
 	RemoveWebhooks(ctx context.Context, token string, id ...string) error
 
@@ -56,26 +84,50 @@ type Service interface {
 	// RemoveWebhooksByGroup removes webhooks related to the given group ID.
 	RemoveWebhooksByGroup(ctx context.Context, groupID string) error
 
+	// ProcessDeliveries periodically removes the logged deliveries and the dead letters
+	// exceeding the retention limits, renews the leases of the pending messages of this instance, and delivers the
+	// pending messages left by other instances, until the context is done. It then stops
+	// delivering messages, and releases the pending ones to be delivered by other instances.
+	ProcessDeliveries(ctx context.Context) error
+
 	consumers.WebhookConsumer
 }
 
 type webhooksService struct {
-	things     domain.ThingsClient
-	webhooks   WebhookRepository
-	forwarder  Forwarder
-	idProvider uuid.IDProvider
+	things      domain.ThingsClient
+	webhooks    WebhookRepository
+	deliveries  DeliveryRepository
+	deadLetters DeadLetterRepository
+	pending     PendingMessageRepository
+	forwarder   Forwarder
+	idProvider  uuid.IDProvider
+	config      DeliveryConfig
+	batcher     *batcher
+	queue       *deliveryQueue
+	patterns    *predicate.Patterns
+	logger      logger.Logger
 }
 
 var _ Service = (*webhooksService)(nil)
 
 // New instantiates the webhooks service implementation.
-func New(things domain.ThingsClient, webhooks WebhookRepository, forwarder Forwarder, idp uuid.IDProvider) Service {
-	return &webhooksService{
-		things:     things,
-		webhooks:   webhooks,
-		forwarder:  forwarder,
-		idProvider: idp,
+func New(things domain.ThingsClient, webhooks WebhookRepository, deliveries DeliveryRepository, deadLetters DeadLetterRepository, pending PendingMessageRepository, forwarder Forwarder, idp uuid.IDProvider, config DeliveryConfig, logger logger.Logger) Service {
+	ws := &webhooksService{
+		things:      things,
+		webhooks:    webhooks,
+		deliveries:  deliveries,
+		deadLetters: deadLetters,
+		pending:     pending,
+		forwarder:   forwarder,
+		idProvider:  idp,
+		config:      config,
+		patterns:    predicate.NewPatterns(patternCacheSize),
+		logger:      logger,
 	}
+	ws.batcher = newBatcher(config.Batch, ws.deliverBatch)
+	ws.queue = newDeliveryQueue(config.QueueSize, ws.deliver)
+
+	return ws
 }
 
 func (ws *webhooksService) CreateWebhooks(ctx context.Context, token, thingID string, webhooks ...Webhook) ([]Webhook, error) {
@@ -91,8 +143,15 @@ func (ws *webhooksService) CreateWebhooks(ctx context.Context, token, thingID st
 
 	whs := []Webhook{}
 	for _, wh := range webhooks {
+		wh = withDefaults(wh)
 		wh.GroupID = grID
 		wh.ThingID = thingID
+		wh.Status = EnabledStatus
+		if wh.Secret == "" {
+			if wh.Secret, err = generateSecret(); err != nil {
+				return []Webhook{}, err
+			}
+		}
 
 		id, err := ws.idProvider.ID()
 		if err != nil {
@@ -162,7 +221,28 @@ func (ws *webhooksService) UpdateWebhook(ctx context.Context, token string, webh
 		return err
 	}
 
-	return ws.webhooks.Update(ctx, webhook)
+	return ws.webhooks.Update(ctx, withDefaults(webhook))
+}
+
+func (ws *webhooksService) EnableWebhook(ctx context.Context, token, id string) error {
+	if _, err := ws.authorizeWebhook(ctx, token, id, domain.GroupEditor); err != nil {
+		return err
+	}
+
+	return ws.webhooks.UpdateStatus(ctx, id, EnabledStatus)
+}
+
+func (ws *webhooksService) DisableWebhook(ctx context.Context, token, id string) error {
+	if _, err := ws.authorizeWebhook(ctx, token, id, domain.GroupEditor); err != nil {
+		return err
+	}
+
+	if err := ws.webhooks.UpdateStatus(ctx, id, DisabledStatus); err != nil {
+		return err
+	}
+	ws.dropDeliveries(ctx, id, true)
+
+	return nil
 }
 
 func (ws *webhooksService) RemoveWebhooks(ctx context.Context, token string, ids ...string) error {
@@ -180,17 +260,51 @@ func (ws *webhooksService) RemoveWebhooks(ctx context.Context, token string, ids
 		return err
 	}
 
+	for _, id := range ids {
+		ws.dropDeliveries(ctx, id, false)
+	}
+
 	return nil
 }
 
 func (ws *webhooksService) RemoveWebhooksByThing(ctx context.Context, thingID string) error {
-	return ws.webhooks.RemoveByThing(ctx, thingID)
+	whs, err := ws.webhooks.RetrieveByThing(ctx, thingID, PageMetadata{})
+	if err != nil {
+		return err
+	}
+
+	if err := ws.webhooks.RemoveByThing(ctx, thingID); err != nil {
+		return err
+	}
+
+	for _, wh := range whs.Webhooks {
+		ws.dropDeliveries(ctx, wh.ID, false)
+	}
+
+	return nil
 }
 
 func (ws *webhooksService) RemoveWebhooksByGroup(ctx context.Context, groupID string) error {
-	return ws.webhooks.RemoveByGroup(ctx, groupID)
+	whs, err := ws.webhooks.RetrieveByGroup(ctx, groupID, PageMetadata{})
+	if err != nil {
+		return err
+	}
+
+	if err := ws.webhooks.RemoveByGroup(ctx, groupID); err != nil {
+		return err
+	}
+
+	for _, wh := range whs.Webhooks {
+		ws.dropDeliveries(ctx, wh.ID, false)
+	}
+
+	return nil
 }
 
+// ConsumeWebhook persists the message as pending for each webhook it's forwarded to
+// before queueing its deliveries, so that it's delivered even if this instance stops
+// or crashes once the message is acknowledged to the broker. An error is returned if
+// a delivery can't be queued, for the broker to redeliver the message.
 func (ws *webhooksService) ConsumeWebhook(_ string, webhook protomfx.Webhook) error {
 	ctx := context.Background()
 
@@ -199,11 +313,46 @@ func (ws *webhooksService) ConsumeWebhook(_ string, webhook protomfx.Webhook) er
 		return err
 	}
 
+	var targets []Webhook
 	for _, wh := range whs.Webhooks {
-		if err := ws.forwarder.Forward(ctx, webhook, wh); err != nil {
-			return err
+		// A message addressed to a single webhook, e.g. by a Lua script, is forwarded to it regardless of its filter.
+		switch {
+		case wh.Status == DisabledStatus:
+			continue
+		case webhook.WebhookId != "":
+			if wh.ID != webhook.WebhookId {
+				continue
+			}
+		case !wh.Filter.Matches(webhook, ws.patterns):
+			continue
 		}
+		targets = append(targets, wh)
+	}
+	if len(targets) == 0 {
+		return nil
 	}
 
-	return nil
+	pms, err := ws.savePending(ctx, webhook, targets)
+	if err != nil {
+		return err
+	}
+
+	// Each webhook is delivered to from its own queue, so that a slow or failing
+	// endpoint doesn't hold back the others.
+	var qerr error
+	for i, wh := range targets {
+		if wh.Batch {
+			ws.batcher.add(wh, webhook, pms[i].ID)
+			continue
+		}
+
+		job := deliveryJob{webhook: wh, msgs: []protomfx.Webhook{webhook}, pending: []string{pms[i].ID}}
+		if err := ws.queue.enqueue(job); err != nil {
+			// The message is redelivered by the broker, so it doesn't stay pending.
+			ws.removePending(ctx, job)
+			qerr = err
+		}
+	}
+
+	return qerr
 }
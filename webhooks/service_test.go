@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/MainfluxLabs/mainflux/logger"
 	"github.com/MainfluxLabs/mainflux/pkg/dbutil"
 	"github.com/MainfluxLabs/mainflux/pkg/errors"
 	"github.com/MainfluxLabs/mainflux/pkg/mocks"
@@ -34,18 +36,44 @@ const (
 )
 
 var (
-	headers  = map[string]string{"Content-Type:": "application/json"}
-	metadata = map[string]any{"test": "data"}
-	webhook  = webhooks.Webhook{ThingID: thingID, GroupID: groupID, Name: webhookName, Url: "https://test.webhook.com", Headers: headers, Metadata: metadata}
+	deliveryConfig = webhooks.DeliveryConfig{MaxAttempts: 3, RetryInterval: time.Millisecond, MaxRetryInterval: time.Millisecond, DisableAfter: 2, SecretGracePeriod: time.Hour, Batch: webhooks.BatchConfig{MaxMessages: 3, Interval: 20 * time.Millisecond}}
+	headers        = map[string]string{"Content-Type:": "application/json"}
+	metadata       = map[string]any{"test": "data"}
+	webhook        = webhooks.Webhook{ThingID: thingID, GroupID: groupID, Name: webhookName, Url: "https://test.webhook.com", Headers: headers, Metadata: metadata}
 )
 
 func newService() webhooks.Service {
+	return newServiceWithConfig(deliveryConfig)
+}
+
+func newServiceWithConfig(config webhooks.DeliveryConfig) webhooks.Service {
+	return newServiceWithRepositories(config, newRepositories())
+}
+
+// repositories are shared by the services representing instances of the same deployment.
+type repositories struct {
+	webhooks    webhooks.WebhookRepository
+	deliveries  webhooks.DeliveryRepository
+	deadLetters webhooks.DeadLetterRepository
+	pending     webhooks.PendingMessageRepository
+	idProvider  uuid.IDProvider
+}
+
+func newRepositories() repositories {
+	return repositories{
+		webhooks:    whmock.NewWebhookRepository(),
+		deliveries:  whmock.NewDeliveryRepository(),
+		deadLetters: whmock.NewDeadLetterRepository(),
+		pending:     whmock.NewPendingMessageRepository(),
+		idProvider:  uuid.NewMock(),
+	}
+}
+
+func newServiceWithRepositories(config webhooks.DeliveryConfig, repos repositories) webhooks.Service {
 	ths := mocks.NewThingsServiceClient(nil, map[string]things.Thing{thingID: {ID: thingID, GroupID: groupID}, token: {ID: thingID, GroupID: groupID}}, map[string]things.Group{token: {ID: groupID}})
-	webhookRepo := whmock.NewWebhookRepository()
 	forwarder := whmock.NewForwarder()
-	idProvider := uuid.NewMock()
 
-	return webhooks.New(ths, webhookRepo, forwarder, idProvider)
+	return webhooks.New(ths, repos.webhooks, repos.deliveries, repos.deadLetters, repos.pending, forwarder, repos.idProvider, config, logger.NewMock())
 }
 
 func TestCreateWebhooks(t *testing.T) {
@@ -505,4 +533,708 @@ func TestConsume(t *testing.T) {
 		err := svc.ConsumeWebhook(subject, tc.wh)
 		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 	}
+
+	assert.Eventually(t, func() bool {
+		dp, err := svc.ListDeliveries(context.Background(), token, whs[0].ID, webhooks.PageMetadata{})
+		return err == nil && dp.Total == uint64(len(cases))
+	}, time.Second, time.Millisecond, "expected a logged delivery for each forwarded message")
+}
+
+func TestConsumeFilter(t *testing.T) {
+	svc := newService()
+	filteredWh := webhook
+	filteredWh.Filter = webhooks.Filter{
+		Subtopics: []string{"sensors/+/temp", "alerts.#"},
+		Conditions: []webhooks.Condition{
+			{Field: "sensor.temp", Comparator: webhooks.ComparatorGT, Value: float64(30)},
+			{Field: "status", Comparator: webhooks.ComparatorIn, Value: []any{"active", "alarm"}},
+			{Field: "device", Comparator: webhooks.ComparatorMatches, Value: "^dev-[0-9]+$"},
+			{Field: "mode", Comparator: webhooks.ComparatorNotIn, Value: []any{"test", "debug"}},
+		},
+	}
+	whs, err := svc.CreateWebhooks(context.Background(), token, thingID, filteredWh)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	payload := `{"sensor":{"temp":35},"status":"active","device":"dev-1","mode":"live"}`
+
+	cases := []struct {
+		desc      string
+		subtopic  string
+		payload   string
+		forwarded bool
+	}{
+		{
+			desc:      "forward message matching single-level wildcard",
+			subtopic:  "sensors.room1.temp",
+			payload:   payload,
+			forwarded: true,
+		},
+		{
+			desc:      "forward message matching multi-level wildcard",
+			subtopic:  "alerts.fire.floor2",
+			payload:   payload,
+			forwarded: true,
+		},
+		{
+			desc:      "forward message matching multi-level wildcard parent",
+			subtopic:  "alerts",
+			payload:   payload,
+			forwarded: true,
+		},
+		{
+			desc:      "forward multiple messages one of which matches",
+			subtopic:  "sensors.room2.temp",
+			payload:   `[{"sensor":{"temp":20},"status":"active","device":"dev-1","mode":"live"},` + payload + `]`,
+			forwarded: true,
+		},
+		{
+			desc:      "skip message with unmatched subtopic",
+			subtopic:  "sensors.room1.humidity",
+			payload:   payload,
+			forwarded: false,
+		},
+		{
+			desc:      "skip message with extra subtopic level",
+			subtopic:  "sensors.room1.temp.raw",
+			payload:   payload,
+			forwarded: false,
+		},
+		{
+			desc:      "skip message without subtopic",
+			subtopic:  "",
+			payload:   payload,
+			forwarded: false,
+		},
+		{
+			desc:      "skip message not meeting numeric condition",
+			subtopic:  "sensors.room1.temp",
+			payload:   `{"sensor":{"temp":25},"status":"active","device":"dev-1","mode":"live"}`,
+			forwarded: false,
+		},
+		{
+			desc:      "skip message not meeting list condition",
+			subtopic:  "sensors.room1.temp",
+			payload:   `{"sensor":{"temp":35},"status":"idle","device":"dev-1","mode":"live"}`,
+			forwarded: false,
+		},
+		{
+			desc:      "skip message not meeting pattern condition",
+			subtopic:  "sensors.room1.temp",
+			payload:   `{"sensor":{"temp":35},"status":"active","device":"gw-1","mode":"live"}`,
+			forwarded: false,
+		},
+		{
+			desc:      "skip message not meeting exclusion list condition",
+			subtopic:  "sensors.room1.temp",
+			payload:   `{"sensor":{"temp":35},"status":"active","device":"dev-1","mode":"test"}`,
+			forwarded: false,
+		},
+		{
+			desc:      "skip message missing condition field",
+			subtopic:  "sensors.room1.temp",
+			payload:   `{"status":"active"}`,
+			forwarded: false,
+		},
+		{
+			desc:      "skip message with non-JSON payload",
+			subtopic:  "sensors.room1.temp",
+			payload:   "35",
+			forwarded: false,
+		},
+	}
+
+	var forwarded uint64
+	for _, tc := range cases {
+		msg := protomfx.Webhook{ThingId: thingID, Subtopic: tc.subtopic, Payload: []byte(tc.payload)}
+		err := svc.ConsumeWebhook(subject, msg)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		if tc.forwarded {
+			forwarded++
+		}
+	}
+
+	// Filtered messages are skipped before delivery, so only forwarded messages are logged.
+	assert.Eventually(t, func() bool {
+		dp, err := svc.ListDeliveries(context.Background(), token, whs[0].ID, webhooks.PageMetadata{})
+		return err == nil && dp.Total == forwarded
+	}, time.Second, time.Millisecond, fmt.Sprintf("expected %d logged deliveries", forwarded))
+}
+
+func TestConsumeAddressed(t *testing.T) {
+	svc := newService()
+	filteredWh := webhook
+	filteredWh.Filter = webhooks.Filter{Subtopics: []string{"alerts"}}
+	otherWh := webhook
+	otherWh.Name = "other-webhook"
+	whs, err := svc.CreateWebhooks(context.Background(), token, thingID, filteredWh, otherWh)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	// The message doesn't match the filter of the addressed webhook, and isn't forwarded to the other one.
+	msg := protomfx.Webhook{ThingId: thingID, Subtopic: "sensors", Payload: []byte(`{"temp":35}`), WebhookId: whs[0].ID}
+	err = svc.ConsumeWebhook(subject, msg)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	assert.Eventually(t, func() bool {
+		dp, err := svc.ListDeliveries(context.Background(), token, whs[0].ID, webhooks.PageMetadata{})
+		return err == nil && dp.Total == 1
+	}, time.Second, time.Millisecond, "expected a logged delivery to the addressed webhook")
+
+	dp, err := svc.ListDeliveries(context.Background(), token, whs[1].ID, webhooks.PageMetadata{})
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, uint64(0), dp.Total, "expected no deliveries to the other webhook")
+}
+
+func TestConsumeBatch(t *testing.T) {
+	svc := newService()
+	batchWh := webhook
+	batchWh.Name = "batch-webhook"
+	batchWh.Batch = true
+	failingWh := batchWh
+	failingWh.Name = "failing-batch-webhook"
+	failingWh.Url = whmock.FailingURL
+	whs, err := svc.CreateWebhooks(context.Background(), token, thingID, batchWh, failingWh)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	// Two batches are flushed for reaching the maximum number of messages, and
+	// the last one once the batch interval elapses.
+	msgs := 7
+	for i := 0; i < msgs; i++ {
+		msg := protomfx.Webhook{ThingId: thingID, Payload: []byte(fmt.Sprintf(`{"seq":%d}`, i))}
+		err := svc.ConsumeWebhook(subject, msg)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc        string
+		webhookID   string
+		status      string
+		batches     []uint64
+		deadLetters uint64
+	}{
+		{
+			desc:        "deliver batches",
+			webhookID:   whs[0].ID,
+			status:      webhooks.DeliveryStatusDelivered,
+			batches:     []uint64{3, 3, 1},
+			deadLetters: 0,
+		},
+		{
+			// The webhook is disabled after the first two batches fail, so the last one isn't attempted.
+			desc:        "dead-letter each message of failed batches",
+			webhookID:   whs[1].ID,
+			status:      webhooks.DeliveryStatusFailed,
+			batches:     []uint64{3, 3},
+			deadLetters: uint64(msgs),
+		},
+	}
+
+	for _, tc := range cases {
+		var dp webhooks.DeliveriesPage
+		assert.Eventually(t, func() bool {
+			dp, err = svc.ListDeliveries(context.Background(), token, tc.webhookID, webhooks.PageMetadata{Dir: ascKey})
+			return err == nil && dp.Total == uint64(len(tc.batches))
+		}, time.Second, time.Millisecond, fmt.Sprintf("%s: expected %d logged deliveries", tc.desc, len(tc.batches)))
+
+		var batched []uint64
+		for _, d := range dp.Deliveries {
+			batched = append(batched, d.Messages)
+			assert.Equal(t, tc.status, d.Status, fmt.Sprintf("%s: expected delivery status %s got %s", tc.desc, tc.status, d.Status))
+		}
+		assert.ElementsMatch(t, tc.batches, batched, fmt.Sprintf("%s: expected batches of %v messages got %v", tc.desc, tc.batches, batched))
+
+		assert.Eventually(t, func() bool {
+			dlp, err := svc.ListDeadLetters(context.Background(), token, tc.webhookID, webhooks.PageMetadata{})
+			return err == nil && dlp.Total == tc.deadLetters
+		}, time.Second, time.Millisecond, fmt.Sprintf("%s: expected %d dead letters", tc.desc, tc.deadLetters))
+	}
+}
+
+func TestConsumeFailure(t *testing.T) {
+	svc := newService()
+	failingWh := webhook
+	failingWh.Url = whmock.FailingURL
+	whs, err := svc.CreateWebhooks(context.Background(), token, thingID, failingWh)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	wh := whs[0]
+
+	msg := protomfx.Webhook{ThingId: thingID, Payload: []byte(`{"key":"val"}`)}
+
+	// Each failed delivery is dead-lettered, and the webhook is disabled after DisableAfter of them.
+	for i := uint64(1); i <= deliveryConfig.DisableAfter; i++ {
+		err := svc.ConsumeWebhook(subject, msg)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+		assert.Eventually(t, func() bool {
+			dlp, err := svc.ListDeadLetters(context.Background(), token, wh.ID, webhooks.PageMetadata{})
+			return err == nil && dlp.Total == i
+		}, time.Second, time.Millisecond, fmt.Sprintf("expected %d dead letters", i))
+	}
+
+	dp, err := svc.ListDeliveries(context.Background(), token, wh.ID, webhooks.PageMetadata{})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	for _, d := range dp.Deliveries {
+		assert.Equal(t, webhooks.DeliveryStatusFailed, d.Status, fmt.Sprintf("expected delivery status %s got %s", webhooks.DeliveryStatusFailed, d.Status))
+		assert.Equal(t, deliveryConfig.MaxAttempts, d.Attempts, fmt.Sprintf("expected %d attempts got %d", deliveryConfig.MaxAttempts, d.Attempts))
+		assert.NotEmpty(t, d.Error, "expected delivery error")
+	}
+
+	assert.Eventually(t, func() bool {
+		res, err := svc.ViewWebhook(context.Background(), token, wh.ID)
+		return err == nil && res.Status == webhooks.DisabledStatus
+	}, time.Second, time.Millisecond, "expected webhook to be disabled")
+
+	// Messages aren't forwarded to disabled webhooks.
+	err = svc.ConsumeWebhook(subject, msg)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	time.Sleep(10 * time.Millisecond)
+	dlp, err := svc.ListDeadLetters(context.Background(), token, wh.ID, webhooks.PageMetadata{})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, deliveryConfig.DisableAfter, dlp.Total, fmt.Sprintf("expected %d dead letters got %d", deliveryConfig.DisableAfter, dlp.Total))
+}
+
+func TestConsumeQueueFull(t *testing.T) {
+	// The first message waits for a retry, the second one fills the queue, and the third one doesn't fit.
+	config := deliveryConfig
+	config.QueueSize = 1
+	config.RetryInterval = time.Hour
+	config.MaxRetryInterval = time.Hour
+	config.InstanceID = "instance-1"
+	repos := newRepositories()
+	svc := newServiceWithRepositories(config, repos)
+
+	failingWh := webhook
+	failingWh.Url = whmock.FailingURL
+	whs, err := svc.CreateWebhooks(context.Background(), token, thingID, failingWh)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	wh := whs[0]
+
+	for i := 0; i < 3; i++ {
+		msg := protomfx.Webhook{ThingId: thingID, Payload: []byte(fmt.Sprintf(`{"seq":%d}`, i))}
+		err := svc.ConsumeWebhook(subject, msg)
+		var expected error
+		if i == 2 {
+			expected = webhooks.ErrQueueFull
+		}
+		require.True(t, errors.Contains(err, expected), fmt.Sprintf("message %d: expected %s got %s", i, expected, err))
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Stopping the delivery releases the message waiting for a retry and the queued one,
+	// without dead-lettering them.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = svc.ProcessDeliveries(ctx)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	dlp, err := svc.ListDeadLetters(context.Background(), token, wh.ID, webhooks.PageMetadata{})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, uint64(0), dlp.Total, fmt.Sprintf("expected no dead letters got %d", dlp.Total))
+
+	// Messages consumed once the delivery is stopped are left to the broker to redeliver.
+	err = svc.ConsumeWebhook(subject, protomfx.Webhook{ThingId: thingID, Payload: []byte(`{"seq":3}`)})
+	assert.True(t, errors.Contains(err, webhooks.ErrStopped), fmt.Sprintf("expected %s got %s", webhooks.ErrStopped, err))
+
+	res, err := svc.ViewWebhook(context.Background(), token, wh.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, uint64(0), res.Failures, fmt.Sprintf("expected undelivered messages not to count as failures got %d", res.Failures))
+
+	// Another instance delivers the released messages to the current URL of the webhook.
+	wh.Url = webhook.Url
+	err = svc.UpdateWebhook(context.Background(), token, wh)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	config = deliveryConfig
+	config.InstanceID = "instance-2"
+	other := newServiceWithRepositories(config, repos)
+	ctx, cancel = context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- other.ProcessDeliveries(ctx)
+	}()
+
+	assert.Eventually(t, func() bool {
+		dp, err := other.ListDeliveries(context.Background(), token, wh.ID, webhooks.PageMetadata{})
+		if err != nil {
+			return false
+		}
+		var delivered int
+		for _, d := range dp.Deliveries {
+			if d.Status == webhooks.DeliveryStatusDelivered {
+				delivered++
+			}
+		}
+		return delivered == 2
+	}, time.Second, time.Millisecond, "expected the released messages to be delivered")
+	cancel()
+	require.Nil(t, <-done, "unexpected error")
+}
+
+func TestDisableWebhookQueued(t *testing.T) {
+	// The first message waits for a retry, and the others are queued.
+	config := deliveryConfig
+	config.RetryInterval = time.Hour
+	config.MaxRetryInterval = time.Hour
+	svc := newServiceWithConfig(config)
+
+	failingWh := webhook
+	failingWh.Url = whmock.FailingURL
+	whs, err := svc.CreateWebhooks(context.Background(), token, thingID, failingWh)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	wh := whs[0]
+
+	for i := 0; i < 3; i++ {
+		msg := protomfx.Webhook{ThingId: thingID, Payload: []byte(fmt.Sprintf(`{"seq":%d}`, i))}
+		err := svc.ConsumeWebhook(subject, msg)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	// Disabling the webhook dead-letters the queued messages without attempting their delivery.
+	err = svc.DisableWebhook(context.Background(), token, wh.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	dlp, err := svc.ListDeadLetters(context.Background(), token, wh.ID, webhooks.PageMetadata{Dir: ascKey})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	require.Equal(t, uint64(2), dlp.Total, fmt.Sprintf("expected 2 dead letters got %d", dlp.Total))
+	for _, dl := range dlp.DeadLetters {
+		assert.Equal(t, uint64(0), dl.Attempts, fmt.Sprintf("expected no attempts got %d", dl.Attempts))
+	}
+}
+
+func TestPruneDeliveries(t *testing.T) {
+	cases := []struct {
+		desc       string
+		maxAge     time.Duration
+		maxCount   uint64
+		deliveries uint64
+	}{
+		{
+			desc:       "keep deliveries without retention limits",
+			deliveries: 3,
+		},
+		{
+			desc:       "keep latest deliveries",
+			maxCount:   2,
+			deliveries: 2,
+		},
+		{
+			desc:       "remove expired deliveries",
+			maxAge:     time.Nanosecond,
+			deliveries: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		config := deliveryConfig
+		config.DeliveriesMaxAge = tc.maxAge
+		config.DeliveriesMaxCount = tc.maxCount
+		svc := newServiceWithConfig(config)
+
+		whs, err := svc.CreateWebhooks(context.Background(), token, thingID, webhook)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+
+		for i := 0; i < 3; i++ {
+			err := svc.ConsumeWebhook(subject, protomfx.Webhook{ThingId: thingID, Payload: []byte(`{"key":"val"}`)})
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		}
+		require.Eventually(t, func() bool {
+			dp, err := svc.ListDeliveries(context.Background(), token, whs[0].ID, webhooks.PageMetadata{})
+			return err == nil && dp.Total == 3
+		}, time.Second, time.Millisecond, fmt.Sprintf("%s: expected 3 logged deliveries", tc.desc))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err = svc.ProcessDeliveries(ctx)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+
+		dp, err := svc.ListDeliveries(context.Background(), token, whs[0].ID, webhooks.PageMetadata{})
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.deliveries, dp.Total, fmt.Sprintf("%s: expected %d deliveries got %d", tc.desc, tc.deliveries, dp.Total))
+	}
+}
+
+func TestPruneDeadLetters(t *testing.T) {
+	cases := []struct {
+		desc        string
+		maxAge      time.Duration
+		maxCount    uint64
+		deadLetters uint64
+	}{
+		{
+			desc:        "keep dead letters without retention limits",
+			deadLetters: 3,
+		},
+		{
+			desc:        "keep latest dead letters",
+			maxCount:    2,
+			deadLetters: 2,
+		},
+		{
+			desc:        "remove expired dead letters",
+			maxAge:      time.Nanosecond,
+			deadLetters: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		config := deliveryConfig
+		config.DisableAfter = 0
+		config.DeadLettersMaxAge = tc.maxAge
+		config.DeadLettersMaxCount = tc.maxCount
+		svc := newServiceWithConfig(config)
+
+		failingWh := webhook
+		failingWh.Url = whmock.FailingURL
+		whs, err := svc.CreateWebhooks(context.Background(), token, thingID, failingWh)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+
+		for i := 0; i < 3; i++ {
+			err := svc.ConsumeWebhook(subject, protomfx.Webhook{ThingId: thingID, Payload: []byte(`{"key":"val"}`)})
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		}
+		require.Eventually(t, func() bool {
+			dlp, err := svc.ListDeadLetters(context.Background(), token, whs[0].ID, webhooks.PageMetadata{})
+			return err == nil && dlp.Total == 3
+		}, time.Second, time.Millisecond, fmt.Sprintf("%s: expected 3 dead letters", tc.desc))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err = svc.ProcessDeliveries(ctx)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+
+		dlp, err := svc.ListDeadLetters(context.Background(), token, whs[0].ID, webhooks.PageMetadata{})
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.deadLetters, dlp.Total, fmt.Sprintf("%s: expected %d dead letters got %d", tc.desc, tc.deadLetters, dlp.Total))
+	}
+}
+
+func TestEnableDisableWebhook(t *testing.T) {
+	svc := newService()
+	whs, err := svc.CreateWebhooks(context.Background(), token, thingID, webhook)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	wh := whs[0]
+
+	cases := []struct {
+		desc   string
+		op     func(ctx context.Context, token, id string) error
+		id     string
+		token  string
+		status string
+		err    error
+	}{
+		{
+			desc:   "disable webhook",
+			op:     svc.DisableWebhook,
+			id:     wh.ID,
+			token:  token,
+			status: webhooks.DisabledStatus,
+			err:    nil,
+		},
+		{
+			desc:   "enable webhook",
+			op:     svc.EnableWebhook,
+			id:     wh.ID,
+			token:  token,
+			status: webhooks.EnabledStatus,
+			err:    nil,
+		},
+		{
+			desc:   "disable webhook with wrong credentials",
+			op:     svc.DisableWebhook,
+			id:     wh.ID,
+			token:  wrongValue,
+			status: webhooks.EnabledStatus,
+			err:    errors.ErrAuthorization,
+		},
+		{
+			desc:   "disable non-existing webhook",
+			op:     svc.DisableWebhook,
+			id:     wrongValue,
+			token:  token,
+			status: webhooks.EnabledStatus,
+			err:    dbutil.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := tc.op(context.Background(), tc.token, tc.id)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+
+		res, err := svc.ViewWebhook(context.Background(), token, wh.ID)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		assert.Equal(t, tc.status, res.Status, fmt.Sprintf("%s: expected status %s got %s\n", tc.desc, tc.status, res.Status))
+	}
+}
+
+func TestReplayDeadLetters(t *testing.T) {
+	svc := newService()
+	failingWh := webhook
+	failingWh.Url = whmock.FailingURL
+	whs, err := svc.CreateWebhooks(context.Background(), token, thingID, failingWh)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	wh := whs[0]
+
+	msg := protomfx.Webhook{ThingId: thingID, Payload: []byte(`{"key":"val"}`)}
+	err = svc.ConsumeWebhook(subject, msg)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	var dlp webhooks.DeadLettersPage
+	require.Eventually(t, func() bool {
+		dlp, err = svc.ListDeadLetters(context.Background(), token, wh.ID, webhooks.PageMetadata{})
+		return err == nil && dlp.Total == 1
+	}, time.Second, time.Millisecond, "expected a dead letter")
+	dl := dlp.DeadLetters[0]
+	assert.Equal(t, msg.Payload, dl.Message.Payload, fmt.Sprintf("expected dead letter payload %s got %s", msg.Payload, dl.Message.Payload))
+
+	cases := []struct {
+		desc  string
+		url   string
+		token string
+		ids   []string
+		res   webhooks.ReplayResult
+		err   error
+	}{
+		{
+			desc:  "replay dead letters with wrong credentials",
+			url:   whmock.FailingURL,
+			token: wrongValue,
+			res:   webhooks.ReplayResult{},
+			err:   errors.ErrAuthorization,
+		},
+		{
+			desc:  "replay non-existing dead letter",
+			url:   whmock.FailingURL,
+			token: token,
+			ids:   []string{wrongValue},
+			res:   webhooks.ReplayResult{},
+			err:   dbutil.ErrNotFound,
+		},
+		{
+			desc:  "replay dead letter to failing webhook",
+			url:   whmock.FailingURL,
+			token: token,
+			ids:   []string{dl.ID},
+			res:   webhooks.ReplayResult{Total: 1, Delivered: 0},
+			err:   nil,
+		},
+		{
+			desc:  "replay all dead letters to fixed webhook",
+			url:   webhook.Url,
+			token: token,
+			res:   webhooks.ReplayResult{Total: 1, Delivered: 1},
+			err:   nil,
+		},
+		{
+			desc:  "replay without dead letters",
+			url:   webhook.Url,
+			token: token,
+			res:   webhooks.ReplayResult{Total: 0, Delivered: 0},
+			err:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		updated := wh
+		updated.Url = tc.url
+		err := svc.UpdateWebhook(context.Background(), token, updated)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+		res, err := svc.ReplayDeadLetters(context.Background(), tc.token, wh.ID, tc.ids...)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.res, res, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.res, res))
+	}
+
+	res, err := svc.ViewWebhook(context.Background(), token, wh.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, uint64(0), res.Failures, fmt.Sprintf("expected failures to be reset got %d", res.Failures))
+}
+
+func TestReplayDeadLettersLimit(t *testing.T) {
+	config := deliveryConfig
+	config.MaxAttempts = 1
+	config.DisableAfter = 0
+	svc := newServiceWithConfig(config)
+
+	failingWh := webhook
+	failingWh.Url = whmock.FailingURL
+	whs, err := svc.CreateWebhooks(context.Background(), token, thingID, failingWh)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	wh := whs[0]
+
+	total := uint64(webhooks.MaxReplay + 1)
+	for i := uint64(0); i < total; i++ {
+		err := svc.ConsumeWebhook(subject, protomfx.Webhook{ThingId: thingID, Payload: []byte(fmt.Sprintf(`{"seq":%d}`, i))})
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+	require.Eventually(t, func() bool {
+		dlp, err := svc.ListDeadLetters(context.Background(), token, wh.ID, webhooks.PageMetadata{})
+		return err == nil && dlp.Total == total
+	}, time.Second, time.Millisecond, fmt.Sprintf("expected %d dead letters", total))
+
+	wh.Url = webhook.Url
+	err = svc.UpdateWebhook(context.Background(), token, wh)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	// The oldest dead letters are replayed first, up to the limit.
+	res, err := svc.ReplayDeadLetters(context.Background(), token, wh.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	expected := webhooks.ReplayResult{Total: webhooks.MaxReplay, Delivered: webhooks.MaxReplay, Remaining: 1}
+	assert.Equal(t, expected, res, fmt.Sprintf("expected %v got %v", expected, res))
+
+	dlp, err := svc.ListDeadLetters(context.Background(), token, wh.ID, webhooks.PageMetadata{})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	require.Equal(t, uint64(1), dlp.Total, fmt.Sprintf("expected 1 dead letter got %d", dlp.Total))
+	assert.Equal(t, fmt.Sprintf(`{"seq":%d}`, webhooks.MaxReplay), string(dlp.DeadLetters[0].Message.Payload), "expected the newest dead letter to remain")
+}
+
+func TestRotateWebhookSecret(t *testing.T) {
+	svc := newService()
+	whs, err := svc.CreateWebhooks(context.Background(), token, thingID, webhook)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	wh := whs[0]
+	require.NotEmpty(t, wh.Secret, "expected a generated secret")
+
+	cases := []struct {
+		desc  string
+		id    string
+		token string
+		err   error
+	}{
+		{
+			desc:  "rotate webhook secret",
+			id:    wh.ID,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "rotate webhook secret with wrong credentials",
+			id:    wh.ID,
+			token: wrongValue,
+			err:   errors.ErrAuthorization,
+		},
+		{
+			desc:  "rotate non-existing webhook secret",
+			id:    wrongValue,
+			token: token,
+			err:   dbutil.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		before, err := svc.ViewWebhook(context.Background(), token, wh.ID)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+		rotated, err := svc.RotateWebhookSecret(context.Background(), tc.token, tc.id)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		after, err := svc.ViewWebhook(context.Background(), token, wh.ID)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		assert.Equal(t, rotated.Secret, after.Secret, fmt.Sprintf("%s: expected secret %s got %s\n", tc.desc, rotated.Secret, after.Secret))
+		assert.NotEqual(t, before.Secret, after.Secret, fmt.Sprintf("%s: expected a new secret\n", tc.desc))
+		assert.Equal(t, before.Secret, after.PreviousSecret, fmt.Sprintf("%s: expected previous secret %s got %s\n", tc.desc, before.Secret, after.PreviousSecret))
+		assert.Greater(t, after.PreviousSecretExpiresAt, time.Now().UnixNano(), fmt.Sprintf("%s: expected previous secret to be valid during the grace period\n", tc.desc))
+	}
 }
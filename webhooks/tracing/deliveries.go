@@ -0,0 +1,118 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"github.com/MainfluxLabs/mainflux/pkg/dbutil"
+	"github.com/MainfluxLabs/mainflux/webhooks"
+	"github.com/opentracing/opentracing-go"
+)
+
+const (
+	saveDelivery                 = "save_delivery"
+	retrieveDeliveriesByWebhook  = "retrieve_deliveries_by_webhook"
+	pruneDeliveries              = "prune_deliveries"
+	saveDeadLetter               = "save_dead_letter"
+	retrieveDeadLetterByID       = "retrieve_dead_letter_by_id"
+	retrieveDeadLettersByWebhook = "retrieve_dead_letters_by_webhook"
+	removeDeadLetters            = "remove_dead_letters"
+	pruneDeadLetters             = "prune_dead_letters"
+)
+
+var (
+	_ webhooks.DeliveryRepository   = (*deliveryRepositoryMiddleware)(nil)
+	_ webhooks.DeadLetterRepository = (*deadLetterRepositoryMiddleware)(nil)
+)
+
+type deliveryRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   webhooks.DeliveryRepository
+}
+
+// DeliveryRepositoryMiddleware tracks request and their latency, and adds spans
+// to context.
+func DeliveryRepositoryMiddleware(tracer opentracing.Tracer, repo webhooks.DeliveryRepository) webhooks.DeliveryRepository {
+	return deliveryRepositoryMiddleware{
+		tracer: tracer,
+		repo:   repo,
+	}
+}
+
+func (drm deliveryRepositoryMiddleware) Save(ctx context.Context, d webhooks.Delivery) error {
+	span := dbutil.CreateSpan(ctx, drm.tracer, saveDelivery)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return drm.repo.Save(ctx, d)
+}
+
+func (drm deliveryRepositoryMiddleware) RetrieveByWebhook(ctx context.Context, webhookID string, pm webhooks.PageMetadata) (webhooks.DeliveriesPage, error) {
+	span := dbutil.CreateSpan(ctx, drm.tracer, retrieveDeliveriesByWebhook)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return drm.repo.RetrieveByWebhook(ctx, webhookID, pm)
+}
+
+func (drm deliveryRepositoryMiddleware) Prune(ctx context.Context, before time.Time, keep uint64) error {
+	span := dbutil.CreateSpan(ctx, drm.tracer, pruneDeliveries)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return drm.repo.Prune(ctx, before, keep)
+}
+
+type deadLetterRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   webhooks.DeadLetterRepository
+}
+
+// DeadLetterRepositoryMiddleware tracks request and their latency, and adds spans
+// to context.
+func DeadLetterRepositoryMiddleware(tracer opentracing.Tracer, repo webhooks.DeadLetterRepository) webhooks.DeadLetterRepository {
+	return deadLetterRepositoryMiddleware{
+		tracer: tracer,
+		repo:   repo,
+	}
+}
+
+func (dlrm deadLetterRepositoryMiddleware) Save(ctx context.Context, dl webhooks.DeadLetter) error {
+	span := dbutil.CreateSpan(ctx, dlrm.tracer, saveDeadLetter)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return dlrm.repo.Save(ctx, dl)
+}
+
+func (dlrm deadLetterRepositoryMiddleware) RetrieveByID(ctx context.Context, id string) (webhooks.DeadLetter, error) {
+	span := dbutil.CreateSpan(ctx, dlrm.tracer, retrieveDeadLetterByID)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return dlrm.repo.RetrieveByID(ctx, id)
+}
+
+func (dlrm deadLetterRepositoryMiddleware) RetrieveByWebhook(ctx context.Context, webhookID string, pm webhooks.PageMetadata) (webhooks.DeadLettersPage, error) {
+	span := dbutil.CreateSpan(ctx, dlrm.tracer, retrieveDeadLettersByWebhook)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return dlrm.repo.RetrieveByWebhook(ctx, webhookID, pm)
+}
+
+func (dlrm deadLetterRepositoryMiddleware) Remove(ctx context.Context, ids ...string) error {
+	span := dbutil.CreateSpan(ctx, dlrm.tracer, removeDeadLetters)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return dlrm.repo.Remove(ctx, ids...)
+}
+
+func (dlrm deadLetterRepositoryMiddleware) Prune(ctx context.Context, before time.Time, keep uint64) error {
+	span := dbutil.CreateSpan(ctx, dlrm.tracer, pruneDeadLetters)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return dlrm.repo.Prune(ctx, before, keep)
+}
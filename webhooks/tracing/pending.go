@@ -0,0 +1,65 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"github.com/MainfluxLabs/mainflux/pkg/dbutil"
+	"github.com/MainfluxLabs/mainflux/webhooks"
+	"github.com/opentracing/opentracing-go"
+)
+
+const (
+	savePendingMessages   = "save_pending_messages"
+	renewPendingLeases    = "renew_pending_leases"
+	claimPendingMessages  = "claim_pending_messages"
+	removePendingMessages = "remove_pending_messages"
+)
+
+var _ webhooks.PendingMessageRepository = (*pendingMessageRepositoryMiddleware)(nil)
+
+type pendingMessageRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   webhooks.PendingMessageRepository
+}
+
+// PendingMessageRepositoryMiddleware tracks request and their latency, and adds spans
+// to context.
+func PendingMessageRepositoryMiddleware(tracer opentracing.Tracer, repo webhooks.PendingMessageRepository) webhooks.PendingMessageRepository {
+	return pendingMessageRepositoryMiddleware{
+		tracer: tracer,
+		repo:   repo,
+	}
+}
+
+func (pmrm pendingMessageRepositoryMiddleware) Save(ctx context.Context, instanceID string, leaseUntil time.Time, pms ...webhooks.PendingMessage) error {
+	span := dbutil.CreateSpan(ctx, pmrm.tracer, savePendingMessages)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return pmrm.repo.Save(ctx, instanceID, leaseUntil, pms...)
+}
+
+func (pmrm pendingMessageRepositoryMiddleware) RenewLeases(ctx context.Context, instanceID string, leaseUntil time.Time) error {
+	span := dbutil.CreateSpan(ctx, pmrm.tracer, renewPendingLeases)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return pmrm.repo.RenewLeases(ctx, instanceID, leaseUntil)
+}
+
+func (pmrm pendingMessageRepositoryMiddleware) Claim(ctx context.Context, instanceID string, leaseUntil time.Time, limit uint64) ([]webhooks.PendingMessage, error) {
+	span := dbutil.CreateSpan(ctx, pmrm.tracer, claimPendingMessages)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return pmrm.repo.Claim(ctx, instanceID, leaseUntil, limit)
+}
+
+func (pmrm pendingMessageRepositoryMiddleware) Remove(ctx context.Context, ids ...string) error {
+	span := dbutil.CreateSpan(ctx, pmrm.tracer, removePendingMessages)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return pmrm.repo.Remove(ctx, ids...)
+}
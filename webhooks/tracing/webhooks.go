@@ -14,6 +14,10 @@ const (
 	retrieveWebhooksByThing = "retrieve_webhooks_by_thing"
 	retrieveWebhookByID     = "retrieve_webhook_by_id"
 	updateWebhook           = "update_webhook"
+	updateWebhookStatus     = "update_webhook_status"
+	incrementFailures       = "increment_webhook_failures"
+	resetFailures           = "reset_webhook_failures"
+	updateWebhookSecret     = "update_webhook_secret"
 	removeWebhooks          = "remove_webhooks"
 	removeWebhooksByThing   = "remove_webhooks_by_thing"
 	removeWebhooksByGroup   = "remove_webhooks_by_group"
@@ -77,6 +81,38 @@ func (wrm webhookRepositoryMiddleware) Update(ctx context.Context, w webhooks.We
 	return wrm.repo.Update(ctx, w)
 }
 
+func (wrm webhookRepositoryMiddleware) UpdateStatus(ctx context.Context, id, status string) error {
+	span := dbutil.CreateSpan(ctx, wrm.tracer, updateWebhookStatus)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return wrm.repo.UpdateStatus(ctx, id, status)
+}
+
+func (wrm webhookRepositoryMiddleware) IncrementFailures(ctx context.Context, id string, disableAfter uint64) (webhooks.Webhook, error) {
+	span := dbutil.CreateSpan(ctx, wrm.tracer, incrementFailures)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return wrm.repo.IncrementFailures(ctx, id, disableAfter)
+}
+
+func (wrm webhookRepositoryMiddleware) ResetFailures(ctx context.Context, id string) error {
+	span := dbutil.CreateSpan(ctx, wrm.tracer, resetFailures)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return wrm.repo.ResetFailures(ctx, id)
+}
+
+func (wrm webhookRepositoryMiddleware) UpdateSecret(ctx context.Context, w webhooks.Webhook) error {
+	span := dbutil.CreateSpan(ctx, wrm.tracer, updateWebhookSecret)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return wrm.repo.UpdateSecret(ctx, w)
+}
+
 func (wrm webhookRepositoryMiddleware) Remove(ctx context.Context, ids ...string) error {
 	span := dbutil.CreateSpan(ctx, wrm.tracer, removeWebhooks)
 	defer span.Finish()
@@ -2,6 +2,13 @@ package webhooks
 
 import (
 	"context"
+
+	"github.com/MainfluxLabs/mainflux/pkg/domain"
+)
+
+const (
+	EnabledStatus  = domain.EnabledStatusKey
+	DisabledStatus = domain.DisabledStatusKey
 )
 
 type Webhook struct {
@@ -12,6 +19,28 @@ type Webhook struct {
 	Url      string
 	Headers  map[string]string
 	Metadata map[string]any
+	// Method is the HTTP method messages are forwarded with, POST by default.
+	Method string
+	// Template is an optional text/template the request body is rendered from.
+	Template string
+	// Format is the body format, json by default.
+	Format string
+	// Filter selects the forwarded messages. An empty filter forwards all messages.
+	Filter Filter
+	// Batch reports whether messages are buffered and forwarded in batches,
+	// which is only supported by the json format.
+	Batch bool
+	// Status is either enabled or disabled. Messages aren't forwarded to disabled webhooks.
+	Status string
+	// Failures is the number of consecutive failed deliveries.
+	Failures uint64
+	// Secret is the key forwarded messages are signed with.
+	Secret string
+	// PreviousSecret is the secret replaced by the last rotation. Messages are
+	// signed with it as well until PreviousSecretExpiresAt.
+	PreviousSecret string
+	// PreviousSecretExpiresAt is the end of the rotation grace period in unix nanoseconds.
+	PreviousSecretExpiresAt int64
 }
 
 type WebhooksPage struct {
@@ -44,6 +73,22 @@ type WebhookRepository interface {
 	// A non-nil error is returned to indicate operation failure.
 	Update(ctx context.Context, w Webhook) error
 
+	// UpdateStatus changes the status of the webhook identified by the provided ID,
+	// and resets its consecutive delivery failures.
+	UpdateStatus(ctx context.Context, id, status string) error
+
+	// IncrementFailures increments the consecutive delivery failures of the webhook
+	// identified by the provided ID, and disables the webhook once they reach
+	// disableAfter, unless it is zero. The updated webhook is returned.
+	IncrementFailures(ctx context.Context, id string, disableAfter uint64) (Webhook, error)
+
+	// ResetFailures resets the consecutive delivery failures of the webhook
+	// identified by the provided ID.
+	ResetFailures(ctx context.Context, id string) error
+
+	// UpdateSecret updates the current and previous secret of the webhook.
+	UpdateSecret(ctx context.Context, w Webhook) error
+
 	// Remove removes the webhooks identified with the provided IDs.
 	Remove(ctx context.Context, ids ...string) error
 